@@ -7,9 +7,11 @@ import (
 	"claude-squad/session"
 	"claude-squad/ui"
 	"claude-squad/ui/overlay"
+	"claude-squad/upgrade"
 	"context"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbles/spinner"
@@ -19,6 +21,9 @@ import (
 
 const GlobalInstanceLimit = 10
 
+// Version is the running claude-squad version. It is set by main and used to check for updates.
+var Version string
+
 // Run is the main entrypoint into the application.
 func Run(ctx context.Context, program string, autoYes bool) error {
 	p := tea.NewProgram(
@@ -73,6 +78,10 @@ type home struct {
 	// keySent is used to manage underlining menu items
 	keySent bool
 
+	// resumingAll is set while a bulk resume is in flight, so that another isn't started on
+	// the same instances
+	resumingAll bool
+
 	// -- UI Components --
 
 	// list displays the list of instances
@@ -111,7 +120,7 @@ func newHome(ctx context.Context, program string, autoYes bool) *home {
 		ctx:          ctx,
 		spinner:      spinner.New(spinner.WithSpinner(spinner.MiniDot)),
 		menu:         ui.NewMenu(),
-		tabbedWindow: ui.NewTabbedWindow(ui.NewPreviewPane(), ui.NewDiffPane()),
+		tabbedWindow: ui.NewTabbedWindow(ui.NewPreviewPane(), ui.NewDiffPane(), ui.NewTimelinePane()),
 		errBox:       ui.NewErrBox(),
 		storage:      storage,
 		appConfig:    appConfig,
@@ -196,6 +205,7 @@ func (m *home) Init() tea.Cmd {
 			return previewTickMsg{}
 		},
 		tickUpdateMetadataCmd,
+		m.checkForUpdateCmd(),
 	)
 }
 
@@ -203,6 +213,25 @@ func (m *home) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case hideErrMsg:
 		m.errBox.Clear()
+	case resumeAllDoneMsg:
+		m.resumingAll = false
+		session.MarkResumed(msg.results)
+		var failures []string
+		for _, result := range msg.results {
+			if result.Err != nil {
+				failures = append(failures, fmt.Sprintf("%s: %v", result.Title, result.Err))
+			}
+		}
+		if err := m.storage.SaveInstances(m.list.GetInstances()); err != nil {
+			return m, m.handleError(err)
+		}
+		if len(failures) > 0 {
+			return m, tea.Batch(tea.WindowSize(), m.handleError(
+				fmt.Errorf("%d of %d sessions not resumed: %s", len(failures), len(msg.results), strings.Join(failures, "; "))))
+		}
+		return m, tea.WindowSize()
+	case updateAvailableMsg:
+		m.errBox.SetHint(fmt.Sprintf("claude-squad %s is available, run `claude-squad upgrade`", msg.version))
 	case previewTickMsg:
 		cmd := m.instanceChanged()
 		return m, tea.Batch(
@@ -216,6 +245,7 @@ func (m *home) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.menu.ClearKeydown()
 		return m, nil
 	case tickUpdateMetadataMessage:
+		cmds := []tea.Cmd{tickUpdateMetadataCmd}
 		for _, instance := range m.list.GetInstances() {
 			if !instance.Started() || instance.Paused() {
 				continue
@@ -233,8 +263,11 @@ func (m *home) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if err := instance.UpdateDiffStats(); err != nil {
 				log.WarningLog.Printf("could not update diff stats: %v", err)
 			}
+			if instance.CheckDiffGuardrails(m.appConfig.DiffGuardrails) {
+				cmds = append(cmds, m.handleDiffGuardrail(instance))
+			}
 		}
-		return m, tickUpdateMetadataCmd
+		return m, tea.Batch(cmds...)
 	case tea.MouseMsg:
 		// Handle mouse wheel events for scrolling the diff/preview pane
 		if msg.Action == tea.MouseActionPress {
@@ -592,6 +625,7 @@ func (m *home) handleKeyPress(msg tea.KeyMsg) (mod tea.Model, cmd tea.Cmd) {
 			if err = worktree.PushChanges(commitMsg, true); err != nil {
 				return err
 			}
+			session.RecordEvent(selected.ID, session.EventCommit, commitMsg)
 			return nil
 		}
 
@@ -621,6 +655,25 @@ func (m *home) handleKeyPress(msg tea.KeyMsg) (mod tea.Model, cmd tea.Cmd) {
 			return m, m.handleError(err)
 		}
 		return m, tea.WindowSize()
+	case keys.KeyResumeAll:
+		if m.resumingAll {
+			return m, nil
+		}
+		// Only the paused instances are handed to the command, whose status the metadata
+		// tick leaves alone; the command marks none of them running itself
+		var paused []*session.Instance
+		for _, instance := range m.list.GetInstances() {
+			if instance.Paused() {
+				paused = append(paused, instance)
+			}
+		}
+		if len(paused) == 0 {
+			return m, nil
+		}
+		m.resumingAll = true
+		return m, func() tea.Msg {
+			return resumeAllDoneMsg{results: session.ResumeAll(paused, session.DefaultResumeConcurrency)}
+		}
 	case keys.KeyEnter:
 		if m.list.NumInstances() == 0 {
 			return m, nil
@@ -652,6 +705,7 @@ func (m *home) instanceChanged() tea.Cmd {
 	selected := m.list.GetSelectedInstance()
 
 	m.tabbedWindow.UpdateDiff(selected)
+	m.tabbedWindow.UpdateTimeline(selected)
 	m.tabbedWindow.SetInstance(selected)
 	// Update menu with current instance
 	m.menu.SetInstance(selected)
@@ -688,6 +742,37 @@ type tickUpdateMetadataMessage struct{}
 
 type instanceChangedMsg struct{}
 
+// resumeAllDoneMsg implements tea.Msg and carries the outcome of a bulk resume.
+type resumeAllDoneMsg struct {
+	results []session.ResumeResult
+}
+
+// updateAvailableMsg implements tea.Msg and is sent when a newer release is published.
+type updateAvailableMsg struct {
+	version string
+}
+
+// checkForUpdateCmd checks for a newer release in the background. Failures are only logged so
+// an offline machine never sees an error for it.
+func (m *home) checkForUpdateCmd() tea.Cmd {
+	if Version == "" {
+		return nil
+	}
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(m.ctx, 10*time.Second)
+		defer cancel()
+		release, newer, err := upgrade.NewClient().Check(ctx, Version)
+		if err != nil {
+			log.InfoLog.Printf("update check failed: %v", err)
+			return nil
+		}
+		if !newer {
+			return nil
+		}
+		return updateAvailableMsg{version: release.Version()}
+	}
+}
+
 // tickUpdateMetadataCmd is the callback to update the metadata of the instances every 500ms. Note that we iterate
 // overall the instances and capture their output. It's a pretty expensive operation. Let's do it 2x a second only.
 var tickUpdateMetadataCmd = func() tea.Msg {
@@ -710,6 +795,20 @@ func (m *home) handleError(err error) tea.Cmd {
 	}
 }
 
+// handleDiffGuardrail notifies that instance's diff exceeded the configured guardrails and
+// pauses it if configured to.
+func (m *home) handleDiffGuardrail(instance *session.Instance) tea.Cmd {
+	err := fmt.Errorf("session '%s' exceeded diff guardrails: %s", instance.Title, instance.DiffWarning())
+	if m.appConfig.DiffGuardrails.PauseOnExceed {
+		if pauseErr := instance.Pause(); pauseErr != nil {
+			err = fmt.Errorf("%w (failed to pause: %v)", err, pauseErr)
+		} else {
+			err = fmt.Errorf("%w, session paused", err)
+		}
+	}
+	return m.handleError(err)
+}
+
 // confirmAction shows a confirmation modal and stores the action to execute on confirm
 func (m *home) confirmAction(message string, action tea.Cmd) tea.Cmd {
 	m.state = stateConfirm
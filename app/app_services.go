@@ -84,7 +84,7 @@ func newHomeWithServices(ctx context.Context, deps *Dependencies, program string
 		state:     stateDefault,
 		spinner:   spinner.New(spinner.WithSpinner(spinner.MiniDot)),
 		menu:      ui.NewMenu(),
-		tabbedWindow: ui.NewTabbedWindow(ui.NewPreviewPane(), ui.NewDiffPane()),
+		tabbedWindow: ui.NewTabbedWindow(ui.NewPreviewPane(), ui.NewDiffPane(), ui.NewTimelinePane()),
 		errBox:    ui.NewErrBox(),
 		instances: make(map[string]*adapter.SessionInstance),
 	}
@@ -464,3 +464,40 @@ func TestConfirmationModalVisualAppearance(t *testing.T) {
 	// Test that the danger indicator is preserved
 	assert.Contains(t, rendered, "[!")
 }
+
+func TestResumeAllKeyIgnoredWhileResuming(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	spinner := spinner.New(spinner.WithSpinner(spinner.MiniDot))
+	list := ui.NewList(&spinner, false)
+	instance, err := session.NewInstance(session.InstanceOptions{Title: "paused", Path: t.TempDir()})
+	require.NoError(t, err)
+	instance.SetStatus(session.Paused)
+	list.AddInstance(instance)
+
+	storage, err := session.NewStorage(config.DefaultState())
+	require.NoError(t, err)
+	h := &home{
+		ctx:       context.Background(),
+		state:     stateDefault,
+		appConfig: config.DefaultConfig(),
+		list:      list,
+		menu:      ui.NewMenu(),
+		storage:   storage,
+	}
+
+	press := func() tea.Cmd {
+		// Skip the menu highlighting round trip and handle the key directly
+		h.keySent = true
+		_, cmd := h.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("R")})
+		return cmd
+	}
+
+	assert.NotNil(t, press())
+	assert.True(t, h.resumingAll)
+	assert.Nil(t, press(), "a second resume shouldn't start while the first is in flight")
+
+	h.Update(resumeAllDoneMsg{})
+	assert.False(t, h.resumingAll)
+	assert.NotNil(t, press())
+}
@@ -51,10 +51,11 @@ func (h helpTypeGeneral) toContent() string {
 		keyStyle.Render("p")+descStyle.Render("         - Commit and push branch to github"),
 		keyStyle.Render("c")+descStyle.Render("         - Checkout: commit changes and pause session"),
 		keyStyle.Render("r")+descStyle.Render("         - Resume a paused session"),
+		keyStyle.Render("R")+descStyle.Render("         - Resume all paused sessions"),
 		"",
 		headerStyle.Render("Other:"),
-		keyStyle.Render("tab")+descStyle.Render("       - Switch between preview and diff tabs"),
-		keyStyle.Render("shift-↓/↑")+descStyle.Render(" - Scroll in diff view"),
+		keyStyle.Render("tab")+descStyle.Render("       - Switch between preview, diff and timeline tabs"),
+		keyStyle.Render("shift-↓/↑")+descStyle.Render(" - Scroll in diff and timeline views"),
 		keyStyle.Render("q")+descStyle.Render("         - Quit the application"),
 	)
 	return content
@@ -1,6 +1,8 @@
 package cmd
 
 import (
+	"fmt"
+	"io"
 	"os/exec"
 	"strings"
 )
@@ -20,6 +22,27 @@ func (e Exec) Output(cmd *exec.Cmd) ([]byte, error) {
 	return cmd.Output()
 }
 
+// DryRun prints the commands given to Run instead of running them. Output still runs its
+// command, since callers use it to read state, so commands that change state must go
+// through Run.
+type DryRun struct {
+	Out io.Writer
+}
+
+func (e DryRun) Run(cmd *exec.Cmd) error {
+	e.Skip("run: " + ToString(cmd))
+	return nil
+}
+
+func (e DryRun) Output(cmd *exec.Cmd) ([]byte, error) {
+	return cmd.Output()
+}
+
+// Skip prints an action that was skipped for the dry run
+func (e DryRun) Skip(action string) {
+	fmt.Fprintf(e.Out, "would %s\n", action)
+}
+
 func MakeExecutor() Executor {
 	return Exec{}
 }
@@ -36,6 +36,191 @@ type Config struct {
 	DaemonPollInterval int `json:"daemon_poll_interval"`
 	// BranchPrefix is the prefix used for git branches created by the application.
 	BranchPrefix string `json:"branch_prefix"`
+	// RedactPatterns are extra regexes (on top of the built-in API key formats) whose matches are
+	// masked before logs, transcripts and exports are persisted.
+	RedactPatterns []string `json:"redact_patterns,omitempty"`
+	// WorktreeDir is the directory session worktrees are created under, e.g.
+	// "~/.claude-squad/worktrees". When empty, they go next to their repository.
+	WorktreeDir string `json:"worktree_dir,omitempty"`
+	// WorktreeName names a session's worktree under WorktreeDir, with {repo}, {branch} and
+	// {id} replaced by the repository's directory name, the branch and the session ID, e.g.
+	// "{repo}/{branch}". Defaults to "{repo}-worktree-{id}". `cs worktrees migrate` moves
+	// existing worktrees after either is changed.
+	WorktreeName string `json:"worktree_name,omitempty"`
+	// WorktreeSubmodules initializes the submodules of each session's worktree when it is
+	// created, which `cs new --submodules` overrides. Without, they start out empty.
+	WorktreeSubmodules bool `json:"worktree_submodules,omitempty"`
+	// DiffGuardrails flags sessions whose diff grows beyond the configured size.
+	DiffGuardrails DiffGuardrails `json:"diff_guardrails,omitempty"`
+	// Commit controls how `cs commit` and `cs finish` commit sessions' changes. Sessions
+	// can override it with `cs commit-settings`.
+	Commit CommitSettings `json:"commit,omitempty"`
+	// Editor is the command `cs open` uses to open a session's worktree, e.g. "code" or
+	// "idea". When empty, $VISUAL and $EDITOR are tried before any known editor on PATH.
+	Editor string `json:"editor,omitempty"`
+	// ForgeHosts maps the host names of self-hosted forges to "github", "gitlab" or
+	// "gitea", e.g. {"git.example.com": "gitlab"}, for `cs pr`. Other hosts are GitLab or
+	// Gitea when their name contains it, and GitHub otherwise.
+	ForgeHosts map[string]string `json:"forge_hosts,omitempty"`
+	// Multiplexer is the terminal multiplexer sessions run in: "tmux", "wezterm" for
+	// WezTerm's multiplexer, which also runs natively on Windows, or "kubernetes" to run
+	// each session in tmux in its own pod, as configured by Kubernetes. When empty it is
+	// tmux, except on Windows.
+	Multiplexer string `json:"multiplexer,omitempty"`
+	// GitBackend selects how git repositories are read: "exec" runs git for everything,
+	// the default, while "go-git" reads status, diff stats, branches and log in process,
+	// which costs less when views refresh many sessions every second. Either way, changes
+	// and worktrees are made by running git.
+	GitBackend string `json:"git_backend,omitempty"`
+	// StorageBackend selects where sessions are stored: "json" (one file per session, the
+	// default), "sqlite" (a single database, faster with many sessions), "bolt" (a single
+	// bbolt key-value file) or "postgres" (a database shared by a team).
+	StorageBackend string `json:"storage_backend,omitempty"`
+	// StorageDSN is the connection string of the postgres backend, e.g.
+	// "postgres://user@host/claude_squad". When empty it is read from $CS_STORAGE_DSN.
+	StorageDSN string `json:"storage_dsn,omitempty"`
+	// EncryptStorage encrypts session prompts, input, metadata and diff snapshots at rest.
+	// The key comes from $CS_STORAGE_KEY, or else the OS keychain, where one is generated
+	// on first use. Sessions saved before it was turned on stay readable.
+	EncryptStorage bool `json:"encrypt_storage,omitempty"`
+	// SessionLogs mirrors everything session panes show to a log file per session in the
+	// session-logs directory of the config directory, so full output survives beyond the
+	// tmux scrollback. The files are kept until removed by hand.
+	SessionLogs bool `json:"session_logs,omitempty"`
+	// AuditCommands records every git, tmux and other command run for sessions, with its
+	// arguments, directory, duration and exit code, in commands.log in the config
+	// directory. Credentials matching the redaction patterns are masked.
+	AuditCommands bool `json:"audit_commands,omitempty"`
+	// CommandCacheTTL is how long (ms) the output of git and tmux commands that only read
+	// state is reused, cutting the processes started by views refreshing every second.
+	// Changes cs makes drop it, but changes made outside it, e.g. by an agent, may go
+	// unnoticed for this long. 0 disables the cache.
+	CommandCacheTTL int `json:"command_cache_ttl,omitempty"`
+	// MetricsAddr is the address, e.g. "127.0.0.1:9464", on which cs serves counts and
+	// durations of the commands it runs to Prometheus at /metrics, for as long as it runs.
+	// Mostly useful with long-running commands like `cs top`. Empty serves nothing.
+	MetricsAddr string `json:"metrics_addr,omitempty"`
+	// EnvProfiles are named sets of environment variables, e.g. each agent's API key. The
+	// profile named after a program, like "claude" or "aider", is given to it whenever a
+	// session runs it. Values are "env:NAME" to copy $NAME, "keychain:ENTRY" to read ENTRY
+	// of the claude-squad service in the OS keychain, or else the value itself. Values
+	// from the environment or keychain are masked in logs. Needs tmux 3.0 or later.
+	EnvProfiles map[string]map[string]string `json:"env_profiles,omitempty"`
+	// PauseDrainTimeout is how long (seconds) a session's program has to save its state
+	// and exit after being interrupted, as with Ctrl-C, when the session is paused, before
+	// it is killed. 10 when 0.
+	PauseDrainTimeout int `json:"pause_drain_timeout,omitempty"`
+	// StallTimeout is how long (seconds) a command may go without output before it is
+	// logged as stalled, since agents and the tools they call often hang silently. It
+	// isn't stopped for it. 0 disables the check.
+	StallTimeout int `json:"stall_timeout,omitempty"`
+	// CheckpointInterval is how often (seconds) the daemon commits the changes in each
+	// running session's worktree, as "checkpoint: <time>", so the session's branch keeps
+	// a history of the agent's progress to go back to. 0, the default, makes no
+	// checkpoints.
+	CheckpointInterval int `json:"checkpoint_interval,omitempty"`
+	// DiffHistoryInterval is how often (seconds) the daemon records the size of each
+	// running session's uncommitted changes, when they have changed, so their growth can
+	// be followed with `cs diff --history`. 0, the default, records nothing.
+	DiffHistoryInterval int `json:"diff_history_interval,omitempty"`
+	// DiffHistoryPatches records the changes themselves along with their size, for the
+	// latest 50 samples of each session, to see what changed since a given time.
+	DiffHistoryPatches bool `json:"diff_history_patches,omitempty"`
+	// Retention limits how many sessions are kept and for how long. The daemon enforces it.
+	Retention Retention `json:"retention,omitempty"`
+	// Remote runs git and tmux on another machine over SSH, so sessions live on e.g. a
+	// devbox while claude-squad runs locally. Session paths are then paths on that host.
+	Remote Remote `json:"remote,omitempty"`
+	// Sandbox runs each session's program in a container with only its worktree mounted,
+	// when an image is set, so untrusted agent commands can't touch the host.
+	Sandbox Sandbox `json:"sandbox,omitempty"`
+	// Kubernetes is where the kubernetes multiplexer runs sessions.
+	Kubernetes Kubernetes `json:"kubernetes,omitempty"`
+}
+
+// Kubernetes is the cluster sessions run on with the kubernetes multiplexer. kubectl and
+// the user's kubeconfig must be set up to reach it.
+type Kubernetes struct {
+	// Context is the kubeconfig context to use. The current one when empty.
+	Context string `json:"context"`
+	// Namespace is where session pods are created. The context's default when empty.
+	Namespace string `json:"namespace"`
+	// Image is the image of session pods, which must contain sh, tmux, the programs
+	// sessions run and a checkout of the code: local worktrees aren't available in pods,
+	// so agents have to push their changes for them to be seen.
+	Image string `json:"image"`
+	// WorkDir is the directory sessions start in within their pod. The image's when empty.
+	WorkDir string `json:"work_dir"`
+	// ReadyTimeout is how long (seconds) to wait for a session's pod to start. 120 when 0.
+	ReadyTimeout int `json:"ready_timeout"`
+}
+
+// Sandbox is the container session programs run in. Leaving Image empty runs them on the host.
+type Sandbox struct {
+	// Image is the container image, which must contain the programs sessions run.
+	Image string `json:"image"`
+	// Runtime is the container CLI, docker when empty. podman works too.
+	Runtime string `json:"runtime"`
+	// Memory caps each container's memory, e.g. "4g". Unlimited when empty.
+	Memory string `json:"memory"`
+	// CPUs caps the CPUs each container may use, e.g. "1.5". Unlimited when empty.
+	CPUs string `json:"cpus"`
+	// PidsLimit caps the number of processes in each container, when non-zero.
+	PidsLimit int `json:"pids_limit"`
+	// Network is the network containers join, "none" to cut them off. Docker's default when empty.
+	Network string `json:"network"`
+	// Env are the names of environment variables passed into containers, e.g. API keys.
+	Env []string `json:"env"`
+}
+
+// Remote is the host sessions run on. Leaving Host empty runs them locally.
+type Remote struct {
+	// Host is the host name or an alias from ~/.ssh/config.
+	Host string `json:"host"`
+	// User logs in as this user instead of the one ssh picks.
+	User string `json:"user"`
+	// Port connects to this port instead of the one ssh picks, when non-zero.
+	Port int `json:"port"`
+	// KeyFile is the private key to authenticate with, in addition to the ssh agent.
+	KeyFile string `json:"key_file"`
+}
+
+// Retention is the session retention policy. A zero limit is disabled.
+type Retention struct {
+	// MaxSessions is the number of sessions kept. The least recently used paused sessions
+	// beyond it are moved to the trash; running sessions are never touched.
+	MaxSessions int `json:"max_sessions"`
+	// MaxAgeDays moves paused sessions that haven't been updated in this many days to the
+	// trash, and permanently deletes those that have been in the trash as long.
+	MaxAgeDays int `json:"max_age_days"`
+	// ArchiveAfterPausedDays archives sessions that have been paused this many days,
+	// freeing their worktrees.
+	ArchiveAfterPausedDays int `json:"archive_after_paused_days"`
+}
+
+// DiffGuardrails are thresholds on the size of a session's diff. A zero limit is disabled.
+type DiffGuardrails struct {
+	// MaxFiles is the maximum number of files a session may change before it is flagged.
+	MaxFiles int `json:"max_files"`
+	// MaxLines is the maximum number of added plus removed lines before a session is flagged.
+	MaxLines int `json:"max_lines"`
+	// PauseOnExceed pauses the agent when a threshold is exceeded.
+	PauseOnExceed bool `json:"pause_on_exceed"`
+}
+
+// CommitSettings control how commits are made for sessions.
+type CommitSettings struct {
+	// RunHooks runs the repository's pre-commit and commit-msg hooks, which are skipped
+	// by default so that checks meant for people don't block agents' commits.
+	RunHooks bool `json:"run_hooks,omitempty"`
+	// Sign signs commits with SigningKey, or git's user.signingkey when empty.
+	Sign       bool   `json:"sign,omitempty"`
+	SigningKey string `json:"signing_key,omitempty"`
+	// SigningFormat is "openpgp" for GPG, "ssh" or "x509"; git's gpg.format when empty.
+	SigningFormat string `json:"signing_format,omitempty"`
+	// Author is who commits are authored by, as "Name <email>", e.g. to tell agents'
+	// commits apart. Defaults to git's user.name and user.email.
+	Author string `json:"author,omitempty"`
 }
 
 // DefaultConfig returns the default configuration
@@ -110,13 +295,12 @@ func GetClaudeCommand() (string, error) {
 }
 
 func LoadConfig() *Config {
-	configDir, err := GetConfigDir()
+	configPath, err := ConfigPath()
 	if err != nil {
 		log.ErrorLog.Printf("failed to get config directory: %v", err)
 		return DefaultConfig()
 	}
 
-	configPath := filepath.Join(configDir, ConfigFileName)
 	data, err := os.ReadFile(configPath)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -143,16 +327,15 @@ func LoadConfig() *Config {
 
 // saveConfig saves the configuration to disk
 func saveConfig(config *Config) error {
-	configDir, err := GetConfigDir()
+	configPath, err := ConfigPath()
 	if err != nil {
-		return fmt.Errorf("failed to get config directory: %w", err)
+		return err
 	}
 
-	if err := os.MkdirAll(configDir, 0755); err != nil {
+	if err := os.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
 		return fmt.Errorf("failed to create config directory: %w", err)
 	}
 
-	configPath := filepath.Join(configDir, ConfigFileName)
 	data, err := json.MarshalIndent(config, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal config: %w", err)
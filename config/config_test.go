@@ -236,4 +236,22 @@ func TestSaveConfig(t *testing.T) {
 		assert.Equal(t, testConfig.DaemonPollInterval, loadedConfig.DaemonPollInterval)
 		assert.Equal(t, testConfig.BranchPrefix, loadedConfig.BranchPrefix)
 	})
+
+	t.Run("uses the overridden config path", func(t *testing.T) {
+		tempHome := t.TempDir()
+		originalHome := os.Getenv("HOME")
+		os.Setenv("HOME", tempHome)
+		defer os.Setenv("HOME", originalHome)
+
+		configPath := filepath.Join(t.TempDir(), "profiles", "work.json")
+		SetConfigPath(configPath)
+		defer SetConfigPath("")
+
+		err := SaveConfig(&Config{DefaultProgram: "aider", DaemonPollInterval: 1000, BranchPrefix: "work/"})
+		require.NoError(t, err)
+
+		assert.FileExists(t, configPath)
+		assert.NoFileExists(t, filepath.Join(tempHome, ".claude-squad", ConfigFileName))
+		assert.Equal(t, "aider", LoadConfig().DefaultProgram)
+	})
 }
@@ -0,0 +1,303 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// configPathOverride replaces the default config file location when set
+var configPathOverride string
+
+// SetConfigPath makes every later load and save use the config file at path instead of
+// the one in the config directory. An empty path restores the default.
+func SetConfigPath(path string) {
+	configPathOverride = path
+}
+
+// ConfigPath returns the path of the config file
+func ConfigPath() (string, error) {
+	if configPathOverride != "" {
+		return configPathOverride, nil
+	}
+	configDir, err := GetConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get config directory: %w", err)
+	}
+	return filepath.Join(configDir, ConfigFileName), nil
+}
+
+// ReadConfig loads the config file like LoadConfig, but returns an error instead of
+// falling back to defaults when the file can't be parsed or is invalid
+func ReadConfig() (*Config, error) {
+	configPath, err := ConfigPath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(configPath)
+	if os.IsNotExist(err) {
+		return DefaultConfig(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var config Config
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse config file: %w", err)
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// Validate checks that the config's values are usable
+func (c *Config) Validate() error {
+	if strings.TrimSpace(c.DefaultProgram) == "" {
+		return fmt.Errorf("default_program must not be empty")
+	}
+	if c.DaemonPollInterval <= 0 {
+		return fmt.Errorf("daemon_poll_interval must be positive")
+	}
+	if c.WorktreeName != "" && !strings.Contains(c.WorktreeName, "{id}") && !strings.Contains(c.WorktreeName, "{branch}") {
+		return fmt.Errorf("worktree_name must contain {id} or {branch}, so sessions get worktrees of their own")
+	}
+	if c.PauseDrainTimeout < 0 {
+		return fmt.Errorf("pause_drain_timeout must not be negative")
+	}
+	if c.StallTimeout < 0 {
+		return fmt.Errorf("stall_timeout must not be negative")
+	}
+	if c.CheckpointInterval < 0 {
+		return fmt.Errorf("checkpoint_interval must not be negative")
+	}
+	if c.DiffHistoryInterval < 0 {
+		return fmt.Errorf("diff_history_interval must not be negative")
+	}
+	if c.CommandCacheTTL < 0 {
+		return fmt.Errorf("command_cache_ttl must not be negative")
+	}
+	for name, profile := range c.EnvProfiles {
+		for variable, source := range profile {
+			if variable == "" || strings.Contains(variable, "=") {
+				return fmt.Errorf("env profile %q has an invalid variable name %q", name, variable)
+			}
+			if source == "env:" || source == "keychain:" {
+				return fmt.Errorf("env profile %q: %s has no %s name", name, variable, strings.TrimSuffix(source, ":"))
+			}
+		}
+	}
+	if c.MetricsAddr != "" {
+		if _, _, err := net.SplitHostPort(c.MetricsAddr); err != nil {
+			return fmt.Errorf("invalid metrics_addr: %w", err)
+		}
+	}
+	if c.DiffGuardrails.MaxFiles < 0 || c.DiffGuardrails.MaxLines < 0 {
+		return fmt.Errorf("diff_guardrails limits must not be negative")
+	}
+	switch c.Commit.SigningFormat {
+	case "", "openpgp", "ssh", "x509":
+	default:
+		return fmt.Errorf("commit.signing_format must be openpgp, ssh or x509")
+	}
+	if c.Commit.Author != "" && !regexp.MustCompile(`^[^<>]+ <[^<>]+>$`).MatchString(c.Commit.Author) {
+		return fmt.Errorf("commit.author must look like \"Name <email>\"")
+	}
+	if c.Retention.MaxSessions < 0 || c.Retention.MaxAgeDays < 0 || c.Retention.ArchiveAfterPausedDays < 0 {
+		return fmt.Errorf("retention limits must not be negative")
+	}
+	if c.Remote.Port < 0 || c.Remote.Port > 65535 {
+		return fmt.Errorf("remote.port must be between 0 and 65535")
+	}
+	if c.Sandbox.PidsLimit < 0 {
+		return fmt.Errorf("sandbox.pids_limit must not be negative")
+	}
+	switch c.Multiplexer {
+	case "", "tmux", "wezterm":
+	case "kubernetes":
+		if c.Kubernetes.Image == "" {
+			return fmt.Errorf("kubernetes.image must be set for the kubernetes multiplexer")
+		}
+	default:
+		return fmt.Errorf("multiplexer must be tmux, wezterm or kubernetes")
+	}
+	if c.Kubernetes.ReadyTimeout < 0 {
+		return fmt.Errorf("kubernetes.ready_timeout must not be negative")
+	}
+	switch c.GitBackend {
+	case "", "exec", "go-git":
+	default:
+		return fmt.Errorf("git_backend must be exec or go-git")
+	}
+	switch c.StorageBackend {
+	case "", "json", "sqlite", "bolt", "postgres":
+	default:
+		return fmt.Errorf("storage_backend must be json, sqlite, bolt or postgres")
+	}
+	for host, kind := range c.ForgeHosts {
+		switch kind {
+		case "github", "gitlab", "gitea":
+		default:
+			return fmt.Errorf("forge_hosts.%s must be github, gitlab or gitea", host)
+		}
+	}
+	for _, pattern := range c.RedactPatterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("invalid redact pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// Keys returns the settable config keys in dotted JSON form, e.g. "diff_guardrails.max_files"
+func Keys() []string {
+	var keys []string
+	var walk func(t reflect.Type, prefix string)
+	walk = func(t reflect.Type, prefix string) {
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			key := prefix + jsonName(f)
+			if f.Type.Kind() == reflect.Struct {
+				walk(f.Type, key+".")
+				continue
+			}
+			keys = append(keys, key)
+		}
+	}
+	walk(reflect.TypeOf(Config{}), "")
+	sort.Strings(keys)
+	return keys
+}
+
+// Get returns the value of a config key formatted for display. Lists are returned as JSON.
+func (c *Config) Get(key string) (string, error) {
+	v, err := c.field(key)
+	if err != nil {
+		return "", err
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Int:
+		return strconv.FormatInt(v.Int(), 10), nil
+	default:
+		data, err := json.Marshal(v.Interface())
+		if err != nil {
+			return "", fmt.Errorf("failed to format %s: %w", key, err)
+		}
+		return string(data), nil
+	}
+}
+
+// Set parses value into a config key and validates the result. List keys take a JSON
+// array, or a single string for a one-element list; an empty value clears them.
+func (c *Config) Set(key, value string) error {
+	v, err := c.field(key)
+	if err != nil {
+		return err
+	}
+
+	updated := *c
+	target := reflect.ValueOf(&updated).Elem()
+	for _, name := range c.fieldPath(key) {
+		target = target.FieldByName(name)
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		target.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("%s must be true or false", key)
+		}
+		target.SetBool(b)
+	case reflect.Int:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("%s must be an integer", key)
+		}
+		target.SetInt(int64(n))
+	case reflect.Slice:
+		var list []string
+		switch {
+		case strings.HasPrefix(strings.TrimSpace(value), "["):
+			if err := json.Unmarshal([]byte(value), &list); err != nil {
+				return fmt.Errorf("%s must be a JSON array of strings: %w", key, err)
+			}
+		case value != "":
+			list = []string{value}
+		}
+		target.Set(reflect.ValueOf(list))
+	default:
+		return fmt.Errorf("%s can't be set from the command line", key)
+	}
+
+	if err := updated.Validate(); err != nil {
+		return err
+	}
+	*c = updated
+	return nil
+}
+
+// field resolves a dotted key to the config field it names
+func (c *Config) field(key string) (reflect.Value, error) {
+	path := c.fieldPath(key)
+	if path == nil {
+		return reflect.Value{}, fmt.Errorf("unknown config key '%s' (see 'cs config get')", key)
+	}
+	v := reflect.ValueOf(c).Elem()
+	for _, name := range path {
+		v = v.FieldByName(name)
+	}
+	return v, nil
+}
+
+// fieldPath maps a dotted key to struct field names. Each segment matches either the
+// JSON name or the Go field name, case-insensitively.
+func (c *Config) fieldPath(key string) []string {
+	t := reflect.TypeOf(*c)
+	var path []string
+	for _, segment := range strings.Split(key, ".") {
+		if t.Kind() != reflect.Struct {
+			return nil
+		}
+		found := false
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if strings.EqualFold(segment, jsonName(f)) || strings.EqualFold(segment, f.Name) {
+				path = append(path, f.Name)
+				t = f.Type
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil
+		}
+	}
+	if t.Kind() == reflect.Struct {
+		return nil
+	}
+	return path
+}
+
+func jsonName(f reflect.StructField) string {
+	name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	if name == "" {
+		return f.Name
+	}
+	return name
+}
@@ -0,0 +1,42 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigGetSet(t *testing.T) {
+	cfg := &Config{DefaultProgram: "claude", DaemonPollInterval: 1000}
+
+	require.NoError(t, cfg.Set("auto_yes", "true"))
+	assert.True(t, cfg.AutoYes)
+
+	require.NoError(t, cfg.Set("DaemonPollInterval", "250"))
+	value, err := cfg.Get("daemon_poll_interval")
+	require.NoError(t, err)
+	assert.Equal(t, "250", value)
+
+	require.NoError(t, cfg.Set("diff_guardrails.max_files", "20"))
+	assert.Equal(t, 20, cfg.DiffGuardrails.MaxFiles)
+
+	require.NoError(t, cfg.Set("retention.max_age_days", "30"))
+	assert.Equal(t, 30, cfg.Retention.MaxAgeDays)
+
+	require.NoError(t, cfg.Set("redact_patterns", `["secret-\\d+", "token"]`))
+	assert.Equal(t, []string{`secret-\d+`, "token"}, cfg.RedactPatterns)
+
+	t.Run("rejects invalid values without changing the config", func(t *testing.T) {
+		assert.Error(t, cfg.Set("daemon_poll_interval", "0"))
+		assert.Error(t, cfg.Set("daemon_poll_interval", "fast"))
+		assert.Error(t, cfg.Set("redact_patterns", "("))
+		assert.Error(t, cfg.Set("diff_guardrails", "1"))
+		assert.Error(t, cfg.Set("no_such_key", "1"))
+		assert.Error(t, cfg.Set("storage_backend", "mysql"))
+		assert.Error(t, cfg.Set("retention.max_sessions", "-1"))
+		assert.Equal(t, 250, cfg.DaemonPollInterval)
+	})
+
+	assert.Contains(t, Keys(), "diff_guardrails.pause_on_exceed")
+}
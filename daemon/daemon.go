@@ -3,6 +3,7 @@ package daemon
 import (
 	"claude-squad/config"
 	"claude-squad/log"
+	"claude-squad/services/executor"
 	"claude-squad/session"
 	"fmt"
 	"os"
@@ -14,6 +15,9 @@ import (
 	"time"
 )
 
+// reapInterval is how often the daemon cleans up exited processes nothing waited for
+const reapInterval = 30 * time.Second
+
 // RunDaemon runs the daemon process which iterates over all sessions and runs AutoYes mode on them.
 // It's expected that the main process kills the daemon when the main process starts.
 func RunDaemon(cfg *config.Config) error {
@@ -71,6 +75,23 @@ func RunDaemon(cfg *config.Config) error {
 		}
 	}()
 
+	// Agents of crashed sessions are adopted rather than left to init, so they don't
+	// linger as zombies of the daemon once they exit
+	reaper := executor.NewReaper(reapInterval, func(event executor.ReapEvent) {
+		if event.Orphan {
+			log.InfoLog.Printf("reaped orphaned process %d (exit code %d)", event.PID, event.ExitCode)
+		} else {
+			log.InfoLog.Printf("reaped process %d of %s (exit code %d)", event.PID, event.Command, event.ExitCode)
+		}
+	})
+	if err := reaper.AdoptOrphans(); err != nil {
+		log.InfoLog.Printf("not adopting orphaned processes: %v", err)
+	}
+	reaper.Start()
+	defer reaper.Stop()
+
+	startMaintenance(cfg, wg, stopCh, reaper)
+
 	// Notify on SIGINT (Ctrl+C) and SIGTERM. Save instances before
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
@@ -87,6 +108,46 @@ func RunDaemon(cfg *config.Config) error {
 	return nil
 }
 
+// PIDFile returns the path of the file holding the running daemon's PID.
+func PIDFile() (string, error) {
+	configDir, err := config.GetConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get config directory: %w", err)
+	}
+	return filepath.Join(configDir, "daemon.pid"), nil
+}
+
+// readPID returns the PID recorded in the PID file, or 0 if there is no PID file.
+func readPID() (int, error) {
+	pidFile, err := PIDFile()
+	if err != nil {
+		return 0, err
+	}
+	data, err := os.ReadFile(pidFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to read PID file: %w", err)
+	}
+
+	var pid int
+	if _, err := fmt.Sscanf(string(data), "%d", &pid); err != nil {
+		return 0, fmt.Errorf("invalid PID file format: %w", err)
+	}
+	return pid, nil
+}
+
+// Status returns the PID of the running daemon and whether it is alive. A PID file left
+// behind by a daemon that has exited reports the stale PID with running false.
+func Status() (pid int, running bool, err error) {
+	pid, err = readPID()
+	if err != nil || pid == 0 {
+		return pid, false, err
+	}
+	return pid, processAlive(pid), nil
+}
+
 // LaunchDaemon launches the daemon process.
 func LaunchDaemon() error {
 	// Find the claude squad binary.
@@ -95,7 +156,16 @@ func LaunchDaemon() error {
 		return fmt.Errorf("failed to get executable path: %w", err)
 	}
 
-	cmd := exec.Command(execPath, "--daemon")
+	// Resolve the PID file first so a daemon is never started without one
+	pidFile, err := PIDFile()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(pidFile), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	cmd := exec.Command(execPath, "daemon", "run")
 
 	// Detach the process from the parent
 	cmd.Stdin = nil
@@ -112,12 +182,6 @@ func LaunchDaemon() error {
 	log.InfoLog.Printf("started daemon child process with PID: %d", cmd.Process.Pid)
 
 	// Save PID to a file for later management
-	pidDir, err := config.GetConfigDir()
-	if err != nil {
-		return fmt.Errorf("failed to get config directory: %w", err)
-	}
-
-	pidFile := filepath.Join(pidDir, "daemon.pid")
 	if err := os.WriteFile(pidFile, []byte(fmt.Sprintf("%d", cmd.Process.Pid)), 0644); err != nil {
 		return fmt.Errorf("failed to write PID file: %w", err)
 	}
@@ -129,39 +193,32 @@ func LaunchDaemon() error {
 // StopDaemon attempts to stop a running daemon process if it exists. Returns no error if the daemon is not found
 // (assumes the daemon does not exist).
 func StopDaemon() error {
-	pidDir, err := config.GetConfigDir()
-	if err != nil {
-		return fmt.Errorf("failed to get config directory: %w", err)
+	pid, running, err := Status()
+	if err != nil || pid == 0 {
+		return err
 	}
 
-	pidFile := filepath.Join(pidDir, "daemon.pid")
-	data, err := os.ReadFile(pidFile)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return nil
+	if running {
+		proc, err := os.FindProcess(pid)
+		if err != nil {
+			return fmt.Errorf("failed to find daemon process: %w", err)
+		}
+		if err := proc.Kill(); err != nil {
+			return fmt.Errorf("failed to stop daemon process: %w", err)
 		}
-		return fmt.Errorf("failed to read PID file: %w", err)
-	}
-
-	var pid int
-	if _, err := fmt.Sscanf(string(data), "%d", &pid); err != nil {
-		return fmt.Errorf("invalid PID file format: %w", err)
 	}
 
-	proc, err := os.FindProcess(pid)
+	// Clean up PID file
+	pidFile, err := PIDFile()
 	if err != nil {
-		return fmt.Errorf("failed to find daemon process: %w", err)
+		return err
 	}
-
-	if err := proc.Kill(); err != nil {
-		return fmt.Errorf("failed to stop daemon process: %w", err)
-	}
-
-	// Clean up PID file
 	if err := os.Remove(pidFile); err != nil {
 		return fmt.Errorf("failed to remove PID file: %w", err)
 	}
 
-	log.InfoLog.Printf("daemon process (PID: %d) stopped successfully", pid)
+	if running {
+		log.InfoLog.Printf("daemon process (PID: %d) stopped successfully", pid)
+	}
 	return nil
 }
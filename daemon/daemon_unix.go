@@ -6,6 +6,13 @@ import (
 	"syscall"
 )
 
+// processAlive reports whether a process with the given PID exists
+func processAlive(pid int) bool {
+	// Signal 0 performs error checking only; EPERM means the process exists but isn't ours
+	err := syscall.Kill(pid, 0)
+	return err == nil || err == syscall.EPERM
+}
+
 // getSysProcAttr returns platform-specific process attributes for detaching the child process
 func getSysProcAttr() *syscall.SysProcAttr {
 	return &syscall.SysProcAttr{
@@ -7,6 +7,22 @@ import (
 	"syscall"
 )
 
+// processAlive reports whether a process with the given PID exists
+func processAlive(pid int) bool {
+	h, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, uint32(pid))
+	if err != nil {
+		return false
+	}
+	defer windows.CloseHandle(h)
+
+	var code uint32
+	if err := windows.GetExitCodeProcess(h, &code); err != nil {
+		return false
+	}
+	// STILL_ACTIVE
+	return code == 259
+}
+
 // getSysProcAttr returns platform-specific process attributes for detaching the child process
 func getSysProcAttr() *syscall.SysProcAttr {
 	return &syscall.SysProcAttr{
@@ -0,0 +1,106 @@
+package daemon
+
+import (
+	"bufio"
+	"claude-squad/log"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// logPollInterval is how often FollowLogs checks the log file for new lines.
+const logPollInterval = 500 * time.Millisecond
+
+// WriteLogs writes the last n daemon lines of the log file to w. If n <= 0, all daemon
+// lines are written. It returns the offset reached in the log file for FollowLogs.
+func WriteLogs(w io.Writer, n int) (int64, error) {
+	f, err := os.Open(log.FilePath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to open log file: %w", err)
+	}
+	defer f.Close()
+
+	var lines []string
+	var offset int64
+	reader := bufio.NewReader(f)
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			// Leave a partially written last line for FollowLogs to pick up
+			break
+		}
+		offset += int64(len(line))
+		if strings.HasPrefix(line, log.DaemonPrefix) {
+			lines = append(lines, line)
+			if n > 0 && len(lines) > n {
+				lines = lines[1:]
+			}
+		}
+	}
+
+	for _, line := range lines {
+		if _, err := io.WriteString(w, line); err != nil {
+			return offset, err
+		}
+	}
+	return offset, nil
+}
+
+// FollowLogs writes daemon lines appended to the log file after offset to w until ctx is
+// done. If the log file is truncated, it starts again from the beginning.
+func FollowLogs(ctx context.Context, w io.Writer, offset int64) error {
+	ticker := time.NewTicker(logPollInterval)
+	defer ticker.Stop()
+
+	var partial string
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		f, err := os.Open(log.FilePath())
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return fmt.Errorf("failed to open log file: %w", err)
+		}
+
+		if info, err := f.Stat(); err == nil && info.Size() < offset {
+			offset, partial = 0, ""
+		}
+		if _, err := f.Seek(offset, io.SeekStart); err != nil {
+			f.Close()
+			return fmt.Errorf("failed to seek log file: %w", err)
+		}
+
+		data, err := io.ReadAll(f)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("failed to read log file: %w", err)
+		}
+		offset += int64(len(data))
+
+		chunk := partial + string(data)
+		end := strings.LastIndexByte(chunk, '\n')
+		partial = chunk[end+1:]
+		if end < 0 {
+			continue
+		}
+		for _, line := range strings.SplitAfter(chunk[:end+1], "\n") {
+			if strings.HasPrefix(line, log.DaemonPrefix) {
+				if _, err := io.WriteString(w, line); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"claude-squad/config"
+	"claude-squad/log"
+	"claude-squad/services/executor"
+	"claude-squad/services/git"
+	"claude-squad/services/session"
+	"claude-squad/services/storage"
+	"claude-squad/services/tmux"
+	"claude-squad/services/types"
+)
+
+// maintenanceInterval is how often the daemon enforces the retention policy
+const maintenanceInterval = time.Hour
+
+// retentionPolicy converts the configured retention limits
+func retentionPolicy(cfg config.Retention) types.RetentionPolicy {
+	day := 24 * time.Hour
+	return types.RetentionPolicy{
+		MaxSessions:        cfg.MaxSessions,
+		MaxAge:             time.Duration(cfg.MaxAgeDays) * day,
+		ArchiveAfterPaused: time.Duration(cfg.ArchiveAfterPausedDays) * day,
+	}
+}
+
+// commitSettings converts how sessions' commits are made
+func commitSettings(cfg config.CommitSettings) types.CommitSettings {
+	return types.CommitSettings{
+		RunHooks:      &cfg.RunHooks,
+		Sign:          &cfg.Sign,
+		SigningKey:    cfg.SigningKey,
+		SigningFormat: cfg.SigningFormat,
+		Author:        cfg.Author,
+	}
+}
+
+// kubernetesOptions converts the configured cluster of the kubernetes multiplexer
+func kubernetesOptions(cfg config.Kubernetes) tmux.KubernetesOptions {
+	return tmux.KubernetesOptions{
+		Context:      cfg.Context,
+		Namespace:    cfg.Namespace,
+		Image:        cfg.Image,
+		WorkDir:      cfg.WorkDir,
+		ReadyTimeout: time.Duration(cfg.ReadyTimeout) * time.Second,
+	}
+}
+
+// openStorage opens the configured session store, with changes recorded in the audit log
+// as made by the daemon
+func openStorage(cfg *config.Config) (storage.StorageRepository, *storage.AuditLog, error) {
+	configDir, err := config.GetConfigDir()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get config directory: %w", err)
+	}
+	dir := filepath.Join(configDir, "sessions")
+
+	repo, err := storage.NewRepository(cfg.StorageBackend, dir, cfg.StorageDSN)
+	if err != nil {
+		return nil, nil, err
+	}
+	if cfg.EncryptStorage {
+		key, err := storage.LoadEncryptionKey()
+		if err != nil {
+			return nil, nil, err
+		}
+		if repo, err = storage.NewEncryptedRepository(repo, key); err != nil {
+			return nil, nil, err
+		}
+	}
+	auditLog := storage.NewAuditLog(filepath.Join(dir, storage.AuditFileName))
+	return storage.NewAuditedRepository(repo, auditLog, storage.ActorDaemon), auditLog, nil
+}
+
+// verifyStorage logs the session records that are damaged, so they are noticed before
+// `cs doctor` is next run
+func verifyStorage(repo storage.StorageRepository) {
+	report, err := repo.Verify(context.Background())
+	if err != nil {
+		log.ErrorLog.Printf("failed to verify session store: %v", err)
+		return
+	}
+	for _, record := range report.Corrupt {
+		log.WarningLog.Printf("session record %s is damaged (%s), run `cs doctor --repair`", record.Record, record.Problem)
+	}
+}
+
+// startMaintenance verifies the session store, then enforces the retention policy on
+// startup and periodically, and checkpoints and samples the diffs of running sessions if
+// configured to, until stopCh is closed. Processes it leaves unwaited for are cleaned up by reaper.
+func startMaintenance(cfg *config.Config, wg *sync.WaitGroup, stopCh <-chan struct{}, reaper *executor.Reaper) {
+	repo, auditLog, err := openStorage(cfg)
+	if err != nil {
+		log.ErrorLog.Printf("failed to open session store for maintenance: %v", err)
+		return
+	}
+	verifyStorage(repo)
+
+	policy := retentionPolicy(cfg.Retention)
+	checkpointInterval := time.Duration(cfg.CheckpointInterval) * time.Second
+	diffHistoryInterval := time.Duration(cfg.DiffHistoryInterval) * time.Second
+	if policy == (types.RetentionPolicy{}) && checkpointInterval == 0 && diffHistoryInterval == 0 {
+		return
+	}
+	exec := executor.NewExecutor(&executor.ExecutorOptions{
+		DefaultTimeout: 120 * time.Second,
+		MaxConcurrent:  10,
+		Pools:          executor.DefaultPools(),
+		RateLimits:     executor.DefaultRateLimits(),
+		CaptureOutput:  true,
+		Reaper:         reaper,
+		StallTimeout:   time.Duration(cfg.StallTimeout) * time.Second,
+		OnStall: func(event executor.StallEvent) {
+			if !event.Resumed {
+				log.WarningLog.Printf("command stalled with no output for %v: %s", event.Silent.Round(time.Second), event.Command)
+			}
+		},
+	})
+	tmuxService, err := tmux.NewService(cfg.Multiplexer, exec, kubernetesOptions(cfg.Kubernetes))
+	if err != nil {
+		log.ErrorLog.Printf("failed to start maintenance: %v", err)
+		return
+	}
+	orchestrator := session.NewOrchestrator(git.NewGitService(exec), tmuxService, repo, exec,
+		session.WithAuditLog(auditLog),
+		session.WithCheckpointInterval(checkpointInterval),
+		session.WithDiffHistory(diffHistoryInterval, cfg.DiffHistoryPatches),
+		session.WithCommitSettings(commitSettings(cfg.Commit)))
+
+	// Each returns at once when not configured
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-stopCh
+		cancel()
+	}()
+	for _, run := range []func(context.Context){orchestrator.RunCheckpoints, orchestrator.RunDiffHistory} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			run(ctx)
+		}()
+	}
+	if policy == (types.RetentionPolicy{}) {
+		return
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(maintenanceInterval)
+		defer ticker.Stop()
+		for {
+			enforceRetention(orchestrator, policy)
+			select {
+			case <-stopCh:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func enforceRetention(orchestrator session.SessionOrchestrator, policy types.RetentionPolicy) {
+	report, err := orchestrator.EnforceRetention(context.Background(), policy)
+	if err != nil {
+		log.ErrorLog.Printf("failed to enforce retention policy: %v", err)
+	}
+	if report == nil {
+		return
+	}
+	for _, s := range report.Archived {
+		log.InfoLog.Printf("retention: archived %s, paused since %s", s.Title, s.UpdatedAt.Format(time.RFC3339))
+	}
+	for _, s := range report.Trashed {
+		log.InfoLog.Printf("retention: moved %s to the trash, over the session limit", s.Title)
+	}
+	for _, s := range report.Expired {
+		log.InfoLog.Printf("retention: moved %s to the trash, last updated %s", s.Title, s.UpdatedAt.Format(time.RFC3339))
+	}
+	for _, s := range report.Purged {
+		log.InfoLog.Printf("retention: deleted %s, in the trash since %s", s.Title, s.DeletedAt.Format(time.RFC3339))
+	}
+}
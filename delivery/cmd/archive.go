@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"context"
+
+	"claude-squad/interface/facade"
+
+	"github.com/spf13/cobra"
+)
+
+// NewArchiveCmd creates a command that archives sessions, freeing their worktrees and tmux
+// sessions while keeping their branches, prompts and a snapshot of their uncommitted diff
+func NewArchiveCmd(sessionManager facade.SessionManager) *cobra.Command {
+	var (
+		all    bool
+		filter sessionFilter
+	)
+
+	cmd := &cobra.Command{
+		Use:   "archive [session-title-or-id]",
+		Short: "Archive a session, or all sessions matching a filter with --all",
+		Long: `Archive a session. Its worktree and tmux session are removed, but its branch, prompt,
+metadata and a snapshot of its uncommitted changes are kept. Archived sessions are
+hidden from 'cs list' unless --archived is given; 'cs diff --patch' shows the snapshot.`,
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeSessions(sessionManager),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runBulk(context.Background(), sessionManager, args, all, &filter, bulkAction{
+				verb:      "archive",
+				pastTense: "archived",
+				skip: func(sess facade.SessionInfo) bool {
+					return sess.Archived
+				},
+				run: sessionManager.ArchiveSession,
+			})
+		},
+	}
+
+	cmd.Flags().BoolVar(&all, "all", false, "Archive all sessions matching the filters")
+	filter.addFlags(cmd)
+
+	return cmd
+}
+
+// NewUnarchiveCmd creates a command that returns archived sessions to the paused state
+func NewUnarchiveCmd(sessionManager facade.SessionManager) *cobra.Command {
+	var (
+		all    bool
+		resume bool
+		filter sessionFilter
+	)
+
+	cmd := &cobra.Command{
+		Use:               "unarchive [session-title-or-id]",
+		Short:             "Unarchive a session, or all archived sessions matching a filter with --all",
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeSessions(sessionManager),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			action := bulkAction{
+				verb:      "unarchive",
+				pastTense: "unarchived",
+				skip: func(sess facade.SessionInfo) bool {
+					return !sess.Archived
+				},
+				run: sessionManager.UnarchiveSession,
+			}
+			if resume {
+				action.run = func(ctx context.Context, id string) error {
+					if err := sessionManager.UnarchiveSession(ctx, id); err != nil {
+						return err
+					}
+					return sessionManager.ResumeSession(ctx, id)
+				}
+				action.pastTense = "unarchived and resumed"
+			}
+			return runBulk(context.Background(), sessionManager, args, all, &filter, action)
+		},
+	}
+
+	cmd.Flags().BoolVar(&all, "all", false, "Unarchive all archived sessions matching the filters")
+	cmd.Flags().BoolVar(&resume, "resume", false, "Resume the sessions after unarchiving them")
+	filter.addFlags(cmd)
+
+	return cmd
+}
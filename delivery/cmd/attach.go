@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"claude-squad/interface/facade"
+
+	"github.com/spf13/cobra"
+)
+
+// NewAttachCmd creates a command that attaches the terminal to a session's tmux session
+func NewAttachCmd(sessionManager facade.SessionManager, sessionInteractor facade.SessionInteractor) *cobra.Command {
+	return &cobra.Command{
+		Use:               "attach [session-title-or-id]",
+		Short:             "Attach to a session's tmux session",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeSessions(sessionManager),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+
+			sess, err := resolveSession(ctx, sessionManager, args[0])
+			if err != nil {
+				return err
+			}
+
+			if err := sessionInteractor.AttachSession(ctx, sess.ID); err != nil {
+				return fmt.Errorf("failed to attach to session '%s': %w", sess.Title, err)
+			}
+			return nil
+		},
+	}
+}
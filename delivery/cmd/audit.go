@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"claude-squad/interface/facade"
+
+	"github.com/spf13/cobra"
+)
+
+// NewAuditCmd creates a command that lists the recorded changes to a session
+func NewAuditCmd(sessionManager facade.SessionManager, sessionViewer facade.SessionViewer) *cobra.Command {
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "audit <session-title-or-id>",
+		Short: "List the changes made to a session and what made them",
+		Long: `List every recorded create, update, status change and delete of a session, with
+when it happened and whether the CLI, TUI or daemon made it. Sessions that have been
+deleted or renamed away can still be looked up by their old ID or title.`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeSessions(sessionManager),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := validateOutputFormat(output); err != nil {
+				return err
+			}
+
+			// Look up a live session's changes by ID, so an older session with the same
+			// title doesn't show up; anything else is looked up as given
+			ctx := context.Background()
+			ref := args[0]
+			if sess, err := resolveSession(ctx, sessionManager, ref); err == nil {
+				ref = sess.ID
+			}
+
+			entries, err := sessionViewer.GetAuditLog(ctx, ref)
+			if err != nil {
+				return fmt.Errorf("failed to read audit log: %w", err)
+			}
+
+			if output != outputText {
+				return writeStructured(output, entries)
+			}
+
+			if len(entries) == 0 {
+				fmt.Printf("No changes recorded for '%s'\n", args[0])
+				return nil
+			}
+			for _, entry := range entries {
+				line := fmt.Sprintf("%s  %-6s  %-6s  %s", entry.Time.Local().Format("2006-01-02 15:04:05"), entry.Actor, entry.Op, entry.Title)
+				if entry.Detail != "" {
+					line += " (" + entry.Detail + ")"
+				}
+				fmt.Println(line)
+			}
+			return nil
+		},
+	}
+
+	addOutputFlag(cmd, &output)
+
+	return cmd
+}
@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"claude-squad/interface/facade"
+
+	"github.com/spf13/cobra"
+)
+
+// NewBlameCmd creates a command that shows who last changed each line of a file in a
+// session's worktree, or the commits that changed it
+func NewBlameCmd(sessionManager facade.SessionManager, diffViewer facade.DiffViewer) *cobra.Command {
+	var (
+		output  string
+		history bool
+		limit   int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "blame [session-title-or-id] [file]",
+		Short: "Show which commits last changed the lines of a file in a session",
+		Long: `Show the commit that last changed each line of a file in a session's worktree, to
+review what an agent changed in the context of what was there before. Lines the agent
+changed but hasn't committed are marked as such. With --history, list the commits that
+changed the file instead, following it across renames. The file is relative to the top
+of the worktree.`,
+		Example: `  cs blame mysession internal/server/handler.go
+  cs blame mysession internal/server/handler.go --history -n 10`,
+		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: completeBlameArgs(sessionManager, diffViewer),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := validateOutputFormat(output); err != nil {
+				return err
+			}
+			if limit < 0 {
+				return fmt.Errorf("--limit must not be negative")
+			}
+
+			ctx := context.Background()
+			sess, err := resolveSession(ctx, sessionManager, args[0])
+			if err != nil {
+				return err
+			}
+			path := args[1]
+
+			if history {
+				commits, err := diffViewer.GetFileHistory(ctx, sess.ID, path, limit)
+				if err != nil {
+					return fmt.Errorf("failed to get history of %s: %w", path, err)
+				}
+				if output != outputText {
+					return writeStructured(output, commits)
+				}
+				if len(commits) == 0 {
+					fmt.Printf("No commits changed %s\n", path)
+					return nil
+				}
+				for _, commit := range commits {
+					fmt.Printf("%s %s %-20s %s\n", shortHash(commit.Hash), commit.Time.Format("2006-01-02"),
+						commit.Author, commit.Message)
+				}
+				return nil
+			}
+
+			lines, err := diffViewer.Blame(ctx, sess.ID, path)
+			if err != nil {
+				return fmt.Errorf("failed to blame %s: %w", path, err)
+			}
+			if output != outputText {
+				return writeStructured(output, lines)
+			}
+			printBlame(lines)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&history, "history", false, "List the commits that changed the file instead")
+	cmd.Flags().IntVarP(&limit, "limit", "n", 0, "With --history, list at most this many commits (0 for all)")
+	addOutputFlag(cmd, &output)
+
+	return cmd
+}
+
+// completeBlameArgs completes the session, then the files changed in it
+func completeBlameArgs(sessionManager facade.SessionManager, diffViewer facade.DiffViewer) completionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		switch len(args) {
+		case 0:
+			return sessionCompletions(sessionManager, args, toComplete), cobra.ShellCompDirectiveNoFileComp
+		case 1:
+			ctx := context.Background()
+			sess, err := resolveSession(ctx, sessionManager, args[0])
+			if err != nil {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+			stats, err := diffViewer.GetDiffStats(ctx, sess.ID)
+			if err != nil {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+			var paths []string
+			for _, file := range stats.Files {
+				if file.Status != "deleted" && strings.HasPrefix(file.Path, toComplete) {
+					paths = append(paths, file.Path)
+				}
+			}
+			return paths, cobra.ShellCompDirectiveNoFileComp
+		default:
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+	}
+}
+
+// printBlame prints each line after the commit that last changed it, like git blame
+func printBlame(lines []facade.BlameLine) {
+	const uncommitted = "Not committed"
+	author := 0
+	for _, line := range lines {
+		if line.Commit == nil {
+			author = max(author, len(uncommitted))
+		} else {
+			author = max(author, len(line.Commit.Author))
+		}
+	}
+	numbers := len(fmt.Sprint(len(lines)))
+
+	for _, line := range lines {
+		if line.Commit == nil {
+			fmt.Printf("%-7s %-*s %-10s %*d) %s\n", "-------", author, uncommitted, "", numbers, line.Line, line.Content)
+			continue
+		}
+		fmt.Printf("%s %-*s %s %*d) %s\n", shortHash(line.Commit.Hash), author, line.Commit.Author,
+			line.Commit.Time.Format("2006-01-02"), numbers, line.Line, line.Content)
+	}
+}
+
+// shortHash abbreviates a commit hash like git does
+func shortHash(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
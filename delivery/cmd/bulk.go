@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"claude-squad/interface/facade"
+)
+
+// bulkAction describes a command that operates on one session or, with --all, every
+// session matching a filter
+type bulkAction struct {
+	// verb and pastTense are used in messages, e.g. "kill" and "killed"
+	verb, pastTense string
+	// skip reports whether a session matched by --all should be left alone
+	skip func(sess facade.SessionInfo) bool
+	run  func(ctx context.Context, id string) error
+}
+
+// runBulk resolves the target sessions from args or the filter and applies the action
+func runBulk(ctx context.Context, sessionManager facade.SessionManager, args []string, all bool, filter *sessionFilter, action bulkAction) error {
+	if all == (len(args) == 1) {
+		return fmt.Errorf("specify either a session or --all")
+	}
+	if !all && filter.isSet() {
+		return fmt.Errorf("--status and --older-than can only be used with --all")
+	}
+
+	var targets []facade.SessionInfo
+	if all {
+		matched, err := filter.selectSessions(ctx, sessionManager)
+		if err != nil {
+			return err
+		}
+		for _, sess := range matched {
+			if action.skip == nil || !action.skip(sess) {
+				targets = append(targets, sess)
+			}
+		}
+	} else {
+		sess, err := resolveSession(ctx, sessionManager, args[0])
+		if err != nil {
+			return err
+		}
+		targets = append(targets, *sess)
+	}
+
+	if len(targets) == 0 {
+		fmt.Println("No matching sessions")
+		return nil
+	}
+
+	failed := 0
+	for _, sess := range targets {
+		if err := action.run(ctx, sess.ID); err != nil {
+			failed++
+			fmt.Printf("failed to %s '%s': %v\n", action.verb, sess.Title, err)
+			continue
+		}
+		fmt.Printf("Session '%s' %s\n", sess.Title, action.pastTense)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d sessions could not be %s", failed, len(targets), action.pastTense)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"claude-squad/interface/facade"
+
+	"github.com/spf13/cobra"
+)
+
+// NewCloneCmd creates a command that duplicates a session onto a fresh branch and worktree
+func NewCloneCmd(sessionManager facade.SessionManager) *cobra.Command {
+	var opts facade.CloneSessionOptions
+
+	cmd := &cobra.Command{
+		Use:   "clone [session-title-or-id]",
+		Short: "Start a new session with the same program and prompt as an existing one",
+		Long: `Start a new session with the same program and prompt as an existing one, on a fresh
+branch and worktree. With --from-branch the new branch starts from the source session's
+branch, so the clone continues from the source's committed work.`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeSessions(sessionManager),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+
+			source, err := resolveSession(ctx, sessionManager, args[0])
+			if err != nil {
+				return err
+			}
+
+			sess, err := sessionManager.CloneSession(ctx, source.ID, opts)
+			if err != nil {
+				return fmt.Errorf("failed to clone session '%s': %w", source.Title, err)
+			}
+
+			fmt.Printf("Cloned '%s' into session '%s' (%s)\n", source.Title, sess.Title, sess.ID)
+			fmt.Printf("  path:    %s\n", sess.Path)
+			fmt.Printf("  branch:  %s\n", sess.Branch)
+			fmt.Printf("  program: %s\n", sess.Program)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.Title, "title", "", "Title of the new session (defaults to '<source>-clone')")
+	cmd.Flags().StringVar(&opts.Branch, "branch", "", "Branch to create for the new session (defaults to one derived from the title)")
+	cmd.Flags().BoolVar(&opts.FromSourceBranch, "from-branch", false, "Start the new branch from the source session's branch instead of HEAD")
+
+	return cmd
+}
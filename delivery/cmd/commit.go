@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"claude-squad/interface/facade"
+
+	"github.com/spf13/cobra"
+)
+
+// NewCommitCmd creates a command that commits the changes in a session's worktree
+func NewCommitCmd(sessionManager facade.SessionManager, diffViewer facade.DiffViewer) *cobra.Command {
+	var (
+		opts        facade.CommitOptions
+		noStageAll  bool
+		interactive bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "commit [session-title-or-id] [file...]",
+		Short: "Commit the changes in a session's worktree",
+		Long: `Commit the changes in a session's worktree. Naming files, or picking them from the
+changed files with --interactive, commits only the changes to those files and leaves the
+rest uncommitted. Files are named relative to the session's worktree.`,
+		Example: `  cs commit mysession -m "checkpoint: tests passing"
+  cs commit mysession -m "Fix login" auth/login.go auth/login_test.go
+  cs commit mysession -m "Fix login" --interactive
+  cs commit mysession --amend`,
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: completeSessions(sessionManager),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+
+			if opts.Message == "" && !opts.Amend {
+				return fmt.Errorf("a commit message is required (--message), unless amending")
+			}
+			if interactive && len(args) > 1 {
+				return fmt.Errorf("name files or pick them with --interactive, not both")
+			}
+			opts.StageAll = !noStageAll
+			opts.Paths = args[1:]
+
+			sess, err := resolveSession(ctx, sessionManager, args[0])
+			if err != nil {
+				return err
+			}
+
+			if interactive {
+				stats, err := diffViewer.GetDiffStats(ctx, sess.ID)
+				if err != nil {
+					return fmt.Errorf("failed to list changed files: %w", err)
+				}
+				// Files left out of reviews are committed all the same
+				files := append(stats.Files, stats.Excluded...)
+				if len(files) == 0 {
+					return fmt.Errorf("session '%s' has no changes to commit", sess.Title)
+				}
+				if opts.Paths, err = pickFiles(cmd.InOrStdin(), cmd.OutOrStdout(), files); err != nil {
+					return err
+				}
+			}
+
+			commit, err := sessionManager.CommitSession(ctx, sess.ID, opts)
+			if err != nil {
+				return fmt.Errorf("failed to commit session '%s': %w", sess.Title, err)
+			}
+
+			hash := commit.Hash
+			if len(hash) > 7 {
+				hash = hash[:7]
+			}
+			fmt.Printf("[%s %s] %s\n", sess.Branch, hash, commit.Message)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.Message, "message", "m", "", "Commit message")
+	cmd.Flags().BoolVar(&opts.Amend, "amend", false, "Amend the last commit instead of creating a new one")
+	cmd.Flags().BoolVar(&noStageAll, "no-stage-all", false, "Only commit changes that are already staged")
+	cmd.Flags().BoolVarP(&interactive, "interactive", "i", false, "Pick the files to commit from the changed ones")
+
+	return cmd
+}
+
+// pickFiles lists the changed files numbered on out and reads which of them to commit
+// from in
+func pickFiles(in io.Reader, out io.Writer, files []facade.FileDiffStat) ([]string, error) {
+	for i, file := range files {
+		fmt.Fprintf(out, "%3d  %-9s %s (+%d -%d)\n", i+1, file.Status, file.Path, file.Added, file.Removed)
+	}
+	fmt.Fprint(out, "Files to commit (e.g. 1 3 5-7, or a for all): ")
+
+	line, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && line == "" {
+		return nil, fmt.Errorf("no files picked")
+	}
+	picked, err := parseSelection(line, len(files))
+	if err != nil {
+		return nil, err
+	}
+	paths := make([]string, len(picked))
+	for i, n := range picked {
+		paths[i] = files[n].Path
+	}
+	return paths, nil
+}
+
+// parseSelection parses space or comma separated numbers and ranges, counted from 1, of
+// n items into indexes in the order given. "a" selects them all.
+func parseSelection(selection string, n int) ([]int, error) {
+	fields := strings.FieldsFunc(selection, func(r rune) bool {
+		return r == ' ' || r == ',' || r == '\t' || r == '\n' || r == '\r'
+	})
+	if len(fields) == 1 && (fields[0] == "a" || fields[0] == "all") {
+		fields = []string{"1-" + strconv.Itoa(n)}
+	}
+
+	var picked []int
+	seen := make(map[int]bool)
+	for _, field := range fields {
+		from, to, isRange := strings.Cut(field, "-")
+		first, err := strconv.Atoi(from)
+		last := first
+		if err == nil && isRange {
+			last, err = strconv.Atoi(to)
+		}
+		if err != nil || first < 1 || last > n || first > last {
+			return nil, fmt.Errorf("invalid selection '%s', expected numbers from 1 to %d", field, n)
+		}
+		for i := first - 1; i < last; i++ {
+			if !seen[i] {
+				seen[i] = true
+				picked = append(picked, i)
+			}
+		}
+	}
+	if len(picked) == 0 {
+		return nil, fmt.Errorf("no files picked")
+	}
+	return picked, nil
+}
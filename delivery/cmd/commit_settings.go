@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"claude-squad/interface/facade"
+
+	"github.com/spf13/cobra"
+)
+
+// NewCommitSettingsCmd creates a command that shows or changes how a session's commits
+// are made
+func NewCommitSettingsCmd(sessionManager facade.SessionManager) *cobra.Command {
+	var (
+		settings facade.CommitSettings
+		runHooks bool
+		sign     bool
+		reset    bool
+		output   string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "commit-settings [session-title-or-id]",
+		Short: "Show or change how a session's commits are made",
+		Long: `Show or change whether a session's commits, made by 'cs commit' and 'cs finish', run
+the repository's hooks, are signed and who they are authored by. Settings a session
+doesn't have come from the commit section of the configuration. Only the settings given
+are changed; --reset drops all of the session's own.`,
+		Example: `  cs commit-settings mysession
+  cs commit-settings mysession --sign --signing-format ssh --signing-key ~/.ssh/id_ed25519.pub
+  cs commit-settings mysession --author "Review Bot <bot@example.com>" --run-hooks=false
+  cs commit-settings mysession --reset`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeSessions(sessionManager),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := validateOutputFormat(output); err != nil {
+				return err
+			}
+
+			ctx := context.Background()
+			sess, err := resolveSession(ctx, sessionManager, args[0])
+			if err != nil {
+				return err
+			}
+
+			flags := cmd.Flags()
+			changed := reset
+			for _, name := range []string{"run-hooks", "sign", "signing-key", "signing-format", "author"} {
+				changed = changed || flags.Changed(name)
+			}
+			if !changed {
+				if output != outputText {
+					return writeStructured(output, sess.CommitSettings)
+				}
+				printCommitSettings(sess.Title, sess.CommitSettings)
+				return nil
+			}
+
+			var updated *facade.CommitSettings
+			if !reset {
+				updated = &facade.CommitSettings{}
+				if sess.CommitSettings != nil {
+					*updated = *sess.CommitSettings
+				}
+				if flags.Changed("run-hooks") {
+					updated.RunHooks = &runHooks
+				}
+				if flags.Changed("sign") {
+					updated.Sign = &sign
+				}
+				if flags.Changed("signing-key") {
+					updated.SigningKey = settings.SigningKey
+				}
+				if flags.Changed("signing-format") {
+					updated.SigningFormat = settings.SigningFormat
+				}
+				if flags.Changed("author") {
+					updated.Author = settings.Author
+				}
+			}
+
+			if err := sessionManager.SetCommitSettings(ctx, sess.ID, updated); err != nil {
+				return fmt.Errorf("failed to change the commit settings of '%s': %w", sess.Title, err)
+			}
+			printCommitSettings(sess.Title, updated)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&runHooks, "run-hooks", false, "Run the repository's pre-commit and commit-msg hooks")
+	cmd.Flags().BoolVar(&sign, "sign", false, "Sign commits")
+	cmd.Flags().StringVar(&settings.SigningKey, "signing-key", "", "Key to sign with (default: git's user.signingkey)")
+	cmd.Flags().StringVar(&settings.SigningFormat, "signing-format", "", "Signature format: openpgp, ssh or x509")
+	cmd.Flags().StringVar(&settings.Author, "author", "", `Author of commits, as "Name <email>"`)
+	cmd.Flags().BoolVar(&reset, "reset", false, "Use the global settings for everything")
+	cmd.MarkFlagsMutuallyExclusive("reset", "run-hooks")
+	cmd.MarkFlagsMutuallyExclusive("reset", "sign")
+	cmd.MarkFlagsMutuallyExclusive("reset", "author")
+	_ = cmd.RegisterFlagCompletionFunc("signing-format", cobra.FixedCompletions([]string{"openpgp", "ssh", "x509"}, cobra.ShellCompDirectiveNoFileComp))
+	addOutputFlag(cmd, &output)
+
+	return cmd
+}
+
+// printCommitSettings lists the commit settings a session has of its own
+func printCommitSettings(title string, settings *facade.CommitSettings) {
+	if settings == nil || *settings == (facade.CommitSettings{}) {
+		fmt.Printf("Session '%s' uses the global commit settings\n", title)
+		return
+	}
+
+	fmt.Printf("Commit settings of session '%s':\n", title)
+	if settings.RunHooks != nil {
+		fmt.Printf("  run hooks:      %t\n", *settings.RunHooks)
+	}
+	if settings.Sign != nil {
+		fmt.Printf("  sign:           %t\n", *settings.Sign)
+	}
+	if settings.SigningKey != "" {
+		fmt.Printf("  signing key:    %s\n", settings.SigningKey)
+	}
+	if settings.SigningFormat != "" {
+		fmt.Printf("  signing format: %s\n", settings.SigningFormat)
+	}
+	if settings.Author != "" {
+		fmt.Printf("  author:         %s\n", settings.Author)
+	}
+}
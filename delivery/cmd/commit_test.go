@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"claude-squad/interface/facade"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSelection(t *testing.T) {
+	picked, err := parseSelection("3, 1-2 2\n", 4)
+	require.NoError(t, err)
+	assert.Equal(t, []int{2, 0, 1}, picked)
+
+	picked, err = parseSelection("a", 3)
+	require.NoError(t, err)
+	assert.Equal(t, []int{0, 1, 2}, picked)
+
+	for _, selection := range []string{"", "0", "5", "2-1", "x", "1-"} {
+		_, err := parseSelection(selection, 4)
+		assert.Error(t, err, selection)
+	}
+}
+
+func TestPickFiles(t *testing.T) {
+	files := []facade.FileDiffStat{
+		{Path: "a.go", Status: "modified", Added: 1},
+		{Path: "b.go", Status: "added", Added: 3},
+		{Path: "c.go", Status: "deleted", Removed: 2},
+	}
+	var out bytes.Buffer
+	paths, err := pickFiles(strings.NewReader("1 3\n"), &out, files)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a.go", "c.go"}, paths)
+	assert.Contains(t, out.String(), "  2  added     b.go (+3 -0)")
+
+	_, err = pickFiles(strings.NewReader(""), &out, files)
+	assert.Error(t, err)
+}
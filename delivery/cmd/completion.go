@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"claude-squad/config"
+	"claude-squad/interface/facade"
+
+	"github.com/spf13/cobra"
+)
+
+// completionFunc is the signature cobra uses for dynamic argument completion
+type completionFunc = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective)
+
+// completeSessions completes the first argument with the titles of existing sessions
+func completeSessions(sessionManager facade.SessionManager) completionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return sessionCompletions(sessionManager, args, toComplete), cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// completeSessionList completes every argument with session titles not already given
+func completeSessionList(sessionManager facade.SessionManager) completionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return sessionCompletions(sessionManager, args, toComplete), cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// sessionCompletions lists session titles starting with toComplete, described by their
+// status and branch
+func sessionCompletions(sessionManager facade.SessionManager, exclude []string, toComplete string) []string {
+	sessions, err := sessionManager.ListSessions(context.Background())
+	if err != nil {
+		cobra.CompDebugln(fmt.Sprintf("failed to list sessions: %v", err), true)
+		return nil
+	}
+
+	given := make(map[string]bool, len(exclude))
+	for _, arg := range exclude {
+		given[arg] = true
+	}
+
+	var completions []string
+	for _, sess := range sessions {
+		if given[sess.Title] || given[sess.ID] || !strings.HasPrefix(sess.Title, toComplete) {
+			continue
+		}
+		completions = append(completions, fmt.Sprintf("%s\t%s, %s", sess.Title, sess.Status, sess.Branch))
+	}
+	return completions
+}
+
+// completeStatuses completes a session status flag value
+func completeStatuses(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	var statuses []string
+	for _, status := range []facade.SessionStatus{facade.StatusRunning, facade.StatusReady, facade.StatusLoading, facade.StatusPaused} {
+		statuses = append(statuses, status.String())
+	}
+	return statuses, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeConfigKeys completes the first argument with config keys
+func completeConfigKeys(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return config.Keys(), cobra.ShellCompDirectiveNoFileComp
+}
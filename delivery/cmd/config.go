@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"claude-squad/config"
+
+	"github.com/spf13/cobra"
+)
+
+// NewConfigCmd creates the config command with its get, set and edit subcommands
+func NewConfigCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Read and change configuration values",
+		Long: `Read and change values in the config file. Keys use the names from the config
+file, with nested keys joined by dots (e.g. diff_guardrails.max_files).`,
+	}
+
+	cmd.AddCommand(newConfigGetCmd(), newConfigSetCmd(), newConfigEditCmd())
+
+	return cmd
+}
+
+func newConfigGetCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:               "get [key]",
+		Short:             "Print a config value, or all values when no key is given",
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeConfigKeys,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.ReadConfig()
+			if err != nil {
+				return err
+			}
+
+			if len(args) == 1 {
+				value, err := cfg.Get(args[0])
+				if err != nil {
+					return err
+				}
+				fmt.Println(value)
+				return nil
+			}
+
+			for _, key := range config.Keys() {
+				value, err := cfg.Get(key)
+				if err != nil {
+					return err
+				}
+				fmt.Printf("%s = %s\n", key, value)
+			}
+			return nil
+		},
+	}
+}
+
+func newConfigSetCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "set <key> <value>",
+		Short: "Change a config value",
+		Long: `Change a config value. The value is validated before the config file is written.
+List values such as redact_patterns take a JSON array; an empty value clears them.`,
+		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: completeConfigKeys,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.ReadConfig()
+			if err != nil {
+				return err
+			}
+
+			if err := cfg.Set(args[0], args[1]); err != nil {
+				return err
+			}
+			if err := config.SaveConfig(cfg); err != nil {
+				return fmt.Errorf("failed to save config: %w", err)
+			}
+			return nil
+		},
+	}
+}
+
+func newConfigEditCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "edit",
+		Short: "Open the config file in $EDITOR",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			configPath, err := config.ConfigPath()
+			if err != nil {
+				return err
+			}
+
+			// Write out the defaults first so there is something to edit
+			if _, err := os.Stat(configPath); os.IsNotExist(err) {
+				if err := config.SaveConfig(config.DefaultConfig()); err != nil {
+					return fmt.Errorf("failed to create config file: %w", err)
+				}
+			}
+
+			editor := strings.Fields(os.Getenv("EDITOR"))
+			if len(editor) == 0 {
+				editor = []string{"vi"}
+			}
+
+			c := exec.Command(editor[0], append(editor[1:], configPath)...)
+			c.Stdin = os.Stdin
+			c.Stdout = os.Stdout
+			c.Stderr = os.Stderr
+			if err := c.Run(); err != nil {
+				return fmt.Errorf("editor exited with an error: %w", err)
+			}
+
+			if _, err := config.ReadConfig(); err != nil {
+				return fmt.Errorf("config file is invalid, fix it with 'cs config edit': %w", err)
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"claude-squad/interface/facade"
+
+	"github.com/spf13/cobra"
+)
+
+// NewConflictsCmd creates a command that checks whether a session's branch still merges
+// cleanly into its base branch
+func NewConflictsCmd(sessionManager facade.SessionManager) *cobra.Command {
+	var (
+		base   string
+		output string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "conflicts [session-title-or-id]",
+		Short: "Show where a session's branch conflicts with its base branch",
+		Long: `Work out the merge of a session's committed changes into the base branch without
+touching either, and list the files that conflict with both sides of each conflicting
+hunk. The session is flagged as conflicted in list and status output until a later check
+finds it merges cleanly. The base branch defaults to the one checked out in the
+repository.`,
+		Example: `  cs conflicts mysession
+  cs conflicts mysession --base develop -o json`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeSessions(sessionManager),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := validateOutputFormat(output); err != nil {
+				return err
+			}
+
+			ctx := context.Background()
+			sess, err := resolveSession(ctx, sessionManager, args[0])
+			if err != nil {
+				return err
+			}
+
+			conflicts, err := sessionManager.CheckConflicts(ctx, sess.ID, base)
+			if err != nil {
+				return fmt.Errorf("failed to check session '%s' for conflicts: %w", sess.Title, err)
+			}
+
+			if output != outputText {
+				if conflicts == nil {
+					conflicts = []facade.FileConflict{}
+				}
+				return writeStructured(output, conflicts)
+			}
+
+			if len(conflicts) == 0 {
+				fmt.Printf("%s merges cleanly\n", sess.Branch)
+				return nil
+			}
+			fmt.Printf("%s conflicts in %d files:\n", sess.Branch, len(conflicts))
+			for _, conflict := range conflicts {
+				fmt.Printf("\n%s\n", conflict.Path)
+				if len(conflict.Hunks) == 0 {
+					fmt.Println("  the whole file conflicts, e.g. it was deleted on one side")
+				}
+				for _, hunk := range conflict.Hunks {
+					fmt.Printf("  line %d\n", hunk.Line)
+					printConflictSide("ours", hunk.Ours)
+					printConflictSide("theirs", hunk.Theirs)
+				}
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&base, "base", "", "Branch to check against (default: the branch checked out in the repository)")
+	addOutputFlag(cmd, &output)
+
+	return cmd
+}
+
+func printConflictSide(side, content string) {
+	fmt.Printf("    %s:\n", side)
+	if content == "" {
+		return
+	}
+	for _, line := range strings.Split(content, "\n") {
+		fmt.Printf("      %s\n", line)
+	}
+}
@@ -3,6 +3,8 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
 	"claude-squad/interface/facade"
 
@@ -11,52 +13,275 @@ import (
 
 // NewDiffCmd creates a diff command using the facade pattern
 func NewDiffCmd(sessionManager facade.SessionManager, diffViewer facade.DiffViewer) *cobra.Command {
-	return &cobra.Command{
-		Use:   "diff [session-title]",
+	var (
+		output     string
+		patch      bool
+		stat       bool
+		nameOnly   bool
+		status     bool
+		untracked  bool
+		submodules bool
+		history    bool
+		since      time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "diff [session-title-or-id]",
 		Short: "Show git diff for a session",
-		Args:  cobra.ExactArgs(1),
+		Long: `Show the uncommitted changes in a session's worktree. By default only the
+totals are printed; use --stat for per-file counts, --name-only for the changed paths,
+--patch for the full unified diff, --status for which changes are staged, --untracked
+for the new files git doesn't track yet, or --submodules for the state of submodules.
+--history lists how the totals changed over time, as recorded by the daemon every
+diff_history_interval seconds; --since limits it to the recent past.
+
+Files a repository excludes in the .claude-squad.json at its top, such as lockfiles and
+generated code, are left out of everything but --status and --untracked:
+
+  {"diff": {"exclude": ["*.lock", "gen/"], "include": ["gen/api.go"]}}
+
+Patterns are matched like .gitignore ones; include patterns take files back in.`,
+		Example: `  cs diff mysession --stat
+  cs diff mysession --history --since 2h`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeSessions(sessionManager),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			ctx := context.Background()
-			title := args[0]
+			if err := validateOutputFormat(output); err != nil {
+				return err
+			}
 
-			// Find session by title
-			sessions, err := sessionManager.ListSessions(ctx)
+			ctx := context.Background()
+			sess, err := resolveSession(ctx, sessionManager, args[0])
 			if err != nil {
-				return fmt.Errorf("failed to list sessions: %w", err)
+				return err
+			}
+			title := sess.Title
+
+			if status || untracked {
+				files, err := diffViewer.GetFileStatus(ctx, sess.ID)
+				if err != nil {
+					return fmt.Errorf("failed to get status: %w", err)
+				}
+				if untracked {
+					shown := files[:0]
+					for _, file := range files {
+						if file.Untracked {
+							shown = append(shown, file)
+						}
+					}
+					files = shown
+				}
+				if output != outputText {
+					return writeStructured(output, files)
+				}
+				if untracked {
+					for _, file := range files {
+						fmt.Println(file.Path)
+					}
+					return nil
+				}
+				printFileStatus(title, files)
+				return nil
 			}
 
-			var sessionID string
-			for _, sess := range sessions {
-				if sess.Title == title {
-					sessionID = sess.ID
-					break
+			if history {
+				var from time.Time
+				if since > 0 {
+					from = time.Now().Add(-since)
 				}
+				samples, err := diffViewer.GetDiffHistory(ctx, sess.ID, from)
+				if err != nil {
+					return fmt.Errorf("failed to get diff history: %w", err)
+				}
+				if output != outputText {
+					return writeStructured(output, samples)
+				}
+				printDiffHistory(title, samples)
+				return nil
+			}
+
+			if submodules {
+				list, err := diffViewer.GetSubmoduleStatus(ctx, sess.ID)
+				if err != nil {
+					return fmt.Errorf("failed to get submodule status: %w", err)
+				}
+				if output != outputText {
+					return writeStructured(output, list)
+				}
+				printSubmoduleStatus(title, list)
+				return nil
 			}
 
-			if sessionID == "" {
-				return fmt.Errorf("session '%s' not found", title)
+			if patch {
+				diff, err := diffViewer.GetDiffPatch(ctx, sess.ID)
+				if err != nil {
+					return fmt.Errorf("failed to get diff: %w", err)
+				}
+				if output != outputText {
+					return writeStructured(output, &facade.DiffStats{Content: diff})
+				}
+				fmt.Print(diff)
+				return nil
 			}
 
 			// Get diff stats
-			stats, err := diffViewer.GetDiffStats(ctx, sessionID)
+			stats, err := diffViewer.GetDiffStats(ctx, sess.ID)
 			if err != nil {
 				return fmt.Errorf("failed to get diff: %w", err)
 			}
 
-			if stats.Added == 0 && stats.Removed == 0 {
-				fmt.Println("No changes")
-				return nil
+			if output != outputText {
+				if !stat && !nameOnly {
+					stats.Files = nil
+				}
+				return writeStructured(output, stats)
 			}
 
-			fmt.Printf("Changes in session '%s':\n", title)
-			fmt.Printf("  +%d additions\n", stats.Added)
-			fmt.Printf("  -%d deletions\n", stats.Removed)
-
-			if stats.Content != "" {
-				fmt.Printf("\n%s\n", stats.Content)
+			if nameOnly {
+				for _, file := range stats.Files {
+					fmt.Println(file.Path)
+				}
+				return nil
 			}
 
+			switch {
+			case len(stats.Files) == 0:
+				fmt.Println("No changes")
+			case stat:
+				printDiffStat(stats)
+			default:
+				fmt.Printf("Changes in session '%s':\n", title)
+				fmt.Printf("  %d files changed\n", len(stats.Files))
+				fmt.Printf("  +%d additions\n", stats.Added)
+				fmt.Printf("  -%d deletions\n", stats.Removed)
+			}
+			if len(stats.Excluded) > 0 {
+				fmt.Printf("%d more files changed are excluded by the repository's .claude-squad.json\n", len(stats.Excluded))
+			}
 			return nil
 		},
 	}
-}
\ No newline at end of file
+
+	cmd.Flags().BoolVarP(&patch, "patch", "p", false, "Print the full unified diff")
+	cmd.Flags().BoolVar(&stat, "stat", false, "Print per-file added and removed line counts")
+	cmd.Flags().BoolVar(&nameOnly, "name-only", false, "Print only the paths of changed files")
+	cmd.Flags().BoolVar(&status, "status", false, "Print the staged, unstaged, untracked and conflicted files")
+	cmd.Flags().BoolVar(&untracked, "untracked", false, "Print only the paths of untracked files")
+	cmd.Flags().BoolVar(&submodules, "submodules", false, "Print the state of the worktree's submodules")
+	cmd.Flags().BoolVar(&history, "history", false, "Print how the totals changed over time")
+	cmd.Flags().DurationVar(&since, "since", 0, "With --history, only print changes recorded this recently (e.g. 2h)")
+	cmd.MarkFlagsMutuallyExclusive("patch", "stat", "name-only", "status", "untracked", "submodules", "history")
+	addOutputFlag(cmd, &output)
+
+	return cmd
+}
+
+// printDiffStat prints per-file counts in the style of git diff --stat
+func printDiffStat(stats *facade.DiffStats) {
+	width := 0
+	for _, file := range stats.Files {
+		width = max(width, len(file.Path))
+	}
+
+	for _, file := range stats.Files {
+		if file.LFS {
+			fmt.Printf(" %-*s | LFS\n", width, file.Path)
+			continue
+		}
+		if file.Binary {
+			fmt.Printf(" %-*s | Bin\n", width, file.Path)
+			continue
+		}
+		fmt.Printf(" %-*s | %5d %s%s\n", width, file.Path, file.Added+file.Removed,
+			strings.Repeat("+", min(file.Added, 40)), strings.Repeat("-", min(file.Removed, 40)))
+	}
+	fmt.Printf(" %d files changed, %d insertions(+), %d deletions(-)\n", len(stats.Files), stats.Added, stats.Removed)
+}
+
+// printFileStatus groups a session's changed files like git status does
+func printFileStatus(title string, files []facade.FileStatus) {
+	if len(files) == 0 {
+		fmt.Printf("No changes in session '%s'\n", title)
+		return
+	}
+
+	var staged, unstaged, untracked, conflicted []string
+	for _, file := range files {
+		path := file.Path
+		if file.OrigPath != "" {
+			path = file.OrigPath + " -> " + file.Path
+		}
+		switch {
+		case file.Conflicted:
+			conflicted = append(conflicted, path)
+		case file.Untracked:
+			untracked = append(untracked, path)
+		}
+		if file.Staged != "" {
+			staged = append(staged, fmt.Sprintf("%-11s %s", file.Staged+":", path))
+		}
+		if file.Unstaged != "" {
+			unstaged = append(unstaged, fmt.Sprintf("%-11s %s", file.Unstaged+":", path))
+		}
+	}
+
+	fmt.Printf("Changes in session '%s': %d staged, %d not staged, %d untracked\n",
+		title, len(staged), len(unstaged), len(untracked))
+	for _, group := range []struct {
+		heading string
+		lines   []string
+	}{
+		{"Conflicted", conflicted},
+		{"Staged", staged},
+		{"Not staged", unstaged},
+		{"Untracked", untracked},
+	} {
+		if len(group.lines) == 0 {
+			continue
+		}
+		fmt.Printf("\n%s:\n", group.heading)
+		for _, line := range group.lines {
+			fmt.Printf("  %s\n", line)
+		}
+	}
+}
+
+// printSubmoduleStatus lists a session's submodules with the commit each is at
+func printSubmoduleStatus(title string, submodules []facade.SubmoduleStatus) {
+	if len(submodules) == 0 {
+		fmt.Printf("Session '%s' has no submodules\n", title)
+		return
+	}
+
+	width := 0
+	for _, submodule := range submodules {
+		width = max(width, len(submodule.Path))
+	}
+	for _, submodule := range submodules {
+		commit := submodule.Commit
+		if len(commit) > 7 {
+			commit = commit[:7]
+		}
+		if submodule.Describe != "" {
+			commit += " (" + submodule.Describe + ")"
+		}
+		fmt.Printf("  %-13s %-*s  %s\n", submodule.State, width, submodule.Path, commit)
+	}
+}
+
+// printDiffHistory lists the recorded totals of a session's changes, then how much they
+// changed over the period
+func printDiffHistory(title string, samples []facade.DiffSample) {
+	if len(samples) == 0 {
+		fmt.Printf("No diff history recorded for session '%s'\n", title)
+		return
+	}
+
+	for _, sample := range samples {
+		fmt.Printf("  %s  %s  %3d files  +%-6d -%d\n", sample.Time.Format("2006-01-02 15:04:05"),
+			shortHash(sample.Head), sample.Files, sample.Added, sample.Removed)
+	}
+	first, last := samples[0], samples[len(samples)-1]
+	fmt.Printf("Since %s: %+d additions, %+d deletions\n", first.Time.Format("2006-01-02 15:04:05"),
+		last.Added-first.Added, last.Removed-first.Removed)
+}
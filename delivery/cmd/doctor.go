@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"claude-squad/interface/facade"
+
+	"github.com/spf13/cobra"
+)
+
+var checkSymbols = map[facade.CheckStatus]string{
+	facade.CheckOK:   "✓",
+	facade.CheckWarn: "!",
+	facade.CheckFail: "✗",
+}
+
+// NewDoctorCmd creates a command that diagnoses the environment claude-squad depends on
+func NewDoctorCmd(diagnostics facade.Diagnostics) *cobra.Command {
+	var repair bool
+
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Check tmux, git, the config directory and leftover sessions or worktrees",
+		Long: `Check tmux, git, the config directory and leftover sessions or worktrees.
+
+Every session record is checked against the checksum saved with it. With --repair, records
+that can't be read or fail their checksum are first restored from their previous version,
+or moved aside into the store's corrupt/ directory when there is none.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if repair {
+				if err := runRepair(context.Background(), diagnostics); err != nil {
+					return err
+				}
+			}
+
+			results := diagnostics.RunChecks(context.Background())
+
+			failed := 0
+			for _, r := range results {
+				fmt.Printf("%s %s: %s\n", checkSymbols[r.Status], r.Name, r.Message)
+				if r.Hint != "" {
+					fmt.Printf("    → %s\n", r.Hint)
+				}
+				if r.Status == facade.CheckFail {
+					failed++
+				}
+			}
+
+			if failed > 0 {
+				cmd.SilenceUsage = true
+				return fmt.Errorf("%d check(s) failed", failed)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&repair, "repair", false, "Recover or quarantine unreadable session records before checking")
+
+	return cmd
+}
+
+// runRepair repairs the session store and prints what it changed
+func runRepair(ctx context.Context, diagnostics facade.Diagnostics) error {
+	report, err := diagnostics.RepairStorage(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to repair storage: %w", err)
+	}
+
+	for _, path := range report.Quarantined {
+		fmt.Printf("moved unreadable session record to %s\n", path)
+	}
+	for _, id := range report.Restored {
+		fmt.Printf("restored session %s from its previous version\n", id)
+	}
+	if len(report.Quarantined) == 0 {
+		fmt.Println("session store is intact")
+	}
+	fmt.Println()
+	return nil
+}
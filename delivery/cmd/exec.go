@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"claude-squad/interface/facade"
+
+	"github.com/spf13/cobra"
+)
+
+// ExitError is returned by commands that fail with the exit status of a command they ran,
+// which has already reported its own failure. main exits with Code once its cleanup has run.
+type ExitError struct {
+	Code int
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("exit status %d", e.Code)
+}
+
+// NewExecCmd creates a command that runs an arbitrary command inside a session's worktree
+func NewExecCmd(sessionManager facade.SessionManager, sessionInteractor facade.SessionInteractor) *cobra.Command {
+	var shell bool
+
+	cmd := &cobra.Command{
+		Use:   "exec [session-title-or-id] -- <command> [args...]",
+		Short: "Run a command in a session's worktree",
+		Example: `  cs exec mysession -- go test ./...
+  cs exec mysession -- git log --oneline -5
+  cs exec --shell mysession -- 'go test ./... 2>&1 | tee test.log'`,
+		Args: cobra.MinimumNArgs(2),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) == 0 {
+				return completeSessions(sessionManager)(cmd, args, toComplete)
+			}
+			// Past the session, leave completion of the command to the shell
+			return nil, cobra.ShellCompDirectiveDefault
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+
+			sess, err := resolveSession(ctx, sessionManager, args[0])
+			if err != nil {
+				return err
+			}
+
+			code, err := sessionInteractor.Exec(ctx, sess.ID, facade.ExecOptions{
+				Program: args[1],
+				Args:    args[2:],
+				Shell:   shell,
+				Stdin:   os.Stdin,
+				Stdout:  os.Stdout,
+				Stderr:  os.Stderr,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to run command in session '%s': %w", sess.Title, err)
+			}
+			if code != 0 {
+				// The command has already reported its own failure; just propagate the status
+				cmd.SilenceUsage = true
+				cmd.SilenceErrors = true
+				return &ExitError{Code: code}
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVarP(&shell, "shell", "s", false, "Run the command line with your shell, allowing pipes and redirections")
+
+	return cmd
+}
+
+// NewReplayCmd creates a command that runs the last commands run with exec in a session's
+// worktree again
+func NewReplayCmd(sessionManager facade.SessionManager, sessionInteractor facade.SessionInteractor) *cobra.Command {
+	var count int
+
+	cmd := &cobra.Command{
+		Use:   "replay [session-title-or-id]",
+		Short: "Run the last commands run with exec in a session's worktree again",
+		Long: `Run the last commands run with exec in a session's worktree again, oldest first,
+e.g. to set its environment up again after resuming it. Replaying stops at the first
+command that fails.`,
+		Example: `  cs replay mysession
+  cs resume mysession && cs replay mysession -n 3`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeSessions(sessionManager),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if count <= 0 {
+				return fmt.Errorf("-n must be positive")
+			}
+			ctx := context.Background()
+
+			sess, err := resolveSession(ctx, sessionManager, args[0])
+			if err != nil {
+				return err
+			}
+			if err := sessionInteractor.Replay(ctx, sess.ID, count, os.Stdout, os.Stderr); err != nil {
+				return fmt.Errorf("failed to replay commands in session '%s': %w", sess.Title, err)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVarP(&count, "count", "n", 1, "Number of commands to replay")
+
+	return cmd
+}
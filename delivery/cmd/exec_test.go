@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"claude-squad/interface/facade"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeExecSessions struct {
+	facade.SessionManager
+}
+
+func (f *fakeExecSessions) ListSessions(ctx context.Context) ([]facade.SessionInfo, error) {
+	return []facade.SessionInfo{{ID: "s1", Title: "fix"}}, nil
+}
+
+type fakeExecInteractor struct {
+	facade.SessionInteractor
+	code int
+}
+
+func (f *fakeExecInteractor) Exec(ctx context.Context, id string, opts facade.ExecOptions) (int, error) {
+	return f.code, nil
+}
+
+func TestExecCmdExitCode(t *testing.T) {
+	interactor := &fakeExecInteractor{code: 3}
+	cmd := NewExecCmd(&fakeExecSessions{}, interactor)
+	cmd.SetArgs([]string{"fix", "--", "false"})
+
+	// A failing command's status is returned for main to exit with, not exited on the spot
+	err := cmd.Execute()
+	var exitErr *ExitError
+	require.True(t, errors.As(err, &exitErr))
+	assert.Equal(t, 3, exitErr.Code)
+
+	interactor.code = 0
+	assert.NoError(t, cmd.Execute())
+}
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"claude-squad/interface/facade"
+
+	"github.com/spf13/cobra"
+)
+
+// sessionFilter selects sessions for bulk commands such as `kill --all`
+type sessionFilter struct {
+	status    string
+	olderThan time.Duration
+}
+
+// addFlags registers the filter flags on cmd
+func (f *sessionFilter) addFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&f.status, "status", "", "Only match sessions with this status (running, ready, loading, paused)")
+	cmd.Flags().DurationVar(&f.olderThan, "older-than", 0, "Only match sessions created longer ago than this (e.g. 24h)")
+	_ = cmd.RegisterFlagCompletionFunc("status", completeStatuses)
+}
+
+// isSet reports whether any filter flag was given
+func (f *sessionFilter) isSet() bool {
+	return f.status != "" || f.olderThan > 0
+}
+
+// selectSessions lists sessions and returns those matching the filter
+func (f *sessionFilter) selectSessions(ctx context.Context, sessionManager facade.SessionManager) ([]facade.SessionInfo, error) {
+	var status *facade.SessionStatus
+	if f.status != "" {
+		s, err := facade.ParseSessionStatus(f.status)
+		if err != nil {
+			return nil, err
+		}
+		status = &s
+	}
+
+	sessions, err := sessionManager.ListSessions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	now := time.Now()
+	var matched []facade.SessionInfo
+	for _, sess := range sessions {
+		if status != nil && sess.Status != *status {
+			continue
+		}
+		if f.olderThan > 0 && now.Sub(sess.CreatedAt) < f.olderThan {
+			continue
+		}
+		matched = append(matched, sess)
+	}
+	return matched, nil
+}
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"claude-squad/interface/facade"
+
+	"github.com/spf13/cobra"
+)
+
+// NewFinishCmd creates a command that merges a session's branch back into its base branch
+func NewFinishCmd(sessionManager facade.SessionManager) *cobra.Command {
+	var (
+		opts   facade.FinishOptions
+		squash bool
+		rebase bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "finish [session-title-or-id]",
+		Short: "Merge a session's branch into its base branch and clean the session up",
+		Long: `Commit a session's pending changes, merge its branch into the base branch in the
+repository, then remove its worktree and move it to the trash. The base branch defaults
+to the one checked out in the repository, which must have no uncommitted changes. A
+merge that conflicts is aborted, leaving everything as it was. With --tag, the tip of the
+session's branch is tagged first, keeping its work findable after --delete-branch.`,
+		Example: `  cs finish mysession
+  cs finish mysession --squash -m "Add rate limiting" --delete-branch
+  cs finish mysession --tag --delete-branch
+  cs finish mysession --rebase --base develop`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeSessions(sessionManager),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			if cmd.Flags().Changed("tag-name") {
+				opts.Tag = true
+			}
+
+			switch {
+			case squash && rebase:
+				return fmt.Errorf("--squash and --rebase can't be used together")
+			case squash:
+				opts.Strategy = "squash"
+			case rebase:
+				opts.Strategy = "rebase"
+			}
+
+			sess, err := resolveSession(ctx, sessionManager, args[0])
+			if err != nil {
+				return err
+			}
+
+			result, err := sessionManager.FinishSession(ctx, sess.ID, opts)
+			if err != nil {
+				return fmt.Errorf("failed to finish session '%s': %w", sess.Title, err)
+			}
+
+			head := result.Head
+			if len(head) > 7 {
+				head = head[:7]
+			}
+			fmt.Printf("Merged %s into %s (%s), now at %s\n", result.Branch, result.Base, result.Strategy, head)
+			if result.Tag != "" {
+				fmt.Printf("Tagged the work as %s\n", result.Tag)
+			}
+			if result.BranchDeleted {
+				fmt.Printf("Deleted branch %s\n", result.Branch)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.Base, "base", "", "Branch to merge into (default: the branch checked out in the repository)")
+	cmd.Flags().BoolVar(&squash, "squash", false, "Apply the branch's changes as a single commit")
+	cmd.Flags().BoolVar(&rebase, "rebase", false, "Rebase the branch onto the base branch and fast-forward it")
+	cmd.Flags().StringVarP(&opts.Message, "message", "m", "", "Message of the merge or squash commit")
+	cmd.Flags().BoolVarP(&opts.DeleteBranch, "delete-branch", "d", false, "Delete the session's branch once it is merged")
+	cmd.Flags().BoolVar(&opts.Tag, "tag", false, "Tag the tip of the session's branch as squad/<title>/done before merging")
+	cmd.Flags().StringVar(&opts.TagName, "tag-name", "", "Name of the tag, implying --tag")
+
+	return cmd
+}
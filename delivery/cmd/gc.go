@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"claude-squad/interface/facade"
+
+	"github.com/spf13/cobra"
+)
+
+// orphanDescriptions explain each orphan kind in the text report
+var orphanDescriptions = map[facade.OrphanKind]string{
+	facade.OrphanTmuxSession:     "tmux session without a stored session",
+	facade.OrphanWorktree:        "worktree without a stored session",
+	facade.OrphanSessionWorktree: "session whose worktree is missing",
+	facade.OrphanSessionTmux:     "session whose tmux session is gone (resume or kill it)",
+}
+
+// NewGCCmd creates a command that reports, and optionally removes, orphaned resources
+func NewGCCmd(reconciler facade.ResourceReconciler) *cobra.Command {
+	var (
+		remove bool
+		output string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "gc",
+		Short: "Find and remove tmux sessions, worktrees and sessions that are out of sync",
+		Long: `Cross-reference stored sessions with claude-squad tmux sessions and git worktrees and
+report anything left behind: tmux sessions or worktrees no session owns, and sessions
+whose worktree has disappeared. Nothing is removed unless --delete is given.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := validateOutputFormat(output); err != nil {
+				return err
+			}
+
+			ctx := context.Background()
+			orphans, err := reconciler.FindOrphans(ctx)
+			if err != nil {
+				return err
+			}
+
+			if output != outputText {
+				if err := writeStructured(output, orphans); err != nil {
+					return err
+				}
+			} else if len(orphans) == 0 {
+				fmt.Println("No orphaned resources")
+				return nil
+			} else {
+				for _, o := range orphans {
+					fmt.Printf("%-48s %s\n", o.Resource, orphanDescriptions[o.Kind])
+				}
+			}
+
+			if !remove {
+				return nil
+			}
+
+			failed, removed := 0, 0
+			for _, o := range orphans {
+				if !o.Removable {
+					continue
+				}
+				if err := reconciler.RemoveOrphan(ctx, o); err != nil {
+					failed++
+					fmt.Printf("failed to remove %s: %v\n", o.Resource, err)
+					continue
+				}
+				removed++
+			}
+			if output == outputText {
+				fmt.Printf("Removed %d orphaned resource(s)\n", removed)
+			}
+			if failed > 0 {
+				return fmt.Errorf("%d orphaned resource(s) could not be removed", failed)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&remove, "delete", false, "Remove the orphaned resources that were found")
+	addOutputFlag(cmd, &output)
+
+	return cmd
+}
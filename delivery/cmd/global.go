@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"io"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// GlobalOptions are flags accepted by every command that select which config file and
+// session store to use, so independent profiles can live side by side, and which
+// repository's sessions to work with
+type GlobalOptions struct {
+	// ConfigPath overrides the config file location. Empty uses the default.
+	ConfigPath string
+	// StorageDir overrides the directory sessions are stored in. Empty uses the default.
+	StorageDir string
+	// AllRepos shows sessions from every repository, not just the one in the working
+	// directory
+	AllRepos bool
+	// DryRun reports the commands and storage changes a command would make instead of
+	// making them, so destructive ones like reset, prune and cleanup can be previewed
+	DryRun bool
+}
+
+func (o *GlobalOptions) addFlags(flags *pflag.FlagSet) {
+	flags.StringVar(&o.ConfigPath, "config", "", "Config file to use instead of the default")
+	flags.StringVar(&o.StorageDir, "storage-dir", "", "Directory to store sessions in instead of the default")
+	flags.BoolVar(&o.AllRepos, "all-repos", false, "Show and act on sessions from every repository, not just the current one")
+	flags.BoolVar(&o.DryRun, "dry-run", false, "Print the commands and changes that would be made without making them")
+}
+
+// AddFlags registers the global flags as persistent flags of root
+func (o *GlobalOptions) AddFlags(root *cobra.Command) {
+	o.addFlags(root.PersistentFlags())
+	_ = root.MarkPersistentFlagFilename("config", "json")
+	_ = root.MarkPersistentFlagDirname("storage-dir")
+}
+
+// ParseGlobalOptions picks the global flags out of args ahead of cobra, since the services
+// commands depend on have to be built before cobra parses the command line. Every other
+// flag is ignored, as are arguments after "--".
+func ParseGlobalOptions(args []string) (GlobalOptions, error) {
+	var opts GlobalOptions
+
+	flags := pflag.NewFlagSet("global", pflag.ContinueOnError)
+	flags.ParseErrorsWhitelist.UnknownFlags = true
+	flags.SetOutput(io.Discard)
+	opts.addFlags(flags)
+
+	// -h is left for cobra to handle
+	if err := flags.Parse(args); err != nil && err != pflag.ErrHelp {
+		return GlobalOptions{}, err
+	}
+	return opts, nil
+}
@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseGlobalOptions(t *testing.T) {
+	opts, err := ParseGlobalOptions([]string{"list", "-o", "json", "--config", "/tmp/work.json", "--storage-dir=/tmp/work"})
+	require.NoError(t, err)
+	assert.Equal(t, GlobalOptions{ConfigPath: "/tmp/work.json", StorageDir: "/tmp/work"}, opts)
+
+	opts, err = ParseGlobalOptions([]string{"--all-repos", "kill", "fix"})
+	require.NoError(t, err)
+	assert.True(t, opts.AllRepos)
+
+	opts, err = ParseGlobalOptions([]string{"reset", "--dry-run"})
+	require.NoError(t, err)
+	assert.Equal(t, GlobalOptions{DryRun: true}, opts)
+
+	// Flags after "--" belong to the program being run
+	opts, err = ParseGlobalOptions([]string{"exec", "fix", "--", "tool", "--config", "x"})
+	require.NoError(t, err)
+	assert.Equal(t, GlobalOptions{}, opts)
+
+	_, err = ParseGlobalOptions([]string{"list", "--config"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"claude-squad/interface/facade"
+
+	"github.com/spf13/cobra"
+)
+
+// NewHistoryCmd creates a command that lists the prompts and input sent to a session
+func NewHistoryCmd(sessionManager facade.SessionManager, sessionViewer facade.SessionViewer) *cobra.Command {
+	var (
+		output      string
+		promptsOnly bool
+	)
+
+	cmd := &cobra.Command{
+		Use:               "history [session-title-or-id]",
+		Short:             "List the prompts and input sent to a session",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeSessions(sessionManager),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := validateOutputFormat(output); err != nil {
+				return err
+			}
+
+			ctx := context.Background()
+			sess, err := resolveSession(ctx, sessionManager, args[0])
+			if err != nil {
+				return err
+			}
+
+			history, err := sessionViewer.GetInputHistory(ctx, sess.ID)
+			if err != nil {
+				return fmt.Errorf("failed to get history for session '%s': %w", sess.Title, err)
+			}
+
+			entries := make([]facade.InputRecord, 0, len(history))
+			for _, entry := range history {
+				if !promptsOnly || entry.Kind == "prompt" {
+					entries = append(entries, entry)
+				}
+			}
+
+			if output != outputText {
+				return writeStructured(output, entries)
+			}
+
+			if len(entries) == 0 {
+				fmt.Println("No input has been sent to this session")
+				return nil
+			}
+			for _, entry := range entries {
+				// Indent continuation lines so multi-line prompts stay readable
+				text := strings.ReplaceAll(entry.Text, "\n", "\n"+strings.Repeat(" ", 29))
+				fmt.Printf("%s  %-6s  %s\n", entry.Time.Local().Format("2006-01-02 15:04:05"), entry.Kind, text)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&promptsOnly, "prompts", false, "Only list submitted prompts, not raw keystrokes")
+	addOutputFlag(cmd, &output)
+
+	return cmd
+}
@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"context"
+
+	"claude-squad/interface/facade"
+
+	"github.com/spf13/cobra"
+)
+
+// NewKillCmd creates a command that stops sessions, cleans up their worktrees and tmux
+// sessions and moves them to the trash
+func NewKillCmd(sessionManager facade.SessionManager) *cobra.Command {
+	var (
+		all    bool
+		filter sessionFilter
+	)
+
+	cmd := &cobra.Command{
+		Use:   "kill [session-title-or-id]",
+		Short: "Kill a session, or all sessions matching a filter with --all",
+		Long: `Kill a session, removing its worktree and tmux session. The session moves to the
+trash with a snapshot of its uncommitted changes, and can be brought back with
+'cs trash restore' until the trash is purged.`,
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeSessions(sessionManager),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runBulk(context.Background(), sessionManager, args, all, &filter, bulkAction{
+				verb:      "kill",
+				pastTense: "killed and moved to the trash",
+				run:       sessionManager.StopSession,
+			})
+		},
+	}
+
+	cmd.Flags().BoolVar(&all, "all", false, "Kill all sessions matching the filters")
+	filter.addFlags(cmd)
+
+	return cmd
+}
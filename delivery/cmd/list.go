@@ -11,32 +11,97 @@ import (
 
 // NewListCmd creates a list command using the facade pattern
 func NewListCmd(sessionManager facade.SessionManager) *cobra.Command {
-	return &cobra.Command{
+	var (
+		output   string
+		archived bool
+		search   string
+		base     string
+	)
+
+	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List all active sessions",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := validateOutputFormat(output); err != nil {
+				return err
+			}
+
 			ctx := context.Background()
 
-			sessions, err := sessionManager.ListSessions(ctx)
+			var (
+				sessions []facade.SessionInfo
+				err      error
+			)
+			if search != "" {
+				sessions, err = sessionManager.SearchSessions(ctx, search)
+			} else {
+				sessions, err = sessionManager.ListSessions(ctx)
+			}
 			if err != nil {
 				return fmt.Errorf("failed to list sessions: %w", err)
 			}
 
+			// Archived sessions are only shown on request
+			shown := sessions[:0]
+			for _, sess := range sessions {
+				if sess.Archived == archived {
+					shown = append(shown, sess)
+				}
+			}
+			sessions = shown
+
+			// Count how far each branch has diverged, leaving out those that can't be
+			// compared, e.g. because their repository has gone or has no default branch
+			for i := range sessions {
+				if tracking, err := sessionManager.GetTracking(ctx, sessions[i].ID, base); err == nil {
+					sessions[i].Tracking = tracking
+				}
+			}
+
+			if output != outputText {
+				return writeStructured(output, sessions)
+			}
+
 			if len(sessions) == 0 {
-				fmt.Println("No active sessions")
+				if search != "" {
+					fmt.Printf("No sessions match '%s'\n", search)
+				} else if archived {
+					fmt.Println("No archived sessions")
+				} else {
+					fmt.Println("No active sessions")
+				}
 				return nil
 			}
 
-			fmt.Printf("Active sessions:\n")
+			if archived {
+				fmt.Printf("Archived sessions:\n")
+			} else {
+				fmt.Printf("Active sessions:\n")
+			}
 			for _, sess := range sessions {
 				status := getStatusString(sess.Status)
-				fmt.Printf("  [%s] %s - %s (%s)\n",
-					status, sess.Title, sess.Path, sess.Branch)
+				tracking := ""
+				if t := sess.Tracking; t != nil {
+					tracking = fmt.Sprintf(", %d ahead, %d behind %s", t.Ahead, t.Behind, t.Base)
+				}
+				conflicted := ""
+				if sess.Conflicted {
+					conflicted = " CONFLICTED"
+				}
+				fmt.Printf("  [%s] %s - %s (%s%s)%s\n",
+					status, sess.Title, sess.Path, sess.Branch, tracking, conflicted)
 			}
 
 			return nil
 		},
 	}
+
+	addOutputFlag(cmd, &output)
+	cmd.Flags().BoolVar(&archived, "archived", false, "List archived sessions instead of active ones")
+	cmd.Flags().StringVar(&base, "base", "", "Branch to count commits ahead and behind of (default: the default branch of each session's repository's origin)")
+	cmd.Flags().StringVarP(&search, "search", "s", "", "Only list sessions whose title, prompts or metadata contain every word of this query")
+
+	return cmd
 }
 
 func getStatusString(status facade.SessionStatus) string {
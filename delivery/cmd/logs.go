@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"claude-squad/interface/facade"
+
+	"github.com/spf13/cobra"
+)
+
+// logsPollInterval is how often --follow polls the session for new output
+const logsPollInterval = 500 * time.Millisecond
+
+// NewLogsCmd creates a command that prints, and optionally tails, a session's output
+func NewLogsCmd(sessionManager facade.SessionManager, sessionViewer facade.SessionViewer) *cobra.Command {
+	var (
+		lines  int
+		follow bool
+	)
+
+	cmd := &cobra.Command{
+		Use:               "logs [session-title-or-id]",
+		Short:             "Print the output of a session",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeSessions(sessionManager),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+			defer stop()
+
+			sess, err := resolveSession(ctx, sessionManager, args[0])
+			if err != nil {
+				return err
+			}
+
+			history, err := sessionViewer.GetFullHistory(ctx, sess.ID)
+			if err != nil {
+				return fmt.Errorf("failed to get output for session '%s': %w", sess.Title, err)
+			}
+			prev := splitOutputLines(history)
+
+			backlog := prev
+			if lines > 0 && len(backlog) > lines {
+				backlog = backlog[len(backlog)-lines:]
+			}
+			printLines(backlog)
+
+			if !follow {
+				return nil
+			}
+
+			ticker := time.NewTicker(logsPollInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return nil
+				case <-ticker.C:
+				}
+
+				history, err := sessionViewer.GetFullHistory(ctx, sess.ID)
+				if err != nil {
+					if ctx.Err() != nil {
+						return nil
+					}
+					return fmt.Errorf("failed to get output for session '%s': %w", sess.Title, err)
+				}
+				cur := splitOutputLines(history)
+				printLines(newOutputLines(prev, cur))
+				prev = cur
+			}
+		},
+	}
+
+	cmd.Flags().IntVarP(&lines, "lines", "n", 0, "Number of lines of backlog to print (0 for all)")
+	cmd.Flags().BoolVarP(&follow, "follow", "f", false, "Keep printing new output as it arrives")
+
+	return cmd
+}
+
+// splitOutputLines splits captured pane output into lines, dropping the blank padding
+// tmux adds below the cursor
+func splitOutputLines(output string) []string {
+	output = strings.TrimRight(output, "\n ")
+	if output == "" {
+		return nil
+	}
+	return strings.Split(output, "\n")
+}
+
+// newOutputLines returns the lines of cur that weren't already printed from prev. Output
+// usually only grows at the bottom, possibly with old lines dropping off the top of the
+// scrollback. If the program redrew lines in place, everything from the first changed
+// line onwards is treated as new.
+func newOutputLines(prev, cur []string) []string {
+	common := 0
+	for common < len(prev) && common < len(cur) && prev[common] == cur[common] {
+		common++
+	}
+	if common == len(prev) {
+		return cur[common:]
+	}
+
+	// Look for prev having scrolled off the top: some suffix of prev is a prefix of cur.
+	if overlap := scrolledOverlap(prev, cur); overlap > 0 {
+		return cur[overlap:]
+	}
+
+	return cur[common:]
+}
+
+// scrolledOverlap returns the length of the longest proper suffix of prev that is also a
+// prefix of cur. Trying each shift in turn is quadratic in the scrollback, which --follow
+// re-reads on every poll, so cur's prefix function is built once and prev run through it.
+func scrolledOverlap(prev, cur []string) int {
+	if len(prev) < 2 || len(cur) == 0 {
+		return 0
+	}
+
+	// fail[i] is the length of the longest proper prefix of cur[:i+1] that is also its suffix
+	fail := make([]int, len(cur))
+	for i, k := 1, 0; i < len(cur); i++ {
+		for k > 0 && cur[i] != cur[k] {
+			k = fail[k-1]
+		}
+		if cur[i] == cur[k] {
+			k++
+		}
+		fail[i] = k
+	}
+
+	k := 0
+	for _, line := range prev[1:] {
+		for k > 0 && (k == len(cur) || line != cur[k]) {
+			k = fail[k-1]
+		}
+		if line == cur[k] {
+			k++
+		}
+	}
+	return k
+}
+
+func printLines(lines []string) {
+	for _, line := range lines {
+		fmt.Println(line)
+	}
+}
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewOutputLines(t *testing.T) {
+	tests := []struct {
+		name      string
+		prev, cur []string
+		want      []string
+	}{
+		{
+			name: "appended",
+			prev: []string{"a", "b"},
+			cur:  []string{"a", "b", "c", "d"},
+			want: []string{"c", "d"},
+		},
+		{
+			name: "unchanged",
+			prev: []string{"a", "b"},
+			cur:  []string{"a", "b"},
+			want: []string{},
+		},
+		{
+			name: "scrolled off the top",
+			prev: []string{"a", "b", "c"},
+			cur:  []string{"b", "c", "d"},
+			want: []string{"d"},
+		},
+		{
+			name: "scrolled off the top with repeated lines",
+			prev: []string{"a", "", "", "b", "", ""},
+			cur:  []string{"", "", "b", "", "", "c"},
+			want: []string{"c"},
+		},
+		{
+			name: "scrolled past everything printed",
+			prev: []string{"a", "b"},
+			cur:  []string{"c", "d"},
+			want: []string{"c", "d"},
+		},
+		{
+			name: "redrawn in place",
+			prev: []string{"a", "b", "> typing"},
+			cur:  []string{"a", "b", "> typed", "done"},
+			want: []string{"> typed", "done"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, newOutputLines(tt.prev, tt.cur))
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"claude-squad/interface/facade"
+
+	"gopkg.in/yaml.v3"
+)
+
+// sessionManifest is a file listing sessions to create in one go. It is YAML, so a JSON
+// file with the same shape works too.
+type sessionManifest struct {
+	Sessions []manifestEntry `yaml:"sessions"`
+}
+
+// manifestEntry is one session in a manifest. Program and path fall back to the
+// values given on the command line.
+type manifestEntry struct {
+	Title   string `yaml:"title"`
+	Prompt  string `yaml:"prompt"`
+	Branch  string `yaml:"branch"`
+	Program string `yaml:"program"`
+	Path    string `yaml:"path"`
+	AutoYes bool   `yaml:"auto_yes"`
+	// Submodules overrides --submodules for this session
+	Submodules *bool `yaml:"submodules"`
+}
+
+// loadManifest reads a manifest and turns its entries into create options. Relative
+// paths are resolved against the manifest's directory.
+func loadManifest(path string, defaults facade.CreateSessionOptions) ([]facade.CreateSessionOptions, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+	return parseManifest(data, filepath.Dir(path), defaults)
+}
+
+func parseManifest(data []byte, baseDir string, defaults facade.CreateSessionOptions) ([]facade.CreateSessionOptions, error) {
+	var manifest sessionManifest
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+	if len(manifest.Sessions) == 0 {
+		return nil, fmt.Errorf("manifest has no sessions")
+	}
+
+	titles := make(map[string]bool, len(manifest.Sessions))
+	all := make([]facade.CreateSessionOptions, 0, len(manifest.Sessions))
+	for i, entry := range manifest.Sessions {
+		if entry.Title == "" {
+			return nil, fmt.Errorf("session %d in manifest has no title", i+1)
+		}
+		if titles[entry.Title] {
+			return nil, fmt.Errorf("duplicate session title '%s' in manifest", entry.Title)
+		}
+		titles[entry.Title] = true
+
+		opts := facade.CreateSessionOptions{
+			Title:   entry.Title,
+			Prompt:  entry.Prompt,
+			Branch:  entry.Branch,
+			Program: entry.Program,
+			Path:    entry.Path,
+			AutoYes: entry.AutoYes,
+
+			Submodules: entry.Submodules,
+			ForkBranch: defaults.ForkBranch,
+		}
+		if opts.Submodules == nil {
+			opts.Submodules = defaults.Submodules
+		}
+		if opts.Program == "" {
+			opts.Program = defaults.Program
+		}
+		if opts.Path == "" {
+			opts.Path = defaults.Path
+		} else if !filepath.IsAbs(opts.Path) {
+			opts.Path = filepath.Join(baseDir, opts.Path)
+		}
+		all = append(all, opts)
+	}
+	return all, nil
+}
+
+// createSessions creates every session in all, running at most concurrency creations at a
+// time, and reports each result as it finishes
+func createSessions(ctx context.Context, sessionManager facade.SessionManager, all []facade.CreateSessionOptions, concurrency int) error {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		failed int
+	)
+	sem := make(chan struct{}, concurrency)
+	for _, opts := range all {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(opts facade.CreateSessionOptions) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			sess, err := sessionManager.CreateSession(ctx, opts)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				failed++
+				fmt.Printf("failed to create '%s': %v\n", opts.Title, err)
+				return
+			}
+			fmt.Printf("Created session '%s' (%s) on %s\n", sess.Title, sess.ID, sess.Branch)
+		}(opts)
+	}
+	wg.Wait()
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d sessions could not be created", failed, len(all))
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"testing"
+
+	"claude-squad/interface/facade"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseManifest(t *testing.T) {
+	defaults := facade.CreateSessionOptions{Program: "claude", Path: "/repo"}
+
+	all, err := parseManifest([]byte(`
+sessions:
+  - title: fix-login
+    prompt: Fix the login redirect loop
+    branch: fix/login
+  - title: docs
+    program: aider
+    path: ../other
+    auto_yes: true
+    submodules: false
+`), "/work/tasks", defaults)
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+
+	assert.Equal(t, facade.CreateSessionOptions{
+		Title:   "fix-login",
+		Prompt:  "Fix the login redirect loop",
+		Branch:  "fix/login",
+		Program: "claude",
+		Path:    "/repo",
+	}, all[0])
+	assert.Equal(t, "aider", all[1].Program)
+	assert.Equal(t, "/work/other", all[1].Path)
+	assert.True(t, all[1].AutoYes)
+	require.NotNil(t, all[1].Submodules)
+	assert.False(t, *all[1].Submodules)
+
+	// Sessions that don't say take --submodules
+	enabled := true
+	all, err = parseManifest([]byte(`sessions: [{title: a}]`), "/", facade.CreateSessionOptions{Submodules: &enabled})
+	require.NoError(t, err)
+	assert.Equal(t, &enabled, all[0].Submodules)
+
+	_, err = parseManifest([]byte(`{"sessions": [{"title": "a"}, {"title": "a"}]}`), "/", defaults)
+	assert.ErrorContains(t, err, "duplicate")
+
+	_, err = parseManifest([]byte(`sessions: [{prompt: untitled}]`), "/", defaults)
+	assert.ErrorContains(t, err, "no title")
+
+	_, err = parseManifest([]byte(`sessions: []`), "/", defaults)
+	assert.Error(t, err)
+}
@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"claude-squad/interface/facade"
+
+	"github.com/spf13/cobra"
+)
+
+// NewNewCmd creates a command that creates a session from flags without launching the TUI
+func NewNewCmd(sessionManager facade.SessionManager, defaultProgram string) *cobra.Command {
+	var (
+		opts        facade.CreateSessionOptions
+		fromFile    string
+		concurrency int
+		submodules  bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "new",
+		Short: "Create a new session without launching the TUI",
+		Long: `Create a new session without launching the TUI, or with --from-file, one session
+per entry of a YAML manifest:
+
+  sessions:
+    - title: fix-login
+      prompt: Fix the login redirect loop
+      branch: fix/login
+      program: claude
+
+With --in-place, the session runs in --path itself rather than a worktree of its own, for
+checkouts too big to copy or tied to where they are, like bazel's. --branch is checked
+out there, and --stash sets the directory's uncommitted changes aside; both are undone
+when the session is deleted, if it left no uncommitted changes behind.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+
+			if (opts.Title == "") == (fromFile == "") {
+				return fmt.Errorf("specify either --title or --from-file")
+			}
+
+			path, err := filepath.Abs(opts.Path)
+			if err != nil {
+				return fmt.Errorf("failed to resolve path: %w", err)
+			}
+			opts.Path = path
+			if cmd.Flags().Changed("submodules") {
+				opts.Submodules = &submodules
+			}
+			if opts.Stash && !opts.InPlace {
+				return fmt.Errorf("--stash requires --in-place")
+			}
+			if opts.InPlace && fromFile != "" {
+				return fmt.Errorf("--in-place can't be used with --from-file")
+			}
+
+			if fromFile != "" {
+				all, err := loadManifest(fromFile, opts)
+				if err != nil {
+					return err
+				}
+				return createSessions(ctx, sessionManager, all, concurrency)
+			}
+
+			sess, err := sessionManager.CreateSession(ctx, opts)
+			var inUse *facade.BranchInUseError
+			if errors.As(err, &inUse) {
+				return fmt.Errorf("failed to create session: %w; use --fork-branch to start a new branch from it", err)
+			}
+			if err != nil {
+				return fmt.Errorf("failed to create session: %w", err)
+			}
+
+			fmt.Printf("Created session '%s' (%s)\n", sess.Title, sess.ID)
+			fmt.Printf("  path:    %s\n", sess.Path)
+			fmt.Printf("  branch:  %s\n", sess.Branch)
+			fmt.Printf("  program: %s\n", sess.Program)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.Title, "title", "", "Title of the session")
+	cmd.Flags().StringVar(&opts.Branch, "branch", "", "Branch to create for the session (defaults to the current branch)")
+	cmd.Flags().StringVar(&opts.Program, "program", defaultProgram, "Program to run in the session")
+	cmd.Flags().StringVar(&opts.Prompt, "prompt", "", "Initial prompt to send to the program")
+	cmd.Flags().StringVar(&opts.Path, "path", ".", "Path to the git repository")
+	cmd.Flags().StringVarP(&fromFile, "from-file", "f", "", "Create every session listed in a YAML manifest")
+	cmd.Flags().IntVar(&concurrency, "concurrency", 4, "Maximum number of sessions created at once with --from-file")
+	cmd.Flags().BoolVar(&opts.InPlace, "in-place", false, "Run the session in --path itself instead of a new worktree")
+	cmd.Flags().BoolVar(&opts.Stash, "stash", false, "With --in-place, stash the directory's uncommitted changes until the session is deleted")
+	cmd.Flags().BoolVar(&opts.ForkBranch, "fork-branch", false, "Start a new branch from the session's branch if another session already works on it")
+	cmd.Flags().BoolVar(&submodules, "submodules", false, "Initialize the worktree's submodules (defaults to the worktree_submodules setting)")
+
+	return cmd
+}
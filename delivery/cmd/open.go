@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"claude-squad/interface/facade"
+
+	"github.com/spf13/cobra"
+)
+
+// knownEditors are tried in order when no editor is configured
+var knownEditors = []string{"code", "cursor", "idea", "subl", "zed"}
+
+// NewOpenCmd creates a command that opens a session's worktree in an editor
+func NewOpenCmd(sessionManager facade.SessionManager, sessionInteractor facade.SessionInteractor, configuredEditor string) *cobra.Command {
+	var editor string
+
+	cmd := &cobra.Command{
+		Use:   "open [session-title-or-id]",
+		Short: "Open a session's worktree in an editor",
+		Long: `Open a session's worktree in an editor. The editor is taken from --editor, the
+editor config key, $VISUAL or $EDITOR, in that order, falling back to the first of
+code, cursor, idea, subl or zed found on PATH.`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeSessions(sessionManager),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+
+			sess, err := resolveSession(ctx, sessionManager, args[0])
+			if err != nil {
+				return err
+			}
+
+			editorCmd, err := resolveEditor(editor, configuredEditor)
+			if err != nil {
+				return err
+			}
+
+			code, err := sessionInteractor.Exec(ctx, sess.ID, facade.ExecOptions{
+				Program: editorCmd[0],
+				Args:    append(editorCmd[1:], sess.Path),
+				Stdin:   os.Stdin,
+				Stdout:  os.Stdout,
+				Stderr:  os.Stderr,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to open session '%s': %w", sess.Title, err)
+			}
+			if code != 0 {
+				return fmt.Errorf("%s exited with status %d", editorCmd[0], code)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&editor, "editor", "e", "", "Editor command to use, overriding the config")
+
+	return cmd
+}
+
+// resolveEditor picks the editor command, split into program and arguments
+func resolveEditor(flagEditor, configuredEditor string) ([]string, error) {
+	for _, candidate := range []string{flagEditor, configuredEditor, os.Getenv("VISUAL"), os.Getenv("EDITOR")} {
+		if fields := strings.Fields(candidate); len(fields) > 0 {
+			return fields, nil
+		}
+	}
+	for _, name := range knownEditors {
+		if _, err := exec.LookPath(name); err == nil {
+			return []string{name}, nil
+		}
+	}
+	return nil, fmt.Errorf("no editor found; set one with 'cs config set editor <command>' or $EDITOR")
+}
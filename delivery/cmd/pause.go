@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"context"
+
+	"claude-squad/interface/facade"
+
+	"github.com/spf13/cobra"
+)
+
+// NewPauseCmd creates a command that pauses sessions, removing their worktrees but keeping branches
+func NewPauseCmd(sessionManager facade.SessionManager) *cobra.Command {
+	var (
+		all    bool
+		filter sessionFilter
+	)
+
+	cmd := &cobra.Command{
+		Use:               "pause [session-title-or-id]",
+		Short:             "Pause a session, or all sessions matching a filter with --all",
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeSessions(sessionManager),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runBulk(context.Background(), sessionManager, args, all, &filter, bulkAction{
+				verb:      "pause",
+				pastTense: "paused",
+				skip: func(sess facade.SessionInfo) bool {
+					return sess.Status == facade.StatusPaused
+				},
+				run: sessionManager.PauseSession,
+			})
+		},
+	}
+
+	cmd.Flags().BoolVar(&all, "all", false, "Pause all sessions matching the filters")
+	filter.addFlags(cmd)
+
+	return cmd
+}
+
+// NewResumeCmd creates a command that resumes paused sessions
+func NewResumeCmd(sessionManager facade.SessionManager) *cobra.Command {
+	var (
+		all    bool
+		filter sessionFilter
+	)
+
+	cmd := &cobra.Command{
+		Use:               "resume [session-title-or-id]",
+		Short:             "Resume a paused session, or all paused sessions matching a filter with --all",
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeSessions(sessionManager),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runBulk(context.Background(), sessionManager, args, all, &filter, bulkAction{
+				verb:      "resume",
+				pastTense: "resumed",
+				skip: func(sess facade.SessionInfo) bool {
+					return sess.Status != facade.StatusPaused || sess.Archived
+				},
+				run: sessionManager.ResumeSession,
+			})
+		},
+	}
+
+	cmd.Flags().BoolVar(&all, "all", false, "Resume all paused sessions matching the filters")
+	filter.addFlags(cmd)
+
+	return cmd
+}
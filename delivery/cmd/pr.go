@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"claude-squad/interface/facade"
+
+	"github.com/spf13/cobra"
+)
+
+// NewPRCmd creates a command that opens a pull request from a session's branch
+func NewPRCmd(sessionManager facade.SessionManager) *cobra.Command {
+	var opts facade.PullRequestOptions
+
+	cmd := &cobra.Command{
+		Use:   "pr [session-title-or-id]",
+		Short: "Open a pull request from a session's branch",
+		Long: `Push a session's branch and open a pull request for it on the forge hosting the
+remote: GitHub with its CLI (gh), GitLab with its CLI (glab), or Gitea through its API with
+an access token in $GITEA_TOKEN. The forge is told apart by the remote's host name, or by
+forge_hosts in the config for self-hosted ones. The title and body default to the session
+prompt and the branch's commits. 'cs pr status' shows the pull request's state, reviews
+and checks.`,
+		Example: `  cs pr mysession
+  cs pr mysession --base develop --draft
+  cs pr status mysession`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeSessions(sessionManager),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+
+			sess, err := resolveSession(ctx, sessionManager, args[0])
+			if err != nil {
+				return err
+			}
+
+			pr, err := sessionManager.CreatePullRequest(ctx, sess.ID, opts)
+			if err != nil {
+				return fmt.Errorf("failed to open pull request for session '%s': %w", sess.Title, err)
+			}
+
+			fmt.Printf("Opened pull request %s -> %s: %s\n", pr.Branch, pr.Base, pr.Title)
+			fmt.Println(pr.URL)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.Remote, "remote", "origin", "Remote to push the branch to")
+	cmd.Flags().StringVar(&opts.Base, "base", "", "Branch to merge into (default: the remote's default branch)")
+	cmd.Flags().StringVarP(&opts.Title, "title", "t", "", "Pull request title")
+	cmd.Flags().StringVarP(&opts.Body, "body", "b", "", "Pull request body")
+	cmd.Flags().BoolVarP(&opts.Draft, "draft", "d", false, "Open the pull request as a draft")
+	cmd.AddCommand(newPRStatusCmd(sessionManager))
+
+	return cmd
+}
+
+func newPRStatusCmd(sessionManager facade.SessionManager) *cobra.Command {
+	var output string
+
+	cmd := &cobra.Command{
+		Use:               "status <session-title-or-id>",
+		Short:             "Show the pull request from a session's branch and its checks",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeSessions(sessionManager),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := validateOutputFormat(output); err != nil {
+				return err
+			}
+
+			ctx := context.Background()
+			sess, err := resolveSession(ctx, sessionManager, args[0])
+			if err != nil {
+				return err
+			}
+
+			status, err := sessionManager.GetPullRequestStatus(ctx, sess.ID)
+			if err != nil {
+				return fmt.Errorf("failed to get pull request for session '%s': %w", sess.Title, err)
+			}
+			if status == nil {
+				return fmt.Errorf("branch %s has no pull request, open one with 'cs pr %s'", sess.Branch, sess.Title)
+			}
+
+			if output != outputText {
+				return writeStructured(output, status)
+			}
+			printPRStatus(status)
+			return nil
+		},
+	}
+
+	addOutputFlag(cmd, &output)
+
+	return cmd
+}
+
+func printPRStatus(status *facade.PullRequestStatus) {
+	state := status.State
+	if status.Draft && state == "open" {
+		state = "draft"
+	}
+	fmt.Printf("#%d %s (%s)\n", status.Number, status.Title, state)
+	fmt.Printf("%s -> %s  %s\n", status.Branch, status.Base, status.URL)
+	if status.ReviewDecision != "" {
+		fmt.Printf("Review: %s\n", strings.ToLower(strings.ReplaceAll(status.ReviewDecision, "_", " ")))
+	}
+	if status.Mergeable == "CONFLICTING" {
+		fmt.Println("Conflicts with the base branch")
+	}
+
+	if len(status.Checks) == 0 {
+		fmt.Println("No checks")
+		return
+	}
+	counts := make(map[string]int)
+	for _, check := range status.Checks {
+		counts[check.State]++
+	}
+	fmt.Printf("Checks: %d passed, %d failed, %d pending, %d skipped\n",
+		counts["pass"], counts["fail"], counts["pending"], counts["skipped"])
+	for _, check := range status.Checks {
+		name := check.Name
+		if check.Workflow != "" {
+			name = check.Workflow + " / " + name
+		}
+		fmt.Printf("  %-8s %s", check.State, name)
+		if check.State == "fail" && check.URL != "" {
+			fmt.Printf("  %s", check.URL)
+		}
+		fmt.Println()
+	}
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"claude-squad/interface/facade"
+
+	"github.com/spf13/cobra"
+)
+
+// NewPruneCmd creates a command that deletes stale sessions by retention policy
+func NewPruneCmd(sessionManager facade.SessionManager) *cobra.Command {
+	var (
+		opts   facade.PruneOptions
+		status string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "prune",
+		Short: "Delete stale sessions along with their worktrees and tmux sessions",
+		Example: `  cs prune --older-than 168h --dry-run
+  cs prune --status paused --older-than 720h`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.OlderThan <= 0 && status == "" {
+				return fmt.Errorf("refusing to prune every session; pass --older-than and/or --status")
+			}
+			if status != "" {
+				s, err := facade.ParseSessionStatus(status)
+				if err != nil {
+					return err
+				}
+				opts.Status = &s
+			}
+
+			sessions, err := sessionManager.PruneSessions(context.Background(), opts)
+			if err != nil {
+				return fmt.Errorf("failed to prune sessions: %w", err)
+			}
+			if len(sessions) == 0 {
+				fmt.Println("No sessions to prune")
+				return nil
+			}
+
+			verb := "Pruned"
+			if opts.DryRun {
+				verb = "Would prune"
+			}
+			for _, sess := range sessions {
+				fmt.Printf("%s '%s' (%s, last updated %s ago)\n", verb, sess.Title, sess.Status,
+					time.Since(sess.UpdatedAt).Round(time.Minute))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().DurationVar(&opts.OlderThan, "older-than", 0, "Only prune sessions not updated for longer than this (e.g. 168h)")
+	cmd.Flags().StringVar(&status, "status", "", "Only prune sessions with this status (running, ready, loading, paused)")
+	cmd.Flags().BoolVar(&opts.DryRun, "dry-run", false, "List the sessions that would be pruned without deleting them")
+	_ = cmd.RegisterFlagCompletionFunc("status", completeStatuses)
+
+	return cmd
+}
@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"claude-squad/interface/facade"
+
+	"github.com/spf13/cobra"
+)
+
+// NewPushCmd creates a command that pushes a session's branch and prints where it went
+func NewPushCmd(sessionManager facade.SessionManager) *cobra.Command {
+	var opts facade.PushOptions
+
+	cmd := &cobra.Command{
+		Use:   "push [session-title-or-id]",
+		Short: "Push a session's branch to a remote",
+		Long: `Push a session's branch to a remote and set it as the branch's upstream. Only
+committed work is pushed; use 'cs commit' first to include pending changes.`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeSessions(sessionManager),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+
+			sess, err := resolveSession(ctx, sessionManager, args[0])
+			if err != nil {
+				return err
+			}
+
+			result, err := sessionManager.PushSession(ctx, sess.ID, opts)
+			if err != nil {
+				return fmt.Errorf("failed to push session '%s': %w", sess.Title, err)
+			}
+
+			fmt.Printf("Pushed %s to %s", result.Branch, result.Remote)
+			if result.RemoteURL != "" {
+				fmt.Printf(" (%s)", result.RemoteURL)
+			}
+			fmt.Println()
+			if result.BranchURL != "" {
+				fmt.Println(result.BranchURL)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.Remote, "remote", "origin", "Remote to push to")
+	cmd.Flags().BoolVarP(&opts.Force, "force", "f", false, "Overwrite the remote branch if it has diverged (uses --force-with-lease)")
+
+	return cmd
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"claude-squad/interface/facade"
+
+	"github.com/spf13/cobra"
+)
+
+// NewRebaseCmd creates a command that rebases a session's branch onto the latest upstream
+func NewRebaseCmd(sessionManager facade.SessionManager) *cobra.Command {
+	var (
+		opts   facade.RebaseOptions
+		output string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "rebase [session-title-or-id]",
+		Short: "Rebase a session's branch onto the latest upstream",
+		Long: `Rebase a session's branch in its worktree, by default onto the default branch of
+the remote after fetching it. A rebase that conflicts stops with the conflicting files
+listed; resolve them in the worktree and run with --continue, or give up with --abort.`,
+		Example: `  cs rebase mysession
+  cs rebase mysession --onto develop
+  cs rebase mysession --continue -o json`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeSessions(sessionManager),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := validateOutputFormat(output); err != nil {
+				return err
+			}
+			if opts.Continue && opts.Abort {
+				return fmt.Errorf("--continue and --abort can't be used together")
+			}
+
+			ctx := context.Background()
+			sess, err := resolveSession(ctx, sessionManager, args[0])
+			if err != nil {
+				return err
+			}
+
+			result, err := sessionManager.RebaseSession(ctx, sess.ID, opts)
+			if err != nil {
+				return fmt.Errorf("failed to rebase session '%s': %w", sess.Title, err)
+			}
+
+			if output != outputText {
+				if err := writeStructured(output, result); err != nil {
+					return err
+				}
+			} else {
+				printRebaseResult(result)
+			}
+			if result.Status == "conflicted" {
+				return fmt.Errorf("rebase of '%s' stopped on %d conflicting files", sess.Title, len(result.Conflicts))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.Onto, "onto", "", "Ref to rebase onto (default: the remote's default branch)")
+	cmd.Flags().StringVar(&opts.Remote, "remote", "", "Remote to fetch the default branch of (default: origin)")
+	cmd.Flags().BoolVar(&opts.Continue, "continue", false, "Continue a rebase once its conflicts are resolved")
+	cmd.Flags().BoolVar(&opts.Abort, "abort", false, "Give up on a rebase, restoring the branch")
+	addOutputFlag(cmd, &output)
+
+	return cmd
+}
+
+func printRebaseResult(result *facade.RebaseResult) {
+	head := result.Head
+	if len(head) > 7 {
+		head = head[:7]
+	}
+	switch result.Status {
+	case "aborted":
+		fmt.Printf("Aborted rebase of %s, back at %s\n", result.Branch, head)
+	case "conflicted":
+		fmt.Printf("Rebase of %s stopped on conflicts in:\n", result.Branch)
+		for _, file := range result.Conflicts {
+			fmt.Printf("  %s\n", file)
+		}
+		fmt.Println("Resolve them in the worktree, then run with --continue, or give up with --abort")
+	default:
+		if result.Onto != "" {
+			fmt.Printf("Rebased %s onto %s, now at %s\n", result.Branch, result.Onto, head)
+		} else {
+			fmt.Printf("Rebased %s, now at %s\n", result.Branch, head)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"claude-squad/interface/facade"
+
+	"github.com/spf13/cobra"
+)
+
+// NewRenameCmd creates a command that renames a session along with its tmux session and branch
+func NewRenameCmd(sessionManager facade.SessionManager) *cobra.Command {
+	var renameBranch bool
+
+	cmd := &cobra.Command{
+		Use:               "rename [session-title-or-id] [new-title]",
+		Short:             "Rename a session and its tmux session, and optionally its branch",
+		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: completeSessions(sessionManager),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+
+			sess, err := resolveSession(ctx, sessionManager, args[0])
+			if err != nil {
+				return err
+			}
+
+			renamed, err := sessionManager.RenameSession(ctx, sess.ID, args[1], renameBranch)
+			if err != nil {
+				return fmt.Errorf("failed to rename session '%s': %w", sess.Title, err)
+			}
+
+			fmt.Printf("Renamed session '%s' to '%s' (%s)\n", sess.Title, renamed.Title, renamed.ID)
+			if renamed.Branch != sess.Branch {
+				fmt.Printf("  branch: %s -> %s\n", sess.Branch, renamed.Branch)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&renameBranch, "branch", false, "Also rename the session's git branch to match the new title")
+
+	return cmd
+}
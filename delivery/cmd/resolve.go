@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"claude-squad/interface/facade"
+)
+
+// resolveSession finds a session by ID or, failing that, by title
+func resolveSession(ctx context.Context, sessionManager facade.SessionManager, ref string) (*facade.SessionInfo, error) {
+	sessions, err := sessionManager.ListSessions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	for i := range sessions {
+		if sessions[i].ID == ref {
+			return &sessions[i], nil
+		}
+	}
+	for i := range sessions {
+		if sessions[i].Title == ref {
+			return &sessions[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("session '%s' not found", ref)
+}
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"claude-squad/interface/facade"
+
+	"github.com/spf13/cobra"
+)
+
+// NewSendCmd creates a command that sends a prompt to a running session
+func NewSendCmd(sessionManager facade.SessionManager, sessionInteractor facade.SessionInteractor) *cobra.Command {
+	var fromStdin bool
+
+	cmd := &cobra.Command{
+		Use:   "send [session-title-or-id] [text...]",
+		Short: "Send a prompt to a running session",
+		Args: func(cmd *cobra.Command, args []string) error {
+			if fromStdin {
+				return cobra.ExactArgs(1)(cmd, args)
+			}
+			return cobra.MinimumNArgs(2)(cmd, args)
+		},
+		ValidArgsFunction: completeSessions(sessionManager),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+
+			var prompt string
+			if fromStdin {
+				data, err := io.ReadAll(os.Stdin)
+				if err != nil {
+					return fmt.Errorf("failed to read prompt from stdin: %w", err)
+				}
+				prompt = strings.TrimRight(string(data), "\n")
+			} else {
+				prompt = strings.Join(args[1:], " ")
+			}
+			if strings.TrimSpace(prompt) == "" {
+				return fmt.Errorf("prompt is empty")
+			}
+
+			sess, err := resolveSession(ctx, sessionManager, args[0])
+			if err != nil {
+				return err
+			}
+
+			if err := sessionInteractor.SendPrompt(ctx, sess.ID, prompt); err != nil {
+				return fmt.Errorf("failed to send prompt to session '%s': %w", sess.Title, err)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&fromStdin, "stdin", false, "Read the prompt from stdin")
+
+	return cmd
+}
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"claude-squad/interface/facade"
+
+	"github.com/spf13/cobra"
+)
+
+// NewStatusCmd creates a command that prints a snapshot of all sessions and the daemon
+func NewStatusCmd(dashboard facade.Dashboard) *cobra.Command {
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show a summary of all sessions and the daemon",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := validateOutputFormat(output); err != nil {
+				return err
+			}
+
+			summary, err := dashboard.Summary(context.Background())
+			if err != nil {
+				return fmt.Errorf("failed to collect status: %w", err)
+			}
+
+			if output != outputText {
+				return writeStructured(output, summary)
+			}
+
+			printStatusSummary(summary)
+			return nil
+		},
+	}
+
+	addOutputFlag(cmd, &output)
+
+	return cmd
+}
+
+func printStatusSummary(summary *facade.StatusSummary) {
+	var counts []string
+	for _, status := range []facade.SessionStatus{facade.StatusRunning, facade.StatusReady, facade.StatusLoading, facade.StatusPaused} {
+		if n := summary.ByStatus[status]; n > 0 {
+			counts = append(counts, fmt.Sprintf("%d %s", n, status))
+		}
+	}
+	fmt.Printf("Sessions: %d", summary.Total)
+	if len(counts) > 0 {
+		fmt.Printf(" (%s)", strings.Join(counts, ", "))
+	}
+	fmt.Println()
+	fmt.Printf("Waiting on prompt: %d\n", summary.Waiting)
+
+	if summary.Daemon.Running {
+		fmt.Printf("Daemon: running (pid %d)\n", summary.Daemon.PID)
+	} else {
+		fmt.Println("Daemon: not running")
+	}
+
+	if len(summary.Sessions) == 0 {
+		return
+	}
+
+	fmt.Println()
+	for _, s := range summary.Sessions {
+		diff := ""
+		if s.Diff != nil {
+			diff = fmt.Sprintf("+%d -%d", s.Diff.Added, s.Diff.Removed)
+		}
+		notes := ""
+		if s.WaitingOnPrompt {
+			notes += "  waiting on prompt"
+		}
+		if s.Conflicted {
+			notes += "  conflicts with base"
+		}
+		fmt.Printf("  [%s] %-24s %-32s %10s%s\n", getStatusString(s.Status), s.Title, s.Branch, diff, notes)
+	}
+}
@@ -0,0 +1,355 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"claude-squad/interface/facade"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+)
+
+var (
+	topHeaderStyle  = lipgloss.NewStyle().Bold(true)
+	topWaitingStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#f59e0b"))
+	topAddedStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("#22c55e"))
+	topRemovedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#ef4444"))
+	topDimStyle     = lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "#9C9C9C", Dark: "#808080"})
+)
+
+// NewTopCmd creates a command that shows a live, periodically refreshed session table
+func NewTopCmd(dashboard facade.Dashboard, sessionManager facade.SessionManager, sessionViewer facade.SessionViewer) *cobra.Command {
+	var interval time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "top",
+		Short: "Show a live view of all sessions",
+		Long: `Show a live view of every session's status, diff size, last activity and whether it
+is waiting for input. Press / to filter sessions by their title, prompts or metadata,
+d to show the git, tmux and other commands run for the view, r to refresh immediately
+and q to quit.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if interval <= 0 {
+				return fmt.Errorf("--interval must be positive")
+			}
+
+			m := &topModel{
+				dashboard:    dashboard,
+				sessions:     sessionManager,
+				viewer:       sessionViewer,
+				interval:     interval,
+				lastOutput:   make(map[string]string),
+				lastActivity: make(map[string]time.Time),
+			}
+			_, err := tea.NewProgram(m).Run()
+			return err
+		},
+	}
+
+	cmd.Flags().DurationVarP(&interval, "interval", "n", 2*time.Second, "How often to refresh")
+
+	return cmd
+}
+
+// topRefreshMsg carries a fresh summary, or the error that prevented collecting one
+type topRefreshMsg struct {
+	summary  *facade.StatusSummary
+	err      error
+	at       time.Time
+	output   map[string]string
+	activity map[string]time.Time
+	// matches holds the IDs of the sessions matching filter, or nil with no filter
+	filter   string
+	matches  map[string]bool
+	commands []facade.CommandStats
+}
+
+// topTickMsg triggers the next refresh
+type topTickMsg struct{}
+
+// topModel is the bubbletea model behind `cs top`
+type topModel struct {
+	dashboard facade.Dashboard
+	sessions  facade.SessionManager
+	viewer    facade.SessionViewer
+	interval  time.Duration
+
+	// filter is the applied search query; editing is set while it is being typed
+	filter  string
+	editing bool
+	input   string
+	matches map[string]bool
+
+	summary    *facade.StatusSummary
+	err        error
+	updatedAt  time.Time
+	refreshing bool
+
+	// showCommands shows the commands run so far below the sessions, for debugging
+	showCommands bool
+	commands     []facade.CommandStats
+
+	// lastOutput and lastActivity track when each session's pane last changed. refresh
+	// builds new maps and Update swaps them in, so View never races with a refresh.
+	lastOutput   map[string]string
+	lastActivity map[string]time.Time
+}
+
+func (m *topModel) Init() tea.Cmd {
+	m.refreshing = true
+	return tea.Batch(m.refresh(m.filter), m.tick())
+}
+
+func (m *topModel) tick() tea.Cmd {
+	return tea.Tick(m.interval, func(time.Time) tea.Msg { return topTickMsg{} })
+}
+
+// startRefresh returns a refresh command unless one is already running
+func (m *topModel) startRefresh() tea.Cmd {
+	if m.refreshing {
+		return nil
+	}
+	m.refreshing = true
+	return m.refresh(m.filter)
+}
+
+func (m *topModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if m.editing {
+			return m, m.updateFilter(msg)
+		}
+		switch msg.String() {
+		case "esc":
+			if m.filter != "" {
+				m.filter, m.matches = "", nil
+				return m, nil
+			}
+			return m, tea.Quit
+		case "q", "ctrl+c":
+			return m, tea.Quit
+		case "r":
+			return m, m.startRefresh()
+		case "d":
+			m.showCommands = !m.showCommands
+		case "/":
+			m.editing, m.input = true, m.filter
+		}
+	case topRefreshMsg:
+		m.refreshing = false
+		m.err, m.updatedAt = msg.err, msg.at
+		m.commands = msg.commands
+		// Keep showing the last good summary when a refresh fails
+		if msg.summary != nil {
+			m.summary, m.lastOutput, m.lastActivity = msg.summary, msg.output, msg.activity
+			// A refresh started before the filter changed has stale matches
+			if msg.filter == m.filter {
+				m.matches = msg.matches
+			}
+		}
+	case topTickMsg:
+		return m, tea.Batch(m.startRefresh(), m.tick())
+	}
+	return m, nil
+}
+
+// updateFilter handles a key pressed while the filter is being typed. Enter applies it,
+// refreshing straight away, and esc abandons the edit.
+func (m *topModel) updateFilter(msg tea.KeyMsg) tea.Cmd {
+	switch msg.Type {
+	case tea.KeyEnter:
+		m.editing = false
+		m.filter = strings.TrimSpace(m.input)
+		if m.filter == "" {
+			m.matches = nil
+			return nil
+		}
+		// Run even if a refresh is in flight, since that one uses the old filter
+		m.refreshing = true
+		return m.refresh(m.filter)
+	case tea.KeyEsc:
+		m.editing = false
+	case tea.KeyCtrlC:
+		return tea.Quit
+	case tea.KeyBackspace:
+		if runes := []rune(m.input); len(runes) > 0 {
+			m.input = string(runes[:len(runes)-1])
+		}
+	case tea.KeyRunes, tea.KeySpace:
+		m.input += string(msg.Runes)
+	}
+	return nil
+}
+
+// refresh returns a command that collects a new summary, the sessions matching filter and
+// each session's last activity time
+func (m *topModel) refresh(filter string) tea.Cmd {
+	return func() tea.Msg {
+		return m.collect(filter)
+	}
+}
+
+func (m *topModel) collect(filter string) tea.Msg {
+	ctx := context.Background()
+	now := time.Now()
+
+	summary, err := m.dashboard.Summary(ctx)
+	if err != nil {
+		return topRefreshMsg{err: err, at: now, commands: m.dashboard.CommandStats()}
+	}
+
+	var matches map[string]bool
+	if filter != "" {
+		found, err := m.sessions.SearchSessions(ctx, filter)
+		if err != nil {
+			return topRefreshMsg{err: err, at: now}
+		}
+		matches = make(map[string]bool, len(found))
+		for _, s := range found {
+			matches[s.ID] = true
+		}
+	}
+
+	output := make(map[string]string, len(summary.Sessions))
+	activity := make(map[string]time.Time, len(summary.Sessions))
+	for _, s := range summary.Sessions {
+		last, seen := m.lastActivity[s.ID]
+		if !seen {
+			last = s.UpdatedAt
+		}
+		activity[s.ID] = last
+
+		if s.Status == facade.StatusPaused {
+			continue
+		}
+		preview, err := m.viewer.GetPreview(ctx, s.ID)
+		if err != nil {
+			continue
+		}
+		if prev, seen := m.lastOutput[s.ID]; seen && prev != preview {
+			activity[s.ID] = now
+		}
+		output[s.ID] = preview
+	}
+
+	return topRefreshMsg{summary: summary, at: now, output: output, activity: activity, filter: filter, matches: matches,
+		commands: m.dashboard.CommandStats()}
+}
+
+func (m *topModel) View() string {
+	var b strings.Builder
+
+	if m.summary == nil {
+		if m.err != nil {
+			return fmt.Sprintf("failed to collect status: %v\n", m.err)
+		}
+		return "Loading sessions...\n"
+	}
+
+	daemon := "daemon not running"
+	if m.summary.Daemon.Running {
+		daemon = fmt.Sprintf("daemon running (pid %d)", m.summary.Daemon.PID)
+	}
+	fmt.Fprintf(&b, "%s  %d sessions, %d waiting, %s  %s\n",
+		topHeaderStyle.Render("cs top"), m.summary.Total, m.summary.Waiting, daemon,
+		topDimStyle.Render(m.updatedAt.Format("15:04:05")))
+	if m.err != nil {
+		fmt.Fprintf(&b, "refresh failed: %v\n", m.err)
+	}
+	switch {
+	case m.editing:
+		fmt.Fprintf(&b, "filter: %s█\n", m.input)
+	case m.filter != "":
+		fmt.Fprintf(&b, "filter: %s\n", m.filter)
+	}
+	b.WriteString("\n")
+
+	fmt.Fprintf(&b, "%s\n", topHeaderStyle.Render(fmt.Sprintf("%-8s %-24s %-28s %12s %10s  %s", "STATUS", "TITLE", "BRANCH", "DIFF", "ACTIVE", "INPUT")))
+	shown := 0
+	for _, s := range m.summary.Sessions {
+		if m.matches != nil && !m.matches[s.ID] {
+			continue
+		}
+		shown++
+
+		diff := topDimStyle.Render(fmt.Sprintf("%12s", "-"))
+		if s.Diff != nil {
+			added := topAddedStyle.Render(fmt.Sprintf("+%d", s.Diff.Added))
+			removed := topRemovedStyle.Render(fmt.Sprintf("-%d", s.Diff.Removed))
+			pad := 12 - len(fmt.Sprintf("+%d -%d", s.Diff.Added, s.Diff.Removed))
+			diff = strings.Repeat(" ", max(pad, 0)) + added + " " + removed
+		}
+
+		input := ""
+		if s.WaitingOnPrompt {
+			input = topWaitingStyle.Render("waiting")
+		}
+		if s.Conflicted {
+			input = strings.TrimSpace(input + " " + topRemovedStyle.Render("conflicted"))
+		}
+
+		fmt.Fprintf(&b, "%-8s %-24s %-28s %s %10s  %s\n",
+			getStatusString(s.Status), truncate(s.Title, 24), truncate(s.Branch, 28), diff,
+			formatAge(time.Since(m.lastActivity[s.ID])), input)
+	}
+	if shown == 0 {
+		if m.filter != "" {
+			b.WriteString(topDimStyle.Render("No sessions match the filter") + "\n")
+		} else {
+			b.WriteString(topDimStyle.Render("No sessions") + "\n")
+		}
+	}
+
+	if m.showCommands {
+		b.WriteString("\n" + m.commandsView())
+	}
+
+	help := "/ filter • d commands • r refresh • q quit"
+	if m.filter != "" {
+		help = "/ filter • esc clear filter • d commands • r refresh • q quit"
+	}
+	b.WriteString("\n" + topDimStyle.Render(help) + "\n")
+	return b.String()
+}
+
+// commandsView renders the commands run so far, per program
+func (m *topModel) commandsView() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s\n", topHeaderStyle.Render(fmt.Sprintf("%-16s %10s %10s %8s %10s", "PROGRAM", "RUNS", "FAILED", "RUNNING", "P95")))
+	if len(m.commands) == 0 {
+		b.WriteString(topDimStyle.Render("No commands counted") + "\n")
+	}
+	for _, c := range m.commands {
+		fmt.Fprintf(&b, "%-16s %10d %10d %8d %10s\n",
+			truncate(c.Program, 16), c.Executions, c.Failures, c.Running, c.P95.Round(time.Millisecond))
+	}
+	return b.String()
+}
+
+// truncate shortens s to at most n runes, marking the cut with an ellipsis
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n-1]) + "…"
+}
+
+// formatAge renders a duration as a short "ago" string, e.g. "3m ago"
+func formatAge(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds ago", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+	}
+}
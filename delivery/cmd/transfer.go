@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"claude-squad/interface/facade"
+
+	"github.com/spf13/cobra"
+)
+
+// NewExportCmd creates a command that exports sessions to a tar.gz archive
+func NewExportCmd(sessionManager facade.SessionManager) *cobra.Command {
+	var (
+		all            bool
+		filter         sessionFilter
+		outputPath     string
+		includeBundles bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "export [session-title-or-id...]",
+		Short: "Export sessions to a tar.gz archive for importing on another machine",
+		Long: `Export sessions to a tar.gz archive for importing on another machine. The archive holds
+each session's title, program, prompt and branch name; with --bundle it also holds a git
+bundle of each branch. Only committed work is bundled.`,
+		ValidArgsFunction: completeSessionList(sessionManager),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+
+			if all == (len(args) > 0) {
+				return fmt.Errorf("specify sessions to export or --all, but not both")
+			}
+
+			var sessions []facade.SessionInfo
+			if all {
+				var err error
+				if sessions, err = filter.selectSessions(ctx, sessionManager); err != nil {
+					return err
+				}
+			} else {
+				for _, ref := range args {
+					sess, err := resolveSession(ctx, sessionManager, ref)
+					if err != nil {
+						return err
+					}
+					sessions = append(sessions, *sess)
+				}
+			}
+			if len(sessions) == 0 {
+				fmt.Println("No sessions to export")
+				return nil
+			}
+
+			ids := make([]string, len(sessions))
+			for i, sess := range sessions {
+				ids[i] = sess.ID
+			}
+
+			f, err := os.Create(outputPath)
+			if err != nil {
+				return fmt.Errorf("failed to create %s: %w", outputPath, err)
+			}
+			if err := sessionManager.ExportSessions(ctx, ids, f, includeBundles); err != nil {
+				f.Close()
+				os.Remove(outputPath)
+				return fmt.Errorf("failed to export sessions: %w", err)
+			}
+			if err := f.Close(); err != nil {
+				return fmt.Errorf("failed to write %s: %w", outputPath, err)
+			}
+
+			fmt.Printf("Exported %d session(s) to %s\n", len(sessions), outputPath)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&all, "all", false, "Export all sessions matching the filters")
+	filter.addFlags(cmd)
+	cmd.Flags().StringVarP(&outputPath, "file", "f", "sessions.tar.gz", "Archive to write")
+	cmd.Flags().BoolVar(&includeBundles, "bundle", false, "Include a git bundle of each session's branch")
+
+	return cmd
+}
+
+// NewImportCmd creates a command that recreates sessions from an export archive
+func NewImportCmd(sessionManager facade.SessionManager) *cobra.Command {
+	var repoPath string
+
+	cmd := &cobra.Command{
+		Use:   "import [archive]",
+		Short: "Recreate sessions, worktrees and tmux sessions from an export archive",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+
+			if repoPath != "" {
+				abs, err := filepath.Abs(repoPath)
+				if err != nil {
+					return fmt.Errorf("failed to resolve path: %w", err)
+				}
+				repoPath = abs
+			}
+
+			f, err := os.Open(args[0])
+			if err != nil {
+				return fmt.Errorf("failed to open %s: %w", args[0], err)
+			}
+			defer f.Close()
+
+			sessions, err := sessionManager.ImportSessions(ctx, f, repoPath)
+			for _, sess := range sessions {
+				fmt.Printf("Imported session '%s' (%s) on branch %s\n", sess.Title, sess.ID, sess.Branch)
+			}
+			if err != nil {
+				return fmt.Errorf("failed to import sessions: %w", err)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&repoPath, "path", "", "Repository to import into (defaults to each session's original repository)")
+
+	return cmd
+}
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"claude-squad/interface/facade"
+
+	"github.com/spf13/cobra"
+)
+
+// NewTransplantCmd creates a command that copies a session's work onto another branch
+func NewTransplantCmd(sessionManager facade.SessionManager) *cobra.Command {
+	var (
+		opts   facade.TransplantOptions
+		to     string
+		output string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "transplant [session-title-or-id]",
+		Short: "Copy a session's commits or changes onto another session or the repository",
+		Long: `Cherry-pick a session's commits onto another session's branch, given with --to, or
+the branch checked out in the repository, without merging the branches. By default every
+commit the target branch doesn't have is picked; --commit picks only those given. With
+--uncommitted, the session's uncommitted changes are applied instead, left unstaged.
+Commits that conflict are aborted and changes that don't apply leave everything as it
+was.`,
+		Example: `  cs transplant mysession
+  cs transplant mysession --to other --commit 1a2b3c4
+  cs transplant mysession --to other --uncommitted`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeSessions(sessionManager),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := validateOutputFormat(output); err != nil {
+				return err
+			}
+			if opts.Uncommitted && len(opts.Commits) > 0 {
+				return fmt.Errorf("--commit and --uncommitted can't be used together")
+			}
+
+			ctx := context.Background()
+			sess, err := resolveSession(ctx, sessionManager, args[0])
+			if err != nil {
+				return err
+			}
+			if to != "" {
+				target, err := resolveSession(ctx, sessionManager, to)
+				if err != nil {
+					return err
+				}
+				opts.Target = target.ID
+			}
+
+			result, err := sessionManager.TransplantSession(ctx, sess.ID, opts)
+			if err != nil {
+				return fmt.Errorf("failed to transplant session '%s': %w", sess.Title, err)
+			}
+
+			if output != outputText {
+				return writeStructured(output, result)
+			}
+			switch {
+			case result.Patched:
+				fmt.Printf("Applied the uncommitted changes of '%s' to %s (%s)\n", sess.Title, result.Branch, result.Path)
+			case len(result.Commits) > 0:
+				fmt.Printf("Cherry-picked %d commits from '%s' onto %s (%s)\n", len(result.Commits), sess.Title, result.Branch, result.Path)
+			default:
+				fmt.Printf("Cherry-picked %s onto %s (%s)\n", strings.Join(opts.Commits, ", "), result.Branch, result.Path)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&to, "to", "", "Session to copy onto (default: the branch checked out in the repository)")
+	cmd.Flags().StringSliceVar(&opts.Commits, "commit", nil, "Commit or range to cherry-pick, repeatable (default: all the target lacks)")
+	cmd.Flags().BoolVar(&opts.Uncommitted, "uncommitted", false, "Apply the session's uncommitted changes instead of its commits")
+	addOutputFlag(cmd, &output)
+	_ = cmd.RegisterFlagCompletionFunc("to", completeSessions(sessionManager))
+
+	return cmd
+}
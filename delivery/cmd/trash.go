@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"claude-squad/interface/facade"
+
+	"github.com/spf13/cobra"
+)
+
+// NewTrashCmd creates a command that lists deleted sessions, with subcommands to restore
+// them or purge them for good
+func NewTrashCmd(sessionManager facade.SessionManager) *cobra.Command {
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "trash",
+		Short: "List deleted sessions that can still be restored",
+		Long: `List the sessions deleted with 'cs kill'. A deleted session keeps its prompt, input
+history, metadata, recorded output and a snapshot of its uncommitted changes until the
+trash is purged, so it can be restored with 'cs trash restore'.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := validateOutputFormat(output); err != nil {
+				return err
+			}
+
+			sessions, err := sessionManager.ListTrash(context.Background())
+			if err != nil {
+				return fmt.Errorf("failed to list trash: %w", err)
+			}
+
+			if output != outputText {
+				return writeStructured(output, sessions)
+			}
+
+			if len(sessions) == 0 {
+				fmt.Println("Trash is empty")
+				return nil
+			}
+			for _, sess := range sessions {
+				fmt.Printf("  %s - %s (deleted %s)\n", sess.Title, sess.Branch, formatAge(time.Since(sess.DeletedAt)))
+			}
+			return nil
+		},
+	}
+
+	addOutputFlag(cmd, &output)
+	cmd.AddCommand(newTrashRestoreCmd(sessionManager))
+	cmd.AddCommand(newTrashPurgeCmd(sessionManager))
+
+	return cmd
+}
+
+func newTrashRestoreCmd(sessionManager facade.SessionManager) *cobra.Command {
+	var resume bool
+
+	cmd := &cobra.Command{
+		Use:               "restore <session-title-or-id>...",
+		Short:             "Restore deleted sessions, paused, with their uncommitted changes",
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: completeTrash(sessionManager),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			trash, err := sessionManager.ListTrash(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to list trash: %w", err)
+			}
+
+			failed := 0
+			for _, ref := range args {
+				if err := restoreFromTrash(ctx, sessionManager, trash, ref, resume); err != nil {
+					failed++
+					fmt.Printf("failed to restore '%s': %v\n", ref, err)
+					continue
+				}
+				fmt.Printf("Session '%s' restored\n", ref)
+			}
+			if failed > 0 {
+				return fmt.Errorf("%d of %d sessions could not be restored", failed, len(args))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&resume, "resume", false, "Resume the sessions after restoring them")
+
+	return cmd
+}
+
+// restoreFromTrash restores the trashed session ref names, by ID or else by title. The
+// trash is listed most recently deleted first, so a reused title restores the latest.
+func restoreFromTrash(ctx context.Context, sessionManager facade.SessionManager, trash []facade.SessionInfo, ref string, resume bool) error {
+	id := ""
+	for _, sess := range trash {
+		if sess.ID == ref {
+			id = sess.ID
+			break
+		}
+	}
+	if id == "" {
+		for _, sess := range trash {
+			if sess.Title == ref {
+				id = sess.ID
+				break
+			}
+		}
+	}
+	if id == "" {
+		return fmt.Errorf("not in the trash")
+	}
+
+	if err := sessionManager.RestoreSession(ctx, id); err != nil {
+		return err
+	}
+	if resume {
+		return sessionManager.ResumeSession(ctx, id)
+	}
+	return nil
+}
+
+func newTrashPurgeCmd(sessionManager facade.SessionManager) *cobra.Command {
+	var olderThan time.Duration
+
+	cmd := &cobra.Command{
+		Use:     "purge",
+		Short:   "Permanently delete sessions in the trash",
+		Example: `  cs trash purge --older-than 720h`,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			sessions, err := sessionManager.PurgeTrash(context.Background(), olderThan)
+			if err != nil {
+				return fmt.Errorf("failed to purge trash: %w", err)
+			}
+			if len(sessions) == 0 {
+				fmt.Println("No sessions to purge")
+				return nil
+			}
+			for _, sess := range sessions {
+				fmt.Printf("Purged '%s'\n", sess.Title)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().DurationVar(&olderThan, "older-than", 0, "Only purge sessions deleted longer ago than this (e.g. 720h)")
+
+	return cmd
+}
+
+// completeTrash completes arguments with the titles of sessions in the trash
+func completeTrash(sessionManager facade.SessionManager) completionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		sessions, err := sessionManager.ListTrash(context.Background())
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		var titles []string
+		for _, sess := range sessions {
+			titles = append(titles, sess.Title)
+		}
+		return titles, cobra.ShellCompDirectiveNoFileComp
+	}
+}
@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"claude-squad/interface/facade"
+
+	"github.com/spf13/cobra"
+)
+
+// Conditions accepted by `cs wait --for`
+const (
+	waitReady  = "ready"
+	waitPaused = "paused"
+	waitPrompt = "prompt"
+	waitIdle   = "idle"
+)
+
+// NewWaitCmd creates a command that blocks until a session reaches a state
+func NewWaitCmd(sessionManager facade.SessionManager, interactor facade.SessionInteractor, viewer facade.SessionViewer) *cobra.Command {
+	var (
+		condition string
+		timeout   time.Duration
+		interval  time.Duration
+		idleFor   time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "wait <session-title-or-id>",
+		Short: "Wait until a session reaches a state",
+		Long: `Block until a session reaches a state, then exit 0. Exits 1 if --timeout passes first.
+
+Conditions:
+  ready   the session's status is ready
+  paused  the session's status is paused
+  prompt  the session's program is waiting for input
+  idle    the session's output has not changed for --idle-for`,
+		Example:           `  cs wait fix-auth --for prompt --timeout 10m && cs send fix-auth "now add tests"`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeSessions(sessionManager),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			switch condition {
+			case waitReady, waitPaused, waitPrompt, waitIdle:
+			default:
+				return fmt.Errorf("unknown condition '%s' (expected ready, paused, prompt or idle)", condition)
+			}
+			if interval <= 0 {
+				return fmt.Errorf("--interval must be positive")
+			}
+
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+			defer stop()
+			if timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, timeout)
+				defer cancel()
+			}
+
+			sess, err := resolveSession(ctx, sessionManager, args[0])
+			if err != nil {
+				return err
+			}
+
+			w := &sessionWaiter{
+				sessionManager: sessionManager,
+				interactor:     interactor,
+				viewer:         viewer,
+				condition:      condition,
+				idleFor:        idleFor,
+			}
+
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+			for {
+				done, err := w.check(ctx, sess.ID, time.Now())
+				if err != nil {
+					return err
+				}
+				if done {
+					return nil
+				}
+
+				select {
+				case <-ctx.Done():
+					if ctx.Err() == context.DeadlineExceeded {
+						return fmt.Errorf("timed out after %s waiting for '%s' to be %s", timeout, sess.Title, condition)
+					}
+					return fmt.Errorf("interrupted while waiting for '%s' to be %s", sess.Title, condition)
+				case <-ticker.C:
+				}
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&condition, "for", waitReady, "Condition to wait for: ready, paused, prompt or idle")
+	cmd.Flags().DurationVar(&timeout, "timeout", 0, "Give up and exit 1 after this long (0 waits forever)")
+	cmd.Flags().DurationVar(&interval, "interval", time.Second, "How often to check the session")
+	cmd.Flags().DurationVar(&idleFor, "idle-for", 30*time.Second, "How long output must be unchanged to count as idle")
+	_ = cmd.RegisterFlagCompletionFunc("for", cobra.FixedCompletions([]string{waitReady, waitPaused, waitPrompt, waitIdle}, cobra.ShellCompDirectiveNoFileComp))
+
+	return cmd
+}
+
+// sessionWaiter checks a session against a `cs wait` condition, remembering the output
+// seen so far for the idle condition
+type sessionWaiter struct {
+	sessionManager facade.SessionManager
+	interactor     facade.SessionInteractor
+	viewer         facade.SessionViewer
+	condition      string
+	idleFor        time.Duration
+
+	lastOutput  string
+	lastChanged time.Time
+}
+
+// check reports whether the session currently meets the condition
+func (w *sessionWaiter) check(ctx context.Context, id string, now time.Time) (bool, error) {
+	sess, err := w.sessionManager.GetSession(ctx, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to get session: %w", err)
+	}
+
+	switch w.condition {
+	case waitReady:
+		return sess.Status == facade.StatusReady, nil
+	case waitPaused:
+		return sess.Status == facade.StatusPaused, nil
+	}
+
+	// A paused session has no pane to inspect, so it can never show a prompt or go idle
+	if sess.Status == facade.StatusPaused {
+		return false, fmt.Errorf("session '%s' is paused", sess.Title)
+	}
+
+	switch w.condition {
+	case waitPrompt:
+		// The pane may not exist yet while the session is starting
+		waiting, err := w.interactor.HasPrompt(ctx, id)
+		return err == nil && waiting, nil
+	case waitIdle:
+		output, err := w.viewer.GetPreview(ctx, id)
+		if err != nil {
+			return false, nil
+		}
+		if w.lastChanged.IsZero() || output != w.lastOutput {
+			w.lastOutput, w.lastChanged = output, now
+			return false, nil
+		}
+		return now.Sub(w.lastChanged) >= w.idleFor, nil
+	default:
+		return false, fmt.Errorf("unknown condition '%s'", w.condition)
+	}
+}
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"claude-squad/interface/facade"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeWaitSessions struct {
+	facade.SessionManager
+	status facade.SessionStatus
+}
+
+func (f *fakeWaitSessions) GetSession(ctx context.Context, id string) (*facade.SessionInfo, error) {
+	return &facade.SessionInfo{ID: id, Title: id, Status: f.status}, nil
+}
+
+type fakeWaitViewer struct {
+	facade.SessionViewer
+	output string
+}
+
+func (f *fakeWaitViewer) GetPreview(ctx context.Context, id string) (string, error) {
+	return f.output, nil
+}
+
+func TestSessionWaiterIdle(t *testing.T) {
+	ctx := context.Background()
+	viewer := &fakeWaitViewer{output: "thinking"}
+	w := &sessionWaiter{
+		sessionManager: &fakeWaitSessions{status: facade.StatusRunning},
+		viewer:         viewer,
+		condition:      waitIdle,
+		idleFor:        time.Minute,
+	}
+
+	start := time.Now()
+	for _, step := range []struct {
+		after  time.Duration
+		output string
+		idle   bool
+	}{
+		{0, "thinking", false},
+		{50 * time.Second, "thinking", false},
+		{55 * time.Second, "editing", false},
+		{time.Minute + 50*time.Second, "editing", false},
+		{2 * time.Minute, "editing", true},
+	} {
+		viewer.output = step.output
+		idle, err := w.check(ctx, "s", start.Add(step.after))
+		require.NoError(t, err)
+		assert.Equal(t, step.idle, idle, "after %s", step.after)
+	}
+}
+
+func TestSessionWaiterStatus(t *testing.T) {
+	ctx := context.Background()
+	sessions := &fakeWaitSessions{status: facade.StatusRunning}
+	w := &sessionWaiter{sessionManager: sessions, condition: waitReady}
+
+	ready, err := w.check(ctx, "s", time.Now())
+	require.NoError(t, err)
+	assert.False(t, ready)
+
+	sessions.status = facade.StatusReady
+	ready, err = w.check(ctx, "s", time.Now())
+	require.NoError(t, err)
+	assert.True(t, ready)
+
+	sessions.status = facade.StatusPaused
+	w.condition = waitPrompt
+	_, err = w.check(ctx, "s", time.Now())
+	assert.Error(t, err)
+}
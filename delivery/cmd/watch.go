@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"claude-squad/interface/facade"
+
+	"github.com/spf13/cobra"
+)
+
+// NewWatchCmd creates a command that streams session events as JSON lines
+func NewWatchCmd(sessionManager facade.SessionManager, watcher facade.SessionWatcher) *cobra.Command {
+	var opts facade.WatchOptions
+
+	cmd := &cobra.Command{
+		Use:   "watch [session-title-or-id...]",
+		Short: "Stream session events as JSON lines",
+		Long: `Stream session events as JSON lines until interrupted. Each line is one event:
+created, status-changed, prompt-detected, stopped, and with --include-output, output.
+Without arguments every session is watched, including ones created later.`,
+		ValidArgsFunction: completeSessionList(sessionManager),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+			defer stop()
+
+			for _, ref := range args {
+				sess, err := resolveSession(ctx, sessionManager, ref)
+				if err != nil {
+					return err
+				}
+				opts.SessionIDs = append(opts.SessionIDs, sess.ID)
+			}
+
+			events, err := watcher.Watch(ctx, opts)
+			if err != nil {
+				return fmt.Errorf("failed to watch sessions: %w", err)
+			}
+
+			enc := json.NewEncoder(os.Stdout)
+			for event := range events {
+				if err := enc.Encode(event); err != nil {
+					return fmt.Errorf("failed to write event: %w", err)
+				}
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().DurationVar(&opts.Interval, "interval", time.Second, "How often to poll sessions for changes")
+	cmd.Flags().BoolVar(&opts.IncludeOutput, "include-output", false, "Also emit an event whenever a session's output changes")
+
+	return cmd
+}
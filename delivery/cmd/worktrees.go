@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"claude-squad/interface/facade"
+
+	"github.com/spf13/cobra"
+)
+
+// NewWorktreesCmd creates a command grouping operations on session worktrees
+func NewWorktreesCmd(sessionManager facade.SessionManager) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "worktrees",
+		Short: "Manage where session worktrees are kept",
+		Args:  cobra.NoArgs,
+	}
+
+	cmd.AddCommand(newWorktreesMigrateCmd(sessionManager))
+
+	return cmd
+}
+
+func newWorktreesMigrateCmd(sessionManager facade.SessionManager) *cobra.Command {
+	var (
+		dryRun bool
+		output string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Move session worktrees to the configured directory and names",
+		Long: `Move the worktrees of sessions to where worktree_dir and worktree_name put them,
+after either was changed. Only paused sessions are moved; pause running ones first.
+Sessions whose worktree is gone are pointed at the new path, where resuming creates it.`,
+		Example: `  cs worktrees migrate --dry-run
+  cs worktrees migrate`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := validateOutputFormat(output); err != nil {
+				return err
+			}
+
+			moves, err := sessionManager.MigrateWorktrees(context.Background(), dryRun)
+			if output != outputText && err == nil {
+				return writeStructured(output, moves)
+			}
+
+			for _, move := range moves {
+				if move.Skipped != "" {
+					fmt.Printf("  %s: skipped, %s\n", move.Title, move.Skipped)
+					continue
+				}
+				fmt.Printf("  %s: %s -> %s\n", move.Title, move.From, move.To)
+			}
+			if err != nil {
+				return fmt.Errorf("failed to migrate worktrees: %w", err)
+			}
+			if len(moves) == 0 {
+				fmt.Println("All worktrees are already in place")
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Only show where worktrees would be moved")
+	addOutputFlag(cmd, &output)
+
+	return cmd
+}
@@ -2,52 +2,353 @@ package main
 
 import (
 	"fmt"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
 	"os"
+	"path/filepath"
+	"time"
 
+	"claude-squad/config"
+	"claude-squad/daemon"
 	"claude-squad/delivery/cmd"
 	"claude-squad/interface/coreadapter"
+	"claude-squad/log"
 	"claude-squad/services/executor"
 	"claude-squad/services/git"
 	"claude-squad/services/session"
 	"claude-squad/services/storage"
 	"claude-squad/services/tmux"
+	"claude-squad/services/types"
 
 	"github.com/spf13/cobra"
 )
 
 // Example of how to wire up the application using facades
 func main() {
+	// Deferred first so it runs last, once everything else deferred has been cleaned up
+	exitCode := 0
+	defer func() {
+		if exitCode != 0 {
+			os.Exit(exitCode)
+		}
+	}()
+
+	// Logging comes first: loading a malformed config logs the error
+	log.Initialize(false)
+	defer log.Close()
+
+	// Global flags pick the config file and session store, so they are read before the
+	// services are built
+	globals, err := cmd.ParseGlobalOptions(os.Args[1:])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	config.SetConfigPath(globals.ConfigPath)
+
+	configDir, err := config.GetConfigDir()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	storageDir := globals.StorageDir
+	if storageDir == "" {
+		storageDir = filepath.Join(configDir, "sessions")
+	}
+	cfg := config.LoadConfig()
+
 	// Initialize core services (this would be in app.InitializeDependencies)
-	executor := executor.NewExecutor(nil)
-	gitService := git.NewExecGitService(executor)
-	tmuxService := tmux.NewExecTmuxService(executor)
-	storage := storage.NewJSONRepository("~/.claude-squad/sessions")
-	orchestrator := session.NewOrchestrator(gitService, tmuxService, storage, executor)
+	metrics := executor.NewMetrics()
+	if cfg.MetricsAddr != "" {
+		serveMetrics(cfg.MetricsAddr, metrics)
+	}
+	executor, err := newExecutor(cfg, configDir, globals.DryRun, metrics)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	gitService, err := git.NewService(cfg.GitBackend, executor)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	tmuxService, err := tmux.NewService(cfg.Multiplexer, executor, tmux.KubernetesOptions{
+		Context:      cfg.Kubernetes.Context,
+		Namespace:    cfg.Kubernetes.Namespace,
+		Image:        cfg.Kubernetes.Image,
+		WorkDir:      cfg.Kubernetes.WorkDir,
+		ReadyTimeout: time.Duration(cfg.Kubernetes.ReadyTimeout) * time.Second,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	storage, auditLog, err := newStorage(cfg, storageDir, globals.DryRun)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	worktreeLayout := session.WorktreeLayout{Dir: cfg.WorktreeDir, Name: cfg.WorktreeName}
+	// Output is recorded periodically while long-running commands like top and watch are
+	// open, and always just before a session's pane is killed
+	outputHistory := session.NewOutputHistory(filepath.Join(storageDir, "output"), tmuxService, storage,
+		session.DefaultOutputHistoryInterval, session.DefaultOutputHistoryLimit)
+	outputHistory.Start()
+	defer outputHistory.Close()
+	orchestratorOpts := []session.OrchestratorOption{
+		session.WithWorktreeLayout(worktreeLayout),
+		session.WithSubmodules(cfg.WorktreeSubmodules),
+		session.WithCommitSettings(types.CommitSettings{
+			RunHooks:      &cfg.Commit.RunHooks,
+			Sign:          &cfg.Commit.Sign,
+			SigningKey:    cfg.Commit.SigningKey,
+			SigningFormat: cfg.Commit.SigningFormat,
+			Author:        cfg.Commit.Author,
+		}),
+		session.WithOutputHistory(outputHistory),
+		session.WithAuditLog(auditLog),
+		session.WithForgeHosts(cfg.ForgeHosts),
+	}
+	// Inside a repository only its sessions are shown, unless --all-repos is given
+	if !globals.AllRepos {
+		if cwd, err := os.Getwd(); err == nil {
+			if root, err := gitService.GetRepositoryRoot(context.Background(), cwd); err == nil {
+				orchestratorOpts = append(orchestratorOpts, session.WithRepository(root))
+			}
+		}
+	}
+	orchestrator := session.NewOrchestrator(gitService, tmuxService, storage, executor, orchestratorOpts...)
 
 	// Create facades (thin adapters)
 	sessionManager := coreadapter.NewSessionManager(orchestrator)
 	sessionInteractor := coreadapter.NewSessionInteractor(orchestrator)
 	sessionViewer := coreadapter.NewSessionViewer(orchestrator)
 	diffViewer := coreadapter.NewDiffViewer(orchestrator, gitService)
+	// The orchestrator only lists the current repository's sessions, so doctor and gc are
+	// told which other claude-squad tmux sessions are still in use
+	keepTmux := keepTmuxSessions(storage, tuiTmuxSessions())
+	diagnostics := coreadapter.NewDiagnostics(executor, gitService, tmuxService, orchestrator, storage, configDir, worktreeLayout, keepTmux)
+	dashboard := coreadapter.NewDashboard(orchestrator, gitService, sessionInteractor, daemon.Status, metrics)
+	sessionWatcher := coreadapter.NewSessionWatcher(orchestrator, sessionInteractor, storage)
+	reconciler := coreadapter.NewResourceReconciler(executor, gitService, tmuxService, orchestrator, worktreeLayout, keepTmux)
 
 	// Create root command
 	rootCmd := &cobra.Command{
 		Use:   "cs",
 		Short: "Claude Squad - Manage AI coding sessions",
 	}
+	globals.AddFlags(rootCmd)
 
 	// Add subcommands with facade dependencies
 	rootCmd.AddCommand(cmd.NewListCmd(sessionManager))
+	rootCmd.AddCommand(cmd.NewStatusCmd(dashboard))
+	rootCmd.AddCommand(cmd.NewTopCmd(dashboard, sessionManager, sessionViewer))
 	rootCmd.AddCommand(cmd.NewDiffCmd(sessionManager, diffViewer))
+	rootCmd.AddCommand(cmd.NewBlameCmd(sessionManager, diffViewer))
+	rootCmd.AddCommand(cmd.NewNewCmd(sessionManager, cfg.DefaultProgram))
+	rootCmd.AddCommand(cmd.NewCloneCmd(sessionManager))
+	rootCmd.AddCommand(cmd.NewRenameCmd(sessionManager))
+	rootCmd.AddCommand(cmd.NewExportCmd(sessionManager))
+	rootCmd.AddCommand(cmd.NewImportCmd(sessionManager))
+	rootCmd.AddCommand(cmd.NewAttachCmd(sessionManager, sessionInteractor))
+	rootCmd.AddCommand(cmd.NewSendCmd(sessionManager, sessionInteractor))
+	rootCmd.AddCommand(cmd.NewLogsCmd(sessionManager, sessionViewer))
+	rootCmd.AddCommand(cmd.NewHistoryCmd(sessionManager, sessionViewer))
+	rootCmd.AddCommand(cmd.NewAuditCmd(sessionManager, sessionViewer))
+	rootCmd.AddCommand(cmd.NewWatchCmd(sessionManager, sessionWatcher))
+	rootCmd.AddCommand(cmd.NewWaitCmd(sessionManager, sessionInteractor, sessionViewer))
+	rootCmd.AddCommand(cmd.NewKillCmd(sessionManager))
+	rootCmd.AddCommand(cmd.NewTrashCmd(sessionManager))
+	rootCmd.AddCommand(cmd.NewWorktreesCmd(sessionManager))
+	rootCmd.AddCommand(cmd.NewPauseCmd(sessionManager))
+	rootCmd.AddCommand(cmd.NewResumeCmd(sessionManager))
+	rootCmd.AddCommand(cmd.NewArchiveCmd(sessionManager))
+	rootCmd.AddCommand(cmd.NewUnarchiveCmd(sessionManager))
+	rootCmd.AddCommand(cmd.NewPruneCmd(sessionManager))
+	rootCmd.AddCommand(cmd.NewExecCmd(sessionManager, sessionInteractor))
+	rootCmd.AddCommand(cmd.NewReplayCmd(sessionManager, sessionInteractor))
+	rootCmd.AddCommand(cmd.NewOpenCmd(sessionManager, sessionInteractor, cfg.Editor))
+	rootCmd.AddCommand(cmd.NewCommitCmd(sessionManager, diffViewer))
+	rootCmd.AddCommand(cmd.NewCommitSettingsCmd(sessionManager))
+	rootCmd.AddCommand(cmd.NewPushCmd(sessionManager))
+	rootCmd.AddCommand(cmd.NewFinishCmd(sessionManager))
+	rootCmd.AddCommand(cmd.NewTransplantCmd(sessionManager))
+	rootCmd.AddCommand(cmd.NewRebaseCmd(sessionManager))
+	rootCmd.AddCommand(cmd.NewConflictsCmd(sessionManager))
+	rootCmd.AddCommand(cmd.NewPRCmd(sessionManager))
+	rootCmd.AddCommand(cmd.NewDoctorCmd(diagnostics))
+	rootCmd.AddCommand(cmd.NewGCCmd(reconciler))
+	rootCmd.AddCommand(cmd.NewConfigCmd())
 
 	// The TUI app would also receive facades:
 	// rootCmd.AddCommand(cmd.NewUICmd(sessionManager, sessionViewer, sessionInteractor))
 
 	// Execute
 	if err := rootCmd.Execute(); err != nil {
+		var exitErr *cmd.ExitError
+		if errors.As(err, &exitErr) {
+			exitCode = exitErr.Code
+			return
+		}
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+		exitCode = 1
+	}
+}
+
+// The command audit log is rotated at 10MB, keeping three older files
+const (
+	commandAuditFileName = "commands.log"
+	commandAuditMaxSize  = 10 << 20
+	commandAuditBackups  = 3
+)
+
+// sessionLogDirName is the directory in the config directory session output is mirrored to
+const sessionLogDirName = "session-logs"
+
+// newExecutor returns the executor git and tmux commands run through: a local one, or one
+// running them on the configured remote host over SSH, with session programs sandboxed in
+// containers when an image is configured. A dry run only prints the commands that would
+// change anything. Every command run is counted in metrics.
+func newExecutor(cfg *config.Config, configDir string, dryRun bool, metrics *executor.Metrics) (executor.CommandExecutor, error) {
+	opts := &executor.ExecutorOptions{
+		DefaultTimeout: 120 * time.Second,
+		MaxConcurrent:  10,
+		Pools:          executor.DefaultPools(),
+		RateLimits:     executor.DefaultRateLimits(),
+		CaptureOutput:  true,
+		DryRun:         dryRun,
+		ResultCacheTTL: time.Duration(cfg.CommandCacheTTL) * time.Millisecond,
+		Metrics:        metrics,
+
+		CancelDrainTimeout: time.Duration(cfg.PauseDrainTimeout) * time.Second,
+		StallTimeout:       time.Duration(cfg.StallTimeout) * time.Second,
+		OnStall:            logStall,
+	}
+	if len(cfg.EnvProfiles) > 0 {
+		opts.EnvProfiles = make(map[string]executor.EnvProfile, len(cfg.EnvProfiles))
+		for name, profile := range cfg.EnvProfiles {
+			opts.EnvProfiles[name] = profile
+		}
+	}
+	if cfg.SessionLogs {
+		opts.SessionLogDir = filepath.Join(configDir, sessionLogDirName)
+	}
+	if cfg.AuditCommands {
+		opts.Audit = executor.NewCommandAuditLog(filepath.Join(configDir, commandAuditFileName),
+			commandAuditMaxSize, commandAuditBackups)
+	}
+	exec := executor.NewExecutor(opts)
+	if cfg.Remote.Host != "" {
+		var err error
+		exec, err = executor.NewSSHExecutor(exec, executor.SSHOptions{
+			Host:       cfg.Remote.Host,
+			User:       cfg.Remote.User,
+			Port:       cfg.Remote.Port,
+			KeyFile:    cfg.Remote.KeyFile,
+			ControlDir: filepath.Join(configDir, "ssh"),
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if cfg.Sandbox.Image != "" {
+		exec = executor.NewContainerExecutor(exec, executor.ContainerOptions{
+			Runtime:   cfg.Sandbox.Runtime,
+			Image:     cfg.Sandbox.Image,
+			Memory:    cfg.Sandbox.Memory,
+			CPUs:      cfg.Sandbox.CPUs,
+			PidsLimit: cfg.Sandbox.PidsLimit,
+			Network:   cfg.Sandbox.Network,
+			Env:       cfg.Sandbox.Env,
+		})
+	}
+	return exec, nil
+}
+
+// logStall logs a command going silent for the stall timeout, and coming back
+func logStall(event executor.StallEvent) {
+	if log.WarningLog == nil {
+		return
+	}
+	if event.Resumed {
+		log.WarningLog.Printf("command resumed output after %v: %s", event.Silent.Round(time.Second), event.Command)
+	} else {
+		log.WarningLog.Printf("command stalled, no output for %v: %s", event.Silent.Round(time.Second), event.Command)
+	}
+}
+
+// serveMetrics serves metrics to Prometheus on addr in the background. Failing to, e.g.
+// because another cs process already serves them there, is logged and otherwise ignored.
+func serveMetrics(addr string, metrics *executor.Metrics) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics)
+	go func() {
+		if err := http.ListenAndServe(addr, mux); err != nil && log.WarningLog != nil {
+			log.WarningLog.Printf("failed to serve metrics on %s: %v", addr, err)
+		}
+	}()
+}
+
+// newStorage opens the configured session store, encrypting it when configured to. Every
+// change made through it is recorded to the returned audit log as made by the CLI, and
+// sessions are cached in memory so long-running views don't re-read the store each tick.
+// A dry run only prints the changes, leaving the store and audit log untouched.
+func newStorage(cfg *config.Config, dir string, dryRun bool) (storage.StorageRepository, *storage.AuditLog, error) {
+	repo, err := storage.NewRepository(cfg.StorageBackend, dir, cfg.StorageDSN)
+	if err != nil {
+		return nil, nil, err
+	}
+	if cfg.EncryptStorage {
+		key, err := storage.LoadEncryptionKey()
+		if err != nil {
+			return nil, nil, err
+		}
+		if repo, err = storage.NewEncryptedRepository(repo, key); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	auditLog := storage.NewAuditLog(filepath.Join(dir, storage.AuditFileName))
+	repo = storage.NewAuditedRepository(repo, auditLog, storage.ActorCLI)
+	repo = storage.NewCachedRepository(context.Background(), repo)
+	if dryRun {
+		repo = storage.NewDryRunRepository(repo, os.Stderr)
+	}
+	return repo, auditLog, nil
+}
+
+// keepTmuxSessions reports the tmux sessions gc must leave alone even though the
+// orchestrator doesn't list them: those owned by the TUI, per tui, and those of stored
+// sessions from repositories other than the current one
+func keepTmuxSessions(repo storage.StorageRepository, tui func(name string) bool) func(name string) bool {
+	stored := make(map[string]bool)
+	if sessions, err := repo.List(context.Background(), nil); err == nil {
+		for _, s := range sessions {
+			stored[tmux.SessionName(s.ID)] = true
+		}
+	}
+	return func(name string) bool {
+		return stored[name] || (tui != nil && tui(name))
+	}
+}
+
+// tuiTmuxSessions reports the tmux sessions owned by instances saved by the TUI, which
+// share the claude-squad prefix but are not in the session store
+func tuiTmuxSessions() func(name string) bool {
+	var instances []struct {
+		Title string `json:"title"`
+	}
+	if err := json.Unmarshal(config.LoadState().GetInstances(), &instances); err != nil {
+		return nil
+	}
+
+	names := make(map[string]bool, len(instances))
+	for _, instance := range instances {
+		names[tmux.SessionName(instance.Title)] = true
 	}
+	return func(name string) bool { return names[name] }
 }
 
 // Example of how a TUI widget would use facades
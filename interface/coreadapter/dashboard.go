@@ -0,0 +1,99 @@
+package coreadapter
+
+import (
+	"context"
+
+	"claude-squad/interface/facade"
+	"claude-squad/services/executor"
+	"claude-squad/services/git"
+	"claude-squad/services/session"
+	"claude-squad/services/types"
+)
+
+// DaemonStatusFunc reports the daemon's PID and whether it is running
+type DaemonStatusFunc func() (pid int, running bool, err error)
+
+// dashboardAdapter builds the status summary from the orchestrator and git service
+type dashboardAdapter struct {
+	orchestrator session.SessionOrchestrator
+	gitService   git.GitService
+	interactor   facade.SessionInteractor
+	daemonStatus DaemonStatusFunc
+	metrics      *executor.Metrics
+}
+
+// NewDashboard creates a new Dashboard facade. daemonStatus may be nil when there is no daemon,
+// and metrics when commands aren't counted.
+func NewDashboard(
+	orchestrator session.SessionOrchestrator,
+	gitService git.GitService,
+	interactor facade.SessionInteractor,
+	daemonStatus DaemonStatusFunc,
+	metrics *executor.Metrics,
+) facade.Dashboard {
+	return &dashboardAdapter{
+		orchestrator: orchestrator,
+		gitService:   gitService,
+		interactor:   interactor,
+		daemonStatus: daemonStatus,
+		metrics:      metrics,
+	}
+}
+
+func (d *dashboardAdapter) Summary(ctx context.Context) (*facade.StatusSummary, error) {
+	sessions, err := d.orchestrator.ListSessions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &facade.StatusSummary{
+		ByStatus: make(map[facade.SessionStatus]int),
+		Sessions: make([]facade.SessionSummary, 0, len(sessions)),
+	}
+	for _, sess := range sessions {
+		if sess.Archived {
+			continue
+		}
+		summary.Total++
+		row := facade.SessionSummary{SessionInfo: toFacadeInfo(sess)}
+		summary.ByStatus[row.Status]++
+
+		// Paused sessions have neither a tmux session nor a worktree to inspect
+		if sess.Status != types.StatusPaused {
+			if waiting, err := d.interactor.HasPrompt(ctx, sess.ID); err == nil && waiting {
+				row.WaitingOnPrompt = true
+				summary.Waiting++
+			}
+			if stats, err := d.gitService.GetDiffStats(ctx, sess.Path); err == nil {
+				row.Diff = &facade.DiffStats{Added: stats.Insertions, Removed: stats.Deletions}
+			}
+		}
+
+		summary.Sessions = append(summary.Sessions, row)
+	}
+
+	if d.daemonStatus != nil {
+		if pid, running, err := d.daemonStatus(); err == nil {
+			summary.Daemon = facade.DaemonState{Running: running, PID: pid}
+		}
+	}
+
+	return summary, nil
+}
+
+func (d *dashboardAdapter) CommandStats() []facade.CommandStats {
+	if d.metrics == nil {
+		return nil
+	}
+	var stats []facade.CommandStats
+	for _, p := range d.metrics.Snapshot() {
+		stats = append(stats, facade.CommandStats{
+			Program:    p.Program,
+			Executions: p.Executions,
+			Failures:   p.Failures,
+			Running:    p.Running,
+			P95:        p.P95,
+		})
+	}
+	return stats
+}
@@ -0,0 +1,398 @@
+package coreadapter
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"claude-squad/interface/facade"
+	"claude-squad/services/executor"
+	"claude-squad/services/git"
+	"claude-squad/services/session"
+	"claude-squad/services/storage"
+	"claude-squad/services/tmux"
+	"claude-squad/services/types"
+)
+
+// Worktree commands claude-squad relies on: `git worktree` itself and `git worktree move`,
+// used to migrate worktrees to a new layout.
+var (
+	minWorktreeGitVersion = [3]int{2, 5, 0}
+	minMoveGitVersion     = [3]int{2, 17, 0}
+)
+
+var versionRegex = regexp.MustCompile(`(\d+)\.(\d+)(?:\.(\d+))?`)
+
+// diagnosticsAdapter runs environment checks against the core services
+type diagnosticsAdapter struct {
+	executor     executor.CommandExecutor
+	gitService   git.GitService
+	tmuxService  tmux.TmuxService
+	orchestrator session.SessionOrchestrator
+	storage      storage.StorageRepository
+	configDir    string
+	layout       session.WorktreeLayout
+	// keepTmux reports tmux sessions owned by something other than the orchestrator, such
+	// as the TUI or sessions of other repositories, which share the claude-squad prefix.
+	// May be nil.
+	keepTmux func(name string) bool
+}
+
+// NewDiagnostics creates a new Diagnostics facade
+func NewDiagnostics(
+	executor executor.CommandExecutor,
+	gitService git.GitService,
+	tmuxService tmux.TmuxService,
+	orchestrator session.SessionOrchestrator,
+	storage storage.StorageRepository,
+	configDir string,
+	layout session.WorktreeLayout,
+	keepTmux func(name string) bool,
+) facade.Diagnostics {
+	return &diagnosticsAdapter{
+		executor:     executor,
+		gitService:   gitService,
+		tmuxService:  tmuxService,
+		orchestrator: orchestrator,
+		storage:      storage,
+		configDir:    configDir,
+		layout:       layout,
+		keepTmux:     keepTmux,
+	}
+}
+
+// checkStorage reports session records that are truncated, corrupt or don't match their
+// checksum, which are otherwise skipped or served as is
+func (d *diagnosticsAdapter) checkStorage(ctx context.Context) facade.CheckResult {
+	result := facade.CheckResult{Name: "session store"}
+	report, err := d.storage.Verify(ctx)
+	if err != nil {
+		result.Status = facade.CheckFail
+		result.Message = fmt.Sprintf("failed to verify: %v", err)
+		return result
+	}
+	if len(report.Corrupt) == 0 {
+		result.Message = fmt.Sprintf("%d session record(s) intact", report.Checked)
+		if report.Unverified > 0 {
+			result.Message += fmt.Sprintf(", %d saved before checksums were recorded", report.Unverified)
+		}
+		return result
+	}
+
+	corrupt := make([]string, len(report.Corrupt))
+	for i, record := range report.Corrupt {
+		corrupt[i] = fmt.Sprintf("%s (%s)", record.Record, record.Problem)
+	}
+	result.Status = facade.CheckWarn
+	result.Message = fmt.Sprintf("%d damaged session record(s):\n    %s", len(corrupt), strings.Join(corrupt, "\n    "))
+	if _, ok := d.storage.(storage.Repairer); ok {
+		result.Hint = "run `cs doctor --repair` to restore or quarantine them"
+	}
+	return result
+}
+
+func (d *diagnosticsAdapter) RepairStorage(ctx context.Context) (*facade.StorageRepair, error) {
+	repairer, ok := d.storage.(storage.Repairer)
+	if !ok {
+		// Database backends keep their own consistency
+		return &facade.StorageRepair{}, nil
+	}
+
+	report, err := repairer.Repair(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &facade.StorageRepair{Restored: report.Restored, Quarantined: report.Quarantined}, nil
+}
+
+func (d *diagnosticsAdapter) RunChecks(ctx context.Context) []facade.CheckResult {
+	results := []facade.CheckResult{
+		d.checkTmux(ctx),
+		d.checkGit(ctx),
+		d.checkConfigDir(),
+	}
+
+	sessions, err := d.orchestrator.ListSessions(ctx)
+	if err != nil {
+		return append(results, facade.CheckResult{
+			Name:    "sessions",
+			Status:  facade.CheckFail,
+			Message: fmt.Sprintf("failed to load sessions: %v", err),
+		})
+	}
+	return append(results,
+		d.checkStorage(ctx),
+		d.checkOrphanedTmuxSessions(ctx, sessions),
+		d.checkStaleWorktrees(ctx, sessions),
+		d.checkOverlaps(ctx, sessions),
+	)
+}
+
+// checkTmux checks the multiplexer sessions run in, tmux or wezterm
+func (d *diagnosticsAdapter) checkTmux(ctx context.Context) facade.CheckResult {
+	program := tmux.ProgramOf(d.tmuxService)
+	result := facade.CheckResult{Name: program}
+	if !d.executor.CommandExists(ctx, program) {
+		result.Status = facade.CheckFail
+		result.Message = program + " not found in PATH"
+		result.Hint = "install tmux, e.g. `brew install tmux` or `apt install tmux`"
+		if program == "wezterm" {
+			result.Hint = "install WezTerm from https://wezterm.org, e.g. `winget install wez.wezterm`"
+		}
+		return result
+	}
+
+	versionFlag := "-V"
+	if program == "wezterm" {
+		versionFlag = "--version"
+	}
+	path, _ := d.executor.Which(ctx, program)
+	version, err := d.programVersion(ctx, program, versionFlag)
+	if err != nil {
+		result.Status = facade.CheckWarn
+		result.Message = fmt.Sprintf("found at %s but could not determine version: %v", path, err)
+		return result
+	}
+	result.Message = fmt.Sprintf("%s (%s)", version, path)
+	return result
+}
+
+func (d *diagnosticsAdapter) checkGit(ctx context.Context) facade.CheckResult {
+	result := facade.CheckResult{Name: "git"}
+	if !d.executor.CommandExists(ctx, "git") {
+		result.Status = facade.CheckFail
+		result.Message = "git not found in PATH"
+		result.Hint = "install git 2.17 or newer"
+		return result
+	}
+
+	path, _ := d.executor.Which(ctx, "git")
+	version, err := d.programVersion(ctx, "git", "--version")
+	if err != nil {
+		result.Status = facade.CheckWarn
+		result.Message = fmt.Sprintf("found at %s but could not determine version: %v", path, err)
+		return result
+	}
+	result.Message = fmt.Sprintf("%s (%s)", version, path)
+
+	parsed, ok := parseVersion(version)
+	switch {
+	case !ok:
+		result.Status = facade.CheckWarn
+		result.Hint = "could not parse the git version; worktree support is unverified"
+	case versionLess(parsed, minWorktreeGitVersion):
+		result.Status = facade.CheckFail
+		result.Hint = "git worktrees require git 2.5 or newer; upgrade git"
+	case versionLess(parsed, minMoveGitVersion):
+		result.Status = facade.CheckWarn
+		result.Hint = "`git worktree move` requires git 2.17; `cs worktrees migrate` will fail"
+	}
+	return result
+}
+
+func (d *diagnosticsAdapter) checkConfigDir() facade.CheckResult {
+	result := facade.CheckResult{Name: "config dir", Message: d.configDir}
+	if err := os.MkdirAll(d.configDir, 0755); err != nil {
+		result.Status = facade.CheckFail
+		result.Message = fmt.Sprintf("failed to create %s: %v", d.configDir, err)
+		result.Hint = "check the permissions of the parent directory"
+		return result
+	}
+
+	f, err := os.CreateTemp(d.configDir, ".doctor-*")
+	if err != nil {
+		result.Status = facade.CheckFail
+		result.Message = fmt.Sprintf("%s is not writable: %v", d.configDir, err)
+		result.Hint = fmt.Sprintf("fix ownership, e.g. `chown -R $USER %s`", d.configDir)
+		return result
+	}
+	f.Close()
+	os.Remove(f.Name())
+	return result
+}
+
+func (d *diagnosticsAdapter) checkOrphanedTmuxSessions(ctx context.Context, sessions []*types.Session) facade.CheckResult {
+	program := tmux.ProgramOf(d.tmuxService)
+	result := facade.CheckResult{Name: program + " sessions"}
+	if !d.executor.CommandExists(ctx, program) {
+		result.Status = facade.CheckWarn
+		result.Message = "skipped, " + program + " not available"
+		return result
+	}
+
+	tmuxSessions, err := d.tmuxService.ListSessions(ctx)
+	if err != nil {
+		result.Status = facade.CheckWarn
+		result.Message = fmt.Sprintf("failed to list tmux sessions: %v", err)
+		return result
+	}
+
+	known := make(map[string]bool, len(sessions))
+	for _, s := range sessions {
+		known[tmux.SessionName(s.ID)] = true
+	}
+
+	var orphaned []string
+	for _, ts := range tmuxSessions {
+		if !strings.HasPrefix(ts.Name, tmux.SessionName("")) || known[ts.Name] {
+			continue
+		}
+		if d.keepTmux != nil && d.keepTmux(ts.Name) {
+			continue
+		}
+		orphaned = append(orphaned, ts.Name)
+	}
+
+	if len(orphaned) == 0 {
+		result.Message = "no orphaned sessions"
+		return result
+	}
+	result.Status = facade.CheckWarn
+	result.Message = fmt.Sprintf("%d tmux session(s) not owned by any session: %s",
+		len(orphaned), strings.Join(orphaned, ", "))
+	result.Hint = "remove them with `cs gc --delete`"
+	return result
+}
+
+func (d *diagnosticsAdapter) checkStaleWorktrees(ctx context.Context, sessions []*types.Session) facade.CheckResult {
+	result := facade.CheckResult{Name: "worktrees"}
+
+	known := make(map[string]bool, len(sessions))
+	repos := make(map[string]bool)
+	var stale []string
+	for _, s := range sessions {
+		known[s.Path] = true
+		if repo := sessionRepo(s); repo != "" {
+			repos[repo] = true
+		}
+		// Paused sessions have their worktree removed on purpose
+		if s.Status == types.StatusPaused {
+			continue
+		}
+		if _, err := os.Stat(s.Path); os.IsNotExist(err) {
+			stale = append(stale, fmt.Sprintf("%s (session '%s', missing)", s.Path, s.Title))
+		}
+	}
+
+	for repo := range repos {
+		worktrees, err := d.gitService.ListWorktrees(ctx, repo)
+		if err != nil {
+			continue
+		}
+		for _, wt := range worktrees {
+			if !isSessionWorktree(d.layout, repo, wt.Path) || known[wt.Path] {
+				continue
+			}
+			stale = append(stale, fmt.Sprintf("%s (no session)", wt.Path))
+		}
+	}
+
+	if len(stale) == 0 {
+		result.Message = "no stale worktrees"
+		return result
+	}
+	result.Status = facade.CheckWarn
+	result.Message = fmt.Sprintf("%d stale worktree(s):\n    %s", len(stale), strings.Join(stale, "\n    "))
+	result.Hint = "remove them with `cs gc --delete`"
+	return result
+}
+
+// checkOverlaps reports sessions sharing a branch or a worktree, whose agents overwrite
+// each other's work
+func (d *diagnosticsAdapter) checkOverlaps(ctx context.Context, sessions []*types.Session) facade.CheckResult {
+	result := facade.CheckResult{Name: "branches"}
+	overlaps, err := d.orchestrator.FindOverlaps(ctx)
+	if err != nil {
+		result.Status = facade.CheckFail
+		result.Message = fmt.Sprintf("failed to find sessions sharing a branch: %v", err)
+		return result
+	}
+	if len(overlaps) == 0 {
+		result.Message = "no sessions share a branch or worktree"
+		return result
+	}
+
+	titles := make(map[string]string, len(sessions))
+	for _, s := range sessions {
+		titles[s.ID] = s.Title
+	}
+	lines := make([]string, len(overlaps))
+	for i, overlap := range overlaps {
+		names := make([]string, len(overlap.SessionIDs))
+		for j, id := range overlap.SessionIDs {
+			names[j] = "'" + titles[id] + "'"
+		}
+		if overlap.Path != "" {
+			lines[i] = fmt.Sprintf("worktree %s: %s", overlap.Path, strings.Join(names, ", "))
+		} else {
+			lines[i] = fmt.Sprintf("branch %s of %s: %s", overlap.Branch, overlap.RepoPath, strings.Join(names, ", "))
+		}
+	}
+	result.Status = facade.CheckWarn
+	result.Message = fmt.Sprintf("%d group(s) of sessions share a branch or worktree:\n    %s",
+		len(overlaps), strings.Join(lines, "\n    "))
+	result.Hint = "stop all but one of each, and start the others on their own branch with `cs new --fork-branch`"
+	return result
+}
+
+// programVersion runs program with versionFlag and returns the first line of its output
+func (d *diagnosticsAdapter) programVersion(ctx context.Context, program, versionFlag string) (string, error) {
+	res, err := d.executor.Execute(ctx, executor.Command{
+		Program: program,
+		Args:    []string{versionFlag},
+		Timeout: 5 * time.Second,
+	})
+	if err != nil {
+		return "", err
+	}
+	if res.ExitCode != 0 {
+		return "", fmt.Errorf("exit code %d: %s", res.ExitCode, strings.TrimSpace(string(res.Stderr)))
+	}
+	return strings.TrimSpace(strings.SplitN(string(res.Stdout), "\n", 2)[0]), nil
+}
+
+// isSessionWorktree reports whether a worktree of the repository at repo is one created for
+// a session, where layout puts them or next to the repository, where they went before a
+// layout was configured
+func isSessionWorktree(layout session.WorktreeLayout, repo, path string) bool {
+	return layout.IsSessionPath(repo, path) || session.WorktreeLayout{}.IsSessionPath(repo, path)
+}
+
+// worktreeRepo returns the repository a claude-squad worktree path was created from,
+// relying on the "<repo>-worktree-<id>" naming of the default layout, for sessions saved
+// before their RepoPath was recorded
+func worktreeRepo(path string) (string, bool) {
+	i := strings.LastIndex(path, "-worktree-")
+	if i <= 0 {
+		return "", false
+	}
+	return path[:i], true
+}
+
+// parseVersion extracts the first major.minor[.patch] version from s
+func parseVersion(s string) ([3]int, bool) {
+	var v [3]int
+	m := versionRegex.FindStringSubmatch(s)
+	if m == nil {
+		return v, false
+	}
+	for i := 0; i < 3; i++ {
+		if m[i+1] != "" {
+			v[i], _ = strconv.Atoi(m[i+1])
+		}
+	}
+	return v, true
+}
+
+func versionLess(a, b [3]int) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+	return false
+}
@@ -0,0 +1,97 @@
+package coreadapter
+
+import (
+	"context"
+	"testing"
+
+	"claude-squad/interface/facade"
+	"claude-squad/services/executor"
+	"claude-squad/services/git"
+	"claude-squad/services/session"
+	"claude-squad/services/tmux"
+	"claude-squad/services/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseVersion(t *testing.T) {
+	v, ok := parseVersion("git version 2.39.5")
+	require.True(t, ok)
+	assert.Equal(t, [3]int{2, 39, 5}, v)
+
+	v, ok = parseVersion("tmux 3.3a")
+	require.True(t, ok)
+	assert.Equal(t, [3]int{3, 3, 0}, v)
+
+	_, ok = parseVersion("unknown")
+	assert.False(t, ok)
+
+	assert.True(t, versionLess([3]int{2, 16, 9}, minMoveGitVersion))
+	assert.False(t, versionLess([3]int{2, 17, 0}, minMoveGitVersion))
+}
+
+func TestWorktreeRepo(t *testing.T) {
+	repo, ok := worktreeRepo("/src/app-worktree-fix-1700000000")
+	require.True(t, ok)
+	assert.Equal(t, "/src/app", repo)
+
+	_, ok = worktreeRepo("/src/app")
+	assert.False(t, ok)
+}
+
+func TestCheckOrphanedTmuxSessions(t *testing.T) {
+	d := &diagnosticsAdapter{
+		executor: &executor.MockExecutor{
+			CommandExistsFunc: func(ctx context.Context, program string) bool { return true },
+		},
+		tmuxService: &tmux.MockTmuxService{
+			ListSessionsFunc: func(ctx context.Context) ([]*tmux.Session, error) {
+				return []*tmux.Session{
+					{Name: "claudesquad_fix-1"},
+					{Name: "claudesquad_leftover"},
+					{Name: "claudesquad_other-repo-1"},
+					{Name: "personal"},
+				}, nil
+			},
+		},
+		// Sessions of other repositories aren't listed by the scoped orchestrator
+		keepTmux: func(name string) bool { return name == "claudesquad_other-repo-1" },
+	}
+
+	result := d.checkOrphanedTmuxSessions(context.Background(), []*types.Session{{ID: "fix-1"}})
+	assert.Equal(t, facade.CheckWarn, result.Status)
+	assert.Contains(t, result.Message, "1 tmux session(s)")
+	assert.Contains(t, result.Message, "claudesquad_leftover")
+	assert.NotContains(t, result.Message, "claudesquad_fix-1")
+	assert.NotContains(t, result.Message, "claudesquad_other-repo-1")
+	assert.NotContains(t, result.Message, "personal")
+}
+
+func TestCheckStaleWorktrees(t *testing.T) {
+	repo := t.TempDir()
+	layout := session.WorktreeLayout{Dir: t.TempDir(), Name: "{repo}-{id}"}
+	d := &diagnosticsAdapter{
+		layout: layout,
+		gitService: &git.MockGitService{
+			ListWorktreesFunc: func(ctx context.Context, repoPath string) ([]*git.Worktree, error) {
+				return []*git.Worktree{
+					{Path: repoPath},
+					{Path: repoPath + "-worktree-fix-1"},
+					{Path: repoPath + "-worktree-abandoned-2"},
+					{Path: layout.Path(repoPath, "main", "kept-4")},
+					{Path: layout.Path(repoPath, "main", "abandoned-5")},
+				}, nil
+			},
+		},
+	}
+
+	result := d.checkStaleWorktrees(context.Background(), []*types.Session{
+		{ID: "fix-1", Title: "fix", Path: repo + "-worktree-fix-1", RepoPath: repo, Status: types.StatusPaused},
+		{ID: "kept-4", Title: "kept", Path: layout.Path(repo, "main", "kept-4"), RepoPath: repo, Status: types.StatusPaused},
+	})
+	assert.Equal(t, facade.CheckWarn, result.Status)
+	assert.Contains(t, result.Message, "2 stale worktree(s)")
+	assert.Contains(t, result.Message, repo+"-worktree-abandoned-2 (no session)")
+	assert.Contains(t, result.Message, layout.Path(repo, "main", "abandoned-5")+" (no session)")
+}
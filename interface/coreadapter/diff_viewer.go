@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"path/filepath"
+	"time"
 
 	"claude-squad/interface/facade"
 	"claude-squad/services/git"
@@ -30,31 +31,224 @@ func (d *diffViewerAdapter) GetDiffStats(ctx context.Context, sessionID string)
 		return nil, err
 	}
 
-	// Get diff from git service
-	diff, err := d.gitService.GetDiff(ctx, sess.Path)
+	// Listing the changed files first also brings untracked files into the numstat below
+	changed, err := d.gitService.GetChangedFiles(ctx, sess.Path)
+	if err != nil {
+		return nil, err
+	}
+	diff, err := d.gitService.GetDiffStats(ctx, sess.Path)
+	if err != nil {
+		return nil, err
+	}
+	// Files the repository's diff rules exclude are listed apart and not counted
+	rules, err := git.LoadDiffRules(sess.Path)
 	if err != nil {
 		return nil, err
 	}
 
-	// Parse diff for stats (simplified - real implementation would parse properly)
-	stats := &facade.DiffStats{
-		Added:   0,
-		Removed: 0,
-		Content: diff,
+	counts := make(map[string]git.FileDiff, len(diff.Files))
+	for _, file := range diff.Files {
+		counts[file.Path] = file
 	}
 
-	// Count lines in diff (very simplified)
-	for _, line := range []byte(diff) {
-		if line == '+' {
-			stats.Added++
-		} else if line == '-' {
-			stats.Removed++
+	kept := rules.FilterStats(diff)
+	stats := &facade.DiffStats{
+		Added:   kept.Insertions,
+		Removed: kept.Deletions,
+		Files:   make([]facade.FileDiffStat, 0, len(changed)),
+	}
+	for _, file := range changed {
+		count := counts[file.Path]
+		stat := facade.FileDiffStat{
+			Path:    file.Path,
+			Status:  file.Status,
+			Added:   count.Insertions,
+			Removed: count.Deletions,
+			Binary:  count.Binary,
+			LFS:     count.LFS,
+		}
+		if rules.Excluded(file.Path) {
+			stats.Excluded = append(stats.Excluded, stat)
+		} else {
+			stats.Files = append(stats.Files, stat)
 		}
 	}
 
 	return stats, nil
 }
 
+func (d *diffViewerAdapter) GetDiffPatch(ctx context.Context, sessionID string) (string, error) {
+	sess, err := d.orchestrator.GetSession(ctx, sessionID)
+	if err != nil {
+		return "", err
+	}
+	// An archived session's worktree is gone; show the changes it had when archived
+	if sess.Archived {
+		return sess.DiffSnapshot, nil
+	}
+	patch, err := d.gitService.GetDiffPatch(ctx, sess.Path)
+	if err != nil {
+		return "", err
+	}
+	rules, err := git.LoadDiffRules(sess.Path)
+	if err != nil {
+		return "", err
+	}
+	patch, _ = rules.FilterPatch(patch)
+	return patch, nil
+}
+
+func (d *diffViewerAdapter) GetFileStatus(ctx context.Context, sessionID string) ([]facade.FileStatus, error) {
+	sess, err := d.orchestrator.GetSession(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := d.gitService.GetStatus(ctx, sess.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]facade.FileStatus, 0, len(status))
+	for _, file := range status {
+		info := facade.FileStatus{
+			Path:       file.Path,
+			OrigPath:   file.OrigPath,
+			Conflicted: file.IsConflicted(),
+			// A file marked intent-to-add is in the index with none of its content
+			Untracked: file.IsUntracked() || (file.Staged == ' ' && file.Unstaged == 'A'),
+		}
+		if file.IsStaged() {
+			info.Staged = fileChange(file.Staged)
+		}
+		if file.IsUnstaged() && !info.Untracked {
+			info.Unstaged = fileChange(file.Unstaged)
+		}
+		files = append(files, info)
+	}
+	return files, nil
+}
+
+func (d *diffViewerAdapter) GetSubmoduleStatus(ctx context.Context, sessionID string) ([]facade.SubmoduleStatus, error) {
+	sess, err := d.orchestrator.GetSession(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	submodules, err := d.gitService.GetSubmoduleStatus(ctx, sess.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]facade.SubmoduleStatus, len(submodules))
+	for i, submodule := range submodules {
+		state := "current"
+		switch {
+		case submodule.IsConflicted():
+			state = "conflicted"
+		case !submodule.IsInitialized():
+			state = "uninitialized"
+		case submodule.IsModified():
+			state = "modified"
+		}
+		result[i] = facade.SubmoduleStatus{
+			Path:     submodule.Path,
+			Commit:   submodule.Hash,
+			Describe: submodule.Describe,
+			State:    state,
+		}
+	}
+	return result, nil
+}
+
+func (d *diffViewerAdapter) GetFileHistory(ctx context.Context, sessionID, path string, limit int) ([]facade.CommitInfo, error) {
+	sess, err := d.orchestrator.GetSession(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	commits, err := d.gitService.GetFileHistory(ctx, sess.Path, path, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]facade.CommitInfo, len(commits))
+	for i, commit := range commits {
+		result[i] = facade.CommitInfo{
+			Hash:    commit.Hash,
+			Author:  commit.Author,
+			Message: commit.Message,
+			Time:    commit.Timestamp,
+		}
+	}
+	return result, nil
+}
+
+func (d *diffViewerAdapter) Blame(ctx context.Context, sessionID, path string) ([]facade.BlameLine, error) {
+	sess, err := d.orchestrator.GetSession(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	lines, err := d.gitService.Blame(ctx, sess.Path, path)
+	if err != nil {
+		return nil, err
+	}
+
+	// Lines share the commits that changed them, so each is converted once
+	commits := make(map[string]*facade.CommitInfo)
+	result := make([]facade.BlameLine, len(lines))
+	for i, line := range lines {
+		result[i] = facade.BlameLine{Line: line.Line, Content: line.Content}
+		if !line.IsCommitted() {
+			continue
+		}
+		commit, ok := commits[line.Hash]
+		if !ok {
+			commit = &facade.CommitInfo{
+				Hash:    line.Hash,
+				Author:  line.Author,
+				Message: line.Summary,
+				Time:    line.Timestamp,
+			}
+			commits[line.Hash] = commit
+		}
+		result[i].Commit = commit
+	}
+	return result, nil
+}
+
+func (d *diffViewerAdapter) GetDiffHistory(ctx context.Context, sessionID string, since time.Time) ([]facade.DiffSample, error) {
+	history, err := d.orchestrator.GetDiffHistory(ctx, sessionID, since)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]facade.DiffSample, len(history))
+	for i, sample := range history {
+		result[i] = facade.DiffSample(sample)
+	}
+	return result, nil
+}
+
+// fileChange names a git status code
+func fileChange(code byte) string {
+	switch code {
+	case 'A':
+		return "added"
+	case 'D':
+		return "deleted"
+	case 'R':
+		return "renamed"
+	case 'C':
+		return "copied"
+	case 'T':
+		return "typechange"
+	default:
+		return "modified"
+	}
+}
+
 func (d *diffViewerAdapter) UpdateDiffStats(ctx context.Context, sessionID string) error {
 	// In the real implementation, this might trigger a cache refresh
 	// For now, just validate the session exists
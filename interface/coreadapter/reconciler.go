@@ -0,0 +1,187 @@
+package coreadapter
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"claude-squad/interface/facade"
+	"claude-squad/services/executor"
+	"claude-squad/services/git"
+	"claude-squad/services/session"
+	"claude-squad/services/tmux"
+	"claude-squad/services/types"
+)
+
+// reconcilerAdapter finds resources that are out of sync between storage, tmux and git
+type reconcilerAdapter struct {
+	executor     executor.CommandExecutor
+	gitService   git.GitService
+	tmuxService  tmux.TmuxService
+	orchestrator session.SessionOrchestrator
+	layout       session.WorktreeLayout
+	// keepTmux reports tmux sessions owned by something other than the orchestrator, such
+	// as the TUI, which share the claude-squad prefix. May be nil.
+	keepTmux func(name string) bool
+}
+
+// NewResourceReconciler creates a new ResourceReconciler facade
+func NewResourceReconciler(
+	executor executor.CommandExecutor,
+	gitService git.GitService,
+	tmuxService tmux.TmuxService,
+	orchestrator session.SessionOrchestrator,
+	layout session.WorktreeLayout,
+	keepTmux func(name string) bool,
+) facade.ResourceReconciler {
+	return &reconcilerAdapter{
+		executor:     executor,
+		gitService:   gitService,
+		tmuxService:  tmuxService,
+		orchestrator: orchestrator,
+		layout:       layout,
+		keepTmux:     keepTmux,
+	}
+}
+
+func (r *reconcilerAdapter) FindOrphans(ctx context.Context) ([]facade.Orphan, error) {
+	sessions, err := r.orchestrator.ListSessions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load sessions: %w", err)
+	}
+
+	// Without tmux there is nothing to compare against, so skip the tmux checks rather
+	// than reporting every session as having lost its tmux session
+	var liveTmux map[string]bool
+	if r.executor.CommandExists(ctx, tmux.ProgramOf(r.tmuxService)) {
+		tmuxSessions, err := r.tmuxService.ListSessions(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list tmux sessions: %w", err)
+		}
+		liveTmux = make(map[string]bool, len(tmuxSessions))
+		for _, ts := range tmuxSessions {
+			liveTmux[ts.Name] = true
+		}
+	}
+
+	var orphans []facade.Orphan
+	orphans = append(orphans, r.orphanedTmuxSessions(sessions, liveTmux)...)
+	orphans = append(orphans, r.orphanedWorktrees(ctx, sessions)...)
+
+	for _, s := range sessions {
+		// Paused sessions have neither a worktree nor a tmux session on purpose
+		if s.Status == types.StatusPaused {
+			continue
+		}
+		orphan := facade.Orphan{Resource: s.Title, SessionID: s.ID, Repo: sessionRepo(s)}
+		if _, err := os.Stat(s.Path); os.IsNotExist(err) {
+			orphan.Kind = facade.OrphanSessionWorktree
+			orphan.Removable = true
+			orphans = append(orphans, orphan)
+		} else if liveTmux != nil && !liveTmux[tmux.SessionName(s.ID)] {
+			orphan.Kind = facade.OrphanSessionTmux
+			orphans = append(orphans, orphan)
+		}
+	}
+
+	return orphans, nil
+}
+
+// orphanedTmuxSessions returns the claude-squad tmux sessions no stored session owns
+func (r *reconcilerAdapter) orphanedTmuxSessions(sessions []*types.Session, liveTmux map[string]bool) []facade.Orphan {
+	known := make(map[string]bool, len(sessions))
+	for _, s := range sessions {
+		known[tmux.SessionName(s.ID)] = true
+	}
+
+	var names []string
+	for name := range liveTmux {
+		if !strings.HasPrefix(name, tmux.SessionName("")) || known[name] {
+			continue
+		}
+		if r.keepTmux != nil && r.keepTmux(name) {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	orphans := make([]facade.Orphan, 0, len(names))
+	for _, name := range names {
+		orphans = append(orphans, facade.Orphan{Kind: facade.OrphanTmuxSession, Resource: name, Removable: true})
+	}
+	return orphans
+}
+
+// orphanedWorktrees returns session worktrees in the sessions' repositories that no
+// stored session points at
+func (r *reconcilerAdapter) orphanedWorktrees(ctx context.Context, sessions []*types.Session) []facade.Orphan {
+	known := make(map[string]bool, len(sessions))
+	repoSet := make(map[string]bool)
+	for _, s := range sessions {
+		known[s.Path] = true
+		if repo := sessionRepo(s); repo != "" {
+			repoSet[repo] = true
+		}
+	}
+	repos := make([]string, 0, len(repoSet))
+	for repo := range repoSet {
+		repos = append(repos, repo)
+	}
+	sort.Strings(repos)
+
+	var orphans []facade.Orphan
+	for _, repo := range repos {
+		worktrees, err := r.gitService.ListWorktrees(ctx, repo)
+		if err != nil {
+			continue
+		}
+		for _, wt := range worktrees {
+			if !isSessionWorktree(r.layout, repo, wt.Path) || known[wt.Path] {
+				continue
+			}
+			orphans = append(orphans, facade.Orphan{Kind: facade.OrphanWorktree, Resource: wt.Path, Repo: repo, Removable: true})
+		}
+	}
+	return orphans
+}
+
+func (r *reconcilerAdapter) RemoveOrphan(ctx context.Context, orphan facade.Orphan) error {
+	if !orphan.Removable {
+		return fmt.Errorf("%s orphans are not removed automatically", orphan.Kind)
+	}
+
+	switch orphan.Kind {
+	case facade.OrphanTmuxSession:
+		// KillSession adds the prefix itself
+		return r.tmuxService.KillSession(ctx, strings.TrimPrefix(orphan.Resource, tmux.SessionName("")))
+	case facade.OrphanWorktree:
+		if err := r.gitService.RemoveWorktree(ctx, orphan.Resource, true); err != nil {
+			return err
+		}
+		return r.gitService.PruneWorktrees(ctx, orphan.Repo)
+	case facade.OrphanSessionWorktree:
+		if err := r.orchestrator.StopSession(ctx, orphan.SessionID); err != nil {
+			return err
+		}
+		// Drop git's record of the worktree that disappeared
+		if orphan.Repo != "" {
+			return r.gitService.PruneWorktrees(ctx, orphan.Repo)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown orphan kind '%s'", orphan.Kind)
+	}
+}
+
+// sessionRepo returns the repository a session's worktree was created from, or "" if
+// it can't be determined
+func sessionRepo(s *types.Session) string {
+	if s.RepoPath != "" {
+		return s.RepoPath
+	}
+	repo, _ := worktreeRepo(s.Path)
+	return repo
+}
@@ -0,0 +1,83 @@
+package coreadapter
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"claude-squad/interface/facade"
+	"claude-squad/services/executor"
+	"claude-squad/services/git"
+	"claude-squad/services/session"
+	"claude-squad/services/storage"
+	"claude-squad/services/tmux"
+	"claude-squad/services/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindOrphans(t *testing.T) {
+	ctx := context.Background()
+	repo := t.TempDir()
+	layout := session.WorktreeLayout{Dir: t.TempDir(), Name: "{repo}/{branch}"}
+	custom := filepath.Join(layout.Dir, filepath.Base(repo))
+
+	store, err := storage.NewJSONRepository(t.TempDir())
+	require.NoError(t, err)
+	for _, s := range []*types.SessionData{
+		{ID: "healthy-1", Title: "healthy", Path: repo, RepoPath: repo, Status: types.StatusRunning},
+		{ID: "lost-2", Title: "lost", Path: filepath.Join(repo, "missing"), RepoPath: repo, Status: types.StatusReady},
+		{ID: "detached-3", Title: "detached", Path: repo, RepoPath: repo, Status: types.StatusReady},
+		{ID: "paused-4", Title: "paused", Path: filepath.Join(repo, "gone"), RepoPath: repo, Status: types.StatusPaused},
+	} {
+		require.NoError(t, store.Create(ctx, s))
+	}
+
+	gitMock := git.NewMockGitService()
+	gitMock.ListWorktreesFunc = func(ctx context.Context, repoPath string) ([]*git.Worktree, error) {
+		return []*git.Worktree{
+			{Path: repoPath},
+			{Path: repoPath + "-worktree-abandoned-1"},
+			{Path: filepath.Join(custom, "feature", "abandoned")},
+			{Path: filepath.Join(layout.Dir, "elsewhere")},
+		}, nil
+	}
+	tmuxMock := &tmux.MockTmuxService{
+		ListSessionsFunc: func(ctx context.Context) ([]*tmux.Session, error) {
+			return []*tmux.Session{
+				{Name: "claudesquad_healthy-1"},
+				{Name: "claudesquad_lost-2"},
+				{Name: "claudesquad_leftover"},
+				{Name: "claudesquad_tui"},
+				{Name: "personal"},
+			}, nil
+		},
+	}
+	exec := &executor.MockExecutor{
+		CommandExistsFunc: func(ctx context.Context, program string) bool { return true },
+	}
+	orch := session.NewOrchestrator(gitMock, tmuxMock, store, exec)
+
+	r := NewResourceReconciler(exec, gitMock, tmuxMock, orch, layout, func(name string) bool { return name == "claudesquad_tui" })
+	orphans, err := r.FindOrphans(ctx)
+	require.NoError(t, err)
+
+	assert.Equal(t, []facade.Orphan{
+		{Kind: facade.OrphanTmuxSession, Resource: "claudesquad_leftover", Removable: true},
+		{Kind: facade.OrphanWorktree, Resource: repo + "-worktree-abandoned-1", Repo: repo, Removable: true},
+		{Kind: facade.OrphanWorktree, Resource: filepath.Join(custom, "feature", "abandoned"), Repo: repo, Removable: true},
+		{Kind: facade.OrphanSessionWorktree, Resource: "lost", SessionID: "lost-2", Repo: repo, Removable: true},
+		{Kind: facade.OrphanSessionTmux, Resource: "detached", SessionID: "detached-3", Repo: repo},
+	}, orphans)
+
+	var killed string
+	tmuxMock.KillSessionFunc = func(ctx context.Context, name string) error {
+		killed = name
+		return nil
+	}
+	require.NoError(t, r.RemoveOrphan(ctx, orphans[0]))
+	assert.Equal(t, "leftover", killed)
+
+	assert.Error(t, r.RemoveOrphan(ctx, orphans[4]))
+}
@@ -2,9 +2,12 @@ package coreadapter
 
 import (
 	"context"
+	"fmt"
+	"io"
 	"strings"
 
 	"claude-squad/interface/facade"
+	"claude-squad/services/executor"
 	"claude-squad/services/session"
 )
 
@@ -29,7 +32,30 @@ func (s *sessionInteractorAdapter) SendKeys(ctx context.Context, id string, keys
 }
 
 func (s *sessionInteractorAdapter) SendPrompt(ctx context.Context, id string, prompt string) error {
-	return s.orchestrator.SendInput(ctx, id, prompt+"\n")
+	return s.orchestrator.SendPrompt(ctx, id, prompt)
+}
+
+func (s *sessionInteractorAdapter) Exec(ctx context.Context, id string, opts facade.ExecOptions) (int, error) {
+	result, err := s.orchestrator.ExecInWorktree(ctx, id, executor.Command{
+		Program: opts.Program,
+		Args:    opts.Args,
+		Shell:   opts.Shell,
+		Stdin:   opts.Stdin,
+		Stdout:  opts.Stdout,
+		Stderr:  opts.Stderr,
+	})
+	if err != nil {
+		return -1, err
+	}
+	// A process killed by a signal has no exit status but still failed
+	if result.Error != nil && result.ExitCode == 0 {
+		return -1, fmt.Errorf("command failed: %w", result.Error)
+	}
+	return result.ExitCode, nil
+}
+
+func (s *sessionInteractorAdapter) Replay(ctx context.Context, id string, n int, stdout, stderr io.Writer) error {
+	return s.orchestrator.Replay(ctx, id, n, stdout, stderr)
 }
 
 func (s *sessionInteractorAdapter) HasPrompt(ctx context.Context, id string) (bool, error) {
@@ -2,9 +2,13 @@ package coreadapter
 
 import (
 	"context"
-	"path/filepath"
+	"errors"
+	"io"
+	"time"
 
 	"claude-squad/interface/facade"
+	"claude-squad/services/forge"
+	"claude-squad/services/git"
 	"claude-squad/services/session"
 	"claude-squad/services/types"
 )
@@ -34,16 +38,52 @@ func (s *sessionManagerAdapter) ListSessions(ctx context.Context) ([]facade.Sess
 	return result, nil
 }
 
-func (s *sessionManagerAdapter) CreateSession(ctx context.Context, title, path, program string) (*facade.SessionInfo, error) {
+func (s *sessionManagerAdapter) SearchSessions(ctx context.Context, query string) ([]facade.SessionInfo, error) {
+	sessions, err := s.orchestrator.SearchSessions(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]facade.SessionInfo, len(sessions))
+	for i, sess := range sessions {
+		result[i] = toFacadeInfo(sess)
+	}
+	return result, nil
+}
+
+func (s *sessionManagerAdapter) CreateSession(ctx context.Context, opts facade.CreateSessionOptions) (*facade.SessionInfo, error) {
 	req := types.CreateSessionRequest{
-		Title:   title,
-		Path:    path,
-		Program: program,
+		Title:   opts.Title,
+		Path:    opts.Path,
+		Branch:  opts.Branch,
+		Program: opts.Program,
+		Prompt:  opts.Prompt,
+		AutoYes: opts.AutoYes,
 		Height:  24,
 		Width:   80,
+
+		Submodules: opts.Submodules,
+		ForkBranch: opts.ForkBranch,
+		InPlace:    opts.InPlace,
+		Stash:      opts.Stash,
 	}
 
 	sess, err := s.orchestrator.CreateSession(ctx, req)
+	if err != nil {
+		return nil, toFacadeError(err)
+	}
+
+	info := toFacadeInfo(sess)
+	return &info, nil
+}
+
+func (s *sessionManagerAdapter) CloneSession(ctx context.Context, id string, opts facade.CloneSessionOptions) (*facade.SessionInfo, error) {
+	sess, err := s.orchestrator.CloneSession(ctx, types.CloneSessionRequest{
+		SourceID:         id,
+		Title:            opts.Title,
+		Branch:           opts.Branch,
+		FromSourceBranch: opts.FromSourceBranch,
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -53,7 +93,7 @@ func (s *sessionManagerAdapter) CreateSession(ctx context.Context, title, path,
 }
 
 func (s *sessionManagerAdapter) StartSession(ctx context.Context, id string) error {
-	return s.orchestrator.StartSession(ctx, id)
+	return toFacadeError(s.orchestrator.StartSession(ctx, id))
 }
 
 func (s *sessionManagerAdapter) StopSession(ctx context.Context, id string) error {
@@ -65,7 +105,15 @@ func (s *sessionManagerAdapter) PauseSession(ctx context.Context, id string) err
 }
 
 func (s *sessionManagerAdapter) ResumeSession(ctx context.Context, id string) error {
-	return s.orchestrator.ResumeSession(ctx, id)
+	return toFacadeError(s.orchestrator.ResumeSession(ctx, id))
+}
+
+func (s *sessionManagerAdapter) ArchiveSession(ctx context.Context, id string) error {
+	return s.orchestrator.ArchiveSession(ctx, id)
+}
+
+func (s *sessionManagerAdapter) UnarchiveSession(ctx context.Context, id string) error {
+	return s.orchestrator.UnarchiveSession(ctx, id)
 }
 
 func (s *sessionManagerAdapter) GetSession(ctx context.Context, id string) (*facade.SessionInfo, error) {
@@ -86,7 +134,288 @@ func (s *sessionManagerAdapter) UpdateTitle(ctx context.Context, id string, titl
 	return s.orchestrator.UpdateSessionStatus(ctx, id, sess.Status)
 }
 
+func (s *sessionManagerAdapter) RenameSession(ctx context.Context, id string, title string, renameBranch bool) (*facade.SessionInfo, error) {
+	sess, err := s.orchestrator.RenameSession(ctx, id, title, renameBranch)
+	if err != nil {
+		return nil, err
+	}
+
+	info := toFacadeInfo(sess)
+	return &info, nil
+}
+
+func (s *sessionManagerAdapter) ExportSessions(ctx context.Context, ids []string, w io.Writer, includeBundles bool) error {
+	return s.orchestrator.ExportSessions(ctx, ids, w, includeBundles)
+}
+
+func (s *sessionManagerAdapter) ImportSessions(ctx context.Context, r io.Reader, repoPath string) ([]facade.SessionInfo, error) {
+	sessions, err := s.orchestrator.ImportSessions(ctx, r, repoPath)
+	result := make([]facade.SessionInfo, len(sessions))
+	for i, sess := range sessions {
+		result[i] = toFacadeInfo(sess)
+	}
+	return result, err
+}
+
+func (s *sessionManagerAdapter) CommitSession(ctx context.Context, id string, opts facade.CommitOptions) (*facade.CommitInfo, error) {
+	commit, err := s.orchestrator.CommitSession(ctx, id, git.CommitOptions{
+		Message:  opts.Message,
+		Amend:    opts.Amend,
+		StageAll: opts.StageAll,
+		Paths:    opts.Paths,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &facade.CommitInfo{
+		Hash:    commit.Hash,
+		Author:  commit.Author,
+		Message: commit.Message,
+		Time:    commit.Timestamp,
+	}, nil
+}
+
+func (s *sessionManagerAdapter) SetCommitSettings(ctx context.Context, id string, settings *facade.CommitSettings) error {
+	return s.orchestrator.SetCommitSettings(ctx, id, (*types.CommitSettings)(settings))
+}
+
+func (s *sessionManagerAdapter) FinishSession(ctx context.Context, id string, opts facade.FinishOptions) (*facade.FinishResult, error) {
+	result, err := s.orchestrator.FinishSession(ctx, id, types.FinishSessionRequest{
+		Base:         opts.Base,
+		Strategy:     types.MergeStrategy(opts.Strategy),
+		Message:      opts.Message,
+		DeleteBranch: opts.DeleteBranch,
+		Tag:          opts.Tag,
+		TagName:      opts.TagName,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &facade.FinishResult{
+		Branch:        result.Branch,
+		Base:          result.Base,
+		Strategy:      string(result.Strategy),
+		Head:          result.Head,
+		BranchDeleted: result.BranchDeleted,
+		Tag:           result.Tag,
+	}, nil
+}
+
+func (s *sessionManagerAdapter) TransplantSession(ctx context.Context, id string, opts facade.TransplantOptions) (*facade.TransplantResult, error) {
+	result, err := s.orchestrator.TransplantSession(ctx, id, types.TransplantRequest{
+		TargetID:    opts.Target,
+		Commits:     opts.Commits,
+		Uncommitted: opts.Uncommitted,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &facade.TransplantResult{
+		Path:    result.Path,
+		Branch:  result.Branch,
+		Commits: result.Commits,
+		Patched: result.Patched,
+	}, nil
+}
+
+func (s *sessionManagerAdapter) GetTracking(ctx context.Context, id string, base string) (*facade.BranchTracking, error) {
+	tracking, err := s.orchestrator.GetTracking(ctx, id, base)
+	if err != nil {
+		return nil, err
+	}
+	return toFacadeTracking(tracking), nil
+}
+
+func (s *sessionManagerAdapter) CheckConflicts(ctx context.Context, id string, base string) ([]facade.FileConflict, error) {
+	conflicts, err := s.orchestrator.CheckConflicts(ctx, id, base)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]facade.FileConflict, len(conflicts))
+	for i, conflict := range conflicts {
+		result[i] = facade.FileConflict{Path: conflict.Path}
+		for _, hunk := range conflict.Hunks {
+			result[i].Hunks = append(result[i].Hunks, facade.ConflictHunk{
+				Line:   hunk.Line,
+				Ours:   hunk.Ours,
+				Theirs: hunk.Theirs,
+			})
+		}
+	}
+	return result, nil
+}
+
+func (s *sessionManagerAdapter) RebaseSession(ctx context.Context, id string, opts facade.RebaseOptions) (*facade.RebaseResult, error) {
+	result, err := s.orchestrator.RebaseSession(ctx, id, types.RebaseRequest{
+		Onto:     opts.Onto,
+		Remote:   opts.Remote,
+		Continue: opts.Continue,
+		Abort:    opts.Abort,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &facade.RebaseResult{
+		Branch:    result.Branch,
+		Onto:      result.Onto,
+		Status:    string(result.Status),
+		Head:      result.Head,
+		Conflicts: result.Conflicts,
+	}, nil
+}
+
+func (s *sessionManagerAdapter) PushSession(ctx context.Context, id string, opts facade.PushOptions) (*facade.PushResult, error) {
+	result, err := s.orchestrator.PushSession(ctx, id, git.PushOptions{
+		Remote: opts.Remote,
+		Force:  opts.Force,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &facade.PushResult{
+		Branch:    result.Branch,
+		Remote:    result.Remote,
+		RemoteURL: result.RemoteURL,
+		BranchURL: result.BranchURL,
+	}, nil
+}
+
+func (s *sessionManagerAdapter) CreatePullRequest(ctx context.Context, id string, opts facade.PullRequestOptions) (*facade.PullRequest, error) {
+	pr, err := s.orchestrator.CreatePullRequest(ctx, id, types.PullRequestRequest{
+		Remote: opts.Remote,
+		Base:   opts.Base,
+		Title:  opts.Title,
+		Body:   opts.Body,
+		Draft:  opts.Draft,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &facade.PullRequest{
+		URL:    pr.URL,
+		Title:  pr.Title,
+		Branch: pr.Branch,
+		Base:   pr.Base,
+	}, nil
+}
+
+func (s *sessionManagerAdapter) GetPullRequestStatus(ctx context.Context, id string) (*facade.PullRequestStatus, error) {
+	pr, err := s.orchestrator.GetPullRequest(ctx, id)
+	if errors.Is(err, forge.ErrNoPullRequest) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	checks, err := s.orchestrator.ListChecks(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &facade.PullRequestStatus{
+		Number:         pr.Number,
+		URL:            pr.URL,
+		Title:          pr.Title,
+		Branch:         pr.Head,
+		Base:           pr.Base,
+		State:          string(pr.State),
+		Draft:          pr.Draft,
+		ReviewDecision: pr.ReviewDecision,
+		Mergeable:      pr.Mergeable,
+		Checks:         make([]facade.Check, len(checks)),
+	}
+	for i, check := range checks {
+		status.Checks[i] = facade.Check{
+			Name:     check.Name,
+			State:    string(check.State),
+			Workflow: check.Workflow,
+			URL:      check.URL,
+		}
+	}
+	return status, nil
+}
+
+func (s *sessionManagerAdapter) PruneSessions(ctx context.Context, opts facade.PruneOptions) ([]facade.SessionInfo, error) {
+	req := types.PruneRequest{
+		OlderThan: opts.OlderThan,
+		DryRun:    opts.DryRun,
+	}
+	if opts.Status != nil {
+		status := types.Status(*opts.Status)
+		req.Status = &status
+	}
+
+	sessions, err := s.orchestrator.PruneSessions(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]facade.SessionInfo, len(sessions))
+	for i, sess := range sessions {
+		result[i] = toFacadeInfo(sess)
+	}
+	return result, nil
+}
+
+func (s *sessionManagerAdapter) MigrateWorktrees(ctx context.Context, dryRun bool) ([]facade.WorktreeMove, error) {
+	moves, err := s.orchestrator.MigrateWorktrees(ctx, dryRun)
+	result := make([]facade.WorktreeMove, len(moves))
+	for i, move := range moves {
+		result[i] = facade.WorktreeMove{
+			SessionID: move.SessionID,
+			Title:     move.Title,
+			From:      move.From,
+			To:        move.To,
+			Skipped:   move.Skipped,
+		}
+	}
+	return result, err
+}
+
+func (s *sessionManagerAdapter) ListTrash(ctx context.Context) ([]facade.SessionInfo, error) {
+	sessions, err := s.orchestrator.ListTrash(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]facade.SessionInfo, len(sessions))
+	for i, sess := range sessions {
+		result[i] = toFacadeInfo(sess)
+	}
+	return result, nil
+}
+
+func (s *sessionManagerAdapter) RestoreSession(ctx context.Context, id string) error {
+	return s.orchestrator.RestoreSession(ctx, id)
+}
+
+func (s *sessionManagerAdapter) PurgeTrash(ctx context.Context, olderThan time.Duration) ([]facade.SessionInfo, error) {
+	sessions, err := s.orchestrator.PurgeTrash(ctx, olderThan)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]facade.SessionInfo, len(sessions))
+	for i, sess := range sessions {
+		result[i] = toFacadeInfo(sess)
+	}
+	return result, nil
+}
+
+func toFacadeTracking(tracking *types.BranchTracking) *facade.BranchTracking {
+	if tracking == nil {
+		return nil
+	}
+	return &facade.BranchTracking{Base: tracking.Base, Ahead: tracking.Ahead, Behind: tracking.Behind}
+}
+
 // Helper to convert types.Session to facade.SessionInfo
+// toFacadeError converts the errors callers tell apart to their facade equivalents
+func toFacadeError(err error) error {
+	var inUse *session.BranchInUseError
+	if errors.As(err, &inUse) {
+		return &facade.BranchInUseError{Branch: inUse.Branch, Sessions: inUse.Sessions, Worktree: inUse.Worktree}
+	}
+	return err
+}
+
 func toFacadeInfo(sess *types.Session) facade.SessionInfo {
 	return facade.SessionInfo{
 		ID:      sess.ID,
@@ -96,5 +425,15 @@ func toFacadeInfo(sess *types.Session) facade.SessionInfo {
 		Status:  facade.SessionStatus(sess.Status),
 		Program: sess.Program,
 		AutoYes: sess.AutoYes,
+
+		Archived:   sess.Archived,
+		DeletedAt:  sess.DeletedAt,
+		Conflicted: sess.Conflicted,
+
+		CommitSettings: (*facade.CommitSettings)(sess.CommitSettings),
+		InPlace:        sess.InPlace != nil,
+
+		CreatedAt: sess.CreatedAt,
+		UpdatedAt: sess.UpdatedAt,
 	}
-}
\ No newline at end of file
+}
@@ -24,9 +24,7 @@ func (s *sessionViewerAdapter) GetPreview(ctx context.Context, id string) (strin
 }
 
 func (s *sessionViewerAdapter) GetFullHistory(ctx context.Context, id string) (string, error) {
-	// In a real implementation, this might use a different method or flag
-	// For now, just return the same as preview
-	return s.orchestrator.GetOutput(ctx, id)
+	return s.orchestrator.GetOutputHistory(ctx, id)
 }
 
 func (s *sessionViewerAdapter) HasUpdated(ctx context.Context, id string, lastPreview string) (bool, error) {
@@ -35,4 +33,37 @@ func (s *sessionViewerAdapter) HasUpdated(ctx context.Context, id string, lastPr
 		return false, err
 	}
 	return current != lastPreview, nil
-}
\ No newline at end of file
+}
+
+func (s *sessionViewerAdapter) GetInputHistory(ctx context.Context, id string) ([]facade.InputRecord, error) {
+	inputs, err := s.orchestrator.GetInputHistory(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	history := make([]facade.InputRecord, len(inputs))
+	for i, input := range inputs {
+		history[i] = facade.InputRecord{Time: input.Time, Kind: string(input.Kind), Text: input.Text}
+	}
+	return history, nil
+}
+
+func (s *sessionViewerAdapter) GetAuditLog(ctx context.Context, ref string) ([]facade.AuditEntry, error) {
+	entries, err := s.orchestrator.GetAuditLog(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	log := make([]facade.AuditEntry, len(entries))
+	for i, entry := range entries {
+		log[i] = facade.AuditEntry{
+			Time:      entry.Time,
+			Actor:     string(entry.Actor),
+			Op:        string(entry.Op),
+			SessionID: entry.SessionID,
+			Title:     entry.Title,
+			Detail:    entry.Detail,
+		}
+	}
+	return log, nil
+}
@@ -0,0 +1,177 @@
+package coreadapter
+
+import (
+	"context"
+	"time"
+
+	"claude-squad/interface/facade"
+	"claude-squad/services/session"
+	"claude-squad/services/storage"
+)
+
+// defaultWatchInterval is used when WatchOptions.Interval is not set
+const defaultWatchInterval = time.Second
+
+// sessionWatcherAdapter implements the SessionWatcher facade by polling the orchestrator
+// and reporting the differences between consecutive snapshots. Changes to the session
+// store trigger a snapshot straight away; polling still catches prompts and output,
+// which the store doesn't record.
+type sessionWatcherAdapter struct {
+	orchestrator session.SessionOrchestrator
+	interactor   facade.SessionInteractor
+	storage      storage.StorageRepository
+}
+
+// NewSessionWatcher creates a new SessionWatcher facade
+func NewSessionWatcher(orchestrator session.SessionOrchestrator, interactor facade.SessionInteractor, storage storage.StorageRepository) facade.SessionWatcher {
+	return &sessionWatcherAdapter{
+		orchestrator: orchestrator,
+		interactor:   interactor,
+		storage:      storage,
+	}
+}
+
+// watchState is what a watch remembers about a session between polls
+type watchState struct {
+	info    facade.SessionInfo
+	waiting bool
+	output  string
+}
+
+func (w *sessionWatcherAdapter) Watch(ctx context.Context, opts facade.WatchOptions) (<-chan facade.SessionEvent, error) {
+	interval := opts.Interval
+	if interval <= 0 {
+		interval = defaultWatchInterval
+	}
+
+	// Take the first snapshot up front so a broken setup fails immediately
+	prev, err := w.snapshot(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Without store notifications the watch falls back to polling alone
+	var changes <-chan storage.StorageEvent
+	if w.storage != nil {
+		if ch, err := w.storage.Watch(ctx); err == nil {
+			changes = ch
+		}
+	}
+
+	events := make(chan facade.SessionEvent)
+	go func() {
+		defer close(events)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			case _, ok := <-changes:
+				if !ok {
+					changes = nil
+				}
+			}
+
+			cur, err := w.snapshot(ctx, opts)
+			if err != nil {
+				// Storage can be mid-write by another process; try again next tick
+				continue
+			}
+			for _, event := range diffWatchStates(prev, cur, time.Now(), opts.IncludeOutput) {
+				select {
+				case events <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
+			prev = cur
+		}
+	}()
+
+	return events, nil
+}
+
+// snapshot collects the current state of the watched sessions
+func (w *sessionWatcherAdapter) snapshot(ctx context.Context, opts facade.WatchOptions) ([]watchState, error) {
+	sessions, err := w.orchestrator.ListSessions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	watched := make(map[string]bool, len(opts.SessionIDs))
+	for _, id := range opts.SessionIDs {
+		watched[id] = true
+	}
+
+	states := make([]watchState, 0, len(sessions))
+	for _, sess := range sessions {
+		if len(watched) > 0 && !watched[sess.ID] {
+			continue
+		}
+
+		state := watchState{info: toFacadeInfo(sess)}
+		// Paused sessions have no tmux pane to inspect
+		if state.info.Status != facade.StatusPaused {
+			if waiting, err := w.interactor.HasPrompt(ctx, sess.ID); err == nil {
+				state.waiting = waiting
+			}
+			if opts.IncludeOutput {
+				if output, err := w.orchestrator.GetOutput(ctx, sess.ID); err == nil {
+					state.output = output
+				}
+			}
+		}
+		states = append(states, state)
+	}
+	return states, nil
+}
+
+// diffWatchStates returns the events that turn prev into cur
+func diffWatchStates(prev, cur []watchState, now time.Time, includeOutput bool) []facade.SessionEvent {
+	before := make(map[string]watchState, len(prev))
+	for _, state := range prev {
+		before[state.info.ID] = state
+	}
+
+	var events []facade.SessionEvent
+	seen := make(map[string]bool, len(cur))
+	for _, state := range cur {
+		seen[state.info.ID] = true
+		event := facade.SessionEvent{Time: now, Session: state.info}
+
+		old, ok := before[state.info.ID]
+		if !ok {
+			event.Type = facade.EventCreated
+			events = append(events, event)
+			continue
+		}
+
+		if old.info.Status != state.info.Status {
+			previous := old.info.Status
+			event.Type = facade.EventStatusChanged
+			event.PreviousStatus = &previous
+			events = append(events, event)
+			event.PreviousStatus = nil
+		}
+		if state.waiting && !old.waiting {
+			event.Type = facade.EventPromptDetected
+			events = append(events, event)
+		}
+		if includeOutput && state.output != old.output {
+			event.Type = facade.EventOutput
+			event.Output = state.output
+			events = append(events, event)
+		}
+	}
+
+	for _, state := range prev {
+		if !seen[state.info.ID] {
+			events = append(events, facade.SessionEvent{Type: facade.EventStopped, Time: now, Session: state.info})
+		}
+	}
+
+	return events
+}
@@ -0,0 +1,48 @@
+package coreadapter
+
+import (
+	"testing"
+	"time"
+
+	"claude-squad/interface/facade"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffWatchStates(t *testing.T) {
+	now := time.Now()
+	prev := []watchState{
+		{info: facade.SessionInfo{ID: "a", Status: facade.StatusRunning}, output: "one"},
+		{info: facade.SessionInfo{ID: "b", Status: facade.StatusRunning}},
+	}
+	cur := []watchState{
+		{info: facade.SessionInfo{ID: "a", Status: facade.StatusReady}, waiting: true, output: "two"},
+		{info: facade.SessionInfo{ID: "c", Status: facade.StatusLoading}},
+	}
+
+	events := diffWatchStates(prev, cur, now, true)
+	require.Len(t, events, 5)
+
+	assert.Equal(t, facade.EventStatusChanged, events[0].Type)
+	require.NotNil(t, events[0].PreviousStatus)
+	assert.Equal(t, facade.StatusRunning, *events[0].PreviousStatus)
+	assert.Equal(t, facade.EventPromptDetected, events[1].Type)
+	assert.Nil(t, events[1].PreviousStatus)
+	assert.Equal(t, facade.EventOutput, events[2].Type)
+	assert.Equal(t, "two", events[2].Output)
+	assert.Equal(t, facade.EventCreated, events[3].Type)
+	assert.Equal(t, "c", events[3].Session.ID)
+	assert.Equal(t, facade.EventStopped, events[4].Type)
+	assert.Equal(t, "b", events[4].Session.ID)
+
+	t.Run("output changes are ignored unless requested", func(t *testing.T) {
+		events := diffWatchStates(prev[:1], cur[:1], now, false)
+		require.Len(t, events, 2)
+		assert.Equal(t, facade.EventPromptDetected, events[1].Type)
+	})
+
+	t.Run("a prompt that is still waiting is reported once", func(t *testing.T) {
+		assert.Empty(t, diffWatchStates(cur, cur, now, true))
+	})
+}
@@ -0,0 +1,46 @@
+package facade
+
+import (
+	"context"
+	"time"
+)
+
+// SessionSummary is one session's row in the dashboard
+type SessionSummary struct {
+	SessionInfo `yaml:",inline"`
+	// WaitingOnPrompt is set when the session's program is waiting for input
+	WaitingOnPrompt bool       `json:"waiting_on_prompt" yaml:"waiting_on_prompt"`
+	Diff            *DiffStats `json:"diff,omitempty" yaml:"diff,omitempty"`
+}
+
+// DaemonState describes the background AutoYes daemon
+type DaemonState struct {
+	Running bool `json:"running" yaml:"running"`
+	PID     int  `json:"pid,omitempty" yaml:"pid,omitempty"`
+}
+
+// StatusSummary is a snapshot of all sessions and the daemon
+type StatusSummary struct {
+	Total    int                   `json:"total" yaml:"total"`
+	ByStatus map[SessionStatus]int `json:"by_status" yaml:"by_status"`
+	Waiting  int                   `json:"waiting" yaml:"waiting"`
+	Sessions []SessionSummary      `json:"sessions" yaml:"sessions"`
+	Daemon   DaemonState           `json:"daemon" yaml:"daemon"`
+}
+
+// CommandStats counts the commands run for one program, e.g. git or tmux
+type CommandStats struct {
+	Program    string        `json:"program" yaml:"program"`
+	Executions uint64        `json:"executions" yaml:"executions"`
+	Failures   uint64        `json:"failures" yaml:"failures"`
+	Running    int           `json:"running" yaml:"running"`
+	P95        time.Duration `json:"p95_ns" yaml:"p95"`
+}
+
+// Dashboard aggregates session state into a single summary
+type Dashboard interface {
+	// Summary collects the state of every session and the daemon
+	Summary(ctx context.Context) (*StatusSummary, error)
+	// CommandStats counts the commands this process has run, per program
+	CommandStats() []CommandStats
+}
@@ -0,0 +1,40 @@
+package facade
+
+import (
+	"context"
+)
+
+// CheckStatus is the outcome of a single diagnostic check
+type CheckStatus int
+
+const (
+	CheckOK CheckStatus = iota
+	CheckWarn
+	CheckFail
+)
+
+// CheckResult describes the outcome of one environment check
+type CheckResult struct {
+	Name    string
+	Status  CheckStatus
+	Message string
+	// Hint suggests how to fix a failed or warning check
+	Hint string
+}
+
+// StorageRepair describes what repairing the session store recovered or set aside
+type StorageRepair struct {
+	// Restored are the IDs of sessions recovered from their previous version
+	Restored []string
+	// Quarantined are the paths unreadable session records were moved to
+	Quarantined []string
+}
+
+// Diagnostics inspects the environment claude-squad depends on
+type Diagnostics interface {
+	// RunChecks runs every check and returns their results in a stable order
+	RunChecks(ctx context.Context) []CheckResult
+
+	// RepairStorage recovers or quarantines corrupted session records
+	RepairStorage(ctx context.Context) (*StorageRepair, error)
+}
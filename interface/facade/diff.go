@@ -2,13 +2,76 @@ package facade
 
 import (
 	"context"
+	"time"
 )
 
 // DiffStats contains git diff statistics
 type DiffStats struct {
-	Added   int
-	Removed int
-	Content string
+	Added   int            `json:"added" yaml:"added"`
+	Removed int            `json:"removed" yaml:"removed"`
+	Content string         `json:"content,omitempty" yaml:"content,omitempty"`
+	Files   []FileDiffStat `json:"files,omitempty" yaml:"files,omitempty"`
+	// Excluded are the changed files the repository's diff rules leave out of Files and
+	// the counts above
+	Excluded []FileDiffStat `json:"excluded,omitempty" yaml:"excluded,omitempty"`
+}
+
+// FileDiffStat describes the changes to one file
+type FileDiffStat struct {
+	Path string `json:"path" yaml:"path"`
+	// Status is one of "added", "modified", "deleted" or "renamed"
+	Status  string `json:"status" yaml:"status"`
+	Added   int    `json:"added" yaml:"added"`
+	Removed int    `json:"removed" yaml:"removed"`
+	Binary  bool   `json:"binary,omitempty" yaml:"binary,omitempty"`
+	// LFS files are stored with Git LFS; their changes aren't counted
+	LFS bool `json:"lfs,omitempty" yaml:"lfs,omitempty"`
+}
+
+// FileStatus is the state of one changed file in a session's worktree
+type FileStatus struct {
+	Path string `json:"path" yaml:"path"`
+	// OrigPath is where a renamed or copied file was before
+	OrigPath string `json:"orig_path,omitempty" yaml:"orig_path,omitempty"`
+	// Staged and Unstaged describe the change in the index and the one in the worktree
+	// not staged yet: "added", "modified", "deleted", "renamed", "copied", "typechange"
+	// or empty when there is none
+	Staged   string `json:"staged,omitempty" yaml:"staged,omitempty"`
+	Unstaged string `json:"unstaged,omitempty" yaml:"unstaged,omitempty"`
+	// Untracked files are new files none of whose content is staged, including those
+	// diffing has marked with intent to add
+	Untracked  bool `json:"untracked,omitempty" yaml:"untracked,omitempty"`
+	Conflicted bool `json:"conflicted,omitempty" yaml:"conflicted,omitempty"`
+}
+
+// SubmoduleStatus is the state of one submodule in a session's worktree
+type SubmoduleStatus struct {
+	Path string `json:"path" yaml:"path"`
+	// Commit is the one checked out, or the one recorded when uninitialized
+	Commit   string `json:"commit" yaml:"commit"`
+	Describe string `json:"describe,omitempty" yaml:"describe,omitempty"`
+	// State is "current" at the recorded commit, "modified" at another one,
+	// "uninitialized" or "conflicted"
+	State string `json:"state" yaml:"state"`
+}
+
+// DiffSample is the size of a session's uncommitted changes at one point in time
+type DiffSample struct {
+	Time    time.Time `json:"time" yaml:"time"`
+	Head    string    `json:"head,omitempty" yaml:"head,omitempty"`
+	Added   int       `json:"added" yaml:"added"`
+	Removed int       `json:"removed" yaml:"removed"`
+	Files   int       `json:"files" yaml:"files"`
+	// Patch is only recorded for the latest samples, with diff_history_patches set
+	Patch string `json:"patch,omitempty" yaml:"patch,omitempty"`
+}
+
+// BlameLine is one line of a file with the commit that last changed it
+type BlameLine struct {
+	Line    int    `json:"line" yaml:"line"`
+	Content string `json:"content" yaml:"content"`
+	// Commit is nil for lines changed in the worktree but not committed yet
+	Commit *CommitInfo `json:"commit,omitempty" yaml:"commit,omitempty"`
 }
 
 // DiffViewer provides git diff information for sessions
@@ -16,6 +79,26 @@ type DiffViewer interface {
 	// Get diff statistics for a session
 	GetDiffStats(ctx context.Context, sessionID string) (*DiffStats, error)
 
+	// Get the full unified diff of a session's changes
+	GetDiffPatch(ctx context.Context, sessionID string) (string, error)
+
+	// Get the staged, unstaged, untracked and conflicted files in a session's worktree
+	GetFileStatus(ctx context.Context, sessionID string) ([]FileStatus, error)
+
+	// Get the state of the submodules in a session's worktree
+	GetSubmoduleStatus(ctx context.Context, sessionID string) ([]SubmoduleStatus, error)
+
+	// Get up to limit commits that changed a file in a session's worktree, newest first;
+	// a limit of 0 gets them all
+	GetFileHistory(ctx context.Context, sessionID, path string, limit int) ([]CommitInfo, error)
+
+	// Get the commit that last changed each line of a file in a session's worktree
+	Blame(ctx context.Context, sessionID, path string) ([]BlameLine, error)
+
+	// Get the recorded sizes of a session's uncommitted changes since a time, oldest
+	// first; a zero since gets them all
+	GetDiffHistory(ctx context.Context, sessionID string, since time.Time) ([]DiffSample, error)
+
 	// Update diff stats (trigger refresh)
 	UpdateDiffStats(ctx context.Context, sessionID string) error
 
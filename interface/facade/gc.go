@@ -0,0 +1,41 @@
+package facade
+
+import (
+	"context"
+)
+
+// OrphanKind identifies which side of a session is missing its counterpart
+type OrphanKind string
+
+const (
+	// OrphanTmuxSession is a claude-squad tmux session with no stored session
+	OrphanTmuxSession OrphanKind = "tmux-session"
+	// OrphanWorktree is a session worktree that no stored session points at
+	OrphanWorktree OrphanKind = "worktree"
+	// OrphanSessionWorktree is a stored session whose worktree no longer exists
+	OrphanSessionWorktree OrphanKind = "session-without-worktree"
+	// OrphanSessionTmux is a stored session whose tmux session is gone but whose
+	// worktree is intact. It is only reported, since removing it would discard work.
+	OrphanSessionTmux OrphanKind = "session-without-tmux"
+)
+
+// Orphan is a resource left behind by a session that no longer exists, or a session
+// that lost one of its resources
+type Orphan struct {
+	Kind OrphanKind `json:"kind" yaml:"kind"`
+	// Resource is the tmux session name, worktree path or session title
+	Resource  string `json:"resource" yaml:"resource"`
+	SessionID string `json:"session_id,omitempty" yaml:"session_id,omitempty"`
+	Repo      string `json:"repo,omitempty" yaml:"repo,omitempty"`
+	// Removable is false for orphans that are reported but never removed automatically
+	Removable bool `json:"removable" yaml:"removable"`
+}
+
+// ResourceReconciler cross-references stored sessions with tmux and git worktrees
+type ResourceReconciler interface {
+	// FindOrphans returns every orphaned resource, in a stable order
+	FindOrphans(ctx context.Context) ([]Orphan, error)
+
+	// RemoveOrphan cleans up a removable orphan returned by FindOrphans
+	RemoveOrphan(ctx context.Context, orphan Orphan) error
+}
@@ -2,17 +2,302 @@ package facade
 
 import (
 	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
 )
 
 // SessionInfo contains basic session information
 type SessionInfo struct {
-	ID        string
-	Title     string
-	Path      string
-	Branch    string
-	Status    SessionStatus
-	Program   string
-	AutoYes   bool
+	ID      string        `json:"id" yaml:"id"`
+	Title   string        `json:"title" yaml:"title"`
+	Path    string        `json:"path" yaml:"path"`
+	Branch  string        `json:"branch" yaml:"branch"`
+	Status  SessionStatus `json:"status" yaml:"status"`
+	Program string        `json:"program" yaml:"program"`
+	AutoYes bool          `json:"auto_yes" yaml:"auto_yes"`
+	// Archived sessions have no worktree or tmux session and are hidden from the default list
+	Archived bool `json:"archived,omitempty" yaml:"archived,omitempty"`
+	// DeletedAt is set for sessions in the trash
+	DeletedAt time.Time `json:"deleted_at,omitempty" yaml:"deleted_at,omitempty"`
+	// Conflicted is set when the session's branch was last found to conflict with its
+	// base branch
+	Conflicted bool `json:"conflicted,omitempty" yaml:"conflicted,omitempty"`
+	// Tracking is how far the session's branch has diverged from its base branch, filled in
+	// by listings that count it
+	Tracking *BranchTracking `json:"tracking,omitempty" yaml:"tracking,omitempty"`
+	// CommitSettings are the session's own, overriding the global ones
+	CommitSettings *CommitSettings `json:"commit_settings,omitempty" yaml:"commit_settings,omitempty"`
+	// InPlace is set for sessions working in an existing checkout, Path, instead of a
+	// worktree of their own
+	InPlace bool `json:"in_place,omitempty" yaml:"in_place,omitempty"`
+
+	CreatedAt time.Time `json:"created_at" yaml:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" yaml:"updated_at"`
+}
+
+// BranchTracking counts the commits a session's branch and its base branch don't share
+type BranchTracking struct {
+	Base   string `json:"base" yaml:"base"`
+	Ahead  int    `json:"ahead" yaml:"ahead"`
+	Behind int    `json:"behind" yaml:"behind"`
+}
+
+// CreateSessionOptions contains the parameters for creating a session
+type CreateSessionOptions struct {
+	Title   string
+	Path    string
+	Branch  string
+	Program string
+	Prompt  string
+	AutoYes bool
+	// Submodules initializes the worktree's submodules; nil leaves it to the configuration
+	Submodules *bool
+	// ForkBranch starts a new branch from Branch when another session already works on
+	// it, instead of failing with a *BranchInUseError
+	ForkBranch bool
+	// InPlace runs the session in Path itself instead of a new worktree; Branch, if set, is
+	// checked out there
+	InPlace bool
+	// Stash sets an in-place session's directory's uncommitted changes aside until the
+	// session is deleted
+	Stash bool
+}
+
+// BranchInUseError is returned when starting a session on a branch another session, or a
+// worktree outside any session, already works on
+type BranchInUseError struct {
+	Branch string
+	// Sessions are the titles of the sessions on the branch
+	Sessions []string
+	// Worktree is where the branch is checked out outside of any session, if it is
+	Worktree string
+}
+
+func (e *BranchInUseError) Error() string {
+	if len(e.Sessions) > 0 {
+		return fmt.Sprintf("branch %s is already used by session '%s'", e.Branch, strings.Join(e.Sessions, "', '"))
+	}
+	return fmt.Sprintf("branch %s is already checked out at %s", e.Branch, e.Worktree)
+}
+
+// CloneSessionOptions contains the parameters for duplicating a session
+type CloneSessionOptions struct {
+	Title            string
+	Branch           string
+	FromSourceBranch bool
+}
+
+// CommitSettings control how a session's commits are made. Unset fields fall back to
+// the global settings.
+type CommitSettings struct {
+	RunHooks      *bool  `json:"run_hooks,omitempty" yaml:"run_hooks,omitempty"`
+	Sign          *bool  `json:"sign,omitempty" yaml:"sign,omitempty"`
+	SigningKey    string `json:"signing_key,omitempty" yaml:"signing_key,omitempty"`
+	SigningFormat string `json:"signing_format,omitempty" yaml:"signing_format,omitempty"`
+	// Author is "Name <email>"
+	Author string `json:"author,omitempty" yaml:"author,omitempty"`
+}
+
+// CommitOptions controls how a session's changes are committed
+type CommitOptions struct {
+	Message  string
+	Amend    bool
+	StageAll bool
+	// Paths limits the commit to these files, leaving other changes uncommitted
+	Paths []string
+}
+
+// CommitInfo describes a commit
+type CommitInfo struct {
+	Hash    string    `json:"hash" yaml:"hash"`
+	Author  string    `json:"author,omitempty" yaml:"author,omitempty"`
+	Message string    `json:"message" yaml:"message"`
+	Time    time.Time `json:"time" yaml:"time"`
+}
+
+// PushOptions controls how a session's branch is pushed
+type PushOptions struct {
+	Remote string
+	Force  bool
+}
+
+// PushResult describes where a session's branch was pushed
+type PushResult struct {
+	Branch    string `json:"branch" yaml:"branch"`
+	Remote    string `json:"remote" yaml:"remote"`
+	RemoteURL string `json:"remote_url" yaml:"remote_url"`
+	BranchURL string `json:"branch_url,omitempty" yaml:"branch_url,omitempty"`
+}
+
+// PullRequestOptions describes a pull request to open from a session's branch. Empty
+// fields are filled in from the session prompt and the branch's commits.
+type PullRequestOptions struct {
+	Remote string
+	Base   string
+	Title  string
+	Body   string
+	Draft  bool
+}
+
+// PullRequest is a pull request opened from a session's branch
+type PullRequest struct {
+	URL    string `json:"url" yaml:"url"`
+	Title  string `json:"title" yaml:"title"`
+	Branch string `json:"branch" yaml:"branch"`
+	Base   string `json:"base" yaml:"base"`
+}
+
+// PullRequestStatus is the state of the latest pull request from a session's branch
+type PullRequestStatus struct {
+	Number int    `json:"number" yaml:"number"`
+	URL    string `json:"url" yaml:"url"`
+	Title  string `json:"title" yaml:"title"`
+	Branch string `json:"branch" yaml:"branch"`
+	Base   string `json:"base" yaml:"base"`
+	// State is "open", "closed" or "merged"
+	State string `json:"state" yaml:"state"`
+	Draft bool   `json:"draft,omitempty" yaml:"draft,omitempty"`
+	// ReviewDecision is e.g. "APPROVED" or "CHANGES_REQUESTED"
+	ReviewDecision string `json:"review_decision,omitempty" yaml:"review_decision,omitempty"`
+	// Mergeable is "MERGEABLE", "CONFLICTING" or "UNKNOWN"
+	Mergeable string  `json:"mergeable,omitempty" yaml:"mergeable,omitempty"`
+	Checks    []Check `json:"checks" yaml:"checks"`
+}
+
+// Check is a status check or CI job run on a pull request
+type Check struct {
+	Name string `json:"name" yaml:"name"`
+	// State is "pass", "fail", "pending" or "skipped"
+	State    string `json:"state" yaml:"state"`
+	Workflow string `json:"workflow,omitempty" yaml:"workflow,omitempty"`
+	URL      string `json:"url,omitempty" yaml:"url,omitempty"`
+}
+
+// FinishOptions controls how a session's branch is integrated into its base branch
+type FinishOptions struct {
+	// Base defaults to the branch checked out in the repository
+	Base string
+	// Strategy is "merge" (default), "squash" or "rebase"
+	Strategy     string
+	Message      string
+	DeleteBranch bool
+	// Tag tags the tip of the session's branch before merging; TagName defaults to
+	// "squad/<title>/done"
+	Tag     bool
+	TagName string
+}
+
+// FinishResult describes a session's branch integrated into its base branch
+type FinishResult struct {
+	Branch        string `json:"branch" yaml:"branch"`
+	Base          string `json:"base" yaml:"base"`
+	Strategy      string `json:"strategy" yaml:"strategy"`
+	Head          string `json:"head" yaml:"head"`
+	BranchDeleted bool   `json:"branch_deleted" yaml:"branch_deleted"`
+	Tag           string `json:"tag,omitempty" yaml:"tag,omitempty"`
+}
+
+// TransplantOptions controls which of a session's work is copied where
+type TransplantOptions struct {
+	// Target is the ID of the session receiving the work; empty for the branch checked
+	// out in the repository
+	Target string
+	// Commits to cherry-pick, single or ranges; defaults to all the target lacks
+	Commits []string
+	// Uncommitted applies the session's uncommitted changes instead of its commits
+	Uncommitted bool
+}
+
+// TransplantResult describes work copied from a session onto another branch
+type TransplantResult struct {
+	Path    string   `json:"path" yaml:"path"`
+	Branch  string   `json:"branch" yaml:"branch"`
+	Commits []string `json:"commits,omitempty" yaml:"commits,omitempty"`
+	Patched bool     `json:"patched,omitempty" yaml:"patched,omitempty"`
+}
+
+// WorktreeMove describes a session's worktree moved to the configured layout, or why it
+// wasn't
+type WorktreeMove struct {
+	SessionID string `json:"session_id" yaml:"session_id"`
+	Title     string `json:"title" yaml:"title"`
+	From      string `json:"from" yaml:"from"`
+	To        string `json:"to" yaml:"to"`
+	Skipped   string `json:"skipped,omitempty" yaml:"skipped,omitempty"`
+}
+
+// RebaseOptions describes how to rebase a session's branch. Continue and Abort act on a
+// rebase stopped by conflicts instead of starting one.
+type RebaseOptions struct {
+	// Onto defaults to the default branch of Remote, fetched first
+	Onto     string
+	Remote   string
+	Continue bool
+	Abort    bool
+}
+
+// RebaseResult describes a session's branch after rebasing it
+type RebaseResult struct {
+	Branch string `json:"branch" yaml:"branch"`
+	Onto   string `json:"onto,omitempty" yaml:"onto,omitempty"`
+	// Status is "rebased", "conflicted" or "aborted"
+	Status    string   `json:"status" yaml:"status"`
+	Head      string   `json:"head" yaml:"head"`
+	Conflicts []string `json:"conflicts,omitempty" yaml:"conflicts,omitempty"`
+}
+
+// FileConflict is a file that can't be merged cleanly
+type FileConflict struct {
+	Path string `json:"path" yaml:"path"`
+	// Hunks is empty for conflicts over the file as a whole, such as one side deleting it
+	Hunks []ConflictHunk `json:"hunks,omitempty" yaml:"hunks,omitempty"`
+}
+
+// ConflictHunk is one conflicting region of a file, with what each side has there
+type ConflictHunk struct {
+	Line   int    `json:"line" yaml:"line"`
+	Ours   string `json:"ours" yaml:"ours"`
+	Theirs string `json:"theirs" yaml:"theirs"`
+}
+
+// PruneOptions selects sessions to delete by retention policy
+type PruneOptions struct {
+	OlderThan time.Duration
+	Status    *SessionStatus
+	DryRun    bool
+}
+
+// ExecOptions describes a command to run inside a session's worktree
+type ExecOptions struct {
+	Program string
+	Args    []string
+	// Shell runs Program as a command line of the user's shell, with Args appended quoted
+	Shell  bool
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
+// InputRecord is one prompt or keystroke sequence sent to a session
+type InputRecord struct {
+	Time time.Time `json:"time" yaml:"time"`
+	// Kind is "prompt" for submitted prompts and "keys" for raw keystrokes
+	Kind string `json:"kind" yaml:"kind"`
+	Text string `json:"text" yaml:"text"`
+}
+
+// AuditEntry is one recorded change to a session
+type AuditEntry struct {
+	Time time.Time `json:"time" yaml:"time"`
+	// Actor is the part of claude-squad that made the change: "cli", "tui" or "daemon"
+	Actor string `json:"actor" yaml:"actor"`
+	// Op is "create", "update", "status" or "delete"
+	Op        string `json:"op" yaml:"op"`
+	SessionID string `json:"session_id" yaml:"session_id"`
+	Title     string `json:"title,omitempty" yaml:"title,omitempty"`
+	Detail    string `json:"detail,omitempty" yaml:"detail,omitempty"`
 }
 
 // SessionStatus represents the state of a session
@@ -25,25 +310,125 @@ const (
 	StatusPaused
 )
 
+// String returns the lowercase name of the status
+func (s SessionStatus) String() string {
+	switch s {
+	case StatusRunning:
+		return "running"
+	case StatusReady:
+		return "ready"
+	case StatusLoading:
+		return "loading"
+	case StatusPaused:
+		return "paused"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseSessionStatus parses a status name as produced by String
+func ParseSessionStatus(s string) (SessionStatus, error) {
+	for _, status := range []SessionStatus{StatusRunning, StatusReady, StatusLoading, StatusPaused} {
+		if status.String() == s {
+			return status, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown session status '%s'", s)
+}
+
+// MarshalText encodes the status by name in structured output
+func (s SessionStatus) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
 // SessionManager handles session lifecycle operations
 type SessionManager interface {
 	// List returns all sessions
 	ListSessions(ctx context.Context) ([]SessionInfo, error)
 
+	// Search returns the sessions whose title, prompts or metadata contain every word of query
+	SearchSessions(ctx context.Context, query string) ([]SessionInfo, error)
+
 	// Create a new session
-	CreateSession(ctx context.Context, title, path, program string) (*SessionInfo, error)
+	CreateSession(ctx context.Context, opts CreateSessionOptions) (*SessionInfo, error)
+
+	// Clone a session's program and prompt onto a fresh branch and worktree
+	CloneSession(ctx context.Context, id string, opts CloneSessionOptions) (*SessionInfo, error)
 
 	// Start/Stop operations
 	StartSession(ctx context.Context, id string) error
 	StopSession(ctx context.Context, id string) error
+
+	// Trash holds deleted sessions until purged. Restore brings one back paused, with its
+	// uncommitted changes.
+	ListTrash(ctx context.Context) ([]SessionInfo, error)
+	RestoreSession(ctx context.Context, id string) error
+	PurgeTrash(ctx context.Context, olderThan time.Duration) ([]SessionInfo, error)
 	PauseSession(ctx context.Context, id string) error
 	ResumeSession(ctx context.Context, id string) error
 
+	// Archive a session: snapshot its diff, remove its worktree and tmux session but keep its
+	// branch and metadata. Unarchive returns it to the paused state.
+	ArchiveSession(ctx context.Context, id string) error
+	UnarchiveSession(ctx context.Context, id string) error
+
+	// Commit the changes in a session's worktree
+	CommitSession(ctx context.Context, id string, opts CommitOptions) (*CommitInfo, error)
+
+	// Replace how a session's commits are made; nil goes back to the global settings
+	SetCommitSettings(ctx context.Context, id string, settings *CommitSettings) error
+
+	// Commit a session's pending changes, merge its branch into the base branch and move
+	// the session to the trash
+	FinishSession(ctx context.Context, id string, opts FinishOptions) (*FinishResult, error)
+
+	// Copy a session's commits or uncommitted changes onto another session's branch, or
+	// the one checked out in the repository, without merging
+	TransplantSession(ctx context.Context, id string, opts TransplantOptions) (*TransplantResult, error)
+
+	// Count the commits a session's branch is ahead and behind base, which defaults to the
+	// default branch of the repository's origin remote
+	GetTracking(ctx context.Context, id string, base string) (*BranchTracking, error)
+
+	// List the files that conflict when merging a session's branch into base, which
+	// defaults to the branch checked out in the repository, and flag the session if any do
+	CheckConflicts(ctx context.Context, id string, base string) ([]FileConflict, error)
+
+	// Rebase a session's branch onto the latest upstream, or continue or abort a rebase
+	// stopped by conflicts. Conflicts are reported in the result.
+	RebaseSession(ctx context.Context, id string, opts RebaseOptions) (*RebaseResult, error)
+
+	// Push a session's branch to a remote
+	PushSession(ctx context.Context, id string, opts PushOptions) (*PushResult, error)
+
+	// Push a session's branch and open a pull request for it
+	CreatePullRequest(ctx context.Context, id string, opts PullRequestOptions) (*PullRequest, error)
+
+	// Get the latest pull request from a session's branch with its checks, nil if there
+	// is none
+	GetPullRequestStatus(ctx context.Context, id string) (*PullRequestStatus, error)
+
+	// Delete sessions not updated within a retention period, cleaning up their resources
+	PruneSessions(ctx context.Context, opts PruneOptions) ([]SessionInfo, error)
+
+	// Move paused sessions' worktrees to the configured worktree directory and naming
+	// scheme, or with dryRun only report where they would go
+	MigrateWorktrees(ctx context.Context, dryRun bool) ([]WorktreeMove, error)
+
 	// Get single session info
 	GetSession(ctx context.Context, id string) (*SessionInfo, error)
 
 	// Update session title
 	UpdateTitle(ctx context.Context, id string, title string) error
+
+	// Export sessions to a tar.gz archive, optionally with git bundles of their branches
+	ExportSessions(ctx context.Context, ids []string, w io.Writer, includeBundles bool) error
+
+	// Import sessions from an export archive into repoPath (empty for their original repositories)
+	ImportSessions(ctx context.Context, r io.Reader, repoPath string) ([]SessionInfo, error)
+
+	// Rename a session, its tmux session and optionally its branch. The session ID changes.
+	RenameSession(ctx context.Context, id string, title string, renameBranch bool) (*SessionInfo, error)
 }
 
 // SessionInteractor handles interaction with running sessions
@@ -57,6 +442,13 @@ type SessionInteractor interface {
 
 	// Check if session has prompts waiting
 	HasPrompt(ctx context.Context, id string) (bool, error)
+
+	// Exec runs a command in the session's worktree, returning its exit code
+	Exec(ctx context.Context, id string, opts ExecOptions) (int, error)
+
+	// Replay runs the last n commands run with Exec in the session's worktree again,
+	// oldest first, stopping at the first that fails
+	Replay(ctx context.Context, id string, n int, stdout, stderr io.Writer) error
 }
 
 // SessionViewer handles viewing session output
@@ -69,4 +461,10 @@ type SessionViewer interface {
 
 	// Check if output has updated
 	HasUpdated(ctx context.Context, id string, lastPreview string) (bool, error)
-}
\ No newline at end of file
+
+	// Get the prompts and keystrokes sent to the session, oldest first
+	GetInputHistory(ctx context.Context, id string) ([]InputRecord, error)
+
+	// Get the recorded changes to the sessions with this ID or title, oldest first
+	GetAuditLog(ctx context.Context, ref string) ([]AuditEntry, error)
+}
@@ -0,0 +1,46 @@
+package facade
+
+import (
+	"context"
+	"time"
+)
+
+// SessionEventType identifies what happened to a session
+type SessionEventType string
+
+const (
+	EventCreated        SessionEventType = "created"
+	EventStatusChanged  SessionEventType = "status-changed"
+	EventPromptDetected SessionEventType = "prompt-detected"
+	EventOutput         SessionEventType = "output"
+	EventStopped        SessionEventType = "stopped"
+)
+
+// SessionEvent is a change observed in a session
+type SessionEvent struct {
+	Type    SessionEventType `json:"type" yaml:"type"`
+	Time    time.Time        `json:"time" yaml:"time"`
+	Session SessionInfo      `json:"session" yaml:"session"`
+	// PreviousStatus is set on status-changed events
+	PreviousStatus *SessionStatus `json:"previous_status,omitempty" yaml:"previous_status,omitempty"`
+	// Output is the session's current pane content on output events
+	Output string `json:"output,omitempty" yaml:"output,omitempty"`
+}
+
+// WatchOptions controls which events a watch reports
+type WatchOptions struct {
+	// Interval is how often sessions are polled for changes
+	Interval time.Duration
+	// SessionIDs limits events to these sessions. Empty watches every session,
+	// including ones created after the watch starts.
+	SessionIDs []string
+	// IncludeOutput reports an output event whenever a session's pane changes
+	IncludeOutput bool
+}
+
+// SessionWatcher reports session lifecycle and output changes as they happen
+type SessionWatcher interface {
+	// Watch sends events on the returned channel until ctx is cancelled, then closes it.
+	// Sessions that exist when the watch starts don't produce created events.
+	Watch(ctx context.Context, opts WatchOptions) (<-chan SessionEvent, error)
+}
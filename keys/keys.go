@@ -22,6 +22,7 @@ const (
 
 	KeyCheckout
 	KeyResume
+	KeyResumeAll
 	KeyPrompt // New key for entering a prompt
 	KeyHelp   // Key for showing help screen
 
@@ -47,6 +48,7 @@ var GlobalKeyStringsMap = map[string]KeyName{
 	"tab":        KeyTab,
 	"c":          KeyCheckout,
 	"r":          KeyResume,
+	"R":          KeyResumeAll,
 	"p":          KeySubmit,
 	"?":          KeyHelp,
 }
@@ -109,6 +111,10 @@ var GlobalkeyBindings = map[KeyName]key.Binding{
 		key.WithKeys("r"),
 		key.WithHelp("r", "resume"),
 	),
+	KeyResumeAll: key.NewBinding(
+		key.WithKeys("R"),
+		key.WithHelp("R", "resume all"),
+	),
 
 	// -- Special keybindings --
 
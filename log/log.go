@@ -16,6 +16,14 @@ var (
 
 var logFileName = filepath.Join(os.TempDir(), "claudesquad.log")
 
+// DaemonPrefix marks log lines written by the daemon process.
+const DaemonPrefix = "[DAEMON] "
+
+// FilePath returns the path of the log file shared by the TUI and the daemon.
+func FilePath() string {
+	return logFileName
+}
+
 var globalLogFile *os.File
 
 // Initialize should be called once at the beginning of the program to set up logging.
@@ -33,11 +41,14 @@ func Initialize(daemon bool) {
 
 	fmtS := "%s"
 	if daemon {
-		fmtS = "[DAEMON] %s"
+		fmtS = DaemonPrefix + "%s"
 	}
-	InfoLog = log.New(f, fmt.Sprintf(fmtS, "INFO:"), log.Ldate|log.Ltime|log.Lshortfile)
-	WarningLog = log.New(f, fmt.Sprintf(fmtS, "WARNING:"), log.Ldate|log.Ltime|log.Lshortfile)
-	ErrorLog = log.New(f, fmt.Sprintf(fmtS, "ERROR:"), log.Ldate|log.Ltime|log.Lshortfile)
+	// Everything written to the log file passes through the redactor so credentials
+	// that show up in agent output or command lines never land on disk.
+	w := &redactWriter{w: f}
+	InfoLog = log.New(w, fmt.Sprintf(fmtS, "INFO:"), log.Ldate|log.Ltime|log.Lshortfile)
+	WarningLog = log.New(w, fmt.Sprintf(fmtS, "WARNING:"), log.Ldate|log.Ltime|log.Lshortfile)
+	ErrorLog = log.New(w, fmt.Sprintf(fmtS, "ERROR:"), log.Ldate|log.Ltime|log.Lshortfile)
 
 	globalLogFile = f
 }
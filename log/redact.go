@@ -0,0 +1,116 @@
+package log
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"sync"
+)
+
+// RedactedPlaceholder replaces any text matched by a redaction pattern.
+const RedactedPlaceholder = "[REDACTED]"
+
+// builtinRedactPatterns match common credential formats that tend to scroll through agent panes.
+// Key prefixes must start a word, so that words merely containing one, like "task-...", are
+// left alone.
+var builtinRedactPatterns = []string{
+	// Anthropic and OpenAI API keys
+	`\bsk-ant-[A-Za-z0-9_\-]{20,}`,
+	`\bsk-(?:proj-)?[A-Za-z0-9_\-]{20,}`,
+	// GitHub personal access, OAuth, app and refresh tokens
+	`\bgh[pousr]_[A-Za-z0-9]{36,}`,
+	`\bgithub_pat_[A-Za-z0-9_]{22,}`,
+	// GitLab personal access tokens
+	`\bglpat-[A-Za-z0-9_\-]{20,}`,
+	// AWS access key IDs
+	`\b(?:AKIA|ASIA)[0-9A-Z]{16}`,
+	// Slack tokens
+	`\bxox[abposr]-[A-Za-z0-9\-]{10,}`,
+	// Google API keys
+	`\bAIza[0-9A-Za-z_\-]{35}`,
+	// Bearer tokens in headers
+	`(?i)bearer\s+[A-Za-z0-9_\-\.=]{20,}`,
+	// PEM private key blocks
+	`-----BEGIN [A-Z ]*PRIVATE KEY-----[\s\S]*?-----END [A-Z ]*PRIVATE KEY-----`,
+	// Generic key=value assignments such as API_KEY=... or "password": "..."
+	`(?i)(?:api[_-]?key|secret|token|password|passwd)["']?\s*[:=]\s*["']?[^\s"']{8,}`,
+}
+
+// Redactor masks credentials in text before it is written to logs or persisted.
+type Redactor struct {
+	patterns []*regexp.Regexp
+}
+
+// NewRedactor creates a Redactor from the built-in patterns plus any extra user-supplied regexes.
+func NewRedactor(extra []string) (*Redactor, error) {
+	r := &Redactor{}
+	for _, p := range append(append([]string{}, builtinRedactPatterns...), extra...) {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction pattern %q: %w", p, err)
+		}
+		r.patterns = append(r.patterns, re)
+	}
+	return r, nil
+}
+
+// Redact returns s with every pattern match replaced by RedactedPlaceholder.
+func (r *Redactor) Redact(s string) string {
+	if r == nil {
+		return s
+	}
+	for _, re := range r.patterns {
+		s = re.ReplaceAllString(s, RedactedPlaceholder)
+	}
+	return s
+}
+
+var (
+	redactorMu      sync.RWMutex
+	defaultRedactor = mustNewRedactor(nil)
+)
+
+func mustNewRedactor(extra []string) *Redactor {
+	r, err := NewRedactor(extra)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// SetRedactPatterns replaces the process-wide redactor with one that also applies the given patterns.
+// It should be called once the config has been loaded.
+func SetRedactPatterns(patterns []string) error {
+	r, err := NewRedactor(patterns)
+	if err != nil {
+		return err
+	}
+	redactorMu.Lock()
+	defaultRedactor = r
+	redactorMu.Unlock()
+	return nil
+}
+
+// DefaultRedactor returns the process-wide redactor.
+func DefaultRedactor() *Redactor {
+	redactorMu.RLock()
+	defer redactorMu.RUnlock()
+	return defaultRedactor
+}
+
+// Redact masks credentials in s using the process-wide redactor.
+func Redact(s string) string {
+	return DefaultRedactor().Redact(s)
+}
+
+// redactWriter redacts everything written through it before passing it on.
+type redactWriter struct {
+	w io.Writer
+}
+
+func (rw *redactWriter) Write(p []byte) (int, error) {
+	if _, err := rw.w.Write([]byte(Redact(string(p)))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
@@ -9,10 +9,16 @@ import (
 	"claude-squad/session"
 	"claude-squad/session/git"
 	"claude-squad/session/tmux"
+	"claude-squad/upgrade"
 	"context"
 	"encoding/json"
 	"fmt"
+	"os"
+	"os/signal"
 	"path/filepath"
+	"runtime"
+	"syscall"
+	"time"
 
 	"github.com/spf13/cobra"
 )
@@ -21,22 +27,20 @@ var (
 	version     = "1.0.13"
 	programFlag string
 	autoYesFlag bool
-	daemonFlag  bool
+	dryRunFlag  bool
+	checkOnly   bool
+	resumeAll   bool
+	resumeJobs  int
+	daemonLines int
+	daemonTail  bool
 	rootCmd     = &cobra.Command{
 		Use:   "claude-squad",
 		Short: "Claude Squad - Manage multiple AI agents like Claude Code, Aider, Codex, and Amp.",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := context.Background()
-			log.Initialize(daemonFlag)
+			log.Initialize(false)
 			defer log.Close()
 
-			if daemonFlag {
-				cfg := config.LoadConfig()
-				err := daemon.RunDaemon(cfg)
-				log.ErrorLog.Printf("failed to start daemon %v", err)
-				return err
-			}
-
 			// Check if we're in a git repository
 			currentDir, err := filepath.Abs(".")
 			if err != nil {
@@ -48,6 +52,9 @@ var (
 			}
 
 			cfg := config.LoadConfig()
+			if err := log.SetRedactPatterns(cfg.RedactPatterns); err != nil {
+				log.ErrorLog.Printf("failed to apply redaction patterns: %v", err)
+			}
 
 			// Program flag overrides config
 			program := cfg.DefaultProgram
@@ -71,6 +78,7 @@ var (
 				log.ErrorLog.Printf("failed to stop daemon: %v", err)
 			}
 
+			app.Version = version
 			return app.Run(ctx, program, autoYes)
 		},
 	}
@@ -82,6 +90,20 @@ var (
 			log.Initialize(false)
 			defer log.Close()
 
+			if dryRunFlag {
+				// Print the cleanup instead, still reading the sessions and worktrees it covers
+				dryRun := cmd2.DryRun{Out: os.Stdout}
+				dryRun.Skip("delete all stored instances")
+				if err := tmux.CleanupSessions(dryRun); err != nil {
+					return fmt.Errorf("failed to cleanup tmux sessions: %w", err)
+				}
+				if err := git.CleanupWorktrees(dryRun); err != nil {
+					return fmt.Errorf("failed to cleanup worktrees: %w", err)
+				}
+				dryRun.Skip("stop the daemon")
+				return nil
+			}
+
 			state := config.LoadState()
 			storage, err := session.NewStorage(state)
 			if err != nil {
@@ -97,7 +119,7 @@ var (
 			}
 			fmt.Println("Tmux sessions have been cleaned up")
 
-			if err := git.CleanupWorktrees(); err != nil {
+			if err := git.CleanupWorktrees(cmd2.MakeExecutor()); err != nil {
 				return fmt.Errorf("failed to cleanup worktrees: %w", err)
 			}
 			fmt.Println("Worktrees have been cleaned up")
@@ -112,6 +134,120 @@ var (
 		},
 	}
 
+	daemonCmd = &cobra.Command{
+		Use:   "daemon",
+		Short: "Manage the background daemon that runs auto-yes on sessions while the TUI is closed",
+	}
+
+	daemonRunCmd = &cobra.Command{
+		Use:    "run",
+		Short:  "Run the daemon in the foreground",
+		Hidden: true, // Started detached by `daemon start` and the TUI
+		RunE: func(cmd *cobra.Command, args []string) error {
+			log.Initialize(true)
+			defer log.Close()
+
+			cfg := config.LoadConfig()
+			if err := log.SetRedactPatterns(cfg.RedactPatterns); err != nil {
+				log.ErrorLog.Printf("failed to apply redaction patterns: %v", err)
+			}
+			err := daemon.RunDaemon(cfg)
+			if err != nil {
+				log.ErrorLog.Printf("failed to start daemon %v", err)
+			}
+			return err
+		},
+	}
+
+	daemonStartCmd = &cobra.Command{
+		Use:   "start",
+		Short: "Start the daemon detached from the terminal",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			log.Initialize(false)
+			defer log.Close()
+
+			if pid, running, err := daemon.Status(); err != nil {
+				return err
+			} else if running {
+				fmt.Printf("daemon is already running (PID %d)\n", pid)
+				return nil
+			}
+
+			if err := daemon.LaunchDaemon(); err != nil {
+				return fmt.Errorf("failed to launch daemon: %w", err)
+			}
+			pid, _, err := daemon.Status()
+			if err != nil {
+				return err
+			}
+			fmt.Printf("daemon started (PID %d)\n", pid)
+			return nil
+		},
+	}
+
+	daemonStopCmd = &cobra.Command{
+		Use:   "stop",
+		Short: "Stop the running daemon",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			log.Initialize(false)
+			defer log.Close()
+
+			pid, running, err := daemon.Status()
+			if err != nil {
+				return err
+			}
+			if err := daemon.StopDaemon(); err != nil {
+				return err
+			}
+			if running {
+				fmt.Printf("daemon stopped (PID %d)\n", pid)
+			} else {
+				fmt.Println("daemon is not running")
+			}
+			return nil
+		},
+	}
+
+	daemonStatusCmd = &cobra.Command{
+		Use:   "status",
+		Short: "Report whether the daemon is running",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			pid, running, err := daemon.Status()
+			if err != nil {
+				return err
+			}
+			pidFile, err := daemon.PIDFile()
+			if err != nil {
+				return err
+			}
+			switch {
+			case running:
+				fmt.Printf("daemon is running (PID %d)\n", pid)
+			case pid != 0:
+				fmt.Printf("daemon is not running (stale PID file %s for PID %d)\n", pidFile, pid)
+			default:
+				fmt.Println("daemon is not running")
+			}
+			fmt.Printf("logs: %s\n", log.FilePath())
+			return nil
+		},
+	}
+
+	daemonLogsCmd = &cobra.Command{
+		Use:   "logs",
+		Short: "Print daemon log lines",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			offset, err := daemon.WriteLogs(os.Stdout, daemonLines)
+			if err != nil || !daemonTail {
+				return err
+			}
+
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			return daemon.FollowLogs(ctx, os.Stdout, offset)
+		},
+	}
+
 	debugCmd = &cobra.Command{
 		Use:   "debug",
 		Short: "Print debug information like config paths",
@@ -141,6 +277,150 @@ var (
 			fmt.Printf("https://github.com/smtg-ai/claude-squad/releases/tag/v%s\n", version)
 		},
 	}
+
+	resumeCmd = &cobra.Command{
+		Use:   "resume [title]",
+		Short: "Resume a paused session, or all paused sessions with --all",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if resumeAll == (len(args) == 1) {
+				return fmt.Errorf("specify either a session title or --all")
+			}
+
+			log.Initialize(false)
+			defer log.Close()
+
+			state := config.LoadState()
+			storage, err := session.NewStorage(state)
+			if err != nil {
+				return fmt.Errorf("failed to initialize storage: %w", err)
+			}
+			instances, err := storage.LoadInstances()
+			if err != nil {
+				return fmt.Errorf("failed to load instances: %w", err)
+			}
+
+			var targets []*session.Instance
+			found := false
+			for _, instance := range instances {
+				if !resumeAll && instance.Title != args[0] {
+					continue
+				}
+				found = true
+				if instance.Paused() {
+					targets = append(targets, instance)
+				}
+			}
+			if !resumeAll && !found {
+				return fmt.Errorf("session not found: %s", args[0])
+			}
+
+			results := session.ResumeAll(targets, resumeJobs)
+			if len(results) == 0 {
+				fmt.Println("No paused sessions to resume")
+				return nil
+			}
+
+			failed := 0
+			for _, result := range results {
+				switch {
+				case result.Skipped:
+					fmt.Printf("skipped %s: %v\n", result.Title, result.Err)
+				case result.Err != nil:
+					failed++
+					fmt.Printf("failed  %s: %v\n", result.Title, result.Err)
+				default:
+					fmt.Printf("resumed %s\n", result.Title)
+				}
+			}
+
+			session.MarkResumed(results)
+			if err := storage.SaveInstances(instances); err != nil {
+				return fmt.Errorf("failed to save instances: %w", err)
+			}
+			if failed > 0 {
+				return fmt.Errorf("%d of %d sessions failed to resume", failed, len(results))
+			}
+			return nil
+		},
+	}
+
+	reportCmd = &cobra.Command{
+		Use:   "report [title]",
+		Short: "Print an activity report for one or all sessions",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			log.Initialize(false)
+			defer log.Close()
+
+			state := config.LoadState()
+			var data []session.InstanceData
+			if err := json.Unmarshal(state.GetInstances(), &data); err != nil {
+				return fmt.Errorf("failed to unmarshal instances: %w", err)
+			}
+			// Event logs are kept by ID, so a title is looked up among the saved sessions
+			if len(args) == 1 {
+				var matched []session.InstanceData
+				for _, d := range data {
+					if d.Title == args[0] {
+						matched = append(matched, d)
+					}
+				}
+				if len(matched) == 0 {
+					return fmt.Errorf("session not found: %s", args[0])
+				}
+				data = matched
+			}
+
+			for i, d := range data {
+				events, err := session.LoadEvents(d.ID)
+				if err != nil {
+					return fmt.Errorf("failed to load events for %s: %w", d.Title, err)
+				}
+				if i > 0 {
+					fmt.Println()
+				}
+				printReport(d.Title, events)
+			}
+			return nil
+		},
+	}
+
+	upgradeCmd = &cobra.Command{
+		Use:   "upgrade",
+		Short: "Upgrade claude-squad to the latest release",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			log.Initialize(false)
+			defer log.Close()
+
+			client := upgrade.NewClient()
+			release, newer, err := client.Check(cmd.Context(), version)
+			if err != nil {
+				return fmt.Errorf("failed to check for updates: %w", err)
+			}
+			if !newer {
+				fmt.Printf("claude-squad is up to date (version %s)\n", version)
+				return nil
+			}
+
+			fmt.Printf("A new version of claude-squad is available: %s (current %s)\n", release.Version(), version)
+			if checkOnly {
+				fmt.Println(release.HTMLURL)
+				return nil
+			}
+
+			execPath, err := os.Executable()
+			if err != nil {
+				return fmt.Errorf("failed to locate executable: %w", err)
+			}
+			fmt.Printf("Downloading %s...\n", upgrade.ArchiveName(release.Version(), runtime.GOOS, runtime.GOARCH))
+			if err := client.Apply(cmd.Context(), release, execPath); err != nil {
+				return fmt.Errorf("failed to upgrade: %w", err)
+			}
+			fmt.Printf("Upgraded claude-squad to version %s\n", release.Version())
+			return nil
+		},
+	}
 )
 
 func init() {
@@ -148,18 +428,52 @@ func init() {
 		"Program to run in new instances (e.g. 'aider --model ollama_chat/gemma3:1b')")
 	rootCmd.Flags().BoolVarP(&autoYesFlag, "autoyes", "y", false,
 		"[experimental] If enabled, all instances will automatically accept prompts")
-	rootCmd.Flags().BoolVar(&daemonFlag, "daemon", false, "Run a program that loads all sessions"+
-		" and runs autoyes mode on them.")
 
-	// Hide the daemonFlag as it's only for internal use
-	err := rootCmd.Flags().MarkHidden("daemon")
-	if err != nil {
-		panic(err)
-	}
+	rootCmd.PersistentFlags().BoolVar(&dryRunFlag, "dry-run", false,
+		"Print what reset would clean up without changing anything")
 
 	rootCmd.AddCommand(debugCmd)
 	rootCmd.AddCommand(versionCmd)
 	rootCmd.AddCommand(resetCmd)
+
+	daemonLogsCmd.Flags().IntVarP(&daemonLines, "lines", "n", 50, "Number of log lines to show (0 for all)")
+	daemonLogsCmd.Flags().BoolVarP(&daemonTail, "follow", "f", false, "Keep printing new log lines as they are written")
+	daemonCmd.AddCommand(daemonRunCmd, daemonStartCmd, daemonStopCmd, daemonStatusCmd, daemonLogsCmd)
+	rootCmd.AddCommand(daemonCmd)
+
+	resumeCmd.Flags().BoolVar(&resumeAll, "all", false, "Resume every paused session")
+	resumeCmd.Flags().IntVarP(&resumeJobs, "jobs", "j", session.DefaultResumeConcurrency,
+		"Number of sessions to resume in parallel")
+	rootCmd.AddCommand(resumeCmd)
+	rootCmd.AddCommand(reportCmd)
+
+	upgradeCmd.Flags().BoolVar(&checkOnly, "check-only", false,
+		"Only check whether a newer version is available")
+	rootCmd.AddCommand(upgradeCmd)
+}
+
+// printReport prints a summary and the activity timeline for a single session.
+func printReport(title string, events []session.Event) {
+	fmt.Printf("== %s ==\n", title)
+	if len(events) == 0 {
+		fmt.Println("No recorded activity")
+		return
+	}
+
+	counts := make(map[session.EventType]int)
+	for _, e := range events {
+		counts[e.Type]++
+	}
+	first, last := events[0].Time, events[len(events)-1].Time
+	fmt.Printf("Active: %s - %s (%s)\n", first.Format("2006-01-02 15:04"), last.Format("2006-01-02 15:04"),
+		last.Sub(first).Round(time.Minute))
+	fmt.Printf("Prompts: %d  Output bursts: %d  Auto-yes: %d  Commits: %d\n",
+		counts[session.EventPrompt], counts[session.EventOutput], counts[session.EventAutoYes], counts[session.EventCommit])
+
+	fmt.Println("\nTimeline:")
+	for _, e := range events {
+		fmt.Printf("  %s\n", e)
+	}
 }
 
 func main() {
@@ -0,0 +1,128 @@
+package executor
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"claude-squad/log"
+)
+
+// CommandRecord describes one command an executor ran. Arguments and the directory are
+// redacted; the environment is left out, since it is where credentials usually are.
+type CommandRecord struct {
+	Time     time.Time     `json:"time"`
+	Program  string        `json:"program"`
+	Args     []string      `json:"args,omitempty"`
+	Dir      string        `json:"dir,omitempty"`
+	Duration time.Duration `json:"duration_ns"`
+	// ExitCode is -1 when the command couldn't be run or was killed by a signal
+	ExitCode int    `json:"exit_code"`
+	Error    string `json:"error,omitempty"`
+}
+
+// CommandAuditSink receives a record of every command an executor runs, for debugging and
+// compliance. Commands run through ExecuteInteractive aren't recorded, having no result.
+type CommandAuditSink interface {
+	Record(record CommandRecord) error
+}
+
+// audit records a finished command with the configured sink, if any. Failing to record
+// it is logged rather than failing the command.
+func (e *execImpl) audit(cmd Command, start time.Time, exitCode int, err error) {
+	if e.opts.Audit == nil {
+		return
+	}
+	record := CommandRecord{
+		Time:     start,
+		Program:  cmd.Program,
+		Dir:      e.redact(cmd.Dir),
+		Duration: time.Since(start),
+		ExitCode: exitCode,
+	}
+	for _, arg := range cmd.Args {
+		record.Args = append(record.Args, e.redact(arg))
+	}
+	if err != nil {
+		record.Error = e.redact(err.Error())
+	}
+	if err := e.opts.Audit.Record(record); err != nil && log.WarningLog != nil {
+		log.WarningLog.Printf("failed to record command in audit log: %v", err)
+	}
+}
+
+// CommandAuditLog is a CommandAuditSink appending records to a file as JSON lines. Once
+// the file grows past its size limit it is rotated, keeping a number of older files
+// alongside it as path.1, path.2 and so on.
+type CommandAuditLog struct {
+	path       string
+	maxSize    int64
+	maxBackups int
+	mu         sync.Mutex
+}
+
+// NewCommandAuditLog creates a command audit log kept at path, rotated once it exceeds
+// maxSize bytes with maxBackups older files kept. A maxSize of zero never rotates.
+func NewCommandAuditLog(path string, maxSize int64, maxBackups int) *CommandAuditLog {
+	return &CommandAuditLog{path: path, maxSize: maxSize, maxBackups: maxBackups}
+}
+
+// Record appends record to the log
+func (l *CommandAuditLog) Record(record CommandRecord) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal command record: %w", err)
+	}
+	line = append(line, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(l.path), 0755); err != nil {
+		return fmt.Errorf("failed to create command audit log directory: %w", err)
+	}
+	if err := l.rotate(int64(len(line))); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open command audit log: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.Write(line); err != nil {
+		return fmt.Errorf("failed to write command audit log: %w", err)
+	}
+	return nil
+}
+
+// rotate moves the log aside if writing n more bytes would take it past its size limit
+func (l *CommandAuditLog) rotate(n int64) error {
+	if l.maxSize <= 0 {
+		return nil
+	}
+	info, err := os.Stat(l.path)
+	if os.IsNotExist(err) || (err == nil && info.Size()+n <= l.maxSize) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to stat command audit log: %w", err)
+	}
+
+	if l.maxBackups <= 0 {
+		return os.Remove(l.path)
+	}
+	// The oldest backup is overwritten by the one before it
+	for i := l.maxBackups - 1; i > 0; i-- {
+		older := fmt.Sprintf("%s.%d", l.path, i)
+		if err := os.Rename(older, fmt.Sprintf("%s.%d", l.path, i+1)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to rotate command audit log: %w", err)
+		}
+	}
+	if err := os.Rename(l.path, l.path+".1"); err != nil {
+		return fmt.Errorf("failed to rotate command audit log: %w", err)
+	}
+	return nil
+}
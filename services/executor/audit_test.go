@@ -0,0 +1,67 @@
+package executor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingSink keeps the records it receives
+type recordingSink struct {
+	mu      sync.Mutex
+	records []CommandRecord
+}
+
+func (s *recordingSink) Record(record CommandRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records = append(s.records, record)
+	return nil
+}
+
+func TestCommandAudit(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs a POSIX shell")
+	}
+	sink := &recordingSink{}
+	e := NewExecutor(&ExecutorOptions{DefaultTimeout: 10 * time.Second, CaptureOutput: true, Audit: sink})
+	dir := t.TempDir()
+
+	secret := "sk-ant-api03-" + strings.Repeat("a", 40)
+	_, err := e.Execute(context.Background(), Command{Program: "sh", Args: []string{"-c", "exit 3", secret}, Dir: dir})
+	require.NoError(t, err)
+
+	require.Len(t, sink.records, 1)
+	record := sink.records[0]
+	assert.Equal(t, "sh", record.Program)
+	assert.Equal(t, dir, record.Dir)
+	assert.Equal(t, 3, record.ExitCode)
+	assert.NotEmpty(t, record.Error)
+	require.Len(t, record.Args, 3)
+	assert.NotContains(t, record.Args[2], secret)
+}
+
+func TestCommandAuditLogRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "commands.log")
+	auditLog := NewCommandAuditLog(path, 200, 2)
+
+	for i := 0; i < 10; i++ {
+		require.NoError(t, auditLog.Record(CommandRecord{Time: time.Now(), Program: "git", Args: []string{"status"}}))
+	}
+
+	for _, name := range []string{path, path + ".1", path + ".2"} {
+		info, err := os.Stat(name)
+		require.NoError(t, err)
+		assert.LessOrEqual(t, info.Size(), int64(200))
+	}
+	_, err := os.Stat(path + ".3")
+	assert.True(t, os.IsNotExist(err))
+}
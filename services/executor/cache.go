@@ -0,0 +1,98 @@
+package executor
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"time"
+)
+
+// volatileCommands are read-only commands whose output changes without any command being
+// run, which are never cached
+var volatileCommands = map[string][]string{
+	"tmux": {"capture-pane"},
+}
+
+// resultCache keeps the results of read-only commands for a while, so views refreshing
+// every second don't start the same git and tmux processes for each session on each tick.
+// Running a command that changes state drops the cached results of the same program.
+type resultCache struct {
+	ttl     time.Duration
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+type cacheEntry struct {
+	program string
+	result  *Result
+	expires time.Time
+}
+
+func newResultCache(ttl time.Duration) *resultCache {
+	return &resultCache{ttl: ttl, entries: make(map[string]cacheEntry)}
+}
+
+// cacheKey returns the key cmd's result is cached under, or false if it isn't cached:
+// when caching is off, or the command may change state, reads input or has its own
+// environment
+func (e *execImpl) cacheKey(cmd Command) (string, bool) {
+	if e.cache == nil || cmd.Stdin != nil || cmd.Env != nil || !readOnly(cmd) {
+		return "", false
+	}
+	args := skipGlobalFlags(cmd.Program, cmd.Args)
+	for _, subcommand := range volatileCommands[cmd.Program] {
+		if args[0] == subcommand {
+			return "", false
+		}
+	}
+	dir := cmd.Dir
+	if dir == "" {
+		dir = e.opts.WorkingDir
+	}
+	return strings.Join(append([]string{cmd.Program, dir}, cmd.Args...), "\x00"), true
+}
+
+// get returns a copy of the result cached under key, if it hasn't expired
+func (c *resultCache) get(key string) (*Result, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	if !ok || time.Now().After(entry.expires) {
+		return nil, false
+	}
+	return copyResult(entry.result), true
+}
+
+// put caches a copy of the result of program under key
+func (c *resultCache) put(key, program string, result *Result) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	for k, entry := range c.entries {
+		if now.After(entry.expires) {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = cacheEntry{program: program, result: copyResult(result), expires: now.Add(c.ttl)}
+}
+
+// invalidate drops the cached results of program when cmd may change what they report
+func (e *execImpl) invalidate(cmd Command) {
+	if e.cache == nil || readOnly(cmd) {
+		return
+	}
+	e.cache.mu.Lock()
+	defer e.cache.mu.Unlock()
+	for k, entry := range e.cache.entries {
+		if entry.program == cmd.Program {
+			delete(e.cache.entries, k)
+		}
+	}
+}
+
+func copyResult(result *Result) *Result {
+	c := *result
+	c.Stdout = bytes.Clone(result.Stdout)
+	c.Stderr = bytes.Clone(result.Stderr)
+	return &c
+}
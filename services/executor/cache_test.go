@@ -0,0 +1,58 @@
+package executor
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResultCache(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	ctx := context.Background()
+	dir := t.TempDir()
+	e := NewExecutor(&ExecutorOptions{DefaultTimeout: 10 * time.Second, CaptureOutput: true, ResultCacheTTL: time.Minute})
+	git := func(args ...string) string {
+		result, err := e.Execute(ctx, Command{Program: "git", Args: append([]string{"-C", dir}, args...)})
+		require.NoError(t, err)
+		require.Equal(t, 0, result.ExitCode, string(result.Stderr))
+		return strings.TrimSpace(string(result.Stdout))
+	}
+	git("init")
+	git("symbolic-ref", "HEAD", "refs/heads/main")
+	assert.Equal(t, "main", git("branch", "--show-current"))
+
+	// A change made behind the executor's back goes unnoticed until the result expires
+	require.NoError(t, exec.Command("git", "-C", dir, "symbolic-ref", "HEAD", "refs/heads/outside").Run())
+	assert.Equal(t, "main", git("branch", "--show-current"))
+
+	// A change made through it is seen straight away
+	git("symbolic-ref", "HEAD", "refs/heads/inside")
+	assert.Equal(t, "inside", git("branch", "--show-current"))
+}
+
+func TestCacheKey(t *testing.T) {
+	e := NewExecutor(&ExecutorOptions{ResultCacheTTL: time.Second}).(*execImpl)
+
+	_, ok := e.cacheKey(Command{Program: "tmux", Args: []string{"list-sessions"}})
+	assert.True(t, ok)
+	_, ok = e.cacheKey(Command{Program: "tmux", Args: []string{"capture-pane", "-p", "-t", "s"}})
+	assert.False(t, ok, "pane contents change on their own")
+	_, ok = e.cacheKey(Command{Program: "tmux", Args: []string{"kill-session", "-t", "s"}})
+	assert.False(t, ok)
+	_, ok = e.cacheKey(Command{Program: "git", Args: []string{"status"}, Env: []string{"GIT_DIR=/x"}})
+	assert.False(t, ok)
+
+	a, _ := e.cacheKey(Command{Program: "git", Args: []string{"status"}, Dir: "/a"})
+	b, _ := e.cacheKey(Command{Program: "git", Args: []string{"status"}, Dir: "/b"})
+	assert.NotEqual(t, a, b)
+
+	_, ok = NewExecutor(nil).(*execImpl).cacheKey(Command{Program: "tmux", Args: []string{"ls"}})
+	assert.False(t, ok, "caching is opt-in")
+}
@@ -0,0 +1,190 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// defaultCancelDrain is how long a cancelled process has to exit after SIGINT by default
+const defaultCancelDrain = 10 * time.Second
+
+func (e *execImpl) cancelDrain() time.Duration {
+	if e.opts.CancelDrainTimeout > 0 {
+		return e.opts.CancelDrainTimeout
+	}
+	return defaultCancelDrain
+}
+
+// Cancel interrupts the process, giving it the drain timeout to save its state and exit
+// before it is killed
+func (e *execImpl) Cancel(ctx context.Context, handle ProcessHandle) error {
+	return cancelProcess(ctx, handle, e.cancelDrain(), killPollInterval)
+}
+
+// cancelProcess sends SIGINT to the process of handle and waits up to drain for it to
+// exit, checking every interval, and kills it if it hasn't by then or ctx is done first
+func cancelProcess(ctx context.Context, handle ProcessHandle, drain, interval time.Duration) error {
+	if err := handle.Signal(int(syscall.SIGINT)); err != nil {
+		if errors.Is(err, os.ErrProcessDone) {
+			return nil
+		}
+		return handle.Kill()
+	}
+
+	deadline := time.NewTimer(drain)
+	defer deadline.Stop()
+	poll := time.NewTicker(interval)
+	defer poll.Stop()
+	for {
+		if state, err := handle.State(); err == nil && state == ProcessStateExited {
+			return nil
+		}
+		select {
+		case <-poll.C:
+		case <-deadline.C:
+			return handle.Kill()
+		case <-ctx.Done():
+			return handle.Kill()
+		}
+	}
+}
+
+// externalProcess is a handle to a process the executor didn't start, such as the program
+// in a tmux pane. Signals reach its whole process group when it leads one.
+type externalProcess struct {
+	pid      int
+	executor *execImpl
+}
+
+// findExternal returns a handle to the running process with the given PID
+func (e *execImpl) findExternal(pid int) (ProcessHandle, error) {
+	if pid <= 0 || signalExternal(pid, 0) != nil {
+		return nil, fmt.Errorf("process with PID %d not found", pid)
+	}
+	return &externalProcess{pid: pid, executor: e}, nil
+}
+
+func (p *externalProcess) PID() int {
+	return p.pid
+}
+
+func (p *externalProcess) Signal(sig int) error {
+	return signalExternal(p.pid, syscall.Signal(sig))
+}
+
+// Kill terminates the process, forcibly once the grace period has passed
+func (p *externalProcess) Kill() error {
+	if err := signalExternal(p.pid, syscall.SIGTERM); err != nil {
+		return err
+	}
+	grace := p.executor.killGrace()
+	go func() {
+		deadline := time.Now().Add(grace)
+		for time.Now().Before(deadline) {
+			time.Sleep(killPollInterval)
+			if signalExternal(p.pid, 0) != nil {
+				return
+			}
+		}
+		signalExternal(p.pid, syscall.SIGKILL)
+	}()
+	return nil
+}
+
+// Wait waits for the process to exit. Its exit code isn't known, as only its parent can
+// collect it.
+func (p *externalProcess) Wait() (*Result, error) {
+	start := time.Now()
+	for signalExternal(p.pid, 0) == nil {
+		time.Sleep(killPollInterval)
+	}
+	return &Result{ExitCode: -1, Duration: time.Since(start)}, nil
+}
+
+func (p *externalProcess) State() (ProcessState, error) {
+	if signalExternal(p.pid, 0) != nil {
+		return ProcessStateExited, nil
+	}
+	return ProcessStateRunning, nil
+}
+
+// remoteProcessPoll is how often a remote process is checked for having exited, each
+// check being a round trip to the remote side
+const remoteProcessPoll = time.Second
+
+// remoteProcess is a handle to a process on the remote side of a remote executor, which
+// it signals with kill(1)
+type remoteProcess struct {
+	pid      int
+	executor *remoteExecutor
+}
+
+// signalName returns the name kill(1) knows sig by
+func signalName(sig syscall.Signal) string {
+	switch sig {
+	case 0:
+		return "0"
+	case syscall.SIGINT:
+		return "INT"
+	case syscall.SIGKILL:
+		return "KILL"
+	default:
+		return "TERM"
+	}
+}
+
+// kill sends sig to the process, and to the rest of its process group when it leads one
+func (p *remoteProcess) kill(sig syscall.Signal) error {
+	pid := strconv.Itoa(p.pid)
+	_, err := p.executor.remoteOutput(context.Background(),
+		fmt.Sprintf(`if [ "$(ps -o pgid= -p %s | tr -d ' ')" = %s ]; then kill -%s -- -%s; else kill -%s %s; fi`,
+			pid, pid, signalName(sig), pid, signalName(sig), pid))
+	return err
+}
+
+func (p *remoteProcess) PID() int {
+	return p.pid
+}
+
+func (p *remoteProcess) Signal(sig int) error {
+	return p.kill(syscall.Signal(sig))
+}
+
+// Kill terminates the process, forcibly once the default grace period has passed
+func (p *remoteProcess) Kill() error {
+	if err := p.kill(syscall.SIGTERM); err != nil {
+		return err
+	}
+	go func() {
+		deadline := time.Now().Add(defaultKillGrace)
+		for time.Now().Before(deadline) {
+			time.Sleep(remoteProcessPoll)
+			if p.kill(0) != nil {
+				return
+			}
+		}
+		p.kill(syscall.SIGKILL)
+	}()
+	return nil
+}
+
+// Wait waits for the process to exit. Its exit code isn't known.
+func (p *remoteProcess) Wait() (*Result, error) {
+	start := time.Now()
+	for p.kill(0) == nil {
+		time.Sleep(remoteProcessPoll)
+	}
+	return &Result{ExitCode: -1, Duration: time.Since(start)}, nil
+}
+
+func (p *remoteProcess) State() (ProcessState, error) {
+	if p.kill(0) != nil {
+		return ProcessStateExited, nil
+	}
+	return ProcessStateRunning, nil
+}
@@ -0,0 +1,90 @@
+//go:build !windows
+
+package executor
+
+import (
+	"context"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// saveOnInterrupt is a shell script that saves and exits on SIGINT, as agents do
+const saveOnInterrupt = "trap 'echo saved; exit 0' INT; echo ready; while :; do sleep 0.05; done"
+
+// waitForOutput waits up to a second for a process to write want
+func waitForOutput(t *testing.T, out *syncBuffer, want string) {
+	t.Helper()
+	require.Eventually(t, func() bool { return out.String() == want }, time.Second, 10*time.Millisecond)
+}
+
+func TestCancelDrains(t *testing.T) {
+	e := NewExecutor(&ExecutorOptions{CancelDrainTimeout: 5 * time.Second})
+	ctx := context.Background()
+
+	var out syncBuffer
+	handle, err := e.Start(ctx, Command{Program: "sh", Args: []string{"-c", saveOnInterrupt}, Stdout: &out})
+	require.NoError(t, err)
+	waited := make(chan *Result, 1)
+	go func() {
+		result, _ := e.Wait(ctx, handle)
+		waited <- result
+	}()
+	waitForOutput(t, &out, "ready\n")
+
+	start := time.Now()
+	require.NoError(t, e.Cancel(ctx, handle))
+	assert.Less(t, time.Since(start), 2*time.Second)
+	assert.Equal(t, "ready\nsaved\n", out.String())
+	assert.Zero(t, (<-waited).ExitCode)
+}
+
+func TestCancelEscalates(t *testing.T) {
+	e := NewExecutor(&ExecutorOptions{CancelDrainTimeout: 100 * time.Millisecond, KillGracePeriod: 100 * time.Millisecond})
+	ctx := context.Background()
+
+	var out syncBuffer
+	handle, err := e.Start(ctx, Command{Program: "sh", Args: []string{"-c", "trap '' INT; echo ready; sleep 30"}, Stdout: &out})
+	require.NoError(t, err)
+	waited := make(chan *Result, 1)
+	go func() {
+		result, _ := e.Wait(ctx, handle)
+		waited <- result
+	}()
+	waitForOutput(t, &out, "ready\n")
+
+	require.NoError(t, e.Cancel(ctx, handle))
+	select {
+	case result := <-waited:
+		assert.NotZero(t, result.ExitCode)
+	case <-time.After(2 * time.Second):
+		t.Fatal("process ignoring SIGINT wasn't killed")
+	}
+}
+
+func TestCancelExternalProcess(t *testing.T) {
+	e := NewExecutor(nil)
+	ctx := context.Background()
+
+	// A process the executor didn't start, like the program in a tmux pane
+	var out syncBuffer
+	cmd := exec.Command("sh", "-c", saveOnInterrupt)
+	cmd.Stdout = &out
+	require.NoError(t, cmd.Start())
+	go cmd.Wait()
+	waitForOutput(t, &out, "ready\n")
+
+	handle, err := e.FindProcess(ctx, cmd.Process.Pid)
+	require.NoError(t, err)
+	require.NoError(t, e.Cancel(ctx, handle))
+	assert.Equal(t, "ready\nsaved\n", out.String())
+	state, err := handle.State()
+	require.NoError(t, err)
+	assert.Equal(t, ProcessStateExited, state)
+
+	_, err = e.FindProcess(ctx, cmd.Process.Pid)
+	assert.Error(t, err)
+}
@@ -0,0 +1,196 @@
+package executor
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ContainerOptions configures the container each session's program runs in
+type ContainerOptions struct {
+	// Runtime is the container CLI, "docker" when empty. Anything accepting docker's run
+	// flags works, e.g. podman.
+	Runtime string
+	// Image is the image the program runs in. It must contain the program.
+	Image string
+	// Memory caps the container's memory, in docker's notation, e.g. "4g". Unlimited when empty.
+	Memory string
+	// CPUs caps the number of CPUs the container may use, e.g. "1.5". Unlimited when empty.
+	CPUs string
+	// PidsLimit caps the number of processes in the container, when non-zero
+	PidsLimit int
+	// Network is the network the container joins, e.g. "none" to cut it off. Docker's
+	// default network when empty.
+	Network string
+	// Env are the names of host environment variables passed into the container, such as
+	// API keys the program needs
+	Env []string
+}
+
+// containerExecutor runs the program of every tmux session inside a container, with only
+// the session's worktree mounted, so an agent can't touch the rest of the host. Everything
+// else, including git and tmux itself, runs through the local executor: tmux keeps the
+// container's terminal, and the worktree stays a normal checkout on the host.
+type containerExecutor struct {
+	CommandExecutor
+	opts ContainerOptions
+}
+
+// NewContainerExecutor creates an executor that sandboxes session programs in containers,
+// running everything through local
+func NewContainerExecutor(local CommandExecutor, opts ContainerOptions) CommandExecutor {
+	if opts.Runtime == "" {
+		opts.Runtime = "docker"
+	}
+	return &containerExecutor{CommandExecutor: local, opts: opts}
+}
+
+// ContainerName returns the name of the container running the tmux session with the given
+// name
+func ContainerName(tmuxSession string) string {
+	return "cs-" + tmuxSession
+}
+
+// tmuxValueFlags are the new-session flags that take a value
+const tmuxValueFlags = "cstnxyeF"
+
+// parseNewSession splits the arguments of `tmux new-session` into the flags, the session
+// name and start directory, and the shell command it runs, if any
+func parseNewSession(args []string) (flags []string, name, dir, command string) {
+	for i := 1; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			return flags, name, dir, strings.Join(args[i+1:], " ")
+		}
+		if !strings.HasPrefix(arg, "-") {
+			return flags, name, dir, strings.Join(args[i:], " ")
+		}
+		flags = append(flags, arg)
+		if len(arg) == 2 && strings.ContainsRune(tmuxValueFlags, rune(arg[1])) && i+1 < len(args) {
+			i++
+			flags = append(flags, args[i])
+			switch arg {
+			case "-s":
+				name = args[i]
+			case "-c":
+				dir = args[i]
+			}
+		}
+	}
+	return flags, name, dir, ""
+}
+
+// gitDirMount returns the repository directory a worktree's git metadata lives in, which
+// has to be mounted too for git to work inside the container, or "" for a plain checkout
+func gitDirMount(dir string) string {
+	data, err := os.ReadFile(filepath.Join(dir, ".git"))
+	if err != nil {
+		return ""
+	}
+	gitDir, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir: ")
+	if !ok {
+		return ""
+	}
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(dir, gitDir)
+	}
+	// <repo>/.git/worktrees/<name> -> <repo>/.git
+	if filepath.Base(filepath.Dir(gitDir)) == "worktrees" {
+		return filepath.Dir(filepath.Dir(gitDir))
+	}
+	return gitDir
+}
+
+// runCommand returns the shell command that runs command in a container named name, in
+// dir mounted at the same path so paths printed by the program are valid on the host
+func (e *containerExecutor) runCommand(name, dir, command string) string {
+	args := []string{e.opts.Runtime, "run", "--rm", "-it", "--init", "--name", ContainerName(name),
+		"--user", strconv.Itoa(os.Getuid()) + ":" + strconv.Itoa(os.Getgid())}
+	if dir != "" {
+		args = append(args, "-v", dir+":"+dir, "-w", dir)
+		if gitDir := gitDirMount(dir); gitDir != "" {
+			args = append(args, "-v", gitDir+":"+gitDir)
+		}
+	}
+	if e.opts.Memory != "" {
+		args = append(args, "--memory", e.opts.Memory)
+	}
+	if e.opts.CPUs != "" {
+		args = append(args, "--cpus", e.opts.CPUs)
+	}
+	if e.opts.PidsLimit != 0 {
+		args = append(args, "--pids-limit", strconv.Itoa(e.opts.PidsLimit))
+	}
+	if e.opts.Network != "" {
+		args = append(args, "--network", e.opts.Network)
+	}
+	for _, env := range e.opts.Env {
+		args = append(args, "-e", env)
+	}
+	args = append(args, e.opts.Image)
+	if command != "" {
+		args = append(args, "sh", "-c", command)
+	}
+
+	return QuoteArgs(args...)
+}
+
+// sandbox rewrites a tmux command that starts a session to run its program in a container
+func (e *containerExecutor) sandbox(cmd Command) Command {
+	if cmd.Program != "tmux" || len(cmd.Args) == 0 || cmd.Args[0] != "new-session" {
+		return cmd
+	}
+	flags, name, dir, command := parseNewSession(cmd.Args)
+	if name == "" {
+		return cmd
+	}
+	args := append([]string{"new-session"}, flags...)
+	cmd.Args = append(args, e.runCommand(name, dir, command))
+	return cmd
+}
+
+// removeContainer force-removes the container of a tmux session being killed, since the
+// container outlives the docker client tmux kills
+func (e *containerExecutor) removeContainer(ctx context.Context, cmd Command) {
+	if cmd.Program != "tmux" || len(cmd.Args) != 3 || cmd.Args[0] != "kill-session" || cmd.Args[1] != "-t" {
+		return
+	}
+	e.CommandExecutor.Execute(ctx, Command{
+		Program: e.opts.Runtime,
+		Args:    []string{"rm", "-f", ContainerName(cmd.Args[2])},
+		Timeout: cmd.Timeout,
+	})
+}
+
+// Basic execution
+
+func (e *containerExecutor) Execute(ctx context.Context, cmd Command) (*Result, error) {
+	result, err := e.CommandExecutor.Execute(ctx, e.sandbox(cmd))
+	e.removeContainer(ctx, cmd)
+	return result, err
+}
+
+func (e *containerExecutor) ExecuteWithInput(ctx context.Context, cmd Command, input []byte) (*Result, error) {
+	result, err := e.CommandExecutor.ExecuteWithInput(ctx, e.sandbox(cmd), input)
+	e.removeContainer(ctx, cmd)
+	return result, err
+}
+
+// Streaming execution
+
+func (e *containerExecutor) ExecuteStreaming(ctx context.Context, cmd Command) (<-chan Output, error) {
+	return e.CommandExecutor.ExecuteStreaming(ctx, e.sandbox(cmd))
+}
+
+func (e *containerExecutor) ExecuteInteractive(ctx context.Context, cmd Command) (io.ReadWriteCloser, error) {
+	return e.CommandExecutor.ExecuteInteractive(ctx, e.sandbox(cmd))
+}
+
+// Process management
+
+func (e *containerExecutor) Start(ctx context.Context, cmd Command) (ProcessHandle, error) {
+	return e.CommandExecutor.Start(ctx, e.sandbox(cmd))
+}
@@ -0,0 +1,41 @@
+package executor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseNewSession(t *testing.T) {
+	flags, name, dir, command := parseNewSession([]string{"new-session", "-d", "-s", "claudesquad_a", "-c", "/work/a", "claude", "--resume"})
+	assert.Equal(t, []string{"-d", "-s", "claudesquad_a", "-c", "/work/a"}, flags)
+	assert.Equal(t, "claudesquad_a", name)
+	assert.Equal(t, "/work/a", dir)
+	assert.Equal(t, "claude --resume", command)
+
+	_, _, _, command = parseNewSession([]string{"new-session", "-d", "-s", "b"})
+	assert.Empty(t, command)
+}
+
+func TestContainerSandbox(t *testing.T) {
+	repo := t.TempDir()
+	worktree := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(worktree, ".git"), []byte("gitdir: "+repo+"/.git/worktrees/a\n"), 0644))
+
+	e := NewContainerExecutor(NewExecutor(nil), ContainerOptions{Image: "agent:latest", Memory: "2g", Network: "none"}).(*containerExecutor)
+	cmd := e.sandbox(Command{Program: "tmux", Args: []string{"new-session", "-d", "-s", "claudesquad_a", "-c", worktree, "claude"}})
+	require.Len(t, cmd.Args, 7)
+	assert.Equal(t, []string{"new-session", "-d", "-s", "claudesquad_a", "-c", worktree}, cmd.Args[:6])
+	run := cmd.Args[6]
+	assert.Contains(t, run, "docker run --rm -it --init --name cs-claudesquad_a")
+	assert.Contains(t, run, "-v "+worktree+":"+worktree+" -w "+worktree)
+	assert.Contains(t, run, "-v "+repo+"/.git:"+repo+"/.git")
+	assert.Contains(t, run, "--memory 2g --network none agent:latest sh -c claude")
+
+	// Everything else runs on the host unchanged
+	other := Command{Program: "git", Args: []string{"status"}, Dir: worktree}
+	assert.Equal(t, other, e.sandbox(other))
+}
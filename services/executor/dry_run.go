@@ -0,0 +1,115 @@
+package executor
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+// readOnlyCommands are the subcommands that only inspect state, which a dry run still
+// runs so that the flows it previews see the real repository and sessions. Everything
+// else is logged and skipped.
+var readOnlyCommands = map[string][]string{
+	"git": {
+		"blame", "cat-file", "describe", "diff", "diff-index", "diff-tree", "for-each-ref",
+		"log", "ls-files", "ls-remote", "merge-base", "name-rev", "rev-list", "rev-parse",
+		"shortlog", "show", "show-ref", "status", "version",
+	},
+	"tmux": {
+		"capture-pane", "display", "display-message", "has-session", "list-buffers",
+		"list-clients", "list-panes", "list-sessions", "list-windows", "ls", "show-options",
+	},
+}
+
+// gitReadOnlyForms are git subcommands that only inspect state with one of these arguments
+var gitReadOnlyForms = map[string][]string{
+	"branch":   {"--list", "--show-current", "-a", "-r", "-v", "-vv", "-rv", "-av"},
+	"config":   {"--get", "--get-all", "--get-regexp", "--list", "-l"},
+	"remote":   {"-v", "get-url", "show"},
+	"stash":    {"list", "show"},
+	"worktree": {"list"},
+}
+
+// dryRun reports whether cmd is skipped because the executor is in dry-run mode, logging
+// what would have run if it is
+func (e *execImpl) dryRun(cmd Command) bool {
+	if !e.opts.DryRun || readOnly(cmd) {
+		return false
+	}
+
+	line := e.redact(strings.TrimSpace(cmd.Program + " " + strings.Join(cmd.Args, " ")))
+	if cmd.Dir != "" {
+		line += " (in " + e.redact(cmd.Dir) + ")"
+	}
+	out := e.opts.DryRunOutput
+	if out == nil {
+		out = os.Stderr
+	}
+	fmt.Fprintf(out, "would run: %s\n", line)
+	if e.opts.Logger != nil {
+		e.opts.Logger.Info("Dry run, skipping command: %s", line)
+	}
+	return true
+}
+
+// readOnly reports whether cmd is a git or tmux command that only inspects state
+func readOnly(cmd Command) bool {
+	subcommands, ok := readOnlyCommands[cmd.Program]
+	if !ok {
+		return false
+	}
+	args := skipGlobalFlags(cmd.Program, cmd.Args)
+	if len(args) == 0 {
+		return false
+	}
+	if slices.Contains(subcommands, args[0]) {
+		return true
+	}
+	if cmd.Program == "git" {
+		if forms, ok := gitReadOnlyForms[args[0]]; ok && len(args) > 1 {
+			return slices.Contains(forms, args[1])
+		}
+	}
+	return false
+}
+
+// skipGlobalFlags returns args without the options given before the subcommand, such as
+// git's -C <path> or tmux's -L <socket>
+func skipGlobalFlags(program string, args []string) []string {
+	withValue := []string{"-C", "-c"}
+	if program == "tmux" {
+		withValue = []string{"-L", "-S", "-f"}
+	}
+	for len(args) > 0 && strings.HasPrefix(args[0], "-") {
+		if slices.Contains(withValue, args[0]) && len(args) > 1 {
+			args = args[1:]
+		}
+		args = args[1:]
+	}
+	return args
+}
+
+// dryRunHandle is the handle of a process Start skipped in dry-run mode, which has
+// already exited successfully
+type dryRunHandle struct {
+	start time.Time
+}
+
+func (h *dryRunHandle) PID() int                     { return 0 }
+func (h *dryRunHandle) Signal(sig int) error         { return nil }
+func (h *dryRunHandle) Kill() error                  { return nil }
+func (h *dryRunHandle) State() (ProcessState, error) { return ProcessStateExited, nil }
+func (h *dryRunHandle) Wait() (*Result, error) {
+	return &Result{Duration: time.Since(h.start)}, nil
+}
+
+// dryRunPipe stands in for the pipes of an interactive command skipped in dry-run mode.
+// It has no output and discards input.
+type dryRunPipe struct{}
+
+func (dryRunPipe) Read(b []byte) (int, error)  { return 0, io.EOF }
+func (dryRunPipe) Write(b []byte) (int, error) { return len(b), nil }
+func (dryRunPipe) Close() error                { return nil }
@@ -0,0 +1,81 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadOnly(t *testing.T) {
+	tests := []struct {
+		cmd      Command
+		readOnly bool
+	}{
+		{Command{Program: "git", Args: []string{"-C", "/repo", "status", "--porcelain"}}, true},
+		{Command{Program: "git", Args: []string{"-C", "/repo", "--no-pager", "diff", "HEAD"}}, true},
+		{Command{Program: "git", Args: []string{"-C", "/repo", "worktree", "list", "--porcelain"}}, true},
+		{Command{Program: "git", Args: []string{"-C", "/repo", "branch", "--show-current"}}, true},
+		{Command{Program: "git", Args: []string{"-C", "/repo", "worktree", "remove", "--force", "/wt"}}, false},
+		{Command{Program: "git", Args: []string{"-C", "/repo", "branch", "-D", "fix"}}, false},
+		{Command{Program: "git", Args: []string{"-C", "/repo", "branch", "fix"}}, false},
+		{Command{Program: "git", Args: []string{"config", "user.name", "me"}}, false},
+		{Command{Program: "tmux", Args: []string{"has-session", "-t", "cs_fix"}}, true},
+		{Command{Program: "tmux", Args: []string{"-L", "cs", "list-sessions"}}, true},
+		{Command{Program: "tmux", Args: []string{"kill-session", "-t", "cs_fix"}}, false},
+		{Command{Program: "rm", Args: []string{"-rf", "/wt"}}, false},
+		{Command{Program: "git"}, false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.readOnly, readOnly(tt.cmd), "%s %v", tt.cmd.Program, tt.cmd.Args)
+	}
+}
+
+func TestDryRun(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	ctx := context.Background()
+	var out bytes.Buffer
+	e := NewExecutor(&ExecutorOptions{DefaultTimeout: 10 * time.Second, CaptureOutput: true, DryRun: true, DryRunOutput: &out})
+	dir := t.TempDir()
+	victim := filepath.Join(dir, "victim")
+	require.NoError(t, os.WriteFile(victim, nil, 0644))
+
+	// Commands that change anything are only printed, and succeed
+	result, err := e.Execute(ctx, Command{Program: "rm", Args: []string{victim}, Dir: dir})
+	require.NoError(t, err)
+	assert.Equal(t, 0, result.ExitCode)
+	assert.FileExists(t, victim)
+	assert.Equal(t, "would run: rm "+victim+" (in "+dir+")\n", out.String())
+
+	ch, err := e.ExecuteStreaming(ctx, Command{Program: "rm", Args: []string{victim}})
+	require.NoError(t, err)
+	var outputs []Output
+	for output := range ch {
+		outputs = append(outputs, output)
+	}
+	require.Len(t, outputs, 1)
+	assert.Equal(t, OutputTypeExit, outputs[0].Type)
+	assert.Equal(t, "0", string(outputs[0].Data))
+
+	handle, err := e.Start(ctx, Command{Program: "rm", Args: []string{victim}})
+	require.NoError(t, err)
+	result, err = handle.Wait()
+	require.NoError(t, err)
+	assert.Equal(t, 0, result.ExitCode)
+	assert.FileExists(t, victim)
+
+	// Commands that only inspect state still run
+	out.Reset()
+	result, err = e.Execute(ctx, Command{Program: "git", Args: []string{"version"}})
+	require.NoError(t, err)
+	assert.Contains(t, string(result.Stdout), "git version")
+	assert.Empty(t, out.String())
+}
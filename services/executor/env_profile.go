@@ -0,0 +1,135 @@
+package executor
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/zalando/go-keyring"
+)
+
+// EnvProfile is a named set of environment variables for commands, e.g. the API keys an
+// agent needs. Each variable maps to where its value comes from: "env:NAME" copies $NAME,
+// "keychain:ENTRY" reads ENTRY of the claude-squad service in the OS keychain, and
+// anything else is the value itself. Values read from the environment or keychain are
+// masked wherever the executor logs or audits a command.
+type EnvProfile map[string]string
+
+// keychainService is the OS keychain service profile values are read from
+const keychainService = "claude-squad"
+
+// envProfileName returns the profile cmd runs with: its own, or the one named after its
+// program. The program of a tmux session is the one it runs rather than tmux.
+func (e *execImpl) envProfileName(cmd Command) string {
+	if cmd.EnvProfile != "" {
+		return cmd.EnvProfile
+	}
+	program := cmd.Program
+	if i := newSessionIndex(cmd); i >= 0 {
+		_, _, _, command := parseNewSession(cmd.Args[i:])
+		if fields := strings.Fields(command); len(fields) > 0 {
+			program = fields[0]
+		}
+	}
+	if _, ok := e.opts.EnvProfiles[programName(program)]; ok {
+		return programName(program)
+	}
+	return ""
+}
+
+// newSessionIndex returns the index of the new-session subcommand in the arguments of a
+// tmux command, or -1 if it isn't one
+func newSessionIndex(cmd Command) int {
+	if programName(cmd.Program) != "tmux" {
+		return -1
+	}
+	args := skipGlobalFlags(cmd.Program, cmd.Args)
+	if len(args) == 0 || args[0] != "new-session" {
+		return -1
+	}
+	return len(cmd.Args) - len(args)
+}
+
+// applyEnvProfile adds the variables of cmd's profile to it. A tmux session gets them
+// through new-session's -e flags, since panes don't inherit the client's environment.
+func (e *execImpl) applyEnvProfile(cmd *Command) error {
+	name := e.envProfileName(*cmd)
+	if name == "" {
+		return nil
+	}
+	vars, err := e.envProfileVars(name)
+	if err != nil {
+		return err
+	}
+
+	if i := newSessionIndex(*cmd); i >= 0 {
+		args := slices.Clone(cmd.Args[:i+1])
+		for _, v := range vars {
+			args = append(args, "-e", v)
+		}
+		cmd.Args = append(args, cmd.Args[i+1:]...)
+		return nil
+	}
+	env := cmd.Env
+	if env == nil {
+		env = e.opts.DefaultEnv
+	}
+	cmd.Env = append(slices.Clone(env), vars...)
+	return nil
+}
+
+// envProfileVars returns the variables of the named profile as NAME=value pairs, reading
+// their values the first time and remembering the secret ones to mask
+func (e *execImpl) envProfileVars(name string) ([]string, error) {
+	e.envMu.Lock()
+	defer e.envMu.Unlock()
+	if vars, ok := e.envProfiles[name]; ok {
+		return vars, nil
+	}
+
+	profile, ok := e.opts.EnvProfiles[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown env profile %q", name)
+	}
+	names := make([]string, 0, len(profile))
+	for variable := range profile {
+		names = append(names, variable)
+	}
+	sort.Strings(names)
+
+	var vars []string
+	for _, variable := range names {
+		value, secret, err := resolveEnvValue(profile[variable])
+		if err != nil {
+			return nil, fmt.Errorf("env profile %q: %s: %w", name, variable, err)
+		}
+		if secret && value != "" {
+			e.secrets = append(e.secrets, value)
+		}
+		vars = append(vars, variable+"="+value)
+	}
+	e.envProfiles[name] = vars
+	return vars, nil
+}
+
+// resolveEnvValue returns the value source refers to, and whether it is a secret
+func resolveEnvValue(source string) (value string, secret bool, err error) {
+	switch {
+	case strings.HasPrefix(source, "env:"):
+		value, ok := os.LookupEnv(strings.TrimPrefix(source, "env:"))
+		if !ok {
+			return "", false, fmt.Errorf("$%s is not set", strings.TrimPrefix(source, "env:"))
+		}
+		return value, true, nil
+	case strings.HasPrefix(source, "keychain:"):
+		value, err := keyring.Get(keychainService, strings.TrimPrefix(source, "keychain:"))
+		if err != nil {
+			return "", false, fmt.Errorf("failed to read %s from the keychain: %w", strings.TrimPrefix(source, "keychain:"), err)
+		}
+		return value, true, nil
+	default:
+		return source, false, nil
+	}
+}
@@ -0,0 +1,60 @@
+package executor
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"claude-squad/log"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zalando/go-keyring"
+)
+
+func TestEnvProfiles(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("needs a POSIX shell")
+	}
+	keyring.MockInit()
+	require.NoError(t, keyring.Set(keychainService, "anthropic", "sk-from-keychain"))
+	t.Setenv("CS_TEST_TOKEN", "token-from-env")
+
+	ctx := context.Background()
+	e := NewExecutor(&ExecutorOptions{
+		DefaultTimeout: 10 * time.Second,
+		CaptureOutput:  true,
+		EnvProfiles: map[string]EnvProfile{
+			"claude": {"ANTHROPIC_API_KEY": "keychain:anthropic", "MODE": "fast"},
+			"ci":     {"TOKEN": "env:CS_TEST_TOKEN"},
+			"broken": {"TOKEN": "env:CS_TEST_UNSET"},
+		},
+	}).(*execImpl)
+
+	result, err := e.Execute(ctx, Command{Program: "sh", Args: []string{"-c", `echo "$TOKEN"`}, EnvProfile: "ci"})
+	require.NoError(t, err)
+	assert.Equal(t, "token-from-env\n", string(result.Stdout))
+
+	// Secret values are masked, literal ones aren't
+	assert.Equal(t, "got "+log.RedactedPlaceholder+" in fast mode", e.redact("got token-from-env in fast mode"))
+
+	// A session running claude gets its profile through tmux
+	cmd := Command{Program: "tmux", Args: []string{"new-session", "-d", "-s", "cs_fix", "-c", "/repo", "claude --continue"}}
+	require.NoError(t, e.applyEnvProfile(&cmd))
+	assert.Equal(t, []string{"new-session", "-e", "ANTHROPIC_API_KEY=sk-from-keychain", "-e", "MODE=fast",
+		"-d", "-s", "cs_fix", "-c", "/repo", "claude --continue"}, cmd.Args)
+	assert.NotContains(t, e.redact(strings.Join(cmd.Args, " ")), "sk-from-keychain")
+
+	// Other commands get no profile
+	cmd = Command{Program: "tmux", Args: []string{"new-session", "-d", "-s", "cs_fix", "aider"}}
+	require.NoError(t, e.applyEnvProfile(&cmd))
+	assert.Len(t, cmd.Args, 5)
+	assert.Nil(t, cmd.Env)
+
+	_, err = e.Execute(ctx, Command{Program: "sh", Args: []string{"-c", "true"}, EnvProfile: "broken"})
+	assert.ErrorContains(t, err, "CS_TEST_UNSET is not set")
+	_, err = e.Execute(ctx, Command{Program: "sh", Args: []string{"-c", "true"}, EnvProfile: "missing"})
+	assert.ErrorContains(t, err, `unknown env profile "missing"`)
+}
@@ -3,21 +3,38 @@ package executor
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
+	"strings"
 	"sync"
 	"syscall"
 	"time"
+
+	"claude-squad/log"
 )
 
 // execImpl is the concrete implementation of CommandExecutor
 type execImpl struct {
-	opts           *ExecutorOptions
-	runningProcs   map[ProcessHandle]*processInfo
-	procMutex      sync.RWMutex
-	concurrentSem  chan struct{}
+	opts          *ExecutorOptions
+	runningProcs  map[ProcessHandle]*processInfo
+	procMutex     sync.RWMutex
+	concurrentSem chan struct{}
+	pools         map[string]chan struct{}
+	rateLimiters  map[string]*tokenBucket
+	cache         *resultCache // nil unless results are cached
+
+	// cpuSamples holds the CPU time each process had used when its usage was last sampled
+	usageMu    sync.Mutex
+	cpuSamples map[int]cpuSample
+
+	// envProfiles holds the variables of the env profiles used so far, and secrets the
+	// values among them to mask
+	envMu       sync.Mutex
+	envProfiles map[string][]string
+	secrets     []string
 }
 
 // processInfo holds information about a running process
@@ -25,6 +42,9 @@ type processInfo struct {
 	cmd       *exec.Cmd
 	startTime time.Time
 	state     ProcessState
+	release   func() // releases the command's resource limits once it has exited
+	command   Command
+	done      func(err error) // counts the command as finished in the metrics
 }
 
 // processHandleImpl implements ProcessHandle
@@ -32,6 +52,10 @@ type processHandleImpl struct {
 	cmd      *exec.Cmd
 	info     *processInfo
 	executor *execImpl
+
+	// The process is waited for once, by whoever calls Wait first, e.g. the reaper
+	waitOnce sync.Once
+	result   *Result
 }
 
 // NewExecutor creates a new command executor with the given options
@@ -39,6 +63,8 @@ func NewDefaultExecutor() CommandExecutor {
 	return NewExecutor(&ExecutorOptions{
 		DefaultTimeout: 120 * time.Second,
 		MaxConcurrent:  10,
+		Pools:          DefaultPools(),
+		RateLimits:     DefaultRateLimits(),
 		CaptureOutput:  true,
 	})
 }
@@ -49,6 +75,8 @@ func NewExecutor(opts *ExecutorOptions) CommandExecutor {
 		opts = &ExecutorOptions{
 			DefaultTimeout: 120 * time.Second,
 			MaxConcurrent:  10,
+			Pools:          DefaultPools(),
+			RateLimits:     DefaultRateLimits(),
 			CaptureOutput:  true,
 		}
 	}
@@ -57,20 +85,62 @@ func NewExecutor(opts *ExecutorOptions) CommandExecutor {
 		opts.MaxConcurrent = 10
 	}
 
-	return &execImpl{
+	e := &execImpl{
 		opts:          opts,
 		runningProcs:  make(map[ProcessHandle]*processInfo),
 		concurrentSem: make(chan struct{}, opts.MaxConcurrent),
+		pools:         newPools(opts.Pools),
+		rateLimiters:  newRateLimiters(opts.RateLimits),
+		envProfiles:   make(map[string][]string),
+		cpuSamples:    make(map[int]cpuSample),
+	}
+	if opts.ResultCacheTTL > 0 {
+		e.cache = newResultCache(opts.ResultCacheTTL)
 	}
+	if opts.Reaper != nil {
+		opts.Reaper.add(e)
+	}
+	return e
 }
 
 // Basic execution
 
 func (e *execImpl) Execute(ctx context.Context, cmd Command) (*Result, error) {
-	// Acquire semaphore
+	cmd = e.viaUserShell(cmd)
+	if e.dryRun(cmd) {
+		return &Result{}, nil
+	}
+
+	key, cacheable := e.cacheKey(cmd)
+	if cacheable {
+		if result, ok := e.cache.get(key); ok {
+			if e.opts.Logger != nil {
+				e.opts.Logger.Debug("Using cached result of: %s", e.redact(fmt.Sprintf("%s %v", cmd.Program, cmd.Args)))
+			}
+			return result, nil
+		}
+	}
+	if err := e.applyEnvProfile(&cmd); err != nil {
+		return nil, err
+	}
+	if err := e.pipeSessionOutput(&cmd); err != nil {
+		return nil, err
+	}
+	sinkOut, sinkErr, closeSinks, err := outputSinks(cmd)
+	if err != nil {
+		return nil, err
+	}
+	defer closeSinks()
+
+	if err := e.waitForRate(ctx, cmd); err != nil {
+		return nil, err
+	}
+
+	// Acquire a slot in the command's pool
+	sem := e.semaphore(cmd)
 	select {
-	case e.concurrentSem <- struct{}{}:
-		defer func() { <-e.concurrentSem }()
+	case sem <- struct{}{}:
+		defer func() { <-sem }()
 	case <-ctx.Done():
 		return nil, ctx.Err()
 	}
@@ -85,107 +155,151 @@ func (e *execImpl) Execute(ctx context.Context, cmd Command) (*Result, error) {
 	execCtx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
-	// Create command
-	execCmd := exec.CommandContext(execCtx, cmd.Program, cmd.Args...)
-	if cmd.Dir != "" {
-		execCmd.Dir = cmd.Dir
-	}
-	if cmd.Env != nil {
-		execCmd.Env = append(os.Environ(), cmd.Env...)
-	} else if e.opts.DefaultEnv != nil {
-		execCmd.Env = append(os.Environ(), e.opts.DefaultEnv...)
-	}
-	if e.opts.WorkingDir != "" && cmd.Dir == "" {
-		execCmd.Dir = e.opts.WorkingDir
-	}
-
-	// Set up stdin
-	if cmd.Stdin != nil {
-		execCmd.Stdin = cmd.Stdin
-	}
-
-	// Capture output if enabled
-	var stdout, stderr bytes.Buffer
-	if e.opts.CaptureOutput {
-		execCmd.Stdout = &stdout
-		execCmd.Stderr = &stderr
-	}
+	limits := e.limitsFor(cmd)
+	policy := e.retryPolicy(cmd)
 
 	// Log command if logger is set
 	if e.opts.Logger != nil {
-		e.opts.Logger.Debug("Executing command: %s %v", cmd.Program, cmd.Args)
+		e.opts.Logger.Debug("Executing command: %s", e.redact(fmt.Sprintf("%s %v", cmd.Program, cmd.Args)))
 	}
 
 	startTime := time.Now()
+	done := e.track(cmd)
 
 	// Execute with retry logic
-	var err error
+	var stdout, stderr *limitedBuffer
 	var exitCode int
-	retries := e.opts.RetryCount
-	if retries < 0 {
-		retries = 0
-	}
-
-	for attempt := 0; attempt <= retries; attempt++ {
+	for attempt := 0; ; attempt++ {
 		if attempt > 0 {
 			if e.opts.Logger != nil {
-				e.opts.Logger.Info("Retrying command (attempt %d/%d)", attempt+1, retries+1)
+				e.opts.Logger.Info("Retrying command (attempt %d/%d)", attempt+1, policy.Count+1)
 			}
-			time.Sleep(e.opts.RetryDelay)
-		}
-
-		err = execCmd.Run()
-		exitCode = 0
-
-		if err != nil {
-			if exitErr, ok := err.(*exec.ExitError); ok {
-				if status, ok := exitErr.Sys().(syscall.WaitStatus); ok {
-					exitCode = status.ExitStatus()
-				} else {
-					exitCode = 1
-				}
-			} else {
-				exitCode = -1
+			if !sleepCtx(execCtx, policy.delay(attempt)) {
+				break
 			}
-
-			// Check if we should retry based on exit code
-			shouldRetry := false
-			for _, retryCode := range e.opts.RetryOnErrors {
-				if exitCode == retryCode {
-					shouldRetry = true
+			// Input already read by the last attempt has to be read again
+			if seeker, ok := cmd.Stdin.(io.Seeker); ok {
+				if _, err := seeker.Seek(0, io.SeekStart); err != nil {
 					break
 				}
 			}
+		}
 
-			if !shouldRetry || attempt == retries {
-				break
-			}
-		} else {
-			break // Success, no need to retry
+		stdout, stderr, exitCode, err = e.runOnce(execCtx, cmd, limits, sinkOut, sinkErr)
+		if err == nil || attempt == policy.Count || !policy.retryable(exitCode, stderr.Bytes()) {
+			break
+		}
+		// Input that can't be rewound can't be given to another attempt
+		if _, ok := cmd.Stdin.(io.Seeker); cmd.Stdin != nil && !ok {
+			break
 		}
 	}
 
 	duration := time.Since(startTime)
+	done(err)
 
 	result := &Result{
-		Stdout:   stdout.Bytes(),
-		Stderr:   stderr.Bytes(),
-		ExitCode: exitCode,
-		Duration: duration,
-		Error:    err,
+		Stdout:    stdout.Bytes(),
+		Stderr:    stderr.Bytes(),
+		ExitCode:  exitCode,
+		Duration:  duration,
+		Error:     err,
+		Truncated: stdout.truncated || stderr.truncated,
 	}
 
 	if e.opts.Logger != nil {
 		if err != nil {
-			e.opts.Logger.Error("Command failed: %v (exit code: %d)", err, exitCode)
+			e.opts.Logger.Error("Command failed: %s (exit code: %d)", e.redact(err.Error()), exitCode)
 		} else {
 			e.opts.Logger.Debug("Command succeeded in %v", duration)
 		}
 	}
+	e.audit(cmd, startTime, exitCode, err)
+
+	// Results of commands that couldn't be run or were cut short aren't kept
+	if cacheable && exitCode >= 0 {
+		e.cache.put(key, cmd.Program, result)
+	}
+	e.invalidate(cmd)
 
 	return result, nil
 }
 
+// runOnce makes one attempt at running cmd, returning its captured output and exit code.
+// The output is copied to sinkOut and sinkErr as well, when they are set.
+func (e *execImpl) runOnce(ctx context.Context, cmd Command, limits Limits, sinkOut, sinkErr io.Writer) (stdout, stderr *limitedBuffer, exitCode int, err error) {
+	// Create command
+	execCmd := exec.CommandContext(ctx, cmd.Program, cmd.Args...)
+	e.ownGroup(execCmd, cmd.Stdin)
+	if cmd.Dir != "" {
+		execCmd.Dir = cmd.Dir
+	}
+	if cmd.Env != nil {
+		execCmd.Env = append(os.Environ(), cmd.Env...)
+	} else if e.opts.DefaultEnv != nil {
+		execCmd.Env = append(os.Environ(), e.opts.DefaultEnv...)
+	}
+	if e.opts.WorkingDir != "" && cmd.Dir == "" {
+		execCmd.Dir = e.opts.WorkingDir
+	}
+
+	// Set up stdin
+	if cmd.Stdin != nil {
+		execCmd.Stdin = cmd.Stdin
+	}
+
+	release := applyLimits(execCmd, limits)
+	defer release()
+
+	watch := e.watchStall(cmd)
+	defer watch.stop()
+	sinkOut = combineWriters(sinkOut, watch.writer())
+	sinkErr = combineWriters(sinkErr, watch.writer())
+
+	// Capture output if enabled, up to the output limit
+	stdout = &limitedBuffer{limit: limits.Output}
+	stderr = &limitedBuffer{limit: limits.Output}
+	if e.opts.CaptureOutput {
+		execCmd.Stdout = combineWriters(stdout, sinkOut)
+		execCmd.Stderr = combineWriters(stderr, sinkErr)
+	} else {
+		execCmd.Stdout = sinkOut
+		execCmd.Stderr = sinkErr
+	}
+
+	err = execCmd.Run()
+	return stdout, stderr, exitCodeOf(err), err
+}
+
+// exitCodeOf returns the exit code of a command that ended with err, or -1 when it
+// couldn't be run or was killed by a signal. ExitError.ExitCode works on every platform,
+// unlike the Unix-only interpretation of syscall.WaitStatus.
+func exitCodeOf(err error) int {
+	if err == nil {
+		return 0
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	return -1
+}
+
+// redact masks credentials in s using the configured redactor, along with the secret
+// values of env profiles
+func (e *execImpl) redact(s string) string {
+	e.envMu.Lock()
+	for _, secret := range e.secrets {
+		s = strings.ReplaceAll(s, secret, log.RedactedPlaceholder)
+	}
+	e.envMu.Unlock()
+
+	if e.opts.Redactor != nil {
+		return e.opts.Redactor.Redact(s)
+	}
+	return log.Redact(s)
+}
+
 func (e *execImpl) ExecuteWithInput(ctx context.Context, cmd Command, input []byte) (*Result, error) {
 	cmd.Stdin = bytes.NewReader(input)
 	return e.Execute(ctx, cmd)
@@ -196,9 +310,31 @@ func (e *execImpl) ExecuteWithInput(ctx context.Context, cmd Command, input []by
 func (e *execImpl) ExecuteStreaming(ctx context.Context, cmd Command) (<-chan Output, error) {
 	outputCh := make(chan Output, 100)
 
-	// Acquire semaphore
+	cmd = e.viaUserShell(cmd)
+	e.invalidate(cmd)
+	if e.dryRun(cmd) {
+		stream := &outputStream{ch: outputCh}
+		stream.send(Output{Type: OutputTypeExit, Data: []byte("0")})
+		close(outputCh)
+		return outputCh, nil
+	}
+	if err := e.applyEnvProfile(&cmd); err != nil {
+		close(outputCh)
+		return outputCh, err
+	}
+	if err := e.pipeSessionOutput(&cmd); err != nil {
+		close(outputCh)
+		return outputCh, err
+	}
+	if err := e.waitForRate(ctx, cmd); err != nil {
+		close(outputCh)
+		return outputCh, err
+	}
+
+	// Acquire a slot in the command's pool
+	sem := e.semaphore(cmd)
 	select {
-	case e.concurrentSem <- struct{}{}:
+	case sem <- struct{}{}:
 		// Will be released when command completes
 	case <-ctx.Done():
 		close(outputCh)
@@ -216,6 +352,7 @@ func (e *execImpl) ExecuteStreaming(ctx context.Context, cmd Command) (<-chan Ou
 
 	// Create command
 	execCmd := exec.CommandContext(execCtx, cmd.Program, cmd.Args...)
+	e.ownGroup(execCmd, cmd.Stdin)
 	if cmd.Dir != "" {
 		execCmd.Dir = cmd.Dir
 	}
@@ -223,10 +360,13 @@ func (e *execImpl) ExecuteStreaming(ctx context.Context, cmd Command) (<-chan Ou
 		execCmd.Env = append(os.Environ(), cmd.Env...)
 	}
 
+	release := applyLimits(execCmd, e.limitsFor(cmd))
+
 	// Set up pipes for stdout and stderr
 	stdoutPipe, err := execCmd.StdoutPipe()
 	if err != nil {
-		<-e.concurrentSem
+		<-sem
+		release()
 		cancel()
 		close(outputCh)
 		return outputCh, fmt.Errorf("failed to create stdout pipe: %w", err)
@@ -234,24 +374,46 @@ func (e *execImpl) ExecuteStreaming(ctx context.Context, cmd Command) (<-chan Ou
 
 	stderrPipe, err := execCmd.StderrPipe()
 	if err != nil {
-		<-e.concurrentSem
+		<-sem
+		release()
 		cancel()
 		close(outputCh)
 		return outputCh, fmt.Errorf("failed to create stderr pipe: %w", err)
 	}
 
+	sinkOut, sinkErr, closeSinks, err := outputSinks(cmd)
+	if err != nil {
+		<-sem
+		release()
+		cancel()
+		close(outputCh)
+		return outputCh, err
+	}
+
 	// Start command
+	startTime := time.Now()
 	if err := execCmd.Start(); err != nil {
-		<-e.concurrentSem
+		<-sem
+		release()
+		closeSinks()
 		cancel()
 		close(outputCh)
 		return outputCh, fmt.Errorf("failed to start command: %w", err)
 	}
 
+	done := e.track(cmd)
+	watch := e.watchStall(cmd)
+	sinkOut = combineWriters(sinkOut, watch.writer())
+	sinkErr = combineWriters(sinkErr, watch.writer())
+
 	// Stream output in background
+	stream := &outputStream{ch: outputCh}
 	go func() {
 		defer func() {
-			<-e.concurrentSem
+			<-sem
+			watch.stop()
+			release()
+			closeSinks()
 			cancel()
 			close(outputCh)
 		}()
@@ -259,85 +421,48 @@ func (e *execImpl) ExecuteStreaming(ctx context.Context, cmd Command) (<-chan Ou
 		var wg sync.WaitGroup
 		wg.Add(2)
 
-		// Read stdout
+		// Read stdout and stderr
 		go func() {
 			defer wg.Done()
-			buf := make([]byte, 4096)
-			for {
-				n, err := stdoutPipe.Read(buf)
-				if n > 0 {
-					outputCh <- Output{
-						Type:      OutputTypeStdout,
-						Data:      append([]byte{}, buf[:n]...),
-						Timestamp: time.Now(),
-					}
-				}
-				if err != nil {
-					if err != io.EOF {
-						outputCh <- Output{
-							Type:      OutputTypeError,
-							Error:     err,
-							Timestamp: time.Now(),
-						}
-					}
-					break
-				}
-			}
+			stream.pump(teeReader(stdoutPipe, sinkOut), OutputTypeStdout, cmd.Framing)
 		}()
-
-		// Read stderr
 		go func() {
 			defer wg.Done()
-			buf := make([]byte, 4096)
-			for {
-				n, err := stderrPipe.Read(buf)
-				if n > 0 {
-					outputCh <- Output{
-						Type:      OutputTypeStderr,
-						Data:      append([]byte{}, buf[:n]...),
-						Timestamp: time.Now(),
-					}
-				}
-				if err != nil {
-					if err != io.EOF {
-						outputCh <- Output{
-							Type:      OutputTypeError,
-							Error:     err,
-							Timestamp: time.Now(),
-						}
-					}
-					break
-				}
-			}
+			stream.pump(teeReader(stderrPipe, sinkErr), OutputTypeStderr, cmd.Framing)
 		}()
 
 		wg.Wait()
 
 		// Wait for command to finish
 		err := execCmd.Wait()
-		exitCode := 0
-		if err != nil {
-			if exitErr, ok := err.(*exec.ExitError); ok {
-				if status, ok := exitErr.Sys().(syscall.WaitStatus); ok {
-					exitCode = status.ExitStatus()
-				}
-			}
-		}
+		exitCode := exitCodeOf(err)
 
-		outputCh <- Output{
-			Type:      OutputTypeExit,
-			Data:      []byte(fmt.Sprintf("%d", exitCode)),
-			Timestamp: time.Now(),
-			Error:     err,
-		}
+		done(err)
+		e.audit(cmd, startTime, exitCode, err)
+
+		stream.send(Output{
+			Type:  OutputTypeExit,
+			Data:  []byte(fmt.Sprintf("%d", exitCode)),
+			Error: err,
+		})
 	}()
 
 	return outputCh, nil
 }
 
 func (e *execImpl) ExecuteInteractive(ctx context.Context, cmd Command) (io.ReadWriteCloser, error) {
+	cmd = e.viaUserShell(cmd)
+	e.invalidate(cmd)
+	if e.dryRun(cmd) {
+		return dryRunPipe{}, nil
+	}
+	if err := e.applyEnvProfile(&cmd); err != nil {
+		return nil, err
+	}
+
 	// Create command
 	execCmd := exec.CommandContext(ctx, cmd.Program, cmd.Args...)
+	e.ownGroup(execCmd, cmd.Stdin)
 	if cmd.Dir != "" {
 		execCmd.Dir = cmd.Dir
 	}
@@ -368,27 +493,62 @@ func (e *execImpl) ExecuteInteractive(ctx context.Context, cmd Command) (io.Read
 
 	// Create a combined reader/writer
 	return &interactivePipe{
-		stdin:  stdin,
-		stdout: stdout,
-		stderr: stderr,
-		cmd:    execCmd,
+		stdin:    stdin,
+		stdout:   stdout,
+		stderr:   stderr,
+		cmd:      execCmd,
+		executor: e,
 	}, nil
 }
 
 // Process management
 
 func (e *execImpl) Start(ctx context.Context, cmd Command) (ProcessHandle, error) {
+	cmd = e.viaUserShell(cmd)
+	e.invalidate(cmd)
+	if e.dryRun(cmd) {
+		return &dryRunHandle{start: time.Now()}, nil
+	}
+	if err := e.applyEnvProfile(&cmd); err != nil {
+		return nil, err
+	}
+
 	// Create command
 	execCmd := exec.CommandContext(ctx, cmd.Program, cmd.Args...)
+	e.ownGroup(execCmd, cmd.Stdin)
 	if cmd.Dir != "" {
 		execCmd.Dir = cmd.Dir
 	}
 	if cmd.Env != nil {
 		execCmd.Env = append(os.Environ(), cmd.Env...)
 	}
+	if err := e.pipeSessionOutput(&cmd); err != nil {
+		return nil, err
+	}
+	sinkOut, sinkErr, closeSinks, err := outputSinks(cmd)
+	if err != nil {
+		return nil, err
+	}
+	// Only output that goes somewhere can be watched for the process going silent
+	var watch *stallWatch
+	if sinkOut != nil || sinkErr != nil {
+		watch = e.watchStall(cmd)
+		sinkOut = combineWriters(sinkOut, watch.writer())
+		sinkErr = combineWriters(sinkErr, watch.writer())
+	}
+	execCmd.Stdin = cmd.Stdin
+	execCmd.Stdout = sinkOut
+	execCmd.Stderr = sinkErr
+	limitsRelease := applyLimits(execCmd, e.limitsFor(cmd))
+	release := func() {
+		watch.stop()
+		limitsRelease()
+		closeSinks()
+	}
 
 	// Start command
 	if err := execCmd.Start(); err != nil {
+		release()
 		return nil, fmt.Errorf("failed to start process: %w", err)
 	}
 
@@ -396,6 +556,9 @@ func (e *execImpl) Start(ctx context.Context, cmd Command) (ProcessHandle, error
 		cmd:       execCmd,
 		startTime: time.Now(),
 		state:     ProcessStateRunning,
+		release:   release,
+		command:   cmd,
+		done:      e.track(cmd),
 	}
 
 	handle := &processHandleImpl{
@@ -425,6 +588,18 @@ func (e *execImpl) Wait(ctx context.Context, handle ProcessHandle) (*Result, err
 }
 
 func (e *execImpl) GetProcessInfo(ctx context.Context, handle ProcessHandle) (*ProcessInfo, error) {
+	// Processes the executor didn't start, such as the programs in tmux panes, are
+	// described from what the system reports
+	if external, ok := handle.(*externalProcess); ok {
+		state, _ := external.State()
+		process := &ProcessInfo{PID: external.pid, State: state}
+		if args := processArgs(external.pid); len(args) > 0 {
+			process.Command, process.Args = args[0], args
+		}
+		e.withUsage(process)
+		return process, nil
+	}
+
 	e.procMutex.RLock()
 	info, exists := e.runningProcs[handle]
 	e.procMutex.RUnlock()
@@ -433,19 +608,19 @@ func (e *execImpl) GetProcessInfo(ctx context.Context, handle ProcessHandle) (*P
 		return nil, fmt.Errorf("process not found")
 	}
 
-	return &ProcessInfo{
+	process := &ProcessInfo{
 		PID:       info.cmd.Process.Pid,
 		StartTime: info.startTime,
 		State:     info.state,
 		Command:   info.cmd.Path,
 		Args:      info.cmd.Args,
-	}, nil
+	}
+	e.withUsage(process)
+	return process, nil
 }
 
 func (e *execImpl) ListProcesses(ctx context.Context) ([]*ProcessInfo, error) {
 	e.procMutex.RLock()
-	defer e.procMutex.RUnlock()
-
 	processes := make([]*ProcessInfo, 0, len(e.runningProcs))
 	for _, info := range e.runningProcs {
 		processes = append(processes, &ProcessInfo{
@@ -456,7 +631,9 @@ func (e *execImpl) ListProcesses(ctx context.Context) ([]*ProcessInfo, error) {
 			Args:      info.cmd.Args,
 		})
 	}
+	e.procMutex.RUnlock()
 
+	e.withUsage(processes...)
 	return processes, nil
 }
 
@@ -470,7 +647,7 @@ func (e *execImpl) FindProcess(ctx context.Context, pid int) (ProcessHandle, err
 		}
 	}
 
-	return nil, fmt.Errorf("process with PID %d not found", pid)
+	return e.findExternal(pid)
 }
 
 // Utilities
@@ -499,11 +676,13 @@ func (h *processHandleImpl) PID() int {
 }
 
 func (h *processHandleImpl) Signal(sig int) error {
-	return h.cmd.Process.Signal(syscall.Signal(sig))
+	return signalProcess(h.cmd, syscall.Signal(sig))
 }
 
+// Kill terminates the process along with everything it started, forcibly once the grace
+// period has passed
 func (h *processHandleImpl) Kill() error {
-	err := h.cmd.Process.Kill()
+	err := h.executor.terminate(h.cmd)
 
 	// Update state
 	h.executor.procMutex.Lock()
@@ -516,16 +695,19 @@ func (h *processHandleImpl) Kill() error {
 }
 
 func (h *processHandleImpl) Wait() (*Result, error) {
+	h.waitOnce.Do(func() {
+		h.result = h.wait()
+	})
+	return h.result, nil
+}
+
+// wait waits for the process to exit and unregisters it
+func (h *processHandleImpl) wait() *Result {
 	err := h.cmd.Wait()
+	h.info.release()
+	h.info.done(err)
 
-	exitCode := 0
-	if err != nil {
-		if exitErr, ok := err.(*exec.ExitError); ok {
-			if status, ok := exitErr.Sys().(syscall.WaitStatus); ok {
-				exitCode = status.ExitStatus()
-			}
-		}
-	}
+	exitCode := exitCodeOf(err)
 
 	// Update state and remove from running processes
 	h.executor.procMutex.Lock()
@@ -534,19 +716,21 @@ func (h *processHandleImpl) Wait() (*Result, error) {
 		delete(h.executor.runningProcs, h)
 	}
 	h.executor.procMutex.Unlock()
+	h.executor.forgetUsage(h.cmd.Process.Pid)
+	h.executor.audit(h.info.command, h.info.startTime, exitCode, err)
 
 	return &Result{
 		ExitCode: exitCode,
 		Duration: time.Since(h.info.startTime),
 		Error:    err,
-	}, nil
+	}
 }
 
 func (h *processHandleImpl) State() (ProcessState, error) {
 	h.executor.procMutex.RLock()
-	info, exists := h.executor.runningProcs[h]
-	h.executor.procMutex.RUnlock()
+	defer h.executor.procMutex.RUnlock()
 
+	info, exists := h.executor.runningProcs[h]
 	if !exists {
 		return ProcessStateExited, nil
 	}
@@ -556,12 +740,13 @@ func (h *processHandleImpl) State() (ProcessState, error) {
 
 // interactivePipe implements io.ReadWriteCloser for interactive commands
 type interactivePipe struct {
-	stdin  io.WriteCloser
-	stdout io.ReadCloser
-	stderr io.ReadCloser
-	cmd    *exec.Cmd
-	mu     sync.Mutex
-	closed bool
+	stdin    io.WriteCloser
+	stdout   io.ReadCloser
+	stderr   io.ReadCloser
+	cmd      *exec.Cmd
+	executor *execImpl
+	mu       sync.Mutex
+	closed   bool
 }
 
 func (p *interactivePipe) Read(b []byte) (n int, err error) {
@@ -614,8 +799,8 @@ func (p *interactivePipe) Close() error {
 		errs = append(errs, err)
 	}
 
-	// Terminate the command
-	if err := p.cmd.Process.Kill(); err != nil {
+	// Terminate the command and everything it started
+	if err := p.executor.terminate(p.cmd); err != nil {
 		errs = append(errs, err)
 	}
 
@@ -623,4 +808,4 @@ func (p *interactivePipe) Close() error {
 		return errs[0]
 	}
 	return nil
-}
\ No newline at end of file
+}
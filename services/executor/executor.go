@@ -3,8 +3,12 @@ package executor
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"io"
+	"regexp"
 	"time"
+
+	"claude-squad/log"
 )
 
 // Command represents a command to be executed
@@ -15,6 +19,42 @@ type Command struct {
 	Env      []string
 	Stdin    io.Reader
 	Timeout  time.Duration
+
+	// Stdout and Stderr receive the output when set. Start has the process write directly
+	// to them, e.g. os.Stdout for commands that need the user's terminal, while Execute and
+	// ExecuteStreaming copy the output to them as well as capturing it.
+	Stdout io.Writer
+	Stderr io.Writer
+
+	// StdoutFile and StderrFile are files the output is appended to as well, created if
+	// needed, and may be the same file. A command starting a tmux session instead has
+	// everything its pane shows appended to StdoutFile. Not used by ExecuteInteractive.
+	StdoutFile string
+	StderrFile string
+
+	// Limits caps the resources the command may use, replacing the executor's default
+	// limits when set
+	Limits *Limits
+
+	// Retry replaces the executor's retry configuration for this command when set. Only
+	// Execute retries.
+	Retry *RetryPolicy
+
+	// Framing is how ExecuteStreaming divides the output into Outputs
+	Framing OutputFraming
+
+	// Pool names the concurrency pool the command waits for a slot in. Defaults to the
+	// program's name, or PoolAgent for AI agents.
+	Pool string
+
+	// EnvProfile names the env profile whose variables the command gets. Defaults to the
+	// profile named after the program, if there is one.
+	EnvProfile string
+
+	// Shell makes Program a command line run by the user's shell, e.g.
+	// "aider --model X | tee log", with Args appended to it quoted. Remote executors run
+	// it with sh. See Quote and BuildPipeline for building lines safely.
+	Shell bool
 }
 
 // Result represents the result of a command execution
@@ -24,6 +64,9 @@ type Result struct {
 	ExitCode int
 	Duration time.Duration
 	Error    error
+
+	// Truncated is set when output beyond the command's output limit was discarded
+	Truncated bool
 }
 
 // Output represents streaming output from a command
@@ -32,6 +75,10 @@ type Output struct {
 	Data      []byte
 	Timestamp time.Time
 	Error     error
+
+	// Seq numbers the Outputs of a command from 1, across stdout and stderr, in the
+	// order they were sent
+	Seq uint64
 }
 
 // OutputType indicates the type of output
@@ -51,6 +98,11 @@ type ProcessInfo struct {
 	State     ProcessState
 	Command   string
 	Args      []string
+
+	// Resource usage, sampled when the info is gathered. Only Linux samples it.
+	RSS        uint64  // resident memory in bytes
+	CPUPercent float64 // CPU use since the process was last sampled, or since it started; 100 is one core
+	Children   int     // number of processes descended from it
 }
 
 // ProcessState represents the state of a process
@@ -76,6 +128,9 @@ type CommandExecutor interface {
 	// Process management
 	Start(ctx context.Context, cmd Command) (ProcessHandle, error)
 	Kill(ctx context.Context, handle ProcessHandle) error
+	// Cancel asks the process to stop with SIGINT, so it can save its state, and kills
+	// it if it hasn't exited after the drain timeout
+	Cancel(ctx context.Context, handle ProcessHandle) error
 	Signal(ctx context.Context, handle ProcessHandle, signal int) error
 	Wait(ctx context.Context, handle ProcessHandle) (*Result, error)
 
@@ -105,25 +160,95 @@ type ExecutorOptions struct {
 	// Default timeout for all commands
 	DefaultTimeout time.Duration
 
-	// Maximum concurrent processes
+	// Maximum concurrent processes of pools without a size of their own
 	MaxConcurrent int
 
+	// Pools sizes named concurrency pools, e.g. {"git": 8, "tmux": 16, "agent": 4}, so a
+	// burst of one kind of command can't hold up another. Only Execute and
+	// ExecuteStreaming wait for a slot.
+	Pools map[string]int
+
+	// RateLimits limits how often commands may start, by program name, with network git
+	// commands limited under RateGitRemote, e.g. DefaultRateLimits(). Only Execute and
+	// ExecuteStreaming wait for their turn.
+	RateLimits map[string]RateLimit
+
 	// Whether to capture output by default
 	CaptureOutput bool
 
 	// Default environment variables
 	DefaultEnv []string
 
+	// EnvProfiles are named sets of variables given to the commands that use them, see
+	// EnvProfile
+	EnvProfiles map[string]EnvProfile
+
 	// Working directory for commands
 	WorkingDir string
 
 	// Logger for debugging
 	Logger Logger
 
-	// Retry configuration
+	// Redactor masks credentials in command lines before they are logged.
+	// Defaults to the process-wide redactor when nil.
+	Redactor *log.Redactor
+
+	// How long a killed or cancelled command and the processes it started have to exit
+	// after SIGTERM before they are sent SIGKILL. Defaults to 5 seconds.
+	KillGracePeriod time.Duration
+
+	// How long a process stopped with Cancel has to exit after SIGINT before it is
+	// killed. Defaults to 10 seconds.
+	CancelDrainTimeout time.Duration
+
+	// StallTimeout is how long a command may go without output before OnStall is told it
+	// has stalled, which unlike the timeout doesn't stop it. Commands started with Start
+	// are only watched when their output goes somewhere. Disabled when zero.
+	StallTimeout time.Duration
+
+	// OnStall is called when a command stalls, and again if it produces output after
+	OnStall func(StallEvent)
+
+	// Audit receives a record of every command run, when set
+	Audit CommandAuditSink
+
+	// SessionLogDir is where the output of every tmux session started through the
+	// executor is mirrored to, in a file named after the session, when set
+	SessionLogDir string
+
+	// Reaper cleans up the processes started with Start that exit without being waited
+	// for, when set
+	Reaper *Reaper
+
+	// Metrics counts the commands run, when set. Commands run through ExecuteInteractive
+	// aren't counted.
+	Metrics *Metrics
+
+	// DryRun logs the commands that would change state instead of running them, and
+	// reports them as having succeeded. Commands that only inspect git or tmux state
+	// still run.
+	DryRun bool
+
+	// DryRunOutput is where a dry run writes the commands it skips. Defaults to stderr.
+	DryRunOutput io.Writer
+
+	// ResultCacheTTL is how long Execute reuses the result of a git or tmux command that
+	// only inspects state, such as `git branch -v` or `tmux list-sessions`, instead of
+	// running it again. Running a command that may change state drops the cached results
+	// of its program. Zero disables caching.
+	ResultCacheTTL time.Duration
+
+	// Resource limits for commands that don't set their own
+	Limits Limits
+
+	// Retry configuration, see RetryPolicy
 	RetryCount    int
 	RetryDelay    time.Duration
-	RetryOnErrors []int // Exit codes to retry on
+	RetryBackoff  float64          // Factor the delay grows by after each retry
+	RetryMaxDelay time.Duration    // Cap on the delay
+	RetryJitter   float64          // Fraction each delay is randomized by
+	RetryOnErrors []int            // Exit codes to retry on
+	RetryOnStderr []*regexp.Regexp // Stderr patterns to retry on
 }
 
 // Logger provides logging for executor operations
@@ -140,6 +265,7 @@ type MockExecutor struct {
 	ExecuteFunc          func(ctx context.Context, cmd Command) (*Result, error)
 	ExecuteStreamingFunc func(ctx context.Context, cmd Command) (<-chan Output, error)
 	StartFunc            func(ctx context.Context, cmd Command) (ProcessHandle, error)
+	FindProcessFunc      func(ctx context.Context, pid int) (ProcessHandle, error)
 	CommandExistsFunc    func(ctx context.Context, program string) bool
 }
 
@@ -179,6 +305,10 @@ func (m *MockExecutor) Kill(ctx context.Context, handle ProcessHandle) error {
 	return handle.Kill()
 }
 
+func (m *MockExecutor) Cancel(ctx context.Context, handle ProcessHandle) error {
+	return cancelProcess(ctx, handle, defaultCancelDrain, killPollInterval)
+}
+
 func (m *MockExecutor) Signal(ctx context.Context, handle ProcessHandle, signal int) error {
 	return handle.Signal(signal)
 }
@@ -196,7 +326,10 @@ func (m *MockExecutor) ListProcesses(ctx context.Context) ([]*ProcessInfo, error
 }
 
 func (m *MockExecutor) FindProcess(ctx context.Context, pid int) (ProcessHandle, error) {
-	return nil, nil
+	if m.FindProcessFunc != nil {
+		return m.FindProcessFunc(ctx, pid)
+	}
+	return nil, fmt.Errorf("process with PID %d not found", pid)
 }
 
 func (m *MockExecutor) CommandExists(ctx context.Context, program string) bool {
@@ -0,0 +1,76 @@
+package executor
+
+import (
+	"fmt"
+)
+
+// KubernetesOptions identifies the pod a Kubernetes executor runs commands in
+type KubernetesOptions struct {
+	// Context is the kubeconfig context to use instead of the current one
+	Context string
+	// Namespace is the pod's namespace instead of the context's default one
+	Namespace string
+	// Pod is the name of the pod
+	Pod string
+	// Container is the container within the pod, needed when it has more than one
+	Container string
+}
+
+// kubernetesExecutor runs commands in a pod through `kubectl exec` on a local executor,
+// relying on the user's kubeconfig for the cluster and credentials
+type kubernetesExecutor struct {
+	remoteExecutor
+	opts KubernetesOptions
+}
+
+// NewKubernetesExecutor creates an executor that runs every command in the pod in opts,
+// using local to run kubectl
+func NewKubernetesExecutor(local CommandExecutor, opts KubernetesOptions) (CommandExecutor, error) {
+	if opts.Pod == "" {
+		return nil, fmt.Errorf("a pod is required for the kubernetes executor")
+	}
+	e := &kubernetesExecutor{opts: opts}
+	e.remoteExecutor = remoteExecutor{local: local, wrap: e.command, where: "pod " + opts.Pod}
+	return e, nil
+}
+
+// KubectlArgs returns the kubectl flags selecting the context and namespace in opts
+func (o KubernetesOptions) KubectlArgs() []string {
+	var args []string
+	if o.Context != "" {
+		args = append(args, "--context", o.Context)
+	}
+	if o.Namespace != "" {
+		args = append(args, "--namespace", o.Namespace)
+	}
+	return args
+}
+
+// command returns the local command that runs cmd in the pod through a shell, which
+// changes to its directory and sets its environment
+func (e *kubernetesExecutor) command(cmd Command, tty bool) Command {
+	args := append(e.opts.KubectlArgs(), "exec", "-i")
+	if tty {
+		args = append(args, "-t")
+	}
+	args = append(args, e.opts.Pod)
+	if e.opts.Container != "" {
+		args = append(args, "--container", e.opts.Container)
+	}
+	args = append(args, "--", "sh", "-c", remoteCommand(cmd))
+
+	return Command{
+		Program:    "kubectl",
+		Args:       args,
+		Stdin:      cmd.Stdin,
+		Timeout:    cmd.Timeout,
+		Stdout:     cmd.Stdout,
+		Stderr:     cmd.Stderr,
+		StdoutFile: cmd.StdoutFile,
+		StderrFile: cmd.StderrFile,
+		Limits:     cmd.Limits,
+		Retry:      cmd.Retry,
+		Framing:    cmd.Framing,
+		Pool:       poolName(cmd),
+	}
+}
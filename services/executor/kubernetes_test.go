@@ -0,0 +1,25 @@
+package executor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKubernetesWrap(t *testing.T) {
+	local := NewExecutor(nil)
+	e, err := NewKubernetesExecutor(local, KubernetesOptions{Context: "prod", Namespace: "agents", Pod: "cs-fix", Container: "agent"})
+	assert.NoError(t, err)
+
+	wrapped := e.(*kubernetesExecutor).wrap(Command{Program: "tmux", Args: []string{"attach", "-t", "s"}, Dir: "/src"}, true)
+	assert.Equal(t, "kubectl", wrapped.Program)
+	assert.Equal(t, []string{"--context", "prod", "--namespace", "agents", "exec", "-i", "-t", "cs-fix", "--container", "agent",
+		"--", "sh", "-c", "cd /src && exec tmux attach -t s"}, wrapped.Args)
+	assert.Equal(t, PoolTmux, wrapped.Pool)
+
+	wrapped = e.(*kubernetesExecutor).wrap(Command{Program: "tmux", Args: []string{"ls"}}, false)
+	assert.NotContains(t, wrapped.Args, "-t")
+
+	_, err = NewKubernetesExecutor(local, KubernetesOptions{})
+	assert.Error(t, err)
+}
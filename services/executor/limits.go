@@ -0,0 +1,134 @@
+package executor
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"claude-squad/log"
+)
+
+// Limits caps the resources a command may use, so a runaway agent subprocess can't exhaust
+// the machine. Zero fields are unlimited.
+//
+// On Linux the limits are enforced for the command's whole process tree through a cgroup,
+// when the cgroup this process runs in is delegated to its user as systemd does for user
+// services. Otherwise they fall back to per-process rlimits, with the caveats noted below.
+type Limits struct {
+	// CPUs caps the CPU time used per second, e.g. 1.5 for one and a half cores. Only
+	// enforced through cgroups.
+	CPUs float64
+	// CPUTime caps the total CPU time each process may use
+	CPUTime time.Duration
+	// Memory caps memory use in bytes. As an rlimit it caps each process's address space,
+	// which runtimes reserving large address ranges may hit well before using as much.
+	Memory int64
+	// Procs caps the number of processes. As an rlimit it counts every process of the user,
+	// not just the command's.
+	Procs int
+	// Output caps the bytes of stdout and of stderr Execute captures. Output beyond it is
+	// discarded and the result marked as truncated.
+	Output int64
+}
+
+// enforced reports whether any limit other than the output size is set
+func (l Limits) enforced() bool {
+	return l.CPUs > 0 || l.CPUTime > 0 || l.Memory > 0 || l.Procs > 0
+}
+
+// limitsFor returns the limits cmd runs under: its own, or the executor's defaults
+func (e *execImpl) limitsFor(cmd Command) Limits {
+	if cmd.Limits != nil {
+		return *cmd.Limits
+	}
+	return e.opts.Limits
+}
+
+var warnNoCgroup sync.Once
+
+// applyLimits makes execCmd run under limits. It must be called before the command starts;
+// the returned function releases what was set up once the command has exited.
+func applyLimits(execCmd *exec.Cmd, limits Limits) func() {
+	if !limits.enforced() || execCmd.Err != nil {
+		return func() {}
+	}
+
+	release := func() {}
+	cg, err := newCgroup(limits)
+	if err == nil {
+		cg.attach(execCmd)
+		release = cg.remove
+		// The rest is enforced for the whole process tree by the cgroup
+		limits = Limits{CPUTime: limits.CPUTime}
+	} else {
+		warnNoCgroup.Do(func() {
+			if log.WarningLog != nil {
+				log.WarningLog.Printf("resource limits fall back to rlimits, cgroups are unavailable: %v", err)
+			}
+		})
+	}
+	wrapRlimits(execCmd, limits)
+	return release
+}
+
+// wrapRlimits makes execCmd set the rlimits for limits before running the program, by
+// running it through a shell that sets them and execs it. There are no rlimits on Windows.
+func wrapRlimits(execCmd *exec.Cmd, limits Limits) {
+	if runtime.GOOS == "windows" {
+		return
+	}
+	var script []string
+	if limits.CPUTime > 0 {
+		script = append(script, fmt.Sprintf("ulimit -t %d", int64(math.Ceil(limits.CPUTime.Seconds()))))
+	}
+	if limits.Memory > 0 {
+		script = append(script, fmt.Sprintf("ulimit -v %d", (limits.Memory+1023)/1024))
+	}
+	if limits.Procs > 0 {
+		// bash and zsh call it -u, dash -p
+		script = append(script, fmt.Sprintf("{ ulimit -u %d 2>/dev/null || ulimit -p %d; }", limits.Procs, limits.Procs))
+	}
+	if len(script) == 0 {
+		return
+	}
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		return
+	}
+
+	args := []string{"sh", "-c", strings.Join(script, " && ") + ` && exec "$0" "$@"`, execCmd.Path}
+	execCmd.Args = append(args, execCmd.Args[1:]...)
+	execCmd.Path = sh
+}
+
+// limitedBuffer is a buffer that keeps the first limit bytes written to it and discards
+// the rest. A limit of zero keeps everything. It doesn't embed bytes.Buffer, whose
+// ReadFrom io.Copy would use to bypass the limit.
+type limitedBuffer struct {
+	buf       bytes.Buffer
+	limit     int64
+	truncated bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	n := len(p)
+	if b.limit > 0 {
+		room := b.limit - int64(b.buf.Len())
+		if int64(len(p)) > room {
+			b.truncated = true
+			p = p[:max(room, 0)]
+		}
+	}
+	b.buf.Write(p)
+	return n, nil
+}
+
+// Bytes returns the bytes kept
+func (b *limitedBuffer) Bytes() []byte {
+	return b.buf.Bytes()
+}
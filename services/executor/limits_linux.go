@@ -0,0 +1,101 @@
+//go:build linux
+
+package executor
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"syscall"
+)
+
+// cgroupRoot is where the cgroup v2 hierarchy is mounted
+const cgroupRoot = "/sys/fs/cgroup"
+
+// cgroupPeriod is the period, in microseconds, CPU quotas are set over
+const cgroupPeriod = 100000
+
+var cgroupSeq atomic.Int64
+
+// cgroup is a cgroup created to limit a single command
+type cgroup struct {
+	path string
+	dir  *os.File
+}
+
+// newCgroup creates a cgroup enforcing limits, below the one this process runs in. It
+// fails when cgroups v2 aren't mounted or that cgroup isn't delegated to this user, or
+// doesn't have the controllers for limits enabled for its children.
+func newCgroup(limits Limits) (*cgroup, error) {
+	data, err := os.ReadFile("/proc/self/cgroup")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read own cgroup: %w", err)
+	}
+	var own string
+	for _, line := range strings.Split(string(data), "\n") {
+		if path, ok := strings.CutPrefix(line, "0::"); ok {
+			own = path
+		}
+	}
+	// Hybrid setups mount the v2 hierarchy elsewhere, with the controllers still in v1
+	if _, err := os.Stat(filepath.Join(cgroupRoot, "cgroup.controllers")); own == "" || err != nil {
+		return nil, fmt.Errorf("cgroups v2 are not in use")
+	}
+
+	path := filepath.Join(cgroupRoot, own, fmt.Sprintf("cs-%d-%d", os.Getpid(), cgroupSeq.Add(1)))
+	if err := os.Mkdir(path, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create cgroup: %w", err)
+	}
+	cg := &cgroup{path: path}
+
+	controls := map[string]string{}
+	if limits.CPUs > 0 {
+		controls["cpu.max"] = fmt.Sprintf("%d %d", int64(limits.CPUs*cgroupPeriod), cgroupPeriod)
+	}
+	if limits.Memory > 0 {
+		controls["memory.max"] = strconv.FormatInt(limits.Memory, 10)
+	}
+	if limits.Procs > 0 {
+		controls["pids.max"] = strconv.Itoa(limits.Procs)
+	}
+	for name, value := range controls {
+		// Control files only exist when their controller is enabled, so don't create them
+		f, err := os.OpenFile(filepath.Join(path, name), os.O_WRONLY|os.O_TRUNC, 0)
+		if err == nil {
+			_, err = f.WriteString(value)
+			f.Close()
+		}
+		if err != nil {
+			cg.remove()
+			return nil, fmt.Errorf("failed to set %s: %w", name, err)
+		}
+	}
+
+	if cg.dir, err = os.Open(path); err != nil {
+		cg.remove()
+		return nil, fmt.Errorf("failed to open cgroup: %w", err)
+	}
+	return cg, nil
+}
+
+// attach makes execCmd start in the cgroup
+func (c *cgroup) attach(execCmd *exec.Cmd) {
+	if execCmd.SysProcAttr == nil {
+		execCmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	execCmd.SysProcAttr.UseCgroupFD = true
+	execCmd.SysProcAttr.CgroupFD = int(c.dir.Fd())
+}
+
+// remove deletes the cgroup. It is left in place while processes that outlived the
+// command, like a tmux server it started, still run in it.
+func (c *cgroup) remove() {
+	if c.dir != nil {
+		c.dir.Close()
+	}
+	os.Remove(c.path)
+}
@@ -0,0 +1,19 @@
+//go:build !linux
+
+package executor
+
+import (
+	"fmt"
+	"os/exec"
+)
+
+// cgroup stands in for Linux cgroups, which other platforms don't have
+type cgroup struct{}
+
+func newCgroup(limits Limits) (*cgroup, error) {
+	return nil, fmt.Errorf("cgroups are only available on Linux")
+}
+
+func (c *cgroup) attach(execCmd *exec.Cmd) {}
+
+func (c *cgroup) remove() {}
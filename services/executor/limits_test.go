@@ -0,0 +1,46 @@
+package executor
+
+import (
+	"context"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutputLimit(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs a POSIX shell")
+	}
+	e := NewExecutor(&ExecutorOptions{DefaultTimeout: 10 * time.Second, CaptureOutput: true, Limits: Limits{Output: 10}})
+
+	result, err := e.Execute(context.Background(), Command{Program: "sh", Args: []string{"-c", "printf 0123456789abcdef"}})
+	require.NoError(t, err)
+	assert.Equal(t, "0123456789", string(result.Stdout))
+	assert.True(t, result.Truncated)
+
+	// A command's own limits replace the executor's
+	result, err = e.Execute(context.Background(), Command{Program: "sh", Args: []string{"-c", "printf 0123456789abcdef"}, Limits: &Limits{}})
+	require.NoError(t, err)
+	assert.Equal(t, "0123456789abcdef", string(result.Stdout))
+	assert.False(t, result.Truncated)
+}
+
+func TestCPUTimeLimit(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("rlimits are not available on Windows")
+	}
+	e := NewExecutor(&ExecutorOptions{DefaultTimeout: 10 * time.Second, CaptureOutput: true})
+
+	result, err := e.Execute(context.Background(), Command{
+		Program: "sh",
+		Args:    []string{"-c", "ulimit -t"},
+		Limits:  &Limits{CPUTime: 4500 * time.Millisecond},
+	})
+	require.NoError(t, err)
+	require.Zero(t, result.ExitCode, string(result.Stderr))
+	assert.Equal(t, "5", strings.TrimSpace(string(result.Stdout)))
+}
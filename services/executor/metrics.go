@@ -0,0 +1,192 @@
+package executor
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// durationBuckets are the upper bounds of the command duration histogram's buckets
+var durationBuckets = [...]time.Duration{
+	5 * time.Millisecond, 10 * time.Millisecond, 25 * time.Millisecond, 50 * time.Millisecond,
+	100 * time.Millisecond, 250 * time.Millisecond, 500 * time.Millisecond, time.Second,
+	2500 * time.Millisecond, 5 * time.Second, 10 * time.Second, 30 * time.Second, time.Minute,
+}
+
+// Metrics counts the commands an executor runs, per program, for the `cs top` debug panel
+// and Prometheus. It is safe for concurrent use and may be shared by several executors.
+type Metrics struct {
+	mu       sync.Mutex
+	programs map[string]*programMetrics
+}
+
+type programMetrics struct {
+	executions uint64
+	failures   uint64
+	running    int
+	// buckets counts the durations up to each bound of durationBuckets, with one more
+	// bucket for longer ones
+	buckets [len(durationBuckets) + 1]uint64
+	sum     time.Duration
+	max     time.Duration
+}
+
+// ProgramStats is a snapshot of the metrics of one program
+type ProgramStats struct {
+	Program    string
+	Executions uint64
+	Failures   uint64
+	Running    int
+	// P95 is an estimate of the 95th percentile duration: the upper bound of the histogram
+	// bucket it falls in
+	P95 time.Duration
+}
+
+// NewMetrics creates an empty metrics registry
+func NewMetrics() *Metrics {
+	return &Metrics{programs: make(map[string]*programMetrics)}
+}
+
+// programName returns the name a program is counted under: its file name, without the
+// extension executables have on Windows
+func programName(program string) string {
+	return strings.TrimSuffix(filepath.Base(program), ".exe")
+}
+
+// track counts cmd as running, returning the function that counts it as finished with err
+func (e *execImpl) track(cmd Command) func(err error) {
+	m := e.opts.Metrics
+	if m == nil {
+		return func(error) {}
+	}
+	program := programName(cmd.Program)
+	start := time.Now()
+
+	m.mu.Lock()
+	p := m.program(program)
+	p.running++
+	m.mu.Unlock()
+
+	return func(err error) {
+		d := time.Since(start)
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		p.running--
+		p.executions++
+		if err != nil {
+			p.failures++
+		}
+		p.buckets[sort.Search(len(durationBuckets), func(i int) bool { return d <= durationBuckets[i] })]++
+		p.sum += d
+		p.max = max(p.max, d)
+	}
+}
+
+// program returns the metrics of program, creating them if needed. m.mu must be held.
+func (m *Metrics) program(name string) *programMetrics {
+	p, ok := m.programs[name]
+	if !ok {
+		p = &programMetrics{}
+		m.programs[name] = p
+	}
+	return p
+}
+
+// Snapshot returns the current metrics of every program seen, sorted by program
+func (m *Metrics) Snapshot() []ProgramStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stats := make([]ProgramStats, 0, len(m.programs))
+	for name, p := range m.programs {
+		stats = append(stats, ProgramStats{
+			Program:    name,
+			Executions: p.executions,
+			Failures:   p.failures,
+			Running:    p.running,
+			P95:        p.percentile(0.95),
+		})
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Program < stats[j].Program })
+	return stats
+}
+
+// percentile estimates the duration q of the executions took at most
+func (p *programMetrics) percentile(q float64) time.Duration {
+	if p.executions == 0 {
+		return 0
+	}
+	rank := uint64(math.Ceil(q * float64(p.executions)))
+	var seen uint64
+	for i, count := range p.buckets {
+		seen += count
+		if seen >= rank {
+			if i == len(durationBuckets) {
+				break
+			}
+			return min(durationBuckets[i], p.max)
+		}
+	}
+	return p.max
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// WritePrometheus writes the metrics in the Prometheus text exposition format
+func (m *Metrics) WritePrometheus(w io.Writer) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	names := make([]string, 0, len(m.programs))
+	for name := range m.programs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	family := func(name, typ, help string, value func(p *programMetrics) string) {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+		for _, program := range names {
+			fmt.Fprintf(&b, "%s{program=\"%s\"} %s\n", name, labelEscaper.Replace(program), value(m.programs[program]))
+		}
+	}
+	family("cs_executor_executions_total", "counter", "Commands run.", func(p *programMetrics) string {
+		return fmt.Sprint(p.executions)
+	})
+	family("cs_executor_failures_total", "counter", "Commands that failed or exited non-zero.", func(p *programMetrics) string {
+		return fmt.Sprint(p.failures)
+	})
+	family("cs_executor_running", "gauge", "Commands running now.", func(p *programMetrics) string {
+		return fmt.Sprint(p.running)
+	})
+
+	const histogram = "cs_executor_duration_seconds"
+	fmt.Fprintf(&b, "# HELP %s How long commands took.\n# TYPE %s histogram\n", histogram, histogram)
+	for _, program := range names {
+		p := m.programs[program]
+		label := labelEscaper.Replace(program)
+		var cumulative uint64
+		for i, bound := range durationBuckets {
+			cumulative += p.buckets[i]
+			fmt.Fprintf(&b, "%s_bucket{program=\"%s\",le=\"%g\"} %d\n", histogram, label, bound.Seconds(), cumulative)
+		}
+		fmt.Fprintf(&b, "%s_bucket{program=\"%s\",le=\"+Inf\"} %d\n", histogram, label, p.executions)
+		fmt.Fprintf(&b, "%s_sum{program=\"%s\"} %g\n", histogram, label, p.sum.Seconds())
+		fmt.Fprintf(&b, "%s_count{program=\"%s\"} %d\n", histogram, label, p.executions)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// ServeHTTP serves the metrics to Prometheus
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_ = m.WritePrometheus(w)
+}
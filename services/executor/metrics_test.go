@@ -0,0 +1,66 @@
+package executor
+
+import (
+	"context"
+	"net/http/httptest"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetrics(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("needs a POSIX shell")
+	}
+	ctx := context.Background()
+	metrics := NewMetrics()
+	e := NewExecutor(&ExecutorOptions{DefaultTimeout: 10 * time.Second, CaptureOutput: true, Metrics: metrics})
+
+	_, err := e.Execute(ctx, Command{Program: "sh", Args: []string{"-c", "exit 0"}})
+	require.NoError(t, err)
+	_, err = e.Execute(ctx, Command{Program: "sh", Args: []string{"-c", "exit 3"}})
+	require.NoError(t, err)
+
+	handle, err := e.Start(ctx, Command{Program: "sh", Args: []string{"-c", "read x"}, Stdin: strings.NewReader("x\n")})
+	require.NoError(t, err)
+	assert.Equal(t, 1, metrics.Snapshot()[0].Running)
+	_, err = handle.Wait()
+	require.NoError(t, err)
+
+	stats := metrics.Snapshot()
+	require.Len(t, stats, 1)
+	assert.Equal(t, "sh", stats[0].Program)
+	assert.Equal(t, uint64(3), stats[0].Executions)
+	assert.Equal(t, uint64(1), stats[0].Failures)
+	assert.Equal(t, 0, stats[0].Running)
+	assert.Positive(t, stats[0].P95)
+
+	rec := httptest.NewRecorder()
+	metrics.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body := rec.Body.String()
+	assert.Contains(t, body, "# TYPE cs_executor_executions_total counter\n")
+	assert.Contains(t, body, `cs_executor_executions_total{program="sh"} 3`)
+	assert.Contains(t, body, `cs_executor_failures_total{program="sh"} 1`)
+	assert.Contains(t, body, `cs_executor_duration_seconds_bucket{program="sh",le="+Inf"} 3`)
+	assert.Contains(t, body, `cs_executor_duration_seconds_count{program="sh"} 3`)
+}
+
+func TestMetricsPercentile(t *testing.T) {
+	p := &programMetrics{}
+	assert.Zero(t, p.percentile(0.95))
+
+	// 19 fast commands and one slow one: the 95th percentile is still fast
+	p.executions, p.buckets[0], p.buckets[11], p.max = 20, 19, 1, 20*time.Second
+	assert.Equal(t, 5*time.Millisecond, p.percentile(0.95))
+	// No estimate exceeds the longest duration seen
+	assert.Equal(t, 20*time.Second, p.percentile(1))
+
+	// Beyond the last bucket the longest duration is all there is to go on
+	p.buckets[11], p.buckets[len(durationBuckets)] = 0, 1
+	p.max = 5 * time.Minute
+	assert.Equal(t, 5*time.Minute, p.percentile(1))
+}
@@ -0,0 +1,54 @@
+package executor
+
+import (
+	"slices"
+)
+
+// Names of the concurrency pools commands are sorted into by program
+const (
+	PoolGit   = "git"
+	PoolTmux  = "tmux"
+	PoolAgent = "agent"
+)
+
+// agentPrograms are the AI agents that run in the PoolAgent pool
+var agentPrograms = []string{"claude", "aider", "gemini", "codex"}
+
+// DefaultPools returns the pool sizes a default executor uses, leaving tmux enough slots
+// that keystrokes are delivered promptly while git is busy
+func DefaultPools() map[string]int {
+	return map[string]int{PoolGit: 8, PoolTmux: 16, PoolAgent: 4}
+}
+
+// poolName returns the name of the pool cmd waits for a slot in: its own, or the name of
+// its program, with AI agents sharing one pool
+func poolName(cmd Command) string {
+	if cmd.Pool != "" {
+		return cmd.Pool
+	}
+	program := programName(cmd.Program)
+	if slices.Contains(agentPrograms, program) {
+		return PoolAgent
+	}
+	return program
+}
+
+// newPools creates a semaphore for each pool with a positive size
+func newPools(sizes map[string]int) map[string]chan struct{} {
+	pools := make(map[string]chan struct{}, len(sizes))
+	for name, size := range sizes {
+		if size > 0 {
+			pools[name] = make(chan struct{}, size)
+		}
+	}
+	return pools
+}
+
+// semaphore returns the semaphore limiting how many commands of cmd's pool run at once.
+// Commands of pools that aren't configured share the default one.
+func (e *execImpl) semaphore(cmd Command) chan struct{} {
+	if sem, ok := e.pools[poolName(cmd)]; ok {
+		return sem
+	}
+	return e.concurrentSem
+}
@@ -0,0 +1,58 @@
+package executor
+
+import (
+	"context"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPoolName(t *testing.T) {
+	assert.Equal(t, PoolGit, poolName(Command{Program: "git"}))
+	assert.Equal(t, PoolTmux, poolName(Command{Program: "/usr/bin/tmux"}))
+	assert.Equal(t, PoolAgent, poolName(Command{Program: "claude"}))
+	assert.Equal(t, PoolAgent, poolName(Command{Program: "aider.exe"}))
+	assert.Equal(t, "gh", poolName(Command{Program: "gh"}))
+	assert.Equal(t, PoolTmux, poolName(Command{Program: "sh", Pool: PoolTmux}))
+}
+
+func TestPools(t *testing.T) {
+	if _, err := exec.LookPath("sleep"); err != nil {
+		t.Skip("sleep not installed")
+	}
+	e := NewExecutor(&ExecutorOptions{
+		DefaultTimeout: 10 * time.Second,
+		MaxConcurrent:  1,
+		Pools:          map[string]int{PoolGit: 1, PoolTmux: 2},
+	})
+	slow := Command{Program: "sleep", Args: []string{"1"}, Pool: PoolGit}
+
+	started := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		close(started)
+		_, _ = e.Execute(context.Background(), slow)
+	}()
+	<-started
+	time.Sleep(100 * time.Millisecond)
+
+	// The git pool is full, the tmux pool isn't
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	_, err := e.Execute(ctx, slow)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	result, err := e.Execute(context.Background(), Command{Program: "sleep", Args: []string{"0"}, Pool: PoolTmux})
+	require.NoError(t, err)
+	assert.Equal(t, 0, result.ExitCode)
+
+	// Pools that aren't configured share the default slots
+	result, err = e.Execute(context.Background(), Command{Program: "sleep", Args: []string{"0"}})
+	require.NoError(t, err)
+	assert.Equal(t, 0, result.ExitCode)
+	<-done
+}
@@ -0,0 +1,58 @@
+package executor
+
+import (
+	"io"
+	"os/exec"
+	"syscall"
+	"time"
+)
+
+// defaultKillGrace is how long a terminated command has to exit before it is killed
+const defaultKillGrace = 5 * time.Second
+
+// killPollInterval is how often a terminated command is checked for having exited
+const killPollInterval = 50 * time.Millisecond
+
+func (e *execImpl) killGrace() time.Duration {
+	if e.opts.KillGracePeriod > 0 {
+		return e.opts.KillGracePeriod
+	}
+	return defaultKillGrace
+}
+
+// ownGroup makes execCmd run in a process group of its own, so that terminating it,
+// including by cancelling its context, terminates everything it started too. Commands on
+// the user's terminal stay in its foreground group, since they couldn't read from it
+// otherwise.
+func (e *execImpl) ownGroup(execCmd *exec.Cmd, stdin io.Reader) {
+	if !isTerminal(stdin) {
+		setProcessGroup(execCmd)
+	}
+	execCmd.Cancel = func() error {
+		return e.terminate(execCmd)
+	}
+	// Children that outlive the command can hold its output pipes open; stop waiting
+	// for them once they should have been killed
+	execCmd.WaitDelay = 2 * e.killGrace()
+}
+
+// terminate asks the process tree of a started command to exit, and kills it if it
+// hasn't after the grace period. It doesn't wait for either.
+func (e *execImpl) terminate(execCmd *exec.Cmd) error {
+	if err := signalTree(execCmd, syscall.SIGTERM); err != nil {
+		return err
+	}
+
+	grace := e.killGrace()
+	go func() {
+		deadline := time.Now().Add(grace)
+		for time.Now().Before(deadline) {
+			time.Sleep(killPollInterval)
+			if !treeAlive(execCmd) {
+				return
+			}
+		}
+		signalTree(execCmd, syscall.SIGKILL)
+	}()
+	return nil
+}
@@ -0,0 +1,85 @@
+//go:build !windows
+
+package executor
+
+import (
+	"context"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// exited reports whether the process with the given PID has gone, waiting up to a second.
+// Orphans can linger as zombies when nothing reaps them, which counts as gone.
+func exited(pid int) bool {
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		state, _ := exec.Command("ps", "-o", "stat=", "-p", strconv.Itoa(pid)).Output()
+		if s := strings.TrimSpace(string(state)); s == "" || strings.HasPrefix(s, "Z") {
+			return true
+		}
+	}
+	return false
+}
+
+// syncBuffer is a strings.Builder safe to read while a process writes to it
+type syncBuffer struct {
+	mu sync.Mutex
+	b  strings.Builder
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.b.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.b.String()
+}
+
+func TestCancelKillsProcessTree(t *testing.T) {
+	e := NewExecutor(&ExecutorOptions{CaptureOutput: true, KillGracePeriod: 100 * time.Millisecond})
+
+	// The shell ignores SIGTERM, so only the escalation to SIGKILL ends it
+	result, err := e.Execute(context.Background(), Command{
+		Program: "sh",
+		Args:    []string{"-c", "trap '' TERM; sleep 30 & echo $!; wait"},
+		Timeout: 200 * time.Millisecond,
+	})
+	require.NoError(t, err)
+	assert.NotZero(t, result.ExitCode)
+
+	child, err := strconv.Atoi(strings.TrimSpace(string(result.Stdout)))
+	require.NoError(t, err)
+	assert.True(t, exited(child), "child of a cancelled command is still running")
+}
+
+func TestKillProcessTree(t *testing.T) {
+	e := NewExecutor(&ExecutorOptions{KillGracePeriod: time.Second})
+	var stdout syncBuffer
+	handle, err := e.Start(context.Background(), Command{
+		Program: "sh",
+		Args:    []string{"-c", "sleep 30 & echo $!; wait"},
+		Stdout:  &stdout,
+	})
+	require.NoError(t, err)
+
+	var child int
+	require.Eventually(t, func() bool {
+		child, err = strconv.Atoi(strings.TrimSpace(stdout.String()))
+		return err == nil
+	}, time.Second, 10*time.Millisecond)
+
+	require.NoError(t, e.Kill(context.Background(), handle))
+	_, err = e.Wait(context.Background(), handle)
+	require.NoError(t, err)
+	assert.True(t, exited(child), "child of a killed command is still running")
+}
@@ -0,0 +1,59 @@
+//go:build !windows
+
+package executor
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// setProcessGroup makes execCmd the leader of a new process group
+func setProcessGroup(execCmd *exec.Cmd) {
+	if execCmd.SysProcAttr == nil {
+		execCmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	execCmd.SysProcAttr.Setpgid = true
+}
+
+// hasOwnGroup reports whether execCmd leads a process group of its own
+func hasOwnGroup(execCmd *exec.Cmd) bool {
+	return execCmd.SysProcAttr != nil && execCmd.SysProcAttr.Setpgid
+}
+
+// signalTree sends sig to the process group a started command leads, or to the command
+// alone when it has none
+func signalTree(execCmd *exec.Cmd, sig syscall.Signal) error {
+	if !hasOwnGroup(execCmd) {
+		return execCmd.Process.Signal(sig)
+	}
+	err := syscall.Kill(-execCmd.Process.Pid, sig)
+	if errors.Is(err, syscall.ESRCH) {
+		return os.ErrProcessDone
+	}
+	return err
+}
+
+// treeAlive reports whether any process of a started command's tree is still running
+func treeAlive(execCmd *exec.Cmd) bool {
+	return signalTree(execCmd, 0) == nil
+}
+
+// signalExternal sends sig to a process the executor didn't start, and to the rest of its
+// process group when it leads one, as the program in a tmux pane does
+func signalExternal(pid int, sig syscall.Signal) error {
+	if pgid, err := syscall.Getpgid(pid); err == nil && pgid == pid {
+		pid = -pid
+	}
+	err := syscall.Kill(pid, sig)
+	if errors.Is(err, syscall.ESRCH) {
+		return os.ErrProcessDone
+	}
+	return err
+}
+
+// signalProcess sends sig to a started command
+func signalProcess(execCmd *exec.Cmd, sig syscall.Signal) error {
+	return execCmd.Process.Signal(sig)
+}
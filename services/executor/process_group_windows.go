@@ -0,0 +1,70 @@
+//go:build windows
+
+package executor
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"syscall"
+)
+
+// setProcessGroup makes execCmd the root of a new process group
+func setProcessGroup(execCmd *exec.Cmd) {
+	if execCmd.SysProcAttr == nil {
+		execCmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	execCmd.SysProcAttr.CreationFlags |= syscall.CREATE_NEW_PROCESS_GROUP
+}
+
+// signalTree ends a started command and its children with taskkill, asking them to close
+// for SIGTERM and forcing them for SIGKILL. Windows has no other signals.
+func signalTree(execCmd *exec.Cmd, sig syscall.Signal) error {
+	args := []string{"/T", "/PID", strconv.Itoa(execCmd.Process.Pid)}
+	if sig == syscall.SIGKILL {
+		args = append(args, "/F")
+	} else if sig != syscall.SIGTERM {
+		return nil
+	}
+	return exec.Command("taskkill", args...).Run()
+}
+
+// treeAlive can't cheaply tell whether the tree has exited, so it assumes it hasn't; the
+// forced kill after the grace period is harmless when it has
+func treeAlive(execCmd *exec.Cmd) bool {
+	return true
+}
+
+// signalProcess delivers the signals Windows has an equivalent of to a started command:
+// SIGKILL kills it, while SIGINT and SIGTERM ask it and its children to close
+func signalProcess(execCmd *exec.Cmd, sig syscall.Signal) error {
+	switch sig {
+	case syscall.SIGKILL:
+		return execCmd.Process.Kill()
+	case syscall.SIGINT, syscall.SIGTERM:
+		return signalTree(execCmd, syscall.SIGTERM)
+	}
+	return fmt.Errorf("signal %v is not supported on windows", sig)
+}
+
+// signalExternal ends a process the executor didn't start and its children with taskkill,
+// asking them to close for SIGINT and SIGTERM and forcing them for SIGKILL. Signal 0
+// checks that the process exists.
+func signalExternal(pid int, sig syscall.Signal) error {
+	args := []string{"/T", "/PID", strconv.Itoa(pid)}
+	switch sig {
+	case 0:
+		p, err := os.FindProcess(pid)
+		if err != nil {
+			return os.ErrProcessDone
+		}
+		return p.Release()
+	case syscall.SIGKILL:
+		args = append(args, "/F")
+	case syscall.SIGINT, syscall.SIGTERM:
+	default:
+		return fmt.Errorf("signal %v is not supported on windows", sig)
+	}
+	return exec.Command("taskkill", args...).Run()
+}
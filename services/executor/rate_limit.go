@@ -0,0 +1,117 @@
+package executor
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+// RateGitRemote is the rate limit key of git commands that talk to a remote, which are
+// limited apart from local git commands
+const RateGitRemote = "git-remote"
+
+// gitRemoteCommands are the git subcommands that contact a remote
+var gitRemoteCommands = []string{"clone", "fetch", "ls-remote", "pull", "push"}
+
+// RateLimit is a token bucket: commands may start at Rate per second on average, with
+// bursts of up to Burst at once
+type RateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+// DefaultRateLimits returns the rate limits a default executor uses, keeping the remote
+// APIs of GitHub, GitLab and git hosts from being hammered when many sessions refresh at once
+func DefaultRateLimits() map[string]RateLimit {
+	return map[string]RateLimit{
+		"gh":          {Rate: 1, Burst: 5},
+		"glab":        {Rate: 1, Burst: 5},
+		RateGitRemote: {Rate: 2, Burst: 4},
+	}
+}
+
+// rateKey returns the key of the rate limit cmd is subject to: its program's name, or
+// RateGitRemote for git commands that contact a remote
+func rateKey(cmd Command) string {
+	program := programName(cmd.Program)
+	if program == "git" {
+		args := skipGlobalFlags(cmd.Program, cmd.Args)
+		if len(args) > 0 && slices.Contains(gitRemoteCommands, args[0]) {
+			return RateGitRemote
+		}
+	}
+	return program
+}
+
+// tokenBucket hands out tokens at a steady rate, holding up to burst of them
+type tokenBucket struct {
+	rate  float64
+	burst float64
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// newRateLimiters creates a token bucket for each usable rate limit, starting full
+func newRateLimiters(limits map[string]RateLimit) map[string]*tokenBucket {
+	buckets := make(map[string]*tokenBucket, len(limits))
+	for key, limit := range limits {
+		if limit.Rate > 0 && limit.Burst > 0 {
+			buckets[key] = &tokenBucket{
+				rate:   limit.Rate,
+				burst:  float64(limit.Burst),
+				tokens: float64(limit.Burst),
+				last:   time.Now(),
+			}
+		}
+	}
+	return buckets
+}
+
+// reserve takes a token, returning how long to wait before it may be used
+func (b *tokenBucket) reserve() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	b.tokens = min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// cancel returns a token taken by reserve that wasn't used
+func (b *tokenBucket) cancel() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tokens = min(b.burst, b.tokens+1)
+}
+
+// waitForRate waits until cmd may start under its rate limit, or ctx is done
+func (e *execImpl) waitForRate(ctx context.Context, cmd Command) error {
+	bucket, ok := e.rateLimiters[rateKey(cmd)]
+	if !ok {
+		return nil
+	}
+	delay := bucket.reserve()
+	if delay == 0 {
+		return nil
+	}
+	if e.opts.Logger != nil {
+		e.opts.Logger.Debug("Rate limiting %s for %v", rateKey(cmd), delay)
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		bucket.cancel()
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,58 @@
+package executor
+
+import (
+	"context"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateKey(t *testing.T) {
+	assert.Equal(t, "gh", rateKey(Command{Program: "gh", Args: []string{"pr", "list"}}))
+	assert.Equal(t, RateGitRemote, rateKey(Command{Program: "git", Args: []string{"fetch", "origin"}}))
+	assert.Equal(t, RateGitRemote, rateKey(Command{Program: "/usr/bin/git", Args: []string{"-C", "/repo", "push"}}))
+	assert.Equal(t, "git", rateKey(Command{Program: "git", Args: []string{"status"}}))
+}
+
+func TestTokenBucket(t *testing.T) {
+	b := newRateLimiters(map[string]RateLimit{"gh": {Rate: 10, Burst: 2}, "off": {Rate: 0, Burst: 1}})
+	require.Contains(t, b, "gh")
+	assert.NotContains(t, b, "off")
+
+	bucket := b["gh"]
+	assert.Zero(t, bucket.reserve())
+	assert.Zero(t, bucket.reserve())
+	delay := bucket.reserve()
+	assert.Greater(t, delay, time.Duration(0))
+	assert.LessOrEqual(t, delay, 100*time.Millisecond)
+
+	bucket.cancel()
+	assert.LessOrEqual(t, bucket.reserve(), 100*time.Millisecond)
+}
+
+func TestRateLimits(t *testing.T) {
+	if _, err := exec.LookPath("true"); err != nil {
+		t.Skip("true not installed")
+	}
+	e := NewExecutor(&ExecutorOptions{
+		DefaultTimeout: 10 * time.Second,
+		RateLimits:     map[string]RateLimit{"true": {Rate: 5, Burst: 1}},
+	})
+	cmd := Command{Program: "true"}
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		_, err := e.Execute(context.Background(), cmd)
+		require.NoError(t, err)
+	}
+	assert.GreaterOrEqual(t, time.Since(start), 350*time.Millisecond)
+
+	// Waiting for a turn gives up with the context
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := e.Execute(ctx, cmd)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
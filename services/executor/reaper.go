@@ -0,0 +1,145 @@
+package executor
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ReapEvent describes a process the reaper cleaned up
+type ReapEvent struct {
+	PID int
+	// Command is the redacted command line of a process started through an executor, and
+	// empty for orphans
+	Command  string
+	ExitCode int
+	// Orphan is set for processes the executors didn't start, such as ones left behind
+	// by a crashed session and adopted by this process
+	Orphan bool
+}
+
+// Reaper periodically cleans up the processes of executors that have exited without
+// being waited for, and exited orphans adopted by this process, so a long-lived process
+// like the daemon doesn't accumulate zombies. Executors use it when given it in their
+// options. Zombies are only found on Linux.
+type Reaper struct {
+	interval time.Duration
+	onReap   func(ReapEvent)
+
+	mu        sync.Mutex
+	executors []*execImpl
+	// candidates are the zombies no executor started that were seen on the last scan.
+	// They are only reaped when seen again, so whoever started them has had the time to
+	// wait for them.
+	candidates map[int]bool
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewReaper creates a reaper scanning for zombies every interval, and calling onReap,
+// when set, for each process it cleans up
+func NewReaper(interval time.Duration, onReap func(ReapEvent)) *Reaper {
+	return &Reaper{interval: interval, onReap: onReap, candidates: make(map[int]bool)}
+}
+
+// AdoptOrphans makes this process adopt the orphaned descendants of the commands it runs,
+// instead of init, so the reaper can clean them up when they exit. Only Linux supports it.
+func (r *Reaper) AdoptOrphans() error {
+	return adoptOrphans()
+}
+
+// Start scans in the background until Stop is called
+func (r *Reaper) Start() {
+	r.stop = make(chan struct{})
+	r.done = make(chan struct{})
+	go func() {
+		defer close(r.done)
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-r.stop:
+				return
+			case <-ticker.C:
+				r.scan()
+			}
+		}
+	}()
+}
+
+// Stop stops scanning, waiting for a scan in progress to finish
+func (r *Reaper) Stop() {
+	if r.stop == nil {
+		return
+	}
+	close(r.stop)
+	<-r.done
+	r.stop = nil
+}
+
+// add makes the reaper clean up the processes e starts
+func (r *Reaper) add(e *execImpl) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.executors = append(r.executors, e)
+}
+
+// scan cleans up the zombies there are now
+func (r *Reaper) scan() {
+	zombies := make(map[int]bool)
+	for _, pid := range zombieChildren() {
+		zombies[pid] = true
+	}
+
+	r.mu.Lock()
+	executors := r.executors
+	r.mu.Unlock()
+
+	// Started processes are waited for through their handles, whose Wait then returns
+	// the same result to callers
+	var exited []*processHandleImpl
+	for _, e := range executors {
+		e.procMutex.RLock()
+		for handle, info := range e.runningProcs {
+			if h, ok := handle.(*processHandleImpl); ok && zombies[info.cmd.Process.Pid] {
+				exited = append(exited, h)
+			}
+		}
+		e.procMutex.RUnlock()
+	}
+	for _, h := range exited {
+		pid := h.cmd.Process.Pid
+		delete(zombies, pid)
+		result, _ := h.Wait()
+		r.report(ReapEvent{
+			PID:      pid,
+			Command:  h.executor.redact(fmt.Sprintf("%s %v", h.info.command.Program, h.info.command.Args)),
+			ExitCode: result.ExitCode,
+		})
+	}
+
+	r.mu.Lock()
+	seen := r.candidates
+	r.candidates = make(map[int]bool)
+	for pid := range zombies {
+		if !seen[pid] {
+			r.candidates[pid] = true
+		}
+	}
+	r.mu.Unlock()
+	for pid := range zombies {
+		if !seen[pid] {
+			continue
+		}
+		if exitCode, err := reapChild(pid); err == nil {
+			r.report(ReapEvent{PID: pid, ExitCode: exitCode, Orphan: true})
+		}
+	}
+}
+
+func (r *Reaper) report(event ReapEvent) {
+	if r.onReap != nil {
+		r.onReap(event)
+	}
+}
@@ -0,0 +1,39 @@
+package executor
+
+import (
+	"os"
+	"strconv"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// zombieChildren returns the PIDs of the children of this process that have exited and
+// haven't been waited for
+func zombieChildren() []int {
+	self := strconv.Itoa(os.Getpid())
+	var pids []int
+	for pid, fields := range procStats() {
+		if fields[statState] == "Z" && fields[statParent] == self {
+			pids = append(pids, pid)
+		}
+	}
+	return pids
+}
+
+// reapChild waits for the exited child with the given PID, returning its exit code
+func reapChild(pid int) (int, error) {
+	var status syscall.WaitStatus
+	if _, err := syscall.Wait4(pid, &status, syscall.WNOHANG, nil); err != nil {
+		return -1, err
+	}
+	if status.Signaled() {
+		return -1, nil
+	}
+	return status.ExitStatus(), nil
+}
+
+// adoptOrphans makes this process a child subreaper
+func adoptOrphans() error {
+	return unix.Prctl(unix.PR_SET_CHILD_SUBREAPER, 1, 0, 0, 0)
+}
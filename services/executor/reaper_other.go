@@ -0,0 +1,20 @@
+//go:build !linux
+
+package executor
+
+import (
+	"errors"
+)
+
+// zombieChildren finds nothing, as only Linux lists processes' states cheaply
+func zombieChildren() []int {
+	return nil
+}
+
+func reapChild(pid int) (int, error) {
+	return -1, errors.ErrUnsupported
+}
+
+func adoptOrphans() error {
+	return errors.New("adopting orphans is only supported on linux")
+}
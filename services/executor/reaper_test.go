@@ -0,0 +1,66 @@
+//go:build linux
+
+package executor
+
+import (
+	"context"
+	"os/exec"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// waitForZombie waits up to a second for the child with the given PID to exit
+func waitForZombie(t *testing.T, pid int) {
+	t.Helper()
+	require.Eventually(t, func() bool { return slices.Contains(zombieChildren(), pid) }, time.Second, 10*time.Millisecond)
+}
+
+func TestReaperWaitsForStartedProcesses(t *testing.T) {
+	var events []ReapEvent
+	reaper := NewReaper(time.Hour, func(event ReapEvent) { events = append(events, event) })
+	e := NewExecutor(&ExecutorOptions{Reaper: reaper})
+
+	handle, err := e.Start(context.Background(), Command{Program: "sh", Args: []string{"-c", "exit 3"}})
+	require.NoError(t, err)
+	waitForZombie(t, handle.PID())
+
+	reaper.scan()
+	assert.Equal(t, []ReapEvent{{PID: handle.PID(), Command: "sh [-c exit 3]", ExitCode: 3}}, events)
+	assert.NotContains(t, zombieChildren(), handle.PID())
+
+	// Waiting afterwards gets the result the reaper collected
+	result, err := handle.Wait()
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.ExitCode)
+	state, err := handle.State()
+	require.NoError(t, err)
+	assert.Equal(t, ProcessStateExited, state)
+}
+
+func TestReaperReapsOrphans(t *testing.T) {
+	// A child nothing waits for, as adopted orphans are
+	cmd := exec.Command("sh", "-c", "exit 2")
+	require.NoError(t, cmd.Start())
+
+	// Zombies left by other tests are reaped too, so only this one's events count
+	var events []ReapEvent
+	reaper := NewReaper(time.Hour, func(event ReapEvent) {
+		if event.PID == cmd.Process.Pid {
+			events = append(events, event)
+		}
+	})
+	waitForZombie(t, cmd.Process.Pid)
+
+	// It is left for a scan, in case whoever started it is about to wait for it
+	reaper.scan()
+	assert.Empty(t, events)
+	assert.Contains(t, zombieChildren(), cmd.Process.Pid)
+
+	reaper.scan()
+	assert.Equal(t, []ReapEvent{{PID: cmd.Process.Pid, ExitCode: 2, Orphan: true}}, events)
+	assert.NotContains(t, zombieChildren(), cmd.Process.Pid)
+}
@@ -0,0 +1,134 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// remoteExecutor runs commands somewhere else, like a remote host or a pod, by running
+// each through a client program like ssh or kubectl on a local executor.
+//
+// Processes started through it are the local client processes, so killing or signalling
+// one ends the remote command by closing its connection.
+type remoteExecutor struct {
+	local CommandExecutor
+	// wrap returns the local command that runs cmd remotely. tty requests a terminal on
+	// the remote side, for commands the user interacts with like tmux attach.
+	wrap func(cmd Command, tty bool) Command
+	// where names the remote side in errors
+	where string
+}
+
+// Basic execution
+
+func (e *remoteExecutor) Execute(ctx context.Context, cmd Command) (*Result, error) {
+	return e.local.Execute(ctx, e.wrap(cmd, false))
+}
+
+func (e *remoteExecutor) ExecuteWithInput(ctx context.Context, cmd Command, input []byte) (*Result, error) {
+	return e.local.ExecuteWithInput(ctx, e.wrap(cmd, false), input)
+}
+
+// Streaming execution
+
+func (e *remoteExecutor) ExecuteStreaming(ctx context.Context, cmd Command) (<-chan Output, error) {
+	return e.local.ExecuteStreaming(ctx, e.wrap(cmd, false))
+}
+
+func (e *remoteExecutor) ExecuteInteractive(ctx context.Context, cmd Command) (io.ReadWriteCloser, error) {
+	return e.local.ExecuteInteractive(ctx, e.wrap(cmd, false))
+}
+
+// Process management
+
+// Start gives the remote command a terminal when it is attached to the user's one
+func (e *remoteExecutor) Start(ctx context.Context, cmd Command) (ProcessHandle, error) {
+	return e.local.Start(ctx, e.wrap(cmd, isTerminal(cmd.Stdin)))
+}
+
+func (e *remoteExecutor) Kill(ctx context.Context, handle ProcessHandle) error {
+	return e.local.Kill(ctx, handle)
+}
+
+func (e *remoteExecutor) Signal(ctx context.Context, handle ProcessHandle, signal int) error {
+	return e.local.Signal(ctx, handle, signal)
+}
+
+// Cancel interrupts a process, with the drain timeout of the local executor
+func (e *remoteExecutor) Cancel(ctx context.Context, handle ProcessHandle) error {
+	if _, ok := handle.(*remoteProcess); !ok {
+		return e.local.Cancel(ctx, handle)
+	}
+	drain := defaultCancelDrain
+	if local, ok := e.local.(*execImpl); ok {
+		drain = local.cancelDrain()
+	}
+	return cancelProcess(ctx, handle, drain, remoteProcessPoll)
+}
+
+func (e *remoteExecutor) Wait(ctx context.Context, handle ProcessHandle) (*Result, error) {
+	return e.local.Wait(ctx, handle)
+}
+
+// Process information describes the local client processes
+
+func (e *remoteExecutor) GetProcessInfo(ctx context.Context, handle ProcessHandle) (*ProcessInfo, error) {
+	return e.local.GetProcessInfo(ctx, handle)
+}
+
+func (e *remoteExecutor) ListProcesses(ctx context.Context) ([]*ProcessInfo, error) {
+	return e.local.ListProcesses(ctx)
+}
+
+// FindProcess finds a process on the remote side, such as the program in a remote tmux
+// pane
+func (e *remoteExecutor) FindProcess(ctx context.Context, pid int) (ProcessHandle, error) {
+	p := &remoteProcess{pid: pid, executor: e}
+	if pid <= 0 || p.kill(0) != nil {
+		return nil, fmt.Errorf("process with PID %d not found on %s", pid, e.where)
+	}
+	return p, nil
+}
+
+// Utilities, answered by the remote side
+
+// remoteOutput runs a shell command on the remote side and returns its trimmed output
+func (e *remoteExecutor) remoteOutput(ctx context.Context, script string) (string, error) {
+	res, err := e.Execute(ctx, Command{Program: "sh", Args: []string{"-c", script}})
+	if err != nil {
+		return "", err
+	}
+	if res.ExitCode != 0 {
+		return "", fmt.Errorf("remote command failed (exit code %d): %s", res.ExitCode, strings.TrimSpace(string(res.Stderr)))
+	}
+	return strings.TrimSpace(string(res.Stdout)), nil
+}
+
+func (e *remoteExecutor) CommandExists(ctx context.Context, program string) bool {
+	_, err := e.Which(ctx, program)
+	return err == nil
+}
+
+func (e *remoteExecutor) Which(ctx context.Context, program string) (string, error) {
+	path, err := e.remoteOutput(ctx, "command -v "+Quote(program))
+	if err != nil || path == "" {
+		return "", fmt.Errorf("%s not found on %s", program, e.where)
+	}
+	return path, nil
+}
+
+func (e *remoteExecutor) GetEnvironment(ctx context.Context) []string {
+	env, err := e.remoteOutput(ctx, "env")
+	if err != nil || env == "" {
+		return nil
+	}
+	return strings.Split(env, "\n")
+}
+
+// GetWorkingDirectory returns the directory commands without a Dir run in, e.g. the
+// remote user's home directory
+func (e *remoteExecutor) GetWorkingDirectory(ctx context.Context) (string, error) {
+	return e.remoteOutput(ctx, "pwd")
+}
@@ -0,0 +1,87 @@
+package executor
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"regexp"
+	"slices"
+	"time"
+)
+
+// RetryPolicy decides whether a failed command is run again, and how long to wait first
+type RetryPolicy struct {
+	// Count is the number of retries after the first attempt
+	Count int
+	// Delay is the wait before the first retry
+	Delay time.Duration
+	// Multiplier grows the wait by this factor after each retry, e.g. 2 to double it. The
+	// wait stays the same when it is 1 or less.
+	Multiplier float64
+	// MaxDelay caps the wait, when non-zero
+	MaxDelay time.Duration
+	// Jitter randomly shortens or lengthens each wait by up to this fraction of it, e.g.
+	// 0.2 for 20%, so that commands failing together don't all retry together
+	Jitter float64
+	// OnExitCodes are the exit codes that are retried
+	OnExitCodes []int
+	// OnStderr retries a failed command whose captured stderr matches one of these, e.g.
+	// "index.lock': File exists" for git racing another git process
+	OnStderr []*regexp.Regexp
+}
+
+// retryPolicy returns the policy cmd is retried under: its own, or the executor's
+func (e *execImpl) retryPolicy(cmd Command) RetryPolicy {
+	if cmd.Retry != nil {
+		return *cmd.Retry
+	}
+	return RetryPolicy{
+		Count:       max(e.opts.RetryCount, 0),
+		Delay:       e.opts.RetryDelay,
+		Multiplier:  e.opts.RetryBackoff,
+		MaxDelay:    e.opts.RetryMaxDelay,
+		Jitter:      e.opts.RetryJitter,
+		OnExitCodes: e.opts.RetryOnErrors,
+		OnStderr:    e.opts.RetryOnStderr,
+	}
+}
+
+// retryable reports whether a failed attempt with the given exit code and stderr is retried
+func (p RetryPolicy) retryable(exitCode int, stderr []byte) bool {
+	if slices.Contains(p.OnExitCodes, exitCode) {
+		return true
+	}
+	for _, pattern := range p.OnStderr {
+		if pattern.Match(stderr) {
+			return true
+		}
+	}
+	return false
+}
+
+// delay returns the wait before the given retry, counting from 1
+func (p RetryPolicy) delay(retry int) time.Duration {
+	d := float64(p.Delay)
+	if p.Multiplier > 1 {
+		d *= math.Pow(p.Multiplier, float64(retry-1))
+	}
+	if p.MaxDelay > 0 {
+		d = math.Min(d, float64(p.MaxDelay))
+	}
+	if p.Jitter > 0 {
+		d += d * p.Jitter * (2*rand.Float64() - 1)
+	}
+	return time.Duration(d)
+}
+
+// sleepCtx waits for d, returning false if ctx is done first
+func sleepCtx(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,62 @@
+package executor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryDelay(t *testing.T) {
+	policy := RetryPolicy{Delay: 100 * time.Millisecond, Multiplier: 2, MaxDelay: time.Second}
+	assert.Equal(t, 100*time.Millisecond, policy.delay(1))
+	assert.Equal(t, 200*time.Millisecond, policy.delay(2))
+	assert.Equal(t, 800*time.Millisecond, policy.delay(4))
+	assert.Equal(t, time.Second, policy.delay(5))
+
+	policy.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		d := policy.delay(2)
+		assert.GreaterOrEqual(t, d, 100*time.Millisecond)
+		assert.LessOrEqual(t, d, 300*time.Millisecond)
+	}
+}
+
+func TestRetryOnStderr(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs a POSIX shell")
+	}
+	e := NewExecutor(&ExecutorOptions{DefaultTimeout: 10 * time.Second, CaptureOutput: true})
+	counter := filepath.Join(t.TempDir(), "attempts")
+
+	// Fails twice with a lock error, then prints its input
+	script := `n=$(cat "$0" 2>/dev/null || echo 0); echo $((n+1)) > "$0"
+[ "$n" -ge 2 ] || { echo "index.lock: File exists" >&2; exit 128; }
+cat`
+	retry := &RetryPolicy{Count: 3, Delay: time.Millisecond, Multiplier: 2, OnStderr: []*regexp.Regexp{regexp.MustCompile(`index\.lock`)}}
+
+	result, err := e.ExecuteWithInput(context.Background(), Command{Program: "sh", Args: []string{"-c", script, counter}, Retry: retry}, []byte("input"))
+	require.NoError(t, err)
+	assert.Zero(t, result.ExitCode)
+	assert.Equal(t, "input", string(result.Stdout))
+	attempts, err := os.ReadFile(counter)
+	require.NoError(t, err)
+	assert.Equal(t, "3", strings.TrimSpace(string(attempts)))
+
+	// Other failures aren't retried
+	require.NoError(t, os.Remove(counter))
+	retry.OnStderr = []*regexp.Regexp{regexp.MustCompile(`connection reset`)}
+	result, err = e.Execute(context.Background(), Command{Program: "sh", Args: []string{"-c", script, counter}, Retry: retry})
+	require.NoError(t, err)
+	assert.Equal(t, 128, result.ExitCode)
+	attempts, err = os.ReadFile(counter)
+	require.NoError(t, err)
+	assert.Equal(t, "1", strings.TrimSpace(string(attempts)))
+}
@@ -0,0 +1,82 @@
+package executor
+
+import (
+	"os"
+	"runtime"
+	"strings"
+)
+
+// Quote quotes s as a single word for a POSIX shell, leaving it as it is when that is safe
+func Quote(s string) string {
+	if s != "" && strings.IndexFunc(s, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-_./=:@,+%", r))
+	}) < 0 {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// QuoteArgs quotes each of args for a POSIX shell and joins them into a command line
+func QuoteArgs(args ...string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = Quote(arg)
+	}
+	return strings.Join(quoted, " ")
+}
+
+// BuildPipeline returns the POSIX shell command line piping the output of each command
+// into the next, with every argument quoted, e.g. `aider --model X | tee log` for
+// {"aider", "--model", "X"} and {"tee", "log"}. Run it with a Shell command.
+func BuildPipeline(commands ...[]string) string {
+	stages := make([]string, len(commands))
+	for i, argv := range commands {
+		stages[i] = QuoteArgs(argv...)
+	}
+	return strings.Join(stages, " | ")
+}
+
+// userShell returns the shell Shell commands run with locally and the flag it takes a
+// command line with: $SHELL, or sh, or cmd on Windows
+func userShell() (shell, flag string) {
+	if shell := os.Getenv("SHELL"); shell != "" {
+		return shell, "-c"
+	}
+	if runtime.GOOS == "windows" {
+		return "cmd", "/C"
+	}
+	return "/bin/sh", "-c"
+}
+
+// viaShell returns the command running a Shell command's line with shell, which takes it
+// after flag. Other commands are returned as they are.
+func viaShell(cmd Command, shell, flag string) Command {
+	if !cmd.Shell {
+		return cmd
+	}
+	line := cmd.Program
+	if len(cmd.Args) > 0 {
+		line += " " + QuoteArgs(cmd.Args...)
+	}
+	cmd.Program, cmd.Args, cmd.Shell = shell, []string{flag, line}, false
+	return cmd
+}
+
+// viaUserShell returns the command running a Shell command's line with the user's shell.
+// Its pool and env profile are still those of the program the line starts with.
+func (e *execImpl) viaUserShell(cmd Command) Command {
+	if !cmd.Shell {
+		return cmd
+	}
+	if fields := strings.Fields(cmd.Program); len(fields) > 0 {
+		program := Command{Program: fields[0]}
+		if cmd.Pool == "" {
+			cmd.Pool = poolName(program)
+		}
+		if cmd.EnvProfile == "" {
+			cmd.EnvProfile = e.envProfileName(program)
+		}
+	}
+	shell, flag := userShell()
+	return viaShell(cmd, shell, flag)
+}
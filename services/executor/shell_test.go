@@ -0,0 +1,55 @@
+package executor
+
+import (
+	"context"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuote(t *testing.T) {
+	assert.Equal(t, "--model=gpt-4", Quote("--model=gpt-4"))
+	assert.Equal(t, "''", Quote(""))
+	assert.Equal(t, `'it'\''s'`, Quote("it's"))
+	assert.Equal(t, "'$HOME; rm -rf /'", Quote("$HOME; rm -rf /"))
+	assert.Equal(t, `aider --model 'x y' | tee 'a log'`,
+		BuildPipeline([]string{"aider", "--model", "x y"}, []string{"tee", "a log"}))
+}
+
+func TestViaShell(t *testing.T) {
+	cmd := Command{Program: "aider --model X | tee log", Args: []string{"a b"}, Shell: true}
+	assert.Equal(t, Command{Program: "sh", Args: []string{"-c", "aider --model X | tee log 'a b'"}}, viaShell(cmd, "sh", "-c"))
+
+	// Remote executors run the line with sh on the remote side
+	assert.Equal(t, `cd /src && exec sh -c 'ls | wc -l'`, remoteCommand(Command{Program: "ls | wc -l", Dir: "/src", Shell: true}))
+
+	plain := Command{Program: "ls", Args: []string{"-l"}}
+	assert.Equal(t, plain, viaShell(plain, "sh", "-c"))
+}
+
+func TestExecuteShell(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a POSIX shell")
+	}
+	t.Setenv("SHELL", "/bin/sh")
+	e := NewExecutor(&ExecutorOptions{CaptureOutput: true, DefaultTimeout: 10 * time.Second})
+
+	result, err := e.Execute(context.Background(), Command{
+		Program: "printf '%s\\n' one two | tr a-z A-Z",
+		Shell:   true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "ONE\nTWO\n", string(result.Stdout))
+
+	// Appended arguments are quoted, so they can't inject commands
+	result, err = e.Execute(context.Background(), Command{
+		Program: "echo",
+		Args:    []string{"a; echo injected"},
+		Shell:   true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "a; echo injected\n", string(result.Stdout))
+}
@@ -0,0 +1,119 @@
+package executor
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// SSHOptions identifies the remote host an SSH executor runs commands on
+type SSHOptions struct {
+	// Host is the host name or an alias from ~/.ssh/config
+	Host string
+	// User logs in as this user instead of the one ssh would pick
+	User string
+	// Port connects to this port instead of the one ssh would pick, when non-zero
+	Port int
+	// KeyFile is the private key to authenticate with, in addition to the ssh agent
+	KeyFile string
+	// ControlDir holds the sockets of the shared connection ssh keeps open to the host,
+	// so each command doesn't pay for a new handshake. Multiplexing is off when empty.
+	ControlDir string
+}
+
+// sshExecutor runs commands on a remote host by running them through the ssh client on
+// a local executor. It relies on the user's ssh setup for host keys and agents, and runs
+// ssh in batch mode so a prompt for a password fails instead of hanging.
+type sshExecutor struct {
+	remoteExecutor
+	opts SSHOptions
+}
+
+// NewSSHExecutor creates an executor that runs every command on the host in opts, using
+// local to run ssh
+func NewSSHExecutor(local CommandExecutor, opts SSHOptions) (CommandExecutor, error) {
+	if opts.Host == "" {
+		return nil, fmt.Errorf("a host is required for the ssh executor")
+	}
+	if opts.ControlDir != "" {
+		if err := os.MkdirAll(opts.ControlDir, 0700); err != nil {
+			return nil, fmt.Errorf("failed to create ssh control directory: %w", err)
+		}
+	}
+	e := &sshExecutor{opts: opts}
+	e.remoteExecutor = remoteExecutor{local: local, wrap: e.command, where: opts.Host}
+	return e, nil
+}
+
+// remoteCommand returns the shell command line that runs cmd on the remote host, in its
+// directory and with its environment
+func remoteCommand(cmd Command) string {
+	// The remote user's shell is unknown, so shell commands run with sh there
+	cmd = viaShell(cmd, "sh", "-c")
+	var b strings.Builder
+	if cmd.Dir != "" {
+		b.WriteString("cd " + Quote(cmd.Dir) + " && ")
+	}
+	b.WriteString("exec ")
+	if len(cmd.Env) > 0 {
+		b.WriteString("env")
+		for _, kv := range cmd.Env {
+			b.WriteString(" " + Quote(kv))
+		}
+		b.WriteString(" ")
+	}
+	b.WriteString(Quote(cmd.Program))
+	for _, arg := range cmd.Args {
+		b.WriteString(" " + Quote(arg))
+	}
+	return b.String()
+}
+
+// command returns the local command that runs cmd over ssh
+func (e *sshExecutor) command(cmd Command, tty bool) Command {
+	args := []string{"-o", "BatchMode=yes"}
+	if e.opts.User != "" {
+		args = append(args, "-l", e.opts.User)
+	}
+	if e.opts.Port != 0 {
+		args = append(args, "-p", strconv.Itoa(e.opts.Port))
+	}
+	if e.opts.KeyFile != "" {
+		args = append(args, "-i", e.opts.KeyFile)
+	}
+	if e.opts.ControlDir != "" {
+		args = append(args, "-o", "ControlMaster=auto", "-o", "ControlPersist=10m",
+			"-o", "ControlPath="+e.opts.ControlDir+"/%C")
+	}
+	if tty {
+		args = append(args, "-t")
+	} else {
+		args = append(args, "-T")
+	}
+	args = append(args, e.opts.Host, "--", remoteCommand(cmd))
+
+	return Command{
+		Program:    "ssh",
+		Args:       args,
+		Stdin:      cmd.Stdin,
+		Timeout:    cmd.Timeout,
+		Stdout:     cmd.Stdout,
+		Stderr:     cmd.Stderr,
+		StdoutFile: cmd.StdoutFile,
+		StderrFile: cmd.StderrFile,
+		Limits:     cmd.Limits,
+		Retry:      cmd.Retry,
+		Framing:    cmd.Framing,
+		Pool:       poolName(cmd),
+	}
+}
+
+// isTerminal reports whether r is the user's terminal
+func isTerminal(r io.Reader) bool {
+	f, ok := r.(*os.File)
+	return ok && term.IsTerminal(int(f.Fd()))
+}
@@ -0,0 +1,32 @@
+package executor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRemoteCommand(t *testing.T) {
+	cmd := Command{
+		Program: "git",
+		Args:    []string{"commit", "-m", "it's done"},
+		Dir:     "/home/me/my repo",
+		Env:     []string{"GIT_AUTHOR_NAME=A B"},
+	}
+	assert.Equal(t, `cd '/home/me/my repo' && exec env 'GIT_AUTHOR_NAME=A B' git commit -m 'it'\''s done'`, remoteCommand(cmd))
+	assert.Equal(t, "exec tmux ls", remoteCommand(Command{Program: "tmux", Args: []string{"ls"}}))
+	assert.Equal(t, "exec echo ''", remoteCommand(Command{Program: "echo", Args: []string{""}}))
+}
+
+func TestSSHWrap(t *testing.T) {
+	local := NewExecutor(nil)
+	e, err := NewSSHExecutor(local, SSHOptions{Host: "devbox", User: "me", Port: 2222})
+	assert.NoError(t, err)
+
+	wrapped := e.(*sshExecutor).wrap(Command{Program: "tmux", Args: []string{"attach", "-t", "s"}}, true)
+	assert.Equal(t, "ssh", wrapped.Program)
+	assert.Equal(t, []string{"-o", "BatchMode=yes", "-l", "me", "-p", "2222", "-t", "devbox", "--", "exec tmux attach -t s"}, wrapped.Args)
+
+	_, err = NewSSHExecutor(local, SSHOptions{})
+	assert.Error(t, err)
+}
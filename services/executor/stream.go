@@ -0,0 +1,84 @@
+package executor
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"sync"
+	"time"
+)
+
+// OutputFraming is how ExecuteStreaming divides a command's output into Outputs
+type OutputFraming int
+
+const (
+	// FramingChunks sends output as it is read, in chunks of up to 4KB that may end
+	// mid-line
+	FramingChunks OutputFraming = iota
+	// FramingLines sends one Output per line, without its line ending, so consumers
+	// like prompt detection don't have to reassemble lines. Lines longer than
+	// maxLineLength are sent in parts.
+	FramingLines
+)
+
+// maxLineLength is the longest line FramingLines sends in one Output
+const maxLineLength = 64 * 1024
+
+// outputStream sends the Outputs of one command, numbering them in the order they are sent
+type outputStream struct {
+	ch  chan<- Output
+	mu  sync.Mutex
+	seq uint64
+}
+
+func (s *outputStream) send(out Output) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.seq++
+	out.Seq = s.seq
+	out.Timestamp = time.Now()
+	s.ch <- out
+}
+
+// pump sends everything read from r as Outputs of type typ, framed as framing asks
+func (s *outputStream) pump(r io.Reader, typ OutputType, framing OutputFraming) {
+	var err error
+	if framing == FramingLines {
+		err = s.pumpLines(r, typ)
+	} else {
+		err = s.pumpChunks(r, typ)
+	}
+	if err != nil && err != io.EOF {
+		s.send(Output{Type: OutputTypeError, Error: err})
+	}
+}
+
+func (s *outputStream) pumpChunks(r io.Reader, typ OutputType) error {
+	buf := make([]byte, 4096)
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			s.send(Output{Type: typ, Data: append([]byte{}, buf[:n]...)})
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (s *outputStream) pumpLines(r io.Reader, typ OutputType) error {
+	br := bufio.NewReaderSize(r, maxLineLength)
+	for {
+		line, err := br.ReadSlice('\n')
+		if err == nil {
+			line = bytes.TrimSuffix(bytes.TrimSuffix(line, []byte("\n")), []byte("\r"))
+		}
+		// A final line without a line ending is still a line
+		if err == nil || len(line) > 0 {
+			s.send(Output{Type: typ, Data: append([]byte{}, line...)})
+		}
+		if err != nil && err != bufio.ErrBufferFull {
+			return err
+		}
+	}
+}
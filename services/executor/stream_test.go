@@ -0,0 +1,56 @@
+package executor
+
+import (
+	"context"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamingLines(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs a POSIX shell")
+	}
+	e := NewExecutor(&ExecutorOptions{DefaultTimeout: 10 * time.Second})
+
+	ch, err := e.ExecuteStreaming(context.Background(), Command{
+		Program: "sh",
+		Args:    []string{"-c", `printf 'one\ntw'; sleep 0.1; printf 'o\r\n'; printf 'warn\n' >&2; printf 'partial'`},
+		Framing: FramingLines,
+	})
+	require.NoError(t, err)
+
+	var stdout, stderr []string
+	var last Output
+	for out := range ch {
+		assert.Equal(t, last.Seq+1, out.Seq)
+		last = out
+		switch out.Type {
+		case OutputTypeStdout:
+			stdout = append(stdout, string(out.Data))
+		case OutputTypeStderr:
+			stderr = append(stderr, string(out.Data))
+		}
+	}
+	assert.Equal(t, []string{"one", "two", "partial"}, stdout)
+	assert.Equal(t, []string{"warn"}, stderr)
+	assert.Equal(t, OutputTypeExit, last.Type)
+	assert.Equal(t, "0", string(last.Data))
+}
+
+func TestStreamingLongLines(t *testing.T) {
+	ch := make(chan Output, 10)
+	s := &outputStream{ch: ch}
+	s.pump(strings.NewReader(strings.Repeat("x", maxLineLength+10)+"\n"), OutputTypeStdout, FramingLines)
+	close(ch)
+
+	var lengths []int
+	for out := range ch {
+		lengths = append(lengths, len(out.Data))
+	}
+	assert.Equal(t, []int{maxLineLength, 10}, lengths)
+}
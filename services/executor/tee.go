@@ -0,0 +1,126 @@
+package executor
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// openOutputFile opens a file for a command's output to be appended to, creating it and
+// its directory if needed
+func openOutputFile(path string) (*os.File, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open output file: %w", err)
+	}
+	return f, nil
+}
+
+// outputSinks returns the writers a copy of cmd's stdout and stderr go to: its Stdout and
+// Stderr, and its output files, opened for appending. Either is nil when cmd has none.
+// closeFiles closes the files once the command has exited.
+func outputSinks(cmd Command) (stdout, stderr io.Writer, closeFiles func(), err error) {
+	var files []*os.File
+	closeFiles = func() {
+		for _, f := range files {
+			f.Close()
+		}
+	}
+	open := func(path string) (io.Writer, error) {
+		if path == "" {
+			return nil, nil
+		}
+		f, err := openOutputFile(path)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, f)
+		return f, nil
+	}
+
+	stdoutFile, err := open(cmd.StdoutFile)
+	if err != nil {
+		return nil, nil, closeFiles, err
+	}
+	stderrFile := stdoutFile
+	if cmd.StderrFile != cmd.StdoutFile {
+		if stderrFile, err = open(cmd.StderrFile); err != nil {
+			closeFiles()
+			return nil, nil, func() {}, err
+		}
+	}
+	return combineWriters(cmd.Stdout, stdoutFile), combineWriters(cmd.Stderr, stderrFile), closeFiles, nil
+}
+
+// combineWriters returns a writer writing to each of the non-nil writers, or nil if there
+// are none
+func combineWriters(writers ...io.Writer) io.Writer {
+	var nonNil []io.Writer
+	for _, w := range writers {
+		if w != nil {
+			nonNil = append(nonNil, w)
+		}
+	}
+	switch len(nonNil) {
+	case 0:
+		return nil
+	case 1:
+		return nonNil[0]
+	default:
+		return io.MultiWriter(nonNil...)
+	}
+}
+
+// teeReader returns r, copying what is read from it to w when w isn't nil
+func teeReader(r io.Reader, w io.Writer) io.Reader {
+	if w == nil {
+		return r
+	}
+	return io.TeeReader(r, w)
+}
+
+// sessionLogFile returns the file the output of the tmux session with the given name is
+// mirrored to: the command's own output file, or one named after the session in the
+// executor's session log directory
+func (e *execImpl) sessionLogFile(cmd Command, name string) string {
+	if cmd.StdoutFile != "" {
+		return cmd.StdoutFile
+	}
+	if e.opts.SessionLogDir == "" || name == "" {
+		return ""
+	}
+	return filepath.Join(e.opts.SessionLogDir, strings.NewReplacer("/", "_", `\`, "_").Replace(name)+".log")
+}
+
+// pipeSessionOutput makes a tmux command starting a session mirror everything its pane
+// shows to the session's log file with pipe-pane, as the program in the pane writes to
+// the terminal rather than to tmux's output. The file is then not written to directly.
+func (e *execImpl) pipeSessionOutput(cmd *Command) error {
+	i := newSessionIndex(*cmd)
+	if i < 0 {
+		return nil
+	}
+	_, name, _, _ := parseNewSession(cmd.Args[i:])
+	path := e.sessionLogFile(*cmd, name)
+	if path == "" || name == "" {
+		return nil
+	}
+	// Created up front so it isn't readable by others, as cat would leave it
+	f, err := openOutputFile(path)
+	if err != nil {
+		return err
+	}
+	f.Close()
+
+	cmd.Args = append(cmd.Args[:len(cmd.Args):len(cmd.Args)], ";", "pipe-pane", "-t", name, "cat >> "+Quote(path))
+	if cmd.StderrFile == cmd.StdoutFile {
+		cmd.StderrFile = ""
+	}
+	cmd.StdoutFile = ""
+	return nil
+}
@@ -0,0 +1,80 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutputTee(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a POSIX shell")
+	}
+	e := NewExecutor(&ExecutorOptions{CaptureOutput: true, DefaultTimeout: 10 * time.Second})
+	ctx := context.Background()
+	logFile := filepath.Join(t.TempDir(), "logs", "run.log")
+
+	var stdout bytes.Buffer
+	result, err := e.Execute(ctx, Command{
+		Program:    "sh",
+		Args:       []string{"-c", "echo out; echo err >&2"},
+		Stdout:     &stdout,
+		StdoutFile: logFile,
+		StderrFile: logFile,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "out\n", string(result.Stdout))
+	assert.Equal(t, "err\n", string(result.Stderr))
+	assert.Equal(t, "out\n", stdout.String())
+
+	outputs, err := e.ExecuteStreaming(ctx, Command{Program: "sh", Args: []string{"-c", "echo streamed"}, StdoutFile: logFile})
+	require.NoError(t, err)
+	for range outputs {
+	}
+
+	handle, err := e.Start(ctx, Command{Program: "sh", Args: []string{"-c", "echo started"}, StdoutFile: logFile})
+	require.NoError(t, err)
+	_, err = handle.Wait()
+	require.NoError(t, err)
+
+	// Output is appended, stdout and stderr interleaved
+	data, err := os.ReadFile(logFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "out\n")
+	assert.Contains(t, string(data), "err\n")
+	assert.True(t, bytes.HasSuffix(data, []byte("streamed\nstarted\n")))
+	info, err := os.Stat(logFile)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+}
+
+func TestPipeSessionOutput(t *testing.T) {
+	dir := t.TempDir()
+	e := NewExecutor(&ExecutorOptions{SessionLogDir: dir}).(*execImpl)
+
+	cmd := Command{Program: "tmux", Args: []string{"new-session", "-d", "-s", "claudesquad_fix", "-c", "/src", "claude"}}
+	require.NoError(t, e.pipeSessionOutput(&cmd))
+	logFile := filepath.Join(dir, "claudesquad_fix.log")
+	assert.Equal(t, []string{"new-session", "-d", "-s", "claudesquad_fix", "-c", "/src", "claude",
+		";", "pipe-pane", "-t", "claudesquad_fix", "cat >> " + Quote(logFile)}, cmd.Args)
+	assert.FileExists(t, logFile)
+
+	// A session's own output file wins over the directory's
+	own := filepath.Join(dir, "own.log")
+	cmd = Command{Program: "tmux", Args: []string{"new-session", "-d", "-s", "s"}, StdoutFile: own}
+	require.NoError(t, e.pipeSessionOutput(&cmd))
+	assert.Equal(t, "cat >> "+Quote(own), cmd.Args[len(cmd.Args)-1])
+	assert.Empty(t, cmd.StdoutFile)
+
+	// Other commands are left alone
+	cmd = Command{Program: "tmux", Args: []string{"list-sessions"}}
+	require.NoError(t, e.pipeSessionOutput(&cmd))
+	assert.Equal(t, []string{"list-sessions"}, cmd.Args)
+}
@@ -0,0 +1,63 @@
+package executor
+
+import (
+	"time"
+)
+
+// processUsage is a sample of the resources a process uses
+type processUsage struct {
+	started time.Time
+	rss     uint64
+	// cpu is the CPU time the process has used since it started
+	cpu      time.Duration
+	children int
+}
+
+// cpuSample is the CPU time a process had used at some point, which the next sample's CPU
+// use is worked out from
+type cpuSample struct {
+	cpu time.Duration
+	at  time.Time
+}
+
+// withUsage fills in the resource usage of each of the processes, sampling them together.
+// Processes whose usage can't be sampled are left as they are.
+func (e *execImpl) withUsage(processes ...*ProcessInfo) {
+	pids := make([]int, len(processes))
+	for i, p := range processes {
+		pids[i] = p.PID
+	}
+	usage := sampleUsage(pids)
+	now := time.Now()
+
+	e.usageMu.Lock()
+	defer e.usageMu.Unlock()
+	for _, p := range processes {
+		u, ok := usage[p.PID]
+		if !ok {
+			continue
+		}
+		if p.StartTime.IsZero() {
+			p.StartTime = u.started
+		}
+		p.RSS = u.rss
+		p.Children = u.children
+
+		// CPU use since the last sample, or over the process's lifetime at first
+		last, ok := e.cpuSamples[p.PID]
+		if !ok {
+			last = cpuSample{at: p.StartTime}
+		}
+		if elapsed := now.Sub(last.at); elapsed > 0 && !last.at.IsZero() {
+			p.CPUPercent = max(0, float64(u.cpu-last.cpu)/float64(elapsed)*100)
+		}
+		e.cpuSamples[p.PID] = cpuSample{cpu: u.cpu, at: now}
+	}
+}
+
+// forgetUsage drops the last CPU sample of a process that has exited
+func (e *execImpl) forgetUsage(pid int) {
+	e.usageMu.Lock()
+	defer e.usageMu.Unlock()
+	delete(e.cpuSamples, pid)
+}
@@ -0,0 +1,111 @@
+package executor
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// clockTicks is the unit of the times in /proc, which Linux fixes at 100 per second
+const clockTicks = 100
+
+// The fields of /proc/<pid>/stat used, counted from the process's state
+const (
+	statState     = 0
+	statParent    = 1
+	statUserTime  = 11
+	statSysTime   = 12
+	statStartTime = 19
+	statRSS       = 21
+)
+
+// procStats returns the fields of /proc/<pid>/stat of every process, by PID, starting at
+// its state
+func procStats() map[int][]string {
+	paths, _ := filepath.Glob("/proc/[0-9]*/stat")
+	stats := make(map[int][]string, len(paths))
+	for _, path := range paths {
+		pid, err := strconv.Atoi(filepath.Base(filepath.Dir(path)))
+		if err != nil {
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		// The command name in parentheses may itself contain spaces and parentheses
+		stat := string(data)
+		if fields := strings.Fields(stat[strings.LastIndexByte(stat, ')')+1:]); len(fields) > statRSS {
+			stats[pid] = fields
+		}
+	}
+	return stats
+}
+
+// bootTime is when the system booted, which processes' start times are relative to
+var bootTime = sync.OnceValue(func() time.Time {
+	f, err := os.Open("/proc/stat")
+	if err != nil {
+		return time.Time{}
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if value, ok := strings.CutPrefix(scanner.Text(), "btime "); ok {
+			if secs, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64); err == nil {
+				return time.Unix(secs, 0)
+			}
+		}
+	}
+	return time.Time{}
+})
+
+// sampleUsage samples the resource usage of the processes with the given PIDs that are
+// running. Their children are all the processes descended from them.
+func sampleUsage(pids []int) map[int]processUsage {
+	stats := procStats()
+	children := make(map[int][]int)
+	for pid, fields := range stats {
+		if parent, err := strconv.Atoi(fields[statParent]); err == nil {
+			children[parent] = append(children[parent], pid)
+		}
+	}
+	field := func(fields []string, i int) uint64 {
+		n, _ := strconv.ParseUint(fields[i], 10, 64)
+		return n
+	}
+
+	usage := make(map[int]processUsage, len(pids))
+	for _, pid := range pids {
+		fields, ok := stats[pid]
+		if !ok {
+			continue
+		}
+		u := processUsage{
+			rss: field(fields, statRSS) * uint64(os.Getpagesize()),
+			cpu: time.Duration(field(fields, statUserTime)+field(fields, statSysTime)) * time.Second / clockTicks,
+		}
+		if boot := bootTime(); !boot.IsZero() {
+			u.started = boot.Add(time.Duration(field(fields, statStartTime)) * time.Second / clockTicks)
+		}
+		for queue := children[pid]; len(queue) > 0; queue = queue[1:] {
+			u.children++
+			queue = append(queue, children[queue[0]]...)
+		}
+		usage[pid] = u
+	}
+	return usage
+}
+
+// processArgs returns the command line of the process with the given PID
+func processArgs(pid int) []string {
+	data, err := os.ReadFile(filepath.Join("/proc", strconv.Itoa(pid), "cmdline"))
+	if err != nil {
+		return nil
+	}
+	return strings.Split(strings.TrimRight(string(data), "\x00"), "\x00")
+}
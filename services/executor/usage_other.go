@@ -0,0 +1,12 @@
+//go:build !linux
+
+package executor
+
+// sampleUsage samples nothing, as only Linux lists processes' usage cheaply
+func sampleUsage(pids []int) map[int]processUsage {
+	return nil
+}
+
+func processArgs(pid int) []string {
+	return nil
+}
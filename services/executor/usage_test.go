@@ -0,0 +1,47 @@
+//go:build linux
+
+package executor
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessUsage(t *testing.T) {
+	e := NewExecutor(&ExecutorOptions{DefaultTimeout: 10 * time.Second})
+	handle, err := e.Start(context.Background(), Command{Program: "sh", Args: []string{"-c", "sleep 5 & sleep 5 & wait"}})
+	require.NoError(t, err)
+	defer handle.Kill()
+
+	require.Eventually(t, func() bool {
+		info, err := e.GetProcessInfo(context.Background(), handle)
+		return err == nil && info.Children == 2
+	}, time.Second, 10*time.Millisecond)
+
+	processes, err := e.ListProcesses(context.Background())
+	require.NoError(t, err)
+	require.Len(t, processes, 1)
+	assert.Equal(t, handle.PID(), processes[0].PID)
+	assert.Positive(t, processes[0].RSS)
+	assert.GreaterOrEqual(t, processes[0].CPUPercent, 0.0)
+	assert.Equal(t, 2, processes[0].Children)
+}
+
+func TestExternalProcessUsage(t *testing.T) {
+	e := NewExecutor(nil)
+	handle, err := e.FindProcess(context.Background(), os.Getpid())
+	require.NoError(t, err)
+
+	info, err := e.GetProcessInfo(context.Background(), handle)
+	require.NoError(t, err)
+	assert.Equal(t, os.Getpid(), info.PID)
+	assert.Equal(t, ProcessStateRunning, info.State)
+	assert.Equal(t, os.Args, info.Args)
+	assert.Positive(t, info.RSS)
+	assert.WithinDuration(t, time.Now(), info.StartTime, time.Hour)
+}
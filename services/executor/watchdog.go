@@ -0,0 +1,117 @@
+package executor
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// StallEvent reports a command that has gone silent for the stall timeout, or that has
+// produced output again after doing so
+type StallEvent struct {
+	// Command is the redacted command line
+	Command string
+	// Silent is how long the command had gone without output
+	Silent time.Duration
+	// Resumed is set when the command produces output again after stalling
+	Resumed bool
+}
+
+// stallWatch watches a running command's output for it going silent
+type stallWatch struct {
+	command string
+	timeout time.Duration
+	report  func(StallEvent)
+
+	mu      sync.Mutex
+	last    time.Time
+	stalled bool
+	timer   *time.Timer
+}
+
+// watchStall starts watching cmd for going without output for the stall timeout, until
+// stop is called. It returns nil, on which the methods do nothing, when there is no stall
+// timeout or nobody to tell.
+func (e *execImpl) watchStall(cmd Command) *stallWatch {
+	if e.opts.StallTimeout <= 0 || e.opts.OnStall == nil {
+		return nil
+	}
+	w := &stallWatch{
+		command: e.redact(fmt.Sprintf("%s %v", cmd.Program, cmd.Args)),
+		timeout: e.opts.StallTimeout,
+		report:  e.opts.OnStall,
+		last:    time.Now(),
+	}
+	// Held so that a timer firing straight away sees itself assigned
+	w.mu.Lock()
+	w.timer = time.AfterFunc(w.timeout, w.fire)
+	w.mu.Unlock()
+	return w
+}
+
+func (w *stallWatch) fire() {
+	w.mu.Lock()
+	if w.stalled || w.timer == nil {
+		w.mu.Unlock()
+		return
+	}
+	w.stalled = true
+	event := StallEvent{Command: w.command, Silent: time.Since(w.last)}
+	w.mu.Unlock()
+	w.report(event)
+}
+
+// touch records output from the command, rearming the timeout
+func (w *stallWatch) touch() {
+	if w == nil {
+		return
+	}
+	w.mu.Lock()
+	if w.timer == nil {
+		w.mu.Unlock()
+		return
+	}
+	now := time.Now()
+	silent := now.Sub(w.last)
+	resumed := w.stalled
+	w.last, w.stalled = now, false
+	w.timer.Reset(w.timeout)
+	w.mu.Unlock()
+	if resumed {
+		w.report(StallEvent{Command: w.command, Silent: silent, Resumed: true})
+	}
+}
+
+// stop stops watching once the command has exited
+func (w *stallWatch) stop() {
+	if w == nil {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.timer != nil {
+		w.timer.Stop()
+		w.timer = nil
+	}
+}
+
+// writer returns a writer that records output written to it as the command's, or nil
+// when nothing is watched
+func (w *stallWatch) writer() io.Writer {
+	if w == nil {
+		return nil
+	}
+	return stallWriter{w}
+}
+
+type stallWriter struct {
+	watch *stallWatch
+}
+
+func (s stallWriter) Write(p []byte) (int, error) {
+	if len(p) > 0 {
+		s.watch.touch()
+	}
+	return len(p), nil
+}
@@ -0,0 +1,82 @@
+package executor
+
+import (
+	"context"
+	"os/exec"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStallWatch(t *testing.T) {
+	var mu sync.Mutex
+	var events []StallEvent
+	e := NewExecutor(&ExecutorOptions{
+		StallTimeout: 20 * time.Millisecond,
+		OnStall: func(event StallEvent) {
+			mu.Lock()
+			defer mu.Unlock()
+			events = append(events, event)
+		},
+	}).(*execImpl)
+	got := func() []StallEvent {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]StallEvent(nil), events...)
+	}
+
+	w := e.watchStall(Command{Program: "agent", Args: []string{"run"}})
+	require.NotNil(t, w)
+	require.Eventually(t, func() bool { return len(got()) == 1 }, time.Second, 5*time.Millisecond)
+	assert.Equal(t, "agent [run]", got()[0].Command)
+	assert.False(t, got()[0].Resumed)
+	assert.GreaterOrEqual(t, got()[0].Silent, 20*time.Millisecond)
+
+	// A stall is reported once, and output after it is reported as resuming
+	time.Sleep(50 * time.Millisecond)
+	require.Len(t, got(), 1)
+	w.writer().Write([]byte("x"))
+	require.Len(t, got(), 2)
+	assert.True(t, got()[1].Resumed)
+
+	w.stop()
+	time.Sleep(50 * time.Millisecond)
+	assert.Len(t, got(), 2)
+}
+
+func TestStallWatchDisabled(t *testing.T) {
+	e := NewExecutor(&ExecutorOptions{StallTimeout: time.Second}).(*execImpl)
+	w := e.watchStall(Command{Program: "agent"})
+	assert.Nil(t, w)
+	assert.Nil(t, w.writer())
+	w.touch()
+	w.stop()
+}
+
+func TestExecuteStalls(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not installed")
+	}
+	stalls := make(chan StallEvent, 10)
+	e := NewExecutor(&ExecutorOptions{
+		DefaultTimeout: 10 * time.Second,
+		CaptureOutput:  true,
+		StallTimeout:   100 * time.Millisecond,
+		OnStall:        func(event StallEvent) { stalls <- event },
+	})
+
+	result, err := e.Execute(context.Background(), Command{Program: "sh", Args: []string{"-c", "sleep 0.3; echo done"}})
+	require.NoError(t, err)
+	assert.Equal(t, "done\n", string(result.Stdout))
+	require.Len(t, stalls, 2)
+	assert.False(t, (<-stalls).Resumed)
+	assert.True(t, (<-stalls).Resumed)
+
+	// Commands that keep producing output don't stall
+	_, err = e.Execute(context.Background(), Command{Program: "sh", Args: []string{"-c", "for i in 1 2 3 4; do echo $i; sleep 0.05; done"}})
+	require.NoError(t, err)
+	assert.Empty(t, stalls)
+}
@@ -0,0 +1,47 @@
+package forge
+
+import (
+	"net/url"
+	"os"
+	"strings"
+
+	"claude-squad/services/executor"
+	"claude-squad/services/git"
+)
+
+// Detect works out which kind of forge hosts the repository behind remoteURL. hosts maps
+// host names to kinds for self-hosted forges whose name doesn't give them away. Other
+// hosts are GitLab or Gitea when their name says so, like gitlab.example.com, and GitHub
+// otherwise.
+func Detect(remoteURL string, hosts map[string]string) Kind {
+	web, err := url.Parse(git.RepoWebURL(remoteURL))
+	if err != nil || web.Host == "" {
+		return KindGitHub
+	}
+	host := strings.ToLower(web.Hostname())
+	if kind, ok := hosts[host]; ok {
+		return Kind(kind)
+	}
+	switch {
+	case strings.Contains(host, "gitlab"):
+		return KindGitLab
+	case strings.Contains(host, "gitea"), host == "codeberg.org":
+		return KindGitea
+	default:
+		return KindGitHub
+	}
+}
+
+// New creates the forge of the given kind for the repository behind remoteURL. GitHub and
+// GitLab are used through their CLIs run by exec; Gitea through its API, with the access
+// token in $GITEA_TOKEN.
+func New(kind Kind, remoteURL string, exec executor.CommandExecutor) Forge {
+	switch kind {
+	case KindGitLab:
+		return NewGitLab(exec)
+	case KindGitea:
+		return NewGitea(remoteURL, os.Getenv("GITEA_TOKEN"))
+	default:
+		return NewGitHub(exec)
+	}
+}
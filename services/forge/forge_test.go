@@ -0,0 +1,124 @@
+package forge
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"claude-squad/services/executor"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetect(t *testing.T) {
+	hosts := map[string]string{"git.example.com": "gitlab"}
+	tests := map[string]Kind{
+		"git@github.com:owner/repo.git":            KindGitHub,
+		"https://gitlab.com/group/sub/repo.git":    KindGitLab,
+		"git@gitlab.example.com:owner/repo.git":    KindGitLab,
+		"https://codeberg.org/owner/repo.git":      KindGitea,
+		"https://gitea.example.com/owner/repo.git": KindGitea,
+		"ssh://git@git.example.com:2222/owner/r":   KindGitLab,
+		"/srv/git/repo.git":                        KindGitHub,
+	}
+	for remote, want := range tests {
+		assert.Equal(t, want, Detect(remote, hosts), remote)
+	}
+
+	assert.IsType(t, &gitlabForge{}, New(KindGitLab, "https://gitlab.com/o/r.git", &executor.MockExecutor{}))
+	gitea := New(KindGitea, "git@codeberg.org:owner/repo.git", &executor.MockExecutor{}).(*giteaForge)
+	assert.Equal(t, "https://codeberg.org/api/v1/repos/owner/repo", gitea.repoAPI)
+}
+
+func TestGitLab(t *testing.T) {
+	ctx := context.Background()
+	results := map[string]*executor.Result{
+		"mr": {Stdout: []byte(`{"iid":7,"web_url":"https://gitlab.com/o/r/-/merge_requests/7","title":"Fix login",
+			"source_branch":"fix","target_branch":"main","state":"opened","draft":true,"has_conflicts":true}`)},
+		"ci": {Stdout: []byte(`{"id":1,"status":"failed","jobs":[
+			{"name":"test","stage":"test","status":"success","web_url":"https://ci/1"},
+			{"name":"lint","stage":"test","status":"failed","web_url":"https://ci/2","allow_failure":true},
+			{"name":"build","stage":"build","status":"failed","web_url":"https://ci/3"},
+			{"name":"deploy","stage":"deploy","status":"created"}]}`)},
+	}
+	glab := NewGitLab(&executor.MockExecutor{ExecuteFunc: func(ctx context.Context, cmd executor.Command) (*executor.Result, error) {
+		assert.Equal(t, "glab", cmd.Program)
+		return results[cmd.Args[0]], nil
+	}})
+
+	pr, err := glab.GetPRStatus(ctx, "/src/app", "fix")
+	require.NoError(t, err)
+	assert.Equal(t, &PullRequest{
+		Number: 7, URL: "https://gitlab.com/o/r/-/merge_requests/7", Title: "Fix login", Head: "fix", Base: "main",
+		State: PROpen, Draft: true, Mergeable: "CONFLICTING",
+	}, pr)
+
+	checks, err := glab.ListChecks(ctx, "/src/app", "fix")
+	require.NoError(t, err)
+	assert.Equal(t, []Check{
+		{Name: "test", State: CheckPass, Workflow: "test", URL: "https://ci/1"},
+		{Name: "lint", State: CheckSkipped, Workflow: "test", URL: "https://ci/2"},
+		{Name: "build", State: CheckFail, Workflow: "build", URL: "https://ci/3"},
+		{Name: "deploy", State: CheckPending, Workflow: "deploy"},
+	}, checks)
+
+	results["mr"] = &executor.Result{ExitCode: 1, Stderr: []byte(`no open merge request available for "fix"`)}
+	_, err = glab.GetPRStatus(ctx, "/src/app", "fix")
+	assert.ErrorIs(t, err, ErrNoPullRequest)
+}
+
+func TestGitea(t *testing.T) {
+	ctx := context.Background()
+	var created map[string]string
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/v1/repos/o/r/pulls", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "token secret", r.Header.Get("Authorization"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&created))
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"number":3,"html_url":"https://gitea/o/r/pulls/3","title":"WIP: Fix login","state":"open",
+			"mergeable":true,"head":{"ref":"fix"},"base":{"ref":"main"}}`))
+	})
+	mux.HandleFunc("GET /api/v1/repos/o/r/pulls", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[
+			{"number":2,"title":"Old","state":"closed","merged":true,"head":{"ref":"fix"},"base":{"ref":"main"}},
+			{"number":4,"title":"Other","state":"open","head":{"ref":"other"},"base":{"ref":"main"}},
+			{"number":3,"title":"Fix login","state":"open","mergeable":true,"head":{"ref":"fix"},"base":{"ref":"main"}}]`))
+	})
+	mux.HandleFunc("GET /api/v1/repos/o/r/commits/fix/status", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"state":"failure","statuses":[
+			{"context":"ci/test","status":"success","target_url":"https://ci/1"},
+			{"context":"ci/lint","status":"failure","target_url":"https://ci/2"}]}`))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	gitea := &giteaForge{repoAPI: srv.URL + "/api/v1/repos/o/r", token: "secret", http: srv.Client()}
+
+	pr, err := gitea.CreatePR(ctx, "/src/app", CreatePROptions{Head: "fix", Base: "main", Title: "Fix login", Body: "body", Draft: true})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"head": "fix", "base": "main", "title": "WIP: Fix login", "body": "body"}, created)
+	assert.Equal(t, "https://gitea/o/r/pulls/3", pr.URL)
+	assert.True(t, pr.Draft)
+
+	pr, err = gitea.GetPRStatus(ctx, "/src/app", "fix")
+	require.NoError(t, err)
+	assert.Equal(t, 3, pr.Number)
+	assert.Equal(t, PROpen, pr.State)
+	assert.Equal(t, "MERGEABLE", pr.Mergeable)
+	_, err = gitea.GetPRStatus(ctx, "/src/app", "missing")
+	assert.ErrorIs(t, err, ErrNoPullRequest)
+
+	checks, err := gitea.ListChecks(ctx, "/src/app", "fix")
+	require.NoError(t, err)
+	assert.Equal(t, []Check{
+		{Name: "ci/test", State: CheckPass, URL: "https://ci/1"},
+		{Name: "ci/lint", State: CheckFail, URL: "https://ci/2"},
+	}, checks)
+
+	// Without a token nothing is requested
+	gitea.token = ""
+	_, err = gitea.ListChecks(ctx, "/src/app", "fix")
+	assert.ErrorContains(t, err, "GITEA_TOKEN")
+}
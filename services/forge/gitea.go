@@ -0,0 +1,206 @@
+package forge
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"claude-squad/services/git"
+)
+
+// giteaPageSize is how many of a repository's most recently updated pull requests are
+// searched for a branch's
+const giteaPageSize = 50
+
+// giteaForge implements Forge with the API of a Gitea instance, such as Codeberg. The tea
+// CLI can't report commit statuses, so unlike GitHub and GitLab no CLI is used.
+type giteaForge struct {
+	// repoAPI is the API URL of the repository, e.g.
+	// https://gitea.example.com/api/v1/repos/owner/repo
+	repoAPI string
+	token   string
+	http    *http.Client
+}
+
+// NewGitea creates a Forge for the Gitea repository behind remoteURL, authenticating with
+// an access token
+func NewGitea(remoteURL, token string) Forge {
+	g := &giteaForge{token: token, http: &http.Client{Timeout: 30 * time.Second}}
+	if web := git.RepoWebURL(remoteURL); web != "" {
+		if u, err := url.Parse(web); err == nil {
+			g.repoAPI = u.Scheme + "://" + u.Host + "/api/v1/repos" + u.Path
+		}
+	}
+	return g
+}
+
+func (g *giteaForge) Available(ctx context.Context) error {
+	if g.repoAPI == "" {
+		return fmt.Errorf("the remote isn't a Gitea repository")
+	}
+	if g.token == "" {
+		return fmt.Errorf("set GITEA_TOKEN to a Gitea access token for pull requests")
+	}
+	return nil
+}
+
+// giteaPullRequest is a pull request as the Gitea API reports it
+type giteaPullRequest struct {
+	Number    int    `json:"number"`
+	HTMLURL   string `json:"html_url"`
+	Title     string `json:"title"`
+	State     string `json:"state"`
+	Merged    bool   `json:"merged"`
+	Mergeable bool   `json:"mergeable"`
+	Head      struct {
+		Ref string `json:"ref"`
+	} `json:"head"`
+	Base struct {
+		Ref string `json:"ref"`
+	} `json:"base"`
+}
+
+func (pr *giteaPullRequest) convert() *PullRequest {
+	out := &PullRequest{
+		Number:    pr.Number,
+		URL:       pr.HTMLURL,
+		Title:     pr.Title,
+		Head:      pr.Head.Ref,
+		Base:      pr.Base.Ref,
+		State:     PRState(pr.State),
+		Draft:     isGiteaDraft(pr.Title),
+		Mergeable: "CONFLICTING",
+	}
+	if pr.Merged {
+		out.State = PRMerged
+	}
+	if pr.Mergeable {
+		out.Mergeable = "MERGEABLE"
+	}
+	return out
+}
+
+// isGiteaDraft reports whether title marks a pull request as a draft, which Gitea calls
+// work in progress
+func isGiteaDraft(title string) bool {
+	title = strings.ToUpper(title)
+	return strings.HasPrefix(title, "WIP:") || strings.HasPrefix(title, "[WIP]")
+}
+
+func (g *giteaForge) CreatePR(ctx context.Context, repoPath string, opts CreatePROptions) (*PullRequest, error) {
+	title := opts.Title
+	if opts.Draft && !isGiteaDraft(title) {
+		title = "WIP: " + title
+	}
+	body := map[string]string{"head": opts.Head, "base": opts.Base, "title": title, "body": opts.Body}
+
+	var pr giteaPullRequest
+	if err := g.request(ctx, http.MethodPost, "/pulls", body, &pr); err != nil {
+		return nil, fmt.Errorf("failed to create pull request: %w", err)
+	}
+	return pr.convert(), nil
+}
+
+// GetPRStatus returns the latest pull request from branch among the repository's most
+// recently updated ones
+func (g *giteaForge) GetPRStatus(ctx context.Context, repoPath, branch string) (*PullRequest, error) {
+	var prs []giteaPullRequest
+	path := fmt.Sprintf("/pulls?state=all&sort=recentupdate&limit=%d", giteaPageSize)
+	if err := g.request(ctx, http.MethodGet, path, nil, &prs); err != nil {
+		return nil, fmt.Errorf("failed to get pull request: %w", err)
+	}
+
+	var latest *giteaPullRequest
+	for i := range prs {
+		if prs[i].Head.Ref == branch && (latest == nil || prs[i].Number > latest.Number) {
+			latest = &prs[i]
+		}
+	}
+	if latest == nil {
+		return nil, fmt.Errorf("%w %s", ErrNoPullRequest, branch)
+	}
+	return latest.convert(), nil
+}
+
+// giteaStatus is a commit status as the Gitea API reports it
+type giteaStatus struct {
+	Context   string `json:"context"`
+	Status    string `json:"status"`
+	TargetURL string `json:"target_url"`
+}
+
+// ListChecks lists the statuses reported on the latest commit of branch, which Gitea
+// Actions and external CI both report
+func (g *giteaForge) ListChecks(ctx context.Context, repoPath, branch string) ([]Check, error) {
+	var combined struct {
+		Statuses []giteaStatus `json:"statuses"`
+	}
+	if err := g.request(ctx, http.MethodGet, "/commits/"+url.PathEscape(branch)+"/status", nil, &combined); err != nil {
+		return nil, fmt.Errorf("failed to list checks: %w", err)
+	}
+
+	checks := make([]Check, len(combined.Statuses))
+	for i, status := range combined.Statuses {
+		state := CheckSkipped
+		switch status.Status {
+		case "success":
+			state = CheckPass
+		case "failure", "error":
+			state = CheckFail
+		case "pending":
+			state = CheckPending
+		}
+		checks[i] = Check{Name: status.Context, State: state, URL: status.TargetURL}
+	}
+	return checks, nil
+}
+
+// request calls the repository's API at path, sending body and decoding the response into
+// out as JSON
+func (g *giteaForge) request(ctx context.Context, method, path string, body, out interface{}) error {
+	if err := g.Available(ctx); err != nil {
+		return err
+	}
+
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, g.repoAPI+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "token "+g.token)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := g.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		// Gitea explains errors in a message field
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		if json.NewDecoder(resp.Body).Decode(&apiErr) == nil && apiErr.Message != "" {
+			return fmt.Errorf("%s: %s", resp.Status, apiErr.Message)
+		}
+		return fmt.Errorf("%s", resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,165 @@
+package forge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"claude-squad/services/executor"
+)
+
+// githubForge implements Forge with the GitHub CLI (gh), which finds the repository from
+// the clone's remotes and uses the user's gh login
+type githubForge struct {
+	executor executor.CommandExecutor
+}
+
+// NewGitHub creates a Forge for GitHub that runs gh through exec
+func NewGitHub(exec executor.CommandExecutor) Forge {
+	return &githubForge{executor: exec}
+}
+
+func (g *githubForge) Available(ctx context.Context) error {
+	if !g.executor.CommandExists(ctx, "gh") {
+		return fmt.Errorf("the GitHub CLI (gh) is required for pull requests")
+	}
+	return nil
+}
+
+// CreatePR opens a pull request from opts.Head, which must already be pushed
+func (g *githubForge) CreatePR(ctx context.Context, repoPath string, opts CreatePROptions) (*PullRequest, error) {
+	args := []string{"pr", "create",
+		"--head", opts.Head,
+		"--base", opts.Base,
+		"--title", opts.Title,
+		"--body", opts.Body,
+	}
+	if opts.Draft {
+		args = append(args, "--draft")
+	}
+	result, err := g.gh(ctx, repoPath, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create pull request: %w", err)
+	}
+	if result.ExitCode != 0 {
+		return nil, fmt.Errorf("failed to create pull request: %s", strings.TrimSpace(string(result.Stderr)))
+	}
+
+	// gh prints progress before the URL, which is always the last line
+	lines := strings.Split(strings.TrimSpace(string(result.Stdout)), "\n")
+	return &PullRequest{
+		URL:   strings.TrimSpace(lines[len(lines)-1]),
+		Title: opts.Title,
+		Head:  opts.Head,
+		Base:  opts.Base,
+		State: PROpen,
+		Draft: opts.Draft,
+	}, nil
+}
+
+// ghPullRequest is a pull request as `gh pr view --json` reports it
+type ghPullRequest struct {
+	Number         int    `json:"number"`
+	URL            string `json:"url"`
+	Title          string `json:"title"`
+	HeadRefName    string `json:"headRefName"`
+	BaseRefName    string `json:"baseRefName"`
+	State          string `json:"state"`
+	IsDraft        bool   `json:"isDraft"`
+	ReviewDecision string `json:"reviewDecision"`
+	Mergeable      string `json:"mergeable"`
+}
+
+func (g *githubForge) GetPRStatus(ctx context.Context, repoPath, branch string) (*PullRequest, error) {
+	result, err := g.gh(ctx, repoPath, "pr", "view", branch,
+		"--json", "number,url,title,headRefName,baseRefName,state,isDraft,reviewDecision,mergeable")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pull request: %w", err)
+	}
+	if result.ExitCode != 0 {
+		return nil, g.viewError(result, branch)
+	}
+
+	var pr ghPullRequest
+	if err := json.Unmarshal(result.Stdout, &pr); err != nil {
+		return nil, fmt.Errorf("failed to parse pull request: %w", err)
+	}
+	return &PullRequest{
+		Number:         pr.Number,
+		URL:            pr.URL,
+		Title:          pr.Title,
+		Head:           pr.HeadRefName,
+		Base:           pr.BaseRefName,
+		State:          PRState(strings.ToLower(pr.State)),
+		Draft:          pr.IsDraft,
+		ReviewDecision: pr.ReviewDecision,
+		Mergeable:      pr.Mergeable,
+	}, nil
+}
+
+// ghCheck is a check as `gh pr checks --json` reports it
+type ghCheck struct {
+	Name     string `json:"name"`
+	Bucket   string `json:"bucket"`
+	Workflow string `json:"workflow"`
+	Link     string `json:"link"`
+}
+
+func (g *githubForge) ListChecks(ctx context.Context, repoPath, branch string) ([]Check, error) {
+	result, err := g.gh(ctx, repoPath, "pr", "checks", branch, "--json", "name,bucket,workflow,link")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list checks: %w", err)
+	}
+
+	// gh exits non-zero when checks fail or are pending too, so only output it can't
+	// report checks in is an error
+	var checks []ghCheck
+	if err := json.Unmarshal(result.Stdout, &checks); err != nil {
+		if result.ExitCode == 0 {
+			return nil, fmt.Errorf("failed to parse checks: %w", err)
+		}
+		if strings.Contains(string(result.Stderr), "no checks reported") {
+			return nil, nil
+		}
+		return nil, g.viewError(result, branch)
+	}
+
+	out := make([]Check, len(checks))
+	for i, c := range checks {
+		out[i] = Check{Name: c.Name, State: checkState(c.Bucket), Workflow: c.Workflow, URL: c.Link}
+	}
+	return out, nil
+}
+
+// checkState maps gh's buckets, which group the many check states and conclusions
+func checkState(bucket string) CheckState {
+	switch bucket {
+	case "pass":
+		return CheckPass
+	case "fail":
+		return CheckFail
+	case "skipping", "cancel":
+		return CheckSkipped
+	default:
+		return CheckPending
+	}
+}
+
+// viewError turns gh failing to find a branch's pull request into ErrNoPullRequest
+func (g *githubForge) viewError(result *executor.Result, branch string) error {
+	stderr := strings.TrimSpace(string(result.Stderr))
+	if strings.Contains(stderr, "no pull requests found") {
+		return fmt.Errorf("%w %s", ErrNoPullRequest, branch)
+	}
+	return fmt.Errorf("gh failed: %s", stderr)
+}
+
+// gh runs the GitHub CLI in repoPath, where it finds the repository from the remotes
+func (g *githubForge) gh(ctx context.Context, repoPath string, args ...string) (*executor.Result, error) {
+	return g.executor.Execute(ctx, executor.Command{
+		Program: "gh",
+		Args:    args,
+		Dir:     repoPath,
+	})
+}
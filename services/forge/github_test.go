@@ -0,0 +1,66 @@
+package forge
+
+import (
+	"context"
+	"testing"
+
+	"claude-squad/services/executor"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGitHubPRStatus(t *testing.T) {
+	ctx := context.Background()
+	var ran executor.Command
+	result := &executor.Result{Stdout: []byte(`{"number":12,"url":"https://github.com/o/r/pull/12","title":"Fix login",
+		"headRefName":"fix","baseRefName":"main","state":"MERGED","isDraft":false,"reviewDecision":"APPROVED","mergeable":"UNKNOWN"}`)}
+	gh := NewGitHub(&executor.MockExecutor{ExecuteFunc: func(ctx context.Context, cmd executor.Command) (*executor.Result, error) {
+		ran = cmd
+		return result, nil
+	}})
+
+	pr, err := gh.GetPRStatus(ctx, "/src/app", "fix")
+	require.NoError(t, err)
+	assert.Equal(t, "/src/app", ran.Dir)
+	assert.Equal(t, []string{"pr", "view", "fix"}, ran.Args[:3])
+	assert.Equal(t, &PullRequest{
+		Number: 12, URL: "https://github.com/o/r/pull/12", Title: "Fix login", Head: "fix", Base: "main",
+		State: PRMerged, ReviewDecision: "APPROVED", Mergeable: "UNKNOWN",
+	}, pr)
+
+	result = &executor.Result{ExitCode: 1, Stderr: []byte(`no pull requests found for branch "fix"`)}
+	_, err = gh.GetPRStatus(ctx, "/src/app", "fix")
+	assert.ErrorIs(t, err, ErrNoPullRequest)
+}
+
+func TestGitHubListChecks(t *testing.T) {
+	ctx := context.Background()
+	// gh exits 8 while checks are pending
+	result := &executor.Result{ExitCode: 8, Stdout: []byte(`[
+		{"name":"test","bucket":"pass","workflow":"CI","link":"https://ci/1"},
+		{"name":"lint","bucket":"fail","workflow":"CI","link":"https://ci/2"},
+		{"name":"deploy","bucket":"pending","workflow":"","link":""},
+		{"name":"docs","bucket":"skipping","workflow":"","link":""}]`)}
+	gh := NewGitHub(&executor.MockExecutor{ExecuteFunc: func(ctx context.Context, cmd executor.Command) (*executor.Result, error) {
+		return result, nil
+	}})
+
+	checks, err := gh.ListChecks(ctx, "/src/app", "fix")
+	require.NoError(t, err)
+	assert.Equal(t, []Check{
+		{Name: "test", State: CheckPass, Workflow: "CI", URL: "https://ci/1"},
+		{Name: "lint", State: CheckFail, Workflow: "CI", URL: "https://ci/2"},
+		{Name: "deploy", State: CheckPending},
+		{Name: "docs", State: CheckSkipped},
+	}, checks)
+
+	result = &executor.Result{ExitCode: 1, Stderr: []byte("no checks reported on the 'fix' branch")}
+	checks, err = gh.ListChecks(ctx, "/src/app", "fix")
+	require.NoError(t, err)
+	assert.Empty(t, checks)
+
+	result = &executor.Result{ExitCode: 1, Stderr: []byte(`no pull requests found for branch "fix"`)}
+	_, err = gh.ListChecks(ctx, "/src/app", "fix")
+	assert.ErrorIs(t, err, ErrNoPullRequest)
+}
@@ -0,0 +1,179 @@
+package forge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"claude-squad/services/executor"
+)
+
+// gitlabForge implements Forge with the GitLab CLI (glab), which like gh finds the project
+// from the clone's remotes. Pull requests are GitLab merge requests.
+type gitlabForge struct {
+	executor executor.CommandExecutor
+}
+
+// NewGitLab creates a Forge for GitLab that runs glab through exec
+func NewGitLab(exec executor.CommandExecutor) Forge {
+	return &gitlabForge{executor: exec}
+}
+
+func (g *gitlabForge) Available(ctx context.Context) error {
+	if !g.executor.CommandExists(ctx, "glab") {
+		return fmt.Errorf("the GitLab CLI (glab) is required for merge requests")
+	}
+	return nil
+}
+
+// CreatePR opens a merge request from opts.Head, which must already be pushed
+func (g *gitlabForge) CreatePR(ctx context.Context, repoPath string, opts CreatePROptions) (*PullRequest, error) {
+	args := []string{"mr", "create",
+		"--source-branch", opts.Head,
+		"--target-branch", opts.Base,
+		"--title", opts.Title,
+		"--description", opts.Body,
+		"--yes",
+	}
+	if opts.Draft {
+		args = append(args, "--draft")
+	}
+	result, err := g.glab(ctx, repoPath, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create merge request: %w", err)
+	}
+	if result.ExitCode != 0 {
+		return nil, fmt.Errorf("failed to create merge request: %s", strings.TrimSpace(string(result.Stderr)))
+	}
+
+	// Like gh, glab ends with the URL
+	lines := strings.Split(strings.TrimSpace(string(result.Stdout)), "\n")
+	return &PullRequest{
+		URL:   strings.TrimSpace(lines[len(lines)-1]),
+		Title: opts.Title,
+		Head:  opts.Head,
+		Base:  opts.Base,
+		State: PROpen,
+		Draft: opts.Draft,
+	}, nil
+}
+
+// glabMergeRequest is a merge request as `glab mr view --output json` reports it
+type glabMergeRequest struct {
+	IID                 int    `json:"iid"`
+	WebURL              string `json:"web_url"`
+	Title               string `json:"title"`
+	SourceBranch        string `json:"source_branch"`
+	TargetBranch        string `json:"target_branch"`
+	State               string `json:"state"`
+	Draft               bool   `json:"draft"`
+	HasConflicts        bool   `json:"has_conflicts"`
+	DetailedMergeStatus string `json:"detailed_merge_status"`
+}
+
+// GetPRStatus returns the open merge request from branch; glab doesn't look up merged or
+// closed ones by branch
+func (g *gitlabForge) GetPRStatus(ctx context.Context, repoPath, branch string) (*PullRequest, error) {
+	result, err := g.glab(ctx, repoPath, "mr", "view", branch, "--output", "json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get merge request: %w", err)
+	}
+	if result.ExitCode != 0 {
+		return nil, g.viewError(result, branch)
+	}
+
+	var mr glabMergeRequest
+	if err := json.Unmarshal(result.Stdout, &mr); err != nil {
+		return nil, fmt.Errorf("failed to parse merge request: %w", err)
+	}
+	pr := &PullRequest{
+		Number:    mr.IID,
+		URL:       mr.WebURL,
+		Title:     mr.Title,
+		Head:      mr.SourceBranch,
+		Base:      mr.TargetBranch,
+		State:     PROpen,
+		Draft:     mr.Draft,
+		Mergeable: "MERGEABLE",
+	}
+	switch mr.State {
+	case "merged":
+		pr.State = PRMerged
+	case "closed", "locked":
+		pr.State = PRClosed
+	}
+	switch {
+	case mr.HasConflicts:
+		pr.Mergeable = "CONFLICTING"
+	case mr.DetailedMergeStatus == "unchecked" || mr.DetailedMergeStatus == "checking" || mr.DetailedMergeStatus == "preparing":
+		pr.Mergeable = "UNKNOWN"
+	}
+	return pr, nil
+}
+
+// glabPipeline is the latest pipeline of a branch as `glab ci get --output json` reports it
+type glabPipeline struct {
+	Jobs []struct {
+		Name         string `json:"name"`
+		Stage        string `json:"stage"`
+		Status       string `json:"status"`
+		WebURL       string `json:"web_url"`
+		AllowFailure bool   `json:"allow_failure"`
+	} `json:"jobs"`
+}
+
+// ListChecks lists the jobs of the latest pipeline run on branch
+func (g *gitlabForge) ListChecks(ctx context.Context, repoPath, branch string) ([]Check, error) {
+	result, err := g.glab(ctx, repoPath, "ci", "get", "--branch", branch, "--output", "json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pipeline jobs: %w", err)
+	}
+	if result.ExitCode != 0 {
+		if strings.Contains(strings.ToLower(string(result.Stderr)), "no pipeline") {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to list pipeline jobs: %s", strings.TrimSpace(string(result.Stderr)))
+	}
+
+	var pipeline glabPipeline
+	if err := json.Unmarshal(result.Stdout, &pipeline); err != nil {
+		return nil, fmt.Errorf("failed to parse pipeline: %w", err)
+	}
+	checks := make([]Check, len(pipeline.Jobs))
+	for i, job := range pipeline.Jobs {
+		state := CheckPending
+		switch job.Status {
+		case "success":
+			state = CheckPass
+		case "failed":
+			// Jobs allowed to fail don't fail the pipeline
+			state = CheckFail
+			if job.AllowFailure {
+				state = CheckSkipped
+			}
+		case "canceled", "skipped", "manual":
+			state = CheckSkipped
+		}
+		checks[i] = Check{Name: job.Name, State: state, Workflow: job.Stage, URL: job.WebURL}
+	}
+	return checks, nil
+}
+
+// viewError turns glab failing to find a branch's merge request into ErrNoPullRequest
+func (g *gitlabForge) viewError(result *executor.Result, branch string) error {
+	stderr := strings.TrimSpace(string(result.Stderr))
+	if strings.Contains(stderr, "no open merge request") || strings.Contains(stderr, "404 Not Found") {
+		return fmt.Errorf("%w %s", ErrNoPullRequest, branch)
+	}
+	return fmt.Errorf("glab failed: %s", stderr)
+}
+
+// glab runs the GitLab CLI in repoPath, where it finds the project from the remotes
+func (g *gitlabForge) glab(ctx context.Context, repoPath string, args ...string) (*executor.Result, error) {
+	return g.executor.Execute(ctx, executor.Command{
+		Program: "glab",
+		Args:    args,
+		Dir:     repoPath,
+	})
+}
@@ -0,0 +1,39 @@
+package forge
+
+import "context"
+
+// MockForge is a mock implementation of Forge for testing
+type MockForge struct {
+	AvailableFunc   func(ctx context.Context) error
+	CreatePRFunc    func(ctx context.Context, repoPath string, opts CreatePROptions) (*PullRequest, error)
+	GetPRStatusFunc func(ctx context.Context, repoPath, branch string) (*PullRequest, error)
+	ListChecksFunc  func(ctx context.Context, repoPath, branch string) ([]Check, error)
+}
+
+func (m *MockForge) Available(ctx context.Context) error {
+	if m.AvailableFunc != nil {
+		return m.AvailableFunc(ctx)
+	}
+	return nil
+}
+
+func (m *MockForge) CreatePR(ctx context.Context, repoPath string, opts CreatePROptions) (*PullRequest, error) {
+	if m.CreatePRFunc != nil {
+		return m.CreatePRFunc(ctx, repoPath, opts)
+	}
+	return &PullRequest{Title: opts.Title, Head: opts.Head, Base: opts.Base, State: PROpen, Draft: opts.Draft}, nil
+}
+
+func (m *MockForge) GetPRStatus(ctx context.Context, repoPath, branch string) (*PullRequest, error) {
+	if m.GetPRStatusFunc != nil {
+		return m.GetPRStatusFunc(ctx, repoPath, branch)
+	}
+	return nil, ErrNoPullRequest
+}
+
+func (m *MockForge) ListChecks(ctx context.Context, repoPath, branch string) ([]Check, error) {
+	if m.ListChecksFunc != nil {
+		return m.ListChecksFunc(ctx, repoPath, branch)
+	}
+	return nil, nil
+}
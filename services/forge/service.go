@@ -0,0 +1,86 @@
+package forge
+
+import (
+	"context"
+	"errors"
+)
+
+// Kind is a type of forge
+type Kind string
+
+const (
+	KindGitHub Kind = "github"
+	KindGitLab Kind = "gitlab"
+	KindGitea  Kind = "gitea"
+)
+
+// ErrNoPullRequest is returned when a branch has no pull request open or closed
+var ErrNoPullRequest = errors.New("no pull request for branch")
+
+// PRState is where a pull request is in its life
+type PRState string
+
+const (
+	PROpen   PRState = "open"
+	PRClosed PRState = "closed"
+	PRMerged PRState = "merged"
+)
+
+// CheckState sums up the outcome of a check run on a pull request
+type CheckState string
+
+const (
+	CheckPass    CheckState = "pass"
+	CheckFail    CheckState = "fail"
+	CheckPending CheckState = "pending"
+	// CheckSkipped covers checks that were skipped or cancelled
+	CheckSkipped CheckState = "skipped"
+)
+
+// CreatePROptions describes a pull request to open
+type CreatePROptions struct {
+	// Head is the branch with the changes, already pushed
+	Head  string
+	Base  string
+	Title string
+	Body  string
+	Draft bool
+}
+
+// PullRequest is a pull request opened from a branch
+type PullRequest struct {
+	Number int
+	URL    string
+	Title  string
+	Head   string
+	Base   string
+	State  PRState
+	Draft  bool
+	// ReviewDecision is how reviewers have decided, e.g. "APPROVED" or
+	// "CHANGES_REQUESTED", empty when no review is required
+	ReviewDecision string
+	// Mergeable is "MERGEABLE", "CONFLICTING" or "UNKNOWN" while the forge works it out
+	Mergeable string
+}
+
+// Check is a status check or CI job run on a pull request
+type Check struct {
+	Name  string
+	State CheckState
+	// Workflow is the CI workflow the check belongs to, if any
+	Workflow string
+	URL      string
+}
+
+// Forge hosts repositories and the pull requests opened on them. Repositories are given by
+// the path of a local clone, whose remote determines the hosted repository.
+type Forge interface {
+	// Available returns an error saying what is missing when the forge can't be used
+	Available(ctx context.Context) error
+
+	CreatePR(ctx context.Context, repoPath string, opts CreatePROptions) (*PullRequest, error)
+	// GetPRStatus returns the most recent pull request from branch, or ErrNoPullRequest
+	GetPRStatus(ctx context.Context, repoPath, branch string) (*PullRequest, error)
+	// ListChecks lists the checks run on the pull request from branch
+	ListChecks(ctx context.Context, repoPath, branch string) ([]Check, error)
+}
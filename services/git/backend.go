@@ -0,0 +1,26 @@
+package git
+
+import (
+	"fmt"
+
+	"claude-squad/services/executor"
+)
+
+// Backends selectable with the git_backend config key
+const (
+	BackendExec  = "exec"
+	BackendGoGit = "go-git"
+)
+
+// NewService creates the GitService for backend, running git commands through exec. An
+// empty backend selects exec, which runs git for everything.
+func NewService(backend string, exec executor.CommandExecutor) (GitService, error) {
+	switch backend {
+	case "", BackendExec:
+		return NewGitService(exec), nil
+	case BackendGoGit:
+		return NewGoGitService(exec), nil
+	default:
+		return nil, fmt.Errorf("unknown git backend '%s'", backend)
+	}
+}
@@ -0,0 +1,198 @@
+package git
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// RepoConfigFile is the repository's own claude-squad configuration, at the top of its
+// worktree, e.g.
+//
+//	{"diff": {"exclude": ["*.lock", "gen/"], "include": ["gen/api.go"]}}
+const RepoConfigFile = ".claude-squad.json"
+
+// DiffRules pick the files left out of the diffs shown for review, such as lockfiles and
+// generated code, so an agent's meaningful changes stand out. Patterns are matched like
+// .gitignore ones: a pattern without a slash matches a file or directory name at any
+// depth, "*" doesn't cross directories, "**" crosses any number of them, and a
+// directory's pattern covers everything in it. A file matching an include pattern is kept
+// even when it also matches an exclude one.
+type DiffRules struct {
+	Exclude []string `json:"exclude,omitempty"`
+	Include []string `json:"include,omitempty"`
+}
+
+// LoadDiffRules reads the diff rules of the repository whose worktree is at worktreePath.
+// It returns nil when the repository has none.
+func LoadDiffRules(worktreePath string) (*DiffRules, error) {
+	data, err := os.ReadFile(filepath.Join(worktreePath, RepoConfigFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", RepoConfigFile, err)
+	}
+
+	var config struct {
+		Diff DiffRules `json:"diff"`
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", RepoConfigFile, err)
+	}
+	for _, pattern := range append(config.Diff.Exclude, config.Diff.Include...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid %s: bad pattern %q", RepoConfigFile, pattern)
+		}
+	}
+	if len(config.Diff.Exclude) == 0 {
+		return nil, nil
+	}
+	return &config.Diff, nil
+}
+
+// Excluded reports whether the file at p, relative to the top of the worktree, is left
+// out of diffs. Nil rules exclude nothing.
+func (r *DiffRules) Excluded(p string) bool {
+	if r == nil {
+		return false
+	}
+	return matchAny(r.Exclude, p) && !matchAny(r.Include, p)
+}
+
+// FilterFiles returns files without the excluded ones
+func (r *DiffRules) FilterFiles(files []FileDiff) []FileDiff {
+	if r == nil {
+		return files
+	}
+	kept := make([]FileDiff, 0, len(files))
+	for _, file := range files {
+		if !r.Excluded(file.Path) {
+			kept = append(kept, file)
+		}
+	}
+	return kept
+}
+
+// FilterStats returns stats without the excluded files, with the totals recounted
+func (r *DiffRules) FilterStats(stats *DiffStats) *DiffStats {
+	if r == nil || stats == nil {
+		return stats
+	}
+	filtered := &DiffStats{Files: r.FilterFiles(stats.Files)}
+	filtered.FilesChanged = len(filtered.Files)
+	for _, file := range filtered.Files {
+		filtered.Insertions += file.Insertions
+		filtered.Deletions += file.Deletions
+	}
+	return filtered
+}
+
+// FilterPatch returns a unified diff, as GetDiffPatch returns it, without the sections of
+// the excluded files, and how many files were dropped
+func (r *DiffRules) FilterPatch(patch string) (string, int) {
+	if r == nil || patch == "" {
+		return patch, 0
+	}
+
+	var b strings.Builder
+	dropped := 0
+	lines := strings.SplitAfter(patch, "\n")
+	for start := 0; start < len(lines); {
+		end := start + 1
+		for end < len(lines) && !strings.HasPrefix(lines[end], "diff --git ") {
+			end++
+		}
+		section := lines[start:end]
+		if strings.HasPrefix(section[0], "diff --git ") && r.Excluded(patchPath(section)) {
+			dropped++
+		} else {
+			for _, line := range section {
+				b.WriteString(line)
+			}
+		}
+		start = end
+	}
+	return b.String(), dropped
+}
+
+// patchPath returns the path of the file a section of a unified diff changes, going by
+// its new name, or its old one when it is deleted
+func patchPath(section []string) string {
+	var old string
+	for _, line := range section[1:] {
+		line = strings.TrimSuffix(line, "\n")
+		if strings.HasPrefix(line, "@@") {
+			break
+		}
+		for _, prefix := range []string{"rename to ", "copy to ", "+++ b/"} {
+			if strings.HasPrefix(line, prefix) {
+				return strings.TrimPrefix(line, prefix)
+			}
+		}
+		if strings.HasPrefix(line, "--- a/") {
+			old = strings.TrimPrefix(line, "--- a/")
+		}
+	}
+	if old != "" {
+		return old
+	}
+	// Sections without ---/+++ lines, like binary files', only name it in the header
+	header := strings.TrimSuffix(strings.TrimPrefix(section[0], "diff --git "), "\n")
+	if i := strings.LastIndex(header, " b/"); i >= 0 {
+		return header[i+len(" b/"):]
+	}
+	return header
+}
+
+// matchAny reports whether any of patterns matches the file at p or a directory it is in
+func matchAny(patterns []string, p string) bool {
+	parts := strings.Split(p, "/")
+	for _, pattern := range patterns {
+		pattern = strings.TrimSuffix(pattern, "/")
+		if pattern == "" {
+			continue
+		}
+		if !strings.Contains(pattern, "/") {
+			// A name matches at any depth
+			for _, part := range parts {
+				if ok, _ := path.Match(pattern, part); ok {
+					return true
+				}
+			}
+			continue
+		}
+		if matchParts(strings.Split(strings.TrimPrefix(pattern, "/"), "/"), parts) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchParts matches a pattern's segments against the leading segments of a path, with
+// "**" standing for any number of them
+func matchParts(pattern, parts []string) bool {
+	if len(pattern) == 0 {
+		// The pattern matched a directory the file is in, or the file itself
+		return true
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(parts); i++ {
+			if matchParts(pattern[1:], parts[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(parts) == 0 {
+		return false
+	}
+	if ok, _ := path.Match(pattern[0], parts[0]); !ok {
+		return false
+	}
+	return matchParts(pattern[1:], parts[1:])
+}
@@ -0,0 +1,89 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffRulesExcluded(t *testing.T) {
+	rules := &DiffRules{
+		Exclude: []string{"*.lock", "gen/", "/vendor", "docs/**/*.svg"},
+		Include: []string{"gen/api.go"},
+	}
+	tests := map[string]bool{
+		"go.lock":           true,
+		"web/yarn.lock":     true,
+		"gen/types.go":      true,
+		"pkg/gen/types.go":  true,
+		"gen/api.go":        false,
+		"vendor/a/b.go":     true,
+		"pkg/vendor/b.go":   false,
+		"docs/logo.svg":     true,
+		"docs/img/logo.svg": true,
+		"img/logo.svg":      false,
+		"main.go":           false,
+	}
+	for path, excluded := range tests {
+		assert.Equal(t, excluded, rules.Excluded(path), path)
+	}
+
+	var none *DiffRules
+	assert.False(t, none.Excluded("go.lock"))
+}
+
+func TestDiffRulesFilter(t *testing.T) {
+	rules := &DiffRules{Exclude: []string{"*.lock", "assets/"}}
+
+	stats := rules.FilterStats(&DiffStats{
+		FilesChanged: 2,
+		Insertions:   1005,
+		Deletions:    402,
+		Files: []FileDiff{
+			{Path: "main.go", Insertions: 5, Deletions: 2},
+			{Path: "yarn.lock", Insertions: 1000, Deletions: 400},
+		},
+	})
+	assert.Equal(t, &DiffStats{FilesChanged: 1, Insertions: 5, Deletions: 2,
+		Files: []FileDiff{{Path: "main.go", Insertions: 5, Deletions: 2}}}, stats)
+
+	main := "diff --git a/main.go b/main.go\n" +
+		"--- a/main.go\n" +
+		"+++ b/main.go\n" +
+		"@@ -1 +1 @@\n" +
+		"-package old\n" +
+		"+package main\n"
+	patch := "diff --git a/yarn.lock b/yarn.lock\n" +
+		"deleted file mode 100644\n" +
+		"--- a/yarn.lock\n" +
+		"+++ /dev/null\n" +
+		"@@ -1 +0,0 @@\n" +
+		"-lock\n" +
+		main +
+		"diff --git a/assets/logo.png b/assets/logo.png\n" +
+		"new file mode 100644\n" +
+		"Binary files /dev/null and b/assets/logo.png differ\n"
+	filtered, dropped := rules.FilterPatch(patch)
+	assert.Equal(t, main, filtered)
+	assert.Equal(t, 2, dropped)
+}
+
+func TestLoadDiffRules(t *testing.T) {
+	dir := t.TempDir()
+	rules, err := LoadDiffRules(dir)
+	require.NoError(t, err)
+	assert.Nil(t, rules)
+
+	config := filepath.Join(dir, RepoConfigFile)
+	require.NoError(t, os.WriteFile(config, []byte(`{"diff": {"exclude": ["*.lock"], "include": ["keep.lock"]}}`), 0644))
+	rules, err = LoadDiffRules(dir)
+	require.NoError(t, err)
+	assert.Equal(t, &DiffRules{Exclude: []string{"*.lock"}, Include: []string{"keep.lock"}}, rules)
+
+	require.NoError(t, os.WriteFile(config, []byte(`{"diff": {"exclude": ["[a-"]}}`), 0644))
+	_, err = LoadDiffRules(dir)
+	assert.ErrorContains(t, err, "bad pattern")
+}
@@ -2,9 +2,12 @@ package git
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -61,121 +64,123 @@ func (g *execAdapter) GetRepositoryRoot(ctx context.Context, path string) (strin
 
 // Branch operations
 
-// ListBranches lists all branches in the repository
+// branchFormat is the for-each-ref format ListBranches parses: whether the branch is
+// checked out here, the ref it points at if symbolic, its name, its commit, and that
+// commit's date and author
+const branchFormat = "%(HEAD)%00%(symref)%00%(refname)%00%(objectname)%00%(committerdate:unix)%00%(authorname)"
+
+// ListBranches lists all local branches, then all remote-tracking ones, with the date and
+// author of the commit each points at
 func (g *execAdapter) ListBranches(ctx context.Context, repoPath string) ([]Branch, error) {
-	// List local branches
-	localCmd := executor.Command{
+	result, err := g.executor.Execute(ctx, executor.Command{
 		Program: "git",
-		Args:    []string{"-C", repoPath, "branch", "-v", "--no-abbrev"},
-	}
-
-	localResult, err := g.executor.Execute(ctx, localCmd)
+		Args:    []string{"-C", repoPath, "for-each-ref", "--format=" + branchFormat, "refs/heads", "refs/remotes"},
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to list local branches: %w", err)
-	}
-
-	// List remote branches
-	remoteCmd := executor.Command{
-		Program: "git",
-		Args:    []string{"-C", repoPath, "branch", "-rv", "--no-abbrev"},
+		return nil, fmt.Errorf("failed to list branches: %w", err)
 	}
-
-	remoteResult, err := g.executor.Execute(ctx, remoteCmd)
-	if err != nil {
-		return nil, fmt.Errorf("failed to list remote branches: %w", err)
+	if result.ExitCode != 0 {
+		return nil, fmt.Errorf("failed to list branches: %s", strings.TrimSpace(string(result.Stderr)))
 	}
+	return parseBranchRefs(string(result.Stdout)), nil
+}
 
+// parseBranchRefs parses for-each-ref output in branchFormat. Symbolic refs like
+// origin/HEAD only point at another branch and are left out.
+func parseBranchRefs(output string) []Branch {
 	var branches []Branch
-
-	// Parse local branches
-	localLines := strings.Split(string(localResult.Stdout), "\n")
-	for _, line := range localLines {
-		line = strings.TrimSpace(line)
-		if line == "" {
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Split(line, "\x00")
+		if len(fields) != 6 || fields[1] != "" {
 			continue
 		}
 
-		branch := g.parseLocalBranch(line)
-		if branch != nil {
-			branches = append(branches, *branch)
+		branch := Branch{
+			IsCurrent: fields[0] == "*",
+			Hash:      fields[3],
+			Author:    fields[5],
 		}
-	}
-
-	// Parse remote branches
-	remoteLines := strings.Split(string(remoteResult.Stdout), "\n")
-	for _, line := range remoteLines {
-		line = strings.TrimSpace(line)
-		if line == "" || strings.Contains(line, "->") {
+		if name, ok := strings.CutPrefix(fields[2], "refs/heads/"); ok {
+			branch.Name = name
+		} else if name, ok := strings.CutPrefix(fields[2], "refs/remotes/"); ok {
+			branch.Name = name
+			branch.IsRemote = true
+		} else {
 			continue
 		}
-
-		branch := g.parseRemoteBranch(line)
-		if branch != nil {
-			branches = append(branches, *branch)
+		if timestamp, err := strconv.ParseInt(fields[4], 10, 64); err == nil {
+			branch.UpdatedAt = time.Unix(timestamp, 0)
 		}
+		branches = append(branches, branch)
 	}
-
-	return branches, nil
+	return branches
 }
 
-// parseLocalBranch parses a local branch line from git branch output
-func (g *execAdapter) parseLocalBranch(line string) *Branch {
-	isCurrent := strings.HasPrefix(line, "*")
-	if isCurrent {
-		line = strings.TrimPrefix(line, "*")
+// CreateBranch creates a new branch
+func (g *execAdapter) CreateBranch(ctx context.Context, repoPath, branchName string) error {
+	cmd := executor.Command{
+		Program: "git",
+		Args:    []string{"-C", repoPath, "branch", branchName},
 	}
-	line = strings.TrimSpace(line)
 
-	parts := strings.Fields(line)
-	if len(parts) < 2 {
-		return nil
+	result, err := g.executor.Execute(ctx, cmd)
+	if err != nil {
+		return fmt.Errorf("failed to create branch %s: %s (%w)", branchName, result.Stderr, err)
 	}
 
-	return &Branch{
-		Name:      parts[0],
-		IsCurrent: isCurrent,
-		IsRemote:  false,
-		Hash:      parts[1],
-		UpdatedAt: time.Now(), // Would need git log for actual timestamp
-	}
+	return nil
 }
 
-// parseRemoteBranch parses a remote branch line from git branch output
-func (g *execAdapter) parseRemoteBranch(line string) *Branch {
-	parts := strings.Fields(line)
-	if len(parts) < 2 {
-		return nil
+// CreateBranchFrom creates a new branch starting at startPoint (a branch, tag or commit)
+func (g *execAdapter) CreateBranchFrom(ctx context.Context, repoPath, branchName, startPoint string) error {
+	cmd := executor.Command{
+		Program: "git",
+		Args:    []string{"-C", repoPath, "branch", branchName, startPoint},
 	}
 
-	// Skip origin/HEAD entries
-	if strings.Contains(parts[0], "/HEAD") {
-		return nil
+	result, err := g.executor.Execute(ctx, cmd)
+	if err != nil {
+		return fmt.Errorf("failed to create branch %s from %s: %w", branchName, startPoint, err)
 	}
-
-	return &Branch{
-		Name:      parts[0],
-		IsCurrent: false,
-		IsRemote:  true,
-		Hash:      parts[1],
-		UpdatedAt: time.Now(),
+	if result.ExitCode != 0 {
+		return fmt.Errorf("failed to create branch %s from %s: %s", branchName, startPoint, strings.TrimSpace(string(result.Stderr)))
 	}
+
+	return nil
 }
 
-// CreateBranch creates a new branch
-func (g *execAdapter) CreateBranch(ctx context.Context, repoPath, branchName string) error {
+// RenameBranch renames a branch, including where it is checked out in a worktree
+func (g *execAdapter) RenameBranch(ctx context.Context, repoPath, oldName, newName string) error {
 	cmd := executor.Command{
 		Program: "git",
-		Args:    []string{"-C", repoPath, "branch", branchName},
+		Args:    []string{"-C", repoPath, "branch", "-m", oldName, newName},
 	}
 
 	result, err := g.executor.Execute(ctx, cmd)
 	if err != nil {
-		return fmt.Errorf("failed to create branch %s: %s (%w)", branchName, result.Stderr, err)
+		return fmt.Errorf("failed to rename branch %s to %s: %w", oldName, newName, err)
+	}
+	if result.ExitCode != 0 {
+		return fmt.Errorf("failed to rename branch %s to %s: %s", oldName, newName, strings.TrimSpace(string(result.Stderr)))
 	}
 
 	return nil
 }
 
+// BranchExists reports whether a local branch exists
+func (g *execAdapter) BranchExists(ctx context.Context, repoPath, branchName string) (bool, error) {
+	cmd := executor.Command{
+		Program: "git",
+		Args:    []string{"-C", repoPath, "rev-parse", "--verify", "--quiet", "refs/heads/" + branchName},
+	}
+
+	result, err := g.executor.Execute(ctx, cmd)
+	if err != nil {
+		return false, fmt.Errorf("failed to check branch %s: %w", branchName, err)
+	}
+	return result.ExitCode == 0, nil
+}
+
 // DeleteBranch deletes a branch
 func (g *execAdapter) DeleteBranch(ctx context.Context, repoPath, branchName string, force bool) error {
 	args := []string{"-C", repoPath, "branch"}
@@ -210,6 +215,9 @@ func (g *execAdapter) CheckoutBranch(ctx context.Context, repoPath, branchName s
 	if err != nil {
 		return fmt.Errorf("failed to checkout branch %s: %s (%w)", branchName, result.Stderr, err)
 	}
+	if result.ExitCode != 0 {
+		return fmt.Errorf("failed to checkout branch %s: %s", branchName, strings.TrimSpace(string(result.Stderr)))
+	}
 
 	return nil
 }
@@ -231,24 +239,99 @@ func (g *execAdapter) GetCurrentBranch(ctx context.Context, repoPath string) (*B
 		return nil, fmt.Errorf("not on any branch (detached HEAD)")
 	}
 
-	// Get hash for current branch
-	hashCmd := executor.Command{
+	// Get hash, date and author of the branch's commit
+	commitCmd := executor.Command{
 		Program: "git",
-		Args:    []string{"-C", repoPath, "rev-parse", "HEAD"},
+		Args:    []string{"-C", repoPath, "log", "-1", "--format=%H%x00%ct%x00%an"},
 	}
 
-	hashResult, err := g.executor.Execute(ctx, hashCmd)
+	commitResult, err := g.executor.Execute(ctx, commitCmd)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get branch hash: %w", err)
 	}
 
-	return &Branch{
+	branch := &Branch{
 		Name:      branchName,
 		IsCurrent: true,
 		IsRemote:  false,
-		Hash:      strings.TrimSpace(string(hashResult.Stdout)),
-		UpdatedAt: time.Now(),
-	}, nil
+	}
+	// A branch without commits yet has none of them
+	fields := strings.Split(strings.TrimSpace(string(commitResult.Stdout)), "\x00")
+	if len(fields) == 3 {
+		branch.Hash, branch.Author = fields[0], fields[2]
+		if timestamp, err := strconv.ParseInt(fields[1], 10, 64); err == nil {
+			branch.UpdatedAt = time.Unix(timestamp, 0)
+		}
+	}
+	return branch, nil
+}
+
+// Tag operations
+
+// CreateTag tags ref, annotated with message or lightweight when message is empty
+func (g *execAdapter) CreateTag(ctx context.Context, repoPath, name, ref, message string) error {
+	args := []string{"-C", repoPath, "tag"}
+	if message != "" {
+		args = append(args, "-a", "-m", message)
+	}
+	result, err := g.executor.Execute(ctx, executor.Command{
+		Program: "git",
+		Args:    append(args, "--", name, ref),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create tag %s: %w", name, err)
+	}
+	if result.ExitCode != 0 {
+		return fmt.Errorf("failed to create tag %s: %s", name, strings.TrimSpace(string(result.Stderr)))
+	}
+	return nil
+}
+
+// ListTags lists the tags matching pattern, or all of them when it is empty, sorted by name
+func (g *execAdapter) ListTags(ctx context.Context, repoPath, pattern string) ([]Tag, error) {
+	// Annotated tags point at a tag object, which %(*objectname) peels to the commit
+	args := []string{"-C", repoPath, "tag", "--list",
+		"--format=%(refname:strip=2)%00%(objectname)%00%(*objectname)%00%(contents:subject)"}
+	if pattern != "" {
+		args = append(args, pattern)
+	}
+	result, err := g.executor.Execute(ctx, executor.Command{Program: "git", Args: args})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+	if result.ExitCode != 0 {
+		return nil, fmt.Errorf("failed to list tags: %s", strings.TrimSpace(string(result.Stderr)))
+	}
+
+	var tags []Tag
+	for _, line := range strings.Split(string(result.Stdout), "\n") {
+		fields := strings.Split(line, "\x00")
+		if len(fields) != 4 {
+			continue
+		}
+		tag := Tag{Name: fields[0], Hash: fields[1]}
+		if fields[2] != "" {
+			tag.Hash = fields[2]
+			tag.Message = fields[3]
+		}
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
+
+// DeleteTag deletes a tag
+func (g *execAdapter) DeleteTag(ctx context.Context, repoPath, name string) error {
+	result, err := g.executor.Execute(ctx, executor.Command{
+		Program: "git",
+		Args:    []string{"-C", repoPath, "tag", "-d", name},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete tag %s: %w", name, err)
+	}
+	if result.ExitCode != 0 {
+		return fmt.Errorf("failed to delete tag %s: %s", name, strings.TrimSpace(string(result.Stderr)))
+	}
+	return nil
 }
 
 // Worktree operations
@@ -261,8 +344,8 @@ func (g *execAdapter) CreateWorktree(ctx context.Context, repoPath, worktreePath
 		Args:    []string{"-C", repoPath, "rev-parse", "--verify", branch},
 	}
 
-	_, err := g.executor.Execute(ctx, branchExistsCmd)
-	branchExists := err == nil
+	existsResult, err := g.executor.Execute(ctx, branchExistsCmd)
+	branchExists := err == nil && existsResult.ExitCode == 0
 
 	var args []string
 	if branchExists {
@@ -282,6 +365,9 @@ func (g *execAdapter) CreateWorktree(ctx context.Context, repoPath, worktreePath
 	if err != nil {
 		return nil, fmt.Errorf("failed to create worktree: %s (%w)", result.Stderr, err)
 	}
+	if result.ExitCode != 0 {
+		return nil, fmt.Errorf("failed to create worktree: %s", strings.TrimSpace(string(result.Stderr)))
+	}
 
 	// Get worktree info
 	return g.GetWorktreeInfo(ctx, worktreePath)
@@ -332,8 +418,9 @@ func (g *execAdapter) parseWorktrees(output string) []*Worktree {
 			current.Branch = strings.TrimPrefix(branch, "refs/heads/")
 		} else if line == "detached" && current != nil {
 			current.IsDetached = true
-		} else if line == "locked" && current != nil {
+		} else if (line == "locked" || strings.HasPrefix(line, "locked ")) && current != nil {
 			current.IsLocked = true
+			current.LockReason = strings.TrimPrefix(strings.TrimPrefix(line, "locked"), " ")
 		}
 	}
 
@@ -346,7 +433,8 @@ func (g *execAdapter) parseWorktrees(output string) []*Worktree {
 
 // RemoveWorktree removes a worktree
 func (g *execAdapter) RemoveWorktree(ctx context.Context, worktreePath string, force bool) error {
-	args := []string{"worktree", "remove"}
+	// Run from the worktree itself, since git only finds worktrees of the repository it's in
+	args := []string{"-C", worktreePath, "worktree", "remove"}
 	if force {
 		args = append(args, "-f")
 	}
@@ -361,6 +449,9 @@ func (g *execAdapter) RemoveWorktree(ctx context.Context, worktreePath string, f
 	if err != nil {
 		return fmt.Errorf("failed to remove worktree %s: %s (%w)", worktreePath, result.Stderr, err)
 	}
+	if result.ExitCode != 0 {
+		return fmt.Errorf("failed to remove worktree %s: %s", worktreePath, strings.TrimSpace(string(result.Stderr)))
+	}
 
 	return nil
 }
@@ -378,10 +469,10 @@ func (g *execAdapter) GetWorktreeInfo(ctx context.Context, worktreePath string)
 	branch := strings.TrimSpace(string(branchResult.Stdout))
 	isDetached := err != nil || branch == ""
 
-	// Get HEAD hash
+	// Get HEAD hash, and the worktree's root to find it in the list of worktrees
 	hashCmd := executor.Command{
 		Program: "git",
-		Args:    []string{"-C", worktreePath, "rev-parse", "HEAD"},
+		Args:    []string{"-C", worktreePath, "rev-parse", "--show-toplevel", "HEAD"},
 		Dir:     worktreePath,
 	}
 
@@ -389,18 +480,305 @@ func (g *execAdapter) GetWorktreeInfo(ctx context.Context, worktreePath string)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get HEAD hash: %w", err)
 	}
+	root, hash, _ := strings.Cut(strings.TrimSpace(string(hashResult.Stdout)), "\n")
 
-	return &Worktree{
+	info := &Worktree{
 		Path:       worktreePath,
 		Branch:     branch,
-		Hash:       strings.TrimSpace(string(hashResult.Stdout)),
+		Hash:       strings.TrimSpace(hash),
 		IsDetached: isDetached,
-		IsLocked:   false, // Would need to check .git/worktrees/<name>/locked
-	}, nil
+	}
+
+	// Only the worktree list tells whether it's locked
+	worktrees, err := g.ListWorktrees(ctx, worktreePath)
+	if err != nil {
+		return nil, err
+	}
+	for _, worktree := range worktrees {
+		if worktree.Path == root || worktree.Path == filepath.Clean(worktreePath) {
+			info.IsLocked = worktree.IsLocked
+			info.LockReason = worktree.LockReason
+		}
+	}
+	return info, nil
+}
+
+// LockWorktree locks the worktree at worktreePath with reason, unless it is locked already
+func (g *execAdapter) LockWorktree(ctx context.Context, worktreePath, reason string) error {
+	args := []string{"-C", worktreePath, "worktree", "lock"}
+	if reason != "" {
+		args = append(args, "--reason", reason)
+	}
+	result, err := g.executor.Execute(ctx, executor.Command{
+		Program: "git",
+		Args:    append(args, worktreePath),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to lock worktree %s: %w", worktreePath, err)
+	}
+	if result.ExitCode != 0 && !strings.Contains(string(result.Stderr), "already locked") {
+		return fmt.Errorf("failed to lock worktree %s: %s", worktreePath, strings.TrimSpace(string(result.Stderr)))
+	}
+	return nil
+}
+
+// UnlockWorktree unlocks the worktree at worktreePath, if it is locked
+func (g *execAdapter) UnlockWorktree(ctx context.Context, worktreePath string) error {
+	result, err := g.executor.Execute(ctx, executor.Command{
+		Program: "git",
+		Args:    []string{"-C", worktreePath, "worktree", "unlock", worktreePath},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to unlock worktree %s: %w", worktreePath, err)
+	}
+	if result.ExitCode != 0 && !strings.Contains(string(result.Stderr), "is not locked") {
+		return fmt.Errorf("failed to unlock worktree %s: %s", worktreePath, strings.TrimSpace(string(result.Stderr)))
+	}
+	return nil
+}
+
+// UpdateSubmodules initializes a worktree's submodules, recursively, and checks out the
+// commits the worktree records for them
+func (g *execAdapter) UpdateSubmodules(ctx context.Context, worktreePath string) error {
+	result, err := g.executor.Execute(ctx, executor.Command{
+		Program: "git",
+		Args:    []string{"-C", worktreePath, "submodule", "update", "--init", "--recursive"},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update submodules: %w", err)
+	}
+	if result.ExitCode != 0 {
+		return fmt.Errorf("failed to update submodules: %s", strings.TrimSpace(string(result.Stderr)))
+	}
+	return nil
+}
+
+// GetSubmoduleStatus lists the submodules of the worktree at repoPath, recursively
+func (g *execAdapter) GetSubmoduleStatus(ctx context.Context, repoPath string) ([]Submodule, error) {
+	result, err := g.executor.Execute(ctx, executor.Command{
+		Program: "git",
+		Args:    []string{"-C", repoPath, "submodule", "status", "--recursive"},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get submodule status: %w", err)
+	}
+	if result.ExitCode != 0 {
+		return nil, fmt.Errorf("failed to get submodule status: %s", strings.TrimSpace(string(result.Stderr)))
+	}
+	return parseSubmoduleStatus(string(result.Stdout)), nil
+}
+
+// parseSubmoduleStatus parses git submodule status output, one "<state><hash> <path>
+// (<describe>)" line per submodule, the describe part left out when there is none
+func parseSubmoduleStatus(output string) []Submodule {
+	var submodules []Submodule
+	for _, line := range strings.Split(output, "\n") {
+		if len(line) < 2 {
+			continue
+		}
+		hash, rest, ok := strings.Cut(line[1:], " ")
+		if !ok {
+			continue
+		}
+		submodule := Submodule{State: line[0], Hash: hash, Path: rest}
+		if i := strings.LastIndex(rest, " ("); i >= 0 && strings.HasSuffix(rest, ")") {
+			submodule.Path, submodule.Describe = rest[:i], rest[i+2:len(rest)-1]
+		}
+		submodules = append(submodules, submodule)
+	}
+	return submodules
+}
+
+// UsesLFS reports whether any .gitattributes file tracked in the worktree at repoPath
+// sends files through Git LFS's filter
+func (g *execAdapter) UsesLFS(ctx context.Context, repoPath string) (bool, error) {
+	result, err := g.executor.Execute(ctx, executor.Command{
+		Program: "git",
+		Args:    []string{"-C", repoPath, "grep", "-q", "-F", "filter=lfs", "--", ":(glob)**/.gitattributes"},
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to read attributes: %w", err)
+	}
+	// git grep exits with 1 when nothing matches
+	switch result.ExitCode {
+	case 0:
+		return true, nil
+	case 1:
+		return false, nil
+	default:
+		return false, fmt.Errorf("failed to read attributes: %s", strings.TrimSpace(string(result.Stderr)))
+	}
+}
+
+// SetupLFS installs Git LFS's hooks and filters in the repository of the worktree at
+// worktreePath and checks out the content of its LFS files that has been fetched already
+func (g *execAdapter) SetupLFS(ctx context.Context, worktreePath string) error {
+	if !g.executor.CommandExists(ctx, "git-lfs") {
+		return fmt.Errorf("repository uses Git LFS, but git-lfs is not installed")
+	}
+	for _, args := range [][]string{
+		{"lfs", "install", "--local"},
+		{"lfs", "checkout"},
+	} {
+		result, err := g.executor.Execute(ctx, executor.Command{
+			Program: "git",
+			Args:    append([]string{"-C", worktreePath}, args...),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to run git %s: %w", strings.Join(args, " "), err)
+		}
+		if result.ExitCode != 0 {
+			return fmt.Errorf("failed to run git %s: %s", strings.Join(args, " "), strings.TrimSpace(string(result.Stderr)))
+		}
+	}
+	return nil
+}
+
+// MoveWorktree moves an existing worktree to a new path
+func (g *execAdapter) MoveWorktree(ctx context.Context, repoPath, worktreePath, newPath string) error {
+	cmd := executor.Command{
+		Program: "git",
+		Args:    []string{"-C", repoPath, "worktree", "move", worktreePath, newPath},
+	}
+
+	result, err := g.executor.Execute(ctx, cmd)
+	if err != nil {
+		return fmt.Errorf("failed to move worktree %s: %s (%w)", worktreePath, result.Stderr, err)
+	}
+	if result.ExitCode != 0 {
+		return fmt.Errorf("failed to move worktree %s: %s", worktreePath, strings.TrimSpace(string(result.Stderr)))
+	}
+
+	return nil
+}
+
+// GetBranchWorktree returns the worktree that has branch checked out, or nil when none has
+func (g *execAdapter) GetBranchWorktree(ctx context.Context, repoPath, branch string) (*Worktree, error) {
+	worktrees, err := g.ListWorktrees(ctx, repoPath)
+	if err != nil {
+		return nil, err
+	}
+	for _, worktree := range worktrees {
+		if !worktree.IsDetached && worktree.Branch == branch {
+			return worktree, nil
+		}
+	}
+	return nil, nil
 }
 
 // Diff operations
 
+// GetDiff gets the diff of the working directory vs HEAD
+func (g *execAdapter) GetDiff(ctx context.Context, repoPath string) (string, error) {
+	return g.getDiff(ctx, repoPath, []string{"HEAD"})
+}
+
+// GetDiffStaged gets the diff of the staged changes vs HEAD
+func (g *execAdapter) GetDiffStaged(ctx context.Context, repoPath string) (string, error) {
+	return g.getDiff(ctx, repoPath, []string{"--cached", "HEAD"})
+}
+
+// GetDiffBetween gets the diff between two commits or branches
+func (g *execAdapter) GetDiffBetween(ctx context.Context, repoPath, from, to string) (string, error) {
+	return g.getDiff(ctx, repoPath, []string{from + ".." + to})
+}
+
+// GetDiffPatch returns the full unified diff of the working directory vs HEAD. Untracked
+// files are marked intent-to-add first so new files show up in the patch.
+func (g *execAdapter) GetDiffPatch(ctx context.Context, repoPath string) (string, error) {
+	if err := g.intentToAddUntracked(ctx, repoPath); err != nil {
+		return "", err
+	}
+	return g.getDiff(ctx, repoPath, []string{"HEAD"})
+}
+
+// getDiff returns the complete unified diff git diff prints with the given arguments,
+// with renamed and copied files detected and shown as such, free of colors and external
+// diff tools that would make it unparseable
+func (g *execAdapter) getDiff(ctx context.Context, repoPath string, diffArgs []string) (string, error) {
+	args := append([]string{"-C", repoPath, "--no-pager", "diff", "--no-color", "--no-ext-diff", "--find-renames", "--find-copies"}, diffArgs...)
+	cmd := executor.Command{
+		Program: "git",
+		Args:    args,
+	}
+
+	result, err := g.executor.Execute(ctx, cmd)
+	if err != nil {
+		return "", fmt.Errorf("failed to get diff: %w", err)
+	}
+	if result.ExitCode != 0 {
+		return "", fmt.Errorf("failed to get diff: %s", strings.TrimSpace(string(result.Stderr)))
+	}
+
+	return string(result.Stdout), nil
+}
+
+// GetChangedFiles lists the files that differ between the working directory and HEAD,
+// with their change status. Like GetDiffPatch, untracked files are included.
+func (g *execAdapter) GetChangedFiles(ctx context.Context, repoPath string) ([]FileDiff, error) {
+	if err := g.intentToAddUntracked(ctx, repoPath); err != nil {
+		return nil, err
+	}
+
+	cmd := executor.Command{
+		Program: "git",
+		Args:    []string{"-C", repoPath, "diff", "--name-status", "--no-renames", "HEAD"},
+	}
+
+	result, err := g.executor.Execute(ctx, cmd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list changed files: %w", err)
+	}
+	if result.ExitCode != 0 {
+		return nil, fmt.Errorf("failed to list changed files: %s", strings.TrimSpace(string(result.Stderr)))
+	}
+
+	return parseNameStatus(string(result.Stdout)), nil
+}
+
+// intentToAddUntracked records untracked files in the index without staging their content,
+// so that diffs against HEAD include them
+func (g *execAdapter) intentToAddUntracked(ctx context.Context, repoPath string) error {
+	cmd := executor.Command{
+		Program: "git",
+		Args:    []string{"-C", repoPath, "add", "--intent-to-add", "--all"},
+	}
+
+	result, err := g.executor.Execute(ctx, cmd)
+	if err != nil {
+		return fmt.Errorf("failed to add untracked files to the diff: %w", err)
+	}
+	if result.ExitCode != 0 {
+		return fmt.Errorf("failed to add untracked files to the diff: %s", strings.TrimSpace(string(result.Stderr)))
+	}
+	return nil
+}
+
+// parseNameStatus parses the output of git diff --name-status
+func parseNameStatus(output string) []FileDiff {
+	var files []FileDiff
+	for _, line := range strings.Split(output, "\n") {
+		parts := strings.Split(line, "\t")
+		if len(parts) < 2 || parts[0] == "" {
+			continue
+		}
+
+		file := FileDiff{Path: parts[len(parts)-1]}
+		switch parts[0][0] {
+		case 'A':
+			file.Status = "added"
+		case 'D':
+			file.Status = "deleted"
+		case 'R':
+			file.Status = "renamed"
+		default:
+			file.Status = "modified"
+		}
+		files = append(files, file)
+	}
+	return files
+}
+
 // GetDiffStats gets diff statistics for the working directory vs HEAD
 func (g *execAdapter) GetDiffStats(ctx context.Context, repoPath string) (*DiffStats, error) {
 	return g.getDiffStats(ctx, repoPath, []string{"HEAD"})
@@ -432,6 +810,7 @@ func (g *execAdapter) getDiffStats(ctx context.Context, repoPath string, diffArg
 
 	// Parse file-level statistics
 	files := g.parseNumstat(string(numstatResult.Stdout))
+	g.markLFS(ctx, repoPath, files)
 
 	// Calculate totals
 	totalInsertions := 0
@@ -501,37 +880,164 @@ func (g *execAdapter) parseNumstat(output string) []FileDiff {
 	return files
 }
 
+// markLFS flags the files stored with Git LFS, going by their filter attribute, and
+// clears their counts, which are of the lines of their pointers. Attributes that can't be
+// read leave the files as they are.
+func (g *execAdapter) markLFS(ctx context.Context, repoPath string, files []FileDiff) {
+	if len(files) == 0 {
+		return
+	}
+	args := []string{"-C", repoPath, "check-attr", "-z", "filter", "--"}
+	for _, file := range files {
+		args = append(args, file.Path)
+	}
+	result, err := g.executor.Execute(ctx, executor.Command{Program: "git", Args: args})
+	if err != nil || result.ExitCode != 0 {
+		return
+	}
+
+	// Each attribute is reported as "path NUL filter NUL value NUL"
+	lfs := make(map[string]bool)
+	fields := strings.Split(string(result.Stdout), "\x00")
+	for i := 0; i+2 < len(fields); i += 3 {
+		if fields[i+2] == "lfs" {
+			lfs[fields[i]] = true
+		}
+	}
+	for i := range files {
+		if lfs[files[i].Path] {
+			files[i].LFS = true
+			files[i].Insertions, files[i].Deletions = 0, 0
+		}
+	}
+}
+
 // Commit operations
 
 // Commit creates a commit with the given message
 func (g *execAdapter) Commit(ctx context.Context, repoPath, message string) error {
-	// Stage all changes first
-	addCmd := executor.Command{
-		Program: "git",
-		Args:    []string{"-C", repoPath, "add", "."},
+	_, err := g.CommitWithOptions(ctx, repoPath, CommitOptions{Message: message, StageAll: true})
+	return err
+}
+
+// CommitWithOptions creates or amends a commit and returns the resulting commit
+func (g *execAdapter) CommitWithOptions(ctx context.Context, repoPath string, opts CommitOptions) (*CommitInfo, error) {
+	if opts.Message == "" && !opts.Amend {
+		return nil, fmt.Errorf("commit message is required")
 	}
 
-	_, err := g.executor.Execute(ctx, addCmd)
-	if err != nil {
-		return fmt.Errorf("failed to stage changes: %w", err)
+	switch {
+	case len(opts.Paths) > 0:
+		// New files must be known to git before they can be committed by path
+		if err := g.StageFiles(ctx, repoPath, opts.Paths); err != nil {
+			return nil, err
+		}
+	case opts.StageAll:
+		addCmd := executor.Command{
+			Program: "git",
+			Args:    []string{"-C", repoPath, "add", "-A"},
+		}
+		result, err := g.executor.Execute(ctx, addCmd)
+		if err != nil {
+			return nil, fmt.Errorf("failed to stage changes: %w", err)
+		}
+		if result.ExitCode != 0 {
+			return nil, fmt.Errorf("failed to stage changes: %s", strings.TrimSpace(string(result.Stderr)))
+		}
 	}
 
-	// Create commit
-	commitCmd := executor.Command{
-		Program: "git",
-		Args:    []string{"-C", repoPath, "commit", "-m", message, "--no-verify"},
+	args := []string{"-C", repoPath}
+	if opts.SigningFormat != "" {
+		args = append(args, "-c", "gpg.format="+opts.SigningFormat)
+	}
+	args = append(args, "commit")
+	if !opts.RunHooks {
+		args = append(args, "--no-verify")
+	}
+	switch {
+	case opts.Sign && opts.SigningKey != "":
+		args = append(args, "--gpg-sign="+opts.SigningKey)
+	case opts.Sign:
+		args = append(args, "--gpg-sign")
+	}
+	if opts.Author != "" {
+		args = append(args, "--author="+opts.Author)
+	}
+	if opts.Amend {
+		args = append(args, "--amend")
+	}
+	if opts.Message != "" {
+		args = append(args, "-m", opts.Message)
+	} else {
+		args = append(args, "--no-edit")
+	}
+	if len(opts.Paths) > 0 {
+		// Committing by path leaves out everything else staged
+		args = append(append(args, "--only", "--"), opts.Paths...)
 	}
 
-	result, err := g.executor.Execute(ctx, commitCmd)
+	result, err := g.executor.Execute(ctx, executor.Command{Program: "git", Args: args})
 	if err != nil {
-		return fmt.Errorf("failed to commit: %s (%w)", result.Stderr, err)
+		return nil, fmt.Errorf("failed to commit: %w", err)
+	}
+	if result.ExitCode != 0 {
+		// git reports "nothing to commit" on stdout
+		output := strings.TrimSpace(string(result.Stderr))
+		if output == "" {
+			output = strings.TrimSpace(string(result.Stdout))
+		}
+		return nil, fmt.Errorf("failed to commit: %s", output)
 	}
 
-	return nil
+	return g.GetLastCommit(ctx, repoPath)
 }
 
-// GetLastCommit gets information about the last commit
-func (g *execAdapter) GetLastCommit(ctx context.Context, repoPath string) (*CommitInfo, error) {
+// CommitPaths commits the changes to paths, and nothing else, with the given message
+func (g *execAdapter) CommitPaths(ctx context.Context, repoPath, message string, paths []string) (*CommitInfo, error) {
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no files to commit")
+	}
+	return g.CommitWithOptions(ctx, repoPath, CommitOptions{Message: message, Paths: paths})
+}
+
+// StageFiles stages the changes to paths, including new and deleted files
+func (g *execAdapter) StageFiles(ctx context.Context, repoPath string, paths []string) error {
+	if len(paths) == 0 {
+		return nil
+	}
+	result, err := g.executor.Execute(ctx, executor.Command{
+		Program: "git",
+		Args:    append([]string{"-C", repoPath, "add", "-A", "--"}, paths...),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to stage files: %w", err)
+	}
+	if result.ExitCode != 0 {
+		return fmt.Errorf("failed to stage files: %s", strings.TrimSpace(string(result.Stderr)))
+	}
+	return nil
+}
+
+// UnstageFiles removes the changes to paths from the index, leaving the files as they are
+func (g *execAdapter) UnstageFiles(ctx context.Context, repoPath string, paths []string) error {
+	if len(paths) == 0 {
+		return nil
+	}
+	result, err := g.executor.Execute(ctx, executor.Command{
+		Program: "git",
+		Args:    append([]string{"-C", repoPath, "reset", "-q", "--"}, paths...),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to unstage files: %w", err)
+	}
+	if result.ExitCode != 0 {
+		return fmt.Errorf("failed to unstage files: %s", strings.TrimSpace(string(result.Stderr)))
+	}
+	return nil
+}
+
+// GetLastCommit gets information about the last commit
+func (g *execAdapter) GetLastCommit(ctx context.Context, repoPath string) (*CommitInfo, error) {
 	cmd := executor.Command{
 		Program: "git",
 		Args: []string{
@@ -583,6 +1089,171 @@ func (g *execAdapter) GetCommitHistory(ctx context.Context, repoPath string, lim
 	return commits, nil
 }
 
+// GetCommitsBetween lists the commits reachable from head but not from base, newest first
+func (g *execAdapter) GetCommitsBetween(ctx context.Context, repoPath, base, head string) ([]*CommitInfo, error) {
+	result, err := g.executor.Execute(ctx, executor.Command{
+		Program: "git",
+		Args: []string{
+			"-C", repoPath,
+			"log", base + ".." + head,
+			"--pretty=format:%H|%an|%ae|%ct|%s",
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list commits: %w", err)
+	}
+	if result.ExitCode != 0 {
+		return nil, fmt.Errorf("failed to list commits: %s", strings.TrimSpace(string(result.Stderr)))
+	}
+
+	var commits []*CommitInfo
+	for _, line := range strings.Split(string(result.Stdout), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		commit, err := g.parseCommitInfo(line)
+		if err != nil {
+			continue
+		}
+		commits = append(commits, commit)
+	}
+	return commits, nil
+}
+
+// GetAheadBehind counts the commits on branch that aren't on base, and those on base that
+// aren't on branch
+func (g *execAdapter) GetAheadBehind(ctx context.Context, repoPath, branch, base string) (ahead, behind int, err error) {
+	result, err := g.executor.Execute(ctx, executor.Command{
+		Program: "git",
+		Args:    []string{"-C", repoPath, "rev-list", "--left-right", "--count", branch + "..." + base},
+	})
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to compare %s with %s: %w", branch, base, err)
+	}
+	if result.ExitCode != 0 {
+		return 0, 0, fmt.Errorf("failed to compare %s with %s: %s", branch, base, strings.TrimSpace(string(result.Stderr)))
+	}
+
+	// The left side is only on branch, the right only on base
+	counts := strings.Fields(string(result.Stdout))
+	if len(counts) != 2 {
+		return 0, 0, fmt.Errorf("unexpected rev-list output: %q", result.Stdout)
+	}
+	if ahead, err = strconv.Atoi(counts[0]); err != nil {
+		return 0, 0, fmt.Errorf("unexpected rev-list output: %q", result.Stdout)
+	}
+	if behind, err = strconv.Atoi(counts[1]); err != nil {
+		return 0, 0, fmt.Errorf("unexpected rev-list output: %q", result.Stdout)
+	}
+	return ahead, behind, nil
+}
+
+// GetFileHistory lists up to limit commits that changed the file at path, newest first,
+// following it across renames
+func (g *execAdapter) GetFileHistory(ctx context.Context, repoPath, path string, limit int) ([]*CommitInfo, error) {
+	args := []string{"-C", repoPath, "log", "--follow", "--pretty=format:%H|%an|%ae|%ct|%s"}
+	if limit > 0 {
+		args = append(args, fmt.Sprintf("-%d", limit))
+	}
+	result, err := g.executor.Execute(ctx, executor.Command{
+		Program: "git",
+		Args:    append(args, "--", path),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get history of %s: %w", path, err)
+	}
+	if result.ExitCode != 0 {
+		return nil, fmt.Errorf("failed to get history of %s: %s", path, strings.TrimSpace(string(result.Stderr)))
+	}
+
+	var commits []*CommitInfo
+	for _, line := range strings.Split(string(result.Stdout), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		commit, err := g.parseCommitInfo(line)
+		if err != nil {
+			continue
+		}
+		commits = append(commits, commit)
+	}
+	return commits, nil
+}
+
+// Blame attributes each line of the file at path, as it is in the worktree, to the commit
+// that last changed it
+func (g *execAdapter) Blame(ctx context.Context, repoPath, path string) ([]BlameLine, error) {
+	result, err := g.executor.Execute(ctx, executor.Command{
+		Program: "git",
+		Args:    []string{"-C", repoPath, "blame", "--porcelain", "--", path},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to blame %s: %w", path, err)
+	}
+	if result.ExitCode != 0 {
+		return nil, fmt.Errorf("failed to blame %s: %s", path, strings.TrimSpace(string(result.Stderr)))
+	}
+	return parseBlame(string(result.Stdout))
+}
+
+// parseBlame parses git blame --porcelain output. Each line starts with a "<hash>
+// <original line> <final line> [<group size>]" header, followed by the commit's
+// "<key> <value>" details the first time that commit appears, and ends with the line's
+// content after a tab.
+func parseBlame(output string) ([]BlameLine, error) {
+	commits := make(map[string]*BlameLine)
+	var (
+		lines   []BlameLine
+		current *BlameLine
+		number  int
+	)
+	for _, line := range strings.Split(output, "\n") {
+		if current == nil {
+			fields := strings.Fields(line)
+			if len(fields) == 0 {
+				continue
+			}
+			if len(fields) < 3 {
+				return nil, fmt.Errorf("unexpected blame output: %q", line)
+			}
+			var err error
+			if number, err = strconv.Atoi(fields[2]); err != nil {
+				return nil, fmt.Errorf("unexpected blame output: %q", line)
+			}
+			if current = commits[fields[0]]; current == nil {
+				current = &BlameLine{Hash: fields[0]}
+				commits[fields[0]] = current
+			}
+			continue
+		}
+
+		if content, ok := strings.CutPrefix(line, "\t"); ok {
+			blamed := *current
+			blamed.Line = number
+			blamed.Content = content
+			lines = append(lines, blamed)
+			current = nil
+			continue
+		}
+		key, value, _ := strings.Cut(line, " ")
+		switch key {
+		case "author":
+			current.Author = value
+		case "author-mail":
+			current.Email = strings.TrimSuffix(strings.TrimPrefix(value, "<"), ">")
+		case "author-time":
+			if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+				current.Timestamp = time.Unix(seconds, 0)
+			}
+		case "summary":
+			current.Summary = value
+		}
+	}
+	return lines, nil
+}
+
 // parseCommitInfo parses a commit info line in format: hash|author|email|timestamp|message
 func (g *execAdapter) parseCommitInfo(line string) (*CommitInfo, error) {
 	parts := strings.Split(line, "|")
@@ -604,6 +1275,282 @@ func (g *execAdapter) parseCommitInfo(line string) (*CommitInfo, error) {
 	}, nil
 }
 
+// Integration operations
+
+// Merge merges branch into the branch checked out at repoPath. A merge that conflicts is
+// aborted, leaving the checkout as it was, and reported as a *ConflictError.
+func (g *execAdapter) Merge(ctx context.Context, repoPath, branch string, opts MergeOptions) error {
+	args := []string{"-C", repoPath, "merge", "--no-edit"}
+	switch {
+	case opts.FastForwardOnly:
+		args = append(args, "--ff-only")
+	case opts.Message != "":
+		args = append(args, "--no-ff", "-m", opts.Message)
+	default:
+		args = append(args, "--no-ff")
+	}
+	args = append(args, branch)
+
+	if err := g.integrate(ctx, repoPath, args, "merge", []string{"merge", "--abort"}); err != nil {
+		return fmt.Errorf("failed to merge %s: %w", branch, err)
+	}
+	return nil
+}
+
+// SquashMerge applies the changes of branch to the branch checked out at repoPath as a
+// single commit with the given message. Like Merge, a merge that conflicts is undone.
+func (g *execAdapter) SquashMerge(ctx context.Context, repoPath, branch, message string) error {
+	if message == "" {
+		return fmt.Errorf("commit message is required")
+	}
+
+	// A squash merge leaves no merge in progress to abort
+	args := []string{"-C", repoPath, "merge", "--squash", branch}
+	if err := g.integrate(ctx, repoPath, args, "merge", []string{"reset", "--merge"}); err != nil {
+		return fmt.Errorf("failed to squash %s: %w", branch, err)
+	}
+
+	if _, err := g.CommitWithOptions(ctx, repoPath, CommitOptions{Message: message}); err != nil {
+		return fmt.Errorf("failed to squash %s: %w", branch, err)
+	}
+	return nil
+}
+
+// Rebase replays the commits of the branch checked out at repoPath onto onto. A rebase
+// that conflicts is left in progress, to be resolved and continued with RebaseContinue or
+// given up with RebaseAbort, and reported as a *ConflictError.
+func (g *execAdapter) Rebase(ctx context.Context, repoPath, onto string) error {
+	if err := g.integrate(ctx, repoPath, []string{"-C", repoPath, "rebase", onto}, "rebase", nil); err != nil {
+		return fmt.Errorf("failed to rebase onto %s: %w", onto, err)
+	}
+	return nil
+}
+
+// RebaseContinue stages every change at repoPath, as the resolutions of the conflicts
+// that stopped a rebase, and continues it. Files still holding conflict markers are
+// reported as a *ConflictError without continuing.
+func (g *execAdapter) RebaseContinue(ctx context.Context, repoPath string) error {
+	unresolved, err := g.unresolvedFiles(ctx, repoPath)
+	if err != nil {
+		return err
+	}
+	if len(unresolved) > 0 {
+		return &ConflictError{Operation: "rebase", Files: unresolved}
+	}
+
+	result, err := g.executor.Execute(ctx, executor.Command{
+		Program: "git",
+		Args:    []string{"-C", repoPath, "add", "-A"},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to stage resolved files: %w", err)
+	}
+	if result.ExitCode != 0 {
+		return fmt.Errorf("failed to stage resolved files: %s", strings.TrimSpace(string(result.Stderr)))
+	}
+
+	// The editor would wait for someone to confirm each commit message
+	args := []string{"-C", repoPath, "-c", "core.editor=true", "rebase", "--continue"}
+	if err := g.integrate(ctx, repoPath, args, "rebase", nil); err != nil {
+		return fmt.Errorf("failed to continue rebase: %w", err)
+	}
+	return nil
+}
+
+// RebaseAbort gives up on the rebase in progress at repoPath, restoring the branch
+func (g *execAdapter) RebaseAbort(ctx context.Context, repoPath string) error {
+	result, err := g.executor.Execute(ctx, executor.Command{
+		Program: "git",
+		Args:    []string{"-C", repoPath, "rebase", "--abort"},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to abort rebase: %w", err)
+	}
+	if result.ExitCode != 0 {
+		return fmt.Errorf("failed to abort rebase: %s", strings.TrimSpace(string(result.Stderr)))
+	}
+	return nil
+}
+
+// CherryPick commits the changes of commit, or of each commit in a range, onto the branch
+// checked out at repoPath. Like Merge, a cherry-pick that conflicts is aborted, and so
+// are the commits of a range picked before it. So is one whose changes are already on the
+// branch, which would make an empty commit.
+func (g *execAdapter) CherryPick(ctx context.Context, repoPath, commit string) error {
+	args := []string{"-C", repoPath, "cherry-pick", commit}
+	if err := g.integrate(ctx, repoPath, args, "cherry-pick", []string{"cherry-pick", "--abort"}); err != nil {
+		return fmt.Errorf("failed to cherry-pick %s: %w", commit, err)
+	}
+	return nil
+}
+
+// ApplyPatch applies a unified diff to the worktree at repoPath, leaving the changes
+// unstaged. git apply checks every hunk before changing anything, so a patch that doesn't
+// apply leaves the worktree as it was.
+func (g *execAdapter) ApplyPatch(ctx context.Context, repoPath string, patch io.Reader) error {
+	result, err := g.executor.Execute(ctx, executor.Command{
+		Program: "git",
+		Args:    []string{"-C", repoPath, "apply", "--whitespace=nowarn", "-"},
+		Stdin:   patch,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to apply patch: %w", err)
+	}
+	if result.ExitCode != 0 {
+		return fmt.Errorf("failed to apply patch: %s", strings.TrimSpace(string(result.Stderr)))
+	}
+	return nil
+}
+
+// GetConflicts lists the files that conflict when merging branch into base, with the
+// conflicting hunks of each. The merge is worked out in the object store, so neither
+// branch needs to be checked out and nothing in the repository changes.
+func (g *execAdapter) GetConflicts(ctx context.Context, repoPath, base, branch string) ([]FileConflict, error) {
+	result, err := g.executor.Execute(ctx, executor.Command{
+		Program: "git",
+		Args:    []string{"-C", repoPath, "merge-tree", "--write-tree", "--name-only", "--no-messages", "-z", base, branch},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to check %s for conflicts with %s: %w", branch, base, err)
+	}
+	// merge-tree exits 1 when the merge conflicts
+	switch result.ExitCode {
+	case 0:
+		return nil, nil
+	case 1:
+	default:
+		return nil, fmt.Errorf("failed to check %s for conflicts with %s: %s", branch, base, strings.TrimSpace(string(result.Stderr)))
+	}
+
+	// The merged tree, whose conflicting files hold conflict markers, then their paths
+	fields := strings.Split(string(result.Stdout), "\x00")
+	tree := fields[0]
+	var conflicts []FileConflict
+	for _, path := range fields[1:] {
+		if path == "" {
+			continue
+		}
+		conflict := FileConflict{Path: path}
+		blob, err := g.executor.Execute(ctx, executor.Command{
+			Program: "git",
+			Args:    []string{"-C", repoPath, "cat-file", "blob", tree + ":" + path},
+		})
+		// A file deleted on one side is missing from the merged tree
+		if err == nil && blob.ExitCode == 0 {
+			conflict.Hunks = parseConflictHunks(string(blob.Stdout))
+		}
+		conflicts = append(conflicts, conflict)
+	}
+	return conflicts, nil
+}
+
+// parseConflictHunks extracts the regions between conflict markers in a merged file. The
+// common ancestor's lines, shown with merge.conflictStyle diff3, are left out.
+func parseConflictHunks(content string) []ConflictHunk {
+	const (
+		outside = iota
+		ours
+		ancestor
+		theirs
+	)
+	var (
+		hunks  []ConflictHunk
+		hunk   ConflictHunk
+		side   []string
+		within = outside
+	)
+	for i, line := range strings.Split(content, "\n") {
+		switch {
+		case strings.HasPrefix(line, "<<<<<<<") && within == outside:
+			hunk, side, within = ConflictHunk{Line: i + 1}, nil, ours
+		case strings.HasPrefix(line, "|||||||") && within == ours:
+			hunk.Ours, side, within = strings.Join(side, "\n"), nil, ancestor
+		case line == "=======" && (within == ours || within == ancestor):
+			if within == ours {
+				hunk.Ours = strings.Join(side, "\n")
+			}
+			side, within = nil, theirs
+		case strings.HasPrefix(line, ">>>>>>>") && within == theirs:
+			hunk.Theirs = strings.Join(side, "\n")
+			hunks = append(hunks, hunk)
+			within = outside
+		case within != outside:
+			side = append(side, line)
+		}
+	}
+	return hunks
+}
+
+// integrate runs a git merge or rebase. If it fails, the files left conflicting are
+// reported in a *ConflictError, after undoing the operation with `git <undo>` when undo
+// is given.
+func (g *execAdapter) integrate(ctx context.Context, repoPath string, args []string, operation string, undo []string) error {
+	result, err := g.executor.Execute(ctx, executor.Command{Program: "git", Args: args})
+	if err != nil {
+		return err
+	}
+	if result.ExitCode == 0 {
+		return nil
+	}
+
+	output := commandOutput(result)
+	conflicts, _ := g.conflictedFiles(ctx, repoPath)
+	if undo != nil {
+		_, _ = g.executor.Execute(ctx, executor.Command{
+			Program: "git",
+			Args:    append([]string{"-C", repoPath}, undo...),
+		})
+	}
+	if len(conflicts) > 0 {
+		return &ConflictError{Operation: operation, Files: conflicts, Output: output}
+	}
+	return errors.New(output)
+}
+
+// conflictedFiles lists the files at repoPath git considers unmerged
+func (g *execAdapter) conflictedFiles(ctx context.Context, repoPath string) ([]string, error) {
+	result, err := g.executor.Execute(ctx, executor.Command{
+		Program: "git",
+		Args:    []string{"-C", repoPath, "diff", "--name-only", "--diff-filter=U"},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list conflicts: %w", err)
+	}
+	return strings.Fields(string(result.Stdout)), nil
+}
+
+// unresolvedFiles lists the files at repoPath that still hold conflict markers
+func (g *execAdapter) unresolvedFiles(ctx context.Context, repoPath string) ([]string, error) {
+	result, err := g.executor.Execute(ctx, executor.Command{
+		Program: "git",
+		Args:    []string{"-C", repoPath, "diff", "--check"},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to check for conflict markers: %w", err)
+	}
+	// --check also reports whitespace errors, which don't stop a rebase
+	var files []string
+	for _, line := range strings.Split(string(result.Stdout), "\n") {
+		if path, _, ok := strings.Cut(line, ":"); ok && strings.HasSuffix(line, "leftover conflict marker") && !slices.Contains(files, path) {
+			files = append(files, path)
+		}
+	}
+	return files, nil
+}
+
+// commandOutput returns what a failed git command reported, which for conflicts is on
+// stdout rather than stderr
+func commandOutput(result *executor.Result) string {
+	output := strings.TrimSpace(string(result.Stderr))
+	if stdout := strings.TrimSpace(string(result.Stdout)); stdout != "" {
+		if output != "" {
+			output += "\n"
+		}
+		output += stdout
+	}
+	return output
+}
+
 // Stash operations
 
 // Stash creates a stash with the given message
@@ -660,30 +1607,107 @@ func (g *execAdapter) ListStashes(ctx context.Context, repoPath string) ([]strin
 	return stashes, nil
 }
 
+// StashChanges stashes all the changes at repoPath, untracked files included, and returns
+// the stash's commit, which identifies it however many stashes are made after it
+func (g *execAdapter) StashChanges(ctx context.Context, repoPath, message string) (string, error) {
+	dirty, err := g.HasUncommittedChanges(ctx, repoPath)
+	if err != nil || !dirty {
+		return "", err
+	}
+
+	result, err := g.executor.Execute(ctx, executor.Command{
+		Program: "git",
+		Args:    []string{"-C", repoPath, "stash", "push", "--include-untracked", "-m", message},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to stash changes: %w", err)
+	}
+	if result.ExitCode != 0 {
+		return "", fmt.Errorf("failed to stash changes: %s", strings.TrimSpace(string(result.Stderr)))
+	}
+
+	result, err = g.executor.Execute(ctx, executor.Command{
+		Program: "git",
+		Args:    []string{"-C", repoPath, "rev-parse", "--verify", "refs/stash"},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to find stash: %w", err)
+	}
+	if result.ExitCode != 0 {
+		return "", fmt.Errorf("failed to find stash: %s", strings.TrimSpace(string(result.Stderr)))
+	}
+	return strings.TrimSpace(string(result.Stdout)), nil
+}
+
+// RestoreStash pops the stash whose commit is stash, wherever it is in the stash list
+func (g *execAdapter) RestoreStash(ctx context.Context, repoPath, stash string) error {
+	result, err := g.executor.Execute(ctx, executor.Command{
+		Program: "git",
+		Args:    []string{"-C", repoPath, "stash", "list", "--format=%H"},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list stashes: %w", err)
+	}
+	if result.ExitCode != 0 {
+		return fmt.Errorf("failed to list stashes: %s", strings.TrimSpace(string(result.Stderr)))
+	}
+
+	index := slices.Index(strings.Fields(string(result.Stdout)), stash)
+	if index < 0 {
+		return fmt.Errorf("stash %s not found", stash)
+	}
+	result, err = g.executor.Execute(ctx, executor.Command{
+		Program: "git",
+		Args:    []string{"-C", repoPath, "stash", "pop", fmt.Sprintf("stash@{%d}", index)},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to restore stash: %w", err)
+	}
+	if result.ExitCode != 0 {
+		return fmt.Errorf("failed to restore stash: %s", commandOutput(result))
+	}
+	return nil
+}
+
 // Status operations
 
-// GetStatus gets the repository status
-func (g *execAdapter) GetStatus(ctx context.Context, repoPath string) ([]string, error) {
+// GetStatus lists the files that are staged, changed in the worktree, untracked or
+// unmerged, in git status order
+func (g *execAdapter) GetStatus(ctx context.Context, repoPath string) ([]FileStatus, error) {
 	cmd := executor.Command{
 		Program: "git",
-		Args:    []string{"-C", repoPath, "status", "--porcelain"},
+		Args:    []string{"-C", repoPath, "status", "--porcelain", "-z"},
 	}
 
 	result, err := g.executor.Execute(ctx, cmd)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get status: %w", err)
 	}
+	if result.ExitCode != 0 {
+		return nil, fmt.Errorf("failed to get status: %s", strings.TrimSpace(string(result.Stderr)))
+	}
 
-	var statusLines []string
-	lines := strings.Split(string(result.Stdout), "\n")
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if line != "" {
-			statusLines = append(statusLines, line)
+	return parsePorcelainStatus(string(result.Stdout)), nil
+}
+
+// parsePorcelainStatus parses git status --porcelain -z output: "XY path" entries ending
+// in NUL, with renamed and copied files followed by their original path as another entry
+func parsePorcelainStatus(output string) []FileStatus {
+	var files []FileStatus
+	entries := strings.Split(output, "\x00")
+	for i := 0; i < len(entries); i++ {
+		entry := entries[i]
+		if len(entry) < 4 {
+			continue
 		}
+		file := FileStatus{Staged: entry[0], Unstaged: entry[1], Path: entry[3:]}
+		if (file.Staged == 'R' || file.Staged == 'C') && i+1 < len(entries) {
+			i++
+			file.OrigPath = entries[i]
+		}
+		files = append(files, file)
 	}
-
-	return statusLines, nil
+	return files
 }
 
 // HasUncommittedChanges checks if there are uncommitted changes
@@ -766,4 +1790,122 @@ func sanitizeBranchName(s string) string {
 	s = strings.Trim(s, "-/")
 
 	return s
-}
\ No newline at end of file
+}
+
+// Bundles
+
+// CreateBundle writes a git bundle containing branchName and its history to bundlePath
+func (g *execAdapter) CreateBundle(ctx context.Context, repoPath, bundlePath, branchName string) error {
+	cmd := executor.Command{
+		Program: "git",
+		Args:    []string{"-C", repoPath, "bundle", "create", bundlePath, branchName},
+	}
+
+	result, err := g.executor.Execute(ctx, cmd)
+	if err != nil {
+		return fmt.Errorf("failed to create bundle of %s: %w", branchName, err)
+	}
+	if result.ExitCode != 0 {
+		return fmt.Errorf("failed to create bundle of %s: %s", branchName, strings.TrimSpace(string(result.Stderr)))
+	}
+
+	return nil
+}
+
+// FetchBundle fetches branchName from the bundle at bundlePath into a local branch of the same name
+func (g *execAdapter) FetchBundle(ctx context.Context, repoPath, bundlePath, branchName string) error {
+	refspec := fmt.Sprintf("refs/heads/%s:refs/heads/%s", branchName, branchName)
+	cmd := executor.Command{
+		Program: "git",
+		Args:    []string{"-C", repoPath, "fetch", bundlePath, refspec},
+	}
+
+	result, err := g.executor.Execute(ctx, cmd)
+	if err != nil {
+		return fmt.Errorf("failed to fetch %s from bundle: %w", branchName, err)
+	}
+	if result.ExitCode != 0 {
+		return fmt.Errorf("failed to fetch %s from bundle: %s", branchName, strings.TrimSpace(string(result.Stderr)))
+	}
+
+	return nil
+}
+
+// Remote operations
+
+// Push pushes branch to the remote and sets it as the branch's upstream
+func (g *execAdapter) Push(ctx context.Context, repoPath, branch string, opts PushOptions) error {
+	remote := opts.Remote
+	if remote == "" {
+		remote = "origin"
+	}
+
+	args := []string{"-C", repoPath, "push", "-u"}
+	if opts.Force {
+		args = append(args, "--force-with-lease")
+	}
+	args = append(args, remote, branch)
+
+	result, err := g.executor.Execute(ctx, executor.Command{
+		Program: "git",
+		Args:    args,
+		Timeout: 5 * time.Minute,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to push %s to %s: %w", branch, remote, err)
+	}
+	if result.ExitCode != 0 {
+		return fmt.Errorf("failed to push %s to %s: %s", branch, remote, strings.TrimSpace(string(result.Stderr)))
+	}
+
+	return nil
+}
+
+// Fetch updates the remote-tracking branches of a remote
+func (g *execAdapter) Fetch(ctx context.Context, repoPath, remote string) error {
+	result, err := g.executor.Execute(ctx, executor.Command{
+		Program: "git",
+		Args:    []string{"-C", repoPath, "fetch", "--prune", remote},
+		Timeout: 5 * time.Minute,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to fetch %s: %w", remote, err)
+	}
+	if result.ExitCode != 0 {
+		return fmt.Errorf("failed to fetch %s: %s", remote, strings.TrimSpace(string(result.Stderr)))
+	}
+
+	return nil
+}
+
+// GetRemoteURL returns the fetch URL of a remote
+func (g *execAdapter) GetRemoteURL(ctx context.Context, repoPath, remote string) (string, error) {
+	result, err := g.executor.Execute(ctx, executor.Command{
+		Program: "git",
+		Args:    []string{"-C", repoPath, "remote", "get-url", remote},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to get URL of remote %s: %w", remote, err)
+	}
+	if result.ExitCode != 0 {
+		return "", fmt.Errorf("failed to get URL of remote %s: %s", remote, strings.TrimSpace(string(result.Stderr)))
+	}
+
+	return strings.TrimSpace(string(result.Stdout)), nil
+}
+
+// GetDefaultBranch returns the branch the remote's HEAD points to, e.g. "main"
+func (g *execAdapter) GetDefaultBranch(ctx context.Context, repoPath, remote string) (string, error) {
+	result, err := g.executor.Execute(ctx, executor.Command{
+		Program: "git",
+		Args:    []string{"-C", repoPath, "symbolic-ref", "--short", "refs/remotes/" + remote + "/HEAD"},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to get default branch of %s: %w", remote, err)
+	}
+	if result.ExitCode != 0 {
+		return "", fmt.Errorf("failed to get default branch of %s: %s", remote, strings.TrimSpace(string(result.Stderr)))
+	}
+
+	return strings.TrimPrefix(strings.TrimSpace(string(result.Stdout)), remote+"/"), nil
+}
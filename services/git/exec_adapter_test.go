@@ -0,0 +1,852 @@
+package git
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"claude-squad/services/executor"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	dir := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "-q", "-b", "main"},
+		{"config", "user.name", "test"},
+		{"config", "user.email", "test@example.com"},
+		{"commit", "-q", "--allow-empty", "-m", "initial"},
+	} {
+		out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	return dir
+}
+
+func TestCommitWithOptions(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepo(t)
+	g := NewGitService(executor.NewDefaultExecutor())
+
+	_, err := g.CommitWithOptions(ctx, repo, CommitOptions{Message: "empty", StageAll: true})
+	assert.ErrorContains(t, err, "nothing to commit")
+
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "a.txt"), []byte("a\n"), 0644))
+	commit, err := g.CommitWithOptions(ctx, repo, CommitOptions{Message: "add a", StageAll: true})
+	require.NoError(t, err)
+	assert.Equal(t, "add a", commit.Message)
+	assert.NotEmpty(t, commit.Hash)
+
+	// Without staging, an untracked file isn't committed
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "b.txt"), []byte("b\n"), 0644))
+	_, err = g.CommitWithOptions(ctx, repo, CommitOptions{Message: "add b"})
+	assert.Error(t, err)
+
+	amended, err := g.CommitWithOptions(ctx, repo, CommitOptions{Amend: true, StageAll: true})
+	require.NoError(t, err)
+	assert.Equal(t, "add a", amended.Message)
+	assert.NotEqual(t, commit.Hash, amended.Hash)
+
+	_, err = g.CommitWithOptions(ctx, repo, CommitOptions{})
+	assert.Error(t, err)
+}
+
+func TestCommitHooksSigningAndAuthor(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepo(t)
+	g := NewGitService(executor.NewDefaultExecutor())
+	git := func(args ...string) string {
+		out, err := exec.Command("git", append([]string{"-C", repo}, args...)...).CombinedOutput()
+		require.NoError(t, err, string(out))
+		return strings.TrimSpace(string(out))
+	}
+	hook := filepath.Join(repo, ".git", "hooks", "pre-commit")
+	require.NoError(t, os.WriteFile(hook, []byte("#!/bin/sh\necho blocked by hook >&2\nexit 1\n"), 0755))
+
+	// Hooks are skipped unless asked for
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "a.txt"), []byte("a\n"), 0644))
+	_, err := g.CommitWithOptions(ctx, repo, CommitOptions{Message: "add a", StageAll: true, RunHooks: true})
+	assert.ErrorContains(t, err, "blocked by hook")
+	_, err = g.CommitWithOptions(ctx, repo, CommitOptions{Message: "add a", StageAll: true, Author: "Agent <agent@example.com>"})
+	require.NoError(t, err)
+	assert.Equal(t, "Agent <agent@example.com>", git("log", "-1", "--format=%an <%ae>"))
+	assert.Equal(t, "test <test@example.com>", git("log", "-1", "--format=%cn <%ce>"))
+
+	if _, err := exec.LookPath("ssh-keygen"); err != nil {
+		t.Skip("ssh-keygen not available")
+	}
+	key := filepath.Join(t.TempDir(), "id_ed25519")
+	out, err := exec.Command("ssh-keygen", "-q", "-t", "ed25519", "-N", "", "-f", key).CombinedOutput()
+	require.NoError(t, err, string(out))
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "b.txt"), []byte("b\n"), 0644))
+	_, err = g.CommitWithOptions(ctx, repo, CommitOptions{Message: "add b", StageAll: true, Sign: true, SigningKey: key, SigningFormat: "ssh"})
+	require.NoError(t, err)
+	assert.Contains(t, git("cat-file", "commit", "HEAD"), "-----BEGIN SSH SIGNATURE-----")
+}
+
+func TestCommitPaths(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepo(t)
+	g := NewGitService(executor.NewDefaultExecutor())
+	write := func(name, content string) {
+		require.NoError(t, os.WriteFile(filepath.Join(repo, name), []byte(content), 0644))
+	}
+	// GetStatus trims the column telling staged changes from unstaged ones
+	status := func() []string {
+		out, err := exec.Command("git", "-C", repo, "status", "--porcelain").Output()
+		require.NoError(t, err)
+		return strings.Split(strings.TrimSuffix(string(out), "\n"), "\n")
+	}
+
+	write("keep.txt", "keep\n")
+	write("gone.txt", "gone\n")
+	_, err := g.CommitPaths(ctx, repo, "base", []string{"keep.txt", "gone.txt"})
+	require.NoError(t, err)
+
+	// Staging and unstaging leave the files alone
+	write("keep.txt", "changed\n")
+	require.NoError(t, g.StageFiles(ctx, repo, []string{"keep.txt"}))
+	assert.Equal(t, []string{"M  keep.txt"}, status())
+	require.NoError(t, g.UnstageFiles(ctx, repo, []string{"keep.txt"}))
+	assert.Equal(t, []string{" M keep.txt"}, status())
+
+	// Only the given files are committed, new and deleted ones included, even with other
+	// changes staged
+	require.NoError(t, os.Remove(filepath.Join(repo, "gone.txt")))
+	write("new.txt", "new\n")
+	write("other.txt", "other\n")
+	require.NoError(t, g.StageFiles(ctx, repo, []string{"other.txt"}))
+	commit, err := g.CommitPaths(ctx, repo, "pick", []string{"gone.txt", "new.txt"})
+	require.NoError(t, err)
+	assert.Equal(t, "pick", commit.Message)
+	assert.ElementsMatch(t, []string{" M keep.txt", "A  other.txt"}, status())
+
+	_, err = g.CommitPaths(ctx, repo, "nothing", nil)
+	assert.Error(t, err)
+}
+
+func TestGetDiffPatchIncludesUntrackedFiles(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepo(t)
+	g := NewGitService(executor.NewDefaultExecutor())
+
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "a.txt"), []byte("a\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "b.txt"), []byte("b\n"), 0644))
+	_, err := g.CommitWithOptions(ctx, repo, CommitOptions{Message: "add files", StageAll: true})
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "a.txt"), []byte("a\nmore\n"), 0644))
+	require.NoError(t, os.Remove(filepath.Join(repo, "b.txt")))
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "new.txt"), []byte("new\n"), 0644))
+
+	patch, err := g.GetDiffPatch(ctx, repo)
+	require.NoError(t, err)
+	assert.Contains(t, patch, "+more")
+	assert.Contains(t, patch, "+++ b/new.txt")
+
+	files, err := g.GetChangedFiles(ctx, repo)
+	require.NoError(t, err)
+	assert.Equal(t, []FileDiff{
+		{Path: "a.txt", Status: "modified"},
+		{Path: "b.txt", Status: "deleted"},
+		{Path: "new.txt", Status: "added"},
+	}, files)
+}
+
+func TestGetDiffVariants(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepo(t)
+	g := NewGitService(executor.NewDefaultExecutor())
+	git := func(args ...string) {
+		out, err := exec.Command("git", append([]string{"-C", repo}, args...)...).CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+
+	content := []byte("one\ntwo\nthree\nfour\nfive\n")
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "old.txt"), content, 0644))
+	_, err := g.CommitWithOptions(ctx, repo, CommitOptions{Message: "add old", StageAll: true})
+	require.NoError(t, err)
+
+	// Renamed files show as renames rather than a deletion and an addition
+	require.NoError(t, os.Rename(filepath.Join(repo, "old.txt"), filepath.Join(repo, "new.txt")))
+	patch, err := g.GetDiffPatch(ctx, repo)
+	require.NoError(t, err)
+	assert.Contains(t, patch, "rename from old.txt")
+	assert.Contains(t, patch, "rename to new.txt")
+
+	// Only staged changes are in the staged diff
+	git("add", "-A")
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "unstaged.txt"), []byte("later\n"), 0644))
+	staged, err := g.GetDiffStaged(ctx, repo)
+	require.NoError(t, err)
+	assert.Contains(t, staged, "rename to new.txt")
+	assert.NotContains(t, staged, "unstaged.txt")
+
+	git("checkout", "-q", "-b", "feature")
+	git("commit", "-q", "-m", "rename")
+	between, err := g.GetDiffBetween(ctx, repo, "main", "feature")
+	require.NoError(t, err)
+	assert.Contains(t, between, "rename from old.txt")
+	assert.NotContains(t, between, "unstaged.txt")
+}
+
+func TestGetDiffFailures(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepo(t)
+	g := NewGitService(executor.NewDefaultExecutor())
+
+	_, err := g.GetDiffBetween(ctx, repo, "main", "no-such-ref")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no-such-ref")
+
+	missing := filepath.Join(t.TempDir(), "missing")
+	_, err = g.GetDiff(ctx, missing)
+	assert.Error(t, err)
+	_, err = g.GetDiffPatch(ctx, missing)
+	assert.Error(t, err)
+	_, err = g.GetChangedFiles(ctx, missing)
+	assert.Error(t, err)
+}
+
+func TestMergeStrategies(t *testing.T) {
+	ctx := context.Background()
+	g := NewGitService(executor.NewDefaultExecutor())
+	setup := func(t *testing.T) (repo string, git func(args ...string) string) {
+		repo = newTestRepo(t)
+		git = func(args ...string) string {
+			out, err := exec.Command("git", append([]string{"-C", repo}, args...)...).CombinedOutput()
+			require.NoError(t, err, string(out))
+			return strings.TrimSpace(string(out))
+		}
+		git("checkout", "-q", "-b", "feature")
+		for _, name := range []string{"a.txt", "b.txt"} {
+			require.NoError(t, os.WriteFile(filepath.Join(repo, name), []byte(name+"\n"), 0644))
+			git("add", name)
+			git("commit", "-q", "-m", "add "+name)
+		}
+		git("checkout", "-q", "main")
+		return repo, git
+	}
+
+	t.Run("merge", func(t *testing.T) {
+		repo, git := setup(t)
+		require.NoError(t, g.Merge(ctx, repo, "feature", MergeOptions{Message: "Merge feature"}))
+		assert.Equal(t, "Merge feature", git("log", "-1", "--format=%s"))
+		// A merge commit has the base and the branch as parents
+		assert.Len(t, strings.Fields(git("rev-list", "--parents", "-n", "1", "HEAD")), 3)
+	})
+
+	t.Run("squash", func(t *testing.T) {
+		repo, git := setup(t)
+		require.NoError(t, g.SquashMerge(ctx, repo, "feature", "Add files"))
+		assert.Equal(t, "Add files", git("log", "-1", "--format=%s"))
+		assert.Equal(t, "2", git("rev-list", "--count", "HEAD"))
+		assert.FileExists(t, filepath.Join(repo, "b.txt"))
+	})
+
+	t.Run("rebase", func(t *testing.T) {
+		repo, git := setup(t)
+		require.NoError(t, os.WriteFile(filepath.Join(repo, "main.txt"), []byte("main\n"), 0644))
+		git("add", "main.txt")
+		git("commit", "-q", "-m", "add main.txt")
+
+		git("checkout", "-q", "feature")
+		require.NoError(t, g.Rebase(ctx, repo, "main"))
+		git("checkout", "-q", "main")
+		require.NoError(t, g.Merge(ctx, repo, "feature", MergeOptions{FastForwardOnly: true}))
+		assert.Equal(t, git("rev-parse", "feature"), git("rev-parse", "HEAD"))
+		assert.Equal(t, "4", git("rev-list", "--count", "HEAD"))
+	})
+
+	conflicting := func(t *testing.T) (repo string, git func(args ...string) string) {
+		repo, git = setup(t)
+		require.NoError(t, os.WriteFile(filepath.Join(repo, "a.txt"), []byte("other\n"), 0644))
+		git("add", "a.txt")
+		git("commit", "-q", "-m", "conflicting a.txt")
+		return repo, git
+	}
+
+	t.Run("merge conflicts are aborted", func(t *testing.T) {
+		repo, git := conflicting(t)
+		head := git("rev-parse", "HEAD")
+
+		var conflict *ConflictError
+		require.ErrorAs(t, g.Merge(ctx, repo, "feature", MergeOptions{}), &conflict)
+		assert.Equal(t, "merge", conflict.Operation)
+		assert.Equal(t, []string{"a.txt"}, conflict.Files)
+		assert.Contains(t, conflict.Output, "CONFLICT")
+
+		require.ErrorAs(t, g.SquashMerge(ctx, repo, "feature", "squash"), &conflict)
+		assert.Equal(t, []string{"a.txt"}, conflict.Files)
+		assert.Equal(t, head, git("rev-parse", "HEAD"))
+		assert.Empty(t, git("status", "--porcelain"))
+	})
+
+	t.Run("rebase conflicts are left to resolve", func(t *testing.T) {
+		repo, git := conflicting(t)
+		git("checkout", "-q", "feature")
+		featureHead := git("rev-parse", "HEAD")
+
+		var conflict *ConflictError
+		require.ErrorAs(t, g.Rebase(ctx, repo, "main"), &conflict)
+		assert.Equal(t, "rebase", conflict.Operation)
+		assert.Equal(t, []string{"a.txt"}, conflict.Files)
+
+		// Continuing with the conflict markers still there is refused
+		require.ErrorAs(t, g.RebaseContinue(ctx, repo), &conflict)
+		assert.Equal(t, []string{"a.txt"}, conflict.Files)
+
+		require.NoError(t, g.RebaseAbort(ctx, repo))
+		assert.Equal(t, featureHead, git("rev-parse", "HEAD"))
+		assert.Empty(t, git("status", "--porcelain"))
+	})
+
+	t.Run("rebase continues once resolved", func(t *testing.T) {
+		repo, git := conflicting(t)
+		git("checkout", "-q", "feature")
+		require.Error(t, g.Rebase(ctx, repo, "main"))
+
+		require.NoError(t, os.WriteFile(filepath.Join(repo, "a.txt"), []byte("resolved\n"), 0644))
+		require.NoError(t, g.RebaseContinue(ctx, repo))
+		assert.Equal(t, "feature", git("branch", "--show-current"))
+		assert.Equal(t, "4", git("rev-list", "--count", "HEAD"))
+		assert.Empty(t, git("status", "--porcelain"))
+	})
+
+	t.Run("cherry-pick", func(t *testing.T) {
+		repo, git := setup(t)
+		require.NoError(t, g.CherryPick(ctx, repo, "feature~1"))
+		assert.Equal(t, "add a.txt", git("log", "-1", "--format=%s"))
+		assert.NoFileExists(t, filepath.Join(repo, "b.txt"))
+
+		// A range picks each commit in it
+		git("reset", "-q", "--hard", "HEAD~1")
+		require.NoError(t, g.CherryPick(ctx, repo, "main..feature"))
+		assert.Equal(t, "3", git("rev-list", "--count", "HEAD"))
+		assert.Equal(t, "main", git("branch", "--show-current"))
+	})
+
+	t.Run("cherry-pick conflicts are aborted", func(t *testing.T) {
+		repo, git := conflicting(t)
+		head := git("rev-parse", "HEAD")
+
+		var conflict *ConflictError
+		require.ErrorAs(t, g.CherryPick(ctx, repo, "main..feature"), &conflict)
+		assert.Equal(t, "cherry-pick", conflict.Operation)
+		assert.Equal(t, []string{"a.txt"}, conflict.Files)
+		assert.Equal(t, head, git("rev-parse", "HEAD"))
+		assert.Empty(t, git("status", "--porcelain"))
+	})
+}
+
+func TestGetConflicts(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepo(t)
+	g := NewGitService(executor.NewDefaultExecutor())
+	git := func(args ...string) {
+		out, err := exec.Command("git", append([]string{"-C", repo}, args...)...).CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	commit := func(files map[string]string, message string) {
+		for name, content := range files {
+			require.NoError(t, os.WriteFile(filepath.Join(repo, name), []byte(content), 0644))
+		}
+		git("add", "-A")
+		git("commit", "-q", "-m", message)
+	}
+
+	commit(map[string]string{"a.txt": "one\ntwo\nthree\n", "b.txt": "b\n"}, "base")
+	git("checkout", "-q", "-b", "feature")
+	commit(map[string]string{"a.txt": "one\nfeature\nthree\n", "b.txt": "feature b\n"}, "feature")
+	git("checkout", "-q", "main")
+	commit(map[string]string{"c.txt": "c\n"}, "unrelated")
+
+	conflicts, err := g.GetConflicts(ctx, repo, "main", "feature")
+	require.NoError(t, err)
+	assert.Empty(t, conflicts)
+
+	commit(map[string]string{"a.txt": "one\nmain\nthree\n"}, "conflicting")
+	git("rm", "-q", "b.txt")
+	git("commit", "-q", "-m", "delete b.txt")
+	head, err := g.GetLastCommit(ctx, repo)
+	require.NoError(t, err)
+
+	conflicts, err = g.GetConflicts(ctx, repo, "main", "feature")
+	require.NoError(t, err)
+	assert.Equal(t, []FileConflict{
+		{Path: "a.txt", Hunks: []ConflictHunk{{Line: 2, Ours: "main", Theirs: "feature"}}},
+		{Path: "b.txt"},
+	}, conflicts)
+
+	// Nothing is changed in the repository
+	after, err := g.GetLastCommit(ctx, repo)
+	require.NoError(t, err)
+	assert.Equal(t, head.Hash, after.Hash)
+	dirty, err := g.HasUncommittedChanges(ctx, repo)
+	require.NoError(t, err)
+	assert.False(t, dirty)
+}
+
+func TestParseConflictHunks(t *testing.T) {
+	content := "a\n<<<<<<< ours\nx\ny\n||||||| base\nold\n=======\nz\n>>>>>>> theirs\nb\n<<<<<<< ours\n=======\nw\n>>>>>>> theirs\n"
+	assert.Equal(t, []ConflictHunk{
+		{Line: 2, Ours: "x\ny", Theirs: "z"},
+		{Line: 11, Ours: "", Theirs: "w"},
+	}, parseConflictHunks(content))
+	assert.Empty(t, parseConflictHunks("no conflicts\n"))
+}
+
+func TestGetAheadBehind(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepo(t)
+	g := NewGitService(executor.NewDefaultExecutor())
+	git := func(args ...string) {
+		out, err := exec.Command("git", append([]string{"-C", repo}, args...)...).CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+
+	git("branch", "feature")
+	ahead, behind, err := g.GetAheadBehind(ctx, repo, "feature", "main")
+	require.NoError(t, err)
+	assert.Equal(t, [2]int{0, 0}, [2]int{ahead, behind})
+
+	git("commit", "-q", "--allow-empty", "-m", "main 1")
+	git("commit", "-q", "--allow-empty", "-m", "main 2")
+	git("checkout", "-q", "feature")
+	git("commit", "-q", "--allow-empty", "-m", "feature 1")
+	ahead, behind, err = g.GetAheadBehind(ctx, repo, "feature", "main")
+	require.NoError(t, err)
+	assert.Equal(t, [2]int{1, 2}, [2]int{ahead, behind})
+
+	_, _, err = g.GetAheadBehind(ctx, repo, "feature", "missing")
+	assert.Error(t, err)
+}
+
+func TestListBranches(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepo(t)
+	run := func(env []string, args ...string) {
+		cmd := exec.Command("git", append([]string{"-C", repo}, args...)...)
+		cmd.Env = append(os.Environ(), env...)
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	run([]string{"GIT_AUTHOR_NAME=Ada", "GIT_COMMITTER_DATE=2024-03-01T12:00:00Z"},
+		"commit", "-q", "--allow-empty", "-m", "second")
+	run(nil, "branch", "feature", "HEAD~1")
+	run(nil, "update-ref", "refs/remotes/origin/main", "HEAD")
+	run(nil, "symbolic-ref", "refs/remotes/origin/HEAD", "refs/remotes/origin/main")
+	run(nil, "worktree", "add", "-q", filepath.Join(t.TempDir(), "feature"), "feature")
+
+	g := NewGitService(executor.NewDefaultExecutor())
+	branches, err := g.ListBranches(ctx, repo)
+	require.NoError(t, err)
+	require.Len(t, branches, 3)
+
+	// A branch checked out in another worktree is listed by its name
+	assert.Equal(t, "feature", branches[0].Name)
+	assert.Equal(t, "test", branches[0].Author)
+	assert.False(t, branches[0].IsCurrent)
+
+	main := branches[1]
+	assert.Equal(t, "main", main.Name)
+	assert.True(t, main.IsCurrent)
+	assert.Equal(t, "Ada", main.Author)
+	assert.Equal(t, time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC).Unix(), main.UpdatedAt.Unix())
+
+	assert.Equal(t, Branch{Name: "origin/main", IsRemote: true, Hash: main.Hash, UpdatedAt: main.UpdatedAt, Author: "Ada"}, branches[2])
+
+	current, err := g.GetCurrentBranch(ctx, repo)
+	require.NoError(t, err)
+	assert.Equal(t, main, *current)
+}
+
+func TestGetStatus(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepo(t)
+	run := func(args ...string) {
+		out, err := exec.Command("git", append([]string{"-C", repo}, args...)...).CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	write := func(name, content string) {
+		require.NoError(t, os.WriteFile(filepath.Join(repo, name), []byte(content), 0644))
+	}
+	write("old name.txt", "same content\n")
+	write("edited.txt", "a\n")
+	run("add", "-A")
+	run("commit", "-q", "-m", "files")
+
+	run("mv", "old name.txt", "new name.txt")
+	write("edited.txt", "b\n")
+	write("staged.txt", "s\n")
+	run("add", "staged.txt")
+	write("staged.txt", "s2\n")
+	write("new.txt", "n\n")
+
+	g := NewGitService(executor.NewDefaultExecutor())
+	status, err := g.GetStatus(ctx, repo)
+	require.NoError(t, err)
+	assert.Equal(t, []FileStatus{
+		{Path: "edited.txt", Staged: ' ', Unstaged: 'M'},
+		{Path: "new name.txt", OrigPath: "old name.txt", Staged: 'R', Unstaged: ' '},
+		{Path: "staged.txt", Staged: 'A', Unstaged: 'M'},
+		{Path: "new.txt", Staged: '?', Unstaged: '?'},
+	}, status)
+
+	edited, renamed, staged, untracked := status[0], status[1], status[2], status[3]
+	assert.True(t, renamed.IsRenamed())
+	assert.True(t, renamed.IsStaged())
+	assert.False(t, renamed.IsUnstaged())
+	assert.True(t, edited.IsUnstaged())
+	assert.False(t, edited.IsStaged())
+	assert.True(t, staged.IsStaged() && staged.IsUnstaged())
+	assert.True(t, untracked.IsUntracked())
+	assert.False(t, untracked.IsStaged() || untracked.IsUnstaged())
+	assert.True(t, FileStatus{Staged: 'U', Unstaged: 'U'}.IsConflicted())
+	assert.True(t, FileStatus{Staged: 'A', Unstaged: 'A'}.IsConflicted())
+
+	_, err = g.GetStatus(ctx, t.TempDir())
+	assert.Error(t, err)
+}
+
+func TestWorktreeLocking(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepo(t)
+	g := NewGitService(executor.NewDefaultExecutor())
+	path := filepath.Join(t.TempDir(), "wt")
+	require.NoError(t, g.CreateBranch(ctx, repo, "feature"))
+	_, err := g.CreateWorktree(ctx, repo, path, "feature")
+	require.NoError(t, err)
+
+	info, err := g.GetWorktreeInfo(ctx, path)
+	require.NoError(t, err)
+	assert.False(t, info.IsLocked)
+
+	require.NoError(t, g.LockWorktree(ctx, path, "paused"))
+	// Locking again keeps the first reason
+	require.NoError(t, g.LockWorktree(ctx, path, "other"))
+	info, err = g.GetWorktreeInfo(ctx, path)
+	require.NoError(t, err)
+	assert.True(t, info.IsLocked)
+	assert.Equal(t, "paused", info.LockReason)
+
+	worktrees, err := g.ListWorktrees(ctx, repo)
+	require.NoError(t, err)
+	require.Len(t, worktrees, 2)
+	assert.True(t, worktrees[1].IsLocked)
+	assert.False(t, worktrees[0].IsLocked)
+
+	// A locked worktree can't be removed, even forced once
+	assert.Error(t, g.RemoveWorktree(ctx, path, true))
+	require.NoError(t, g.UnlockWorktree(ctx, path))
+	require.NoError(t, g.UnlockWorktree(ctx, path))
+	info, err = g.GetWorktreeInfo(ctx, path)
+	require.NoError(t, err)
+	assert.False(t, info.IsLocked)
+
+	// Nor can one with uncommitted changes unless forced
+	require.NoError(t, os.WriteFile(filepath.Join(path, "new.txt"), []byte("n\n"), 0644))
+	assert.Error(t, g.RemoveWorktree(ctx, path, false))
+	require.NoError(t, g.RemoveWorktree(ctx, path, true))
+}
+
+func TestGetBranchWorktree(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepo(t)
+	g := NewGitService(executor.NewDefaultExecutor())
+	path := filepath.Join(t.TempDir(), "wt")
+	require.NoError(t, g.CreateBranch(ctx, repo, "feature"))
+	require.NoError(t, g.CreateBranch(ctx, repo, "idle"))
+	_, err := g.CreateWorktree(ctx, repo, path, "feature")
+	require.NoError(t, err)
+
+	// The main worktree counts too
+	worktree, err := g.GetBranchWorktree(ctx, repo, "main")
+	require.NoError(t, err)
+	require.NotNil(t, worktree)
+	assert.Equal(t, repo, worktree.Path)
+
+	worktree, err = g.GetBranchWorktree(ctx, repo, "feature")
+	require.NoError(t, err)
+	require.NotNil(t, worktree)
+	assert.Equal(t, path, worktree.Path)
+
+	worktree, err = g.GetBranchWorktree(ctx, repo, "idle")
+	require.NoError(t, err)
+	assert.Nil(t, worktree)
+}
+
+func TestSubmodules(t *testing.T) {
+	ctx := context.Background()
+	lib := newTestRepo(t)
+	repo := newTestRepo(t)
+	// Cloning submodules from local paths is off by default since git 2.38
+	t.Setenv("GIT_CONFIG_COUNT", "1")
+	t.Setenv("GIT_CONFIG_KEY_0", "protocol.file.allow")
+	t.Setenv("GIT_CONFIG_VALUE_0", "always")
+	for _, args := range [][]string{
+		{"submodule", "add", "-q", lib, "vendor/lib"},
+		{"commit", "-q", "-m", "add lib"},
+	} {
+		out, err := exec.Command("git", append([]string{"-C", repo}, args...)...).CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	g := NewGitService(executor.NewDefaultExecutor())
+	libHead, err := g.GetLastCommit(ctx, lib)
+	require.NoError(t, err)
+
+	// A new worktree starts with its submodules uninitialized
+	path := filepath.Join(t.TempDir(), "wt")
+	require.NoError(t, g.CreateBranch(ctx, repo, "feature"))
+	_, err = g.CreateWorktree(ctx, repo, path, "feature")
+	require.NoError(t, err)
+	submodules, err := g.GetSubmoduleStatus(ctx, path)
+	require.NoError(t, err)
+	require.Len(t, submodules, 1)
+	assert.Equal(t, "vendor/lib", submodules[0].Path)
+	assert.Equal(t, libHead.Hash, submodules[0].Hash)
+	assert.False(t, submodules[0].IsInitialized())
+
+	require.NoError(t, g.UpdateSubmodules(ctx, path))
+	submodules, err = g.GetSubmoduleStatus(ctx, path)
+	require.NoError(t, err)
+	require.Len(t, submodules, 1)
+	assert.True(t, submodules[0].IsInitialized())
+	assert.False(t, submodules[0].IsModified())
+	assert.Equal(t, "heads/main", submodules[0].Describe)
+	assert.FileExists(t, filepath.Join(path, "vendor", "lib", ".git"))
+
+	// Repositories without submodules have none to update
+	require.NoError(t, g.UpdateSubmodules(ctx, lib))
+	submodules, err = g.GetSubmoduleStatus(ctx, lib)
+	require.NoError(t, err)
+	assert.Empty(t, submodules)
+}
+
+func TestParseSubmoduleStatus(t *testing.T) {
+	output := "-1111111111111111111111111111111111111111 vendor/a\n" +
+		"+2222222222222222222222222222222222222222 vendor/b c (v1.0-2-g2222222)\n" +
+		"U0000000000000000000000000000000000000000 vendor/c\n"
+	assert.Equal(t, []Submodule{
+		{Path: "vendor/a", Hash: "1111111111111111111111111111111111111111", State: '-'},
+		{Path: "vendor/b c", Hash: "2222222222222222222222222222222222222222", Describe: "v1.0-2-g2222222", State: '+'},
+		{Path: "vendor/c", Hash: "0000000000000000000000000000000000000000", State: 'U'},
+	}, parseSubmoduleStatus(output))
+}
+
+func TestLFS(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepo(t)
+	g := NewGitService(executor.NewDefaultExecutor())
+	run := func(args ...string) {
+		out, err := exec.Command("git", append([]string{"-C", repo}, args...)...).CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+
+	uses, err := g.UsesLFS(ctx, repo)
+	require.NoError(t, err)
+	assert.False(t, uses)
+
+	// Attributes in any directory count, once tracked
+	require.NoError(t, os.MkdirAll(filepath.Join(repo, "assets"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "assets", ".gitattributes"), []byte("*.psd filter=lfs diff=lfs merge=lfs -text\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "assets", "logo.psd"), []byte(lfsPointer("1111", 10)), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "notes.txt"), []byte("a\nb\nc\n"), 0644))
+	run("add", "-A")
+	run("commit", "-q", "-m", "add assets")
+	uses, err = g.UsesLFS(ctx, repo)
+	require.NoError(t, err)
+	assert.True(t, uses)
+
+	// Only the lines of other files are counted
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "assets", "logo.psd"), []byte(lfsPointer("2222", 20)), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "notes.txt"), []byte("a\n"), 0644))
+	stats, err := g.GetDiffStats(ctx, repo)
+	require.NoError(t, err)
+	assert.Equal(t, 2, stats.FilesChanged)
+	assert.Equal(t, 0, stats.Insertions)
+	assert.Equal(t, 2, stats.Deletions)
+	assert.ElementsMatch(t, []FileDiff{
+		{Path: "assets/logo.psd", Status: "modified", LFS: true},
+		{Path: "notes.txt", Status: "deleted", Deletions: 2},
+	}, stats.Files)
+
+	_, err = g.UsesLFS(ctx, t.TempDir())
+	assert.Error(t, err)
+}
+
+func TestSetupLFS(t *testing.T) {
+	ctx := context.Background()
+	var ran [][]string
+	installed := true
+	g := NewGitService(&executor.MockExecutor{
+		CommandExistsFunc: func(ctx context.Context, program string) bool {
+			return installed && program == "git-lfs"
+		},
+		ExecuteFunc: func(ctx context.Context, cmd executor.Command) (*executor.Result, error) {
+			ran = append(ran, cmd.Args)
+			return &executor.Result{}, nil
+		},
+	})
+
+	require.NoError(t, g.SetupLFS(ctx, "/wt"))
+	assert.Equal(t, [][]string{
+		{"-C", "/wt", "lfs", "install", "--local"},
+		{"-C", "/wt", "lfs", "checkout"},
+	}, ran)
+
+	installed = false
+	assert.ErrorContains(t, g.SetupLFS(ctx, "/wt"), "git-lfs is not installed")
+}
+
+func TestApplyPatch(t *testing.T) {
+	ctx := context.Background()
+	source := newTestRepo(t)
+	target := newTestRepo(t)
+	g := NewGitService(executor.NewDefaultExecutor())
+	write := func(dir, name, content string) {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+
+	write(source, "new.txt", "new\n")
+	patch, err := g.GetDiffPatch(ctx, source)
+	require.NoError(t, err)
+
+	require.NoError(t, g.ApplyPatch(ctx, target, strings.NewReader(patch)))
+	content, err := os.ReadFile(filepath.Join(target, "new.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "new\n", string(content))
+	// Applied changes aren't staged
+	status, err := g.GetStatus(ctx, target)
+	require.NoError(t, err)
+	assert.Equal(t, []FileStatus{{Path: "new.txt", Staged: '?', Unstaged: '?'}}, status)
+
+	// Applying it again fails without touching anything
+	write(target, "new.txt", "changed\n")
+	assert.ErrorContains(t, g.ApplyPatch(ctx, target, strings.NewReader(patch)), "already exists")
+	content, err = os.ReadFile(filepath.Join(target, "new.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "changed\n", string(content))
+}
+
+func TestFileHistoryAndBlame(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepo(t)
+	g := NewGitService(executor.NewDefaultExecutor())
+	run := func(args ...string) {
+		out, err := exec.Command("git", append([]string{"-C", repo}, args...)...).CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "a.txt"), []byte("one\ntwo\n"), 0644))
+	run("add", "a.txt")
+	run("commit", "-q", "-m", "add a")
+	run("mv", "a.txt", "b.txt")
+	run("commit", "-q", "-m", "rename a")
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "b.txt"), []byte("one\n2\n"), 0644))
+	run("-c", "user.name=other", "-c", "user.email=other@example.com", "commit", "-q", "-am", "change two")
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "b.txt"), []byte("one\n2\nthree\n"), 0644))
+
+	// The history follows the file back across its rename
+	history, err := g.GetFileHistory(ctx, repo, "b.txt", 0)
+	require.NoError(t, err)
+	var messages []string
+	for _, commit := range history {
+		messages = append(messages, commit.Message)
+	}
+	assert.Equal(t, []string{"change two", "rename a", "add a"}, messages)
+
+	history, err = g.GetFileHistory(ctx, repo, "b.txt", 1)
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, "other", history[0].Author)
+
+	lines, err := g.Blame(ctx, repo, "b.txt")
+	require.NoError(t, err)
+	require.Len(t, lines, 3)
+	assert.Equal(t, "one", lines[0].Content)
+	assert.Equal(t, "add a", lines[0].Summary)
+	assert.Equal(t, "test@example.com", lines[0].Email)
+	assert.Equal(t, 2, lines[1].Line)
+	assert.Equal(t, "change two", lines[1].Summary)
+	assert.Equal(t, "other", lines[1].Author)
+	assert.Equal(t, history[0].Hash, lines[1].Hash)
+	assert.True(t, lines[1].IsCommitted())
+	// Lines changed in the worktree aren't attributed to any commit yet
+	assert.Equal(t, "three", lines[2].Content)
+	assert.False(t, lines[2].IsCommitted())
+
+	_, err = g.Blame(ctx, repo, "missing.txt")
+	assert.Error(t, err)
+}
+
+func TestTags(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepo(t)
+	g := NewGitService(executor.NewDefaultExecutor())
+
+	head, err := g.GetLastCommit(ctx, repo)
+	require.NoError(t, err)
+	require.NoError(t, g.CreateTag(ctx, repo, "squad/auth/done", "main", "Finish auth"))
+	require.NoError(t, g.CreateTag(ctx, repo, "v1", "HEAD", ""))
+	assert.ErrorContains(t, g.CreateTag(ctx, repo, "v1", "HEAD", ""), "already exists")
+
+	// Both kinds of tag report the commit they point at
+	tags, err := g.ListTags(ctx, repo, "")
+	require.NoError(t, err)
+	assert.Equal(t, []Tag{
+		{Name: "squad/auth/done", Hash: head.Hash, Message: "Finish auth"},
+		{Name: "v1", Hash: head.Hash},
+	}, tags)
+
+	tags, err = g.ListTags(ctx, repo, "squad/*")
+	require.NoError(t, err)
+	require.Len(t, tags, 1)
+	assert.Equal(t, "squad/auth/done", tags[0].Name)
+
+	require.NoError(t, g.DeleteTag(ctx, repo, "squad/auth/done"))
+	assert.Error(t, g.DeleteTag(ctx, repo, "squad/auth/done"))
+	tags, err = g.ListTags(ctx, repo, "squad/*")
+	require.NoError(t, err)
+	assert.Empty(t, tags)
+}
+
+func TestStashChanges(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepo(t)
+	g := NewGitService(executor.NewDefaultExecutor())
+
+	stash, err := g.StashChanges(ctx, repo, "nothing")
+	require.NoError(t, err)
+	assert.Empty(t, stash)
+
+	// The untracked file is set aside too, and restored from under a later stash
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "mine.txt"), []byte("mine\n"), 0644))
+	stash, err = g.StashChanges(ctx, repo, "set aside")
+	require.NoError(t, err)
+	require.NotEmpty(t, stash)
+	assert.NoFileExists(t, filepath.Join(repo, "mine.txt"))
+
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "other.txt"), []byte("other\n"), 0644))
+	_, err = g.StashChanges(ctx, repo, "later")
+	require.NoError(t, err)
+
+	require.NoError(t, g.RestoreStash(ctx, repo, stash))
+	assert.FileExists(t, filepath.Join(repo, "mine.txt"))
+	stashes, err := g.ListStashes(ctx, repo)
+	require.NoError(t, err)
+	require.Len(t, stashes, 1)
+	assert.Contains(t, stashes[0], "later")
+
+	assert.ErrorContains(t, g.RestoreStash(ctx, repo, stash), "not found")
+}
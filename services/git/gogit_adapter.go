@@ -0,0 +1,421 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"sort"
+	"strings"
+	"time"
+
+	"claude-squad/services/executor"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/format/gitattributes"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/utils/diff"
+	"github.com/sergi/go-diff/diffmatchpatch"
+)
+
+// goGitAdapter implements GitService like execAdapter, except that the read-only
+// operations views refresh for every session every second, status, diff stats, branches
+// and log, are answered in process by go-git instead of starting a git process each.
+// Everything else, worktrees included, still runs git. So does any read go-git can't
+// serve, e.g. in a repository using an extension it doesn't support.
+type goGitAdapter struct {
+	GitService
+}
+
+// NewGoGitService creates a GitService reading repositories with go-git, and running
+// everything else through exec like NewGitService
+func NewGoGitService(exec executor.CommandExecutor) GitService {
+	return &goGitAdapter{GitService: NewGitService(exec)}
+}
+
+// open opens the repository containing path, which may be a linked worktree
+func (g *goGitAdapter) open(path string) (*git.Repository, error) {
+	return git.PlainOpenWithOptions(path, &git.PlainOpenOptions{
+		DetectDotGit:          true,
+		EnableDotGitCommonDir: true,
+	})
+}
+
+// ListBranches lists all local and remote-tracking branches in the repository
+func (g *goGitAdapter) ListBranches(ctx context.Context, repoPath string) ([]Branch, error) {
+	repo, err := g.open(repoPath)
+	if err != nil {
+		return g.GitService.ListBranches(ctx, repoPath)
+	}
+	var current plumbing.ReferenceName
+	if head, err := repo.Head(); err == nil {
+		current = head.Name()
+	}
+	refs, err := repo.References()
+	if err != nil {
+		return g.GitService.ListBranches(ctx, repoPath)
+	}
+	defer refs.Close()
+
+	var local, remote []Branch
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		// Symbolic references like origin/HEAD only point at another branch
+		if ref.Type() != plumbing.HashReference {
+			return nil
+		}
+		branch := Branch{Name: ref.Name().Short()}
+		setBranchCommit(repo, &branch, ref.Hash())
+		switch {
+		case ref.Name().IsBranch():
+			branch.IsCurrent = ref.Name() == current
+			local = append(local, branch)
+		case ref.Name().IsRemote():
+			branch.IsRemote = true
+			remote = append(remote, branch)
+		}
+		return nil
+	})
+	if err != nil {
+		return g.GitService.ListBranches(ctx, repoPath)
+	}
+
+	// Sorted by name, local branches first, like git branch lists them
+	byName := func(branches []Branch) {
+		sort.Slice(branches, func(i, j int) bool { return branches[i].Name < branches[j].Name })
+	}
+	byName(local)
+	byName(remote)
+	return append(local, remote...), nil
+}
+
+// GetCurrentBranch gets the branch checked out at repoPath
+func (g *goGitAdapter) GetCurrentBranch(ctx context.Context, repoPath string) (*Branch, error) {
+	repo, err := g.open(repoPath)
+	if err != nil {
+		return g.GitService.GetCurrentBranch(ctx, repoPath)
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return g.GitService.GetCurrentBranch(ctx, repoPath)
+	}
+	if !head.Name().IsBranch() {
+		return nil, fmt.Errorf("not on any branch (detached HEAD)")
+	}
+	branch := &Branch{Name: head.Name().Short(), IsCurrent: true}
+	setBranchCommit(repo, branch, head.Hash())
+	return branch, nil
+}
+
+// setBranchCommit fills in the hash of the commit branch points at, and its date and
+// author when the commit can be read
+func setBranchCommit(repo *git.Repository, branch *Branch, hash plumbing.Hash) {
+	branch.Hash = hash.String()
+	if commit, err := repo.CommitObject(hash); err == nil {
+		branch.UpdatedAt = time.Unix(commit.Committer.When.Unix(), 0)
+		branch.Author = commit.Author.Name
+	}
+}
+
+// GetStatus lists the files that are staged, changed in the worktree, untracked or
+// unmerged, sorted by path like git status
+func (g *goGitAdapter) GetStatus(ctx context.Context, repoPath string) ([]FileStatus, error) {
+	status, err := g.status(repoPath)
+	if err != nil {
+		return g.GitService.GetStatus(ctx, repoPath)
+	}
+
+	paths := make([]string, 0, len(status))
+	for path := range status {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var files []FileStatus
+	for _, path := range paths {
+		file := status[path]
+		if file.Staging == git.Unmodified && file.Worktree == git.Unmodified {
+			continue
+		}
+		files = append(files, FileStatus{
+			Path:     path,
+			OrigPath: file.Extra,
+			Staged:   byte(file.Staging),
+			Unstaged: byte(file.Worktree),
+		})
+	}
+	return files, nil
+}
+
+// HasUncommittedChanges checks if there are uncommitted changes
+func (g *goGitAdapter) HasUncommittedChanges(ctx context.Context, repoPath string) (bool, error) {
+	status, err := g.status(repoPath)
+	if err != nil {
+		return g.GitService.HasUncommittedChanges(ctx, repoPath)
+	}
+	return !status.IsClean(), nil
+}
+
+// status reads the status of the worktree at repoPath
+func (g *goGitAdapter) status(repoPath string) (git.Status, error) {
+	repo, err := g.open(repoPath)
+	if err != nil {
+		return nil, err
+	}
+	return worktreeStatus(repo)
+}
+
+// worktreeStatus reads the status of repo's worktree. go-git takes files added with
+// --intent-to-add, as GetDiffPatch does, for staged ones; git shows them as added in the
+// worktree only, and so do we.
+func worktreeStatus(repo *git.Repository) (git.Status, error) {
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return nil, err
+	}
+	status, err := worktree.Status()
+	if err != nil {
+		return nil, err
+	}
+	idx, err := repo.Storer.Index()
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range idx.Entries {
+		if file, ok := status[entry.Name]; ok && entry.IntentToAdd && file.Worktree != git.Deleted {
+			file.Staging, file.Worktree = git.Unmodified, git.Added
+		}
+	}
+	return status, nil
+}
+
+// GetDiffStats gets diff statistics for the working directory vs HEAD. Like git diff
+// HEAD, untracked files aren't counted unless they were added with --intent-to-add.
+func (g *goGitAdapter) GetDiffStats(ctx context.Context, repoPath string) (*DiffStats, error) {
+	stats, err := g.diffStats(repoPath)
+	if err != nil {
+		return g.GitService.GetDiffStats(ctx, repoPath)
+	}
+	return stats, nil
+}
+
+func (g *goGitAdapter) diffStats(repoPath string) (*DiffStats, error) {
+	repo, err := g.open(repoPath)
+	if err != nil {
+		return nil, err
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return nil, err
+	}
+	status, err := worktreeStatus(repo)
+	if err != nil {
+		return nil, err
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return nil, err
+	}
+	commit, err := repo.CommitObject(head.Hash())
+	if err != nil {
+		return nil, err
+	}
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make([]string, 0, len(status))
+	for path, file := range status {
+		if file.Worktree == git.Untracked || (file.Staging == git.Unmodified && file.Worktree == git.Unmodified) {
+			continue
+		}
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	// Without attributes, no file is taken for an LFS one
+	var attributes gitattributes.Matcher
+	if patterns, err := gitattributes.ReadPatterns(worktree.Filesystem, nil); err == nil {
+		attributes = gitattributes.NewMatcher(patterns)
+	}
+
+	stats := &DiffStats{}
+	for _, path := range paths {
+		if isLFS(attributes, path) {
+			// The worktree has the content where HEAD has a pointer, so neither is diffed
+			stats.Files = append(stats.Files, FileDiff{Path: path, Status: lfsChange(status[path]), LFS: true})
+			continue
+		}
+
+		before, beforeBinary, err := headContents(tree, path)
+		if err != nil {
+			return nil, err
+		}
+		after, afterBinary, err := worktreeContents(worktree, path)
+		if err != nil {
+			return nil, err
+		}
+		if before == after && !beforeBinary && !afterBinary {
+			// Only the mode or the index changed
+			continue
+		}
+
+		file := FileDiff{Path: path, Status: "modified", Binary: beforeBinary || afterBinary}
+		if !file.Binary {
+			file.Insertions, file.Deletions = countChangedLines(before, after)
+		}
+		if file.Insertions > 0 && file.Deletions == 0 {
+			file.Status = "added"
+		} else if file.Insertions == 0 && file.Deletions > 0 {
+			file.Status = "deleted"
+		}
+
+		stats.Files = append(stats.Files, file)
+		stats.Insertions += file.Insertions
+		stats.Deletions += file.Deletions
+	}
+	stats.FilesChanged = len(stats.Files)
+	return stats, nil
+}
+
+// isLFS reports whether attributes send path through Git LFS's filter
+func isLFS(attributes gitattributes.Matcher, path string) bool {
+	if attributes == nil {
+		return false
+	}
+	matched, ok := attributes.Match(strings.Split(path, "/"), []string{"filter"})
+	if !ok {
+		return false
+	}
+	filter, ok := matched["filter"]
+	return ok && filter.IsValueSet() && filter.Value() == "lfs"
+}
+
+// lfsChange names the change to an LFS file from its status, there being no line counts
+// to tell it by
+func lfsChange(file *git.FileStatus) string {
+	switch {
+	case file.Staging == git.Added || file.Worktree == git.Added:
+		return "added"
+	case file.Staging == git.Deleted || file.Worktree == git.Deleted:
+		return "deleted"
+	default:
+		return "modified"
+	}
+}
+
+// headContents reads path as committed in tree, which is empty when it isn't there
+func headContents(tree *object.Tree, path string) (string, bool, error) {
+	file, err := tree.File(path)
+	if errors.Is(err, object.ErrFileNotFound) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	if binary, err := file.IsBinary(); err != nil || binary {
+		return "", binary, err
+	}
+	contents, err := file.Contents()
+	return contents, false, err
+}
+
+// worktreeContents reads path from the worktree, which is empty once it has been deleted
+func worktreeContents(worktree *git.Worktree, path string) (string, bool, error) {
+	f, err := worktree.Filesystem.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	defer f.Close()
+
+	contents, err := io.ReadAll(f)
+	if err != nil {
+		return "", false, err
+	}
+	// git's own heuristic: a NUL byte early on makes a file binary
+	if bytes.IndexByte(contents[:min(len(contents), 8000)], 0) >= 0 {
+		return "", true, nil
+	}
+	return string(contents), false, nil
+}
+
+// countChangedLines counts the lines added and removed going from before to after
+func countChangedLines(before, after string) (insertions, deletions int) {
+	for _, d := range diff.Do(before, after) {
+		lines := strings.Count(d.Text, "\n")
+		if !strings.HasSuffix(d.Text, "\n") {
+			lines++
+		}
+		switch d.Type {
+		case diffmatchpatch.DiffInsert:
+			insertions += lines
+		case diffmatchpatch.DiffDelete:
+			deletions += lines
+		}
+	}
+	return insertions, deletions
+}
+
+// GetLastCommit gets information about the last commit
+func (g *goGitAdapter) GetLastCommit(ctx context.Context, repoPath string) (*CommitInfo, error) {
+	commits, err := g.log(repoPath, 1)
+	if err != nil {
+		return g.GitService.GetLastCommit(ctx, repoPath)
+	}
+	if len(commits) == 0 {
+		return nil, fmt.Errorf("failed to get last commit: no commits")
+	}
+	return commits[0], nil
+}
+
+// GetCommitHistory gets commit history with a limit
+func (g *goGitAdapter) GetCommitHistory(ctx context.Context, repoPath string, limit int) ([]*CommitInfo, error) {
+	commits, err := g.log(repoPath, limit)
+	if err != nil {
+		return g.GitService.GetCommitHistory(ctx, repoPath, limit)
+	}
+	return commits, nil
+}
+
+// log lists up to limit commits reachable from HEAD, newest first like git log
+func (g *goGitAdapter) log(repoPath string, limit int) ([]*CommitInfo, error) {
+	repo, err := g.open(repoPath)
+	if err != nil {
+		return nil, err
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return nil, err
+	}
+	iter, err := repo.Log(&git.LogOptions{From: head.Hash(), Order: git.LogOrderCommitterTime})
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close()
+
+	var commits []*CommitInfo
+	for len(commits) < limit {
+		commit, err := iter.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		subject, _, _ := strings.Cut(strings.TrimSpace(commit.Message), "\n")
+		commits = append(commits, &CommitInfo{
+			Hash:      commit.Hash.String(),
+			Author:    commit.Author.Name,
+			Email:     commit.Author.Email,
+			Timestamp: time.Unix(commit.Committer.When.Unix(), 0),
+			Message:   strings.TrimSpace(subject),
+		})
+	}
+	return commits, nil
+}
@@ -0,0 +1,127 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"claude-squad/services/executor"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestGoGitMatchesExec checks that go-git answers the reads it serves the way git does,
+// in the main checkout and in a linked worktree
+func TestGoGitMatchesExec(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepo(t)
+	run := func(dir string, args ...string) {
+		out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	write := func(dir, name, content string) {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+	write(repo, "a.txt", "one\ntwo\nthree\n")
+	write(repo, "b.txt", "b\n")
+	write(repo, "bin.dat", "\x00\x01")
+	write(repo, ".gitattributes", "*.bin filter=lfs diff=lfs merge=lfs -text\n")
+	write(repo, "model.bin", lfsPointer("1111", 10))
+	run(repo, "add", "-A")
+	run(repo, "commit", "-q", "-m", "add files\n\nwith a body")
+	run(repo, "branch", "feature")
+	worktree := filepath.Join(t.TempDir(), "feature")
+	run(repo, "worktree", "add", "-q", worktree, "feature")
+
+	execGit := NewGitService(executor.NewDefaultExecutor())
+	goGit := NewGoGitService(executor.NewDefaultExecutor())
+	same := func(dir string) {
+		t.Helper()
+		wantStatus, err := execGit.GetStatus(ctx, dir)
+		require.NoError(t, err)
+		status, err := goGit.GetStatus(ctx, dir)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, wantStatus, status)
+
+		wantDirty, err := execGit.HasUncommittedChanges(ctx, dir)
+		require.NoError(t, err)
+		dirty, err := goGit.HasUncommittedChanges(ctx, dir)
+		require.NoError(t, err)
+		assert.Equal(t, wantDirty, dirty)
+
+		wantStats, err := execGit.GetDiffStats(ctx, dir)
+		require.NoError(t, err)
+		stats, err := goGit.GetDiffStats(ctx, dir)
+		require.NoError(t, err)
+		assert.Equal(t, wantStats.FilesChanged, stats.FilesChanged)
+		assert.Equal(t, wantStats.Insertions, stats.Insertions)
+		assert.Equal(t, wantStats.Deletions, stats.Deletions)
+		assert.ElementsMatch(t, wantStats.Files, stats.Files)
+
+		wantBranch, err := execGit.GetCurrentBranch(ctx, dir)
+		require.NoError(t, err)
+		branch, err := goGit.GetCurrentBranch(ctx, dir)
+		require.NoError(t, err)
+		assert.Equal(t, wantBranch, branch)
+
+		wantLog, err := execGit.GetCommitHistory(ctx, dir, 10)
+		require.NoError(t, err)
+		log, err := goGit.GetCommitHistory(ctx, dir, 10)
+		require.NoError(t, err)
+		assert.Equal(t, wantLog, log)
+	}
+
+	same(repo)
+	same(worktree)
+
+	// Modified, deleted, staged, binary and untracked files
+	write(repo, "a.txt", "one\n2\nthree\nfour\n")
+	require.NoError(t, os.Remove(filepath.Join(repo, "b.txt")))
+	write(repo, "bin.dat", "\x00\x02")
+	write(repo, "c.txt", "c\n")
+	write(repo, "untracked.txt", "u\n")
+	write(repo, "model.bin", lfsPointer("2222", 20))
+	write(repo, "weights.bin", lfsPointer("3333", 30))
+	run(repo, "add", "c.txt", "weights.bin")
+	same(repo)
+
+	write(worktree, "a.txt", "one\n")
+	run(worktree, "commit", "-q", "-am", "trim a")
+	write(worktree, "d.txt", "d\n")
+	run(worktree, "add", "--intent-to-add", "d.txt")
+	same(worktree)
+
+	run(repo, "update-ref", "refs/remotes/origin/main", "feature")
+	run(repo, "symbolic-ref", "refs/remotes/origin/HEAD", "refs/remotes/origin/main")
+	wantBranches, err := execGit.ListBranches(ctx, repo)
+	require.NoError(t, err)
+	branches, err := goGit.ListBranches(ctx, repo)
+	require.NoError(t, err)
+	assert.Equal(t, wantBranches, branches)
+
+	run(repo, "checkout", "-q", "--detach")
+	_, err = goGit.GetCurrentBranch(ctx, repo)
+	assert.ErrorContains(t, err, "detached HEAD")
+
+	// Reads of something that isn't a repository fail like git's
+	_, err = goGit.GetLastCommit(ctx, t.TempDir())
+	assert.Error(t, err)
+}
+
+func TestNewService(t *testing.T) {
+	for _, backend := range []string{"", BackendExec, BackendGoGit} {
+		_, err := NewService(backend, executor.NewDefaultExecutor())
+		assert.NoError(t, err, backend)
+	}
+	_, err := NewService("libgit2", executor.NewDefaultExecutor())
+	assert.ErrorContains(t, err, "unknown git backend")
+}
+
+// lfsPointer returns the pointer Git LFS commits in place of a file's content
+func lfsPointer(oid string, size int) string {
+	return fmt.Sprintf("version https://git-lfs.github.com/spec/v1\noid sha256:%s\nsize %d\n", oid, size)
+}
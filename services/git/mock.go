@@ -3,6 +3,7 @@ package git
 import (
 	"context"
 	"fmt"
+	"io"
 	"time"
 )
 
@@ -13,23 +14,66 @@ type MockGitService struct {
 	GetRepositoryRootFunc            func(ctx context.Context, path string) (string, error)
 	ListBranchesFunc                 func(ctx context.Context, repoPath string) ([]Branch, error)
 	CreateBranchFunc                 func(ctx context.Context, repoPath, branchName string) error
+	CreateBranchFromFunc             func(ctx context.Context, repoPath, branchName, startPoint string) error
+	RenameBranchFunc                 func(ctx context.Context, repoPath, oldName, newName string) error
+	BranchExistsFunc                 func(ctx context.Context, repoPath, branchName string) (bool, error)
+	CreateBundleFunc                 func(ctx context.Context, repoPath, bundlePath, branchName string) error
+	FetchBundleFunc                  func(ctx context.Context, repoPath, bundlePath, branchName string) error
 	DeleteBranchFunc                 func(ctx context.Context, repoPath, branchName string, force bool) error
+	CreateTagFunc                    func(ctx context.Context, repoPath, name, ref, message string) error
+	ListTagsFunc                     func(ctx context.Context, repoPath, pattern string) ([]Tag, error)
+	DeleteTagFunc                    func(ctx context.Context, repoPath, name string) error
 	CheckoutBranchFunc               func(ctx context.Context, repoPath, branchName string) error
 	GetCurrentBranchFunc             func(ctx context.Context, repoPath string) (*Branch, error)
 	CreateWorktreeFunc               func(ctx context.Context, repoPath, worktreePath, branch string) (*Worktree, error)
 	ListWorktreesFunc                func(ctx context.Context, repoPath string) ([]*Worktree, error)
 	RemoveWorktreeFunc               func(ctx context.Context, worktreePath string, force bool) error
 	GetWorktreeInfoFunc              func(ctx context.Context, worktreePath string) (*Worktree, error)
+	MoveWorktreeFunc                 func(ctx context.Context, repoPath, worktreePath, newPath string) error
+	GetBranchWorktreeFunc            func(ctx context.Context, repoPath, branch string) (*Worktree, error)
+	LockWorktreeFunc                 func(ctx context.Context, worktreePath, reason string) error
+	UnlockWorktreeFunc               func(ctx context.Context, worktreePath string) error
+	UpdateSubmodulesFunc             func(ctx context.Context, worktreePath string) error
+	GetSubmoduleStatusFunc           func(ctx context.Context, repoPath string) ([]Submodule, error)
+	UsesLFSFunc                      func(ctx context.Context, repoPath string) (bool, error)
+	SetupLFSFunc                     func(ctx context.Context, worktreePath string) error
+	GetDiffFunc                      func(ctx context.Context, repoPath string) (string, error)
+	GetDiffStagedFunc                func(ctx context.Context, repoPath string) (string, error)
+	GetDiffBetweenFunc               func(ctx context.Context, repoPath, from, to string) (string, error)
 	GetDiffStatsFunc                 func(ctx context.Context, repoPath string) (*DiffStats, error)
 	GetDiffStatsStagedFunc           func(ctx context.Context, repoPath string) (*DiffStats, error)
 	GetDiffStatsBetweenBranchesFunc func(ctx context.Context, repoPath, fromBranch, toBranch string) (*DiffStats, error)
+	GetDiffPatchFunc                 func(ctx context.Context, repoPath string) (string, error)
+	GetChangedFilesFunc              func(ctx context.Context, repoPath string) ([]FileDiff, error)
 	CommitFunc                       func(ctx context.Context, repoPath, message string) error
+	CommitWithOptionsFunc            func(ctx context.Context, repoPath string, opts CommitOptions) (*CommitInfo, error)
+	CommitPathsFunc                  func(ctx context.Context, repoPath, message string, paths []string) (*CommitInfo, error)
+	StageFilesFunc                   func(ctx context.Context, repoPath string, paths []string) error
+	UnstageFilesFunc                 func(ctx context.Context, repoPath string, paths []string) error
 	GetLastCommitFunc                func(ctx context.Context, repoPath string) (*CommitInfo, error)
 	GetCommitHistoryFunc             func(ctx context.Context, repoPath string, limit int) ([]*CommitInfo, error)
+	GetFileHistoryFunc               func(ctx context.Context, repoPath, path string, limit int) ([]*CommitInfo, error)
+	BlameFunc                        func(ctx context.Context, repoPath, path string) ([]BlameLine, error)
+	GetCommitsBetweenFunc            func(ctx context.Context, repoPath, base, head string) ([]*CommitInfo, error)
+	GetAheadBehindFunc               func(ctx context.Context, repoPath, branch, base string) (int, int, error)
+	MergeFunc                        func(ctx context.Context, repoPath, branch string, opts MergeOptions) error
+	SquashMergeFunc                  func(ctx context.Context, repoPath, branch, message string) error
+	RebaseFunc                       func(ctx context.Context, repoPath, onto string) error
+	RebaseContinueFunc               func(ctx context.Context, repoPath string) error
+	RebaseAbortFunc                  func(ctx context.Context, repoPath string) error
+	CherryPickFunc                   func(ctx context.Context, repoPath, commit string) error
+	ApplyPatchFunc                   func(ctx context.Context, repoPath string, patch io.Reader) error
+	FetchFunc                        func(ctx context.Context, repoPath, remote string) error
+	GetConflictsFunc                 func(ctx context.Context, repoPath, base, branch string) ([]FileConflict, error)
+	PushFunc                         func(ctx context.Context, repoPath, branch string, opts PushOptions) error
+	GetRemoteURLFunc                 func(ctx context.Context, repoPath, remote string) (string, error)
+	GetDefaultBranchFunc             func(ctx context.Context, repoPath, remote string) (string, error)
 	StashFunc                        func(ctx context.Context, repoPath, message string) error
 	PopStashFunc                     func(ctx context.Context, repoPath string) error
 	ListStashesFunc                  func(ctx context.Context, repoPath string) ([]string, error)
-	GetStatusFunc                    func(ctx context.Context, repoPath string) ([]string, error)
+	StashChangesFunc                 func(ctx context.Context, repoPath, message string) (string, error)
+	RestoreStashFunc                 func(ctx context.Context, repoPath, stash string) error
+	GetStatusFunc                    func(ctx context.Context, repoPath string) ([]FileStatus, error)
 	HasUncommittedChangesFunc        func(ctx context.Context, repoPath string) (bool, error)
 	CleanupWorktreesFunc             func(ctx context.Context, repoPath string) error
 	PruneWorktreesFunc               func(ctx context.Context, repoPath string) error
@@ -100,6 +144,41 @@ func (m *MockGitService) CreateBranch(ctx context.Context, repoPath, branchName
 	return nil
 }
 
+func (m *MockGitService) CreateBranchFrom(ctx context.Context, repoPath, branchName, startPoint string) error {
+	if m.CreateBranchFromFunc != nil {
+		return m.CreateBranchFromFunc(ctx, repoPath, branchName, startPoint)
+	}
+	return nil
+}
+
+func (m *MockGitService) RenameBranch(ctx context.Context, repoPath, oldName, newName string) error {
+	if m.RenameBranchFunc != nil {
+		return m.RenameBranchFunc(ctx, repoPath, oldName, newName)
+	}
+	return nil
+}
+
+func (m *MockGitService) BranchExists(ctx context.Context, repoPath, branchName string) (bool, error) {
+	if m.BranchExistsFunc != nil {
+		return m.BranchExistsFunc(ctx, repoPath, branchName)
+	}
+	return false, nil
+}
+
+func (m *MockGitService) CreateBundle(ctx context.Context, repoPath, bundlePath, branchName string) error {
+	if m.CreateBundleFunc != nil {
+		return m.CreateBundleFunc(ctx, repoPath, bundlePath, branchName)
+	}
+	return nil
+}
+
+func (m *MockGitService) FetchBundle(ctx context.Context, repoPath, bundlePath, branchName string) error {
+	if m.FetchBundleFunc != nil {
+		return m.FetchBundleFunc(ctx, repoPath, bundlePath, branchName)
+	}
+	return nil
+}
+
 func (m *MockGitService) DeleteBranch(ctx context.Context, repoPath, branchName string, force bool) error {
 	if m.DeleteBranchFunc != nil {
 		return m.DeleteBranchFunc(ctx, repoPath, branchName, force)
@@ -121,6 +200,27 @@ func (m *MockGitService) GetCurrentBranch(ctx context.Context, repoPath string)
 	return &Branch{Name: m.DefaultBranch, IsCurrent: true, Hash: "abc123"}, nil
 }
 
+func (m *MockGitService) CreateTag(ctx context.Context, repoPath, name, ref, message string) error {
+	if m.CreateTagFunc != nil {
+		return m.CreateTagFunc(ctx, repoPath, name, ref, message)
+	}
+	return nil
+}
+
+func (m *MockGitService) ListTags(ctx context.Context, repoPath, pattern string) ([]Tag, error) {
+	if m.ListTagsFunc != nil {
+		return m.ListTagsFunc(ctx, repoPath, pattern)
+	}
+	return nil, nil
+}
+
+func (m *MockGitService) DeleteTag(ctx context.Context, repoPath, name string) error {
+	if m.DeleteTagFunc != nil {
+		return m.DeleteTagFunc(ctx, repoPath, name)
+	}
+	return nil
+}
+
 func (m *MockGitService) CreateWorktree(ctx context.Context, repoPath, worktreePath, branch string) (*Worktree, error) {
 	if m.CreateWorktreeFunc != nil {
 		return m.CreateWorktreeFunc(ctx, repoPath, worktreePath, branch)
@@ -156,6 +256,83 @@ func (m *MockGitService) GetWorktreeInfo(ctx context.Context, worktreePath strin
 	return nil, fmt.Errorf("worktree not found")
 }
 
+func (m *MockGitService) MoveWorktree(ctx context.Context, repoPath, worktreePath, newPath string) error {
+	if m.MoveWorktreeFunc != nil {
+		return m.MoveWorktreeFunc(ctx, repoPath, worktreePath, newPath)
+	}
+	return nil
+}
+
+func (m *MockGitService) GetBranchWorktree(ctx context.Context, repoPath, branch string) (*Worktree, error) {
+	if m.GetBranchWorktreeFunc != nil {
+		return m.GetBranchWorktreeFunc(ctx, repoPath, branch)
+	}
+	return nil, nil
+}
+
+func (m *MockGitService) LockWorktree(ctx context.Context, worktreePath, reason string) error {
+	if m.LockWorktreeFunc != nil {
+		return m.LockWorktreeFunc(ctx, worktreePath, reason)
+	}
+	return nil
+}
+
+func (m *MockGitService) UnlockWorktree(ctx context.Context, worktreePath string) error {
+	if m.UnlockWorktreeFunc != nil {
+		return m.UnlockWorktreeFunc(ctx, worktreePath)
+	}
+	return nil
+}
+
+func (m *MockGitService) UpdateSubmodules(ctx context.Context, worktreePath string) error {
+	if m.UpdateSubmodulesFunc != nil {
+		return m.UpdateSubmodulesFunc(ctx, worktreePath)
+	}
+	return nil
+}
+
+func (m *MockGitService) GetSubmoduleStatus(ctx context.Context, repoPath string) ([]Submodule, error) {
+	if m.GetSubmoduleStatusFunc != nil {
+		return m.GetSubmoduleStatusFunc(ctx, repoPath)
+	}
+	return nil, nil
+}
+
+func (m *MockGitService) UsesLFS(ctx context.Context, repoPath string) (bool, error) {
+	if m.UsesLFSFunc != nil {
+		return m.UsesLFSFunc(ctx, repoPath)
+	}
+	return false, nil
+}
+
+func (m *MockGitService) SetupLFS(ctx context.Context, worktreePath string) error {
+	if m.SetupLFSFunc != nil {
+		return m.SetupLFSFunc(ctx, worktreePath)
+	}
+	return nil
+}
+
+func (m *MockGitService) GetDiff(ctx context.Context, repoPath string) (string, error) {
+	if m.GetDiffFunc != nil {
+		return m.GetDiffFunc(ctx, repoPath)
+	}
+	return "", nil
+}
+
+func (m *MockGitService) GetDiffStaged(ctx context.Context, repoPath string) (string, error) {
+	if m.GetDiffStagedFunc != nil {
+		return m.GetDiffStagedFunc(ctx, repoPath)
+	}
+	return "", nil
+}
+
+func (m *MockGitService) GetDiffBetween(ctx context.Context, repoPath, from, to string) (string, error) {
+	if m.GetDiffBetweenFunc != nil {
+		return m.GetDiffBetweenFunc(ctx, repoPath, from, to)
+	}
+	return "", nil
+}
+
 func (m *MockGitService) GetDiffStats(ctx context.Context, repoPath string) (*DiffStats, error) {
 	if m.GetDiffStatsFunc != nil {
 		return m.GetDiffStatsFunc(ctx, repoPath)
@@ -177,6 +354,23 @@ func (m *MockGitService) GetDiffStatsBetweenBranches(ctx context.Context, repoPa
 	return m.DefaultDiffStats, nil
 }
 
+func (m *MockGitService) GetDiffPatch(ctx context.Context, repoPath string) (string, error) {
+	if m.GetDiffPatchFunc != nil {
+		return m.GetDiffPatchFunc(ctx, repoPath)
+	}
+	return "", nil
+}
+
+func (m *MockGitService) GetChangedFiles(ctx context.Context, repoPath string) ([]FileDiff, error) {
+	if m.GetChangedFilesFunc != nil {
+		return m.GetChangedFilesFunc(ctx, repoPath)
+	}
+	if m.DefaultDiffStats != nil {
+		return m.DefaultDiffStats.Files, nil
+	}
+	return nil, nil
+}
+
 func (m *MockGitService) Commit(ctx context.Context, repoPath, message string) error {
 	if m.CommitFunc != nil {
 		return m.CommitFunc(ctx, repoPath, message)
@@ -184,6 +378,34 @@ func (m *MockGitService) Commit(ctx context.Context, repoPath, message string) e
 	return nil
 }
 
+func (m *MockGitService) CommitWithOptions(ctx context.Context, repoPath string, opts CommitOptions) (*CommitInfo, error) {
+	if m.CommitWithOptionsFunc != nil {
+		return m.CommitWithOptionsFunc(ctx, repoPath, opts)
+	}
+	return &CommitInfo{Message: opts.Message}, nil
+}
+
+func (m *MockGitService) CommitPaths(ctx context.Context, repoPath, message string, paths []string) (*CommitInfo, error) {
+	if m.CommitPathsFunc != nil {
+		return m.CommitPathsFunc(ctx, repoPath, message, paths)
+	}
+	return &CommitInfo{Message: message}, nil
+}
+
+func (m *MockGitService) StageFiles(ctx context.Context, repoPath string, paths []string) error {
+	if m.StageFilesFunc != nil {
+		return m.StageFilesFunc(ctx, repoPath, paths)
+	}
+	return nil
+}
+
+func (m *MockGitService) UnstageFiles(ctx context.Context, repoPath string, paths []string) error {
+	if m.UnstageFilesFunc != nil {
+		return m.UnstageFilesFunc(ctx, repoPath, paths)
+	}
+	return nil
+}
+
 func (m *MockGitService) GetLastCommit(ctx context.Context, repoPath string) (*CommitInfo, error) {
 	if m.GetLastCommitFunc != nil {
 		return m.GetLastCommitFunc(ctx, repoPath)
@@ -198,6 +420,118 @@ func (m *MockGitService) GetCommitHistory(ctx context.Context, repoPath string,
 	return []*CommitInfo{m.DefaultCommitInfo}, nil
 }
 
+func (m *MockGitService) GetCommitsBetween(ctx context.Context, repoPath, base, head string) ([]*CommitInfo, error) {
+	if m.GetCommitsBetweenFunc != nil {
+		return m.GetCommitsBetweenFunc(ctx, repoPath, base, head)
+	}
+	return []*CommitInfo{m.DefaultCommitInfo}, nil
+}
+
+func (m *MockGitService) GetAheadBehind(ctx context.Context, repoPath, branch, base string) (int, int, error) {
+	if m.GetAheadBehindFunc != nil {
+		return m.GetAheadBehindFunc(ctx, repoPath, branch, base)
+	}
+	return 0, 0, nil
+}
+
+func (m *MockGitService) GetFileHistory(ctx context.Context, repoPath, path string, limit int) ([]*CommitInfo, error) {
+	if m.GetFileHistoryFunc != nil {
+		return m.GetFileHistoryFunc(ctx, repoPath, path, limit)
+	}
+	return nil, nil
+}
+
+func (m *MockGitService) Blame(ctx context.Context, repoPath, path string) ([]BlameLine, error) {
+	if m.BlameFunc != nil {
+		return m.BlameFunc(ctx, repoPath, path)
+	}
+	return nil, nil
+}
+
+func (m *MockGitService) Merge(ctx context.Context, repoPath, branch string, opts MergeOptions) error {
+	if m.MergeFunc != nil {
+		return m.MergeFunc(ctx, repoPath, branch, opts)
+	}
+	return nil
+}
+
+func (m *MockGitService) SquashMerge(ctx context.Context, repoPath, branch, message string) error {
+	if m.SquashMergeFunc != nil {
+		return m.SquashMergeFunc(ctx, repoPath, branch, message)
+	}
+	return nil
+}
+
+func (m *MockGitService) Rebase(ctx context.Context, repoPath, onto string) error {
+	if m.RebaseFunc != nil {
+		return m.RebaseFunc(ctx, repoPath, onto)
+	}
+	return nil
+}
+
+func (m *MockGitService) RebaseContinue(ctx context.Context, repoPath string) error {
+	if m.RebaseContinueFunc != nil {
+		return m.RebaseContinueFunc(ctx, repoPath)
+	}
+	return nil
+}
+
+func (m *MockGitService) RebaseAbort(ctx context.Context, repoPath string) error {
+	if m.RebaseAbortFunc != nil {
+		return m.RebaseAbortFunc(ctx, repoPath)
+	}
+	return nil
+}
+
+func (m *MockGitService) CherryPick(ctx context.Context, repoPath, commit string) error {
+	if m.CherryPickFunc != nil {
+		return m.CherryPickFunc(ctx, repoPath, commit)
+	}
+	return nil
+}
+
+func (m *MockGitService) ApplyPatch(ctx context.Context, repoPath string, patch io.Reader) error {
+	if m.ApplyPatchFunc != nil {
+		return m.ApplyPatchFunc(ctx, repoPath, patch)
+	}
+	return nil
+}
+
+func (m *MockGitService) GetConflicts(ctx context.Context, repoPath, base, branch string) ([]FileConflict, error) {
+	if m.GetConflictsFunc != nil {
+		return m.GetConflictsFunc(ctx, repoPath, base, branch)
+	}
+	return nil, nil
+}
+
+func (m *MockGitService) Fetch(ctx context.Context, repoPath, remote string) error {
+	if m.FetchFunc != nil {
+		return m.FetchFunc(ctx, repoPath, remote)
+	}
+	return nil
+}
+
+func (m *MockGitService) Push(ctx context.Context, repoPath, branch string, opts PushOptions) error {
+	if m.PushFunc != nil {
+		return m.PushFunc(ctx, repoPath, branch, opts)
+	}
+	return nil
+}
+
+func (m *MockGitService) GetRemoteURL(ctx context.Context, repoPath, remote string) (string, error) {
+	if m.GetRemoteURLFunc != nil {
+		return m.GetRemoteURLFunc(ctx, repoPath, remote)
+	}
+	return "", nil
+}
+
+func (m *MockGitService) GetDefaultBranch(ctx context.Context, repoPath, remote string) (string, error) {
+	if m.GetDefaultBranchFunc != nil {
+		return m.GetDefaultBranchFunc(ctx, repoPath, remote)
+	}
+	return "main", nil
+}
+
 func (m *MockGitService) Stash(ctx context.Context, repoPath, message string) error {
 	if m.StashFunc != nil {
 		return m.StashFunc(ctx, repoPath, message)
@@ -219,11 +553,25 @@ func (m *MockGitService) ListStashes(ctx context.Context, repoPath string) ([]st
 	return []string{}, nil
 }
 
-func (m *MockGitService) GetStatus(ctx context.Context, repoPath string) ([]string, error) {
+func (m *MockGitService) StashChanges(ctx context.Context, repoPath, message string) (string, error) {
+	if m.StashChangesFunc != nil {
+		return m.StashChangesFunc(ctx, repoPath, message)
+	}
+	return "", nil
+}
+
+func (m *MockGitService) RestoreStash(ctx context.Context, repoPath, stash string) error {
+	if m.RestoreStashFunc != nil {
+		return m.RestoreStashFunc(ctx, repoPath, stash)
+	}
+	return nil
+}
+
+func (m *MockGitService) GetStatus(ctx context.Context, repoPath string) ([]FileStatus, error) {
 	if m.GetStatusFunc != nil {
 		return m.GetStatusFunc(ctx, repoPath)
 	}
-	return []string{}, nil
+	return []FileStatus{}, nil
 }
 
 func (m *MockGitService) HasUncommittedChanges(ctx context.Context, repoPath string) (bool, error) {
@@ -0,0 +1,60 @@
+package git
+
+import (
+	"net/url"
+	"strings"
+)
+
+// RepoWebURL converts a remote URL such as git@github.com:owner/repo.git or
+// https://gitlab.com/owner/repo.git to the repository's web URL. It returns "" for
+// remotes that don't look like a hosted repository, such as local paths.
+func RepoWebURL(remoteURL string) string {
+	remoteURL = strings.TrimSpace(remoteURL)
+
+	var host, path string
+	if u, err := url.Parse(remoteURL); err == nil && u.Host != "" {
+		switch u.Scheme {
+		case "https", "http", "ssh", "git":
+		default:
+			return ""
+		}
+		host, path = u.Hostname(), u.Path
+	} else if at := strings.Index(remoteURL, "@"); at >= 0 {
+		// scp-like syntax: user@host:owner/repo.git
+		rest := remoteURL[at+1:]
+		colon := strings.Index(rest, ":")
+		if colon < 0 {
+			return ""
+		}
+		host, path = rest[:colon], rest[colon+1:]
+	} else {
+		return ""
+	}
+
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	if host == "" || !strings.Contains(path, "/") {
+		return ""
+	}
+	return "https://" + host + "/" + path
+}
+
+// StripCredentials removes any username and password embedded in an http(s) remote URL
+// so it can be displayed.
+func StripCredentials(remoteURL string) string {
+	u, err := url.Parse(remoteURL)
+	if err != nil || u.User == nil || (u.Scheme != "https" && u.Scheme != "http") {
+		return remoteURL
+	}
+	u.User = nil
+	return u.String()
+}
+
+// BranchWebURL returns the web URL of branch on the repository behind remoteURL, or ""
+// if the remote isn't a hosted repository.
+func BranchWebURL(remoteURL, branch string) string {
+	repo := RepoWebURL(remoteURL)
+	if repo == "" {
+		return ""
+	}
+	return repo + "/tree/" + branch
+}
@@ -2,16 +2,30 @@ package git
 
 import (
 	"context"
+	"fmt"
+	"io"
+	"strings"
 	"time"
 )
 
-// Branch represents a git branch
+// Branch represents a git branch. UpdatedAt and Author are the committer date and the
+// author of the commit it points at.
 type Branch struct {
 	Name      string
 	IsCurrent bool
 	IsRemote  bool
 	Hash      string
 	UpdatedAt time.Time
+	Author    string
+}
+
+// Tag is a git tag
+type Tag struct {
+	Name string
+	// Hash is the commit the tag points at
+	Hash string
+	// Message is the subject of an annotated tag's message; lightweight tags have none
+	Message string
 }
 
 // Worktree represents a git worktree
@@ -21,6 +35,8 @@ type Worktree struct {
 	Hash       string
 	IsDetached bool
 	IsLocked   bool
+	// LockReason is why a locked worktree was locked, if a reason was given
+	LockReason string
 }
 
 // DiffStats represents statistics about git diff
@@ -38,6 +54,78 @@ type FileDiff struct {
 	Deletions  int
 	Binary     bool
 	Status     string // "modified", "added", "deleted", "renamed"
+	// LFS files are stored with Git LFS. Only their pointers are diffed, so their line
+	// counts are left at zero.
+	LFS bool
+}
+
+// FileStatus is the state of one changed file in a worktree, as git status reports it
+type FileStatus struct {
+	Path string
+	// OrigPath is the path a renamed or copied file was at, otherwise empty
+	OrigPath string
+	// Staged and Unstaged are git status's codes for the change in the index and in the
+	// worktree: 'M' modified, 'A' added, 'D' deleted, 'R' renamed, 'C' copied, 'T' type
+	// changed, 'U' unmerged, '?' untracked or ' ' unchanged
+	Staged   byte
+	Unstaged byte
+}
+
+// IsUntracked reports whether the file isn't known to git at all
+func (f FileStatus) IsUntracked() bool {
+	return f.Staged == '?'
+}
+
+// IsStaged reports whether the file has changes in the index
+func (f FileStatus) IsStaged() bool {
+	return f.Staged != ' ' && f.Staged != '?' && !f.IsConflicted()
+}
+
+// IsUnstaged reports whether the file has changes in the worktree that aren't staged
+func (f FileStatus) IsUnstaged() bool {
+	return f.Unstaged != ' ' && f.Unstaged != '?' && !f.IsConflicted()
+}
+
+// IsRenamed reports whether the file was renamed from OrigPath
+func (f FileStatus) IsRenamed() bool {
+	return f.Staged == 'R' || f.Unstaged == 'R'
+}
+
+// IsConflicted reports whether the file is unmerged, with conflicts left to resolve
+func (f FileStatus) IsConflicted() bool {
+	return f.Staged == 'U' || f.Unstaged == 'U' ||
+		(f.Staged == 'A' && f.Unstaged == 'A') || (f.Staged == 'D' && f.Unstaged == 'D')
+}
+
+// Submodule is a submodule of a worktree, as git submodule status reports it
+type Submodule struct {
+	// Path is where the submodule is checked out, relative to the top of the worktree
+	Path string
+	// Hash is the commit checked out, or the one the superproject records when the
+	// submodule isn't initialized
+	Hash string
+	// Describe names Hash after a tag or branch, e.g. "v1.2.0" or "heads/main", when git
+	// can
+	Describe string
+	// State is git submodule status's code: ' ' at the recorded commit, '-' not
+	// initialized, '+' at another commit or 'U' with merge conflicts
+	State byte
+}
+
+// IsInitialized reports whether the submodule has been cloned and checked out
+func (s Submodule) IsInitialized() bool {
+	return s.State != '-'
+}
+
+// IsModified reports whether the submodule has a different commit checked out than the
+// one the superproject records
+func (s Submodule) IsModified() bool {
+	return s.State == '+'
+}
+
+// IsConflicted reports whether the commit recorded for the submodule is unmerged
+func (s Submodule) IsConflicted() bool {
+	return s.State == 'U'
 }
 
 // CommitInfo represents git commit information
@@ -49,6 +137,96 @@ type CommitInfo struct {
 	Timestamp time.Time
 }
 
+// BlameLine is one line of a file with the commit that last changed it
+type BlameLine struct {
+	// Line is the line's number in the file, from 1
+	Line int
+	// Hash is the commit; it is all zeros for lines changed in the worktree but not
+	// committed yet
+	Hash      string
+	Author    string
+	Email     string
+	Timestamp time.Time
+	// Summary is the first line of the commit's message
+	Summary string
+	Content string
+}
+
+// IsCommitted reports whether the line is as some commit left it, rather than changed
+// since
+func (b BlameLine) IsCommitted() bool {
+	return strings.Trim(b.Hash, "0") != ""
+}
+
+// CommitOptions controls how CommitWithOptions creates a commit
+type CommitOptions struct {
+	// Message is the commit message; it may be empty when amending to keep the old one
+	Message string
+	// Amend replaces the last commit instead of creating a new one
+	Amend bool
+	// StageAll stages every change, including untracked files, before committing
+	StageAll bool
+	// Paths limits the commit to the changes to these files, staged first; other changes,
+	// staged or not, are left as they are. StageAll is ignored when set.
+	Paths []string
+	// RunHooks runs the pre-commit and commit-msg hooks, which are skipped otherwise
+	RunHooks bool
+	// Sign signs the commit with SigningKey, or git's user.signingkey when empty
+	Sign       bool
+	SigningKey string
+	// SigningFormat is "openpgp", "ssh" or "x509"; git's gpg.format when empty
+	SigningFormat string
+	// Author is who the commit is authored by, as "Name <email>", instead of git's
+	// user.name and user.email
+	Author string
+}
+
+// PushOptions controls how a branch is pushed
+type PushOptions struct {
+	// Remote defaults to "origin"
+	Remote string
+	// Force overwrites the remote branch if it has diverged, unless someone else pushed to it
+	Force bool
+}
+
+// MergeOptions controls how Merge integrates a branch
+type MergeOptions struct {
+	// Message is the message of the merge commit; defaults to git's
+	Message string
+	// FastForwardOnly only moves the branch forward, failing if it has diverged, instead
+	// of always creating a merge commit
+	FastForwardOnly bool
+}
+
+// ConflictError reports a merge or rebase stopped by conflicting changes
+type ConflictError struct {
+	// Operation is "merge", "rebase" or "cherry-pick"
+	Operation string
+	// Files are the paths, relative to the repository, with conflicts
+	Files []string
+	// Output is what git reported
+	Output string
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("%s conflicts in %s", e.Operation, strings.Join(e.Files, ", "))
+}
+
+// FileConflict is a file that can't be merged cleanly
+type FileConflict struct {
+	Path string
+	// Hunks is empty for conflicts over the file as a whole, such as one side deleting it
+	Hunks []ConflictHunk
+}
+
+// ConflictHunk is one conflicting region of a file, with what each side has there
+type ConflictHunk struct {
+	// Line is where the region starts in the merged file, from 1
+	Line   int
+	Ours   string
+	Theirs string
+}
+
 // GitService provides git repository operations
 type GitService interface {
 	// Repository operations
@@ -58,33 +236,117 @@ type GitService interface {
 	// Branch operations
 	ListBranches(ctx context.Context, repoPath string) ([]Branch, error)
 	CreateBranch(ctx context.Context, repoPath, branchName string) error
+	CreateBranchFrom(ctx context.Context, repoPath, branchName, startPoint string) error
+	RenameBranch(ctx context.Context, repoPath, oldName, newName string) error
+	BranchExists(ctx context.Context, repoPath, branchName string) (bool, error)
+
+	// Bundles
+	CreateBundle(ctx context.Context, repoPath, bundlePath, branchName string) error
+	FetchBundle(ctx context.Context, repoPath, bundlePath, branchName string) error
 	DeleteBranch(ctx context.Context, repoPath, branchName string, force bool) error
 	CheckoutBranch(ctx context.Context, repoPath, branchName string) error
 	GetCurrentBranch(ctx context.Context, repoPath string) (*Branch, error)
 
+	// Tag operations
+	// CreateTag tags ref, annotated with message or lightweight when message is empty.
+	// An existing tag of the same name is an error.
+	CreateTag(ctx context.Context, repoPath, name, ref, message string) error
+	// ListTags lists the tags matching a glob pattern like "squad/*", or every tag when
+	// pattern is empty, sorted by name
+	ListTags(ctx context.Context, repoPath, pattern string) ([]Tag, error)
+	DeleteTag(ctx context.Context, repoPath, name string) error
+
 	// Worktree operations
 	CreateWorktree(ctx context.Context, repoPath, worktreePath, branch string) (*Worktree, error)
 	ListWorktrees(ctx context.Context, repoPath string) ([]*Worktree, error)
 	RemoveWorktree(ctx context.Context, worktreePath string, force bool) error
 	GetWorktreeInfo(ctx context.Context, worktreePath string) (*Worktree, error)
+	MoveWorktree(ctx context.Context, repoPath, worktreePath, newPath string) error
+	// GetBranchWorktree returns the worktree of the repository, the main one included,
+	// that has branch checked out, or nil when none has
+	GetBranchWorktree(ctx context.Context, repoPath, branch string) (*Worktree, error)
+	// LockWorktree keeps git from pruning or removing a worktree, unless forced twice,
+	// until it is unlocked. Locking a locked worktree keeps its original reason.
+	LockWorktree(ctx context.Context, worktreePath, reason string) error
+	UnlockWorktree(ctx context.Context, worktreePath string) error
+
+	// Submodule operations
+	// UpdateSubmodules initializes a worktree's submodules, recursively, and checks out
+	// the commits the worktree records for them. Worktrees start without any.
+	UpdateSubmodules(ctx context.Context, worktreePath string) error
+	GetSubmoduleStatus(ctx context.Context, repoPath string) ([]Submodule, error)
+
+	// LFS operations
+	// UsesLFS reports whether any of the worktree's .gitattributes files store files with
+	// Git LFS
+	UsesLFS(ctx context.Context, repoPath string) (bool, error)
+	// SetupLFS installs Git LFS's hooks and filters in the repository of the worktree and
+	// replaces its pointer files with the content already fetched
+	SetupLFS(ctx context.Context, worktreePath string) error
 
 	// Diff operations
+	GetDiff(ctx context.Context, repoPath string) (string, error)
+	GetDiffStaged(ctx context.Context, repoPath string) (string, error)
+	GetDiffBetween(ctx context.Context, repoPath, from, to string) (string, error)
 	GetDiffStats(ctx context.Context, repoPath string) (*DiffStats, error)
 	GetDiffStatsStaged(ctx context.Context, repoPath string) (*DiffStats, error)
 	GetDiffStatsBetweenBranches(ctx context.Context, repoPath, fromBranch, toBranch string) (*DiffStats, error)
+	GetDiffPatch(ctx context.Context, repoPath string) (string, error)
+	GetChangedFiles(ctx context.Context, repoPath string) ([]FileDiff, error)
 
 	// Commit operations
 	Commit(ctx context.Context, repoPath, message string) error
+	CommitWithOptions(ctx context.Context, repoPath string, opts CommitOptions) (*CommitInfo, error)
+	CommitPaths(ctx context.Context, repoPath, message string, paths []string) (*CommitInfo, error)
+	StageFiles(ctx context.Context, repoPath string, paths []string) error
+	UnstageFiles(ctx context.Context, repoPath string, paths []string) error
 	GetLastCommit(ctx context.Context, repoPath string) (*CommitInfo, error)
 	GetCommitHistory(ctx context.Context, repoPath string, limit int) ([]*CommitInfo, error)
+	GetCommitsBetween(ctx context.Context, repoPath, base, head string) ([]*CommitInfo, error)
+	GetAheadBehind(ctx context.Context, repoPath, branch, base string) (ahead, behind int, err error)
+
+	// History operations
+	// GetFileHistory lists up to limit commits that changed the file at path, relative to
+	// repoPath, newest first, following it across renames. A limit of 0 lists them all.
+	GetFileHistory(ctx context.Context, repoPath, path string, limit int) ([]*CommitInfo, error)
+	// Blame attributes each line of the file at path, as it is in the worktree, to the
+	// commit that last changed it
+	Blame(ctx context.Context, repoPath, path string) ([]BlameLine, error)
+
+	// Integration operations, on the branch checked out at repoPath
+	Merge(ctx context.Context, repoPath, branch string, opts MergeOptions) error
+	SquashMerge(ctx context.Context, repoPath, branch, message string) error
+	Rebase(ctx context.Context, repoPath, onto string) error
+	RebaseContinue(ctx context.Context, repoPath string) error
+	RebaseAbort(ctx context.Context, repoPath string) error
+	GetConflicts(ctx context.Context, repoPath, base, branch string) ([]FileConflict, error)
+	// CherryPick commits the changes of commit, or of each commit in a range like
+	// "main..feature", onto the branch checked out at repoPath. One that conflicts is
+	// aborted, leaving the branch as it was, and reported as a *ConflictError.
+	CherryPick(ctx context.Context, repoPath, commit string) error
+	// ApplyPatch applies a unified diff, like GetDiffPatch's, to the worktree at repoPath
+	// without staging it. A patch that doesn't apply cleanly changes nothing.
+	ApplyPatch(ctx context.Context, repoPath string, patch io.Reader) error
+
+	// Remote operations
+	Fetch(ctx context.Context, repoPath, remote string) error
+	Push(ctx context.Context, repoPath, branch string, opts PushOptions) error
+	GetRemoteURL(ctx context.Context, repoPath, remote string) (string, error)
+	GetDefaultBranch(ctx context.Context, repoPath, remote string) (string, error)
 
 	// Stash operations
 	Stash(ctx context.Context, repoPath, message string) error
 	PopStash(ctx context.Context, repoPath string) error
 	ListStashes(ctx context.Context, repoPath string) ([]string, error)
+	// StashChanges stashes the changes at repoPath, untracked files included, and returns
+	// the stash's commit, or "" when there was nothing to stash
+	StashChanges(ctx context.Context, repoPath, message string) (string, error)
+	// RestoreStash applies the stash StashChanges returned at repoPath and drops it. A stash
+	// that doesn't apply cleanly is kept.
+	RestoreStash(ctx context.Context, repoPath, stash string) error
 
 	// Status operations
-	GetStatus(ctx context.Context, repoPath string) ([]string, error)
+	GetStatus(ctx context.Context, repoPath string) ([]FileStatus, error)
 	HasUncommittedChanges(ctx context.Context, repoPath string) (bool, error)
 
 	// Cleanup operations
@@ -0,0 +1,127 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"claude-squad/services/types"
+)
+
+// BranchInUseError reports a branch that another session, or a worktree outside any
+// session, already works on. Agents sharing a branch overwrite each other's work.
+type BranchInUseError struct {
+	Branch string
+	// Sessions are the titles of the sessions on the branch
+	Sessions []string
+	// Worktree is where the branch is checked out outside of any session, if it is
+	Worktree string
+}
+
+func (e *BranchInUseError) Error() string {
+	if len(e.Sessions) > 0 {
+		return fmt.Sprintf("branch %s is already used by session '%s'", e.Branch, strings.Join(e.Sessions, "', '"))
+	}
+	return fmt.Sprintf("branch %s is already checked out at %s", e.Branch, e.Worktree)
+}
+
+// checkBranchFree returns a *BranchInUseError when a session other than except works on
+// branch in the repository at repoPath, or the branch is checked out in a worktree other
+// than except's. Paused sessions count unless only running ones are asked for, since
+// they come back to their branch when resumed.
+func (o *orchestratorImpl) checkBranchFree(ctx context.Context, repoPath, branch string, except *types.Session, runningOnly bool) error {
+	data, err := o.storage.List(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	inUse := &BranchInUseError{Branch: branch}
+	paths := make(map[string]bool)
+	for _, session := range liveSessions(data) {
+		paths[session.Path] = true
+		if except != nil && session.ID == except.ID {
+			continue
+		}
+		if session.Branch != branch || repoPathOf(session) != repoPath {
+			continue
+		}
+		if runningOnly && session.Status == types.StatusPaused {
+			continue
+		}
+		inUse.Sessions = append(inUse.Sessions, session.Title)
+	}
+	if len(inUse.Sessions) > 0 {
+		return inUse
+	}
+
+	// Worktrees of sessions were accounted for above, and an in-place session works in
+	// the repository's own checkout
+	worktree, err := o.gitService.GetBranchWorktree(ctx, repoPath, branch)
+	if err != nil || worktree == nil || paths[worktree.Path] {
+		return nil
+	}
+	if except != nil && except.InPlace != nil && worktree.Path == repoPath {
+		return nil
+	}
+	inUse.Worktree = worktree.Path
+	return inUse
+}
+
+// forkBranch creates a branch starting where branch is, named after it with the first
+// free "-2", "-3", ... suffix, for a session to work on instead of sharing branch
+func (o *orchestratorImpl) forkBranch(ctx context.Context, repoPath, branch string) (string, error) {
+	for n := 2; n < 100; n++ {
+		fork := fmt.Sprintf("%s-%d", branch, n)
+		exists, err := o.gitService.BranchExists(ctx, repoPath, fork)
+		if err != nil {
+			return "", fmt.Errorf("failed to check branch: %w", err)
+		}
+		if exists {
+			continue
+		}
+		if err := o.gitService.CreateBranchFrom(ctx, repoPath, fork, branch); err != nil {
+			return "", fmt.Errorf("failed to fork branch %s: %w", branch, err)
+		}
+		return fork, nil
+	}
+	return "", fmt.Errorf("failed to fork branch %s: no free name", branch)
+}
+
+// FindOverlaps lists the groups of sessions that work on the same branch of a repository,
+// or in the same worktree
+func (o *orchestratorImpl) FindOverlaps(ctx context.Context) ([]types.SessionOverlap, error) {
+	sessions, err := o.ListSessions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	type key struct{ repo, branch string }
+	byBranch := make(map[key][]string)
+	byPath := make(map[string][]string)
+	for _, session := range sessions {
+		k := key{repoPathOf(session), session.Branch}
+		byBranch[k] = append(byBranch[k], session.ID)
+		byPath[session.Path] = append(byPath[session.Path], session.ID)
+	}
+
+	var overlaps []types.SessionOverlap
+	for k, ids := range byBranch {
+		if len(ids) > 1 {
+			overlaps = append(overlaps, types.SessionOverlap{RepoPath: k.repo, Branch: k.branch, SessionIDs: ids})
+		}
+	}
+	for path, ids := range byPath {
+		if len(ids) > 1 {
+			overlaps = append(overlaps, types.SessionOverlap{Path: path, SessionIDs: ids})
+		}
+	}
+	sort.Slice(overlaps, func(i, j int) bool {
+		a, b := overlaps[i], overlaps[j]
+		if a.RepoPath+a.Branch != b.RepoPath+b.Branch {
+			return a.RepoPath+a.Branch < b.RepoPath+b.Branch
+		}
+		return a.Path < b.Path
+	})
+	return overlaps, nil
+}
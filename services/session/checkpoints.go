@@ -0,0 +1,80 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"claude-squad/services/git"
+	"claude-squad/services/types"
+)
+
+// CheckpointPrefix starts the message of every checkpoint commit, which is followed by
+// when it was made
+const CheckpointPrefix = "checkpoint: "
+
+// WithCheckpointInterval makes RunCheckpoints commit the changes in every running
+// session's worktree this often, so each session's branch records the agent's progress
+// and any point of it can be gone back to. 0, the default, makes no checkpoints.
+func WithCheckpointInterval(interval time.Duration) OrchestratorOption {
+	return func(o *orchestratorImpl) {
+		o.checkpointInterval = interval
+	}
+}
+
+// Checkpoint commits all the changes in a session's worktree as a checkpoint. It returns
+// nil when there is nothing to commit.
+func (o *orchestratorImpl) Checkpoint(ctx context.Context, sessionID string) (*git.CommitInfo, error) {
+	session, err := o.GetSession(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session.Status == types.StatusPaused {
+		return nil, fmt.Errorf("session is paused")
+	}
+
+	if dirty, err := o.gitService.HasUncommittedChanges(ctx, session.Path); err != nil || !dirty {
+		return nil, err
+	}
+	return o.gitService.CommitWithOptions(ctx, session.Path, o.commitOptions(session, git.CommitOptions{
+		Message:  CheckpointPrefix + time.Now().Format(time.RFC3339),
+		StageAll: true,
+	}))
+}
+
+// RunCheckpoints checkpoints every session that isn't paused at the configured interval
+// until ctx is done. It returns at once when checkpoints are off.
+func (o *orchestratorImpl) RunCheckpoints(ctx context.Context) {
+	if o.checkpointInterval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(o.checkpointInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		o.checkpointAll(ctx)
+	}
+}
+
+// checkpointAll checkpoints every session that isn't paused. A session that fails is
+// warned about and tried again next time.
+func (o *orchestratorImpl) checkpointAll(ctx context.Context) {
+	sessions, err := o.ListSessions(ctx)
+	if err != nil {
+		fmt.Printf("warning: failed to list sessions to checkpoint: %v\n", err)
+		return
+	}
+	for _, session := range sessions {
+		if session.Status == types.StatusPaused {
+			continue
+		}
+		if _, err := o.Checkpoint(ctx, session.ID); err != nil {
+			fmt.Printf("warning: failed to checkpoint session %s: %v\n", session.Title, err)
+		}
+	}
+}
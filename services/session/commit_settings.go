@@ -0,0 +1,96 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"claude-squad/services/git"
+	"claude-squad/services/types"
+)
+
+// authorPattern is the "Name <email>" form git takes an author in. Anything else is a
+// pattern git looks up among existing authors.
+var authorPattern = regexp.MustCompile(`^[^<>]+ <[^<>]+>$`)
+
+// WithCommitSettings makes sessions' commits as settings say, unless a session's own
+// settings say otherwise
+func WithCommitSettings(settings types.CommitSettings) OrchestratorOption {
+	return func(o *orchestratorImpl) {
+		o.commitSettings = settings
+	}
+}
+
+func (o *orchestratorImpl) SetCommitSettings(ctx context.Context, sessionID string, settings *types.CommitSettings) error {
+	if settings != nil {
+		if err := validateCommitSettings(settings); err != nil {
+			return err
+		}
+	}
+	session, err := o.GetSession(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+
+	o.mu.Lock()
+	session.CommitSettings = settings
+	o.mu.Unlock()
+
+	data, err := o.storage.Get(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+	data.CommitSettings = settings
+	return o.storage.Update(ctx, data)
+}
+
+func validateCommitSettings(settings *types.CommitSettings) error {
+	switch settings.SigningFormat {
+	case "", "openpgp", "ssh", "x509":
+	default:
+		return fmt.Errorf("signing format must be openpgp, ssh or x509")
+	}
+	if settings.Author != "" && !authorPattern.MatchString(settings.Author) {
+		return fmt.Errorf("author must look like \"Name <email>\"")
+	}
+	return nil
+}
+
+// commitOptions fills in what opts leaves unset from the session's commit settings, and
+// what those leave unset from the global ones
+func (o *orchestratorImpl) commitOptions(session *types.Session, opts git.CommitOptions) git.CommitOptions {
+	var defaults git.CommitOptions
+	for _, settings := range []*types.CommitSettings{&o.commitSettings, session.CommitSettings} {
+		if settings == nil {
+			continue
+		}
+		if settings.RunHooks != nil {
+			defaults.RunHooks = *settings.RunHooks
+		}
+		if settings.Sign != nil {
+			defaults.Sign = *settings.Sign
+		}
+		if settings.SigningKey != "" {
+			defaults.SigningKey = settings.SigningKey
+		}
+		if settings.SigningFormat != "" {
+			defaults.SigningFormat = settings.SigningFormat
+		}
+		if settings.Author != "" {
+			defaults.Author = settings.Author
+		}
+	}
+
+	opts.RunHooks = opts.RunHooks || defaults.RunHooks
+	opts.Sign = opts.Sign || defaults.Sign
+	if opts.SigningKey == "" {
+		opts.SigningKey = defaults.SigningKey
+	}
+	if opts.SigningFormat == "" {
+		opts.SigningFormat = defaults.SigningFormat
+	}
+	if opts.Author == "" {
+		opts.Author = defaults.Author
+	}
+	return opts
+}
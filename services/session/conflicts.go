@@ -0,0 +1,54 @@
+package session
+
+import (
+	"context"
+	"fmt"
+
+	"claude-squad/services/git"
+	"claude-squad/services/types"
+)
+
+func (o *orchestratorImpl) CheckConflicts(ctx context.Context, sessionID, base string) ([]git.FileConflict, error) {
+	session, err := o.GetSession(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	repoPath := repoPathOf(session)
+	if base == "" {
+		current, err := o.gitService.GetCurrentBranch(ctx, repoPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get current branch: %w", err)
+		}
+		base = current.Name
+	}
+	if base == session.Branch {
+		return nil, fmt.Errorf("session works on %s itself, there is nothing to merge", base)
+	}
+
+	conflicts, err := o.gitService.GetConflicts(ctx, repoPath, base, session.Branch)
+	if err != nil {
+		return nil, err
+	}
+	o.setConflicted(ctx, session, len(conflicts) > 0)
+	return conflicts, nil
+}
+
+// setConflicted records whether the session's branch conflicts with its base branch. Like
+// input, a failure to save it is not reported to the caller.
+func (o *orchestratorImpl) setConflicted(ctx context.Context, session *types.Session, conflicted bool) {
+	o.mu.Lock()
+	changed := session.Conflicted != conflicted
+	session.Conflicted = conflicted
+	o.mu.Unlock()
+	if !changed {
+		return
+	}
+
+	data, err := o.storage.Get(ctx, session.ID)
+	if err != nil {
+		return
+	}
+	data.Conflicted = conflicted
+	_ = o.storage.Update(ctx, data)
+}
@@ -0,0 +1,166 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"claude-squad/services/git"
+	"claude-squad/services/types"
+)
+
+const (
+	// maxDiffHistory is how many diff samples are kept per session
+	maxDiffHistory = 1000
+	// maxDiffPatches is how many of the latest samples keep their patch
+	maxDiffPatches = 50
+)
+
+// WithDiffHistory makes RunDiffHistory sample the size of every running session's
+// uncommitted changes this often, and their patches too when patches is set, so how a
+// session's changes grew can be followed over its lifetime. 0, the default, samples none.
+func WithDiffHistory(interval time.Duration, patches bool) OrchestratorOption {
+	return func(o *orchestratorImpl) {
+		o.diffHistoryInterval = interval
+		o.diffHistoryPatches = patches
+	}
+}
+
+// RecordDiff samples a session's uncommitted changes and appends them to its diff
+// history. Nothing is recorded, and nil returned, when they are the same as in the last
+// sample.
+func (o *orchestratorImpl) RecordDiff(ctx context.Context, sessionID string) (*types.DiffSample, error) {
+	session, err := o.GetSession(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session.Status == types.StatusPaused {
+		return nil, fmt.Errorf("session is paused")
+	}
+
+	stats, err := o.gitService.GetDiffStats(ctx, session.Path)
+	if err != nil {
+		return nil, err
+	}
+	// Like the diffs shown for review, samples leave out the files the repository excludes
+	rules, err := git.LoadDiffRules(session.Path)
+	if err != nil {
+		return nil, err
+	}
+	stats = rules.FilterStats(stats)
+	sample := types.DiffSample{Time: time.Now(), Added: stats.Insertions, Removed: stats.Deletions, Files: stats.FilesChanged}
+	if head, err := o.gitService.GetLastCommit(ctx, session.Path); err == nil {
+		sample.Head = head.Hash
+	}
+	if o.diffHistoryPatches {
+		patch, err := o.gitService.GetDiffPatch(ctx, session.Path)
+		if err != nil {
+			return nil, err
+		}
+		sample.Patch, _ = rules.FilterPatch(patch)
+	}
+
+	o.mu.Lock()
+	unchanged := sameDiff(session.DiffHistory, sample)
+	if !unchanged {
+		session.DiffHistory = appendDiffSample(session.DiffHistory, sample)
+	}
+	o.mu.Unlock()
+	if unchanged {
+		return nil, nil
+	}
+
+	// Append to the stored copy, which another process may have sampled too
+	data, err := o.storage.Get(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("session not found: %s", sessionID)
+	}
+	if !sameDiff(data.DiffHistory, sample) {
+		data.DiffHistory = appendDiffSample(data.DiffHistory, sample)
+		if err := o.storage.Update(ctx, data); err != nil {
+			return nil, fmt.Errorf("failed to save diff history: %w", err)
+		}
+	}
+	return &sample, nil
+}
+
+// sameDiff reports whether sample has the same changes as the last one in history
+func sameDiff(history []types.DiffSample, sample types.DiffSample) bool {
+	if len(history) == 0 {
+		return false
+	}
+	last := history[len(history)-1]
+	last.Time = sample.Time
+	// Patches dropped from older samples can't be compared
+	if last.Patch == "" {
+		last.Patch = sample.Patch
+	}
+	return last == sample
+}
+
+// appendDiffSample adds sample to history, dropping the oldest samples beyond
+// maxDiffHistory and the patches of those beyond maxDiffPatches
+func appendDiffSample(history []types.DiffSample, sample types.DiffSample) []types.DiffSample {
+	history = append(history, sample)
+	if len(history) > maxDiffHistory {
+		history = slices.Clone(history[len(history)-maxDiffHistory:])
+	}
+	if i := len(history) - maxDiffPatches - 1; i >= 0 {
+		history[i].Patch = ""
+	}
+	return history
+}
+
+// GetDiffHistory returns the diff samples of a session taken after since, oldest first;
+// a zero since returns them all
+func (o *orchestratorImpl) GetDiffHistory(ctx context.Context, sessionID string, since time.Time) ([]types.DiffSample, error) {
+	if _, err := o.GetSession(ctx, sessionID); err != nil {
+		return nil, err
+	}
+	data, err := o.storage.Get(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("session not found: %s", sessionID)
+	}
+
+	history := data.DiffHistory
+	i, _ := slices.BinarySearchFunc(history, since, func(sample types.DiffSample, t time.Time) int {
+		if sample.Time.After(t) {
+			return 1
+		}
+		return -1
+	})
+	return history[i:], nil
+}
+
+// RunDiffHistory samples every session that isn't paused at the configured interval until
+// ctx is done. It returns at once when diff history is off.
+func (o *orchestratorImpl) RunDiffHistory(ctx context.Context) {
+	if o.diffHistoryInterval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(o.diffHistoryInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		sessions, err := o.ListSessions(ctx)
+		if err != nil {
+			fmt.Printf("warning: failed to list sessions to sample: %v\n", err)
+			continue
+		}
+		for _, session := range sessions {
+			if session.Status == types.StatusPaused {
+				continue
+			}
+			if _, err := o.RecordDiff(ctx, session.ID); err != nil {
+				fmt.Printf("warning: failed to sample diff of session %s: %v\n", session.Title, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"claude-squad/services/git"
+	"claude-squad/services/types"
+)
+
+func (o *orchestratorImpl) FinishSession(ctx context.Context, sessionID string, req types.FinishSessionRequest) (*types.FinishResult, error) {
+	session, err := o.GetSession(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	switch req.Strategy {
+	case "":
+		req.Strategy = types.MergeCommit
+	case types.MergeCommit, types.MergeSquash, types.MergeRebase:
+	default:
+		return nil, fmt.Errorf("unknown merge strategy %q", req.Strategy)
+	}
+	paused := session.Status == types.StatusPaused
+	// An in-place session works in the repository's own checkout, where the base branch is
+	// checked out to merge into; it can't be switched away under a running agent, and there
+	// is no other worktree to rebase the session's branch in
+	if session.InPlace != nil {
+		if req.Strategy == types.MergeRebase {
+			return nil, fmt.Errorf("session works in place in %s, finish it with a merge or squash instead", session.Path)
+		}
+		if !paused {
+			return nil, fmt.Errorf("session works in place in %s, pause it before finishing it", session.Path)
+		}
+	}
+	// Paused sessions have no worktree for their branch to be rebased in
+	if paused && req.Strategy == types.MergeRebase {
+		return nil, fmt.Errorf("session is paused, resume it to rebase its branch")
+	}
+
+	// The base branch is merged into where it is checked out, in the repository itself
+	repoPath := repoPathOf(session)
+	current, err := o.gitService.GetCurrentBranch(ctx, repoPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current branch: %w", err)
+	}
+	if req.Base == "" {
+		req.Base = current.Name
+	}
+	if req.Base == session.Branch {
+		return nil, fmt.Errorf("session works on %s itself, there is nothing to merge", req.Base)
+	}
+	if dirty, err := o.gitService.HasUncommittedChanges(ctx, repoPath); err != nil {
+		return nil, err
+	} else if dirty {
+		return nil, fmt.Errorf("%s has uncommitted changes, commit or stash them first", repoPath)
+	}
+	if current.Name != req.Base {
+		if err := o.gitService.CheckoutBranch(ctx, repoPath, req.Base); err != nil {
+			return nil, err
+		}
+	}
+
+	if !paused {
+		if dirty, err := o.gitService.HasUncommittedChanges(ctx, session.Path); err != nil {
+			return nil, err
+		} else if dirty {
+			if _, err := o.gitService.CommitWithOptions(ctx, session.Path, o.commitOptions(session, git.CommitOptions{
+				Message:  "Finish " + session.Title,
+				StageAll: true,
+			})); err != nil {
+				return nil, err
+			}
+		}
+	}
+	commits, err := o.gitService.GetCommitsBetween(ctx, repoPath, req.Base, session.Branch)
+	if err != nil {
+		return nil, err
+	}
+	if len(commits) == 0 {
+		return nil, fmt.Errorf("branch %s has no commits ahead of %s", session.Branch, req.Base)
+	}
+	// Tagged before merging, which for a rebase rewrites the branch
+	var tag string
+	if req.Tag {
+		if tag = req.TagName; tag == "" {
+			tag = defaultTagName(session)
+		}
+		if err := o.gitService.CreateTag(ctx, repoPath, tag, session.Branch, "Finish "+session.Title); err != nil {
+			return nil, err
+		}
+	}
+
+	switch req.Strategy {
+	case types.MergeCommit:
+		err = o.gitService.Merge(ctx, repoPath, session.Branch, git.MergeOptions{Message: req.Message})
+	case types.MergeSquash:
+		message := req.Message
+		if message == "" {
+			message = pullRequestTitle(session, commits)
+		}
+		err = o.gitService.SquashMerge(ctx, repoPath, session.Branch, message)
+	case types.MergeRebase:
+		if err = o.gitService.Rebase(ctx, session.Path, req.Base); err == nil {
+			err = o.gitService.Merge(ctx, repoPath, session.Branch, git.MergeOptions{FastForwardOnly: true})
+		} else if abortErr := o.gitService.RebaseAbort(ctx, session.Path); abortErr != nil {
+			fmt.Printf("warning: failed to abort rebase: %v\n", abortErr)
+		}
+	}
+	if err != nil {
+		var conflict *git.ConflictError
+		if errors.As(err, &conflict) {
+			o.setConflicted(ctx, session, true)
+		}
+		// The session isn't finished, so neither is its work
+		if tag != "" {
+			if deleteErr := o.gitService.DeleteTag(ctx, repoPath, tag); deleteErr != nil {
+				fmt.Printf("warning: failed to delete tag %s: %v\n", tag, deleteErr)
+			}
+		}
+		return nil, err
+	}
+
+	result := &types.FinishResult{Branch: session.Branch, Base: req.Base, Strategy: req.Strategy, Tag: tag}
+	if head, err := o.gitService.GetLastCommit(ctx, repoPath); err == nil {
+		result.Head = head.Hash
+	}
+
+	// The session's work is done, so its worktree and tmux session go
+	if err := o.StopSession(ctx, sessionID); err != nil {
+		return nil, err
+	}
+	if req.DeleteBranch {
+		// Squashed commits never become part of the base branch, so git can't tell the
+		// branch was merged
+		if err := o.gitService.DeleteBranch(ctx, repoPath, session.Branch, req.Strategy == types.MergeSquash); err != nil {
+			fmt.Printf("warning: failed to delete branch: %v\n", err)
+		} else {
+			result.BranchDeleted = true
+		}
+	}
+	return result, nil
+}
+
+// defaultTagName names the tag marking a finished session's work
+func defaultTagName(session *types.Session) string {
+	return "squad/" + branchNameFromTitle(session.Title) + "/done"
+}
@@ -0,0 +1,92 @@
+package session
+
+import (
+	"context"
+	"fmt"
+
+	"claude-squad/services/types"
+)
+
+// checkInPlaceFree returns an error when another session already works in place in the
+// checkout at repoRoot, where a second one would switch branches under the first
+func (o *orchestratorImpl) checkInPlaceFree(ctx context.Context, repoRoot string) error {
+	data, err := o.storage.List(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to list sessions: %w", err)
+	}
+	for _, session := range liveSessions(data) {
+		if session.InPlace != nil && repoPathOf(session) == repoRoot {
+			return fmt.Errorf("session '%s' already works in %s", session.Title, repoRoot)
+		}
+	}
+	return nil
+}
+
+// enterInPlace readies the directory at dir for an in-place session on branch: its
+// uncommitted changes are stashed if stash is set, and branch is checked out. It returns
+// what to undo once the session is deleted.
+func (o *orchestratorImpl) enterInPlace(ctx context.Context, dir, branch, title string, stash bool) (*types.InPlace, error) {
+	current, err := o.gitService.GetCurrentBranch(ctx, dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current branch: %w", err)
+	}
+
+	inPlace := &types.InPlace{}
+	if stash {
+		if inPlace.Stash, err = o.gitService.StashChanges(ctx, dir, "claude-squad: set aside for session "+title); err != nil {
+			return nil, err
+		}
+	}
+	if branch != current.Name {
+		if err := o.gitService.CheckoutBranch(ctx, dir, branch); err != nil {
+			if _, restoreErr := o.leaveInPlace(ctx, dir, *inPlace); restoreErr != nil {
+				fmt.Printf("warning: %v\n", restoreErr)
+			}
+			return nil, err
+		}
+		inPlace.RestoreBranch = current.Name
+	}
+	return inPlace, nil
+}
+
+// leaveInPlace gives an in-place session's directory back as enterInPlace found it,
+// checking out the branch it was on and restoring its stashed changes. Nothing is done
+// while the session's own changes are still uncommitted there. It returns what is left to
+// undo.
+func (o *orchestratorImpl) leaveInPlace(ctx context.Context, dir string, inPlace types.InPlace) (types.InPlace, error) {
+	if inPlace == (types.InPlace{}) {
+		return inPlace, nil
+	}
+	if dirty, err := o.gitService.HasUncommittedChanges(ctx, dir); err != nil || dirty {
+		if err == nil {
+			err = fmt.Errorf("the session left uncommitted changes")
+		}
+		return inPlace, fmt.Errorf("failed to restore %s: %w; %s", dir, err, restoreHint(inPlace))
+	}
+
+	if inPlace.RestoreBranch != "" {
+		if err := o.gitService.CheckoutBranch(ctx, dir, inPlace.RestoreBranch); err != nil {
+			return inPlace, fmt.Errorf("failed to restore %s: %w; %s", dir, err, restoreHint(inPlace))
+		}
+		inPlace.RestoreBranch = ""
+	}
+	if inPlace.Stash != "" {
+		if err := o.gitService.RestoreStash(ctx, dir, inPlace.Stash); err != nil {
+			return inPlace, fmt.Errorf("failed to restore %s: %w; %s", dir, err, restoreHint(inPlace))
+		}
+		inPlace.Stash = ""
+	}
+	return inPlace, nil
+}
+
+// restoreHint tells how to restore what leaveInPlace couldn't by hand
+func restoreHint(inPlace types.InPlace) string {
+	switch {
+	case inPlace.RestoreBranch != "" && inPlace.Stash != "":
+		return fmt.Sprintf("check out %s and apply stash %s yourself", inPlace.RestoreBranch, inPlace.Stash)
+	case inPlace.RestoreBranch != "":
+		return fmt.Sprintf("check out %s yourself", inPlace.RestoreBranch)
+	default:
+		return fmt.Sprintf("apply stash %s yourself", inPlace.Stash)
+	}
+}
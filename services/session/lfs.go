@@ -0,0 +1,19 @@
+package session
+
+import (
+	"context"
+	"fmt"
+)
+
+// setupLFS sets Git LFS up in the worktree at worktreePath when its repository uses it,
+// so that the agent finds the content of LFS files instead of their pointers. Failing to
+// only warns, like updateSubmodules.
+func (o *orchestratorImpl) setupLFS(ctx context.Context, worktreePath string) {
+	uses, err := o.gitService.UsesLFS(ctx, worktreePath)
+	if err != nil || !uses {
+		return
+	}
+	if err := o.gitService.SetupLFS(ctx, worktreePath); err != nil {
+		fmt.Printf("warning: %v\n", err)
+	}
+}
@@ -1,8 +1,14 @@
 package session
 
 import (
+	"claude-squad/services/executor"
+	"claude-squad/services/forge"
+	"claude-squad/services/git"
+	"claude-squad/services/storage"
 	"claude-squad/services/types"
 	"context"
+	"io"
+	"time"
 )
 
 // SessionOrchestrator coordinates session lifecycle operations
@@ -10,6 +16,20 @@ type SessionOrchestrator interface {
 	// CreateSession creates a new session with the given parameters
 	CreateSession(ctx context.Context, req types.CreateSessionRequest) (*types.Session, error)
 
+	// CloneSession creates a new session with the program and prompt of an existing one
+	CloneSession(ctx context.Context, req types.CloneSessionRequest) (*types.Session, error)
+
+	// RenameSession renames a session together with its tmux session and, optionally, its branch
+	RenameSession(ctx context.Context, sessionID, newTitle string, renameBranch bool) (*types.Session, error)
+
+	// ExportSessions writes the given sessions, and optionally git bundles of their
+	// branches, to w as a tar.gz archive
+	ExportSessions(ctx context.Context, sessionIDs []string, w io.Writer, includeBundles bool) error
+
+	// ImportSessions recreates the sessions in an export archive in repoPath, or in each
+	// session's original repository when repoPath is empty
+	ImportSessions(ctx context.Context, r io.Reader, repoPath string) ([]*types.Session, error)
+
 	// StartSession starts an existing session
 	StartSession(ctx context.Context, sessionID string) error
 
@@ -19,24 +39,147 @@ type SessionOrchestrator interface {
 	// ResumeSession resumes a paused session
 	ResumeSession(ctx context.Context, sessionID string) error
 
-	// StopSession stops and cleans up a session
+	// ArchiveSession snapshots a session's uncommitted diff, removes its worktree and tmux
+	// session and hides it from the default list. The branch, prompt and metadata are kept.
+	ArchiveSession(ctx context.Context, sessionID string) error
+
+	// UnarchiveSession returns an archived session to the paused state so it can be resumed
+	UnarchiveSession(ctx context.Context, sessionID string) error
+
+	// PruneSessions stops and deletes sessions matching a retention policy
+	PruneSessions(ctx context.Context, req types.PruneRequest) ([]*types.Session, error)
+
+	// EnforceRetention archives, trashes and deletes sessions as the policy requires
+	EnforceRetention(ctx context.Context, policy types.RetentionPolicy) (*types.RetentionReport, error)
+
+	// MigrateWorktrees moves paused sessions' worktrees to where the worktree layout puts
+	// them. With dryRun it only reports the moves.
+	MigrateWorktrees(ctx context.Context, dryRun bool) ([]types.WorktreeMove, error)
+
+	// FindOverlaps lists the groups of sessions that share a branch or a worktree
+	FindOverlaps(ctx context.Context) ([]types.SessionOverlap, error)
+
+	// StopSession stops and cleans up a session, moving its record to the trash
 	StopSession(ctx context.Context, sessionID string) error
 
+	// ListTrash lists the deleted sessions that can still be restored, most recent first
+	ListTrash(ctx context.Context) ([]*types.Session, error)
+
+	// RestoreSession takes a session out of the trash, paused, with the uncommitted changes
+	// it was deleted with
+	RestoreSession(ctx context.Context, sessionID string) error
+
+	// PurgeTrash permanently deletes sessions deleted longer ago than olderThan, or every
+	// session in the trash when it is zero
+	PurgeTrash(ctx context.Context, olderThan time.Duration) ([]*types.Session, error)
+
 	// GetSession retrieves session information
 	GetSession(ctx context.Context, sessionID string) (*types.Session, error)
 
 	// ListSessions lists all available sessions
 	ListSessions(ctx context.Context) ([]*types.Session, error)
 
+	// SearchSessions lists the sessions whose title, prompts or metadata contain every
+	// word of query
+	SearchSessions(ctx context.Context, query string) ([]*types.Session, error)
+
 	// AttachSession attaches to a running session
 	AttachSession(ctx context.Context, sessionID string) error
 
 	// SendInput sends input to a session
 	SendInput(ctx context.Context, sessionID string, input string) error
 
+	// SendPrompt types a prompt into a session and submits it
+	SendPrompt(ctx context.Context, sessionID string, prompt string) error
+
+	// GetInputHistory returns the prompts and keystrokes sent to a session, oldest first
+	GetInputHistory(ctx context.Context, sessionID string) ([]types.InputRecord, error)
+
 	// GetOutput retrieves recent output from a session
 	GetOutput(ctx context.Context, sessionID string) (string, error)
 
+	// GetOutputHistory retrieves the full scrollback of a session
+	GetOutputHistory(ctx context.Context, sessionID string) (string, error)
+
+	// GetAuditLog returns the recorded changes to the sessions with the given ID or title,
+	// oldest first, including sessions since deleted
+	GetAuditLog(ctx context.Context, ref string) ([]storage.AuditEntry, error)
+
+	// CommitSession commits the changes in a session's worktree, running hooks, signing
+	// and setting the author as the session's commit settings say unless opts does
+	CommitSession(ctx context.Context, sessionID string, opts git.CommitOptions) (*git.CommitInfo, error)
+
+	// SetCommitSettings replaces how a session's commits are made; nil goes back to the
+	// global settings
+	SetCommitSettings(ctx context.Context, sessionID string, settings *types.CommitSettings) error
+
+	// Checkpoint commits all the changes in a session's worktree with a "checkpoint:
+	// <time>" message, returning nil when there are none
+	Checkpoint(ctx context.Context, sessionID string) (*git.CommitInfo, error)
+
+	// RunCheckpoints checkpoints the sessions that aren't paused periodically, as
+	// WithCheckpointInterval configures, until ctx is done
+	RunCheckpoints(ctx context.Context)
+
+	// RecordDiff appends the size, and patch if configured, of a session's uncommitted
+	// changes to its diff history, returning nil when they haven't changed since the last
+	// sample
+	RecordDiff(ctx context.Context, sessionID string) (*types.DiffSample, error)
+
+	// GetDiffHistory returns the diff samples of a session taken after since, oldest first
+	GetDiffHistory(ctx context.Context, sessionID string, since time.Time) ([]types.DiffSample, error)
+
+	// RunDiffHistory samples the sessions that aren't paused periodically, as
+	// WithDiffHistory configures, until ctx is done
+	RunDiffHistory(ctx context.Context)
+
+	// FinishSession commits a session's pending changes, integrates its branch into the
+	// base branch in the repository and moves the session to the trash, optionally
+	// deleting its branch. Nothing is changed when the merge conflicts.
+	FinishSession(ctx context.Context, sessionID string, req types.FinishSessionRequest) (*types.FinishResult, error)
+
+	// TransplantSession cherry-picks a session's commits, or applies its uncommitted
+	// changes, onto another session's branch or the one checked out in the repository
+	TransplantSession(ctx context.Context, sessionID string, req types.TransplantRequest) (*types.TransplantResult, error)
+
+	// RebaseSession rebases a session's branch in its worktree, by default onto the latest
+	// default branch of its remote, or continues or aborts a rebase stopped by conflicts.
+	// Conflicts are reported in the result rather than as an error.
+	RebaseSession(ctx context.Context, sessionID string, req types.RebaseRequest) (*types.RebaseResult, error)
+
+	// CheckConflicts lists the files that conflict when merging a session's committed work
+	// into base, which defaults to the branch checked out in the repository, and records
+	// whether there are any on the session
+	CheckConflicts(ctx context.Context, sessionID, base string) ([]git.FileConflict, error)
+
+	// GetTracking counts the commits a session's branch is ahead and behind base, which
+	// defaults to the default branch of the repository's origin remote
+	GetTracking(ctx context.Context, sessionID, base string) (*types.BranchTracking, error)
+
+	// PushSession pushes a session's branch to a remote
+	PushSession(ctx context.Context, sessionID string, opts git.PushOptions) (*types.PushResult, error)
+
+	// CreatePullRequest pushes a session's branch and opens a pull request for it on the forge
+	CreatePullRequest(ctx context.Context, sessionID string, req types.PullRequestRequest) (*types.PullRequest, error)
+
+	// GetPullRequest returns the latest pull request from a session's branch, or
+	// forge.ErrNoPullRequest
+	GetPullRequest(ctx context.Context, sessionID string) (*forge.PullRequest, error)
+
+	// ListChecks lists the checks run on the pull request from a session's branch
+	ListChecks(ctx context.Context, sessionID string) ([]forge.Check, error)
+
+	// ExecInWorktree runs cmd in the session's worktree and waits for it to exit
+	ExecInWorktree(ctx context.Context, sessionID string, cmd executor.Command) (*executor.Result, error)
+
+	// GetCommandHistory returns the commands run in a session's worktree, oldest first
+	GetCommandHistory(ctx context.Context, sessionID string) ([]types.CommandRecord, error)
+
+	// Replay runs the last n commands run in the session's worktree again, oldest first,
+	// e.g. to set up its environment again after it is resumed. Their output goes to
+	// stdout and stderr. It stops at the first command that fails.
+	Replay(ctx context.Context, sessionID string, n int, stdout, stderr io.Writer) error
+
 	// UpdateSessionStatus updates the status of a session
 	UpdateSessionStatus(ctx context.Context, sessionID string, status types.Status) error
 }
\ No newline at end of file
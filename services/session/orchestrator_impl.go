@@ -2,11 +2,19 @@ package session
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
 	"sync"
 	"time"
 
 	"claude-squad/services/executor"
+	"claude-squad/services/forge"
 	"claude-squad/services/git"
 	"claude-squad/services/storage"
 	"claude-squad/services/tmux"
@@ -20,17 +28,94 @@ type orchestratorImpl struct {
 	storage     storage.StorageRepository
 	executor    executor.CommandExecutor
 
+	// forge, when set, hosts the pull requests of every session; otherwise each
+	// repository's forge is detected from its remote
+	forge forge.Forge
+
+	// forgeHosts maps self-hosted forge host names to their kind for detection
+	forgeHosts map[string]string
+
+	// worktreeLayout decides where session worktrees are created
+	worktreeLayout WorktreeLayout
+
+	// commitSettings are how sessions' commits are made unless a session says otherwise
+	commitSettings types.CommitSettings
+
+	// diffHistoryInterval is how often RunDiffHistory samples running sessions' changes,
+	// with their patches when diffHistoryPatches is set; none are sampled when 0
+	diffHistoryInterval time.Duration
+	diffHistoryPatches  bool
+
+	// checkpointInterval is how often RunCheckpoints commits running sessions' changes;
+	// none are made when 0
+	checkpointInterval time.Duration
+
+	// submodules is whether sessions initialize their worktree's submodules unless
+	// created otherwise
+	submodules bool
+
+
+	// outputHistory is optional; when set, output is kept after a session's pane is gone
+	outputHistory *OutputHistory
+
+	// auditLog is optional; when set, the changes recorded to it can be read back
+	auditLog *storage.AuditLog
+
+	// repoScope, when set, is the only repository whose sessions are visible
+	repoScope string
+
 	// In-memory cache of active sessions
 	sessions map[string]*types.Session
 	mu       sync.RWMutex
 }
 
+// OrchestratorOption configures optional orchestrator behaviour
+type OrchestratorOption func(*orchestratorImpl)
+
+// WithOutputHistory records each session's scrollback to history before its pane is
+// killed, and serves output history from it once the pane is gone
+func WithOutputHistory(history *OutputHistory) OrchestratorOption {
+	return func(o *orchestratorImpl) {
+		o.outputHistory = history
+	}
+}
+
+// WithForge opens pull requests on f instead of the forge detected from each remote
+func WithForge(f forge.Forge) OrchestratorOption {
+	return func(o *orchestratorImpl) {
+		o.forge = f
+	}
+}
+
+// WithForgeHosts tells forge detection the kind of self-hosted forges, keyed by host name
+func WithForgeHosts(hosts map[string]string) OrchestratorOption {
+	return func(o *orchestratorImpl) {
+		o.forgeHosts = hosts
+	}
+}
+
+// WithAuditLog serves the changes recorded to auditLog by an audited store
+func WithAuditLog(auditLog *storage.AuditLog) OrchestratorOption {
+	return func(o *orchestratorImpl) {
+		o.auditLog = auditLog
+	}
+}
+
+// WithRepository limits the orchestrator to the sessions created from the repository
+// rooted at root, so commands run in one repository can't see or change another's sessions
+func WithRepository(root string) OrchestratorOption {
+	return func(o *orchestratorImpl) {
+		o.repoScope = root
+	}
+}
+
 // NewOrchestrator creates a new SessionOrchestrator instance
 func NewOrchestrator(
 	gitService git.GitService,
 	tmuxService tmux.TmuxService,
 	storage storage.StorageRepository,
 	executor executor.CommandExecutor,
+	opts ...OrchestratorOption,
 ) SessionOrchestrator {
 	orch := &orchestratorImpl{
 		gitService:  gitService,
@@ -39,24 +124,16 @@ func NewOrchestrator(
 		executor:    executor,
 		sessions:    make(map[string]*types.Session),
 	}
+	for _, opt := range opts {
+		opt(orch)
+	}
 
 	// Load existing sessions from storage
 	ctx := context.Background()
-	if sessions, err := storage.List(ctx, nil); err == nil {
+	if sessions, err := storage.List(ctx, orch.scoped(nil)); err == nil {
 		for _, s := range sessions {
-			orch.sessions[s.ID] = &types.Session{
-				ID:        s.ID,
-				Title:     s.Title,
-				Path:      s.Path,
-				Branch:    s.Branch,
-				Status:    s.Status,
-				Program:   s.Program,
-				Height:    s.Height,
-				Width:     s.Width,
-				CreatedAt: s.CreatedAt,
-				UpdatedAt: s.UpdatedAt,
-				AutoYes:   s.AutoYes,
-				Prompt:    s.Prompt,
+			if s.DeletedAt.IsZero() {
+				orch.sessions[s.ID] = sessionFromData(s)
 			}
 		}
 	}
@@ -64,6 +141,25 @@ func NewOrchestrator(
 	return orch
 }
 
+// scoped adds the repository scope, if any, to opts
+func (o *orchestratorImpl) scoped(opts *storage.QueryOptions) *storage.QueryOptions {
+	if o.repoScope == "" {
+		return opts
+	}
+	scoped := storage.QueryOptions{}
+	if opts != nil {
+		scoped = *opts
+	}
+	scoped.RepoPath = &o.repoScope
+	return &scoped
+}
+
+// inScope reports whether a session belongs to the repository the orchestrator is
+// limited to, if any
+func (o *orchestratorImpl) inScope(session *types.Session) bool {
+	return o.repoScope == "" || repoPathOf(session) == o.repoScope
+}
+
 func (o *orchestratorImpl) CreateSession(ctx context.Context, req types.CreateSessionRequest) (*types.Session, error) {
 	// Validate request
 	if req.Title == "" {
@@ -82,35 +178,110 @@ func (o *orchestratorImpl) CreateSession(ctx context.Context, req types.CreateSe
 		return nil, fmt.Errorf("path is not a git repository: %s", req.Path)
 	}
 
+	// Sessions are namespaced by the root of the repository they were created from, which
+	// req.Path may be a subdirectory of
+	repoRoot, err := o.gitService.GetRepositoryRoot(ctx, req.Path)
+	if err != nil {
+		repoRoot = req.Path
+	}
+	var except *types.Session
+	if req.InPlace {
+		if err := o.checkInPlaceFree(ctx, repoRoot); err != nil {
+			return nil, err
+		}
+		// The checkout the session takes over doesn't count as another user of its branch
+		except = &types.Session{RepoPath: repoRoot, InPlace: &types.InPlace{}}
+	} else if req.Stash {
+		return nil, fmt.Errorf("only in-place sessions stash changes")
+	}
+
 	// Generate session ID
 	sessionID := generateSessionID(req.Title)
 
 	// Create branch if needed
-	if req.Branch != "" {
+	reuse := false
+	if req.Branch != "" && req.ReuseBranch {
+		reuse, err = o.gitService.BranchExists(ctx, req.Path, req.Branch)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check branch: %w", err)
+		}
+	}
+	switch {
+	case reuse:
+		// The existing branch is checked out in the worktree below
+	case req.Branch != "" && req.BaseRef != "":
+		if err := o.gitService.CreateBranchFrom(ctx, req.Path, req.Branch, req.BaseRef); err != nil {
+			return nil, fmt.Errorf("failed to create branch: %w", err)
+		}
+	case req.Branch != "":
 		if err := o.gitService.CreateBranch(ctx, req.Path, req.Branch); err != nil {
 			return nil, fmt.Errorf("failed to create branch: %w", err)
 		}
-	} else {
+	case req.BaseRef != "":
+		return nil, fmt.Errorf("a branch is required when a base ref is given")
+	default:
 		// Use current branch
 		currentBranch, err := o.gitService.GetCurrentBranch(ctx, req.Path)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get current branch: %w", err)
 		}
 		req.Branch = currentBranch.Name
+		reuse = true
+	}
+	// A branch that already existed may be another session's
+	if reuse {
+		if err := o.checkBranchFree(ctx, repoRoot, req.Branch, except, false); err != nil {
+			var inUse *BranchInUseError
+			if !errors.As(err, &inUse) || !req.ForkBranch {
+				return nil, err
+			}
+			if req.Branch, err = o.forkBranch(ctx, req.Path, req.Branch); err != nil {
+				return nil, err
+			}
+		}
 	}
 
-	// Create worktree
-	worktreePath := fmt.Sprintf("%s-worktree-%s", req.Path, sessionID)
-	worktree, err := o.gitService.CreateWorktree(ctx, req.Path, worktreePath, req.Branch)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create worktree: %w", err)
+	// Create worktree, or take over the directory for in-place sessions, which is set up
+	// already
+	var (
+		worktree *git.Worktree
+		inPlace  *types.InPlace
+		cleanup  func()
+	)
+	submodules := o.submodules
+	if req.Submodules != nil {
+		submodules = *req.Submodules
+	}
+	if req.InPlace {
+		inPlace, err = o.enterInPlace(ctx, req.Path, req.Branch, req.Title, req.Stash)
+		if err != nil {
+			return nil, err
+		}
+		worktree = &git.Worktree{Path: req.Path, Branch: req.Branch}
+		submodules = false
+		cleanup = func() {
+			if _, err := o.leaveInPlace(ctx, req.Path, *inPlace); err != nil {
+				fmt.Printf("warning: %v\n", err)
+			}
+		}
+	} else {
+		worktreePath := o.worktreeLayout.Path(req.Path, req.Branch, sessionID)
+		worktree, err = o.gitService.CreateWorktree(ctx, req.Path, worktreePath, req.Branch)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create worktree: %w", err)
+		}
+		o.setupLFS(ctx, worktree.Path)
+		if submodules {
+			o.updateSubmodules(ctx, worktree.Path)
+		}
+		cleanup = func() { _ = o.gitService.RemoveWorktree(ctx, worktreePath, true) }
 	}
 
 	// Create tmux session
 	tmuxSession, err := o.tmuxService.CreateSession(ctx, sessionID, worktree.Path, req.Program)
 	if err != nil {
 		// Cleanup worktree on failure
-		_ = o.gitService.RemoveWorktree(ctx, worktreePath, true)
+		cleanup()
 		return nil, fmt.Errorf("failed to create tmux session: %w", err)
 	}
 
@@ -119,6 +290,7 @@ func (o *orchestratorImpl) CreateSession(ctx context.Context, req types.CreateSe
 		ID:        sessionID,
 		Title:     req.Title,
 		Path:      worktree.Path,
+		RepoPath:  repoRoot,
 		Branch:    req.Branch,
 		Status:    types.StatusLoading,
 		Program:   req.Program,
@@ -128,6 +300,9 @@ func (o *orchestratorImpl) CreateSession(ctx context.Context, req types.CreateSe
 		UpdatedAt: time.Now(),
 		AutoYes:   req.AutoYes,
 		Prompt:    req.Prompt,
+
+		Submodules: submodules,
+		InPlace:    inPlace,
 	}
 
 	// Send initial prompt if provided
@@ -135,28 +310,16 @@ func (o *orchestratorImpl) CreateSession(ctx context.Context, req types.CreateSe
 		if err := o.tmuxService.SendKeys(ctx, tmuxSession.Name, req.Prompt); err != nil {
 			// Log but don't fail
 			fmt.Printf("warning: failed to send initial prompt: %v\n", err)
+		} else {
+			session.Inputs = []types.InputRecord{{Time: session.CreatedAt, Kind: types.InputPrompt, Text: req.Prompt}}
 		}
 	}
 
 	// Save to storage
-	storageData := &types.SessionData{
-		ID:        session.ID,
-		Title:     session.Title,
-		Path:      session.Path,
-		Branch:    session.Branch,
-		Status:    session.Status,
-		Program:   session.Program,
-		Height:    session.Height,
-		Width:     session.Width,
-		CreatedAt: session.CreatedAt,
-		UpdatedAt: session.UpdatedAt,
-		AutoYes:   session.AutoYes,
-		Prompt:    session.Prompt,
-	}
-	if err := o.storage.Create(ctx, storageData); err != nil {
+	if err := o.storage.Create(ctx, sessionToData(session)); err != nil {
 		// Cleanup on failure
 		_ = o.tmuxService.KillSession(ctx, tmuxSession.Name)
-		_ = o.gitService.RemoveWorktree(ctx, worktreePath, true)
+		cleanup()
 		return nil, fmt.Errorf("failed to save session: %w", err)
 	}
 
@@ -174,6 +337,120 @@ func (o *orchestratorImpl) CreateSession(ctx context.Context, req types.CreateSe
 	return session, nil
 }
 
+func (o *orchestratorImpl) CloneSession(ctx context.Context, req types.CloneSessionRequest) (*types.Session, error) {
+	source, err := o.GetSession(ctx, req.SourceID)
+	if err != nil {
+		return nil, err
+	}
+
+	title := req.Title
+	if title == "" {
+		title = source.Title + "-clone"
+	}
+	branch := req.Branch
+	if branch == "" {
+		branch = branchNameFromTitle(title)
+	}
+
+	createReq := types.CreateSessionRequest{
+		Title:   title,
+		Path:    repoPathOf(source),
+		Branch:  branch,
+		Program: source.Program,
+		Height:  source.Height,
+		Width:   source.Width,
+		AutoYes: source.AutoYes,
+		Prompt:  source.Prompt,
+
+		Submodules: &source.Submodules,
+	}
+	if req.FromSourceBranch {
+		createReq.BaseRef = source.Branch
+	}
+
+	return o.CreateSession(ctx, createReq)
+}
+
+func (o *orchestratorImpl) RenameSession(ctx context.Context, sessionID, newTitle string, renameBranch bool) (*types.Session, error) {
+	if newTitle == "" {
+		return nil, fmt.Errorf("session title is required")
+	}
+
+	session, err := o.GetSession(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The tmux session is named after the session ID, so the ID follows the title
+	renamed := *session
+	renamed.ID = generateSessionID(newTitle)
+	renamed.Title = newTitle
+	renamed.RepoPath = repoPathOf(session)
+	renamed.UpdatedAt = time.Now()
+	if renameBranch {
+		renamed.Branch = branchNameFromTitle(newTitle)
+	}
+
+	if exists, _ := o.storage.Exists(ctx, renamed.ID); exists {
+		return nil, fmt.Errorf("session already exists: %s", renamed.ID)
+	}
+
+	// Each step registers its undo so a failure leaves everything under the old name
+	var undo []func()
+	rollback := func() {
+		for i := len(undo) - 1; i >= 0; i-- {
+			undo[i]()
+		}
+	}
+
+	// Paused sessions have no tmux session
+	if session.Status != types.StatusPaused {
+		if err := o.tmuxService.RenameSession(ctx, sessionID, renamed.ID); err != nil {
+			return nil, fmt.Errorf("failed to rename tmux session: %w", err)
+		}
+		undo = append(undo, func() { _ = o.tmuxService.RenameSession(ctx, renamed.ID, sessionID) })
+	}
+
+	if renamed.Branch != session.Branch {
+		if err := o.gitService.RenameBranch(ctx, renamed.RepoPath, session.Branch, renamed.Branch); err != nil {
+			rollback()
+			return nil, fmt.Errorf("failed to rename branch: %w", err)
+		}
+		undo = append(undo, func() { _ = o.gitService.RenameBranch(ctx, renamed.RepoPath, renamed.Branch, session.Branch) })
+	}
+
+	data := sessionToData(&renamed)
+	if err := o.storage.Create(ctx, data); err != nil {
+		rollback()
+		return nil, fmt.Errorf("failed to save session: %w", err)
+	}
+	// Create stamps a fresh creation time; keep the original one
+	data.CreatedAt = session.CreatedAt
+	if err := o.storage.Update(ctx, data); err != nil {
+		_ = o.storage.Delete(ctx, renamed.ID)
+		rollback()
+		return nil, fmt.Errorf("failed to save session: %w", err)
+	}
+	if err := o.storage.Delete(ctx, sessionID); err != nil {
+		_ = o.storage.Delete(ctx, renamed.ID)
+		rollback()
+		return nil, fmt.Errorf("failed to delete old session from storage: %w", err)
+	}
+
+	if o.outputHistory != nil {
+		if err := o.outputHistory.Rename(sessionID, renamed.ID); err != nil {
+			fmt.Printf("warning: %v\n", err)
+		}
+	}
+
+	o.mu.Lock()
+	delete(o.sessions, sessionID)
+	o.sessions[renamed.ID] = &renamed
+	o.mu.Unlock()
+
+	return &renamed, nil
+}
+
 func (o *orchestratorImpl) StartSession(ctx context.Context, sessionID string) error {
 	session, err := o.GetSession(ctx, sessionID)
 	if err != nil {
@@ -183,15 +460,49 @@ func (o *orchestratorImpl) StartSession(ctx context.Context, sessionID string) e
 	if session.Status != types.StatusPaused {
 		return fmt.Errorf("session is not paused")
 	}
+	if session.Archived {
+		return fmt.Errorf("session is archived; unarchive it first")
+	}
 
-	// Recreate worktree
-	worktree, err := o.gitService.CreateWorktree(ctx, session.Path, session.Path, session.Branch)
-	if err != nil {
-		return fmt.Errorf("failed to recreate worktree: %w", err)
+	// Another session may have taken up the branch meanwhile
+	if err := o.checkBranchFree(ctx, repoPathOf(session), session.Branch, session, true); err != nil {
+		return err
+	}
+
+	if session.InPlace != nil {
+		// The directory was left as it was; don't switch branches under whoever used it since
+		current, err := o.gitService.GetCurrentBranch(ctx, session.Path)
+		if err != nil {
+			return fmt.Errorf("failed to get current branch: %w", err)
+		}
+		if current.Name != session.Branch {
+			return fmt.Errorf("%s is on branch %s; check out %s there first", session.Path, current.Name, session.Branch)
+		}
+	} else {
+		// Take back the worktree kept while paused, or recreate it
+		worktree, err := o.keptWorktree(ctx, session)
+		if err != nil {
+			return err
+		}
+		if worktree == nil {
+			worktree, err = o.gitService.CreateWorktree(ctx, repoPathOf(session), session.Path, session.Branch)
+			if err != nil {
+				return fmt.Errorf("failed to recreate worktree: %w", err)
+			}
+		}
+		if worktree.IsLocked && worktree.LockReason == pausedWorktreeLockReason {
+			if err := o.gitService.UnlockWorktree(ctx, session.Path); err != nil {
+				fmt.Printf("warning: failed to unlock worktree: %v\n", err)
+			}
+		}
+		o.setupLFS(ctx, worktree.Path)
+		if session.Submodules {
+			o.updateSubmodules(ctx, worktree.Path)
+		}
 	}
 
 	// Recreate tmux session
-	_, err = o.tmuxService.CreateSession(ctx, sessionID, worktree.Path, session.Program)
+	_, err = o.tmuxService.CreateSession(ctx, sessionID, session.Path, session.Program)
 	if err != nil {
 		return fmt.Errorf("failed to recreate tmux session: %w", err)
 	}
@@ -209,46 +520,207 @@ func (o *orchestratorImpl) PauseSession(ctx context.Context, sessionID string) e
 		return nil // Already paused
 	}
 
+	o.recordOutput(ctx, sessionID)
+
+	// Let the agent save its state before its session goes
+	o.cancelProgram(ctx, sessionID)
+
 	// Kill tmux session
 	if err := o.tmuxService.KillSession(ctx, sessionID); err != nil {
 		// Session might not exist, continue anyway
 		fmt.Printf("warning: failed to kill tmux session: %v\n", err)
 	}
 
-	// Remove worktree but keep branch
-	if err := o.gitService.RemoveWorktree(ctx, session.Path, false); err != nil {
-		// Worktree might not exist, continue anyway
-		fmt.Printf("warning: failed to remove worktree: %v\n", err)
+	// Remove worktree but keep branch. A worktree with uncommitted changes isn't removed;
+	// it is locked instead, so pruning or removing worktrees elsewhere can't destroy them.
+	// In-place sessions' directories are left as they are.
+	if session.InPlace == nil {
+		if err := o.gitService.RemoveWorktree(ctx, session.Path, false); err != nil {
+			if lockErr := o.gitService.LockWorktree(ctx, session.Path, pausedWorktreeLockReason); lockErr != nil {
+				// Worktree might not exist, continue anyway
+				fmt.Printf("warning: failed to remove worktree: %v\n", err)
+			}
+		}
 	}
 
 	return o.UpdateSessionStatus(ctx, sessionID, types.StatusPaused)
 }
 
+// pausedWorktreeLockReason marks the worktrees PauseSession keeps, so that only those are
+// unlocked on resume
+const pausedWorktreeLockReason = "kept by claude-squad for a paused session"
+
+// keptWorktree returns the worktree registered at a paused session's path, which
+// PauseSession keeps when it has uncommitted changes, or nil when there is none and it has
+// to be recreated
+func (o *orchestratorImpl) keptWorktree(ctx context.Context, session *types.Session) (*git.Worktree, error) {
+	worktrees, err := o.gitService.ListWorktrees(ctx, repoPathOf(session))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list worktrees: %w", err)
+	}
+	path := filepath.Clean(session.Path)
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+	for _, worktree := range worktrees {
+		if worktree.Path != path && worktree.Path != filepath.Clean(session.Path) {
+			continue
+		}
+		if _, err := os.Stat(worktree.Path); err != nil {
+			// Its directory is gone, so it has to be recreated
+			return nil, nil
+		}
+		return worktree, nil
+	}
+	return nil, nil
+}
+
+// cancelProgram interrupts the program running in a session's pane and waits for it to
+// exit, up to the executor's drain timeout
+func (o *orchestratorImpl) cancelProgram(ctx context.Context, sessionID string) {
+	pid, err := o.tmuxService.GetSessionPID(ctx, sessionID)
+	if err != nil {
+		// Nothing is running, or the multiplexer can't tell; killing the session will do
+		return
+	}
+	handle, err := o.executor.FindProcess(ctx, pid)
+	if err != nil {
+		return
+	}
+	if err := o.executor.Cancel(ctx, handle); err != nil {
+		fmt.Printf("warning: failed to stop session program: %v\n", err)
+	}
+}
+
 func (o *orchestratorImpl) ResumeSession(ctx context.Context, sessionID string) error {
 	return o.StartSession(ctx, sessionID)
 }
 
-func (o *orchestratorImpl) StopSession(ctx context.Context, sessionID string) error {
+func (o *orchestratorImpl) ArchiveSession(ctx context.Context, sessionID string) error {
 	session, err := o.GetSession(ctx, sessionID)
 	if err != nil {
 		return err
 	}
+	if session.Archived {
+		return nil
+	}
 
-	// Kill tmux session
-	if err := o.tmuxService.KillSession(ctx, sessionID); err != nil {
-		// Log but don't fail
-		fmt.Printf("warning: failed to kill tmux session: %v\n", err)
+	// Snapshot uncommitted work before the worktree goes; paused sessions have none
+	snapshot := ""
+	if session.Status != types.StatusPaused {
+		snapshot, err = o.gitService.GetDiffPatch(ctx, session.Path)
+		if err != nil {
+			return fmt.Errorf("failed to snapshot diff: %w", err)
+		}
 	}
 
-	// Remove worktree
-	if err := o.gitService.RemoveWorktree(ctx, session.Path, true); err != nil {
-		// Log but don't fail
-		fmt.Printf("warning: failed to remove worktree: %v\n", err)
+	data, err := o.storage.Get(ctx, sessionID)
+	if err != nil {
+		return fmt.Errorf("session not found: %s", sessionID)
+	}
+	data.Status = types.StatusPaused
+	data.Archived = true
+	data.ArchivedAt = time.Now()
+	data.DiffSnapshot = snapshot
+	if err := o.storage.Update(ctx, data); err != nil {
+		return fmt.Errorf("failed to save session: %w", err)
 	}
 
-	// Delete from storage
-	if err := o.storage.Delete(ctx, sessionID); err != nil {
-		return fmt.Errorf("failed to delete session from storage: %w", err)
+	if session.Status != types.StatusPaused {
+		o.recordOutput(ctx, sessionID)
+		if err := o.tmuxService.KillSession(ctx, sessionID); err != nil {
+			fmt.Printf("warning: failed to kill tmux session: %v\n", err)
+		}
+		// The snapshot holds the uncommitted changes, so the worktree can go even if dirty.
+		// An in-place session's directory stays as it is until the session is deleted.
+		if session.InPlace == nil {
+			_ = o.gitService.UnlockWorktree(ctx, session.Path)
+			if err := o.gitService.RemoveWorktree(ctx, session.Path, true); err != nil {
+				fmt.Printf("warning: failed to remove worktree: %v\n", err)
+			}
+		}
+	}
+
+	o.mu.Lock()
+	session.Status = types.StatusPaused
+	session.Archived = true
+	session.ArchivedAt = data.ArchivedAt
+	session.DiffSnapshot = snapshot
+	session.UpdatedAt = data.UpdatedAt
+	o.mu.Unlock()
+
+	return nil
+}
+
+func (o *orchestratorImpl) UnarchiveSession(ctx context.Context, sessionID string) error {
+	session, err := o.GetSession(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+	if !session.Archived {
+		return fmt.Errorf("session is not archived")
+	}
+
+	data, err := o.storage.Get(ctx, sessionID)
+	if err != nil {
+		return fmt.Errorf("session not found: %s", sessionID)
+	}
+	// The diff snapshot is kept as a record of the work done before archiving
+	data.Archived = false
+	data.ArchivedAt = time.Time{}
+	if err := o.storage.Update(ctx, data); err != nil {
+		return fmt.Errorf("failed to save session: %w", err)
+	}
+
+	o.mu.Lock()
+	session.Archived = false
+	session.ArchivedAt = time.Time{}
+	session.UpdatedAt = data.UpdatedAt
+	o.mu.Unlock()
+
+	return nil
+}
+
+func (o *orchestratorImpl) StopSession(ctx context.Context, sessionID string) error {
+	session, err := o.GetSession(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+
+	// Keep the uncommitted changes and output with the record for RestoreSession to put
+	// back. A paused session's changes are in the worktree it was kept with, if any; an
+	// archived one's are already in its snapshot.
+	snapshot := session.DiffSnapshot
+	if !session.Archived && (session.Status != types.StatusPaused || o.hasKeptWorktree(session)) {
+		if patch, err := o.gitService.GetDiffPatch(ctx, session.Path); err == nil {
+			snapshot = patch
+		} else {
+			fmt.Printf("warning: failed to snapshot diff: %v\n", err)
+		}
+	}
+	if session.Status != types.StatusPaused {
+		o.recordOutput(ctx, sessionID)
+	}
+
+	o.releaseResources(ctx, session)
+
+	// Move to the trash rather than deleting the record
+	data, err := o.storage.Get(ctx, sessionID)
+	if err != nil {
+		return fmt.Errorf("session not found: %s", sessionID)
+	}
+	if data.InPlace != nil {
+		left, err := o.leaveInPlace(ctx, session.Path, *data.InPlace)
+		if err != nil {
+			fmt.Printf("warning: %v\n", err)
+		}
+		data.InPlace = &left
+	}
+	data.Status = types.StatusPaused
+	data.DiffSnapshot = snapshot
+	data.DeletedAt = time.Now()
+	if err := o.storage.Update(ctx, data); err != nil {
+		return fmt.Errorf("failed to move session to trash: %w", err)
 	}
 
 	// Remove from cache
@@ -259,6 +731,280 @@ func (o *orchestratorImpl) StopSession(ctx context.Context, sessionID string) er
 	return nil
 }
 
+func (o *orchestratorImpl) ListTrash(ctx context.Context) ([]*types.Session, error) {
+	data, err := o.storage.List(ctx, o.scoped(&storage.QueryOptions{SortBy: "updated_at", SortOrder: "desc"}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	var sessions []*types.Session
+	for _, d := range data {
+		if !d.DeletedAt.IsZero() {
+			sessions = append(sessions, sessionFromData(d))
+		}
+	}
+	return sessions, nil
+}
+
+func (o *orchestratorImpl) RestoreSession(ctx context.Context, sessionID string) error {
+	data, err := o.storage.Get(ctx, sessionID)
+	if err != nil || !o.inScope(sessionFromData(data)) {
+		return fmt.Errorf("session not found: %s", sessionID)
+	}
+	if data.DeletedAt.IsZero() {
+		return fmt.Errorf("session is not in the trash")
+	}
+
+	// The session comes back paused. Its uncommitted changes go back into a worktree kept
+	// for it, which resuming takes back; without any, resuming recreates it from the branch.
+	// If they can't be put back, or the session is archived, the snapshot keeps them.
+	restored := false
+	if data.DiffSnapshot != "" && data.InPlace == nil && !data.Archived {
+		if err := o.restoreSnapshot(ctx, sessionFromData(data)); err != nil {
+			fmt.Printf("warning: %v; they are kept in the session's diff snapshot\n", err)
+		} else {
+			data.DiffSnapshot = ""
+			restored = true
+		}
+	}
+	data.DeletedAt = time.Time{}
+	if err := o.storage.Update(ctx, data); err != nil {
+		if restored {
+			o.releaseResources(ctx, sessionFromData(data))
+		}
+		return fmt.Errorf("failed to save session: %w", err)
+	}
+
+	o.mu.Lock()
+	o.sessions[sessionID] = sessionFromData(data)
+	o.mu.Unlock()
+
+	return nil
+}
+
+// hasKeptWorktree reports whether a paused session still has the worktree PauseSession
+// keeps when it has uncommitted changes
+func (o *orchestratorImpl) hasKeptWorktree(session *types.Session) bool {
+	if session.InPlace != nil {
+		return false
+	}
+	_, err := os.Stat(session.Path)
+	return err == nil
+}
+
+// restoreSnapshot recreates a restored session's worktree with the uncommitted changes it
+// was deleted with, locked as PauseSession keeps a worktree with changes
+func (o *orchestratorImpl) restoreSnapshot(ctx context.Context, session *types.Session) error {
+	if _, err := os.Stat(session.Path); err == nil {
+		return fmt.Errorf("failed to restore uncommitted changes: %s already exists", session.Path)
+	}
+	if _, err := o.gitService.CreateWorktree(ctx, repoPathOf(session), session.Path, session.Branch); err != nil {
+		return fmt.Errorf("failed to recreate worktree: %w", err)
+	}
+	if err := o.gitService.ApplyPatch(ctx, session.Path, strings.NewReader(session.DiffSnapshot)); err != nil {
+		_ = o.gitService.RemoveWorktree(ctx, session.Path, true)
+		return fmt.Errorf("failed to restore uncommitted changes: %w", err)
+	}
+	if err := o.gitService.LockWorktree(ctx, session.Path, pausedWorktreeLockReason); err != nil {
+		fmt.Printf("warning: failed to lock worktree: %v\n", err)
+	}
+	return nil
+}
+
+func (o *orchestratorImpl) PurgeTrash(ctx context.Context, olderThan time.Duration) ([]*types.Session, error) {
+	trash, err := o.ListTrash(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	cutoff := time.Now().Add(-olderThan)
+	var purged []*types.Session
+	var ids []string
+	for _, session := range trash {
+		if olderThan <= 0 || session.DeletedAt.Before(cutoff) {
+			purged = append(purged, session)
+			ids = append(ids, session.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	if err := o.storage.DeleteBatch(ctx, ids); err != nil {
+		return nil, fmt.Errorf("failed to delete sessions from storage: %w", err)
+	}
+	for _, id := range ids {
+		o.deleteOutput(id)
+	}
+	return purged, nil
+}
+
+func (o *orchestratorImpl) PruneSessions(ctx context.Context, req types.PruneRequest) ([]*types.Session, error) {
+	if req.OlderThan <= 0 && req.Status == nil {
+		return nil, fmt.Errorf("a retention age or status is required")
+	}
+
+	opts := &storage.QueryOptions{Status: req.Status}
+	var cutoff time.Time
+	if req.OlderThan > 0 {
+		cutoff = time.Now().Add(-req.OlderThan)
+		opts.UpdatedBefore = &cutoff
+	}
+
+	data, err := o.storage.List(ctx, o.scoped(opts))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+	sessions := make([]*types.Session, len(data))
+	for i, d := range data {
+		sessions[i] = sessionFromData(d)
+	}
+	if req.DryRun || len(sessions) == 0 {
+		return sessions, nil
+	}
+
+	ids := make([]string, len(sessions))
+	for i, session := range sessions {
+		ids[i] = session.ID
+		o.releaseResources(ctx, session)
+	}
+
+	if req.Status == nil && o.repoScope == "" {
+		// Pass the age of the cutoff used above so exactly the listed sessions are removed
+		err = o.storage.DeleteOlderThan(ctx, time.Since(cutoff))
+	} else {
+		err = o.storage.DeleteBatch(ctx, ids)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete sessions from storage: %w", err)
+	}
+
+	o.mu.Lock()
+	for _, id := range ids {
+		delete(o.sessions, id)
+	}
+	o.mu.Unlock()
+	for _, id := range ids {
+		o.deleteOutput(id)
+	}
+
+	return sessions, nil
+}
+
+func (o *orchestratorImpl) EnforceRetention(ctx context.Context, policy types.RetentionPolicy) (*types.RetentionReport, error) {
+	report := &types.RetentionReport{}
+
+	// Age sessions out first, since archiving counts as an update. Only paused sessions,
+	// archived ones included, are moved to the trash; running ones are never touched.
+	if policy.MaxAge > 0 {
+		sessions, err := o.ListSessions(ctx)
+		if err != nil {
+			return report, err
+		}
+		cutoff := time.Now().Add(-policy.MaxAge)
+		for _, session := range sessions {
+			if session.Status != types.StatusPaused || !session.UpdatedAt.Before(cutoff) {
+				continue
+			}
+			if err := o.StopSession(ctx, session.ID); err != nil {
+				fmt.Printf("warning: failed to move session %s to the trash: %v\n", session.Title, err)
+				continue
+			}
+			report.Expired = append(report.Expired, session)
+		}
+
+		purged, err := o.PurgeTrash(ctx, policy.MaxAge)
+		if err != nil {
+			return report, err
+		}
+		report.Purged = purged
+	}
+
+	if policy.ArchiveAfterPaused > 0 {
+		sessions, err := o.ListSessions(ctx)
+		if err != nil {
+			return report, err
+		}
+		cutoff := time.Now().Add(-policy.ArchiveAfterPaused)
+		for _, session := range sessions {
+			if session.Status != types.StatusPaused || session.Archived || !session.UpdatedAt.Before(cutoff) {
+				continue
+			}
+			if err := o.ArchiveSession(ctx, session.ID); err != nil {
+				fmt.Printf("warning: failed to archive session %s: %v\n", session.Title, err)
+				continue
+			}
+			report.Archived = append(report.Archived, session)
+		}
+	}
+
+	if policy.MaxSessions > 0 {
+		sessions, err := o.ListSessions(ctx)
+		if err != nil {
+			return report, err
+		}
+		excess := len(sessions) - policy.MaxSessions
+		slices.SortFunc(sessions, func(a, b *types.Session) int { return a.UpdatedAt.Compare(b.UpdatedAt) })
+		for _, session := range sessions {
+			if excess <= 0 {
+				break
+			}
+			if session.Status != types.StatusPaused {
+				continue
+			}
+			if err := o.StopSession(ctx, session.ID); err != nil {
+				fmt.Printf("warning: failed to move session %s to the trash: %v\n", session.Title, err)
+				continue
+			}
+			report.Trashed = append(report.Trashed, session)
+			excess--
+		}
+	}
+
+	return report, nil
+}
+
+// releaseResources kills a session's tmux session and removes its worktree, even if it was
+// locked while paused. Failures are only warned about since either may already be gone.
+// A paused session has no tmux session left, and only has a worktree if it was kept. An
+// in-place session's directory isn't its own to remove.
+func (o *orchestratorImpl) releaseResources(ctx context.Context, session *types.Session) {
+	paused := session.Status == types.StatusPaused
+	if !paused {
+		if err := o.tmuxService.KillSession(ctx, session.ID); err != nil {
+			fmt.Printf("warning: failed to kill tmux session: %v\n", err)
+		}
+	}
+	if session.InPlace != nil {
+		return
+	}
+	_ = o.gitService.UnlockWorktree(ctx, session.Path)
+	if err := o.gitService.RemoveWorktree(ctx, session.Path, true); err != nil && (!paused || o.hasKeptWorktree(session)) {
+		fmt.Printf("warning: failed to remove worktree: %v\n", err)
+	}
+}
+
+// recordOutput saves a session's scrollback to the output history, if there is one,
+// before its pane is killed
+func (o *orchestratorImpl) recordOutput(ctx context.Context, sessionID string) {
+	if o.outputHistory == nil {
+		return
+	}
+	if err := o.outputHistory.Record(ctx, sessionID); err != nil {
+		fmt.Printf("warning: failed to record output history: %v\n", err)
+	}
+}
+
+// deleteOutput removes a deleted session's output history, if there is one
+func (o *orchestratorImpl) deleteOutput(sessionID string) {
+	if o.outputHistory == nil {
+		return
+	}
+	if err := o.outputHistory.Delete(sessionID); err != nil {
+		fmt.Printf("warning: %v\n", err)
+	}
+}
+
 func (o *orchestratorImpl) GetSession(ctx context.Context, sessionID string) (*types.Session, error) {
 	o.mu.RLock()
 	session, exists := o.sessions[sessionID]
@@ -268,25 +1014,16 @@ func (o *orchestratorImpl) GetSession(ctx context.Context, sessionID string) (*t
 		return session, nil
 	}
 
-	// Try loading from storage
+	// Try loading from storage. Sessions in the trash are only reachable through the
+	// trash methods.
 	data, err := o.storage.Get(ctx, sessionID)
-	if err != nil {
+	if err != nil || !data.DeletedAt.IsZero() {
 		return nil, fmt.Errorf("session not found: %s", sessionID)
 	}
 
-	session = &types.Session{
-		ID:        data.ID,
-		Title:     data.Title,
-		Path:      data.Path,
-		Branch:    data.Branch,
-		Status:    data.Status,
-		Program:   data.Program,
-		Height:    data.Height,
-		Width:     data.Width,
-		CreatedAt: data.CreatedAt,
-		UpdatedAt: data.UpdatedAt,
-		AutoYes:   data.AutoYes,
-		Prompt:    data.Prompt,
+	session = sessionFromData(data)
+	if !o.inScope(session) {
+		return nil, fmt.Errorf("session not found: %s", sessionID)
 	}
 
 	// Cache it
@@ -298,30 +1035,32 @@ func (o *orchestratorImpl) GetSession(ctx context.Context, sessionID string) (*t
 }
 
 func (o *orchestratorImpl) ListSessions(ctx context.Context) ([]*types.Session, error) {
-	data, err := o.storage.List(ctx, nil)
+	data, err := o.storage.List(ctx, o.scoped(nil))
 	if err != nil {
 		return nil, fmt.Errorf("failed to list sessions: %w", err)
 	}
 
-	sessions := make([]*types.Session, len(data))
-	for i, d := range data {
-		sessions[i] = &types.Session{
-			ID:        d.ID,
-			Title:     d.Title,
-			Path:      d.Path,
-			Branch:    d.Branch,
-			Status:    d.Status,
-			Program:   d.Program,
-			Height:    d.Height,
-			Width:     d.Width,
-			CreatedAt: d.CreatedAt,
-			UpdatedAt: d.UpdatedAt,
-			AutoYes:   d.AutoYes,
-			Prompt:    d.Prompt,
-		}
+	return liveSessions(data), nil
+}
+
+func (o *orchestratorImpl) SearchSessions(ctx context.Context, query string) ([]*types.Session, error) {
+	data, err := o.storage.Search(ctx, query, o.scoped(nil))
+	if err != nil {
+		return nil, fmt.Errorf("failed to search sessions: %w", err)
 	}
 
-	return sessions, nil
+	return liveSessions(data), nil
+}
+
+// liveSessions converts stored sessions, leaving out those in the trash
+func liveSessions(data []*types.SessionData) []*types.Session {
+	sessions := make([]*types.Session, 0, len(data))
+	for _, d := range data {
+		if d.DeletedAt.IsZero() {
+			sessions = append(sessions, sessionFromData(d))
+		}
+	}
+	return sessions
 }
 
 func (o *orchestratorImpl) AttachSession(ctx context.Context, sessionID string) error {
@@ -347,7 +1086,63 @@ func (o *orchestratorImpl) SendInput(ctx context.Context, sessionID string, inpu
 		return fmt.Errorf("session is not ready or running")
 	}
 
-	return o.tmuxService.SendKeys(ctx, sessionID, input)
+	if err := o.tmuxService.SendKeys(ctx, sessionID, input); err != nil {
+		return err
+	}
+	o.recordInput(ctx, session, types.InputKeys, input)
+	return nil
+}
+
+func (o *orchestratorImpl) SendPrompt(ctx context.Context, sessionID string, prompt string) error {
+	session, err := o.GetSession(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+
+	if session.Status != types.StatusReady && session.Status != types.StatusRunning {
+		return fmt.Errorf("session is not ready or running")
+	}
+
+	if err := o.tmuxService.SendText(ctx, sessionID, prompt); err != nil {
+		return err
+	}
+	// Brief pause so the program doesn't treat Enter as part of a paste
+	time.Sleep(100 * time.Millisecond)
+	if err := o.tmuxService.SendKeys(ctx, sessionID, "Enter"); err != nil {
+		return err
+	}
+	o.recordInput(ctx, session, types.InputPrompt, prompt)
+	return nil
+}
+
+// recordInput appends input to the session's persisted history. The input has already
+// reached the session, so a failure to save it is not reported to the caller.
+func (o *orchestratorImpl) recordInput(ctx context.Context, session *types.Session, kind types.InputKind, text string) {
+	record := types.InputRecord{Time: time.Now(), Kind: kind, Text: text}
+
+	o.mu.Lock()
+	session.Inputs = append(session.Inputs, record)
+	o.mu.Unlock()
+
+	// Append to the stored copy rather than overwriting it with the cached session, which
+	// may be stale if another process sent input too
+	data, err := o.storage.Get(ctx, session.ID)
+	if err != nil {
+		return
+	}
+	data.Inputs = append(data.Inputs, record)
+	_ = o.storage.Update(ctx, data)
+}
+
+func (o *orchestratorImpl) GetInputHistory(ctx context.Context, sessionID string) ([]types.InputRecord, error) {
+	if _, err := o.GetSession(ctx, sessionID); err != nil {
+		return nil, err
+	}
+	data, err := o.storage.Get(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("session not found: %s", sessionID)
+	}
+	return data.Inputs, nil
 }
 
 func (o *orchestratorImpl) GetOutput(ctx context.Context, sessionID string) (string, error) {
@@ -369,6 +1164,190 @@ func (o *orchestratorImpl) GetOutput(ctx context.Context, sessionID string) (str
 	return output, nil
 }
 
+func (o *orchestratorImpl) GetAuditLog(ctx context.Context, ref string) ([]storage.AuditEntry, error) {
+	if o.auditLog == nil {
+		return nil, fmt.Errorf("audit log is not enabled")
+	}
+	return o.auditLog.Entries(func(entry storage.AuditEntry) bool {
+		return entry.SessionID == ref || entry.Title == ref
+	})
+}
+
+func (o *orchestratorImpl) GetOutputHistory(ctx context.Context, sessionID string) (string, error) {
+	session, err := o.GetSession(ctx, sessionID)
+	if err != nil {
+		return "", err
+	}
+
+	// Fall back to the recorded history once the pane is gone
+	if session.Status == types.StatusPaused {
+		if o.outputHistory == nil {
+			return "", fmt.Errorf("session is paused")
+		}
+		output, err := o.outputHistory.Load(sessionID)
+		if errors.Is(err, ErrNoOutputHistory) {
+			return "", fmt.Errorf("session is paused and has no recorded output")
+		}
+		return output, err
+	}
+
+	output, err := o.tmuxService.GetPaneScrollback(ctx, sessionID, "0")
+	if err != nil {
+		if o.outputHistory != nil {
+			if recorded, loadErr := o.outputHistory.Load(sessionID); loadErr == nil {
+				return recorded, nil
+			}
+		}
+		return "", fmt.Errorf("failed to capture history: %w", err)
+	}
+
+	return output, nil
+}
+
+func (o *orchestratorImpl) CommitSession(ctx context.Context, sessionID string, opts git.CommitOptions) (*git.CommitInfo, error) {
+	session, err := o.GetSession(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Paused sessions have had their worktree removed
+	if session.Status == types.StatusPaused {
+		return nil, fmt.Errorf("session is paused")
+	}
+
+	return o.gitService.CommitWithOptions(ctx, session.Path, o.commitOptions(session, opts))
+}
+
+func (o *orchestratorImpl) PushSession(ctx context.Context, sessionID string, opts git.PushOptions) (*types.PushResult, error) {
+	session, err := o.GetSession(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Remote == "" {
+		opts.Remote = "origin"
+	}
+
+	// Push from the repository so paused sessions, which have no worktree, can be pushed too
+	repoPath := repoPathOf(session)
+	if err := o.gitService.Push(ctx, repoPath, session.Branch, opts); err != nil {
+		return nil, err
+	}
+
+	result := &types.PushResult{Branch: session.Branch, Remote: opts.Remote}
+	if remoteURL, err := o.gitService.GetRemoteURL(ctx, repoPath, opts.Remote); err == nil {
+		result.RemoteURL = git.StripCredentials(remoteURL)
+		result.BranchURL = git.BranchWebURL(remoteURL, session.Branch)
+	}
+	return result, nil
+}
+
+func (o *orchestratorImpl) ExecInWorktree(ctx context.Context, sessionID string, cmd executor.Command) (*executor.Result, error) {
+	session, err := o.GetSession(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Paused sessions have had their worktree removed
+	if session.Status == types.StatusPaused {
+		return nil, fmt.Errorf("session is paused")
+	}
+
+	cmd.Dir = session.Path
+	handle, err := o.executor.Start(ctx, cmd)
+	if err != nil {
+		return nil, err
+	}
+	result, err := o.executor.Wait(ctx, handle)
+	if err == nil {
+		o.recordCommand(ctx, session, types.CommandRecord{
+			Time:     time.Now(),
+			Program:  cmd.Program,
+			Args:     cmd.Args,
+			Shell:    cmd.Shell,
+			ExitCode: result.ExitCode,
+		})
+	}
+	return result, err
+}
+
+// maxCommandHistory is how many of the commands run in a session are kept
+const maxCommandHistory = 200
+
+// recordCommand appends a command run in the session's worktree to its persisted history,
+// dropping the oldest beyond maxCommandHistory. Like input, a failure to save it is not
+// reported to the caller.
+func (o *orchestratorImpl) recordCommand(ctx context.Context, session *types.Session, record types.CommandRecord) {
+	appendRecord := func(commands []types.CommandRecord) []types.CommandRecord {
+		commands = append(commands, record)
+		if len(commands) > maxCommandHistory {
+			commands = slices.Clone(commands[len(commands)-maxCommandHistory:])
+		}
+		return commands
+	}
+
+	o.mu.Lock()
+	session.Commands = appendRecord(session.Commands)
+	o.mu.Unlock()
+
+	data, err := o.storage.Get(ctx, session.ID)
+	if err != nil {
+		return
+	}
+	data.Commands = appendRecord(data.Commands)
+	_ = o.storage.Update(ctx, data)
+}
+
+func (o *orchestratorImpl) GetCommandHistory(ctx context.Context, sessionID string) ([]types.CommandRecord, error) {
+	if _, err := o.GetSession(ctx, sessionID); err != nil {
+		return nil, err
+	}
+	data, err := o.storage.Get(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("session not found: %s", sessionID)
+	}
+	return data.Commands, nil
+}
+
+func (o *orchestratorImpl) Replay(ctx context.Context, sessionID string, n int, stdout, stderr io.Writer) error {
+	commands, err := o.GetCommandHistory(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+	session, err := o.GetSession(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+	if session.Status == types.StatusPaused {
+		return fmt.Errorf("session is paused")
+	}
+
+	if n < len(commands) {
+		commands = commands[len(commands)-n:]
+	}
+	// Replayed commands aren't recorded again, so replaying twice runs the same ones
+	for _, record := range commands {
+		handle, err := o.executor.Start(ctx, executor.Command{
+			Program: record.Program,
+			Args:    record.Args,
+			Shell:   record.Shell,
+			Dir:     session.Path,
+			Stdout:  stdout,
+			Stderr:  stderr,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to replay %s: %w", record.Program, err)
+		}
+		result, err := o.executor.Wait(ctx, handle)
+		if err != nil {
+			return fmt.Errorf("failed to replay %s: %w", record.Program, err)
+		}
+		if result.ExitCode != 0 || result.Error != nil {
+			return fmt.Errorf("replaying %s failed with exit code %d", record.Program, result.ExitCode)
+		}
+	}
+	return nil
+}
+
 func (o *orchestratorImpl) UpdateSessionStatus(ctx context.Context, sessionID string, status types.Status) error {
 	o.mu.Lock()
 	defer o.mu.Unlock()
@@ -385,6 +1364,91 @@ func (o *orchestratorImpl) UpdateSessionStatus(ctx context.Context, sessionID st
 	return o.storage.UpdateStatus(ctx, sessionID, status)
 }
 
+var unsafeBranchChars = regexp.MustCompile(`[^a-z0-9/._-]+`)
+
+// branchNameFromTitle derives a git branch name from a session title
+func branchNameFromTitle(title string) string {
+	name := unsafeBranchChars.ReplaceAllString(strings.ToLower(title), "-")
+	return strings.Trim(name, "-./")
+}
+
+// repoPathOf returns the repository a session's worktree was created from. Sessions saved
+// before RepoPath was recorded fall back to the "<repo>-worktree-<session id>" naming.
+func repoPathOf(session *types.Session) string {
+	if session.RepoPath != "" {
+		return session.RepoPath
+	}
+	return strings.TrimSuffix(session.Path, "-worktree-"+session.ID)
+}
+
+// sessionFromData converts a stored session to its in-memory form
+func sessionFromData(d *types.SessionData) *types.Session {
+	return &types.Session{
+		ID:        d.ID,
+		Title:     d.Title,
+		Path:      d.Path,
+		RepoPath:  d.RepoPath,
+		Branch:    d.Branch,
+		Status:    d.Status,
+		Program:   d.Program,
+		Height:    d.Height,
+		Width:     d.Width,
+		CreatedAt: d.CreatedAt,
+		UpdatedAt: d.UpdatedAt,
+		AutoYes:   d.AutoYes,
+		Prompt:    d.Prompt,
+		Inputs:    d.Inputs,
+		Commands:  d.Commands,
+
+		DiffHistory: d.DiffHistory,
+
+		Archived:     d.Archived,
+		ArchivedAt:   d.ArchivedAt,
+		DiffSnapshot: d.DiffSnapshot,
+		DeletedAt:    d.DeletedAt,
+		Tags:         d.Tags,
+		Conflicted:   d.Conflicted,
+		Submodules:   d.Submodules,
+
+		CommitSettings: d.CommitSettings,
+		InPlace:        d.InPlace,
+	}
+}
+
+// sessionToData converts a session to its stored form
+func sessionToData(s *types.Session) *types.SessionData {
+	return &types.SessionData{
+		ID:        s.ID,
+		Title:     s.Title,
+		Path:      s.Path,
+		RepoPath:  s.RepoPath,
+		Branch:    s.Branch,
+		Status:    s.Status,
+		Program:   s.Program,
+		Height:    s.Height,
+		Width:     s.Width,
+		CreatedAt: s.CreatedAt,
+		UpdatedAt: s.UpdatedAt,
+		AutoYes:   s.AutoYes,
+		Prompt:    s.Prompt,
+		Inputs:    s.Inputs,
+		Commands:  s.Commands,
+
+		DiffHistory: s.DiffHistory,
+
+		Archived:     s.Archived,
+		ArchivedAt:   s.ArchivedAt,
+		DiffSnapshot: s.DiffSnapshot,
+		DeletedAt:    s.DeletedAt,
+		Tags:         s.Tags,
+		Conflicted:   s.Conflicted,
+		Submodules:   s.Submodules,
+
+		CommitSettings: s.CommitSettings,
+		InPlace:        s.InPlace,
+	}
+}
+
 // generateSessionID creates a unique session ID from the title
 func generateSessionID(title string) string {
 	// Simple implementation - in production, use a proper ID generator
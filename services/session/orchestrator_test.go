@@ -0,0 +1,1505 @@
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"claude-squad/log"
+	"claude-squad/services/executor"
+	"claude-squad/services/forge"
+	"claude-squad/services/git"
+	"claude-squad/services/storage"
+	"claude-squad/services/tmux"
+	"claude-squad/services/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testEnv holds the mocks and storage behind an orchestrator from newTestOrchestrator. The
+// orchestrator calls through to the mocks, so tests set their funcs at any point.
+type testEnv struct {
+	git     *git.MockGitService
+	tmux    *tmux.MockTmuxService
+	exec    *executor.MockExecutor
+	storage storage.StorageRepository
+	dir     string
+}
+
+// newTestOrchestrator returns an orchestrator over mocks that take every path to be a
+// repository, storing sessions as JSON in a temporary directory
+func newTestOrchestrator(t *testing.T, opts ...OrchestratorOption) (SessionOrchestrator, *testEnv) {
+	t.Helper()
+	env := &testEnv{
+		git:  git.NewMockGitService(),
+		tmux: tmux.NewMockTmuxService(),
+		exec: &executor.MockExecutor{},
+		dir:  t.TempDir(),
+	}
+	env.git.DefaultIsRepo = true
+	repo, err := storage.NewJSONRepository(env.dir)
+	require.NoError(t, err)
+	env.storage = repo
+	return env.reopen(opts...), env
+}
+
+// reopen returns a new orchestrator over the same mocks and storage, as another process
+// would load it
+func (e *testEnv) reopen(opts ...OrchestratorOption) SessionOrchestrator {
+	return NewOrchestrator(e.git, e.tmux, e.storage, e.exec, opts...)
+}
+
+// seed writes sessions straight to storage, which would otherwise stamp their times on
+// write. Orchestrators only see them once reopened.
+func (e *testEnv) seed(t *testing.T, sessions ...*types.SessionData) {
+	t.Helper()
+	for _, d := range sessions {
+		data, err := json.Marshal(d)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(e.dir, d.ID+".json"), data, 0644))
+	}
+}
+
+func TestCloneSession(t *testing.T) {
+	orch, env := newTestOrchestrator(t)
+	var branchedFrom [3]string
+	env.git.CreateBranchFromFunc = func(ctx context.Context, repoPath, branchName, startPoint string) error {
+		branchedFrom = [3]string{repoPath, branchName, startPoint}
+		return nil
+	}
+
+	ctx := context.Background()
+	source, err := orch.CreateSession(ctx, types.CreateSessionRequest{
+		Title:   "fix",
+		Path:    "/src/app",
+		Branch:  "fix",
+		Program: "claude",
+		Prompt:  "fix the bug",
+	})
+	require.NoError(t, err)
+
+	clone, err := orch.CloneSession(ctx, types.CloneSessionRequest{
+		SourceID:         source.ID,
+		Title:            "Fix Alt",
+		FromSourceBranch: true,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, [3]string{"/src/app", "fix-alt", "fix"}, branchedFrom)
+	assert.Equal(t, "fix-alt", clone.Branch)
+	assert.Equal(t, "claude", clone.Program)
+	assert.Equal(t, "fix the bug", clone.Prompt)
+	assert.NotEqual(t, source.Path, clone.Path)
+}
+
+func TestRenameSessionRollsBackOnBranchFailure(t *testing.T) {
+	orch, env := newTestOrchestrator(t)
+	var tmuxRenames [][2]string
+	env.tmux.RenameSessionFunc = func(ctx context.Context, oldName, newName string) error {
+		tmuxRenames = append(tmuxRenames, [2]string{oldName, newName})
+		return nil
+	}
+
+	ctx := context.Background()
+	source, err := orch.CreateSession(ctx, types.CreateSessionRequest{
+		Title:  "fix",
+		Path:   "/src/app",
+		Branch: "fix",
+	})
+	require.NoError(t, err)
+
+	env.git.RenameBranchFunc = func(ctx context.Context, repoPath, oldName, newName string) error {
+		return assert.AnError
+	}
+	_, err = orch.RenameSession(ctx, source.ID, "better fix", true)
+	require.Error(t, err)
+
+	// The tmux rename was undone and the session kept its old identity
+	require.Len(t, tmuxRenames, 2)
+	assert.Equal(t, tmuxRenames[0][0], tmuxRenames[1][1])
+	sess, err := orch.GetSession(ctx, source.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "fix", sess.Title)
+
+	env.git.RenameBranchFunc = nil
+	renamed, err := orch.RenameSession(ctx, source.ID, "better fix", true)
+	require.NoError(t, err)
+	assert.Equal(t, "better fix", renamed.Title)
+	assert.Equal(t, "better-fix", renamed.Branch)
+	assert.Equal(t, "/src/app", renamed.RepoPath)
+
+	_, err = orch.GetSession(ctx, source.ID)
+	assert.Error(t, err)
+}
+
+func TestRenameSessionKeepsCreatedAt(t *testing.T) {
+	backends := map[string]func(dir string) (storage.StorageRepository, error){
+		"json":   storage.NewJSONRepository,
+		"bolt":   storage.NewBoltRepository,
+		"sqlite": storage.NewSQLiteRepository,
+	}
+	for name, open := range backends {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			_, env := newTestOrchestrator(t)
+			repo, err := open(t.TempDir())
+			require.NoError(t, err)
+			env.storage = repo
+
+			sess, err := env.reopen().CreateSession(ctx, types.CreateSessionRequest{Title: "fix", Path: "/src/app", Branch: "fix"})
+			require.NoError(t, err)
+			created := time.Now().Add(-48 * time.Hour).Truncate(time.Second)
+			data, err := repo.Get(ctx, sess.ID)
+			require.NoError(t, err)
+			data.CreatedAt = created
+			require.NoError(t, repo.Update(ctx, data))
+
+			renamed, err := env.reopen().RenameSession(ctx, sess.ID, "better fix", false)
+			require.NoError(t, err)
+			assert.True(t, created.Equal(renamed.CreatedAt))
+			data, err = repo.Get(ctx, renamed.ID)
+			require.NoError(t, err)
+			assert.True(t, created.Equal(data.CreatedAt), "stored created_at %v", data.CreatedAt)
+		})
+	}
+}
+
+func TestPruneSessions(t *testing.T) {
+	_, env := newTestOrchestrator(t)
+	var removed []string
+	env.git.RemoveWorktreeFunc = func(ctx context.Context, worktreePath string, force bool) error {
+		removed = append(removed, worktreePath)
+		return nil
+	}
+	old := time.Now().Add(-48 * time.Hour)
+	env.seed(t,
+		&types.SessionData{ID: "stale", Title: "stale", Path: "/wt/stale", Status: types.StatusReady, CreatedAt: old, UpdatedAt: old},
+		&types.SessionData{ID: "stale-paused", Title: "stale-paused", Path: "/wt/stale-paused", Status: types.StatusPaused, CreatedAt: old, UpdatedAt: old},
+		&types.SessionData{ID: "fresh", Title: "fresh", Path: "/wt/fresh", Status: types.StatusReady, CreatedAt: time.Now(), UpdatedAt: time.Now()},
+	)
+	ctx := context.Background()
+	orch := env.reopen()
+
+	pruned, err := orch.PruneSessions(ctx, types.PruneRequest{OlderThan: 24 * time.Hour, DryRun: true})
+	require.NoError(t, err)
+	assert.Len(t, pruned, 2)
+	assert.Empty(t, removed)
+
+	pruned, err = orch.PruneSessions(ctx, types.PruneRequest{OlderThan: 24 * time.Hour})
+	require.NoError(t, err)
+	assert.Len(t, pruned, 2)
+	assert.ElementsMatch(t, []string{"/wt/stale", "/wt/stale-paused"}, removed)
+
+	remaining, err := orch.ListSessions(ctx)
+	require.NoError(t, err)
+	require.Len(t, remaining, 1)
+	assert.Equal(t, "fresh", remaining[0].ID)
+
+	_, err = orch.PruneSessions(ctx, types.PruneRequest{})
+	assert.Error(t, err)
+}
+
+func TestPrunePausedSessionWorktree(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	ctx := context.Background()
+	gitService := git.NewGitService(executor.NewDefaultExecutor())
+	repo, err := storage.NewJSONRepository(t.TempDir())
+	require.NoError(t, err)
+	orch := NewOrchestrator(gitService, tmux.NewMockTmuxService(), repo, &executor.MockExecutor{},
+		WithWorktreeLayout(WorktreeLayout{Dir: t.TempDir()}))
+
+	repoPath := newTestRepo(t)
+	sess, err := orch.CreateSession(ctx, types.CreateSessionRequest{Title: "agent", Path: repoPath, Branch: "agent"})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(sess.Path, "work.txt"), []byte("uncommitted\n"), 0644))
+	require.NoError(t, orch.PauseSession(ctx, sess.ID))
+
+	// The worktree kept and locked while paused goes with the record
+	paused := types.StatusPaused
+	pruned, err := orch.PruneSessions(ctx, types.PruneRequest{Status: &paused})
+	require.NoError(t, err)
+	require.Len(t, pruned, 1)
+	assert.NoDirExists(t, sess.Path)
+	worktrees, err := gitService.ListWorktrees(ctx, repoPath)
+	require.NoError(t, err)
+	require.Len(t, worktrees, 1)
+	assert.NotEqual(t, "agent", worktrees[0].Branch)
+}
+
+func TestEnforceRetention(t *testing.T) {
+	_, env := newTestOrchestrator(t)
+	daysAgo := func(days int) time.Time { return time.Now().Add(-time.Duration(days) * 24 * time.Hour) }
+	for _, d := range []*types.SessionData{
+		{ID: "ancient", Title: "ancient", Status: types.StatusPaused, UpdatedAt: daysAgo(100)},
+		{ID: "idle", Title: "idle", Status: types.StatusPaused, UpdatedAt: daysAgo(10)},
+		{ID: "older-paused", Title: "older-paused", Status: types.StatusPaused, UpdatedAt: daysAgo(3)},
+		{ID: "recent-paused", Title: "recent-paused", Status: types.StatusPaused, UpdatedAt: daysAgo(2)},
+		{ID: "running", Title: "running", Status: types.StatusRunning, UpdatedAt: daysAgo(20)},
+		{ID: "stale-running", Title: "stale-running", Status: types.StatusRunning, UpdatedAt: daysAgo(40)},
+		{ID: "long-gone", Title: "long-gone", Status: types.StatusPaused, UpdatedAt: daysAgo(50), DeletedAt: daysAgo(40)},
+	} {
+		d.CreatedAt = d.UpdatedAt
+		env.seed(t, d)
+	}
+	ctx := context.Background()
+	orch := env.reopen()
+
+	report, err := orch.EnforceRetention(ctx, types.RetentionPolicy{
+		MaxSessions:        4,
+		MaxAge:             30 * 24 * time.Hour,
+		ArchiveAfterPaused: 7 * 24 * time.Hour,
+	})
+	require.NoError(t, err)
+
+	titles := func(sessions []*types.Session) []string {
+		var out []string
+		for _, s := range sessions {
+			out = append(out, s.Title)
+		}
+		return out
+	}
+	assert.Equal(t, []string{"idle"}, titles(report.Archived))
+	// Running sessions are older, but only paused sessions age out or are trashed to meet
+	// the limit, and aged out ones go to the trash rather than being deleted
+	assert.Equal(t, []string{"ancient"}, titles(report.Expired))
+	assert.Equal(t, []string{"older-paused"}, titles(report.Trashed))
+	assert.Equal(t, []string{"long-gone"}, titles(report.Purged))
+
+	remaining, err := orch.ListSessions(ctx)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"idle", "recent-paused", "running", "stale-running"}, titles(remaining))
+	trash, err := orch.ListTrash(ctx)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"ancient", "older-paused"}, titles(trash))
+	idle, err := orch.GetSession(ctx, "idle")
+	require.NoError(t, err)
+	assert.True(t, idle.Archived)
+}
+
+func TestInputHistory(t *testing.T) {
+	orch, env := newTestOrchestrator(t)
+
+	ctx := context.Background()
+	sess, err := orch.CreateSession(ctx, types.CreateSessionRequest{
+		Title:  "fix",
+		Path:   "/src/app",
+		Branch: "fix",
+		Prompt: "fix the bug",
+	})
+	require.NoError(t, err)
+	require.NoError(t, orch.UpdateSessionStatus(ctx, sess.ID, types.StatusReady))
+
+	require.NoError(t, orch.SendPrompt(ctx, sess.ID, "add a test"))
+	require.NoError(t, orch.SendInput(ctx, sess.ID, "y"))
+
+	// History is read back from storage, so it survives a restart
+	history, err := env.reopen().GetInputHistory(ctx, sess.ID)
+	require.NoError(t, err)
+	require.Len(t, history, 3)
+	assert.Equal(t, types.InputRecord{Time: history[0].Time, Kind: types.InputPrompt, Text: "fix the bug"}, history[0])
+	assert.Equal(t, "add a test", history[1].Text)
+	assert.Equal(t, types.InputKeys, history[2].Kind)
+
+	renamed, err := orch.RenameSession(ctx, sess.ID, "better fix", false)
+	require.NoError(t, err)
+	history, err = orch.GetInputHistory(ctx, renamed.ID)
+	require.NoError(t, err)
+	assert.Len(t, history, 3)
+}
+
+func TestArchiveSession(t *testing.T) {
+	orch, env := newTestOrchestrator(t)
+	env.git.GetDiffPatchFunc = func(ctx context.Context, repoPath string) (string, error) {
+		return "+wip\n", nil
+	}
+	var removedForce bool
+	env.git.RemoveWorktreeFunc = func(ctx context.Context, worktreePath string, force bool) error {
+		removedForce = force
+		return nil
+	}
+
+	ctx := context.Background()
+	sess, err := orch.CreateSession(ctx, types.CreateSessionRequest{Title: "old", Path: "/src/app", Branch: "old"})
+	require.NoError(t, err)
+	require.NoError(t, orch.UpdateSessionStatus(ctx, sess.ID, types.StatusReady))
+
+	require.NoError(t, orch.ArchiveSession(ctx, sess.ID))
+	assert.True(t, removedForce)
+
+	// The archive state and snapshot are persisted
+	archived, err := env.reopen().GetSession(ctx, sess.ID)
+	require.NoError(t, err)
+	assert.True(t, archived.Archived)
+	assert.Equal(t, types.StatusPaused, archived.Status)
+	assert.Equal(t, "+wip\n", archived.DiffSnapshot)
+	assert.Equal(t, "old", archived.Branch)
+
+	assert.Error(t, orch.ResumeSession(ctx, sess.ID))
+
+	require.NoError(t, orch.UnarchiveSession(ctx, sess.ID))
+	unarchived, err := orch.GetSession(ctx, sess.ID)
+	require.NoError(t, err)
+	assert.False(t, unarchived.Archived)
+	assert.Equal(t, types.StatusPaused, unarchived.Status)
+}
+
+func TestOutputHistorySurvivesPause(t *testing.T) {
+	ctx := context.Background()
+	_, env := newTestOrchestrator(t)
+	scrollback := "line 1\nline 2\nline 3\n"
+	env.tmux.GetPaneScrollbackFunc = func(ctx context.Context, sessionName, paneID string) (string, error) {
+		return scrollback, nil
+	}
+	history := NewOutputHistory(t.TempDir(), env.tmux, env.storage, 0, 14)
+	orch := env.reopen(WithOutputHistory(history))
+
+	sess, err := orch.CreateSession(ctx, types.CreateSessionRequest{Title: "logs", Path: "/src/app", Program: "claude"})
+	require.NoError(t, err)
+	_, err = history.Load(sess.ID)
+	assert.ErrorIs(t, err, ErrNoOutputHistory)
+
+	require.NoError(t, orch.PauseSession(ctx, sess.ID))
+	scrollback = ""
+
+	// The pane is gone, so the history comes from the recording, trimmed to whole lines
+	output, err := orch.GetOutputHistory(ctx, sess.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "line 2\nline 3\n", output)
+
+	renamed, err := orch.RenameSession(ctx, sess.ID, "logs-renamed", false)
+	require.NoError(t, err)
+	output, err = orch.GetOutputHistory(ctx, renamed.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "line 2\nline 3\n", output)
+
+	// Deleted sessions keep their history until the trash is purged
+	require.NoError(t, orch.StopSession(ctx, renamed.ID))
+	_, err = history.Load(renamed.ID)
+	assert.NoError(t, err)
+	_, err = orch.PurgeTrash(ctx, 0)
+	require.NoError(t, err)
+	_, err = history.Load(renamed.ID)
+	assert.ErrorIs(t, err, ErrNoOutputHistory)
+}
+
+func TestOutputHistoryRedactsSecrets(t *testing.T) {
+	ctx := context.Background()
+	_, env := newTestOrchestrator(t)
+	env.tmux.GetPaneScrollbackFunc = func(ctx context.Context, sessionName, paneID string) (string, error) {
+		return "export ANTHROPIC_API_KEY=sk-ant-REDACTED\n", nil
+	}
+	history := NewOutputHistory(t.TempDir(), env.tmux, env.storage, 0, DefaultOutputHistoryLimit)
+
+	require.NoError(t, history.Record(ctx, "s1"))
+	output, err := history.Load("s1")
+	require.NoError(t, err)
+	assert.NotContains(t, output, "sk-ant-api03")
+	assert.Contains(t, output, log.RedactedPlaceholder)
+}
+
+func TestTrash(t *testing.T) {
+	ctx := context.Background()
+	orch, env := newTestOrchestrator(t)
+	env.git.GetDiffPatchFunc = func(ctx context.Context, worktreePath string) (string, error) {
+		return "diff --git a/main.go b/main.go\n", nil
+	}
+
+	sess, err := orch.CreateSession(ctx, types.CreateSessionRequest{Title: "oops", Path: "/src/app", Program: "claude", Prompt: "context"})
+	require.NoError(t, err)
+	require.NoError(t, orch.StopSession(ctx, sess.ID))
+
+	// Deleted sessions leave the list but keep their record, diff and prompt
+	sessions, err := orch.ListSessions(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, sessions)
+	_, err = orch.GetSession(ctx, sess.ID)
+	assert.Error(t, err)
+
+	trash, err := orch.ListTrash(ctx)
+	require.NoError(t, err)
+	require.Len(t, trash, 1)
+	assert.Equal(t, "context", trash[0].Prompt)
+	assert.Equal(t, "diff --git a/main.go b/main.go\n", trash[0].DiffSnapshot)
+	assert.False(t, trash[0].DeletedAt.IsZero())
+
+	// Restoring puts the diff back in a worktree kept for resuming to take back
+	var applied, locked string
+	env.git.ApplyPatchFunc = func(ctx context.Context, repoPath string, patch io.Reader) error {
+		content, err := io.ReadAll(patch)
+		applied = string(content)
+		return err
+	}
+	env.git.LockWorktreeFunc = func(ctx context.Context, worktreePath, reason string) error {
+		locked = reason
+		return nil
+	}
+	require.NoError(t, orch.RestoreSession(ctx, sess.ID))
+	restored, err := orch.GetSession(ctx, sess.ID)
+	require.NoError(t, err)
+	assert.Equal(t, types.StatusPaused, restored.Status)
+	assert.Equal(t, "diff --git a/main.go b/main.go\n", applied)
+	assert.Equal(t, pausedWorktreeLockReason, locked)
+	assert.Empty(t, restored.DiffSnapshot)
+	assert.Error(t, orch.RestoreSession(ctx, sess.ID))
+
+	// Purging respects the age cutoff
+	require.NoError(t, orch.StopSession(ctx, sess.ID))
+	purged, err := orch.PurgeTrash(ctx, time.Hour)
+	require.NoError(t, err)
+	assert.Empty(t, purged)
+	purged, err = orch.PurgeTrash(ctx, 0)
+	require.NoError(t, err)
+	require.Len(t, purged, 1)
+	exists, err := env.storage.Exists(ctx, sess.ID)
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestRepositoryScope(t *testing.T) {
+	ctx := context.Background()
+	all, env := newTestOrchestrator(t)
+	env.git.GetRepositoryRootFunc = func(ctx context.Context, path string) (string, error) {
+		return strings.TrimSuffix(path, "/cmd"), nil
+	}
+
+	// Sessions are namespaced by repository root, even when created from a subdirectory
+	app, err := all.CreateSession(ctx, types.CreateSessionRequest{Title: "app", Path: "/src/app/cmd", Program: "claude"})
+	require.NoError(t, err)
+	assert.Equal(t, "/src/app", app.RepoPath)
+	lib, err := all.CreateSession(ctx, types.CreateSessionRequest{Title: "lib", Path: "/src/lib", Program: "claude"})
+	require.NoError(t, err)
+
+	scoped := env.reopen(WithRepository("/src/app"))
+	sessions, err := scoped.ListSessions(ctx)
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	assert.Equal(t, app.ID, sessions[0].ID)
+
+	_, err = scoped.GetSession(ctx, lib.ID)
+	assert.Error(t, err)
+	assert.Error(t, scoped.StopSession(ctx, lib.ID))
+
+	// Pruning by age only removes the scoped repository's sessions
+	pruned, err := scoped.PruneSessions(ctx, types.PruneRequest{OlderThan: time.Nanosecond})
+	require.NoError(t, err)
+	require.Len(t, pruned, 1)
+	exists, err := env.storage.Exists(ctx, lib.ID)
+	require.NoError(t, err)
+	assert.True(t, exists)
+}
+
+// interruptibleProcess is a process handle that exits when interrupted
+type interruptibleProcess struct {
+	signals []int
+	killed  bool
+}
+
+func (p *interruptibleProcess) PID() int { return 12345 }
+func (p *interruptibleProcess) Signal(sig int) error {
+	p.signals = append(p.signals, sig)
+	return nil
+}
+func (p *interruptibleProcess) Kill() error {
+	p.killed = true
+	return nil
+}
+func (p *interruptibleProcess) Wait() (*executor.Result, error) { return &executor.Result{}, nil }
+func (p *interruptibleProcess) State() (executor.ProcessState, error) {
+	if len(p.signals) > 0 {
+		return executor.ProcessStateExited, nil
+	}
+	return executor.ProcessStateRunning, nil
+}
+
+func TestPauseSessionInterruptsProgram(t *testing.T) {
+	ctx := context.Background()
+	orch, env := newTestOrchestrator(t)
+	killed := false
+	env.tmux.KillSessionFunc = func(ctx context.Context, sessionName string) error {
+		killed = true
+		return nil
+	}
+	process := &interruptibleProcess{}
+	env.exec.FindProcessFunc = func(ctx context.Context, pid int) (executor.ProcessHandle, error) {
+		// The program must still be running when it is interrupted
+		assert.False(t, killed)
+		assert.Equal(t, 12345, pid)
+		return process, nil
+	}
+
+	sess, err := orch.CreateSession(ctx, types.CreateSessionRequest{Title: "agent", Path: "/src/app", Program: "claude"})
+	require.NoError(t, err)
+	require.NoError(t, orch.PauseSession(ctx, sess.ID))
+
+	assert.Equal(t, []int{int(syscall.SIGINT)}, process.signals)
+	assert.False(t, process.killed)
+	assert.True(t, killed)
+}
+
+func TestPausedWorktreeLocking(t *testing.T) {
+	ctx := context.Background()
+	orch, env := newTestOrchestrator(t, WithWorktreeLayout(WorktreeLayout{Dir: t.TempDir()}))
+	var locks []string
+	env.git.LockWorktreeFunc = func(ctx context.Context, worktreePath, reason string) error {
+		locks = append(locks, "lock "+reason)
+		return nil
+	}
+	env.git.UnlockWorktreeFunc = func(ctx context.Context, worktreePath string) error {
+		locks = append(locks, "unlock")
+		return nil
+	}
+
+	sess, err := orch.CreateSession(ctx, types.CreateSessionRequest{Title: "agent", Path: "/src/app"})
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(sess.Path, 0755))
+	// A kept worktree is taken back rather than added again, which git refuses
+	var kept *git.Worktree
+	env.git.ListWorktreesFunc = func(ctx context.Context, repoPath string) ([]*git.Worktree, error) {
+		if kept == nil {
+			return nil, nil
+		}
+		return []*git.Worktree{kept}, nil
+	}
+	env.git.CreateWorktreeFunc = func(ctx context.Context, repoPath, worktreePath, branch string) (*git.Worktree, error) {
+		assert.Nil(t, kept, "worktree added over the kept one")
+		return &git.Worktree{Path: worktreePath, Branch: branch}, nil
+	}
+
+	// A clean worktree is removed, leaving nothing to lock
+	require.NoError(t, orch.PauseSession(ctx, sess.ID))
+	assert.Empty(t, locks)
+	require.NoError(t, orch.ResumeSession(ctx, sess.ID))
+	assert.Empty(t, locks)
+
+	// One with uncommitted changes is kept, locked, and unlocked again on resume
+	env.git.RemoveWorktreeFunc = func(ctx context.Context, worktreePath string, force bool) error {
+		if force {
+			return nil
+		}
+		return assert.AnError
+	}
+	require.NoError(t, orch.PauseSession(ctx, sess.ID))
+	assert.Equal(t, []string{"lock " + pausedWorktreeLockReason}, locks)
+	kept = &git.Worktree{Path: sess.Path, Branch: sess.Branch, IsLocked: true, LockReason: pausedWorktreeLockReason}
+	require.NoError(t, orch.ResumeSession(ctx, sess.ID))
+	assert.Equal(t, []string{"lock " + pausedWorktreeLockReason, "unlock"}, locks)
+
+	// Worktrees locked by someone else stay locked
+	locks = nil
+	require.NoError(t, orch.PauseSession(ctx, sess.ID))
+	kept = &git.Worktree{Path: sess.Path, Branch: sess.Branch, IsLocked: true, LockReason: "on a USB drive"}
+	require.NoError(t, orch.ResumeSession(ctx, sess.ID))
+	assert.Equal(t, []string{"lock " + pausedWorktreeLockReason}, locks)
+}
+
+func TestResumeKeptWorktree(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	ctx := context.Background()
+	gitService := git.NewGitService(executor.NewDefaultExecutor())
+	repo, err := storage.NewJSONRepository(t.TempDir())
+	require.NoError(t, err)
+	orch := NewOrchestrator(gitService, tmux.NewMockTmuxService(), repo, &executor.MockExecutor{},
+		WithWorktreeLayout(WorktreeLayout{Dir: t.TempDir()}))
+
+	sess, err := orch.CreateSession(ctx, types.CreateSessionRequest{Title: "agent", Path: newTestRepo(t), Branch: "agent"})
+	require.NoError(t, err)
+	work := filepath.Join(sess.Path, "work.txt")
+	require.NoError(t, os.WriteFile(work, []byte("uncommitted\n"), 0644))
+
+	// The worktree with uncommitted changes is kept and locked while paused
+	require.NoError(t, orch.PauseSession(ctx, sess.ID))
+	info, err := gitService.GetWorktreeInfo(ctx, sess.Path)
+	require.NoError(t, err)
+	assert.True(t, info.IsLocked)
+
+	// and taken back, changes and all, on resume
+	require.NoError(t, orch.ResumeSession(ctx, sess.ID))
+	content, err := os.ReadFile(work)
+	require.NoError(t, err)
+	assert.Equal(t, "uncommitted\n", string(content))
+	info, err = gitService.GetWorktreeInfo(ctx, sess.Path)
+	require.NoError(t, err)
+	assert.False(t, info.IsLocked)
+	assert.Equal(t, "agent", info.Branch)
+
+	// A clean one is removed and recreated
+	require.NoError(t, os.Remove(work))
+	require.NoError(t, orch.PauseSession(ctx, sess.ID))
+	assert.NoDirExists(t, sess.Path)
+	require.NoError(t, orch.ResumeSession(ctx, sess.ID))
+	assert.DirExists(t, sess.Path)
+}
+
+func TestRestoreKeepsUncommittedChanges(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	ctx := context.Background()
+	gitService := git.NewGitService(executor.NewDefaultExecutor())
+	repo, err := storage.NewJSONRepository(t.TempDir())
+	require.NoError(t, err)
+	orch := NewOrchestrator(gitService, tmux.NewMockTmuxService(), repo, &executor.MockExecutor{},
+		WithWorktreeLayout(WorktreeLayout{Dir: t.TempDir()}))
+
+	sess, err := orch.CreateSession(ctx, types.CreateSessionRequest{Title: "agent", Path: newTestRepo(t), Branch: "agent"})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(sess.Path, "README"), []byte("edited\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(sess.Path, "new.txt"), []byte("new\n"), 0644))
+
+	// Deleted while paused with its worktree kept, the changes still go with the record
+	require.NoError(t, orch.PauseSession(ctx, sess.ID))
+	require.NoError(t, orch.StopSession(ctx, sess.ID))
+	assert.NoDirExists(t, sess.Path)
+
+	require.NoError(t, orch.RestoreSession(ctx, sess.ID))
+	require.NoError(t, orch.ResumeSession(ctx, sess.ID))
+	for file, want := range map[string]string{"README": "edited\n", "new.txt": "new\n"} {
+		content, err := os.ReadFile(filepath.Join(sess.Path, file))
+		require.NoError(t, err)
+		assert.Equal(t, want, string(content))
+	}
+	info, err := gitService.GetWorktreeInfo(ctx, sess.Path)
+	require.NoError(t, err)
+	assert.False(t, info.IsLocked)
+}
+
+// exitedProcess is a process handle that has exited with the given code
+type exitedProcess struct {
+	code int
+}
+
+func (p exitedProcess) PID() int             { return 12345 }
+func (p exitedProcess) Signal(sig int) error { return nil }
+func (p exitedProcess) Kill() error          { return nil }
+func (p exitedProcess) Wait() (*executor.Result, error) {
+	return &executor.Result{ExitCode: p.code}, nil
+}
+func (p exitedProcess) State() (executor.ProcessState, error) {
+	return executor.ProcessStateExited, nil
+}
+
+func TestReplayCommands(t *testing.T) {
+	ctx := context.Background()
+	orch, env := newTestOrchestrator(t)
+	var started []executor.Command
+	env.exec.StartFunc = func(ctx context.Context, cmd executor.Command) (executor.ProcessHandle, error) {
+		started = append(started, cmd)
+		if cmd.Program == "false" {
+			return exitedProcess{code: 1}, nil
+		}
+		return exitedProcess{}, nil
+	}
+
+	sess, err := orch.CreateSession(ctx, types.CreateSessionRequest{Title: "agent", Path: "/src/app", Program: "claude"})
+	require.NoError(t, err)
+	for _, cmd := range []executor.Command{
+		{Program: "npm", Args: []string{"install"}},
+		{Program: "source .env && make setup", Shell: true},
+		{Program: "go", Args: []string{"test", "./..."}},
+	} {
+		_, err := orch.ExecInWorktree(ctx, sess.ID, cmd)
+		require.NoError(t, err)
+	}
+
+	history, err := orch.GetCommandHistory(ctx, sess.ID)
+	require.NoError(t, err)
+	require.Len(t, history, 3)
+	assert.Equal(t, "npm", history[0].Program)
+	assert.Equal(t, []string{"install"}, history[0].Args)
+	assert.True(t, history[1].Shell)
+
+	// The last two run again in order in the worktree, without being recorded again
+	started = nil
+	require.NoError(t, orch.Replay(ctx, sess.ID, 2, nil, nil))
+	require.Len(t, started, 2)
+	assert.Equal(t, "source .env && make setup", started[0].Program)
+	assert.True(t, started[0].Shell)
+	assert.Equal(t, "go", started[1].Program)
+	assert.Equal(t, sess.Path, started[1].Dir)
+	history, err = orch.GetCommandHistory(ctx, sess.ID)
+	require.NoError(t, err)
+	assert.Len(t, history, 3)
+
+	// Replaying stops at a command that fails
+	_, err = orch.ExecInWorktree(ctx, sess.ID, executor.Command{Program: "false"})
+	require.NoError(t, err)
+	_, err = orch.ExecInWorktree(ctx, sess.ID, executor.Command{Program: "true"})
+	require.NoError(t, err)
+	started = nil
+	assert.Error(t, orch.Replay(ctx, sess.ID, 2, nil, nil))
+	assert.Len(t, started, 1)
+}
+
+func TestFinishSession(t *testing.T) {
+	ctx := context.Background()
+	orch, env := newTestOrchestrator(t)
+	env.git.DefaultBranch = "main"
+	var calls []string
+	env.git.HasUncommittedChangesFunc = func(ctx context.Context, repoPath string) (bool, error) {
+		// Only the session's worktree has pending changes
+		return strings.Contains(repoPath, "-worktree-"), nil
+	}
+	env.git.CommitWithOptionsFunc = func(ctx context.Context, repoPath string, opts git.CommitOptions) (*git.CommitInfo, error) {
+		calls = append(calls, "commit "+opts.Message)
+		return &git.CommitInfo{Message: opts.Message}, nil
+	}
+	env.git.SquashMergeFunc = func(ctx context.Context, repoPath, branch, message string) error {
+		calls = append(calls, "squash "+branch+" "+message)
+		return nil
+	}
+	env.git.RemoveWorktreeFunc = func(ctx context.Context, worktreePath string, force bool) error {
+		calls = append(calls, "remove worktree")
+		return nil
+	}
+	env.git.DeleteBranchFunc = func(ctx context.Context, repoPath, branchName string, force bool) error {
+		assert.True(t, force)
+		calls = append(calls, "delete "+branchName)
+		return nil
+	}
+
+	sess, err := orch.CreateSession(ctx, types.CreateSessionRequest{Title: "auth", Path: "/src/app", Branch: "auth", Prompt: "Fix the login bug"})
+	require.NoError(t, err)
+
+	result, err := orch.FinishSession(ctx, sess.ID, types.FinishSessionRequest{Strategy: types.MergeSquash, DeleteBranch: true})
+	require.NoError(t, err)
+	assert.Equal(t, &types.FinishResult{Branch: "auth", Base: "main", Strategy: types.MergeSquash, Head: result.Head, BranchDeleted: true}, result)
+	assert.Equal(t, []string{"commit Finish auth", "squash auth Test commit", "remove worktree", "delete auth"}, calls)
+
+	// The finished session is in the trash
+	_, err = orch.GetSession(ctx, sess.ID)
+	assert.Error(t, err)
+	trash, err := orch.ListTrash(ctx)
+	require.NoError(t, err)
+	require.Len(t, trash, 1)
+}
+
+func TestFinishInPlaceSession(t *testing.T) {
+	ctx := context.Background()
+	orch, env := newTestOrchestrator(t)
+	current := "main"
+	env.git.GetCurrentBranchFunc = func(ctx context.Context, repoPath string) (*git.Branch, error) {
+		return &git.Branch{Name: current, IsCurrent: true}, nil
+	}
+	var calls []string
+	env.git.CheckoutBranchFunc = func(ctx context.Context, repoPath, branch string) error {
+		calls = append(calls, "checkout "+branch)
+		current = branch
+		return nil
+	}
+	env.git.MergeFunc = func(ctx context.Context, repoPath, branch string, opts git.MergeOptions) error {
+		calls = append(calls, "merge "+branch+" into "+current)
+		return nil
+	}
+	env.git.RebaseFunc = func(ctx context.Context, repoPath, onto string) error {
+		t.Errorf("rebased onto %s", onto)
+		return nil
+	}
+
+	sess, err := orch.CreateSession(ctx, types.CreateSessionRequest{Title: "agent", Path: "/src/app", Branch: "agent", InPlace: true})
+	require.NoError(t, err)
+	calls = nil
+
+	// The running agent's directory isn't switched to the base branch
+	_, err = orch.FinishSession(ctx, sess.ID, types.FinishSessionRequest{Base: "main"})
+	assert.ErrorContains(t, err, "pause it before finishing it")
+	require.NoError(t, orch.PauseSession(ctx, sess.ID))
+	_, err = orch.FinishSession(ctx, sess.ID, types.FinishSessionRequest{Base: "main", Strategy: types.MergeRebase})
+	assert.ErrorContains(t, err, "merge or squash")
+	assert.Empty(t, calls)
+	assert.Equal(t, "agent", current)
+
+	// Once paused it is merged, and the directory given back on the branch it was on
+	result, err := orch.FinishSession(ctx, sess.ID, types.FinishSessionRequest{Base: "main"})
+	require.NoError(t, err)
+	assert.Equal(t, "main", result.Base)
+	assert.Equal(t, []string{"checkout main", "merge agent into main", "checkout main"}, calls)
+}
+
+func TestFinishSessionChecks(t *testing.T) {
+	ctx := context.Background()
+	orch, env := newTestOrchestrator(t)
+	env.git.DefaultBranch = "main"
+	merged := false
+	env.git.MergeFunc = func(ctx context.Context, repoPath, branch string, opts git.MergeOptions) error {
+		merged = true
+		return nil
+	}
+
+	sess, err := orch.CreateSession(ctx, types.CreateSessionRequest{Title: "auth", Path: "/src/app", Branch: "auth"})
+	require.NoError(t, err)
+
+	_, err = orch.FinishSession(ctx, sess.ID, types.FinishSessionRequest{Strategy: "octopus"})
+	assert.ErrorContains(t, err, "unknown merge strategy")
+	_, err = orch.FinishSession(ctx, sess.ID, types.FinishSessionRequest{Base: "auth"})
+	assert.ErrorContains(t, err, "nothing to merge")
+
+	// The repository's own uncommitted changes would be mixed into the merge
+	env.git.HasUncommittedChangesFunc = func(ctx context.Context, repoPath string) (bool, error) {
+		return repoPath == "/src/app", nil
+	}
+	_, err = orch.FinishSession(ctx, sess.ID, types.FinishSessionRequest{})
+	assert.ErrorContains(t, err, "uncommitted changes")
+
+	env.git.HasUncommittedChangesFunc = nil
+	env.git.GetCommitsBetweenFunc = func(ctx context.Context, repoPath, base, head string) ([]*git.CommitInfo, error) {
+		return nil, nil
+	}
+	_, err = orch.FinishSession(ctx, sess.ID, types.FinishSessionRequest{})
+	assert.ErrorContains(t, err, "no commits ahead of main")
+	assert.False(t, merged)
+
+	_, err = orch.GetSession(ctx, sess.ID)
+	assert.NoError(t, err)
+}
+
+func TestFinishSessionTag(t *testing.T) {
+	ctx := context.Background()
+	orch, env := newTestOrchestrator(t)
+	env.git.DefaultBranch = "main"
+	var calls []string
+	env.git.CreateTagFunc = func(ctx context.Context, repoPath, name, ref, message string) error {
+		calls = append(calls, "tag "+name+" "+ref+" "+message)
+		return nil
+	}
+	env.git.DeleteTagFunc = func(ctx context.Context, repoPath, name string) error {
+		calls = append(calls, "delete tag "+name)
+		return nil
+	}
+	env.git.MergeFunc = func(ctx context.Context, repoPath, branch string, opts git.MergeOptions) error {
+		calls = append(calls, "merge "+branch)
+		return &git.ConflictError{Operation: "merge", Files: []string{"a.go"}}
+	}
+
+	sess, err := orch.CreateSession(ctx, types.CreateSessionRequest{Title: "Fix Auth", Path: "/src/app", Branch: "auth"})
+	require.NoError(t, err)
+
+	// A merge that fails leaves no tag behind
+	_, err = orch.FinishSession(ctx, sess.ID, types.FinishSessionRequest{Tag: true})
+	assert.Error(t, err)
+	assert.Equal(t, []string{"tag squad/fix-auth/done auth Finish Fix Auth", "merge auth", "delete tag squad/fix-auth/done"}, calls)
+
+	calls = nil
+	env.git.MergeFunc = nil
+	result, err := orch.FinishSession(ctx, sess.ID, types.FinishSessionRequest{Tag: true, TagName: "release/auth"})
+	require.NoError(t, err)
+	assert.Equal(t, "release/auth", result.Tag)
+	assert.Equal(t, []string{"tag release/auth auth Finish Fix Auth"}, calls)
+}
+
+func TestPullRequests(t *testing.T) {
+	ctx := context.Background()
+	var opened forge.CreatePROptions
+	forgeMock := &forge.MockForge{
+		CreatePRFunc: func(ctx context.Context, repoPath string, opts forge.CreatePROptions) (*forge.PullRequest, error) {
+			assert.Equal(t, "/src/app", repoPath)
+			opened = opts
+			return &forge.PullRequest{URL: "https://github.com/o/r/pull/1"}, nil
+		},
+	}
+	orch, env := newTestOrchestrator(t, WithForge(forgeMock))
+
+	sess, err := orch.CreateSession(ctx, types.CreateSessionRequest{Title: "auth", Path: "/src/app", Branch: "auth"})
+	require.NoError(t, err)
+
+	_, err = orch.GetPullRequest(ctx, sess.ID)
+	assert.ErrorIs(t, err, forge.ErrNoPullRequest)
+
+	pr, err := orch.CreatePullRequest(ctx, sess.ID, types.PullRequestRequest{Draft: true})
+	require.NoError(t, err)
+	assert.Equal(t, "https://github.com/o/r/pull/1", pr.URL)
+	assert.Equal(t, forge.CreatePROptions{Head: "auth", Base: "main", Title: "Test commit", Body: opened.Body, Draft: true}, opened)
+
+	forgeMock.AvailableFunc = func(ctx context.Context) error { return assert.AnError }
+	_, err = orch.ListChecks(ctx, sess.ID)
+	assert.ErrorIs(t, err, assert.AnError)
+
+	// Without a configured forge, the remote's host picks one
+	env.git.GetRemoteURLFunc = func(ctx context.Context, repoPath, remote string) (string, error) {
+		return "git@git.example.com:team/app.git", nil
+	}
+	env.exec.CommandExistsFunc = func(ctx context.Context, name string) bool { return name == "glab" }
+	orch = env.reopen(WithForgeHosts(map[string]string{"git.example.com": "gitlab"}))
+	env.exec.ExecuteFunc = func(ctx context.Context, cmd executor.Command) (*executor.Result, error) {
+		assert.Equal(t, []string{"glab", "mr", "view", "auth"}, append([]string{cmd.Program}, cmd.Args[:3]...))
+		return &executor.Result{Stdout: []byte(`{"iid":4,"state":"opened","source_branch":"auth"}`)}, nil
+	}
+	status, err := orch.GetPullRequest(ctx, sess.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 4, status.Number)
+}
+
+func TestCheckConflicts(t *testing.T) {
+	ctx := context.Background()
+	orch, env := newTestOrchestrator(t)
+	env.git.DefaultBranch = "main"
+	var conflicts []git.FileConflict
+	env.git.GetConflictsFunc = func(ctx context.Context, repoPath, base, branch string) ([]git.FileConflict, error) {
+		assert.Equal(t, [3]string{"/src/app", "main", "auth"}, [3]string{repoPath, base, branch})
+		return conflicts, nil
+	}
+
+	sess, err := orch.CreateSession(ctx, types.CreateSessionRequest{Title: "auth", Path: "/src/app", Branch: "auth"})
+	require.NoError(t, err)
+
+	conflicts = []git.FileConflict{{Path: "auth.go", Hunks: []git.ConflictHunk{{Line: 3, Ours: "a", Theirs: "b"}}}}
+	found, err := orch.CheckConflicts(ctx, sess.ID, "")
+	require.NoError(t, err)
+	assert.Equal(t, conflicts, found)
+	assert.True(t, sess.Conflicted)
+
+	// The flag is saved for other processes to see
+	data, err := env.storage.Get(ctx, sess.ID)
+	require.NoError(t, err)
+	assert.True(t, data.Conflicted)
+
+	conflicts = nil
+	found, err = orch.CheckConflicts(ctx, sess.ID, "main")
+	require.NoError(t, err)
+	assert.Empty(t, found)
+	data, err = env.storage.Get(ctx, sess.ID)
+	require.NoError(t, err)
+	assert.False(t, data.Conflicted)
+
+	_, err = orch.CheckConflicts(ctx, sess.ID, "auth")
+	assert.ErrorContains(t, err, "nothing to merge")
+}
+
+func TestGetTracking(t *testing.T) {
+	ctx := context.Background()
+	orch, env := newTestOrchestrator(t)
+	// The checked out branch isn't the base, the remote's default branch is
+	env.git.DefaultBranch = "feature"
+	env.git.GetDefaultBranchFunc = func(ctx context.Context, repoPath, remote string) (string, error) {
+		assert.Equal(t, [2]string{"/src/app", "origin"}, [2]string{repoPath, remote})
+		return "main", nil
+	}
+	env.git.GetAheadBehindFunc = func(ctx context.Context, repoPath, branch, base string) (int, int, error) {
+		assert.Equal(t, [3]string{"/src/app", "auth"}, [3]string{repoPath, branch})
+		if base == "develop" {
+			return 1, 0, nil
+		}
+		assert.Equal(t, "main", base)
+		return 2, 5, nil
+	}
+
+	sess, err := orch.CreateSession(ctx, types.CreateSessionRequest{Title: "auth", Path: "/src/app", Branch: "auth"})
+	require.NoError(t, err)
+	before, err := env.storage.Get(ctx, sess.ID)
+	require.NoError(t, err)
+
+	tracking, err := orch.GetTracking(ctx, sess.ID, "")
+	require.NoError(t, err)
+	assert.Equal(t, &types.BranchTracking{Base: "main", Ahead: 2, Behind: 5}, tracking)
+	tracking, err = orch.GetTracking(ctx, sess.ID, "develop")
+	require.NoError(t, err)
+	assert.Equal(t, &types.BranchTracking{Base: "develop", Ahead: 1}, tracking)
+
+	// Counting leaves the session's record alone
+	after, err := env.storage.Get(ctx, sess.ID)
+	require.NoError(t, err)
+	assert.Equal(t, before.UpdatedAt, after.UpdatedAt)
+
+	env.git.GetDefaultBranchFunc = func(ctx context.Context, repoPath, remote string) (string, error) {
+		return "", assert.AnError
+	}
+	_, err = orch.GetTracking(ctx, sess.ID, "")
+	assert.ErrorIs(t, err, assert.AnError)
+}
+
+func TestRebaseSession(t *testing.T) {
+	ctx := context.Background()
+	orch, env := newTestOrchestrator(t)
+	var calls []string
+	env.git.FetchFunc = func(ctx context.Context, repoPath, remote string) error {
+		calls = append(calls, "fetch "+remote)
+		return nil
+	}
+	env.git.GetDefaultBranchFunc = func(ctx context.Context, repoPath, remote string) (string, error) {
+		return "main", nil
+	}
+	env.git.RebaseFunc = func(ctx context.Context, repoPath, onto string) error {
+		calls = append(calls, "rebase "+onto)
+		return &git.ConflictError{Operation: "rebase", Files: []string{"auth.go"}}
+	}
+	env.git.RebaseContinueFunc = func(ctx context.Context, repoPath string) error {
+		calls = append(calls, "continue")
+		return nil
+	}
+
+	sess, err := orch.CreateSession(ctx, types.CreateSessionRequest{Title: "auth", Path: "/src/app", Branch: "auth"})
+	require.NoError(t, err)
+
+	// Conflicts are reported in the result
+	result, err := orch.RebaseSession(ctx, sess.ID, types.RebaseRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, types.RebaseConflicted, result.Status)
+	assert.Equal(t, "origin/main", result.Onto)
+	assert.Equal(t, []string{"auth.go"}, result.Conflicts)
+
+	result, err = orch.RebaseSession(ctx, sess.ID, types.RebaseRequest{Continue: true})
+	require.NoError(t, err)
+	assert.Equal(t, types.RebaseDone, result.Status)
+	assert.Empty(t, result.Conflicts)
+	assert.Equal(t, []string{"fetch origin", "rebase origin/main", "continue"}, calls)
+
+	// Other failures are errors
+	env.git.RebaseFunc = func(ctx context.Context, repoPath, onto string) error {
+		return assert.AnError
+	}
+	_, err = orch.RebaseSession(ctx, sess.ID, types.RebaseRequest{Onto: "nope"})
+	assert.ErrorIs(t, err, assert.AnError)
+	_, err = orch.RebaseSession(ctx, sess.ID, types.RebaseRequest{Continue: true, Abort: true})
+	assert.Error(t, err)
+
+	env.git.HasUncommittedChangesFunc = func(ctx context.Context, repoPath string) (bool, error) {
+		return true, nil
+	}
+	_, err = orch.RebaseSession(ctx, sess.ID, types.RebaseRequest{Onto: "main"})
+	assert.ErrorContains(t, err, "uncommitted changes")
+}
+
+func TestSubmodules(t *testing.T) {
+	ctx := context.Background()
+	orch, env := newTestOrchestrator(t, WithWorktreeLayout(WorktreeLayout{Dir: t.TempDir()}), WithSubmodules(true))
+	// Worktrees of the repository with submodules check out its .gitmodules
+	env.git.CreateWorktreeFunc = func(ctx context.Context, repoPath, worktreePath, branch string) (*git.Worktree, error) {
+		if err := os.MkdirAll(worktreePath, 0755); err != nil {
+			return nil, err
+		}
+		if repoPath == "/src/app" {
+			if err := os.WriteFile(filepath.Join(worktreePath, ".gitmodules"), nil, 0644); err != nil {
+				return nil, err
+			}
+		}
+		return &git.Worktree{Path: worktreePath, Branch: branch}, nil
+	}
+	var updated []string
+	env.git.UpdateSubmodulesFunc = func(ctx context.Context, worktreePath string) error {
+		updated = append(updated, worktreePath)
+		return nil
+	}
+
+	enabled, disabled := true, false
+	with, err := orch.CreateSession(ctx, types.CreateSessionRequest{Title: "with", Path: "/src/app", Branch: "with", Submodules: &enabled})
+	require.NoError(t, err)
+	_, err = orch.CreateSession(ctx, types.CreateSessionRequest{Title: "without", Path: "/src/app", Branch: "without", Submodules: &disabled})
+	require.NoError(t, err)
+	byDefault, err := orch.CreateSession(ctx, types.CreateSessionRequest{Title: "default", Path: "/src/app", Branch: "default"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{with.Path, byDefault.Path}, updated)
+
+	// Recreated worktrees get them again, and so do clones
+	updated = nil
+	require.NoError(t, orch.PauseSession(ctx, with.ID))
+	require.NoError(t, orch.ResumeSession(ctx, with.ID))
+	clone, err := orch.CloneSession(ctx, types.CloneSessionRequest{SourceID: with.ID})
+	require.NoError(t, err)
+	assert.Equal(t, []string{with.Path, clone.Path}, updated)
+
+	data, err := env.storage.Get(ctx, with.ID)
+	require.NoError(t, err)
+	assert.True(t, data.Submodules)
+
+	// Worktrees without .gitmodules have nothing to update
+	updated = nil
+	_, err = orch.CreateSession(ctx, types.CreateSessionRequest{Title: "plain", Path: "/src/other"})
+	require.NoError(t, err)
+	assert.Empty(t, updated)
+}
+
+func TestLFSSetup(t *testing.T) {
+	ctx := context.Background()
+	orch, env := newTestOrchestrator(t)
+	env.git.UsesLFSFunc = func(ctx context.Context, repoPath string) (bool, error) {
+		return strings.Contains(repoPath, "assets"), nil
+	}
+	var setUp []string
+	env.git.SetupLFSFunc = func(ctx context.Context, worktreePath string) error {
+		setUp = append(setUp, worktreePath)
+		return nil
+	}
+
+	sess, err := orch.CreateSession(ctx, types.CreateSessionRequest{Title: "art", Path: "/src/assets"})
+	require.NoError(t, err)
+	_, err = orch.CreateSession(ctx, types.CreateSessionRequest{Title: "code", Path: "/src/app"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{sess.Path}, setUp)
+
+	// A recreated worktree is set up again
+	require.NoError(t, orch.PauseSession(ctx, sess.ID))
+	require.NoError(t, orch.ResumeSession(ctx, sess.ID))
+	assert.Equal(t, []string{sess.Path, sess.Path}, setUp)
+}
+
+func TestTransplantSession(t *testing.T) {
+	ctx := context.Background()
+	orch, env := newTestOrchestrator(t)
+	env.git.DefaultBranch = "main"
+	var calls []string
+	env.git.GetCommitsBetweenFunc = func(ctx context.Context, repoPath, base, head string) ([]*git.CommitInfo, error) {
+		return []*git.CommitInfo{{Hash: "bbb"}, {Hash: "aaa"}}, nil
+	}
+	env.git.CherryPickFunc = func(ctx context.Context, repoPath, commit string) error {
+		calls = append(calls, "cherry-pick "+commit+" in "+filepath.Base(repoPath))
+		return nil
+	}
+	env.git.GetDiffPatchFunc = func(ctx context.Context, repoPath string) (string, error) {
+		return "diff --git a/x b/x\n", nil
+	}
+	env.git.ApplyPatchFunc = func(ctx context.Context, repoPath string, patch io.Reader) error {
+		content, err := io.ReadAll(patch)
+		calls = append(calls, "apply "+string(content)+"in "+filepath.Base(repoPath))
+		return err
+	}
+
+	auth, err := orch.CreateSession(ctx, types.CreateSessionRequest{Title: "auth", Path: "/src/app", Branch: "auth"})
+	require.NoError(t, err)
+	api, err := orch.CreateSession(ctx, types.CreateSessionRequest{Title: "api", Path: "/src/app", Branch: "api"})
+	require.NoError(t, err)
+
+	// Onto the repository's checkout, every commit main lacks is picked
+	result, err := orch.TransplantSession(ctx, auth.ID, types.TransplantRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, &types.TransplantResult{Path: "/src/app", Branch: "main", Commits: []string{"bbb", "aaa"}}, result)
+
+	result, err = orch.TransplantSession(ctx, auth.ID, types.TransplantRequest{TargetID: api.ID, Commits: []string{"c1", "c2"}})
+	require.NoError(t, err)
+	assert.Equal(t, &types.TransplantResult{Path: api.Path, Branch: "api"}, result)
+
+	result, err = orch.TransplantSession(ctx, auth.ID, types.TransplantRequest{TargetID: api.ID, Uncommitted: true})
+	require.NoError(t, err)
+	assert.True(t, result.Patched)
+
+	apiDir := filepath.Base(api.Path)
+	assert.Equal(t, []string{
+		"cherry-pick main..auth in app",
+		"cherry-pick c1 in " + apiDir,
+		"cherry-pick c2 in " + apiDir,
+		"apply diff --git a/x b/x\nin " + apiDir,
+	}, calls)
+
+	_, err = orch.TransplantSession(ctx, auth.ID, types.TransplantRequest{TargetID: auth.ID})
+	assert.ErrorContains(t, err, "onto itself")
+	require.NoError(t, orch.PauseSession(ctx, api.ID))
+	_, err = orch.TransplantSession(ctx, auth.ID, types.TransplantRequest{TargetID: api.ID})
+	assert.ErrorContains(t, err, "paused")
+
+	// A conflict is passed on as it is
+	env.git.CherryPickFunc = func(ctx context.Context, repoPath, commit string) error {
+		return &git.ConflictError{Operation: "cherry-pick", Files: []string{"x"}}
+	}
+	_, err = orch.TransplantSession(ctx, auth.ID, types.TransplantRequest{})
+	var conflict *git.ConflictError
+	assert.ErrorAs(t, err, &conflict)
+}
+
+func TestCommitSettings(t *testing.T) {
+	ctx := context.Background()
+	enabled, disabled := true, false
+	orch, env := newTestOrchestrator(t, WithCommitSettings(types.CommitSettings{Sign: &enabled, SigningKey: "ABCD", RunHooks: &disabled}))
+	var made []git.CommitOptions
+	env.git.CommitWithOptionsFunc = func(ctx context.Context, repoPath string, opts git.CommitOptions) (*git.CommitInfo, error) {
+		made = append(made, opts)
+		return &git.CommitInfo{Message: opts.Message}, nil
+	}
+
+	sess, err := orch.CreateSession(ctx, types.CreateSessionRequest{Title: "auth", Path: "/src/app"})
+	require.NoError(t, err)
+	_, err = orch.CommitSession(ctx, sess.ID, git.CommitOptions{Message: "one"})
+	require.NoError(t, err)
+
+	// The session's own settings win over the global ones they set
+	require.NoError(t, orch.SetCommitSettings(ctx, sess.ID, &types.CommitSettings{
+		Sign: &disabled, RunHooks: &enabled, Author: "Agent <agent@example.com>",
+	}))
+	_, err = orch.CommitSession(ctx, sess.ID, git.CommitOptions{Message: "two"})
+	require.NoError(t, err)
+	// and what a commit asks for wins over both
+	_, err = orch.CommitSession(ctx, sess.ID, git.CommitOptions{Message: "three", Sign: true, Author: "Me <me@example.com>"})
+	require.NoError(t, err)
+
+	assert.Equal(t, []git.CommitOptions{
+		{Message: "one", Sign: true, SigningKey: "ABCD"},
+		{Message: "two", RunHooks: true, SigningKey: "ABCD", Author: "Agent <agent@example.com>"},
+		{Message: "three", RunHooks: true, Sign: true, SigningKey: "ABCD", Author: "Me <me@example.com>"},
+	}, made)
+
+	data, err := env.storage.Get(ctx, sess.ID)
+	require.NoError(t, err)
+	require.NotNil(t, data.CommitSettings)
+	assert.Equal(t, "Agent <agent@example.com>", data.CommitSettings.Author)
+
+	require.NoError(t, orch.SetCommitSettings(ctx, sess.ID, nil))
+	data, err = env.storage.Get(ctx, sess.ID)
+	require.NoError(t, err)
+	assert.Nil(t, data.CommitSettings)
+
+	assert.ErrorContains(t, orch.SetCommitSettings(ctx, sess.ID, &types.CommitSettings{Author: "agent"}), "Name <email>")
+	assert.ErrorContains(t, orch.SetCommitSettings(ctx, sess.ID, &types.CommitSettings{SigningFormat: "pgp"}), "signing format")
+}
+
+func TestCheckpoints(t *testing.T) {
+	ctx := context.Background()
+	orch, env := newTestOrchestrator(t, WithCheckpointInterval(10*time.Millisecond))
+	dirty := true
+	env.git.HasUncommittedChangesFunc = func(ctx context.Context, repoPath string) (bool, error) {
+		return dirty, nil
+	}
+	var mu sync.Mutex
+	var committed []string
+	env.git.CommitWithOptionsFunc = func(ctx context.Context, repoPath string, opts git.CommitOptions) (*git.CommitInfo, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		assert.True(t, opts.StageAll)
+		assert.True(t, strings.HasPrefix(opts.Message, CheckpointPrefix), opts.Message)
+		committed = append(committed, repoPath)
+		return &git.CommitInfo{Message: opts.Message}, nil
+	}
+
+	running, err := orch.CreateSession(ctx, types.CreateSessionRequest{Title: "running", Path: "/src/app", Branch: "running"})
+	require.NoError(t, err)
+	paused, err := orch.CreateSession(ctx, types.CreateSessionRequest{Title: "paused", Path: "/src/app", Branch: "paused"})
+	require.NoError(t, err)
+	require.NoError(t, orch.PauseSession(ctx, paused.ID))
+
+	commit, err := orch.Checkpoint(ctx, running.ID)
+	require.NoError(t, err)
+	require.NotNil(t, commit)
+	_, err = orch.Checkpoint(ctx, paused.ID)
+	assert.ErrorContains(t, err, "paused")
+
+	// Only sessions that aren't paused are checkpointed, and only when they have changes
+	committed = nil
+	runCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		orch.RunCheckpoints(runCtx)
+		close(done)
+	}()
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(committed) > 0
+	}, time.Second, 5*time.Millisecond)
+	cancel()
+	<-done
+	for _, path := range committed {
+		assert.Equal(t, running.Path, path)
+	}
+
+	dirty = false
+	commit, err = orch.Checkpoint(ctx, running.ID)
+	require.NoError(t, err)
+	assert.Nil(t, commit)
+
+	// Without an interval, there is nothing to run
+	env.reopen().RunCheckpoints(ctx)
+}
+
+func TestDiffHistory(t *testing.T) {
+	ctx := context.Background()
+	orch, env := newTestOrchestrator(t, WithDiffHistory(time.Minute, true))
+	stats := &git.DiffStats{FilesChanged: 1, Insertions: 3, Deletions: 1}
+	env.git.GetDiffStatsFunc = func(ctx context.Context, repoPath string) (*git.DiffStats, error) {
+		return stats, nil
+	}
+	env.git.GetDiffPatchFunc = func(ctx context.Context, repoPath string) (string, error) {
+		return fmt.Sprintf("+%d -%d", stats.Insertions, stats.Deletions), nil
+	}
+
+	sess, err := orch.CreateSession(ctx, types.CreateSessionRequest{Title: "auth", Path: "/src/app"})
+	require.NoError(t, err)
+
+	sample, err := orch.RecordDiff(ctx, sess.ID)
+	require.NoError(t, err)
+	require.NotNil(t, sample)
+	assert.Equal(t, "+3 -1", sample.Patch)
+
+	// Unchanged diffs aren't recorded again
+	sample, err = orch.RecordDiff(ctx, sess.ID)
+	require.NoError(t, err)
+	assert.Nil(t, sample)
+
+	between := time.Now()
+	time.Sleep(10 * time.Millisecond)
+	stats = &git.DiffStats{FilesChanged: 2, Insertions: 10, Deletions: 4}
+	sample, err = orch.RecordDiff(ctx, sess.ID)
+	require.NoError(t, err)
+	require.NotNil(t, sample)
+
+	history, err := orch.GetDiffHistory(ctx, sess.ID, time.Time{})
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.Equal(t, 3, history[0].Added)
+	assert.Equal(t, 2, history[1].Files)
+
+	history, err = orch.GetDiffHistory(ctx, sess.ID, between)
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, "+10 -4", history[0].Patch)
+
+	// Only the latest samples keep their patches
+	var long []types.DiffSample
+	for i := 0; i < maxDiffPatches+5; i++ {
+		long = appendDiffSample(long, types.DiffSample{Added: i, Patch: "patch"})
+	}
+	assert.Empty(t, long[4].Patch)
+	assert.Equal(t, "patch", long[5].Patch)
+}
+
+func TestSharedBranches(t *testing.T) {
+	ctx := context.Background()
+	orch, env := newTestOrchestrator(t)
+	env.git.DefaultBranch = "main"
+	branches := map[string]bool{"main": true, "feature": true, "feature-2": true}
+	env.git.BranchExistsFunc = func(ctx context.Context, repoPath, branch string) (bool, error) {
+		return branches[branch], nil
+	}
+	var forked []string
+	env.git.CreateBranchFromFunc = func(ctx context.Context, repoPath, branch, startPoint string) error {
+		forked = append(forked, branch+" from "+startPoint)
+		branches[branch] = true
+		return nil
+	}
+
+	first, err := orch.CreateSession(ctx, types.CreateSessionRequest{Title: "first", Path: "/src/app", Branch: "feature", ReuseBranch: true})
+	require.NoError(t, err)
+
+	// A second session on the branch is refused, or forked onto a new one
+	_, err = orch.CreateSession(ctx, types.CreateSessionRequest{Title: "second", Path: "/src/app", Branch: "feature", ReuseBranch: true})
+	var inUse *BranchInUseError
+	require.ErrorAs(t, err, &inUse)
+	assert.Equal(t, "feature", inUse.Branch)
+	assert.Equal(t, []string{"first"}, inUse.Sessions)
+	second, err := orch.CreateSession(ctx, types.CreateSessionRequest{Title: "second", Path: "/src/app", Branch: "feature", ReuseBranch: true, ForkBranch: true})
+	require.NoError(t, err)
+	assert.Equal(t, "feature-3", second.Branch)
+	assert.Equal(t, []string{"feature-3 from feature"}, forked)
+
+	// So is one on the current branch when it's checked out outside of any session
+	env.git.GetBranchWorktreeFunc = func(ctx context.Context, repoPath, branch string) (*git.Worktree, error) {
+		return &git.Worktree{Path: "/src/app", Branch: branch}, nil
+	}
+	_, err = orch.CreateSession(ctx, types.CreateSessionRequest{Title: "third", Path: "/src/app"})
+	require.ErrorAs(t, err, &inUse)
+	assert.Equal(t, "/src/app", inUse.Worktree)
+	env.git.GetBranchWorktreeFunc = nil
+
+	overlaps, err := orch.FindOverlaps(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, overlaps)
+
+	// Sessions that came to share a branch some other way, like being imported, are
+	// found, and a paused one isn't resumed while the other runs
+	require.NoError(t, orch.PauseSession(ctx, first.ID))
+	data, err := env.storage.Get(ctx, second.ID)
+	require.NoError(t, err)
+	data.Branch = "feature"
+	require.NoError(t, env.storage.Update(ctx, data))
+	orch = env.reopen()
+
+	overlaps, err = orch.FindOverlaps(ctx)
+	require.NoError(t, err)
+	require.Len(t, overlaps, 1)
+	assert.Equal(t, "feature", overlaps[0].Branch)
+	assert.ElementsMatch(t, []string{first.ID, second.ID}, overlaps[0].SessionIDs)
+
+	err = orch.ResumeSession(ctx, first.ID)
+	require.ErrorAs(t, err, &inUse)
+	assert.Equal(t, []string{"second"}, inUse.Sessions)
+}
+
+func TestInPlaceSessions(t *testing.T) {
+	ctx := context.Background()
+	orch, env := newTestOrchestrator(t)
+	current := "main"
+	env.git.GetCurrentBranchFunc = func(ctx context.Context, repoPath string) (*git.Branch, error) {
+		return &git.Branch{Name: current, IsCurrent: true}, nil
+	}
+	env.git.CheckoutBranchFunc = func(ctx context.Context, repoPath, branch string) error {
+		assert.Equal(t, "/src/app", repoPath)
+		current = branch
+		return nil
+	}
+	env.git.StashChangesFunc = func(ctx context.Context, repoPath, message string) (string, error) {
+		return "abc123", nil
+	}
+	var restored []string
+	env.git.RestoreStashFunc = func(ctx context.Context, repoPath, stash string) error {
+		restored = append(restored, stash)
+		return nil
+	}
+	dirty := false
+	env.git.HasUncommittedChangesFunc = func(ctx context.Context, repoPath string) (bool, error) {
+		return dirty, nil
+	}
+	// The directory is never made or removed as a worktree
+	env.git.CreateWorktreeFunc = func(ctx context.Context, repoPath, worktreePath, branch string) (*git.Worktree, error) {
+		t.Errorf("worktree created at %s", worktreePath)
+		return nil, fmt.Errorf("unexpected")
+	}
+	env.git.RemoveWorktreeFunc = func(ctx context.Context, worktreePath string, force bool) error {
+		t.Errorf("worktree removed at %s", worktreePath)
+		return nil
+	}
+	var dirs []string
+	env.tmux.CreateSessionFunc = func(ctx context.Context, name, workDir, program string) (*tmux.Session, error) {
+		dirs = append(dirs, workDir)
+		return &tmux.Session{Name: name}, nil
+	}
+
+	_, err := orch.CreateSession(ctx, types.CreateSessionRequest{Title: "stashed", Path: "/src/app", Stash: true})
+	assert.ErrorContains(t, err, "only in-place sessions")
+
+	sess, err := orch.CreateSession(ctx, types.CreateSessionRequest{Title: "agent", Path: "/src/app", Branch: "agent", InPlace: true, Stash: true})
+	require.NoError(t, err)
+	assert.Equal(t, "/src/app", sess.Path)
+	assert.Equal(t, &types.InPlace{RestoreBranch: "main", Stash: "abc123"}, sess.InPlace)
+	assert.Equal(t, "agent", current)
+	assert.Equal(t, []string{"/src/app"}, dirs)
+
+	// One in-place session per checkout
+	_, err = orch.CreateSession(ctx, types.CreateSessionRequest{Title: "other", Path: "/src/app", InPlace: true})
+	assert.ErrorContains(t, err, "session 'agent' already works in /src/app")
+
+	// Resuming doesn't switch back to the session's branch by itself
+	require.NoError(t, orch.PauseSession(ctx, sess.ID))
+	current = "main"
+	assert.ErrorContains(t, orch.ResumeSession(ctx, sess.ID), "check out agent there first")
+	current = "agent"
+	require.NoError(t, orch.ResumeSession(ctx, sess.ID))
+	assert.Equal(t, []string{"/src/app", "/src/app"}, dirs)
+
+	// Deleting it gives the directory back as it was found, once the agent's changes are
+	// committed
+	dirty = true
+	require.NoError(t, orch.StopSession(ctx, sess.ID))
+	assert.Equal(t, "agent", current)
+	assert.Empty(t, restored)
+	data, err := env.storage.Get(ctx, sess.ID)
+	require.NoError(t, err)
+	assert.Equal(t, &types.InPlace{RestoreBranch: "main", Stash: "abc123"}, data.InPlace)
+
+	dirty = false
+	require.NoError(t, orch.RestoreSession(ctx, sess.ID))
+	require.NoError(t, orch.StopSession(ctx, sess.ID))
+	assert.Equal(t, "main", current)
+	assert.Equal(t, []string{"abc123"}, restored)
+	data, err = env.storage.Get(ctx, sess.ID)
+	require.NoError(t, err)
+	assert.Equal(t, &types.InPlace{}, data.InPlace)
+}
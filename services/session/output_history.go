@@ -0,0 +1,229 @@
+package session
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"claude-squad/log"
+	"claude-squad/services/storage"
+	"claude-squad/services/tmux"
+	"claude-squad/services/types"
+)
+
+const (
+	// DefaultOutputHistoryInterval is how often running sessions' scrollback is recorded
+	DefaultOutputHistoryInterval = 30 * time.Second
+	// DefaultOutputHistoryLimit is how much of the end of each session's scrollback is kept
+	DefaultOutputHistoryLimit = 1 << 20
+)
+
+// ErrNoOutputHistory is returned by OutputHistory.Load for sessions never recorded
+var ErrNoOutputHistory = errors.New("no output history recorded")
+
+// OutputHistory keeps a compressed copy of the end of each session's pane scrollback on
+// disk, so output can still be read once the tmux session is gone, e.g. after a pause,
+// archive or crash. Running sessions are recorded periodically while the history is
+// started, and the orchestrator records a session just before it kills its pane.
+type OutputHistory struct {
+	dir         string
+	tmuxService tmux.TmuxService
+	storage     storage.StorageRepository
+	interval    time.Duration
+	limit       int
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu sync.Mutex
+	// recorded holds a hash of the last snapshot written per session, to skip rewriting
+	// output that hasn't changed
+	recorded map[string][sha256.Size]byte
+}
+
+// NewOutputHistory creates a history that stores snapshots in dir, keeping the last
+// limit bytes of each session's scrollback. Nothing is recorded periodically until Start.
+func NewOutputHistory(dir string, tmuxService tmux.TmuxService, storage storage.StorageRepository, interval time.Duration, limit int) *OutputHistory {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &OutputHistory{
+		dir:         dir,
+		tmuxService: tmuxService,
+		storage:     storage,
+		interval:    interval,
+		limit:       limit,
+		ctx:         ctx,
+		cancel:      cancel,
+		recorded:    make(map[string][sha256.Size]byte),
+	}
+}
+
+// Start records every running session's scrollback in the background until Close
+func (h *OutputHistory) Start() {
+	if h.interval <= 0 {
+		return
+	}
+
+	h.wg.Add(1)
+	go func() {
+		defer h.wg.Done()
+
+		ticker := time.NewTicker(h.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-h.ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			h.recordAll(h.ctx)
+		}
+	}()
+}
+
+// Close stops background recording
+func (h *OutputHistory) Close() {
+	h.cancel()
+	h.wg.Wait()
+}
+
+// recordAll records every session that still has a pane. Failures are skipped, since
+// panes come and go between listing and capturing.
+func (h *OutputHistory) recordAll(ctx context.Context) {
+	sessions, err := h.storage.List(ctx, nil)
+	if err != nil {
+		return
+	}
+	for _, session := range sessions {
+		if session.Status == types.StatusPaused {
+			continue
+		}
+		_ = h.Record(ctx, session.ID)
+	}
+}
+
+// Record captures a session's scrollback now and stores it
+func (h *OutputHistory) Record(ctx context.Context, sessionID string) error {
+	output, err := h.tmuxService.GetPaneScrollback(ctx, sessionID, "0")
+	if err != nil {
+		return fmt.Errorf("failed to capture history: %w", err)
+	}
+	return h.save(sessionID, output)
+}
+
+// save stores output as a session's history, keeping only the last limit bytes
+func (h *OutputHistory) save(sessionID, output string) error {
+	// Scrollback often echoes credentials, which shouldn't outlive the pane on disk
+	output = log.Redact(output)
+	if h.limit > 0 && len(output) > h.limit {
+		cut := len(output) - h.limit
+		partial := output[cut-1] != '\n'
+		output = output[cut:]
+		// Drop the partial line left at the cut
+		if i := strings.IndexByte(output, '\n'); partial && i >= 0 {
+			output = output[i+1:]
+		}
+	}
+
+	sum := sha256.Sum256([]byte(output))
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.recorded[sessionID] == sum {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write([]byte(output)); err != nil {
+		return fmt.Errorf("failed to compress history: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("failed to compress history: %w", err)
+	}
+
+	if err := os.MkdirAll(h.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create history directory: %w", err)
+	}
+	// Write to a temporary file and rename it so readers never see a partial snapshot
+	tmp, err := os.CreateTemp(h.dir, "."+sessionID+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to write history: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(buf.Bytes()); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write history: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write history: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), h.path(sessionID)); err != nil {
+		return fmt.Errorf("failed to write history: %w", err)
+	}
+
+	h.recorded[sessionID] = sum
+	return nil
+}
+
+// Load returns the last recorded scrollback of a session, or ErrNoOutputHistory
+func (h *OutputHistory) Load(sessionID string) (string, error) {
+	f, err := os.Open(h.path(sessionID))
+	if os.IsNotExist(err) {
+		return "", ErrNoOutputHistory
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read history: %w", err)
+	}
+	defer f.Close()
+
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		return "", fmt.Errorf("failed to read history: %w", err)
+	}
+	defer zr.Close()
+	data, err := io.ReadAll(zr)
+	if err != nil {
+		return "", fmt.Errorf("failed to read history: %w", err)
+	}
+	return string(data), nil
+}
+
+// Rename moves a session's history to its new ID
+func (h *OutputHistory) Rename(oldID, newID string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if err := os.Rename(h.path(oldID), h.path(newID)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to rename history: %w", err)
+	}
+	if sum, ok := h.recorded[oldID]; ok {
+		h.recorded[newID] = sum
+		delete(h.recorded, oldID)
+	}
+	return nil
+}
+
+// Delete removes a session's history
+func (h *OutputHistory) Delete(sessionID string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	delete(h.recorded, sessionID)
+	if err := os.Remove(h.path(sessionID)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete history: %w", err)
+	}
+	return nil
+}
+
+func (h *OutputHistory) path(sessionID string) string {
+	return filepath.Join(h.dir, sessionID+".log.gz")
+}
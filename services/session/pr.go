@@ -0,0 +1,151 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"claude-squad/services/forge"
+	"claude-squad/services/git"
+	"claude-squad/services/types"
+)
+
+// maxPRTitleLength keeps generated titles within what forges display untruncated
+const maxPRTitleLength = 72
+
+func (o *orchestratorImpl) CreatePullRequest(ctx context.Context, sessionID string, req types.PullRequestRequest) (*types.PullRequest, error) {
+	session, err := o.GetSession(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if req.Remote == "" {
+		req.Remote = "origin"
+	}
+
+	repoPath := repoPathOf(session)
+	f, err := o.forgeFor(ctx, repoPath, req.Remote)
+	if err != nil {
+		return nil, err
+	}
+	if req.Base == "" {
+		if req.Base, err = o.gitService.GetDefaultBranch(ctx, repoPath, req.Remote); err != nil {
+			return nil, fmt.Errorf("failed to determine base branch, pass one explicitly: %w", err)
+		}
+	}
+
+	commits, err := o.gitService.GetCommitsBetween(ctx, repoPath, req.Remote+"/"+req.Base, session.Branch)
+	if err != nil {
+		return nil, err
+	}
+	if len(commits) == 0 {
+		return nil, fmt.Errorf("branch %s has no commits ahead of %s", session.Branch, req.Base)
+	}
+	if req.Title == "" {
+		req.Title = pullRequestTitle(session, commits)
+	}
+	if req.Body == "" {
+		req.Body = pullRequestBody(session, commits)
+	}
+
+	if err := o.gitService.Push(ctx, repoPath, session.Branch, git.PushOptions{Remote: req.Remote}); err != nil {
+		return nil, err
+	}
+
+	pr, err := f.CreatePR(ctx, repoPath, forge.CreatePROptions{
+		Head:  session.Branch,
+		Base:  req.Base,
+		Title: req.Title,
+		Body:  req.Body,
+		Draft: req.Draft,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &types.PullRequest{
+		URL:    pr.URL,
+		Title:  req.Title,
+		Branch: session.Branch,
+		Base:   req.Base,
+	}, nil
+}
+
+func (o *orchestratorImpl) GetPullRequest(ctx context.Context, sessionID string) (*forge.PullRequest, error) {
+	session, err := o.GetSession(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	f, err := o.forgeFor(ctx, repoPathOf(session), "origin")
+	if err != nil {
+		return nil, err
+	}
+	return f.GetPRStatus(ctx, repoPathOf(session), session.Branch)
+}
+
+func (o *orchestratorImpl) ListChecks(ctx context.Context, sessionID string) ([]forge.Check, error) {
+	session, err := o.GetSession(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	f, err := o.forgeFor(ctx, repoPathOf(session), "origin")
+	if err != nil {
+		return nil, err
+	}
+	return f.ListChecks(ctx, repoPathOf(session), session.Branch)
+}
+
+// forgeFor returns the forge hosting the repository at repoPath behind remote, detected
+// from the remote's URL unless one was configured, once it's ready to be used
+func (o *orchestratorImpl) forgeFor(ctx context.Context, repoPath, remote string) (forge.Forge, error) {
+	f := o.forge
+	if f == nil {
+		remoteURL, err := o.gitService.GetRemoteURL(ctx, repoPath, remote)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find the forge of remote %s: %w", remote, err)
+		}
+		f = forge.New(forge.Detect(remoteURL, o.forgeHosts), remoteURL, o.executor)
+	}
+	if err := f.Available(ctx); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// pullRequestTitle uses the subject of a single commit, or else the first line of the
+// session prompt, falling back to the session title
+func pullRequestTitle(session *types.Session, commits []*git.CommitInfo) string {
+	title := session.Title
+	switch {
+	case len(commits) == 1:
+		title = commits[0].Message
+	case strings.TrimSpace(session.Prompt) != "":
+		title, _, _ = strings.Cut(strings.TrimSpace(session.Prompt), "\n")
+	}
+
+	title = strings.TrimSpace(title)
+	if len(title) > maxPRTitleLength {
+		title = strings.TrimSpace(title[:maxPRTitleLength-3]) + "..."
+	}
+	return title
+}
+
+// pullRequestBody quotes the session prompt and lists the branch's commits, oldest first
+func pullRequestBody(session *types.Session, commits []*git.CommitInfo) string {
+	var b strings.Builder
+	if prompt := strings.TrimSpace(session.Prompt); prompt != "" {
+		b.WriteString("## Prompt\n\n")
+		for _, line := range strings.Split(prompt, "\n") {
+			b.WriteString(strings.TrimRight("> "+line, " ") + "\n")
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("## Commits\n\n")
+	for i := len(commits) - 1; i >= 0; i-- {
+		hash := commits[i].Hash
+		if len(hash) > 7 {
+			hash = hash[:7]
+		}
+		fmt.Fprintf(&b, "- %s %s\n", hash, commits[i].Message)
+	}
+	return b.String()
+}
@@ -0,0 +1,72 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"claude-squad/services/git"
+	"claude-squad/services/types"
+)
+
+func (o *orchestratorImpl) RebaseSession(ctx context.Context, sessionID string, req types.RebaseRequest) (*types.RebaseResult, error) {
+	session, err := o.GetSession(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if req.Continue && req.Abort {
+		return nil, fmt.Errorf("a rebase can't be both continued and aborted")
+	}
+	// Paused sessions have no worktree for their branch to be rebased in
+	if session.Status == types.StatusPaused {
+		return nil, fmt.Errorf("session is paused, resume it to rebase its branch")
+	}
+
+	result := &types.RebaseResult{Branch: session.Branch, Status: types.RebaseDone}
+	switch {
+	case req.Abort:
+		if err := o.gitService.RebaseAbort(ctx, session.Path); err != nil {
+			return nil, err
+		}
+		result.Status = types.RebaseAborted
+	case req.Continue:
+		err = o.gitService.RebaseContinue(ctx, session.Path)
+	default:
+		if req.Onto == "" {
+			if req.Remote == "" {
+				req.Remote = "origin"
+			}
+			if err := o.gitService.Fetch(ctx, session.Path, req.Remote); err != nil {
+				return nil, err
+			}
+			branch, err := o.gitService.GetDefaultBranch(ctx, session.Path, req.Remote)
+			if err != nil {
+				return nil, fmt.Errorf("failed to determine branch to rebase onto, pass one explicitly: %w", err)
+			}
+			req.Onto = req.Remote + "/" + branch
+		}
+		if dirty, err := o.gitService.HasUncommittedChanges(ctx, session.Path); err != nil {
+			return nil, err
+		} else if dirty {
+			return nil, fmt.Errorf("session has uncommitted changes, commit them first")
+		}
+		result.Onto = req.Onto
+		err = o.gitService.Rebase(ctx, session.Path, req.Onto)
+	}
+
+	var conflict *git.ConflictError
+	if errors.As(err, &conflict) {
+		result.Status = types.RebaseConflicted
+		result.Conflicts = conflict.Files
+	} else if err != nil {
+		return nil, err
+	}
+	if result.Status != types.RebaseAborted {
+		o.setConflicted(ctx, session, result.Status == types.RebaseConflicted)
+	}
+
+	if head, err := o.gitService.GetLastCommit(ctx, session.Path); err == nil {
+		result.Head = head.Hash
+	}
+	return result, nil
+}
@@ -0,0 +1,29 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// WithSubmodules initializes the submodules of every session's worktree, recursively,
+// each time it is created, unless the session is created without. Worktrees otherwise
+// start with empty submodule directories.
+func WithSubmodules(enabled bool) OrchestratorOption {
+	return func(o *orchestratorImpl) {
+		o.submodules = enabled
+	}
+}
+
+// updateSubmodules initializes the submodules of the worktree at worktreePath, if it has
+// any. Failing to only warns: the session is still usable, and GetSubmoduleStatus shows
+// what is missing.
+func (o *orchestratorImpl) updateSubmodules(ctx context.Context, worktreePath string) {
+	if _, err := os.Stat(filepath.Join(worktreePath, ".gitmodules")); err != nil {
+		return
+	}
+	if err := o.gitService.UpdateSubmodules(ctx, worktreePath); err != nil {
+		fmt.Printf("warning: %v\n", err)
+	}
+}
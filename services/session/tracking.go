@@ -0,0 +1,34 @@
+package session
+
+import (
+	"context"
+	"fmt"
+
+	"claude-squad/services/types"
+)
+
+// trackingRemote is the remote whose default branch sessions are compared with when no
+// base is given
+const trackingRemote = "origin"
+
+func (o *orchestratorImpl) GetTracking(ctx context.Context, sessionID, base string) (*types.BranchTracking, error) {
+	session, err := o.GetSession(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Branches live in the repository, so this works for paused sessions too
+	repoPath := repoPathOf(session)
+	if base == "" {
+		if base, err = o.gitService.GetDefaultBranch(ctx, repoPath, trackingRemote); err != nil {
+			return nil, fmt.Errorf("failed to determine base branch, pass one explicitly: %w", err)
+		}
+	}
+
+	ahead, behind, err := o.gitService.GetAheadBehind(ctx, repoPath, session.Branch, base)
+	if err != nil {
+		return nil, err
+	}
+	// Not saved, so listing sessions doesn't rewrite them or restart their retention clocks
+	return &types.BranchTracking{Base: base, Ahead: ahead, Behind: behind}, nil
+}
@@ -0,0 +1,246 @@
+package session
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+
+	"claude-squad/log"
+	"claude-squad/services/types"
+)
+
+const (
+	exportManifestName  = "manifest.json"
+	exportBundleDir     = "bundles"
+	exportFormatVersion = 1
+)
+
+// exportManifest is the index of a session export archive
+type exportManifest struct {
+	Version  int               `json:"version"`
+	Sessions []exportedSession `json:"sessions"`
+}
+
+// exportedSession is one session in an export archive. Bundle is the archive path of a
+// git bundle holding the session's branch, if one was included.
+type exportedSession struct {
+	Session *types.SessionData `json:"session"`
+	Bundle  string             `json:"bundle,omitempty"`
+}
+
+func (o *orchestratorImpl) ExportSessions(ctx context.Context, sessionIDs []string, w io.Writer, includeBundles bool) error {
+	tmpDir, err := os.MkdirTemp("", "claudesquad-export-")
+	if err != nil {
+		return fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	manifest := exportManifest{Version: exportFormatVersion}
+	bundles := make(map[string]string) // archive path -> local file
+	for _, id := range sessionIDs {
+		session, err := o.GetSession(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		data := sessionToData(session)
+		data.RepoPath = repoPathOf(session)
+		entry := exportedSession{Session: data}
+
+		if includeBundles {
+			if dirty, err := o.gitService.HasUncommittedChanges(ctx, session.Path); err == nil && dirty {
+				fmt.Printf("warning: session '%s' has uncommitted changes that are not exported\n", session.Title)
+			}
+
+			local := filepath.Join(tmpDir, session.ID+".bundle")
+			if err := o.gitService.CreateBundle(ctx, data.RepoPath, local, session.Branch); err != nil {
+				return fmt.Errorf("failed to bundle session '%s': %w", session.Title, err)
+			}
+			entry.Bundle = path.Join(exportBundleDir, session.ID+".bundle")
+			bundles[entry.Bundle] = local
+		}
+
+		manifest.Sessions = append(manifest.Sessions, entry)
+	}
+
+	manifestData, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal manifest: %w", err)
+	}
+	// Exports are made to be handed to others, so mask credentials that may have been
+	// pasted into prompts or metadata, as archives do
+	manifestData = []byte(log.Redact(string(manifestData)))
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	if err := writeTarFile(tw, exportManifestName, manifestData); err != nil {
+		return err
+	}
+	for _, entry := range manifest.Sessions {
+		if entry.Bundle == "" {
+			continue
+		}
+		data, err := os.ReadFile(bundles[entry.Bundle])
+		if err != nil {
+			return fmt.Errorf("failed to read bundle: %w", err)
+		}
+		if err := writeTarFile(tw, entry.Bundle, data); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("failed to finish archive: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("failed to finish archive: %w", err)
+	}
+	return nil
+}
+
+func (o *orchestratorImpl) ImportSessions(ctx context.Context, r io.Reader, repoPath string) ([]*types.Session, error) {
+	tmpDir, err := os.MkdirTemp("", "claudesquad-import-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	manifest, err := extractExport(r, tmpDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var imported []*types.Session
+	var errs []error
+	for _, entry := range manifest.Sessions {
+		session, err := o.importSession(ctx, entry, tmpDir, repoPath)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("session '%s': %w", entry.Session.Title, err))
+			continue
+		}
+		imported = append(imported, session)
+	}
+
+	if len(errs) > 0 {
+		return imported, fmt.Errorf("failed to import %d of %d sessions: %w",
+			len(errs), len(manifest.Sessions), errors.Join(errs...))
+	}
+	return imported, nil
+}
+
+// importSession fetches an exported session's branch, if bundled, and creates a session on it
+func (o *orchestratorImpl) importSession(ctx context.Context, entry exportedSession, dir, repoPath string) (*types.Session, error) {
+	data := entry.Session
+	if repoPath == "" {
+		repoPath = data.RepoPath
+	}
+	if repoPath == "" {
+		return nil, fmt.Errorf("no repository path recorded; pass one explicitly")
+	}
+
+	if entry.Bundle != "" {
+		if err := o.gitService.FetchBundle(ctx, repoPath, filepath.Join(dir, filepath.FromSlash(entry.Bundle)), data.Branch); err != nil {
+			return nil, err
+		}
+	}
+
+	return o.CreateSession(ctx, types.CreateSessionRequest{
+		Title:       data.Title,
+		Path:        repoPath,
+		Branch:      data.Branch,
+		Program:     data.Program,
+		Height:      data.Height,
+		Width:       data.Width,
+		AutoYes:     data.AutoYes,
+		Prompt:      data.Prompt,
+		ReuseBranch: true,
+	})
+}
+
+// extractExport unpacks an export archive into dir and returns its manifest
+func extractExport(r io.Reader, dir string) (*exportManifest, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archive: %w", err)
+	}
+	defer gz.Close()
+
+	var manifest *exportManifest
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read archive: %w", err)
+		}
+
+		switch {
+		case hdr.Name == exportManifestName:
+			manifest = &exportManifest{}
+			if err := json.NewDecoder(tr).Decode(manifest); err != nil {
+				return nil, fmt.Errorf("failed to parse manifest: %w", err)
+			}
+		case path.Dir(hdr.Name) == exportBundleDir && hdr.Typeflag == tar.TypeReg:
+			// Only flat bundle names are accepted so entries can't escape dir
+			local := filepath.Join(dir, exportBundleDir, path.Base(hdr.Name))
+			if err := writeFileFrom(local, tr); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if manifest == nil {
+		return nil, fmt.Errorf("archive has no %s", exportManifestName)
+	}
+	if manifest.Version != exportFormatVersion {
+		return nil, fmt.Errorf("unsupported export version %d", manifest.Version)
+	}
+	for _, entry := range manifest.Sessions {
+		if entry.Session == nil {
+			return nil, fmt.Errorf("manifest has an empty session entry")
+		}
+		if entry.Bundle != "" && path.Dir(entry.Bundle) != exportBundleDir {
+			return nil, fmt.Errorf("invalid bundle path in manifest: %s", entry.Bundle)
+		}
+	}
+	return manifest, nil
+}
+
+func writeTarFile(tw *tar.Writer, name string, data []byte) error {
+	hdr := &tar.Header{
+		Name: name,
+		Mode: 0644,
+		Size: int64(len(data)),
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return fmt.Errorf("failed to write %s to archive: %w", name, err)
+	}
+	if _, err := tw.Write(data); err != nil {
+		return fmt.Errorf("failed to write %s to archive: %w", name, err)
+	}
+	return nil
+}
+
+func writeFileFrom(name string, r io.Reader) error {
+	if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	f, err := os.Create(name)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", name, err)
+	}
+	defer f.Close()
+	if _, err := io.Copy(f, r); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
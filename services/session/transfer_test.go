@@ -0,0 +1,86 @@
+package session
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"claude-squad/log"
+	"claude-squad/services/executor"
+	"claude-squad/services/git"
+	"claude-squad/services/storage"
+	"claude-squad/services/tmux"
+	"claude-squad/services/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func runGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+		"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com")
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+}
+
+func newTestRepo(t *testing.T) string {
+	t.Helper()
+	dir := filepath.Join(t.TempDir(), "repo")
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	runGit(t, dir, "init", "-q", "-b", "main")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README"), []byte("hello\n"), 0644))
+	runGit(t, dir, "add", ".")
+	runGit(t, dir, "commit", "-q", "-m", "initial")
+	return dir
+}
+
+func TestExportImportRoundTrip(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	ctx := context.Background()
+	gitService := git.NewGitService(executor.NewDefaultExecutor())
+
+	srcRepo := newTestRepo(t)
+	srcStorage, err := storage.NewJSONRepository(t.TempDir())
+	require.NoError(t, err)
+	src := NewOrchestrator(gitService, tmux.NewMockTmuxService(), srcStorage, &executor.MockExecutor{})
+
+	sess, err := src.CreateSession(ctx, types.CreateSessionRequest{
+		Title:   "feature",
+		Path:    srcRepo,
+		Branch:  "feature",
+		Program: "claude",
+		Prompt:  "add a feature using sk-ant-REDACTED",
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(filepath.Join(sess.Path, "feature.txt"), []byte("work\n"), 0644))
+	runGit(t, sess.Path, "add", ".")
+	runGit(t, sess.Path, "commit", "-q", "-m", "agent work")
+
+	var archive bytes.Buffer
+	require.NoError(t, src.ExportSessions(ctx, []string{sess.ID}, &archive, true))
+
+	dstRepo := newTestRepo(t)
+	dstStorage, err := storage.NewJSONRepository(t.TempDir())
+	require.NoError(t, err)
+	dst := NewOrchestrator(gitService, tmux.NewMockTmuxService(), dstStorage, &executor.MockExecutor{})
+
+	imported, err := dst.ImportSessions(ctx, &archive, dstRepo)
+	require.NoError(t, err)
+	require.Len(t, imported, 1)
+
+	assert.Equal(t, "feature", imported[0].Title)
+	assert.Equal(t, "feature", imported[0].Branch)
+	// Credentials don't leave in the export
+	assert.Equal(t, "add a feature using "+log.RedactedPlaceholder, imported[0].Prompt)
+	assert.Equal(t, dstRepo, imported[0].RepoPath)
+	assert.FileExists(t, filepath.Join(imported[0].Path, "feature.txt"))
+}
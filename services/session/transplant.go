@@ -0,0 +1,89 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"claude-squad/services/types"
+)
+
+// TransplantSession copies a session's work onto another session's branch, or the branch
+// checked out in the repository, without merging the branches: its commits are
+// cherry-picked, or its uncommitted changes applied, in the target's worktree. Commits
+// that conflict are aborted and a patch that doesn't apply changes nothing.
+func (o *orchestratorImpl) TransplantSession(ctx context.Context, sessionID string, req types.TransplantRequest) (*types.TransplantResult, error) {
+	session, err := o.GetSession(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	repoPath := repoPathOf(session)
+
+	result := &types.TransplantResult{Path: repoPath}
+	if req.TargetID != "" {
+		target, err := o.GetSession(ctx, req.TargetID)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case target.ID == session.ID:
+			return nil, fmt.Errorf("a session can't be transplanted onto itself")
+		case repoPathOf(target) != repoPath:
+			return nil, fmt.Errorf("session '%s' is in another repository", target.Title)
+		case target.Status == types.StatusPaused:
+			return nil, fmt.Errorf("session '%s' is paused, resume it first", target.Title)
+		}
+		result.Path, result.Branch = target.Path, target.Branch
+	} else {
+		current, err := o.gitService.GetCurrentBranch(ctx, repoPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get current branch: %w", err)
+		}
+		result.Branch = current.Name
+	}
+	if result.Branch == session.Branch {
+		return nil, fmt.Errorf("session works on %s itself", result.Branch)
+	}
+
+	if req.Uncommitted {
+		if session.Status == types.StatusPaused {
+			return nil, fmt.Errorf("session is paused, resume it to reach its uncommitted changes")
+		}
+		patch, err := o.gitService.GetDiffPatch(ctx, session.Path)
+		if err != nil {
+			return nil, err
+		}
+		if strings.TrimSpace(patch) == "" {
+			return nil, fmt.Errorf("session has no uncommitted changes")
+		}
+		if err := o.gitService.ApplyPatch(ctx, result.Path, strings.NewReader(patch)); err != nil {
+			return nil, err
+		}
+		result.Patched = true
+		return result, nil
+	}
+
+	if len(req.Commits) > 0 {
+		for _, commit := range req.Commits {
+			if err := o.gitService.CherryPick(ctx, result.Path, commit); err != nil {
+				return nil, err
+			}
+		}
+		return result, nil
+	}
+
+	commits, err := o.gitService.GetCommitsBetween(ctx, repoPath, result.Branch, session.Branch)
+	if err != nil {
+		return nil, err
+	}
+	if len(commits) == 0 {
+		return nil, fmt.Errorf("branch %s has no commits that %s doesn't have", session.Branch, result.Branch)
+	}
+	if err := o.gitService.CherryPick(ctx, result.Path, result.Branch+".."+session.Branch); err != nil {
+		return nil, err
+	}
+	for _, commit := range commits {
+		result.Commits = append(result.Commits, commit.Hash)
+	}
+	return result, nil
+}
@@ -0,0 +1,181 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"claude-squad/services/git"
+	"claude-squad/services/types"
+)
+
+// DefaultWorktreeName puts each session's worktree next to its repository, named after both
+const DefaultWorktreeName = "{repo}-worktree-{id}"
+
+// WorktreeLayout decides where session worktrees are created
+type WorktreeLayout struct {
+	// Dir is the directory worktrees are created under, with a leading ~ standing for the
+	// home directory. When empty, each goes in the directory holding its repository.
+	Dir string
+	// Name is a worktree's path under Dir, in which {repo}, {branch} and {id} stand for
+	// the repository's directory name, the session's branch and its ID. Slashes, also
+	// those in branch names, make subdirectories. DefaultWorktreeName when empty.
+	Name string
+}
+
+// Path returns where the worktree of session id on branch, created from the repository at
+// repoPath, belongs
+func (l WorktreeLayout) Path(repoPath, branch, id string) string {
+	name := l.Name
+	if name == "" {
+		name = DefaultWorktreeName
+	}
+	name = strings.NewReplacer(
+		"{repo}", filepath.Base(repoPath),
+		"{branch}", branch,
+		"{id}", id,
+	).Replace(name)
+	return filepath.Join(l.dir(repoPath), filepath.FromSlash(name))
+}
+
+// IsSessionPath reports whether path is where the layout puts the worktree of some session
+// of the repository at repoPath, whatever its branch and ID. The repository's own checkout
+// isn't.
+func (l WorktreeLayout) IsSessionPath(repoPath, path string) bool {
+	path = filepath.Clean(path)
+	if path == filepath.Clean(repoPath) {
+		return false
+	}
+	// Path's placeholders come out of QuoteMeta escaped
+	session := strings.NewReplacer(
+		`\{branch\}`, ".+",
+		`\{id\}`, "[^"+regexp.QuoteMeta(string(filepath.Separator))+"]+",
+	).Replace(regexp.QuoteMeta(l.Path(repoPath, "{branch}", "{id}")))
+	return regexp.MustCompile("^" + session + "$").MatchString(path)
+}
+
+func (l WorktreeLayout) dir(repoPath string) string {
+	if l.Dir == "" {
+		return filepath.Dir(repoPath)
+	}
+	if rest, ok := strings.CutPrefix(l.Dir, "~"); ok && (rest == "" || rest[0] == '/' || rest[0] == filepath.Separator) {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, rest)
+		}
+	}
+	return l.Dir
+}
+
+// WithWorktreeLayout creates session worktrees where layout puts them instead of next to
+// their repository
+func WithWorktreeLayout(layout WorktreeLayout) OrchestratorOption {
+	return func(o *orchestratorImpl) {
+		o.worktreeLayout = layout
+	}
+}
+
+// moveWorktree moves a worktree of the repository at repoPath, creating the directories
+// leading to newPath, which git leaves to the caller
+func (o *orchestratorImpl) moveWorktree(ctx context.Context, repoPath, worktreePath, newPath string) error {
+	if err := os.MkdirAll(filepath.Dir(newPath), 0755); err != nil {
+		return fmt.Errorf("failed to create worktree directory: %w", err)
+	}
+	return o.gitService.MoveWorktree(ctx, repoPath, worktreePath, newPath)
+}
+
+// MigrateWorktrees moves the worktrees of paused sessions to where the worktree layout
+// puts them and points the sessions there. Sessions whose worktree is gone are only
+// pointed at the new path, where resuming recreates it. Running sessions are skipped,
+// since their program works in the old path, and so are worktrees locked by anyone else.
+func (o *orchestratorImpl) MigrateWorktrees(ctx context.Context, dryRun bool) ([]types.WorktreeMove, error) {
+	sessions, err := o.ListSessions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var moves []types.WorktreeMove
+	for _, session := range sessions {
+		if session.InPlace != nil {
+			// Its directory isn't a worktree of claude-squad's
+			continue
+		}
+		repoPath := repoPathOf(session)
+		move := types.WorktreeMove{
+			SessionID: session.ID,
+			Title:     session.Title,
+			From:      session.Path,
+			To:        o.worktreeLayout.Path(repoPath, session.Branch, session.ID),
+		}
+		if move.From == move.To {
+			continue
+		}
+
+		if session.Status != types.StatusPaused {
+			move.Skipped = "running; pause it first"
+			moves = append(moves, move)
+			continue
+		}
+		// A paused session only has a worktree when it had uncommitted changes
+		worktree, err := o.gitService.GetWorktreeInfo(ctx, session.Path)
+		if err == nil && worktree.IsLocked && worktree.LockReason != pausedWorktreeLockReason {
+			move.Skipped = "worktree is locked: " + worktree.LockReason
+			moves = append(moves, move)
+			continue
+		}
+		if dryRun {
+			moves = append(moves, move)
+			continue
+		}
+
+		if err == nil {
+			if err := o.relocateWorktree(ctx, repoPath, worktree, move.To); err != nil {
+				move.Skipped = err.Error()
+				moves = append(moves, move)
+				continue
+			}
+		}
+		if err := o.setPath(ctx, session, move.To); err != nil {
+			return moves, err
+		}
+		moves = append(moves, move)
+	}
+	return moves, nil
+}
+
+// relocateWorktree moves a paused session's worktree to newPath, keeping it locked
+func (o *orchestratorImpl) relocateWorktree(ctx context.Context, repoPath string, worktree *git.Worktree, newPath string) error {
+	if worktree.IsLocked {
+		// git doesn't move locked worktrees
+		if err := o.gitService.UnlockWorktree(ctx, worktree.Path); err != nil {
+			return err
+		}
+	}
+	moveErr := o.moveWorktree(ctx, repoPath, worktree.Path, newPath)
+	if worktree.IsLocked {
+		path := newPath
+		if moveErr != nil {
+			path = worktree.Path
+		}
+		if err := o.gitService.LockWorktree(ctx, path, pausedWorktreeLockReason); err != nil {
+			fmt.Printf("warning: failed to lock worktree: %v\n", err)
+		}
+	}
+	return moveErr
+}
+
+// setPath points a session at the worktree path given and saves it
+func (o *orchestratorImpl) setPath(ctx context.Context, session *types.Session, path string) error {
+	o.mu.Lock()
+	session.Path = path
+	o.mu.Unlock()
+
+	data, err := o.storage.Get(ctx, session.ID)
+	if err != nil {
+		return err
+	}
+	data.Path = path
+	return o.storage.Update(ctx, data)
+}
@@ -0,0 +1,128 @@
+package session
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"claude-squad/services/executor"
+	"claude-squad/services/git"
+	"claude-squad/services/storage"
+	"claude-squad/services/tmux"
+	"claude-squad/services/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorktreeLayoutPath(t *testing.T) {
+	home, err := os.UserHomeDir()
+	require.NoError(t, err)
+
+	// The default keeps the naming worktrees always had, which repoPathOf relies on
+	assert.Equal(t, "/src/app-worktree-s1", WorktreeLayout{}.Path("/src/app", "feature/x", "s1"))
+
+	layout := WorktreeLayout{Dir: "~/.claude-squad/worktrees", Name: "{repo}/{branch}"}
+	dir := filepath.Join(home, ".claude-squad", "worktrees")
+	assert.Equal(t, filepath.Join(dir, "app", "feature", "x"), layout.Path("/src/app", "feature/x", "s1"))
+
+	assert.Equal(t, "/wt/app-worktree-s1", WorktreeLayout{Dir: "/wt"}.Path("/src/app", "main", "s1"))
+	assert.Equal(t, "~user/app-worktree-s1", WorktreeLayout{Dir: "~user"}.Path("/src/app", "main", "s1"))
+}
+
+func TestWorktreeLayoutIsSessionPath(t *testing.T) {
+	assert.True(t, WorktreeLayout{}.IsSessionPath("/src/app", "/src/app-worktree-s1"))
+	assert.False(t, WorktreeLayout{}.IsSessionPath("/src/app", "/src/app"))
+	assert.False(t, WorktreeLayout{}.IsSessionPath("/src/app", "/src/web-worktree-s1"))
+	assert.False(t, WorktreeLayout{}.IsSessionPath("/src/app", "/src/app-worktree-a/b"))
+
+	layout := WorktreeLayout{Dir: "/wt", Name: "{repo}/{branch}-{id}"}
+	assert.True(t, layout.IsSessionPath("/src/app", "/wt/app/feature/x-s1"))
+	assert.False(t, layout.IsSessionPath("/src/app", "/wt/web/main-s1"))
+	assert.False(t, layout.IsSessionPath("/src/app", "/src/app-worktree-s1"))
+}
+
+func TestMigrateWorktrees(t *testing.T) {
+	ctx := context.Background()
+	gitMock := git.NewMockGitService()
+	gitMock.DefaultIsRepo = true
+	repo, err := storage.NewJSONRepository(t.TempDir())
+	require.NoError(t, err)
+	orch := NewOrchestrator(gitMock, tmux.NewMockTmuxService(), repo, &executor.MockExecutor{}).(*orchestratorImpl)
+
+	create := func(title string) *types.Session {
+		sess, err := orch.CreateSession(ctx, types.CreateSessionRequest{Title: title, Path: "/src/app", Branch: "cs/" + title})
+		require.NoError(t, err)
+		return sess
+	}
+	running, removed, kept := create("running"), create("removed"), create("kept")
+	require.NoError(t, orch.PauseSession(ctx, removed.ID))
+	require.NoError(t, orch.PauseSession(ctx, kept.ID))
+	keptPath := kept.Path
+
+	// Only the kept session's worktree still exists, locked while it is paused
+	gitMock.GetWorktreeInfoFunc = func(ctx context.Context, worktreePath string) (*git.Worktree, error) {
+		if worktreePath != keptPath {
+			return nil, assert.AnError
+		}
+		return &git.Worktree{Path: worktreePath, IsLocked: true, LockReason: pausedWorktreeLockReason}, nil
+	}
+	var calls []string
+	gitMock.UnlockWorktreeFunc = func(ctx context.Context, worktreePath string) error {
+		calls = append(calls, "unlock "+worktreePath)
+		return nil
+	}
+	gitMock.MoveWorktreeFunc = func(ctx context.Context, repoPath, worktreePath, newPath string) error {
+		calls = append(calls, "move "+worktreePath+" "+newPath)
+		return nil
+	}
+	gitMock.LockWorktreeFunc = func(ctx context.Context, worktreePath, reason string) error {
+		calls = append(calls, "lock "+worktreePath)
+		return nil
+	}
+
+	dir := t.TempDir()
+	orch.worktreeLayout = WorktreeLayout{Dir: dir, Name: "{repo}/{branch}"}
+	target := func(sess *types.Session) string { return filepath.Join(dir, "app", "cs", sess.Title) }
+
+	// A dry run reports the moves without making them
+	moves, err := orch.MigrateWorktrees(ctx, true)
+	require.NoError(t, err)
+	require.Len(t, moves, 3)
+	assert.Empty(t, calls)
+	assert.Equal(t, keptPath, kept.Path)
+
+	moves, err = orch.MigrateWorktrees(ctx, false)
+	require.NoError(t, err)
+	byTitle := make(map[string]types.WorktreeMove)
+	for _, move := range moves {
+		byTitle[move.Title] = move
+	}
+	assert.Equal(t, "running; pause it first", byTitle["running"].Skipped)
+	assert.Empty(t, byTitle["removed"].Skipped)
+	assert.Empty(t, byTitle["kept"].Skipped)
+	assert.Equal(t, []string{
+		"unlock " + keptPath,
+		"move " + keptPath + " " + target(kept),
+		"lock " + target(kept),
+	}, calls)
+	assert.DirExists(t, filepath.Dir(target(kept)))
+
+	// The new paths are saved, and the running session stays where it is
+	for _, sess := range []*types.Session{running, removed, kept} {
+		data, err := repo.Get(ctx, sess.ID)
+		require.NoError(t, err)
+		if sess == running {
+			assert.NotEqual(t, target(sess), data.Path)
+		} else {
+			assert.Equal(t, target(sess), data.Path)
+		}
+	}
+
+	// Once in place, there is nothing left to move but the running session
+	moves, err = orch.MigrateWorktrees(ctx, false)
+	require.NoError(t, err)
+	require.Len(t, moves, 1)
+	assert.Equal(t, running.ID, moves[0].SessionID)
+}
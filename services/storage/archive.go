@@ -0,0 +1,142 @@
+package storage
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"claude-squad/log"
+	"claude-squad/services/types"
+)
+
+const (
+	// archiveVersion is the layout version written to an archive's manifest
+	archiveVersion = 1
+	// archiveManifest is the name of the manifest entry in an archive
+	archiveManifest = "manifest.json"
+	// archiveSessionDir is the directory holding one JSON file per session in an archive
+	archiveSessionDir = "sessions"
+)
+
+// archiveManifestData describes an archive written by writeArchive
+type archiveManifestData struct {
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	Sessions  int       `json:"sessions"`
+}
+
+// writeArchive writes sessions to w as a gzipped tar holding a manifest and one JSON file
+// per session. Every backend reads and writes the same layout, so an archive exported
+// from one store can be imported into any other.
+func writeArchive(w io.Writer, sessions []*types.SessionData) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	now := time.Now()
+
+	put := func(name string, data []byte) error {
+		header := &tar.Header{Name: name, Mode: 0644, Size: int64(len(data)), ModTime: now}
+		if err := tw.WriteHeader(header); err != nil {
+			return fmt.Errorf("failed to write archive entry %s: %w", name, err)
+		}
+		if _, err := tw.Write(data); err != nil {
+			return fmt.Errorf("failed to write archive entry %s: %w", name, err)
+		}
+		return nil
+	}
+
+	manifest, err := json.MarshalIndent(archiveManifestData{Version: archiveVersion, CreatedAt: now, Sessions: len(sessions)}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal archive manifest: %w", err)
+	}
+	if err := put(archiveManifest, manifest); err != nil {
+		return err
+	}
+
+	for _, session := range sessions {
+		data, err := json.MarshalIndent(session, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal session %s: %w", session.ID, err)
+		}
+
+		// Archives leave the machine more often than the live store, so mask credentials
+		// that may have been pasted into prompts or metadata.
+		data = []byte(log.Redact(string(data)))
+
+		if err := put(path.Join(archiveSessionDir, session.ID+".json"), data); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("failed to finish archive: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("failed to finish archive: %w", err)
+	}
+	return nil
+}
+
+// readArchive reads every session in an archive written by writeArchive. The whole
+// archive is read and checked before anything is returned, so a truncated or foreign
+// file is rejected rather than half imported.
+func readArchive(r io.Reader) ([]*types.SessionData, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("not a session archive: %w", err)
+	}
+	defer gz.Close()
+	tr := tar.NewReader(gz)
+
+	var (
+		manifest *archiveManifestData
+		sessions []*types.SessionData
+	)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read archive: %w", err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read archive entry %s: %w", header.Name, err)
+		}
+
+		switch {
+		case header.Name == archiveManifest:
+			manifest = &archiveManifestData{}
+			if err := json.Unmarshal(data, manifest); err != nil {
+				return nil, fmt.Errorf("failed to parse archive manifest: %w", err)
+			}
+			if manifest.Version > archiveVersion {
+				return nil, fmt.Errorf("archive version %d is newer than this version of claude-squad supports", manifest.Version)
+			}
+		case path.Dir(header.Name) == archiveSessionDir && strings.HasSuffix(header.Name, ".json"):
+			var session types.SessionData
+			if err := json.Unmarshal(data, &session); err != nil {
+				return nil, fmt.Errorf("failed to parse archive entry %s: %w", header.Name, err)
+			}
+			sessions = append(sessions, &session)
+		}
+	}
+
+	if manifest == nil {
+		return nil, fmt.Errorf("not a session archive: missing %s", archiveManifest)
+	}
+	if len(sessions) != manifest.Sessions {
+		return nil, fmt.Errorf("archive is incomplete: expected %d sessions, found %d", manifest.Sessions, len(sessions))
+	}
+	return sessions, nil
+}
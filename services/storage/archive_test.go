@@ -0,0 +1,74 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"claude-squad/services/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportImportAcrossBackends(t *testing.T) {
+	ctx := context.Background()
+	sessions := []*types.SessionData{
+		{ID: "a", Title: "alpha", Status: types.StatusPaused, Metadata: map[string]string{"ticket": "ENG-1"}},
+		{ID: "b", Title: "bravo", Inputs: []types.InputRecord{{Kind: types.InputPrompt, Text: "add tests"}}},
+	}
+
+	for fromName, openFrom := range testBackends {
+		for toName, openTo := range testBackends {
+			t.Run(fromName+" to "+toName, func(t *testing.T) {
+				from, err := openFrom(t.TempDir())
+				require.NoError(t, err)
+				for _, session := range sessions {
+					require.NoError(t, from.Create(ctx, session))
+				}
+
+				to, err := openTo(t.TempDir())
+				require.NoError(t, err)
+				require.NoError(t, to.Create(ctx, &types.SessionData{ID: "stale", Title: "stale"}))
+
+				var archive bytes.Buffer
+				require.NoError(t, from.Export(ctx, &archive))
+				require.NoError(t, to.Import(ctx, &archive))
+
+				// Import replaces what was there
+				exists, err := to.Exists(ctx, "stale")
+				require.NoError(t, err)
+				assert.False(t, exists)
+
+				a, err := to.Get(ctx, "a")
+				require.NoError(t, err)
+				assert.Equal(t, types.StatusPaused, a.Status)
+				assert.Equal(t, "ENG-1", a.Metadata["ticket"])
+				b, err := to.Get(ctx, "b")
+				require.NoError(t, err)
+				require.Len(t, b.Inputs, 1)
+				assert.Equal(t, "add tests", b.Inputs[0].Text)
+			})
+		}
+	}
+}
+
+func TestImportRejectsBadArchive(t *testing.T) {
+	ctx := context.Background()
+	repo, err := NewJSONRepository(t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, repo.Create(ctx, &types.SessionData{ID: "a", Title: "alpha"}))
+
+	var archive bytes.Buffer
+	require.NoError(t, repo.Export(ctx, &archive))
+	truncated := bytes.NewReader(archive.Bytes()[:archive.Len()/2])
+
+	assert.Error(t, repo.Import(ctx, truncated))
+	assert.Error(t, repo.Import(ctx, strings.NewReader("not an archive")))
+
+	// A failed import leaves the store as it was
+	exists, err := repo.Exists(ctx, "a")
+	require.NoError(t, err)
+	assert.True(t, exists)
+}
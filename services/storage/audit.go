@@ -0,0 +1,469 @@
+package storage
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"claude-squad/log"
+	"claude-squad/services/types"
+)
+
+// AuditFileName is the audit log's file name within the storage directory
+const AuditFileName = "audit.log"
+
+// Actor names the part of claude-squad that made a change
+type Actor string
+
+const (
+	ActorCLI    Actor = "cli"
+	ActorTUI    Actor = "tui"
+	ActorDaemon Actor = "daemon"
+)
+
+// AuditOp is the kind of change an audit entry records
+type AuditOp string
+
+const (
+	AuditCreate       AuditOp = "create"
+	AuditUpdate       AuditOp = "update"
+	AuditStatusChange AuditOp = "status"
+	AuditDelete       AuditOp = "delete"
+)
+
+// AuditEntry records one change to a session in the store
+type AuditEntry struct {
+	Time      time.Time `json:"time"`
+	Actor     Actor     `json:"actor"`
+	Op        AuditOp   `json:"op"`
+	SessionID string    `json:"session_id"`
+	Title     string    `json:"title,omitempty"`
+	// Detail describes the change, e.g. the status transition
+	Detail string `json:"detail,omitempty"`
+}
+
+// AuditLog is an append-only file of audit entries, one JSON object per line. Appends
+// take an exclusive lock so the CLI, TUI and daemon can share one log.
+type AuditLog struct {
+	path string
+}
+
+// NewAuditLog creates an audit log kept at path
+func NewAuditLog(path string) *AuditLog {
+	return &AuditLog{path: path}
+}
+
+// Append adds entries to the end of the log
+func (l *AuditLog) Append(entries ...AuditEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	var buf []byte
+	for _, entry := range entries {
+		line, err := json.Marshal(entry)
+		if err != nil {
+			return fmt.Errorf("failed to marshal audit entry: %w", err)
+		}
+		buf = append(append(buf, line...), '\n')
+	}
+
+	if err := os.MkdirAll(filepath.Dir(l.path), 0755); err != nil {
+		return fmt.Errorf("failed to create audit log directory: %w", err)
+	}
+	f, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer f.Close()
+	if err := lockFile(f, true); err != nil {
+		return fmt.Errorf("failed to lock audit log: %w", err)
+	}
+	defer unlockFile(f)
+
+	if _, err := f.Write(buf); err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	return nil
+}
+
+// Entries returns the entries match accepts, oldest first, or every entry if match is nil
+func (l *AuditLog) Entries(match func(AuditEntry) bool) ([]AuditEntry, error) {
+	f, err := os.Open(l.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer f.Close()
+	if err := lockFile(f, false); err != nil {
+		return nil, fmt.Errorf("failed to lock audit log: %w", err)
+	}
+	defer unlockFile(f)
+
+	var entries []AuditEntry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var entry AuditEntry
+		// A line cut short by a crash is skipped rather than failing the whole log
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		if match == nil || match(entry) {
+			entries = append(entries, entry)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+	return entries, nil
+}
+
+// auditedRepository wraps another repository and records each change it makes to the
+// audit log, attributed to actor. Reads pass straight through.
+type auditedRepository struct {
+	StorageRepository
+	log   *AuditLog
+	actor Actor
+
+	// pending collects the entries of a transaction until it commits
+	pending *[]AuditEntry
+}
+
+// NewAuditedRepository wraps inner so every session it creates, updates or deletes is
+// recorded to auditLog as a change made by actor
+func NewAuditedRepository(inner StorageRepository, auditLog *AuditLog, actor Actor) StorageRepository {
+	return &auditedRepository{StorageRepository: inner, log: auditLog, actor: actor}
+}
+
+func (r *auditedRepository) entry(op AuditOp, id, title, detail string) AuditEntry {
+	return AuditEntry{Time: time.Now(), Actor: r.actor, Op: op, SessionID: id, Title: title, Detail: detail}
+}
+
+// record writes entries to the log. The change has already been made by then, so a
+// failure to record it is logged rather than returned.
+func (r *auditedRepository) record(entries ...AuditEntry) {
+	if r.pending != nil {
+		*r.pending = append(*r.pending, entries...)
+		return
+	}
+	if err := r.log.Append(entries...); err != nil && log.WarningLog != nil {
+		log.WarningLog.Printf("failed to record audit entry: %v", err)
+	}
+}
+
+// previous returns the stored version of a session, or nil if it can't be read
+func (r *auditedRepository) previous(ctx context.Context, id string) *types.SessionData {
+	session, err := r.StorageRepository.Get(ctx, id)
+	if err != nil {
+		return nil
+	}
+	return session
+}
+
+func (r *auditedRepository) titleOf(ctx context.Context, id string) string {
+	if session := r.previous(ctx, id); session != nil {
+		return session.Title
+	}
+	return ""
+}
+
+// statusName names a status in audit entries
+func statusName(status types.Status) string {
+	switch status {
+	case types.StatusRunning:
+		return "running"
+	case types.StatusReady:
+		return "ready"
+	case types.StatusLoading:
+		return "loading"
+	case types.StatusPaused:
+		return "paused"
+	}
+	return fmt.Sprintf("status %d", status)
+}
+
+func statusDetail(from, to types.Status) string {
+	return statusName(from) + " -> " + statusName(to)
+}
+
+// updateEntry describes the change from prev to session, calling out status changes and
+// moves in and out of the trash
+func (r *auditedRepository) updateEntry(prev, session *types.SessionData) AuditEntry {
+	if prev == nil {
+		return r.entry(AuditUpdate, session.ID, session.Title, "")
+	}
+	switch {
+	case prev.DeletedAt.IsZero() && !session.DeletedAt.IsZero():
+		return r.entry(AuditStatusChange, session.ID, session.Title, "moved to trash")
+	case !prev.DeletedAt.IsZero() && session.DeletedAt.IsZero():
+		return r.entry(AuditStatusChange, session.ID, session.Title, "restored from trash")
+	case prev.Status != session.Status:
+		return r.entry(AuditStatusChange, session.ID, session.Title, statusDetail(prev.Status, session.Status))
+	}
+	return r.entry(AuditUpdate, session.ID, session.Title, "")
+}
+
+// Basic CRUD operations
+
+func (r *auditedRepository) Create(ctx context.Context, session *types.SessionData) error {
+	if err := r.StorageRepository.Create(ctx, session); err != nil {
+		return err
+	}
+	r.record(r.entry(AuditCreate, session.ID, session.Title, statusName(session.Status)))
+	return nil
+}
+
+func (r *auditedRepository) Update(ctx context.Context, session *types.SessionData) error {
+	prev := r.previous(ctx, session.ID)
+	if err := r.StorageRepository.Update(ctx, session); err != nil {
+		return err
+	}
+	r.record(r.updateEntry(prev, session))
+	return nil
+}
+
+func (r *auditedRepository) Delete(ctx context.Context, id string) error {
+	title := r.titleOf(ctx, id)
+	if err := r.StorageRepository.Delete(ctx, id); err != nil {
+		return err
+	}
+	r.record(r.entry(AuditDelete, id, title, ""))
+	return nil
+}
+
+// Batch operations
+
+func (r *auditedRepository) CreateBatch(ctx context.Context, sessions []*types.SessionData) error {
+	if err := r.StorageRepository.CreateBatch(ctx, sessions); err != nil {
+		return err
+	}
+	entries := make([]AuditEntry, len(sessions))
+	for i, session := range sessions {
+		entries[i] = r.entry(AuditCreate, session.ID, session.Title, statusName(session.Status))
+	}
+	r.record(entries...)
+	return nil
+}
+
+func (r *auditedRepository) UpdateBatch(ctx context.Context, sessions []*types.SessionData) error {
+	prev := make([]*types.SessionData, len(sessions))
+	for i, session := range sessions {
+		prev[i] = r.previous(ctx, session.ID)
+	}
+	if err := r.StorageRepository.UpdateBatch(ctx, sessions); err != nil {
+		return err
+	}
+	entries := make([]AuditEntry, len(sessions))
+	for i, session := range sessions {
+		entries[i] = r.updateEntry(prev[i], session)
+	}
+	r.record(entries...)
+	return nil
+}
+
+func (r *auditedRepository) DeleteBatch(ctx context.Context, ids []string) error {
+	titles := make([]string, len(ids))
+	for i, id := range ids {
+		titles[i] = r.titleOf(ctx, id)
+	}
+	if err := r.StorageRepository.DeleteBatch(ctx, ids); err != nil {
+		return err
+	}
+	entries := make([]AuditEntry, len(ids))
+	for i, id := range ids {
+		entries[i] = r.entry(AuditDelete, id, titles[i], "")
+	}
+	r.record(entries...)
+	return nil
+}
+
+// Status operations
+
+func (r *auditedRepository) UpdateStatus(ctx context.Context, id string, status types.Status) error {
+	prev := r.previous(ctx, id)
+	if err := r.StorageRepository.UpdateStatus(ctx, id, status); err != nil {
+		return err
+	}
+	if prev == nil {
+		r.record(r.entry(AuditStatusChange, id, "", statusName(status)))
+	} else {
+		r.record(r.entry(AuditStatusChange, id, prev.Title, statusDetail(prev.Status, status)))
+	}
+	return nil
+}
+
+func (r *auditedRepository) UpdateStatusBatch(ctx context.Context, updates map[string]types.Status) error {
+	prev := make(map[string]*types.SessionData, len(updates))
+	for id := range updates {
+		prev[id] = r.previous(ctx, id)
+	}
+	if err := r.StorageRepository.UpdateStatusBatch(ctx, updates); err != nil {
+		return err
+	}
+	entries := make([]AuditEntry, 0, len(updates))
+	for id, status := range updates {
+		if p := prev[id]; p != nil {
+			entries = append(entries, r.entry(AuditStatusChange, id, p.Title, statusDetail(p.Status, status)))
+		} else {
+			entries = append(entries, r.entry(AuditStatusChange, id, "", statusName(status)))
+		}
+	}
+	r.record(entries...)
+	return nil
+}
+
+// Tag operations
+
+func (r *auditedRepository) Tag(ctx context.Context, id string, tags ...string) error {
+	if err := r.StorageRepository.Tag(ctx, id, tags...); err != nil {
+		return err
+	}
+	r.record(r.entry(AuditUpdate, id, r.titleOf(ctx, id), "tagged "+strings.Join(tags, ", ")))
+	return nil
+}
+
+func (r *auditedRepository) Untag(ctx context.Context, id string, tags ...string) error {
+	if err := r.StorageRepository.Untag(ctx, id, tags...); err != nil {
+		return err
+	}
+	r.record(r.entry(AuditUpdate, id, r.titleOf(ctx, id), "untagged "+strings.Join(tags, ", ")))
+	return nil
+}
+
+// Metadata operations record the key but never the value, which may hold credentials
+
+func (r *auditedRepository) SetMetadata(ctx context.Context, id string, key, value string) error {
+	if err := r.StorageRepository.SetMetadata(ctx, id, key, value); err != nil {
+		return err
+	}
+	r.record(r.entry(AuditUpdate, id, r.titleOf(ctx, id), "set metadata "+key))
+	return nil
+}
+
+func (r *auditedRepository) DeleteMetadata(ctx context.Context, id string, key string) error {
+	if err := r.StorageRepository.DeleteMetadata(ctx, id, key); err != nil {
+		return err
+	}
+	r.record(r.entry(AuditUpdate, id, r.titleOf(ctx, id), "deleted metadata "+key))
+	return nil
+}
+
+// Maintenance operations
+
+func (r *auditedRepository) DeleteAll(ctx context.Context) error {
+	sessions, _ := r.StorageRepository.List(ctx, nil)
+	if err := r.StorageRepository.DeleteAll(ctx); err != nil {
+		return err
+	}
+	entries := make([]AuditEntry, len(sessions))
+	for i, session := range sessions {
+		entries[i] = r.entry(AuditDelete, session.ID, session.Title, "")
+	}
+	r.record(entries...)
+	return nil
+}
+
+func (r *auditedRepository) DeleteOlderThan(ctx context.Context, duration time.Duration) error {
+	before, _ := r.StorageRepository.List(ctx, nil)
+	if err := r.StorageRepository.DeleteOlderThan(ctx, duration); err != nil {
+		return err
+	}
+
+	var entries []AuditEntry
+	for _, session := range before {
+		if exists, err := r.StorageRepository.Exists(ctx, session.ID); err == nil && !exists {
+			entries = append(entries, r.entry(AuditDelete, session.ID, session.Title, "expired"))
+		}
+	}
+	r.record(entries...)
+	return nil
+}
+
+// Import records every session it replaces as deleted and every imported one as created
+func (r *auditedRepository) Import(ctx context.Context, rd io.Reader) error {
+	before, _ := r.StorageRepository.List(ctx, nil)
+	if err := r.StorageRepository.Import(ctx, rd); err != nil {
+		return err
+	}
+	after, err := r.StorageRepository.List(ctx, nil)
+	if err != nil {
+		return nil
+	}
+
+	imported := make(map[string]bool, len(after))
+	entries := make([]AuditEntry, 0, len(before)+len(after))
+	for _, session := range after {
+		imported[session.ID] = true
+	}
+	for _, session := range before {
+		if !imported[session.ID] {
+			entries = append(entries, r.entry(AuditDelete, session.ID, session.Title, "replaced by import"))
+		}
+	}
+	for _, session := range after {
+		entries = append(entries, r.entry(AuditCreate, session.ID, session.Title, "imported"))
+	}
+	r.record(entries...)
+	return nil
+}
+
+// Repair and Unreadable pass through to stores that support them
+
+func (r *auditedRepository) Unreadable(ctx context.Context) ([]string, error) {
+	if repairer, ok := r.StorageRepository.(Repairer); ok {
+		return repairer.Unreadable(ctx)
+	}
+	return nil, nil
+}
+
+func (r *auditedRepository) Repair(ctx context.Context) (*RepairReport, error) {
+	if repairer, ok := r.StorageRepository.(Repairer); ok {
+		return repairer.Repair(ctx)
+	}
+	return &RepairReport{}, nil
+}
+
+// Transaction support
+
+func (r *auditedRepository) BeginTx(ctx context.Context) (Transaction, error) {
+	tx, err := r.StorageRepository.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &auditedTransaction{
+		auditedRepository: &auditedRepository{StorageRepository: tx, log: r.log, actor: r.actor, pending: &[]AuditEntry{}},
+		tx:                tx,
+	}, nil
+}
+
+// auditedTransaction holds back a transaction's audit entries until it commits
+type auditedTransaction struct {
+	*auditedRepository
+	tx Transaction
+}
+
+func (t *auditedTransaction) Commit() error {
+	if err := t.tx.Commit(); err != nil {
+		return err
+	}
+	entries := *t.pending
+	*t.pending = nil
+	if err := t.log.Append(entries...); err != nil && log.WarningLog != nil {
+		log.WarningLog.Printf("failed to record audit entry: %v", err)
+	}
+	return nil
+}
+
+func (t *auditedTransaction) Rollback() error {
+	*t.pending = nil
+	return t.tx.Rollback()
+}
@@ -0,0 +1,69 @@
+package storage
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"claude-squad/services/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditedRepository(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	inner, err := NewSQLiteRepository(dir)
+	require.NoError(t, err)
+	auditLog := NewAuditLog(filepath.Join(dir, AuditFileName))
+	repo := NewAuditedRepository(inner, auditLog, ActorDaemon)
+
+	session := &types.SessionData{ID: "a", Title: "fix-auth", Status: types.StatusRunning}
+	require.NoError(t, repo.Create(ctx, session))
+	require.NoError(t, repo.UpdateStatus(ctx, "a", types.StatusPaused))
+	require.NoError(t, repo.SetMetadata(ctx, "a", "token", "secret-value"))
+
+	session.Status = types.StatusPaused
+	session.DeletedAt = time.Now()
+	require.NoError(t, repo.Update(ctx, session))
+
+	// A rolled back transaction records nothing, a committed one records its changes
+	tx, err := repo.BeginTx(ctx)
+	require.NoError(t, err)
+	require.NoError(t, tx.Create(ctx, &types.SessionData{ID: "b", Title: "other"}))
+	require.NoError(t, tx.Rollback())
+
+	tx, err = repo.BeginTx(ctx)
+	require.NoError(t, err)
+	require.NoError(t, tx.Delete(ctx, "a"))
+	require.NoError(t, tx.Commit())
+
+	entries, err := auditLog.Entries(nil)
+	require.NoError(t, err)
+	require.Len(t, entries, 5)
+
+	want := []struct {
+		op     AuditOp
+		detail string
+	}{
+		{AuditCreate, "running"},
+		{AuditStatusChange, "running -> paused"},
+		{AuditUpdate, "set metadata token"},
+		{AuditStatusChange, "moved to trash"},
+		{AuditDelete, ""},
+	}
+	for i, w := range want {
+		assert.Equal(t, w.op, entries[i].Op, "entry %d", i)
+		assert.Equal(t, w.detail, entries[i].Detail, "entry %d", i)
+		assert.Equal(t, ActorDaemon, entries[i].Actor)
+		assert.Equal(t, "a", entries[i].SessionID)
+		assert.Equal(t, "fix-auth", entries[i].Title)
+	}
+
+	// The deleted session's history can still be read back by title
+	byTitle, err := auditLog.Entries(func(entry AuditEntry) bool { return entry.Title == "fix-auth" })
+	require.NoError(t, err)
+	assert.Len(t, byTitle, 5)
+}
@@ -0,0 +1,39 @@
+package storage
+
+import (
+	"fmt"
+	"os"
+)
+
+// Storage backends selectable with the storage_backend config key
+const (
+	BackendJSON     = "json"
+	BackendSQLite   = "sqlite"
+	BackendBolt     = "bolt"
+	BackendPostgres = "postgres"
+)
+
+// DSNEnv names the environment variable the postgres backend reads its connection string
+// from when none is configured, so a password needn't be kept in the config file
+const DSNEnv = "CS_STORAGE_DSN"
+
+// NewRepository creates the storage repository for backend, keeping its files in dir, or
+// for the postgres backend connecting to the database at dsn. An empty backend selects
+// the JSON store.
+func NewRepository(backend, dir, dsn string) (StorageRepository, error) {
+	switch backend {
+	case "", BackendJSON:
+		return NewJSONRepository(dir)
+	case BackendSQLite:
+		return NewSQLiteRepository(dir)
+	case BackendBolt:
+		return NewBoltRepository(dir)
+	case BackendPostgres:
+		if dsn == "" {
+			dsn = os.Getenv(DSNEnv)
+		}
+		return NewPostgresRepository(dsn)
+	default:
+		return nil, fmt.Errorf("unknown storage backend '%s'", backend)
+	}
+}
@@ -0,0 +1,694 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"claude-squad/services/types"
+
+	"github.com/fsnotify/fsnotify"
+	bolt "go.etcd.io/bbolt"
+)
+
+// BoltFileName is the database file NewBoltRepository creates in its directory
+const BoltFileName = "sessions.bolt"
+
+var (
+	// boltSessionsBucket maps session IDs to their JSON encoding, without metadata
+	boltSessionsBucket = []byte("sessions")
+	// boltMetadataBucket holds one nested bucket of metadata key/values per session ID
+	boltMetadataBucket = []byte("metadata")
+)
+
+// boltOpenTimeout bounds how long an operation waits for another process to release the
+// database file
+const boltOpenTimeout = 5 * time.Second
+
+// boltRepository is a bbolt-backed implementation of StorageRepository. bbolt locks its
+// file for as long as it is open, so the database is opened per operation to let the
+// daemon and CLI share it.
+type boltRepository struct {
+	path string
+	// tx is set when the repository runs inside a transaction
+	tx *bolt.Tx
+}
+
+// NewBoltRepository creates a storage repository backed by a bbolt database in dir
+func NewBoltRepository(dir string) (StorageRepository, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create storage directory: %w", err)
+	}
+
+	r := &boltRepository{path: filepath.Join(dir, BoltFileName)}
+	err := r.update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{boltSessionsBucket, boltMetadataBucket} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return fmt.Errorf("failed to create bucket %s: %w", name, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *boltRepository) open() (*bolt.DB, error) {
+	db, err := bolt.Open(r.path, 0600, &bolt.Options{Timeout: boltOpenTimeout})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	return db, nil
+}
+
+// update runs fn in a read-write transaction, or in the current one inside BeginTx
+func (r *boltRepository) update(fn func(tx *bolt.Tx) error) error {
+	if r.tx != nil {
+		return fn(r.tx)
+	}
+	db, err := r.open()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	return db.Update(fn)
+}
+
+// view runs fn in a read-only transaction, or in the current one inside BeginTx
+func (r *boltRepository) view(fn func(tx *bolt.Tx) error) error {
+	if r.tx != nil {
+		return fn(r.tx)
+	}
+	db, err := r.open()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	return db.View(fn)
+}
+
+// decodeSession unmarshals a stored session and attaches its metadata
+func decodeSession(tx *bolt.Tx, data []byte) (*types.SessionData, error) {
+	var session types.SessionData
+	if err := json.Unmarshal(data, &session); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal session: %w", err)
+	}
+
+	if mb := tx.Bucket(boltMetadataBucket).Bucket([]byte(session.ID)); mb != nil {
+		session.Metadata = make(map[string]string)
+		mb.ForEach(func(k, v []byte) error {
+			session.Metadata[string(k)] = string(v)
+			return nil
+		})
+	}
+	return &session, nil
+}
+
+func getSession(tx *bolt.Tx, id string) (*types.SessionData, error) {
+	data := tx.Bucket(boltSessionsBucket).Get([]byte(id))
+	if data == nil {
+		return nil, fmt.Errorf("session not found: %s", id)
+	}
+	return decodeSession(tx, data)
+}
+
+// putRecord writes a session without touching its metadata bucket
+func putRecord(tx *bolt.Tx, session *types.SessionData) error {
+	record := *session
+	record.Metadata = nil
+	data, err := sealRecord(&record, false)
+	if err != nil {
+		return err
+	}
+	return tx.Bucket(boltSessionsBucket).Put([]byte(session.ID), data)
+}
+
+// putSession writes a session and replaces its metadata
+func putSession(tx *bolt.Tx, session *types.SessionData) error {
+	if err := putRecord(tx, session); err != nil {
+		return err
+	}
+
+	metadata := tx.Bucket(boltMetadataBucket)
+	if err := metadata.DeleteBucket([]byte(session.ID)); err != nil && !errors.Is(err, bolt.ErrBucketNotFound) {
+		return fmt.Errorf("failed to clear metadata: %w", err)
+	}
+	if len(session.Metadata) == 0 {
+		return nil
+	}
+	mb, err := metadata.CreateBucket([]byte(session.ID))
+	if err != nil {
+		return fmt.Errorf("failed to write metadata: %w", err)
+	}
+	for key, value := range session.Metadata {
+		if err := mb.Put([]byte(key), []byte(value)); err != nil {
+			return fmt.Errorf("failed to write metadata: %w", err)
+		}
+	}
+	return nil
+}
+
+func deleteSession(tx *bolt.Tx, id string) error {
+	sessions := tx.Bucket(boltSessionsBucket)
+	if sessions.Get([]byte(id)) == nil {
+		return fmt.Errorf("session not found: %s", id)
+	}
+	if err := sessions.Delete([]byte(id)); err != nil {
+		return fmt.Errorf("failed to delete session: %w", err)
+	}
+	if err := tx.Bucket(boltMetadataBucket).DeleteBucket([]byte(id)); err != nil && !errors.Is(err, bolt.ErrBucketNotFound) {
+		return fmt.Errorf("failed to delete metadata: %w", err)
+	}
+	return nil
+}
+
+// allSessions returns every session, skipping records that can't be decoded
+func allSessions(tx *bolt.Tx) []*types.SessionData {
+	var sessions []*types.SessionData
+	tx.Bucket(boltSessionsBucket).ForEach(func(k, v []byte) error {
+		if session, err := decodeSession(tx, v); err == nil {
+			sessions = append(sessions, session)
+		}
+		return nil
+	})
+	return sessions
+}
+
+// Basic CRUD operations
+
+func (r *boltRepository) Create(ctx context.Context, session *types.SessionData) error {
+	if session.ID == "" {
+		return fmt.Errorf("session ID is required")
+	}
+
+	return r.update(func(tx *bolt.Tx) error {
+		if tx.Bucket(boltSessionsBucket).Get([]byte(session.ID)) != nil {
+			return fmt.Errorf("session already exists: %s", session.ID)
+		}
+
+		session.CreatedAt = time.Now()
+		session.UpdatedAt = time.Now()
+		return putSession(tx, session)
+	})
+}
+
+func (r *boltRepository) Get(ctx context.Context, id string) (*types.SessionData, error) {
+	var session *types.SessionData
+	err := r.view(func(tx *bolt.Tx) error {
+		var err error
+		session, err = getSession(tx, id)
+		return err
+	})
+	return session, err
+}
+
+func (r *boltRepository) Update(ctx context.Context, session *types.SessionData) error {
+	if session.ID == "" {
+		return fmt.Errorf("session ID is required")
+	}
+
+	return r.update(func(tx *bolt.Tx) error {
+		if tx.Bucket(boltSessionsBucket).Get([]byte(session.ID)) == nil {
+			return fmt.Errorf("session not found: %s", session.ID)
+		}
+
+		session.UpdatedAt = time.Now()
+		return putSession(tx, session)
+	})
+}
+
+func (r *boltRepository) Delete(ctx context.Context, id string) error {
+	return r.update(func(tx *bolt.Tx) error {
+		return deleteSession(tx, id)
+	})
+}
+
+// Batch operations
+
+func (r *boltRepository) CreateBatch(ctx context.Context, sessions []*types.SessionData) error {
+	return r.update(func(tx *bolt.Tx) error {
+		inTx := &boltRepository{tx: tx}
+		for _, session := range sessions {
+			if err := inTx.Create(ctx, session); err != nil {
+				return fmt.Errorf("failed to create session %s: %w", session.ID, err)
+			}
+		}
+		return nil
+	})
+}
+
+func (r *boltRepository) UpdateBatch(ctx context.Context, sessions []*types.SessionData) error {
+	return r.update(func(tx *bolt.Tx) error {
+		inTx := &boltRepository{tx: tx}
+		for _, session := range sessions {
+			if err := inTx.Update(ctx, session); err != nil {
+				return fmt.Errorf("failed to update session %s: %w", session.ID, err)
+			}
+		}
+		return nil
+	})
+}
+
+func (r *boltRepository) DeleteBatch(ctx context.Context, ids []string) error {
+	return r.update(func(tx *bolt.Tx) error {
+		for _, id := range ids {
+			if err := deleteSession(tx, id); err != nil {
+				return fmt.Errorf("failed to delete session %s: %w", id, err)
+			}
+		}
+		return nil
+	})
+}
+
+// Query operations
+
+func (r *boltRepository) List(ctx context.Context, opts *QueryOptions) ([]*types.SessionData, error) {
+	return r.Search(ctx, "", opts)
+}
+
+// Search matches substrings of session text, scanning every session
+func (r *boltRepository) Search(ctx context.Context, query string, opts *QueryOptions) ([]*types.SessionData, error) {
+	terms := searchTerms(query)
+	var sessions []*types.SessionData
+	err := r.view(func(tx *bolt.Tx) error {
+		for _, session := range allSessions(tx) {
+			if matchesQuery(session, opts) && matchesSearch(session, terms) {
+				sessions = append(sessions, session)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sortAndPaginate(sessions, opts)
+}
+
+func (r *boltRepository) Count(ctx context.Context, opts *QueryOptions) (int, error) {
+	sessions, err := r.List(ctx, opts)
+	if err != nil {
+		return 0, err
+	}
+	return len(sessions), nil
+}
+
+func (r *boltRepository) Exists(ctx context.Context, id string) (bool, error) {
+	var exists bool
+	err := r.view(func(tx *bolt.Tx) error {
+		exists = tx.Bucket(boltSessionsBucket).Get([]byte(id)) != nil
+		return nil
+	})
+	return exists, err
+}
+
+// Specialized queries
+
+func (r *boltRepository) GetByTitle(ctx context.Context, title string) (*types.SessionData, error) {
+	sessions, err := r.List(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, session := range sessions {
+		if session.Title == title {
+			return session, nil
+		}
+	}
+
+	return nil, fmt.Errorf("session not found with title: %s", title)
+}
+
+func (r *boltRepository) GetByBranch(ctx context.Context, branch string) ([]*types.SessionData, error) {
+	return r.List(ctx, &QueryOptions{Branch: &branch})
+}
+
+func (r *boltRepository) GetActive(ctx context.Context) ([]*types.SessionData, error) {
+	sessions, err := r.List(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var active []*types.SessionData
+	for _, session := range sessions {
+		if session.Status == types.StatusRunning || session.Status == types.StatusReady {
+			active = append(active, session)
+		}
+	}
+	return active, nil
+}
+
+func (r *boltRepository) GetPaused(ctx context.Context) ([]*types.SessionData, error) {
+	paused := types.StatusPaused
+	return r.List(ctx, &QueryOptions{Status: &paused})
+}
+
+func (r *boltRepository) GetByRepo(ctx context.Context, repoPath string) ([]*types.SessionData, error) {
+	return r.List(ctx, &QueryOptions{RepoPath: &repoPath})
+}
+
+// Status operations
+
+func (r *boltRepository) UpdateStatus(ctx context.Context, id string, status types.Status) error {
+	return r.update(func(tx *bolt.Tx) error {
+		session, err := getSession(tx, id)
+		if err != nil {
+			return err
+		}
+
+		session.Status = status
+		session.UpdatedAt = time.Now()
+		return putRecord(tx, session)
+	})
+}
+
+func (r *boltRepository) UpdateStatusBatch(ctx context.Context, updates map[string]types.Status) error {
+	return r.update(func(tx *bolt.Tx) error {
+		inTx := &boltRepository{tx: tx}
+		for id, status := range updates {
+			if err := inTx.UpdateStatus(ctx, id, status); err != nil {
+				return fmt.Errorf("failed to update status for %s: %w", id, err)
+			}
+		}
+		return nil
+	})
+}
+
+// Tag operations
+
+func (r *boltRepository) Tag(ctx context.Context, id string, tags ...string) error {
+	return r.update(func(tx *bolt.Tx) error {
+		session, err := getSession(tx, id)
+		if err != nil {
+			return err
+		}
+
+		added, err := addTags(session, tags)
+		if err != nil || !added {
+			return err
+		}
+		session.UpdatedAt = time.Now()
+		return putRecord(tx, session)
+	})
+}
+
+func (r *boltRepository) Untag(ctx context.Context, id string, tags ...string) error {
+	return r.update(func(tx *bolt.Tx) error {
+		session, err := getSession(tx, id)
+		if err != nil {
+			return err
+		}
+
+		if !removeTags(session, tags) {
+			return nil
+		}
+		session.UpdatedAt = time.Now()
+		return putRecord(tx, session)
+	})
+}
+
+func (r *boltRepository) GetByTag(ctx context.Context, tag string) ([]*types.SessionData, error) {
+	return r.List(ctx, &QueryOptions{Tags: []string{tag}})
+}
+
+// Metadata operations
+
+func (r *boltRepository) SetMetadata(ctx context.Context, id string, key, value string) error {
+	return r.update(func(tx *bolt.Tx) error {
+		session, err := getSession(tx, id)
+		if err != nil {
+			return err
+		}
+
+		mb, err := tx.Bucket(boltMetadataBucket).CreateBucketIfNotExists([]byte(id))
+		if err != nil {
+			return fmt.Errorf("failed to write metadata: %w", err)
+		}
+		if err := mb.Put([]byte(key), []byte(value)); err != nil {
+			return fmt.Errorf("failed to write metadata: %w", err)
+		}
+
+		session.UpdatedAt = time.Now()
+		return putRecord(tx, session)
+	})
+}
+
+func (r *boltRepository) GetMetadata(ctx context.Context, id string, key string) (string, error) {
+	var value string
+	err := r.view(func(tx *bolt.Tx) error {
+		if tx.Bucket(boltSessionsBucket).Get([]byte(id)) == nil {
+			return fmt.Errorf("session not found: %s", id)
+		}
+
+		var v []byte
+		if mb := tx.Bucket(boltMetadataBucket).Bucket([]byte(id)); mb != nil {
+			v = mb.Get([]byte(key))
+		}
+		if v == nil {
+			return fmt.Errorf("metadata key not found: %s", key)
+		}
+		value = string(v)
+		return nil
+	})
+	return value, err
+}
+
+func (r *boltRepository) DeleteMetadata(ctx context.Context, id string, key string) error {
+	return r.update(func(tx *bolt.Tx) error {
+		session, err := getSession(tx, id)
+		if err != nil {
+			return err
+		}
+
+		mb := tx.Bucket(boltMetadataBucket).Bucket([]byte(id))
+		if mb == nil || mb.Get([]byte(key)) == nil {
+			return nil
+		}
+		if err := mb.Delete([]byte(key)); err != nil {
+			return fmt.Errorf("failed to delete metadata: %w", err)
+		}
+
+		session.UpdatedAt = time.Now()
+		return putRecord(tx, session)
+	})
+}
+
+// Maintenance operations
+
+func (r *boltRepository) DeleteAll(ctx context.Context) error {
+	return r.update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{boltSessionsBucket, boltMetadataBucket} {
+			if err := tx.DeleteBucket(name); err != nil {
+				return fmt.Errorf("failed to clear bucket %s: %w", name, err)
+			}
+			if _, err := tx.CreateBucket(name); err != nil {
+				return fmt.Errorf("failed to create bucket %s: %w", name, err)
+			}
+		}
+		return nil
+	})
+}
+
+func (r *boltRepository) DeleteOlderThan(ctx context.Context, duration time.Duration) error {
+	cutoff := time.Now().Add(-duration)
+	return r.update(func(tx *bolt.Tx) error {
+		for _, session := range allSessions(tx) {
+			if session.UpdatedAt.After(cutoff) {
+				continue
+			}
+			if err := deleteSession(tx, session.ID); err != nil {
+				return fmt.Errorf("failed to delete old session %s: %w", session.ID, err)
+			}
+		}
+		return nil
+	})
+}
+
+// Vacuum compacts the database into a new file and swaps it in, since bbolt never
+// shrinks its file on its own
+func (r *boltRepository) Vacuum(ctx context.Context) error {
+	if r.tx != nil {
+		return fmt.Errorf("vacuum cannot run inside a transaction")
+	}
+
+	src, err := r.open()
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	tmpPath := r.path + ".compact"
+	os.Remove(tmpPath)
+	dst, err := bolt.Open(tmpPath, 0600, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create compacted database: %w", err)
+	}
+	if err := bolt.Compact(dst, src, 0); err != nil {
+		dst.Close()
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to compact database: %w", err)
+	}
+	if err := dst.Close(); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to compact database: %w", err)
+	}
+
+	// Replace the file while still holding the lock on the original
+	if err := os.Rename(tmpPath, r.path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to replace database: %w", err)
+	}
+	return nil
+}
+
+// Verify runs bbolt's consistency check of the database file, then checks each session
+// record. Metadata is kept outside the records, so it isn't covered by their checksums.
+func (r *boltRepository) Verify(ctx context.Context) (*VerifyReport, error) {
+	report := &VerifyReport{}
+	err := r.view(func(tx *bolt.Tx) error {
+		for err := range tx.Check() {
+			report.Corrupt = append(report.Corrupt, CorruptRecord{Record: "database", Problem: err.Error()})
+		}
+		return tx.Bucket(boltSessionsBucket).ForEach(func(k, v []byte) error {
+			report.add(string(k), v)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+func (r *boltRepository) Export(ctx context.Context, w io.Writer) error {
+	sessions, err := r.List(ctx, nil)
+	if err != nil {
+		return err
+	}
+	return writeArchive(w, sessions)
+}
+
+// Import replaces every session with the ones in an archive in a single transaction
+func (r *boltRepository) Import(ctx context.Context, rd io.Reader) error {
+	sessions, err := readArchive(rd)
+	if err != nil {
+		return err
+	}
+
+	return r.update(func(tx *bolt.Tx) error {
+		if err := (&boltRepository{tx: tx}).DeleteAll(ctx); err != nil {
+			return fmt.Errorf("failed to clear existing data: %w", err)
+		}
+		for _, session := range sessions {
+			if err := putSession(tx, session); err != nil {
+				return fmt.Errorf("failed to import session %s: %w", session.ID, err)
+			}
+		}
+		return nil
+	})
+}
+
+// Change notification
+
+// Watch reports changes by listening for writes to the database file and comparing the
+// sessions before and after. The directory is watched rather than the file because
+// Vacuum replaces the file.
+func (r *boltRepository) Watch(ctx context.Context) (<-chan StorageEvent, error) {
+	if r.tx != nil {
+		return nil, fmt.Errorf("watch cannot run inside a transaction")
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create watcher: %w", err)
+	}
+	if err := watcher.Add(filepath.Dir(r.path)); err != nil {
+		watcher.Close()
+		return nil, fmt.Errorf("failed to watch storage directory: %w", err)
+	}
+
+	// Start watching before taking the snapshot so no change falls between the two
+	sessions, err := r.List(ctx, nil)
+	if err != nil {
+		watcher.Close()
+		return nil, err
+	}
+	watched := newWatchedSessions(sessions)
+
+	events := make(chan StorageEvent)
+	go func() {
+		defer close(events)
+		defer watcher.Close()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case _, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				// The snapshot below catches up on anything an overflow dropped
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if filepath.Base(event.Name) != BoltFileName || event.Op&(fsnotify.Write|fsnotify.Create) == 0 {
+					continue
+				}
+			}
+
+			sessions, err := r.List(ctx, nil)
+			if err != nil {
+				continue // Another process holds the database; its write will notify again
+			}
+			if !sendEvents(ctx, events, watched.diff(sessions)...) {
+				return
+			}
+		}
+	}()
+
+	return events, nil
+}
+
+// Transaction support
+
+func (r *boltRepository) BeginTx(ctx context.Context) (Transaction, error) {
+	db, err := r.open()
+	if err != nil {
+		return nil, err
+	}
+	tx, err := db.Begin(true)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	return &boltTransaction{boltRepository: &boltRepository{path: r.path, tx: tx}, db: db}, nil
+}
+
+// boltTransaction is a repository whose operations all run in one read-write transaction.
+// It keeps the database open, and so locked, until it is committed or rolled back.
+type boltTransaction struct {
+	*boltRepository
+	db *bolt.DB
+}
+
+func (t *boltTransaction) Commit() error {
+	defer t.db.Close()
+	return t.tx.Commit()
+}
+
+func (t *boltTransaction) Rollback() error {
+	defer t.db.Close()
+	return t.tx.Rollback()
+}
+
+func (t *boltTransaction) BeginTx(ctx context.Context) (Transaction, error) {
+	return t, nil // Nested transactions join the outer one
+}
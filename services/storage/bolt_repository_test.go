@@ -0,0 +1,72 @@
+package storage
+
+import (
+	"context"
+	"testing"
+
+	"claude-squad/services/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBoltRepository(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	repo, err := NewBoltRepository(dir)
+	require.NoError(t, err)
+
+	require.NoError(t, repo.CreateBatch(ctx, []*types.SessionData{
+		{ID: "a", Title: "alpha", Branch: "feat", Status: types.StatusRunning, Metadata: map[string]string{"k": "v"}},
+		{ID: "b", Title: "beta", Branch: "feat", Status: types.StatusPaused},
+	}))
+	assert.Error(t, repo.Create(ctx, &types.SessionData{ID: "a", Title: "dup"}))
+
+	got, err := repo.Get(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, "alpha", got.Title)
+	assert.Equal(t, map[string]string{"k": "v"}, got.Metadata)
+
+	require.NoError(t, repo.SetMetadata(ctx, "b", "pr", "42"))
+	require.NoError(t, repo.UpdateStatus(ctx, "b", types.StatusReady))
+	value, err := repo.GetMetadata(ctx, "b", "pr")
+	require.NoError(t, err)
+	assert.Equal(t, "42", value)
+
+	active, err := repo.GetActive(ctx)
+	require.NoError(t, err)
+	assert.Len(t, active, 2)
+
+	require.NoError(t, repo.Delete(ctx, "a"))
+	assert.Error(t, repo.Delete(ctx, "a"))
+	_, err = repo.GetMetadata(ctx, "a", "k")
+	assert.Error(t, err)
+
+	require.NoError(t, repo.Vacuum(ctx))
+
+	// Sessions persist across reopening the database
+	reopened, err := NewBoltRepository(dir)
+	require.NoError(t, err)
+	sessions, err := reopened.List(ctx, nil)
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	assert.Equal(t, "42", sessions[0].Metadata["pr"])
+}
+
+func TestBoltTransactionRollback(t *testing.T) {
+	ctx := context.Background()
+	repo, err := NewBoltRepository(t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, repo.Create(ctx, &types.SessionData{ID: "a", Title: "alpha"}))
+
+	tx, err := repo.BeginTx(ctx)
+	require.NoError(t, err)
+	require.NoError(t, tx.Create(ctx, &types.SessionData{ID: "b", Title: "beta"}))
+	require.NoError(t, tx.Delete(ctx, "a"))
+	require.NoError(t, tx.Rollback())
+
+	sessions, err := repo.List(ctx, nil)
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	assert.Equal(t, "a", sessions[0].ID)
+}
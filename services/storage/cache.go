@@ -0,0 +1,444 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"claude-squad/services/types"
+)
+
+// cachedRepository wraps another repository and answers reads from an in-memory copy of
+// every session, so frequent polling doesn't re-read the store each time. Writes go
+// through to the store and then refresh the sessions they touched. Changes made by other
+// processes arrive through the store's Watch, and if that can't be started or stops,
+// reads go straight to the store rather than risk serving stale sessions.
+type cachedRepository struct {
+	StorageRepository
+	ctx context.Context
+
+	mu sync.Mutex
+	// sessions is nil until the cache is first loaded, and after it is invalidated
+	sessions map[string]*types.SessionData
+	// watching is set while the watch that keeps sessions current is running
+	watching bool
+	// disabled is set once the watch fails, after which every read goes to the store
+	disabled bool
+}
+
+// NewCachedRepository wraps inner with a write-through cache kept current until ctx ends.
+// Nothing is read until the first query.
+func NewCachedRepository(ctx context.Context, inner StorageRepository) StorageRepository {
+	return &cachedRepository{StorageRepository: inner, ctx: ctx}
+}
+
+// cloneSession copies a session deeply enough that changing the copy can't change the cache
+func cloneSession(session *types.SessionData) *types.SessionData {
+	out := *session
+	out.Inputs = slices.Clone(session.Inputs)
+	out.Commands = slices.Clone(session.Commands)
+	out.Metadata = maps.Clone(session.Metadata)
+	out.Tags = slices.Clone(session.Tags)
+	return &out
+}
+
+// snapshot returns copies of every cached session, loading the cache first if needed.
+// ok is false when the cache is disabled and the store must be read instead.
+func (r *cachedRepository) snapshot(ctx context.Context) (sessions []*types.SessionData, ok bool, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.load(ctx); err != nil || r.disabled {
+		return nil, false, err
+	}
+	sessions = make([]*types.SessionData, 0, len(r.sessions))
+	for _, session := range r.sessions {
+		sessions = append(sessions, cloneSession(session))
+	}
+	return sessions, true, nil
+}
+
+// load fills the cache from the store, starting the watch first so no change made in
+// between is missed. The caller holds mu.
+func (r *cachedRepository) load(ctx context.Context) error {
+	if r.disabled || r.sessions != nil {
+		return nil
+	}
+	if !r.watching {
+		events, err := r.StorageRepository.Watch(r.ctx)
+		if err != nil {
+			r.disabled = true
+			return nil
+		}
+		r.watching = true
+		go r.watch(events)
+	}
+
+	sessions, err := r.StorageRepository.List(ctx, nil)
+	if err != nil {
+		return err
+	}
+	r.sessions = make(map[string]*types.SessionData, len(sessions))
+	for _, session := range sessions {
+		r.sessions[session.ID] = session
+	}
+	return nil
+}
+
+// watch applies changes reported by the store until the watch ends, then disables the
+// cache since it can no longer be kept current
+func (r *cachedRepository) watch(events <-chan StorageEvent) {
+	for event := range events {
+		r.mu.Lock()
+		if r.sessions != nil {
+			r.apply(event.ID, event.Session)
+		}
+		r.mu.Unlock()
+	}
+
+	r.mu.Lock()
+	r.watching = false
+	r.disabled = true
+	r.sessions = nil
+	r.mu.Unlock()
+}
+
+// apply stores the latest version of a session, or removes it when session is nil. A
+// version older than the cached one, from a watch event that lost the race with a write
+// through the cache, is ignored. The caller holds mu.
+func (r *cachedRepository) apply(id string, session *types.SessionData) {
+	if session == nil {
+		delete(r.sessions, id)
+		return
+	}
+	if cached, ok := r.sessions[id]; ok && cached.UpdatedAt.After(session.UpdatedAt) {
+		return
+	}
+	r.sessions[id] = cloneSession(session)
+}
+
+// refresh re-reads the given sessions from the store after a write
+func (r *cachedRepository) refresh(ctx context.Context, ids ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.sessions == nil {
+		return
+	}
+	for _, id := range ids {
+		session, err := r.StorageRepository.Get(ctx, id)
+		if err != nil {
+			if exists, err := r.StorageRepository.Exists(ctx, id); err == nil && !exists {
+				delete(r.sessions, id)
+				continue
+			}
+			// Unsure what the store holds, so reload everything on the next read
+			r.sessions = nil
+			return
+		}
+		r.sessions[id] = session
+	}
+}
+
+// invalidate drops the cache after a change too broad to refresh session by session
+func (r *cachedRepository) invalidate() {
+	r.mu.Lock()
+	r.sessions = nil
+	r.mu.Unlock()
+}
+
+func sessionIDsOf(sessions []*types.SessionData) []string {
+	ids := make([]string, len(sessions))
+	for i, session := range sessions {
+		ids[i] = session.ID
+	}
+	return ids
+}
+
+// Basic CRUD operations
+
+func (r *cachedRepository) Create(ctx context.Context, session *types.SessionData) error {
+	if err := r.StorageRepository.Create(ctx, session); err != nil {
+		return err
+	}
+	r.refresh(ctx, session.ID)
+	return nil
+}
+
+// lookup returns a copy of a cached session. ok is false when the cache is disabled and
+// the store must be asked instead.
+func (r *cachedRepository) lookup(ctx context.Context, id string) (session *types.SessionData, ok bool, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.load(ctx); err != nil || r.disabled {
+		return nil, false, err
+	}
+	if cached, found := r.sessions[id]; found {
+		return cloneSession(cached), true, nil
+	}
+	return nil, true, nil
+}
+
+func (r *cachedRepository) Get(ctx context.Context, id string) (*types.SessionData, error) {
+	session, ok, err := r.lookup(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return r.StorageRepository.Get(ctx, id)
+	}
+	if session == nil {
+		return nil, fmt.Errorf("session not found: %s", id)
+	}
+	return session, nil
+}
+
+func (r *cachedRepository) Update(ctx context.Context, session *types.SessionData) error {
+	err := r.StorageRepository.Update(ctx, session)
+	r.refresh(ctx, session.ID)
+	return err
+}
+
+func (r *cachedRepository) Delete(ctx context.Context, id string) error {
+	err := r.StorageRepository.Delete(ctx, id)
+	r.refresh(ctx, id)
+	return err
+}
+
+// Batch operations. A batch that fails partway may have written some sessions, so every
+// session in it is refreshed either way.
+
+func (r *cachedRepository) CreateBatch(ctx context.Context, sessions []*types.SessionData) error {
+	err := r.StorageRepository.CreateBatch(ctx, sessions)
+	r.refresh(ctx, sessionIDsOf(sessions)...)
+	return err
+}
+
+func (r *cachedRepository) UpdateBatch(ctx context.Context, sessions []*types.SessionData) error {
+	err := r.StorageRepository.UpdateBatch(ctx, sessions)
+	r.refresh(ctx, sessionIDsOf(sessions)...)
+	return err
+}
+
+func (r *cachedRepository) DeleteBatch(ctx context.Context, ids []string) error {
+	err := r.StorageRepository.DeleteBatch(ctx, ids)
+	r.refresh(ctx, ids...)
+	return err
+}
+
+// Query operations
+
+// query answers a query from the cache. ok is false when the cache is disabled and the
+// store must be asked instead.
+func (r *cachedRepository) query(ctx context.Context, opts *QueryOptions, terms []string) (sessions []*types.SessionData, ok bool, err error) {
+	sessions, ok, err = r.snapshot(ctx)
+	if err != nil || !ok {
+		return nil, ok, err
+	}
+
+	matched := sessions[:0]
+	for _, session := range sessions {
+		if matchesQuery(session, opts) && matchesSearch(session, terms) {
+			matched = append(matched, session)
+		}
+	}
+	sessions, err = sortAndPaginate(matched, opts)
+	return sessions, true, err
+}
+
+func (r *cachedRepository) List(ctx context.Context, opts *QueryOptions) ([]*types.SessionData, error) {
+	sessions, ok, err := r.query(ctx, opts, nil)
+	if !ok && err == nil {
+		return r.StorageRepository.List(ctx, opts)
+	}
+	return sessions, err
+}
+
+func (r *cachedRepository) Count(ctx context.Context, opts *QueryOptions) (int, error) {
+	sessions, err := r.List(ctx, opts)
+	if err != nil {
+		return 0, err
+	}
+	return len(sessions), nil
+}
+
+func (r *cachedRepository) Exists(ctx context.Context, id string) (bool, error) {
+	session, ok, err := r.lookup(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	if !ok {
+		return r.StorageRepository.Exists(ctx, id)
+	}
+	return session != nil, nil
+}
+
+// Specialized queries
+
+func (r *cachedRepository) GetByTitle(ctx context.Context, title string) (*types.SessionData, error) {
+	sessions, err := r.List(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, session := range sessions {
+		if session.Title == title {
+			return session, nil
+		}
+	}
+	return nil, fmt.Errorf("session not found with title: %s", title)
+}
+
+func (r *cachedRepository) GetByBranch(ctx context.Context, branch string) ([]*types.SessionData, error) {
+	return r.List(ctx, &QueryOptions{Branch: &branch})
+}
+
+func (r *cachedRepository) GetActive(ctx context.Context) ([]*types.SessionData, error) {
+	sessions, err := r.List(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	active := sessions[:0]
+	for _, session := range sessions {
+		if session.Status == types.StatusRunning || session.Status == types.StatusReady {
+			active = append(active, session)
+		}
+	}
+	return active, nil
+}
+
+func (r *cachedRepository) GetPaused(ctx context.Context) ([]*types.SessionData, error) {
+	paused := types.StatusPaused
+	return r.List(ctx, &QueryOptions{Status: &paused})
+}
+
+func (r *cachedRepository) GetByRepo(ctx context.Context, repoPath string) ([]*types.SessionData, error) {
+	return r.List(ctx, &QueryOptions{RepoPath: &repoPath})
+}
+
+func (r *cachedRepository) Search(ctx context.Context, query string, opts *QueryOptions) ([]*types.SessionData, error) {
+	sessions, ok, err := r.query(ctx, opts, searchTerms(query))
+	if !ok && err == nil {
+		return r.StorageRepository.Search(ctx, query, opts)
+	}
+	return sessions, err
+}
+
+// Tag operations
+
+func (r *cachedRepository) Tag(ctx context.Context, id string, tags ...string) error {
+	err := r.StorageRepository.Tag(ctx, id, tags...)
+	r.refresh(ctx, id)
+	return err
+}
+
+func (r *cachedRepository) Untag(ctx context.Context, id string, tags ...string) error {
+	err := r.StorageRepository.Untag(ctx, id, tags...)
+	r.refresh(ctx, id)
+	return err
+}
+
+func (r *cachedRepository) GetByTag(ctx context.Context, tag string) ([]*types.SessionData, error) {
+	return r.List(ctx, &QueryOptions{Tags: []string{tag}})
+}
+
+// Status operations
+
+func (r *cachedRepository) UpdateStatus(ctx context.Context, id string, status types.Status) error {
+	err := r.StorageRepository.UpdateStatus(ctx, id, status)
+	r.refresh(ctx, id)
+	return err
+}
+
+func (r *cachedRepository) UpdateStatusBatch(ctx context.Context, updates map[string]types.Status) error {
+	err := r.StorageRepository.UpdateStatusBatch(ctx, updates)
+	r.refresh(ctx, slices.Collect(maps.Keys(updates))...)
+	return err
+}
+
+// Metadata operations
+
+func (r *cachedRepository) SetMetadata(ctx context.Context, id string, key, value string) error {
+	err := r.StorageRepository.SetMetadata(ctx, id, key, value)
+	r.refresh(ctx, id)
+	return err
+}
+
+func (r *cachedRepository) GetMetadata(ctx context.Context, id string, key string) (string, error) {
+	session, err := r.Get(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	value, exists := session.Metadata[key]
+	if !exists {
+		return "", fmt.Errorf("metadata key not found: %s", key)
+	}
+	return value, nil
+}
+
+func (r *cachedRepository) DeleteMetadata(ctx context.Context, id string, key string) error {
+	err := r.StorageRepository.DeleteMetadata(ctx, id, key)
+	r.refresh(ctx, id)
+	return err
+}
+
+// Maintenance operations
+
+func (r *cachedRepository) DeleteAll(ctx context.Context) error {
+	defer r.invalidate()
+	return r.StorageRepository.DeleteAll(ctx)
+}
+
+func (r *cachedRepository) DeleteOlderThan(ctx context.Context, duration time.Duration) error {
+	defer r.invalidate()
+	return r.StorageRepository.DeleteOlderThan(ctx, duration)
+}
+
+func (r *cachedRepository) Import(ctx context.Context, rd io.Reader) error {
+	defer r.invalidate()
+	return r.StorageRepository.Import(ctx, rd)
+}
+
+// Repair and Unreadable pass through to stores that support them
+
+func (r *cachedRepository) Unreadable(ctx context.Context) ([]string, error) {
+	if repairer, ok := r.StorageRepository.(Repairer); ok {
+		return repairer.Unreadable(ctx)
+	}
+	return nil, nil
+}
+
+func (r *cachedRepository) Repair(ctx context.Context) (*RepairReport, error) {
+	repairer, ok := r.StorageRepository.(Repairer)
+	if !ok {
+		return &RepairReport{}, nil
+	}
+	defer r.invalidate()
+	return repairer.Repair(ctx)
+}
+
+// Transaction support
+
+// BeginTx runs the transaction against the store directly. The cache is dropped when it
+// commits rather than tracking what it wrote.
+func (r *cachedRepository) BeginTx(ctx context.Context) (Transaction, error) {
+	tx, err := r.StorageRepository.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &cachedTransaction{Transaction: tx, cache: r}, nil
+}
+
+// cachedTransaction invalidates the cache its transaction bypassed once it commits
+type cachedTransaction struct {
+	Transaction
+	cache *cachedRepository
+}
+
+func (t *cachedTransaction) Commit() error {
+	defer t.cache.invalidate()
+	return t.Transaction.Commit()
+}
@@ -0,0 +1,89 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"claude-squad/services/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// unwatchableRepository is a store whose Watch always fails
+type unwatchableRepository struct {
+	StorageRepository
+}
+
+func (r unwatchableRepository) Watch(ctx context.Context) (<-chan StorageEvent, error) {
+	return nil, fmt.Errorf("watch not supported")
+}
+
+func TestCachedRepository(t *testing.T) {
+	for name, open := range testBackends {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+
+			dir := t.TempDir()
+			inner, err := open(dir)
+			require.NoError(t, err)
+			repo := NewCachedRepository(ctx, inner)
+
+			// Writes through the cache are visible straight away
+			require.NoError(t, repo.Create(ctx, &types.SessionData{ID: "a", Title: "alpha", Status: types.StatusRunning}))
+			require.NoError(t, repo.SetMetadata(ctx, "a", "key", "value"))
+			session, err := repo.Get(ctx, "a")
+			require.NoError(t, err)
+			assert.Equal(t, "value", session.Metadata["key"])
+
+			// Changing a returned session doesn't change the cache
+			session.Metadata["key"] = "changed"
+			value, err := repo.GetMetadata(ctx, "a", "key")
+			require.NoError(t, err)
+			assert.Equal(t, "value", value)
+
+			// Changes made by another process arrive through the watch
+			writer, err := open(dir)
+			require.NoError(t, err)
+			require.NoError(t, writer.Create(ctx, &types.SessionData{ID: "b", Title: "bravo", Status: types.StatusPaused}))
+			require.NoError(t, writer.UpdateStatus(ctx, "a", types.StatusPaused))
+			require.Eventually(t, func() bool {
+				paused, err := repo.GetPaused(ctx)
+				return err == nil && len(paused) == 2
+			}, 5*time.Second, 10*time.Millisecond)
+
+			require.NoError(t, writer.Delete(ctx, "b"))
+			require.Eventually(t, func() bool {
+				exists, err := repo.Exists(ctx, "b")
+				return err == nil && !exists
+			}, 5*time.Second, 10*time.Millisecond)
+
+			require.NoError(t, repo.DeleteAll(ctx))
+			count, err := repo.Count(ctx, nil)
+			require.NoError(t, err)
+			assert.Equal(t, 0, count)
+		})
+	}
+}
+
+func TestCachedRepositoryWithoutWatch(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	inner, err := NewJSONRepository(dir)
+	require.NoError(t, err)
+	repo := NewCachedRepository(ctx, unwatchableRepository{inner})
+
+	// With no way to hear of other processes' changes, every read goes to the store
+	require.NoError(t, inner.Create(ctx, &types.SessionData{ID: "a", Title: "alpha"}))
+	sessions, err := repo.List(ctx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a"}, sessionIDs(sessions))
+
+	require.NoError(t, inner.Create(ctx, &types.SessionData{ID: "b", Title: "bravo"}))
+	sessions, err = repo.Search(ctx, "bravo", nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"b"}, sessionIDs(sessions))
+}
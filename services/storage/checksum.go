@@ -0,0 +1,126 @@
+package storage
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"claude-squad/services/types"
+)
+
+// checksumKey is the field of a stored record that holds its checksum
+const checksumKey = "checksum"
+
+// VerifyReport describes the outcome of checking every stored record against its checksum
+type VerifyReport struct {
+	// Checked is the number of records examined
+	Checked int
+	// Corrupt are the records that are truncated, can't be parsed or don't match their checksum
+	Corrupt []CorruptRecord
+	// Unverified is the number of records saved before checksums were recorded, which can
+	// only be checked for being parseable. Each gains a checksum the next time it is saved.
+	Unverified int
+}
+
+// CorruptRecord identifies a damaged record and what is wrong with it
+type CorruptRecord struct {
+	// Record is the session ID, or the file path for stores where the ID may be unreadable
+	Record  string
+	Problem string
+}
+
+// canonicalRecord returns the contents of a record in a form that doesn't depend on how
+// the store encoded it, with object keys sorted, whitespace removed and the checksum left
+// out. Postgres, for one, reorders the keys of the JSON it stores.
+func canonicalRecord(data []byte) ([]byte, string, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var record map[string]interface{}
+	if err := decoder.Decode(&record); err != nil {
+		return nil, "", err
+	}
+	if decoder.More() {
+		return nil, "", fmt.Errorf("trailing data after record")
+	}
+
+	checksum, _ := record[checksumKey].(string)
+	delete(record, checksumKey)
+	canonical, err := json.Marshal(record)
+	if err != nil {
+		return nil, "", err
+	}
+	return canonical, checksum, nil
+}
+
+func recordChecksum(canonical []byte) string {
+	sum := sha256.Sum256(canonical)
+	return hex.EncodeToString(sum[:])
+}
+
+// sealRecord encodes session for storage with a checksum of its contents, indented when
+// the record is meant to be read by people
+func sealRecord(session *types.SessionData, indent bool) ([]byte, error) {
+	record := *session
+	record.Checksum = ""
+	data, err := json.Marshal(&record)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal session: %w", err)
+	}
+	canonical, _, err := canonicalRecord(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal session: %w", err)
+	}
+
+	record.Checksum = recordChecksum(canonical)
+	if indent {
+		data, err = json.MarshalIndent(&record, "", "  ")
+	} else {
+		data, err = json.Marshal(&record)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal session: %w", err)
+	}
+	return data, nil
+}
+
+// verifyRecord checks a stored record, returning what is wrong with it or "" when it is
+// intact. sealed is false for a record that has no checksum to check.
+func verifyRecord(data []byte) (problem string, sealed bool) {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return "empty record", true
+	}
+	canonical, checksum, err := canonicalRecord(data)
+	if err != nil {
+		return fmt.Sprintf("unparseable, possibly truncated: %v", err), true
+	}
+	var session types.SessionData
+	if err := json.Unmarshal(data, &session); err != nil {
+		return fmt.Sprintf("not a session record: %v", err), true
+	}
+	if checksum == "" {
+		return "", false
+	}
+	if recordChecksum(canonical) != checksum {
+		return "checksum mismatch", true
+	}
+	return "", true
+}
+
+// intact reports whether a stored record is undamaged
+func intact(data []byte) bool {
+	problem, _ := verifyRecord(data)
+	return problem == ""
+}
+
+// add checks one record and adds the result to the report
+func (r *VerifyReport) add(record string, data []byte) {
+	r.Checked++
+	problem, sealed := verifyRecord(data)
+	if problem != "" {
+		r.Corrupt = append(r.Corrupt, CorruptRecord{Record: record, Problem: problem})
+	} else if !sealed {
+		r.Unverified++
+	}
+}
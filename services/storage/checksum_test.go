@@ -0,0 +1,87 @@
+package storage
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"claude-squad/services/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerify(t *testing.T) {
+	for name, open := range testBackends {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			repo, err := open(t.TempDir())
+			require.NoError(t, err)
+			require.NoError(t, repo.Create(ctx, &types.SessionData{ID: "a", Title: "alpha", Prompt: "<fix> & \"ship\""}))
+			require.NoError(t, repo.Create(ctx, &types.SessionData{ID: "b", Title: "bravo", Inputs: []types.InputRecord{{Text: "hi"}}}))
+			require.NoError(t, repo.SetMetadata(ctx, "b", "key", "value"))
+
+			report, err := repo.Verify(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, 2, report.Checked)
+			assert.Empty(t, report.Corrupt)
+			assert.Zero(t, report.Unverified)
+
+			// A session read back carries its old checksum, which saving it again replaces
+			session, err := repo.Get(ctx, "a")
+			require.NoError(t, err)
+			require.NoError(t, repo.Update(ctx, session))
+			report, err = repo.Verify(ctx)
+			require.NoError(t, err)
+			assert.Empty(t, report.Corrupt)
+		})
+	}
+}
+
+func TestVerifyDetectsDamage(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	repo, err := NewJSONRepository(dir)
+	require.NoError(t, err)
+	for _, id := range []string{"edited", "truncated", "intact"} {
+		require.NoError(t, repo.Create(ctx, &types.SessionData{ID: id, Title: id}))
+	}
+	require.NoError(t, repo.UpdateStatus(ctx, "edited", types.StatusPaused))
+
+	// A record still parseable but changed behind the store's back fails its checksum
+	path := filepath.Join(dir, "edited.json")
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, []byte(strings.Replace(string(data), `"title": "edited"`, `"title": "tampered"`, 1)), 0644))
+
+	path = filepath.Join(dir, "truncated.json")
+	data, err = os.ReadFile(path)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, data[:len(data)/2], 0644))
+
+	// Records saved before checksums existed are only checked for being parseable
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "legacy.json"), []byte(`{"id": "legacy", "title": "legacy"}`), 0644))
+
+	report, err := repo.Verify(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 4, report.Checked)
+	assert.Equal(t, 1, report.Unverified)
+	problems := map[string]string{}
+	for _, record := range report.Corrupt {
+		problems[filepath.Base(record.Record)] = record.Problem
+	}
+	assert.Equal(t, "checksum mismatch", problems["edited.json"])
+	assert.Contains(t, problems["truncated.json"], "unparseable")
+	assert.Len(t, problems, 2)
+
+	// Repair restores the edited record from the version before its last write
+	repaired, err := repo.(Repairer).Repair(ctx)
+	require.NoError(t, err)
+	assert.Contains(t, repaired.Restored, "edited")
+	session, err := repo.Get(ctx, "edited")
+	require.NoError(t, err)
+	assert.Equal(t, "edited", session.Title)
+	assert.NotEqual(t, types.StatusPaused, session.Status)
+}
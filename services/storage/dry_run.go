@@ -0,0 +1,138 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"claude-squad/services/types"
+)
+
+// dryRunRepository wraps another repository, reporting the changes asked of it instead
+// of making them. Reads pass straight through.
+type dryRunRepository struct {
+	StorageRepository
+	out io.Writer
+}
+
+// NewDryRunRepository wraps inner so that nothing is written to it, with each change that
+// would have been made described on out instead
+func NewDryRunRepository(inner StorageRepository, out io.Writer) StorageRepository {
+	return &dryRunRepository{StorageRepository: inner, out: out}
+}
+
+func (r *dryRunRepository) would(format string, args ...interface{}) {
+	fmt.Fprintf(r.out, "would "+format+"\n", args...)
+}
+
+func joinSessionIDs(sessions []*types.SessionData) string {
+	ids := make([]string, len(sessions))
+	for i, session := range sessions {
+		ids[i] = session.ID
+	}
+	return strings.Join(ids, ", ")
+}
+
+func (r *dryRunRepository) Create(ctx context.Context, session *types.SessionData) error {
+	r.would("create session %s", session.ID)
+	return nil
+}
+
+func (r *dryRunRepository) Update(ctx context.Context, session *types.SessionData) error {
+	r.would("update session %s", session.ID)
+	return nil
+}
+
+func (r *dryRunRepository) Delete(ctx context.Context, id string) error {
+	r.would("delete session %s", id)
+	return nil
+}
+
+func (r *dryRunRepository) CreateBatch(ctx context.Context, sessions []*types.SessionData) error {
+	r.would("create sessions %s", joinSessionIDs(sessions))
+	return nil
+}
+
+func (r *dryRunRepository) UpdateBatch(ctx context.Context, sessions []*types.SessionData) error {
+	r.would("update sessions %s", joinSessionIDs(sessions))
+	return nil
+}
+
+func (r *dryRunRepository) DeleteBatch(ctx context.Context, ids []string) error {
+	r.would("delete sessions %s", strings.Join(ids, ", "))
+	return nil
+}
+
+func (r *dryRunRepository) Tag(ctx context.Context, id string, tags ...string) error {
+	r.would("tag session %s with %s", id, strings.Join(tags, ", "))
+	return nil
+}
+
+func (r *dryRunRepository) Untag(ctx context.Context, id string, tags ...string) error {
+	r.would("untag %s from session %s", strings.Join(tags, ", "), id)
+	return nil
+}
+
+func (r *dryRunRepository) UpdateStatus(ctx context.Context, id string, status types.Status) error {
+	r.would("set the status of session %s to %s", id, statusName(status))
+	return nil
+}
+
+func (r *dryRunRepository) UpdateStatusBatch(ctx context.Context, updates map[string]types.Status) error {
+	for id, status := range updates {
+		r.would("set the status of session %s to %s", id, statusName(status))
+	}
+	return nil
+}
+
+func (r *dryRunRepository) SetMetadata(ctx context.Context, id string, key, value string) error {
+	r.would("set metadata %s of session %s", key, id)
+	return nil
+}
+
+func (r *dryRunRepository) DeleteMetadata(ctx context.Context, id string, key string) error {
+	r.would("delete metadata %s of session %s", key, id)
+	return nil
+}
+
+func (r *dryRunRepository) DeleteAll(ctx context.Context) error {
+	r.would("delete all sessions")
+	return nil
+}
+
+func (r *dryRunRepository) DeleteOlderThan(ctx context.Context, duration time.Duration) error {
+	r.would("delete sessions older than %s", duration)
+	return nil
+}
+
+func (r *dryRunRepository) Vacuum(ctx context.Context) error {
+	r.would("vacuum the store")
+	return nil
+}
+
+func (r *dryRunRepository) Import(ctx context.Context, rd io.Reader) error {
+	r.would("replace all sessions with the imported ones")
+	return nil
+}
+
+// Unreadable passes through to stores that support it, Repair is only reported
+
+func (r *dryRunRepository) Unreadable(ctx context.Context) ([]string, error) {
+	if repairer, ok := r.StorageRepository.(Repairer); ok {
+		return repairer.Unreadable(ctx)
+	}
+	return nil, nil
+}
+
+func (r *dryRunRepository) Repair(ctx context.Context) (*RepairReport, error) {
+	r.would("repair unreadable records")
+	return &RepairReport{}, nil
+}
+
+// Transaction support
+
+func (r *dryRunRepository) BeginTx(ctx context.Context) (Transaction, error) {
+	return &noOpTransaction{repo: r}, nil
+}
@@ -0,0 +1,48 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"claude-squad/services/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDryRunRepository(t *testing.T) {
+	ctx := context.Background()
+	inner, err := NewSQLiteRepository(t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, inner.Create(ctx, &types.SessionData{ID: "a", Title: "fix-auth", Status: types.StatusRunning}))
+
+	var out bytes.Buffer
+	repo := NewDryRunRepository(inner, &out)
+
+	// Reads see the real store
+	session, err := repo.Get(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, "fix-auth", session.Title)
+
+	require.NoError(t, repo.Create(ctx, &types.SessionData{ID: "b", Title: "other"}))
+	require.NoError(t, repo.UpdateStatus(ctx, "a", types.StatusPaused))
+	require.NoError(t, repo.DeleteOlderThan(ctx, time.Hour))
+	tx, err := repo.BeginTx(ctx)
+	require.NoError(t, err)
+	require.NoError(t, tx.Delete(ctx, "a"))
+	require.NoError(t, tx.Commit())
+	require.NoError(t, repo.DeleteAll(ctx))
+
+	// Writes only describe themselves
+	sessions, err := inner.List(ctx, nil)
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	assert.Equal(t, types.StatusRunning, sessions[0].Status)
+	assert.Equal(t, "would create session b\n"+
+		"would set the status of session a to paused\n"+
+		"would delete sessions older than 1h0m0s\n"+
+		"would delete session a\n"+
+		"would delete all sessions\n", out.String())
+}
@@ -0,0 +1,449 @@
+package storage
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"claude-squad/services/types"
+)
+
+// encryptedPrefix marks a field value encrypted by encryptedRepository. Values without it
+// are read as plaintext, so turning encryption on doesn't strand existing sessions.
+const encryptedPrefix = "enc:v1:"
+
+// encryptedRepository wraps another repository and encrypts the free-form text of each
+// session, its prompts, input, metadata values and diff snapshots, with AES-256-GCM before
+// it reaches the store. Fields the stores filter and sort on stay readable.
+type encryptedRepository struct {
+	inner StorageRepository
+	aead  cipher.AEAD
+}
+
+// NewEncryptedRepository wraps inner so session text is encrypted at rest with a 32-byte key
+func NewEncryptedRepository(inner StorageRepository, key []byte) (StorageRepository, error) {
+	if len(key) != 32 {
+		return nil, fmt.Errorf("encryption key must be 32 bytes, got %d", len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	return &encryptedRepository{inner: inner, aead: aead}, nil
+}
+
+func (r *encryptedRepository) encrypt(plaintext string) (string, error) {
+	if plaintext == "" {
+		return "", nil
+	}
+	nonce := make([]byte, r.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	sealed := r.aead.Seal(nonce, nonce, []byte(plaintext), nil)
+	return encryptedPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func (r *encryptedRepository) decrypt(value string) (string, error) {
+	encoded, ok := strings.CutPrefix(value, encryptedPrefix)
+	if !ok {
+		return value, nil
+	}
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(sealed) < r.aead.NonceSize() {
+		return "", fmt.Errorf("malformed encrypted value")
+	}
+	nonce, ciphertext := sealed[:r.aead.NonceSize()], sealed[r.aead.NonceSize():]
+	plaintext, err := r.aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt session data, is the storage key right? %w", err)
+	}
+	return string(plaintext), nil
+}
+
+// transform returns a copy of session with each encrypted field passed through fn
+func transform(session *types.SessionData, fn func(string) (string, error)) (*types.SessionData, error) {
+	out := *session
+	var err error
+	if out.Prompt, err = fn(session.Prompt); err != nil {
+		return nil, err
+	}
+	if out.DiffSnapshot, err = fn(session.DiffSnapshot); err != nil {
+		return nil, err
+	}
+	if session.Inputs != nil {
+		out.Inputs = make([]types.InputRecord, len(session.Inputs))
+		for i, input := range session.Inputs {
+			out.Inputs[i] = input
+			if out.Inputs[i].Text, err = fn(input.Text); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if session.Commands != nil {
+		out.Commands = make([]types.CommandRecord, len(session.Commands))
+		for i, command := range session.Commands {
+			out.Commands[i] = command
+			if out.Commands[i].Program, err = fn(command.Program); err != nil {
+				return nil, err
+			}
+			if command.Args != nil {
+				out.Commands[i].Args = make([]string, len(command.Args))
+				for j, arg := range command.Args {
+					if out.Commands[i].Args[j], err = fn(arg); err != nil {
+						return nil, err
+					}
+				}
+			}
+		}
+	}
+	if session.DiffHistory != nil {
+		out.DiffHistory = make([]types.DiffSample, len(session.DiffHistory))
+		for i, sample := range session.DiffHistory {
+			out.DiffHistory[i] = sample
+			if out.DiffHistory[i].Patch, err = fn(sample.Patch); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if session.Metadata != nil {
+		out.Metadata = make(map[string]string, len(session.Metadata))
+		for key, value := range session.Metadata {
+			if out.Metadata[key], err = fn(value); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return &out, nil
+}
+
+func (r *encryptedRepository) seal(session *types.SessionData) (*types.SessionData, error) {
+	return transform(session, r.encrypt)
+}
+
+func (r *encryptedRepository) open(session *types.SessionData) (*types.SessionData, error) {
+	if session == nil {
+		return nil, nil
+	}
+	opened, err := transform(session, r.decrypt)
+	if err != nil {
+		return nil, fmt.Errorf("session %s: %w", session.ID, err)
+	}
+	return opened, nil
+}
+
+func (r *encryptedRepository) sealAll(sessions []*types.SessionData) ([]*types.SessionData, error) {
+	sealed := make([]*types.SessionData, len(sessions))
+	for i, session := range sessions {
+		var err error
+		if sealed[i], err = r.seal(session); err != nil {
+			return nil, err
+		}
+	}
+	return sealed, nil
+}
+
+func (r *encryptedRepository) openAll(sessions []*types.SessionData, err error) ([]*types.SessionData, error) {
+	if err != nil {
+		return nil, err
+	}
+	opened := make([]*types.SessionData, len(sessions))
+	for i, session := range sessions {
+		if opened[i], err = r.open(session); err != nil {
+			return nil, err
+		}
+	}
+	return opened, nil
+}
+
+// stamp copies the timestamps the inner store set on a sealed copy back to the caller's
+// session, as the stores do for the session passed to them
+func stamp(session, sealed *types.SessionData) {
+	session.CreatedAt, session.UpdatedAt = sealed.CreatedAt, sealed.UpdatedAt
+}
+
+// Basic CRUD operations
+
+func (r *encryptedRepository) Create(ctx context.Context, session *types.SessionData) error {
+	sealed, err := r.seal(session)
+	if err != nil {
+		return err
+	}
+	if err := r.inner.Create(ctx, sealed); err != nil {
+		return err
+	}
+	stamp(session, sealed)
+	return nil
+}
+
+func (r *encryptedRepository) Get(ctx context.Context, id string) (*types.SessionData, error) {
+	session, err := r.inner.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return r.open(session)
+}
+
+func (r *encryptedRepository) Update(ctx context.Context, session *types.SessionData) error {
+	sealed, err := r.seal(session)
+	if err != nil {
+		return err
+	}
+	if err := r.inner.Update(ctx, sealed); err != nil {
+		return err
+	}
+	stamp(session, sealed)
+	return nil
+}
+
+func (r *encryptedRepository) Delete(ctx context.Context, id string) error {
+	return r.inner.Delete(ctx, id)
+}
+
+// Batch operations
+
+func (r *encryptedRepository) CreateBatch(ctx context.Context, sessions []*types.SessionData) error {
+	sealed, err := r.sealAll(sessions)
+	if err != nil {
+		return err
+	}
+	if err := r.inner.CreateBatch(ctx, sealed); err != nil {
+		return err
+	}
+	for i := range sessions {
+		stamp(sessions[i], sealed[i])
+	}
+	return nil
+}
+
+func (r *encryptedRepository) UpdateBatch(ctx context.Context, sessions []*types.SessionData) error {
+	sealed, err := r.sealAll(sessions)
+	if err != nil {
+		return err
+	}
+	if err := r.inner.UpdateBatch(ctx, sealed); err != nil {
+		return err
+	}
+	for i := range sessions {
+		stamp(sessions[i], sealed[i])
+	}
+	return nil
+}
+
+func (r *encryptedRepository) DeleteBatch(ctx context.Context, ids []string) error {
+	return r.inner.DeleteBatch(ctx, ids)
+}
+
+// Query operations
+
+func (r *encryptedRepository) List(ctx context.Context, opts *QueryOptions) ([]*types.SessionData, error) {
+	return r.openAll(r.inner.List(ctx, opts))
+}
+
+func (r *encryptedRepository) Count(ctx context.Context, opts *QueryOptions) (int, error) {
+	return r.inner.Count(ctx, opts)
+}
+
+func (r *encryptedRepository) Exists(ctx context.Context, id string) (bool, error) {
+	return r.inner.Exists(ctx, id)
+}
+
+// Specialized queries
+
+func (r *encryptedRepository) GetByTitle(ctx context.Context, title string) (*types.SessionData, error) {
+	session, err := r.inner.GetByTitle(ctx, title)
+	if err != nil {
+		return nil, err
+	}
+	return r.open(session)
+}
+
+func (r *encryptedRepository) GetByBranch(ctx context.Context, branch string) ([]*types.SessionData, error) {
+	return r.openAll(r.inner.GetByBranch(ctx, branch))
+}
+
+func (r *encryptedRepository) GetActive(ctx context.Context) ([]*types.SessionData, error) {
+	return r.openAll(r.inner.GetActive(ctx))
+}
+
+func (r *encryptedRepository) GetPaused(ctx context.Context) ([]*types.SessionData, error) {
+	return r.openAll(r.inner.GetPaused(ctx))
+}
+
+func (r *encryptedRepository) GetByRepo(ctx context.Context, repoPath string) ([]*types.SessionData, error) {
+	return r.openAll(r.inner.GetByRepo(ctx, repoPath))
+}
+
+// Search matches the decrypted sessions in memory, since the store can only see ciphertext
+func (r *encryptedRepository) Search(ctx context.Context, query string, opts *QueryOptions) ([]*types.SessionData, error) {
+	// Filter in the store, but sort and paginate only once the search has narrowed it down
+	var filter *QueryOptions
+	if opts != nil {
+		o := *opts
+		o.SortBy, o.SortOrder, o.Limit, o.Offset = "", "", 0, 0
+		filter = &o
+	}
+	sessions, err := r.openAll(r.inner.List(ctx, filter))
+	if err != nil {
+		return nil, err
+	}
+
+	terms := searchTerms(query)
+	matched := sessions[:0]
+	for _, session := range sessions {
+		if matchesSearch(session, terms) {
+			matched = append(matched, session)
+		}
+	}
+	return sortAndPaginate(matched, opts)
+}
+
+// Status operations
+
+func (r *encryptedRepository) UpdateStatus(ctx context.Context, id string, status types.Status) error {
+	return r.inner.UpdateStatus(ctx, id, status)
+}
+
+func (r *encryptedRepository) UpdateStatusBatch(ctx context.Context, updates map[string]types.Status) error {
+	return r.inner.UpdateStatusBatch(ctx, updates)
+}
+
+// Tag operations, with tags left readable so the stores can filter on them
+
+func (r *encryptedRepository) Tag(ctx context.Context, id string, tags ...string) error {
+	return r.inner.Tag(ctx, id, tags...)
+}
+
+func (r *encryptedRepository) Untag(ctx context.Context, id string, tags ...string) error {
+	return r.inner.Untag(ctx, id, tags...)
+}
+
+func (r *encryptedRepository) GetByTag(ctx context.Context, tag string) ([]*types.SessionData, error) {
+	return r.openAll(r.inner.GetByTag(ctx, tag))
+}
+
+// Metadata operations
+
+func (r *encryptedRepository) SetMetadata(ctx context.Context, id string, key, value string) error {
+	sealed, err := r.encrypt(value)
+	if err != nil {
+		return err
+	}
+	return r.inner.SetMetadata(ctx, id, key, sealed)
+}
+
+func (r *encryptedRepository) GetMetadata(ctx context.Context, id string, key string) (string, error) {
+	value, err := r.inner.GetMetadata(ctx, id, key)
+	if err != nil {
+		return "", err
+	}
+	return r.decrypt(value)
+}
+
+func (r *encryptedRepository) DeleteMetadata(ctx context.Context, id string, key string) error {
+	return r.inner.DeleteMetadata(ctx, id, key)
+}
+
+// Maintenance operations
+
+func (r *encryptedRepository) DeleteAll(ctx context.Context) error {
+	return r.inner.DeleteAll(ctx)
+}
+
+func (r *encryptedRepository) DeleteOlderThan(ctx context.Context, duration time.Duration) error {
+	return r.inner.DeleteOlderThan(ctx, duration)
+}
+
+func (r *encryptedRepository) Vacuum(ctx context.Context) error {
+	return r.inner.Vacuum(ctx)
+}
+
+// Verify checks the records as stored, still encrypted
+func (r *encryptedRepository) Verify(ctx context.Context) (*VerifyReport, error) {
+	return r.inner.Verify(ctx)
+}
+
+// Export archives the sessions still encrypted, so an archive is only readable with the key
+func (r *encryptedRepository) Export(ctx context.Context, w io.Writer) error {
+	return r.inner.Export(ctx, w)
+}
+
+func (r *encryptedRepository) Import(ctx context.Context, rd io.Reader) error {
+	return r.inner.Import(ctx, rd)
+}
+
+// Repair and Unreadable pass through to stores that support them
+
+func (r *encryptedRepository) Unreadable(ctx context.Context) ([]string, error) {
+	if repairer, ok := r.inner.(Repairer); ok {
+		return repairer.Unreadable(ctx)
+	}
+	return nil, nil
+}
+
+func (r *encryptedRepository) Repair(ctx context.Context) (*RepairReport, error) {
+	if repairer, ok := r.inner.(Repairer); ok {
+		return repairer.Repair(ctx)
+	}
+	return &RepairReport{}, nil
+}
+
+// Change notification
+
+func (r *encryptedRepository) Watch(ctx context.Context) (<-chan StorageEvent, error) {
+	inner, err := r.inner.Watch(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make(chan StorageEvent)
+	go func() {
+		defer close(events)
+		for event := range inner {
+			// A session that can't be decrypted is reported without its contents
+			if session, err := r.open(event.Session); err == nil {
+				event.Session = session
+			} else {
+				event.Session = nil
+			}
+			if !sendEvents(ctx, events, event) {
+				return
+			}
+		}
+	}()
+	return events, nil
+}
+
+// Transaction support
+
+func (r *encryptedRepository) BeginTx(ctx context.Context) (Transaction, error) {
+	tx, err := r.inner.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &encryptedTransaction{encryptedRepository: &encryptedRepository{inner: tx, aead: r.aead}, tx: tx}, nil
+}
+
+// encryptedTransaction encrypts the sessions written through a transaction of the inner store
+type encryptedTransaction struct {
+	*encryptedRepository
+	tx Transaction
+}
+
+func (t *encryptedTransaction) Commit() error {
+	return t.tx.Commit()
+}
+
+func (t *encryptedTransaction) Rollback() error {
+	return t.tx.Rollback()
+}
@@ -0,0 +1,78 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"claude-squad/services/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncryptedRepository(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	inner, err := NewJSONRepository(dir)
+	require.NoError(t, err)
+	key := bytes.Repeat([]byte{7}, 32)
+	repo, err := NewEncryptedRepository(inner, key)
+	require.NoError(t, err)
+
+	// Sessions written before encryption was turned on stay readable
+	require.NoError(t, inner.Create(ctx, &types.SessionData{ID: "legacy", Title: "legacy", Prompt: "old prompt"}))
+
+	session := &types.SessionData{
+		ID:       "a",
+		Title:    "fix-auth",
+		Prompt:   "use the secret signing scheme",
+		Inputs:   []types.InputRecord{{Kind: types.InputPrompt, Text: "also rotate tokens"}},
+		Commands: []types.CommandRecord{{Program: "vault", Args: []string{"login", "hunter2"}}},
+		Metadata: map[string]string{"ticket": "SEC-1"},
+
+		DiffHistory: []types.DiffSample{{Added: 1, Patch: "+const apiKey = 1"}},
+	}
+	require.NoError(t, repo.Create(ctx, session))
+	assert.False(t, session.CreatedAt.IsZero())
+
+	raw, err := os.ReadFile(filepath.Join(dir, "a.json"))
+	require.NoError(t, err)
+	for _, secret := range []string{"signing", "rotate", "SEC-1", "vault", "hunter2", "apiKey"} {
+		assert.NotContains(t, string(raw), secret)
+	}
+	assert.Contains(t, string(raw), "fix-auth")
+
+	got, err := repo.Get(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, "use the secret signing scheme", got.Prompt)
+	assert.Equal(t, "also rotate tokens", got.Inputs[0].Text)
+	assert.Equal(t, []types.CommandRecord{{Program: "vault", Args: []string{"login", "hunter2"}}}, got.Commands)
+	assert.Equal(t, "SEC-1", got.Metadata["ticket"])
+	assert.Equal(t, "+const apiKey = 1", got.DiffHistory[0].Patch)
+
+	legacy, err := repo.Get(ctx, "legacy")
+	require.NoError(t, err)
+	assert.Equal(t, "old prompt", legacy.Prompt)
+
+	require.NoError(t, repo.SetMetadata(ctx, "a", "pr", "42"))
+	value, err := repo.GetMetadata(ctx, "a", "pr")
+	require.NoError(t, err)
+	assert.Equal(t, "42", value)
+
+	found, err := repo.Search(ctx, "rotate", nil)
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	assert.Equal(t, "a", found[0].ID)
+
+	// The wrong key can't read the sessions
+	wrong, err := NewEncryptedRepository(inner, bytes.Repeat([]byte{8}, 32))
+	require.NoError(t, err)
+	_, err = wrong.Get(ctx, "a")
+	assert.Error(t, err)
+
+	_, err = NewEncryptedRepository(inner, []byte("short"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,53 @@
+package storage
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/zalando/go-keyring"
+)
+
+const (
+	// EncryptionKeyEnv holds a base64-encoded 32-byte storage key, overriding the keychain
+	EncryptionKeyEnv = "CS_STORAGE_KEY"
+
+	// The keychain entry the storage key is kept under
+	keyringService = "claude-squad"
+	keyringUser    = "storage-key"
+)
+
+// LoadEncryptionKey returns the key for encrypted storage: the one in $CS_STORAGE_KEY if
+// set, otherwise the one in the OS keychain, which is generated and saved on first use
+func LoadEncryptionKey() ([]byte, error) {
+	if encoded := os.Getenv(EncryptionKeyEnv); encoded != "" {
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil || len(key) != 32 {
+			return nil, fmt.Errorf("%s must be a base64-encoded 32-byte key", EncryptionKeyEnv)
+		}
+		return key, nil
+	}
+
+	encoded, err := keyring.Get(keyringService, keyringUser)
+	if err == nil {
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil || len(key) != 32 {
+			return nil, fmt.Errorf("storage key in the keychain is malformed")
+		}
+		return key, nil
+	}
+	if !errors.Is(err, keyring.ErrNotFound) {
+		return nil, fmt.Errorf("failed to read storage key from the keychain (set %s instead): %w", EncryptionKeyEnv, err)
+	}
+
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("failed to generate storage key: %w", err)
+	}
+	if err := keyring.Set(keyringService, keyringUser, base64.StdEncoding.EncodeToString(key)); err != nil {
+		return nil, fmt.Errorf("failed to save storage key to the keychain (set %s instead): %w", EncryptionKeyEnv, err)
+	}
+	return key, nil
+}
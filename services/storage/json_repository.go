@@ -4,19 +4,36 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 
+	"claude-squad/log"
 	"claude-squad/services/types"
+
+	"github.com/fsnotify/fsnotify"
 )
 
+// lockFileName is the file in the storage directory that processes sharing the store
+// lock before touching session files
+const lockFileName = ".lock"
+
+// backupSuffix is appended to a session file's name for the copy of its previous version
+const backupSuffix = ".bak"
+
+// quarantineDir is the subdirectory Repair moves unrecoverable session files into
+const quarantineDir = "corrupt"
+
 // jsonRepository is a JSON file-based implementation of StorageRepository
 type jsonRepository struct {
 	basePath string
-	mu       sync.RWMutex
+	// mu serializes goroutines in this process; the lock file serializes processes, since
+	// the TUI, daemon and CLI all open the same store
+	mu sync.RWMutex
 }
 
 // NewJSONRepository creates a new JSON-based storage repository
@@ -31,6 +48,41 @@ func NewJSONRepository(basePath string) (StorageRepository, error) {
 	}, nil
 }
 
+// lock takes the store's lock, shared for reads or exclusive for writes, and returns the
+// function that releases it. The advisory lock is released by the OS if the process dies,
+// so a crashed process can never leave a stale lock behind.
+func (r *jsonRepository) lock(exclusive bool) (func(), error) {
+	if exclusive {
+		r.mu.Lock()
+	} else {
+		r.mu.RLock()
+	}
+	release := func() {
+		if exclusive {
+			r.mu.Unlock()
+		} else {
+			r.mu.RUnlock()
+		}
+	}
+
+	f, err := os.OpenFile(filepath.Join(r.basePath, lockFileName), os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		release()
+		return nil, fmt.Errorf("failed to open lock file: %w", err)
+	}
+	if err := lockFile(f, exclusive); err != nil {
+		f.Close()
+		release()
+		return nil, fmt.Errorf("failed to lock storage: %w", err)
+	}
+
+	return func() {
+		unlockFile(f)
+		f.Close()
+		release()
+	}, nil
+}
+
 func (r *jsonRepository) getFilePath(id string) string {
 	return filepath.Join(r.basePath, fmt.Sprintf("%s.json", id))
 }
@@ -50,110 +102,248 @@ func (r *jsonRepository) getAllFilePaths() ([]string, error) {
 	return paths, nil
 }
 
-// Basic CRUD operations
+// The helpers below expect the caller to hold the lock
 
-func (r *jsonRepository) Create(ctx context.Context, session *types.SessionData) error {
-	r.mu.Lock()
-	defer r.mu.Unlock()
+func (r *jsonRepository) read(id string) (*types.SessionData, error) {
+	filePath := r.getFilePath(id)
 
-	if session.ID == "" {
-		return fmt.Errorf("session ID is required")
+	data, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("session not found: %s", id)
+		}
+		return nil, fmt.Errorf("failed to read session file: %w", err)
 	}
 
-	filePath := r.getFilePath(session.ID)
-
-	// Check if already exists
-	if _, err := os.Stat(filePath); err == nil {
-		return fmt.Errorf("session already exists: %s", session.ID)
+	var session types.SessionData
+	if err := json.Unmarshal(data, &session); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal session: %w", err)
 	}
 
-	session.CreatedAt = time.Now()
-	session.UpdatedAt = time.Now()
+	return &session, nil
+}
 
-	data, err := json.MarshalIndent(session, "", "  ")
+// write saves a session, keeping the version it replaces as a .bak file
+func (r *jsonRepository) write(session *types.SessionData) error {
+	data, err := sealRecord(session, true)
 	if err != nil {
-		return fmt.Errorf("failed to marshal session: %w", err)
+		return err
 	}
 
-	if err := ioutil.WriteFile(filePath, data, 0644); err != nil {
+	filePath := r.getFilePath(session.ID)
+	if previous, err := ioutil.ReadFile(filePath); err == nil && intact(previous) {
+		if err := writeFileAtomic(filePath+backupSuffix, previous, 0644); err != nil {
+			return fmt.Errorf("failed to back up session file: %w", err)
+		}
+	}
+
+	if err := writeFileAtomic(filePath, data, 0644); err != nil {
 		return fmt.Errorf("failed to write session file: %w", err)
 	}
 
 	return nil
 }
 
-func (r *jsonRepository) Get(ctx context.Context, id string) (*types.SessionData, error) {
-	r.mu.RLock()
-	defer r.mu.RUnlock()
+// writeFileAtomic writes data to a temporary file next to path and renames it into
+// place, so readers and crashes only ever see the old or the new contents
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
 
-	filePath := r.getFilePath(id)
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Chmod(tmpPath, perm); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	return nil
+}
 
-	data, err := ioutil.ReadFile(filePath)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return nil, fmt.Errorf("session not found: %s", id)
-		}
-		return nil, fmt.Errorf("failed to read session file: %w", err)
+func (r *jsonRepository) create(session *types.SessionData) error {
+	if session.ID == "" {
+		return fmt.Errorf("session ID is required")
 	}
 
-	var session types.SessionData
-	if err := json.Unmarshal(data, &session); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal session: %w", err)
+	// Check if already exists
+	if _, err := os.Stat(r.getFilePath(session.ID)); err == nil {
+		return fmt.Errorf("session already exists: %s", session.ID)
 	}
 
-	return &session, nil
-}
+	session.CreatedAt = time.Now()
+	session.UpdatedAt = time.Now()
 
-func (r *jsonRepository) Update(ctx context.Context, session *types.SessionData) error {
-	r.mu.Lock()
-	defer r.mu.Unlock()
+	return r.write(session)
+}
 
+func (r *jsonRepository) update(session *types.SessionData) error {
 	if session.ID == "" {
 		return fmt.Errorf("session ID is required")
 	}
 
-	filePath := r.getFilePath(session.ID)
-
 	// Check if exists
-	if _, err := os.Stat(filePath); os.IsNotExist(err) {
+	if _, err := os.Stat(r.getFilePath(session.ID)); os.IsNotExist(err) {
 		return fmt.Errorf("session not found: %s", session.ID)
 	}
 
 	session.UpdatedAt = time.Now()
 
-	data, err := json.MarshalIndent(session, "", "  ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal session: %w", err)
-	}
+	return r.write(session)
+}
 
-	if err := ioutil.WriteFile(filePath, data, 0644); err != nil {
-		return fmt.Errorf("failed to write session file: %w", err)
+func (r *jsonRepository) delete(id string) error {
+	filePath := r.getFilePath(id)
+	if err := os.Remove(filePath); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("session not found: %s", id)
+		}
+		return fmt.Errorf("failed to delete session file: %w", err)
 	}
+	os.Remove(filePath + backupSuffix)
 
 	return nil
 }
 
-func (r *jsonRepository) Delete(ctx context.Context, id string) error {
-	r.mu.Lock()
-	defer r.mu.Unlock()
+// list returns the sessions matching opts whose text contains every search term
+func (r *jsonRepository) list(opts *QueryOptions, terms []string) ([]*types.SessionData, error) {
+	paths, err := r.getAllFilePaths()
+	if err != nil {
+		return nil, err
+	}
 
-	filePath := r.getFilePath(id)
+	var sessions []*types.SessionData
+	for _, path := range paths {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			continue // Skip files that can't be read
+		}
 
-	if err := os.Remove(filePath); err != nil {
-		if os.IsNotExist(err) {
-			return fmt.Errorf("session not found: %s", id)
+		var session types.SessionData
+		if err := json.Unmarshal(data, &session); err != nil {
+			// The logger is only set up by the TUI and daemon
+			if log.WarningLog != nil {
+				log.WarningLog.Printf("skipping unreadable session file %s, run `cs doctor --repair` to recover it: %v", path, err)
+			}
+			continue
 		}
-		return fmt.Errorf("failed to delete session file: %w", err)
+
+		// Apply filters if options provided
+		if !matchesQuery(&session, opts) || !matchesSearch(&session, terms) {
+			continue
+		}
+
+		sessions = append(sessions, &session)
+	}
+
+	return sortAndPaginate(sessions, opts)
+}
+
+func (r *jsonRepository) deleteAll() error {
+	paths, err := r.getAllFilePaths()
+	if err != nil {
+		return err
+	}
+
+	for _, path := range paths {
+		if err := os.Remove(path); err != nil {
+			return fmt.Errorf("failed to delete file %s: %w", path, err)
+		}
+		os.Remove(path + backupSuffix)
 	}
 
 	return nil
 }
 
+// modify applies fn to a stored session and saves it, holding the lock throughout so no
+// other process can write the session in between
+func (r *jsonRepository) modify(id string, fn func(session *types.SessionData) bool) error {
+	unlock, err := r.lock(true)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	session, err := r.read(id)
+	if err != nil {
+		return err
+	}
+	if !fn(session) {
+		return nil
+	}
+	return r.update(session)
+}
+
+// Basic CRUD operations
+
+func (r *jsonRepository) Create(ctx context.Context, session *types.SessionData) error {
+	unlock, err := r.lock(true)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	return r.create(session)
+}
+
+func (r *jsonRepository) Get(ctx context.Context, id string) (*types.SessionData, error) {
+	unlock, err := r.lock(false)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	return r.read(id)
+}
+
+func (r *jsonRepository) Update(ctx context.Context, session *types.SessionData) error {
+	unlock, err := r.lock(true)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	return r.update(session)
+}
+
+func (r *jsonRepository) Delete(ctx context.Context, id string) error {
+	unlock, err := r.lock(true)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	return r.delete(id)
+}
+
 // Batch operations
 
 func (r *jsonRepository) CreateBatch(ctx context.Context, sessions []*types.SessionData) error {
+	unlock, err := r.lock(true)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	for _, session := range sessions {
-		if err := r.Create(ctx, session); err != nil {
+		if err := r.create(session); err != nil {
 			return fmt.Errorf("failed to create session %s: %w", session.ID, err)
 		}
 	}
@@ -161,8 +351,14 @@ func (r *jsonRepository) CreateBatch(ctx context.Context, sessions []*types.Sess
 }
 
 func (r *jsonRepository) UpdateBatch(ctx context.Context, sessions []*types.SessionData) error {
+	unlock, err := r.lock(true)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	for _, session := range sessions {
-		if err := r.Update(ctx, session); err != nil {
+		if err := r.update(session); err != nil {
 			return fmt.Errorf("failed to update session %s: %w", session.ID, err)
 		}
 	}
@@ -170,8 +366,14 @@ func (r *jsonRepository) UpdateBatch(ctx context.Context, sessions []*types.Sess
 }
 
 func (r *jsonRepository) DeleteBatch(ctx context.Context, ids []string) error {
+	unlock, err := r.lock(true)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	for _, id := range ids {
-		if err := r.Delete(ctx, id); err != nil {
+		if err := r.delete(id); err != nil {
 			return fmt.Errorf("failed to delete session %s: %w", id, err)
 		}
 	}
@@ -181,79 +383,13 @@ func (r *jsonRepository) DeleteBatch(ctx context.Context, ids []string) error {
 // Query operations
 
 func (r *jsonRepository) List(ctx context.Context, opts *QueryOptions) ([]*types.SessionData, error) {
-	r.mu.RLock()
-	defer r.mu.RUnlock()
-
-	paths, err := r.getAllFilePaths()
+	unlock, err := r.lock(false)
 	if err != nil {
 		return nil, err
 	}
+	defer unlock()
 
-	var sessions []*types.SessionData
-	for _, path := range paths {
-		data, err := ioutil.ReadFile(path)
-		if err != nil {
-			continue // Skip files that can't be read
-		}
-
-		var session types.SessionData
-		if err := json.Unmarshal(data, &session); err != nil {
-			continue // Skip invalid JSON files
-		}
-
-		// Apply filters if options provided
-		if opts != nil {
-			if opts.Status != nil && session.Status != *opts.Status {
-				continue
-			}
-			if opts.Branch != nil && session.Branch != *opts.Branch {
-				continue
-			}
-			if opts.Path != nil && session.Path != *opts.Path {
-				continue
-			}
-			if opts.Program != nil && session.Program != *opts.Program {
-				continue
-			}
-			if opts.AutoYes != nil && session.AutoYes != *opts.AutoYes {
-				continue
-			}
-			if opts.CreatedAfter != nil && session.CreatedAt.Before(*opts.CreatedAfter) {
-				continue
-			}
-			if opts.CreatedBefore != nil && session.CreatedAt.After(*opts.CreatedBefore) {
-				continue
-			}
-			if opts.UpdatedAfter != nil && session.UpdatedAt.Before(*opts.UpdatedAfter) {
-				continue
-			}
-			if opts.UpdatedBefore != nil && session.UpdatedAt.After(*opts.UpdatedBefore) {
-				continue
-			}
-		}
-
-		sessions = append(sessions, &session)
-	}
-
-	// Apply sorting
-	if opts != nil && opts.SortBy != "" {
-		sortSessions(sessions, opts.SortBy, opts.SortOrder)
-	}
-
-	// Apply pagination
-	if opts != nil && opts.Limit > 0 {
-		start := opts.Offset
-		if start >= len(sessions) {
-			return []*types.SessionData{}, nil
-		}
-		end := start + opts.Limit
-		if end > len(sessions) {
-			end = len(sessions)
-		}
-		sessions = sessions[start:end]
-	}
-
-	return sessions, nil
+	return r.list(opts, nil)
 }
 
 func (r *jsonRepository) Count(ctx context.Context, opts *QueryOptions) (int, error) {
@@ -265,11 +401,14 @@ func (r *jsonRepository) Count(ctx context.Context, opts *QueryOptions) (int, er
 }
 
 func (r *jsonRepository) Exists(ctx context.Context, id string) (bool, error) {
-	r.mu.RLock()
-	defer r.mu.RUnlock()
+	unlock, err := r.lock(false)
+	if err != nil {
+		return false, err
+	}
+	defer unlock()
 
 	filePath := r.getFilePath(id)
-	_, err := os.Stat(filePath)
+	_, err = os.Stat(filePath)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return false, nil
@@ -323,18 +462,28 @@ func (r *jsonRepository) GetPaused(ctx context.Context) ([]*types.SessionData, e
 	return r.List(ctx, &QueryOptions{Status: &paused})
 }
 
-// Status operations
+func (r *jsonRepository) GetByRepo(ctx context.Context, repoPath string) ([]*types.SessionData, error) {
+	return r.List(ctx, &QueryOptions{RepoPath: &repoPath})
+}
 
-func (r *jsonRepository) UpdateStatus(ctx context.Context, id string, status types.Status) error {
-	session, err := r.Get(ctx, id)
+// Search matches substrings of session text, scanning every session file
+func (r *jsonRepository) Search(ctx context.Context, query string, opts *QueryOptions) ([]*types.SessionData, error) {
+	unlock, err := r.lock(false)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	defer unlock()
 
-	session.Status = status
-	session.UpdatedAt = time.Now()
+	return r.list(opts, searchTerms(query))
+}
+
+// Status operations
 
-	return r.Update(ctx, session)
+func (r *jsonRepository) UpdateStatus(ctx context.Context, id string, status types.Status) error {
+	return r.modify(id, func(session *types.SessionData) bool {
+		session.Status = status
+		return true
+	})
 }
 
 func (r *jsonRepository) UpdateStatusBatch(ctx context.Context, updates map[string]types.Status) error {
@@ -346,21 +495,41 @@ func (r *jsonRepository) UpdateStatusBatch(ctx context.Context, updates map[stri
 	return nil
 }
 
-// Metadata operations
+// Tag operations
 
-func (r *jsonRepository) SetMetadata(ctx context.Context, id string, key, value string) error {
-	session, err := r.Get(ctx, id)
+func (r *jsonRepository) Tag(ctx context.Context, id string, tags ...string) error {
+	var tagErr error
+	err := r.modify(id, func(session *types.SessionData) bool {
+		var added bool
+		added, tagErr = addTags(session, tags)
+		return added
+	})
 	if err != nil {
 		return err
 	}
+	return tagErr
+}
 
-	if session.Metadata == nil {
-		session.Metadata = make(map[string]string)
-	}
-	session.Metadata[key] = value
-	session.UpdatedAt = time.Now()
+func (r *jsonRepository) Untag(ctx context.Context, id string, tags ...string) error {
+	return r.modify(id, func(session *types.SessionData) bool {
+		return removeTags(session, tags)
+	})
+}
+
+func (r *jsonRepository) GetByTag(ctx context.Context, tag string) ([]*types.SessionData, error) {
+	return r.List(ctx, &QueryOptions{Tags: []string{tag}})
+}
 
-	return r.Update(ctx, session)
+// Metadata operations
+
+func (r *jsonRepository) SetMetadata(ctx context.Context, id string, key, value string) error {
+	return r.modify(id, func(session *types.SessionData) bool {
+		if session.Metadata == nil {
+			session.Metadata = make(map[string]string)
+		}
+		session.Metadata[key] = value
+		return true
+	})
 }
 
 func (r *jsonRepository) GetMetadata(ctx context.Context, id string, key string) (string, error) {
@@ -382,49 +551,42 @@ func (r *jsonRepository) GetMetadata(ctx context.Context, id string, key string)
 }
 
 func (r *jsonRepository) DeleteMetadata(ctx context.Context, id string, key string) error {
-	session, err := r.Get(ctx, id)
-	if err != nil {
-		return err
-	}
-
-	if session.Metadata != nil {
+	return r.modify(id, func(session *types.SessionData) bool {
+		if session.Metadata == nil {
+			return false
+		}
 		delete(session.Metadata, key)
-		session.UpdatedAt = time.Now()
-		return r.Update(ctx, session)
-	}
-
-	return nil
+		return true
+	})
 }
 
 // Maintenance operations
 
 func (r *jsonRepository) DeleteAll(ctx context.Context) error {
-	r.mu.Lock()
-	defer r.mu.Unlock()
-
-	paths, err := r.getAllFilePaths()
+	unlock, err := r.lock(true)
 	if err != nil {
 		return err
 	}
+	defer unlock()
 
-	for _, path := range paths {
-		if err := os.Remove(path); err != nil {
-			return fmt.Errorf("failed to delete file %s: %w", path, err)
-		}
-	}
-
-	return nil
+	return r.deleteAll()
 }
 
 func (r *jsonRepository) DeleteOlderThan(ctx context.Context, duration time.Duration) error {
+	unlock, err := r.lock(true)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	cutoff := time.Now().Add(-duration)
-	sessions, err := r.List(ctx, &QueryOptions{UpdatedBefore: &cutoff})
+	sessions, err := r.list(&QueryOptions{UpdatedBefore: &cutoff}, nil)
 	if err != nil {
 		return err
 	}
 
 	for _, session := range sessions {
-		if err := r.Delete(ctx, session.ID); err != nil {
+		if err := r.delete(session.ID); err != nil {
 			return fmt.Errorf("failed to delete old session %s: %w", session.ID, err)
 		}
 	}
@@ -432,79 +594,249 @@ func (r *jsonRepository) DeleteOlderThan(ctx context.Context, duration time.Dura
 	return nil
 }
 
-func (r *jsonRepository) Vacuum(ctx context.Context) error {
-	// For JSON repository, vacuum could compact files or clean up metadata
-	// Currently a no-op
-	return nil
-}
+// Unreadable returns the session files that can't be parsed
+func (r *jsonRepository) Unreadable(ctx context.Context) ([]string, error) {
+	unlock, err := r.lock(false)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
 
-func (r *jsonRepository) Backup(ctx context.Context, backupPath string) error {
-	r.mu.RLock()
-	defer r.mu.RUnlock()
+	paths, err := r.getAllFilePaths()
+	if err != nil {
+		return nil, err
+	}
 
-	// Create backup directory
-	if err := os.MkdirAll(backupPath, 0755); err != nil {
-		return fmt.Errorf("failed to create backup directory: %w", err)
+	var unreadable []string
+	for _, path := range paths {
+		data, err := ioutil.ReadFile(path)
+		if err != nil || !intact(data) {
+			unreadable = append(unreadable, path)
+		}
 	}
+	return unreadable, nil
+}
 
-	paths, err := r.getAllFilePaths()
+// Repair recovers session files that can't be parsed from their .bak copy, moves the ones
+// that can't be recovered into the corrupt/ subdirectory, and removes temporary files left
+// by interrupted writes
+func (r *jsonRepository) Repair(ctx context.Context) (*RepairReport, error) {
+	unlock, err := r.lock(true)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	defer unlock()
+
+	entries, err := os.ReadDir(r.basePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read directory: %w", err)
 	}
 
-	for _, srcPath := range paths {
-		filename := filepath.Base(srcPath)
-		dstPath := filepath.Join(backupPath, filename)
+	report := &RepairReport{}
+	for _, entry := range entries {
+		name := entry.Name()
+		path := filepath.Join(r.basePath, name)
+		if entry.IsDir() {
+			continue
+		}
 
-		data, err := ioutil.ReadFile(srcPath)
+		// Only writes holding the lock create temporary files, so any left now are orphans
+		if strings.HasPrefix(name, ".") && strings.Contains(name, ".json.tmp-") {
+			if err := os.Remove(path); err != nil {
+				return report, fmt.Errorf("failed to remove temporary file %s: %w", path, err)
+			}
+			continue
+		}
+
+		if filepath.Ext(name) != ".json" {
+			continue
+		}
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return report, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		if intact(data) {
+			continue
+		}
+
+		quarantined, err := r.quarantine(path)
 		if err != nil {
-			return fmt.Errorf("failed to read file %s: %w", srcPath, err)
+			return report, err
 		}
+		report.Quarantined = append(report.Quarantined, quarantined)
 
-		if err := ioutil.WriteFile(dstPath, data, 0644); err != nil {
-			return fmt.Errorf("failed to write backup file %s: %w", dstPath, err)
+		previous, err := ioutil.ReadFile(path + backupSuffix)
+		if err != nil || !intact(previous) {
+			continue
+		}
+		if err := writeFileAtomic(path, previous, 0644); err != nil {
+			return report, fmt.Errorf("failed to restore %s: %w", path, err)
 		}
+		report.Restored = append(report.Restored, strings.TrimSuffix(name, ".json"))
 	}
 
-	return nil
+	return report, nil
 }
 
-func (r *jsonRepository) Restore(ctx context.Context, backupPath string) error {
-	r.mu.Lock()
-	defer r.mu.Unlock()
+// quarantine moves a session file into the corrupt/ subdirectory and returns its new path
+func (r *jsonRepository) quarantine(path string) (string, error) {
+	dir := filepath.Join(r.basePath, quarantineDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create quarantine directory: %w", err)
+	}
 
-	// Clear existing data
-	if err := r.DeleteAll(ctx); err != nil {
-		return fmt.Errorf("failed to clear existing data: %w", err)
+	dst := filepath.Join(dir, fmt.Sprintf("%s.%d", filepath.Base(path), time.Now().UnixNano()))
+	if err := os.Rename(path, dst); err != nil {
+		return "", fmt.Errorf("failed to quarantine %s: %w", path, err)
 	}
+	return dst, nil
+}
 
-	// Read backup files
-	entries, err := os.ReadDir(backupPath)
+// Verify checks each session file, reporting damaged ones by path since their ID may not
+// be readable
+func (r *jsonRepository) Verify(ctx context.Context) (*VerifyReport, error) {
+	unlock, err := r.lock(false)
 	if err != nil {
-		return fmt.Errorf("failed to read backup directory: %w", err)
+		return nil, err
 	}
+	defer unlock()
 
-	for _, entry := range entries {
-		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+	paths, err := r.getAllFilePaths()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &VerifyReport{}
+	for _, path := range paths {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			report.Checked++
+			report.Corrupt = append(report.Corrupt, CorruptRecord{Record: path, Problem: fmt.Sprintf("unreadable: %v", err)})
 			continue
 		}
+		report.add(path, data)
+	}
+	return report, nil
+}
 
-		srcPath := filepath.Join(backupPath, entry.Name())
-		dstPath := filepath.Join(r.basePath, entry.Name())
+func (r *jsonRepository) Vacuum(ctx context.Context) error {
+	// For JSON repository, vacuum could compact files or clean up metadata
+	// Currently a no-op
+	return nil
+}
 
-		data, err := ioutil.ReadFile(srcPath)
-		if err != nil {
-			return fmt.Errorf("failed to read backup file %s: %w", srcPath, err)
-		}
+func (r *jsonRepository) Export(ctx context.Context, w io.Writer) error {
+	unlock, err := r.lock(false)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	sessions, err := r.list(nil, nil)
+	if err != nil {
+		return err
+	}
+	return writeArchive(w, sessions)
+}
+
+// Import reads the whole archive before touching the store, so a bad archive leaves the
+// existing sessions in place
+func (r *jsonRepository) Import(ctx context.Context, rd io.Reader) error {
+	sessions, err := readArchive(rd)
+	if err != nil {
+		return err
+	}
 
-		if err := ioutil.WriteFile(dstPath, data, 0644); err != nil {
-			return fmt.Errorf("failed to restore file %s: %w", dstPath, err)
+	unlock, err := r.lock(true)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if err := r.deleteAll(); err != nil {
+		return fmt.Errorf("failed to clear existing data: %w", err)
+	}
+	for _, session := range sessions {
+		if err := r.write(session); err != nil {
+			return fmt.Errorf("failed to import session %s: %w", session.ID, err)
 		}
 	}
 
 	return nil
 }
 
+// Change notification
+
+// Watch reports changes to session files using filesystem notifications. Writes replace
+// the file atomically, so each change shows up as the new file appearing.
+func (r *jsonRepository) Watch(ctx context.Context) (<-chan StorageEvent, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create watcher: %w", err)
+	}
+	if err := watcher.Add(r.basePath); err != nil {
+		watcher.Close()
+		return nil, fmt.Errorf("failed to watch storage directory: %w", err)
+	}
+
+	// Start watching before taking the snapshot so no change falls between the two
+	sessions, err := r.List(ctx, nil)
+	if err != nil {
+		watcher.Close()
+		return nil, err
+	}
+	watched := newWatchedSessions(sessions)
+
+	events := make(chan StorageEvent)
+	go func() {
+		defer close(events)
+		defer watcher.Close()
+
+		for {
+			var name string
+			select {
+			case <-ctx.Done():
+				return
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				// An overflowed event queue loses changes, so resync from a full listing
+				if err == fsnotify.ErrEventOverflow {
+					if sessions, err := r.List(ctx, nil); err == nil && !sendEvents(ctx, events, watched.diff(sessions)...) {
+						return
+					}
+				}
+				continue
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				name = filepath.Base(event.Name)
+			}
+
+			// Skip temporary files, backups and the lock file
+			if strings.HasPrefix(name, ".") || filepath.Ext(name) != ".json" {
+				continue
+			}
+			id := strings.TrimSuffix(name, ".json")
+
+			session, err := r.Get(ctx, id)
+			if err != nil {
+				if _, statErr := os.Stat(r.getFilePath(id)); !os.IsNotExist(statErr) {
+					continue // Unreadable rather than deleted; doctor reports those
+				}
+				session = nil
+			}
+			if event, ok := watched.observe(id, session); ok && !sendEvents(ctx, events, event) {
+				return
+			}
+		}
+	}()
+
+	return events, nil
+}
+
 // Transaction support
 
 func (r *jsonRepository) BeginTx(ctx context.Context) (Transaction, error) {
@@ -583,6 +915,10 @@ func (t *noOpTransaction) GetPaused(ctx context.Context) ([]*types.SessionData,
 	return t.repo.GetPaused(ctx)
 }
 
+func (t *noOpTransaction) Search(ctx context.Context, query string, opts *QueryOptions) ([]*types.SessionData, error) {
+	return t.repo.Search(ctx, query, opts)
+}
+
 func (t *noOpTransaction) UpdateStatus(ctx context.Context, id string, status types.Status) error {
 	return t.repo.UpdateStatus(ctx, id, status)
 }
@@ -599,6 +935,22 @@ func (t *noOpTransaction) GetMetadata(ctx context.Context, id string, key string
 	return t.repo.GetMetadata(ctx, id, key)
 }
 
+func (t *noOpTransaction) GetByRepo(ctx context.Context, repoPath string) ([]*types.SessionData, error) {
+	return t.repo.GetByRepo(ctx, repoPath)
+}
+
+func (t *noOpTransaction) Tag(ctx context.Context, id string, tags ...string) error {
+	return t.repo.Tag(ctx, id, tags...)
+}
+
+func (t *noOpTransaction) Untag(ctx context.Context, id string, tags ...string) error {
+	return t.repo.Untag(ctx, id, tags...)
+}
+
+func (t *noOpTransaction) GetByTag(ctx context.Context, tag string) ([]*types.SessionData, error) {
+	return t.repo.GetByTag(ctx, tag)
+}
+
 func (t *noOpTransaction) DeleteMetadata(ctx context.Context, id string, key string) error {
 	return t.repo.DeleteMetadata(ctx, id, key)
 }
@@ -615,21 +967,22 @@ func (t *noOpTransaction) Vacuum(ctx context.Context) error {
 	return t.repo.Vacuum(ctx)
 }
 
-func (t *noOpTransaction) Backup(ctx context.Context, path string) error {
-	return t.repo.Backup(ctx, path)
+func (t *noOpTransaction) Verify(ctx context.Context) (*VerifyReport, error) {
+	return t.repo.Verify(ctx)
+}
+
+func (t *noOpTransaction) Export(ctx context.Context, w io.Writer) error {
+	return t.repo.Export(ctx, w)
 }
 
-func (t *noOpTransaction) Restore(ctx context.Context, path string) error {
-	return t.repo.Restore(ctx, path)
+func (t *noOpTransaction) Import(ctx context.Context, rd io.Reader) error {
+	return t.repo.Import(ctx, rd)
+}
+
+func (t *noOpTransaction) Watch(ctx context.Context) (<-chan StorageEvent, error) {
+	return t.repo.Watch(ctx)
 }
 
 func (t *noOpTransaction) BeginTx(ctx context.Context) (Transaction, error) {
 	return t, nil // Return self
 }
-
-// Helper function to sort sessions
-func sortSessions(sessions []*types.SessionData, sortBy, sortOrder string) {
-	// Implementation of sorting logic based on sortBy field
-	// This is a simplified version - you may want to use sort.Slice
-	// with appropriate comparison functions based on sortBy
-}
\ No newline at end of file
@@ -0,0 +1,96 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"claude-squad/services/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONRepositoryConcurrentWriters(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	// Two repositories on one directory stand in for two processes: they share no
+	// mutex, so only the file lock keeps their read-modify-writes apart
+	first, err := NewJSONRepository(dir)
+	require.NoError(t, err)
+	second, err := NewJSONRepository(dir)
+	require.NoError(t, err)
+	require.NoError(t, first.Create(ctx, &types.SessionData{ID: "a", Title: "alpha"}))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 40; i++ {
+		repo := first
+		if i%2 == 1 {
+			repo = second
+		}
+		wg.Add(1)
+		go func(repo StorageRepository, i int) {
+			defer wg.Done()
+			assert.NoError(t, repo.SetMetadata(ctx, "a", fmt.Sprintf("key-%d", i), "v"))
+		}(repo, i)
+	}
+	wg.Wait()
+
+	session, err := first.Get(ctx, "a")
+	require.NoError(t, err)
+	assert.Len(t, session.Metadata, 40)
+}
+
+func TestJSONRepositoryImport(t *testing.T) {
+	ctx := context.Background()
+	repo, err := NewJSONRepository(t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, repo.Create(ctx, &types.SessionData{ID: "a", Title: "alpha"}))
+
+	var archive bytes.Buffer
+	require.NoError(t, repo.Export(ctx, &archive))
+	require.NoError(t, repo.Delete(ctx, "a"))
+
+	require.NoError(t, repo.Import(ctx, &archive))
+	exists, err := repo.Exists(ctx, "a")
+	require.NoError(t, err)
+	assert.True(t, exists)
+}
+
+func TestJSONRepositoryRepair(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	repo, err := NewJSONRepository(dir)
+	require.NoError(t, err)
+
+	require.NoError(t, repo.Create(ctx, &types.SessionData{ID: "a", Title: "alpha"}))
+	require.NoError(t, repo.Update(ctx, &types.SessionData{ID: "a", Title: "alpha v2"}))
+	require.NoError(t, repo.Create(ctx, &types.SessionData{ID: "b", Title: "beta"}))
+
+	// Corrupt both: a has a previous version to fall back to, b does not
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.json"), []byte("{trunc"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "b.json"), []byte("{trunc"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".a.json.tmp-123"), []byte("{"), 0644))
+
+	sessions, err := repo.List(ctx, nil)
+	require.NoError(t, err)
+	assert.Empty(t, sessions)
+
+	report, err := repo.(Repairer).Repair(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a"}, report.Restored)
+	assert.Len(t, report.Quarantined, 2)
+	assert.NoFileExists(t, filepath.Join(dir, ".a.json.tmp-123"))
+
+	restored, err := repo.Get(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, "alpha", restored.Title)
+	exists, err := repo.Exists(ctx, "b")
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
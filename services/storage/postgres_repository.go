@@ -0,0 +1,760 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"claude-squad/services/types"
+
+	"github.com/lib/pq"
+)
+
+// postgresMigrations are applied in order, each once, and recorded in schema_migrations.
+// Sessions are stored as JSONB with the columns queries filter and sort on copied out of
+// it, like the SQLite store, so new SessionData fields need no migration.
+var postgresMigrations = []string{
+	`CREATE TABLE sessions (
+		id         TEXT PRIMARY KEY,
+		title      TEXT NOT NULL,
+		path       TEXT NOT NULL,
+		repo_path  TEXT NOT NULL,
+		branch     TEXT NOT NULL,
+		program    TEXT NOT NULL,
+		auto_yes   BOOLEAN NOT NULL,
+		status     INTEGER NOT NULL,
+		created_at BIGINT NOT NULL,
+		updated_at BIGINT NOT NULL,
+		search     TEXT NOT NULL,
+		data       JSONB NOT NULL
+	);
+	CREATE INDEX sessions_status ON sessions(status);
+	CREATE INDEX sessions_branch ON sessions(branch);
+	CREATE INDEX sessions_repo_path ON sessions(repo_path);
+	CREATE INDEX sessions_updated_at ON sessions(updated_at);
+	CREATE INDEX sessions_tags ON sessions USING GIN ((data->'tags'));
+
+	CREATE FUNCTION notify_session_change() RETURNS trigger AS $$
+	BEGIN
+		PERFORM pg_notify('` + postgresChangeChannel + `', CASE TG_OP WHEN 'DELETE' THEN OLD.id ELSE NEW.id END);
+		RETURN NULL;
+	END;
+	$$ LANGUAGE plpgsql;
+	CREATE TRIGGER sessions_changed AFTER INSERT OR UPDATE OR DELETE ON sessions
+		FOR EACH ROW EXECUTE PROCEDURE notify_session_change();`,
+}
+
+// postgresChangeChannel is the channel the schema's trigger notifies of changed sessions
+const postgresChangeChannel = "cs_session_changes"
+
+// postgresMigrationLock is the advisory lock key that keeps processes starting together
+// from migrating the same database at once
+const postgresMigrationLock = 0x63735f6d6967
+
+// postgresSortColumns maps QueryOptions.SortBy values to columns
+var postgresSortColumns = map[string]string{
+	"created_at": "created_at",
+	"updated_at": "updated_at",
+	"title":      "title",
+	"status":     "status",
+}
+
+// ErrConflict is returned by Update when the stored session changed after the caller
+// read it. Only stores shared between machines, like Postgres, check for it.
+var ErrConflict = errors.New("session was changed by someone else since it was read")
+
+// postgresRepository is a Postgres-backed implementation of StorageRepository, for a
+// team sharing one session store
+type postgresRepository struct {
+	// db is nil when the repository runs inside a transaction
+	db  *sql.DB
+	q   sqlQuerier
+	dsn string
+}
+
+// NewPostgresRepository connects to the Postgres database at dsn and brings its schema
+// up to date
+func NewPostgresRepository(dsn string) (StorageRepository, error) {
+	if dsn == "" {
+		return nil, fmt.Errorf("a connection string is required for the postgres backend")
+	}
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	r := &postgresRepository{db: db, q: db, dsn: dsn}
+	if err := r.migrate(context.Background()); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return r, nil
+}
+
+// migrate applies the migrations the database hasn't had yet
+func (r *postgresRepository) migrate(ctx context.Context) error {
+	return r.withTx(ctx, func(tx *postgresRepository) error {
+		if _, err := tx.q.ExecContext(ctx, "SELECT pg_advisory_xact_lock($1)", postgresMigrationLock); err != nil {
+			return fmt.Errorf("failed to lock schema: %w", err)
+		}
+		if _, err := tx.q.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+			version    INTEGER PRIMARY KEY,
+			applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+		)`); err != nil {
+			return fmt.Errorf("failed to create schema: %w", err)
+		}
+
+		var version int
+		if err := tx.q.QueryRowContext(ctx, "SELECT COALESCE(MAX(version), 0) FROM schema_migrations").Scan(&version); err != nil {
+			return fmt.Errorf("failed to read schema version: %w", err)
+		}
+		if version > len(postgresMigrations) {
+			return fmt.Errorf("database schema version %d is newer than this version of claude-squad supports", version)
+		}
+		for i := version; i < len(postgresMigrations); i++ {
+			if _, err := tx.q.ExecContext(ctx, postgresMigrations[i]); err != nil {
+				return fmt.Errorf("failed to apply migration %d: %w", i+1, err)
+			}
+			if _, err := tx.q.ExecContext(ctx, "INSERT INTO schema_migrations (version) VALUES ($1)", i+1); err != nil {
+				return fmt.Errorf("failed to record migration %d: %w", i+1, err)
+			}
+		}
+		return nil
+	})
+}
+
+// withTx runs fn in a transaction, or directly when the repository already is one
+func (r *postgresRepository) withTx(ctx context.Context, fn func(tx *postgresRepository) error) error {
+	if r.db == nil {
+		return fn(r)
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	if err := fn(&postgresRepository{q: tx, dsn: r.dsn}); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// pgArgs collects query arguments, handing out their $n placeholders
+type pgArgs []interface{}
+
+func (a *pgArgs) add(arg interface{}) string {
+	*a = append(*a, arg)
+	return fmt.Sprintf("$%d", len(*a))
+}
+
+// postgresColumns are the columns written for a session, in the order of postgresValues
+const postgresColumns = "id, title, path, repo_path, branch, program, auto_yes, status, created_at, updated_at, search, data"
+
+// postgresValues returns the column values stored for a session
+func postgresValues(session *types.SessionData) ([]interface{}, error) {
+	data, err := sealRecord(session, false)
+	if err != nil {
+		return nil, err
+	}
+	return []interface{}{
+		session.ID, session.Title, session.Path, repoPathOf(session), session.Branch, session.Program,
+		session.AutoYes, int(session.Status), session.CreatedAt.UnixNano(), session.UpdatedAt.UnixNano(),
+		searchText(session), string(data),
+	}, nil
+}
+
+// insert adds a session as is, without touching its timestamps, reporting false if a
+// session with its ID already exists
+func (r *postgresRepository) insert(ctx context.Context, session *types.SessionData) (bool, error) {
+	values, err := postgresValues(session)
+	if err != nil {
+		return false, err
+	}
+	res, err := r.q.ExecContext(ctx, `INSERT INTO sessions (`+postgresColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		ON CONFLICT (id) DO NOTHING`, values...)
+	if err != nil {
+		return false, fmt.Errorf("failed to write session: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to write session: %w", err)
+	}
+	return n > 0, nil
+}
+
+// get reads a session, locking its row until the transaction ends when forUpdate is set
+func (r *postgresRepository) get(ctx context.Context, id string, forUpdate bool) (*types.SessionData, error) {
+	query := "SELECT data FROM sessions WHERE id = $1"
+	if forUpdate {
+		query += " FOR UPDATE"
+	}
+
+	var data string
+	err := r.q.QueryRowContext(ctx, query, id).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("session not found: %s", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read session: %w", err)
+	}
+
+	var session types.SessionData
+	if err := json.Unmarshal([]byte(data), &session); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal session: %w", err)
+	}
+	return &session, nil
+}
+
+// modify applies fn to a stored session and saves it if fn reports a change, with the
+// row locked so concurrent changes queue up rather than conflict
+func (r *postgresRepository) modify(ctx context.Context, id string, fn func(session *types.SessionData) (bool, error)) error {
+	return r.withTx(ctx, func(tx *postgresRepository) error {
+		session, err := tx.get(ctx, id, true)
+		if err != nil {
+			return err
+		}
+		changed, err := fn(session)
+		if err != nil || !changed {
+			return err
+		}
+		return tx.Update(ctx, session)
+	})
+}
+
+func (r *postgresRepository) queryRows(ctx context.Context, query string, args ...interface{}) ([]*types.SessionData, error) {
+	rows, err := r.q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query sessions: %w", err)
+	}
+	defer rows.Close()
+
+	var sessions []*types.SessionData
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("failed to read session: %w", err)
+		}
+		var session types.SessionData
+		if err := json.Unmarshal([]byte(data), &session); err != nil {
+			continue // Skip rows that can't be decoded, like the JSON store skips bad files
+		}
+		sessions = append(sessions, &session)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query sessions: %w", err)
+	}
+	return sessions, nil
+}
+
+// Basic CRUD operations
+
+func (r *postgresRepository) Create(ctx context.Context, session *types.SessionData) error {
+	if session.ID == "" {
+		return fmt.Errorf("session ID is required")
+	}
+
+	created := *session
+	created.CreatedAt = time.Now()
+	created.UpdatedAt = created.CreatedAt
+	inserted, err := r.insert(ctx, &created)
+	if err != nil {
+		return err
+	}
+	if !inserted {
+		return fmt.Errorf("session already exists: %s", session.ID)
+	}
+	session.CreatedAt, session.UpdatedAt = created.CreatedAt, created.UpdatedAt
+	return nil
+}
+
+func (r *postgresRepository) Get(ctx context.Context, id string) (*types.SessionData, error) {
+	return r.get(ctx, id, false)
+}
+
+// Update saves session only if the stored copy still has the UpdatedAt the caller read,
+// returning ErrConflict otherwise, so one teammate's change isn't silently overwritten
+// by another's stale copy. A session with no UpdatedAt is saved unconditionally.
+func (r *postgresRepository) Update(ctx context.Context, session *types.SessionData) error {
+	if session.ID == "" {
+		return fmt.Errorf("session ID is required")
+	}
+
+	updated := *session
+	updated.UpdatedAt = time.Now()
+	values, err := postgresValues(&updated)
+	if err != nil {
+		return err
+	}
+
+	query := `UPDATE sessions SET (` + postgresColumns + `) =
+		($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) WHERE id = $1`
+	if !session.UpdatedAt.IsZero() {
+		query += " AND updated_at = $13"
+		values = append(values, session.UpdatedAt.UnixNano())
+	}
+	res, err := r.q.ExecContext(ctx, query, values...)
+	if err != nil {
+		return fmt.Errorf("failed to write session: %w", err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		if exists, err := r.Exists(ctx, session.ID); err == nil && !exists {
+			return fmt.Errorf("session not found: %s", session.ID)
+		}
+		return fmt.Errorf("failed to update session %s: %w", session.ID, ErrConflict)
+	}
+
+	session.UpdatedAt = updated.UpdatedAt
+	return nil
+}
+
+func (r *postgresRepository) Delete(ctx context.Context, id string) error {
+	res, err := r.q.ExecContext(ctx, "DELETE FROM sessions WHERE id = $1", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete session: %w", err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("session not found: %s", id)
+	}
+	return nil
+}
+
+// Batch operations
+
+func (r *postgresRepository) CreateBatch(ctx context.Context, sessions []*types.SessionData) error {
+	return r.withTx(ctx, func(tx *postgresRepository) error {
+		for _, session := range sessions {
+			if err := tx.Create(ctx, session); err != nil {
+				return fmt.Errorf("failed to create session %s: %w", session.ID, err)
+			}
+		}
+		return nil
+	})
+}
+
+func (r *postgresRepository) UpdateBatch(ctx context.Context, sessions []*types.SessionData) error {
+	return r.withTx(ctx, func(tx *postgresRepository) error {
+		for _, session := range sessions {
+			if err := tx.Update(ctx, session); err != nil {
+				return fmt.Errorf("failed to update session %s: %w", session.ID, err)
+			}
+		}
+		return nil
+	})
+}
+
+func (r *postgresRepository) DeleteBatch(ctx context.Context, ids []string) error {
+	return r.withTx(ctx, func(tx *postgresRepository) error {
+		for _, id := range ids {
+			if err := tx.Delete(ctx, id); err != nil {
+				return fmt.Errorf("failed to delete session %s: %w", id, err)
+			}
+		}
+		return nil
+	})
+}
+
+// Query operations
+
+// postgresWhere builds the WHERE clause for the filters in opts and the search terms,
+// adding their arguments to args
+func postgresWhere(opts *QueryOptions, terms []string, args *pgArgs) string {
+	var conds []string
+	for _, term := range terms {
+		// Escape LIKE wildcards so terms match literally, like the other stores
+		pattern := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(term)
+		conds = append(conds, "search LIKE "+args.add("%"+pattern+"%"))
+	}
+	if opts == nil {
+		return strings.Join(conds, " AND ")
+	}
+
+	if opts.Status != nil {
+		conds = append(conds, "status = "+args.add(int(*opts.Status)))
+	}
+	if opts.Branch != nil {
+		conds = append(conds, "branch = "+args.add(*opts.Branch))
+	}
+	if opts.Path != nil {
+		conds = append(conds, "path = "+args.add(*opts.Path))
+	}
+	if opts.Program != nil {
+		conds = append(conds, "program = "+args.add(*opts.Program))
+	}
+	if opts.AutoYes != nil {
+		conds = append(conds, "auto_yes = "+args.add(*opts.AutoYes))
+	}
+	if opts.RepoPath != nil {
+		conds = append(conds, "repo_path = "+args.add(*opts.RepoPath))
+	}
+	for _, tag := range opts.Tags {
+		conds = append(conds, "data->'tags' ? "+args.add(tag))
+	}
+	if opts.CreatedAfter != nil {
+		conds = append(conds, "created_at >= "+args.add(opts.CreatedAfter.UnixNano()))
+	}
+	if opts.CreatedBefore != nil {
+		conds = append(conds, "created_at <= "+args.add(opts.CreatedBefore.UnixNano()))
+	}
+	if opts.UpdatedAfter != nil {
+		conds = append(conds, "updated_at >= "+args.add(opts.UpdatedAfter.UnixNano()))
+	}
+	if opts.UpdatedBefore != nil {
+		conds = append(conds, "updated_at <= "+args.add(opts.UpdatedBefore.UnixNano()))
+	}
+	return strings.Join(conds, " AND ")
+}
+
+func (r *postgresRepository) List(ctx context.Context, opts *QueryOptions) ([]*types.SessionData, error) {
+	return r.list(ctx, opts, nil)
+}
+
+// list returns the sessions matching opts whose text contains every search term
+func (r *postgresRepository) list(ctx context.Context, opts *QueryOptions, terms []string) ([]*types.SessionData, error) {
+	var args pgArgs
+	query := "SELECT data FROM sessions"
+	if where := postgresWhere(opts, terms, &args); where != "" {
+		query += " WHERE " + where
+	}
+
+	order := "created_at ASC"
+	if opts != nil && opts.SortBy != "" {
+		column, ok := postgresSortColumns[opts.SortBy]
+		if !ok {
+			return nil, fmt.Errorf("unsupported sort field: %s", opts.SortBy)
+		}
+		direction := "ASC"
+		if strings.EqualFold(opts.SortOrder, "desc") {
+			direction = "DESC"
+		}
+		order = column + " " + direction
+	}
+	query += " ORDER BY " + order + ", id ASC"
+
+	if opts != nil && opts.Limit > 0 {
+		query += " LIMIT " + args.add(opts.Limit)
+	}
+	if opts != nil && opts.Offset > 0 {
+		query += " OFFSET " + args.add(opts.Offset)
+	}
+
+	return r.queryRows(ctx, query, args...)
+}
+
+func (r *postgresRepository) Count(ctx context.Context, opts *QueryOptions) (int, error) {
+	var args pgArgs
+	query := "SELECT COUNT(*) FROM sessions"
+	if where := postgresWhere(opts, nil, &args); where != "" {
+		query += " WHERE " + where
+	}
+
+	var count int
+	if err := r.q.QueryRowContext(ctx, query, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count sessions: %w", err)
+	}
+
+	// Pagination applies to counts too, matching List
+	if opts != nil {
+		count = max(count-opts.Offset, 0)
+		if opts.Limit > 0 {
+			count = min(count, opts.Limit)
+		}
+	}
+	return count, nil
+}
+
+func (r *postgresRepository) Exists(ctx context.Context, id string) (bool, error) {
+	var exists bool
+	if err := r.q.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM sessions WHERE id = $1)", id).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to check session: %w", err)
+	}
+	return exists, nil
+}
+
+// Specialized queries
+
+func (r *postgresRepository) GetByTitle(ctx context.Context, title string) (*types.SessionData, error) {
+	sessions, err := r.queryRows(ctx, "SELECT data FROM sessions WHERE title = $1 ORDER BY created_at LIMIT 1", title)
+	if err != nil {
+		return nil, err
+	}
+	if len(sessions) == 0 {
+		return nil, fmt.Errorf("session not found with title: %s", title)
+	}
+	return sessions[0], nil
+}
+
+func (r *postgresRepository) GetByBranch(ctx context.Context, branch string) ([]*types.SessionData, error) {
+	return r.List(ctx, &QueryOptions{Branch: &branch})
+}
+
+func (r *postgresRepository) GetActive(ctx context.Context) ([]*types.SessionData, error) {
+	return r.queryRows(ctx, "SELECT data FROM sessions WHERE status IN ($1, $2) ORDER BY created_at",
+		int(types.StatusRunning), int(types.StatusReady))
+}
+
+func (r *postgresRepository) GetPaused(ctx context.Context) ([]*types.SessionData, error) {
+	paused := types.StatusPaused
+	return r.List(ctx, &QueryOptions{Status: &paused})
+}
+
+func (r *postgresRepository) GetByRepo(ctx context.Context, repoPath string) ([]*types.SessionData, error) {
+	return r.List(ctx, &QueryOptions{RepoPath: &repoPath})
+}
+
+// Search matches substrings of the search text stored with each session, like the JSON
+// store does
+func (r *postgresRepository) Search(ctx context.Context, query string, opts *QueryOptions) ([]*types.SessionData, error) {
+	return r.list(ctx, opts, searchTerms(query))
+}
+
+// Tag operations
+
+func (r *postgresRepository) Tag(ctx context.Context, id string, tags ...string) error {
+	return r.modify(ctx, id, func(session *types.SessionData) (bool, error) {
+		return addTags(session, tags)
+	})
+}
+
+func (r *postgresRepository) Untag(ctx context.Context, id string, tags ...string) error {
+	return r.modify(ctx, id, func(session *types.SessionData) (bool, error) {
+		return removeTags(session, tags), nil
+	})
+}
+
+func (r *postgresRepository) GetByTag(ctx context.Context, tag string) ([]*types.SessionData, error) {
+	return r.List(ctx, &QueryOptions{Tags: []string{tag}})
+}
+
+// Status operations
+
+func (r *postgresRepository) UpdateStatus(ctx context.Context, id string, status types.Status) error {
+	return r.modify(ctx, id, func(session *types.SessionData) (bool, error) {
+		session.Status = status
+		return true, nil
+	})
+}
+
+func (r *postgresRepository) UpdateStatusBatch(ctx context.Context, updates map[string]types.Status) error {
+	return r.withTx(ctx, func(tx *postgresRepository) error {
+		for id, status := range updates {
+			if err := tx.UpdateStatus(ctx, id, status); err != nil {
+				return fmt.Errorf("failed to update status for %s: %w", id, err)
+			}
+		}
+		return nil
+	})
+}
+
+// Metadata operations
+
+func (r *postgresRepository) SetMetadata(ctx context.Context, id string, key, value string) error {
+	return r.modify(ctx, id, func(session *types.SessionData) (bool, error) {
+		if session.Metadata == nil {
+			session.Metadata = make(map[string]string)
+		}
+		session.Metadata[key] = value
+		return true, nil
+	})
+}
+
+func (r *postgresRepository) GetMetadata(ctx context.Context, id string, key string) (string, error) {
+	session, err := r.Get(ctx, id)
+	if err != nil {
+		return "", err
+	}
+
+	value, exists := session.Metadata[key]
+	if !exists {
+		return "", fmt.Errorf("metadata key not found: %s", key)
+	}
+	return value, nil
+}
+
+func (r *postgresRepository) DeleteMetadata(ctx context.Context, id string, key string) error {
+	return r.modify(ctx, id, func(session *types.SessionData) (bool, error) {
+		if _, exists := session.Metadata[key]; !exists {
+			return false, nil
+		}
+		delete(session.Metadata, key)
+		return true, nil
+	})
+}
+
+// Maintenance operations
+
+func (r *postgresRepository) DeleteAll(ctx context.Context) error {
+	if _, err := r.q.ExecContext(ctx, "DELETE FROM sessions"); err != nil {
+		return fmt.Errorf("failed to delete sessions: %w", err)
+	}
+	return nil
+}
+
+func (r *postgresRepository) DeleteOlderThan(ctx context.Context, duration time.Duration) error {
+	cutoff := time.Now().Add(-duration)
+	if _, err := r.q.ExecContext(ctx, "DELETE FROM sessions WHERE updated_at <= $1", cutoff.UnixNano()); err != nil {
+		return fmt.Errorf("failed to delete old sessions: %w", err)
+	}
+	return nil
+}
+
+// Verify checks each session record. Postgres checks its own pages, so only damage done
+// to the records themselves, by hand edits or faulty clients, is found.
+func (r *postgresRepository) Verify(ctx context.Context) (*VerifyReport, error) {
+	rows, err := r.q.QueryContext(ctx, "SELECT id, data FROM sessions ORDER BY id")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query sessions: %w", err)
+	}
+	defer rows.Close()
+
+	report := &VerifyReport{}
+	for rows.Next() {
+		var id, data string
+		if err := rows.Scan(&id, &data); err != nil {
+			return nil, fmt.Errorf("failed to read session: %w", err)
+		}
+		report.add(id, []byte(data))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query sessions: %w", err)
+	}
+	return report, nil
+}
+
+func (r *postgresRepository) Vacuum(ctx context.Context) error {
+	if r.db == nil {
+		return fmt.Errorf("vacuum cannot run inside a transaction")
+	}
+	if _, err := r.db.ExecContext(ctx, "VACUUM ANALYZE sessions"); err != nil {
+		return fmt.Errorf("failed to vacuum database: %w", err)
+	}
+	return nil
+}
+
+func (r *postgresRepository) Export(ctx context.Context, w io.Writer) error {
+	sessions, err := r.queryRows(ctx, "SELECT data FROM sessions")
+	if err != nil {
+		return err
+	}
+	return writeArchive(w, sessions)
+}
+
+// Import replaces every session with the ones in an archive in a single transaction
+func (r *postgresRepository) Import(ctx context.Context, rd io.Reader) error {
+	sessions, err := readArchive(rd)
+	if err != nil {
+		return err
+	}
+
+	return r.withTx(ctx, func(tx *postgresRepository) error {
+		if err := tx.DeleteAll(ctx); err != nil {
+			return fmt.Errorf("failed to clear existing data: %w", err)
+		}
+		for _, session := range sessions {
+			if _, err := tx.insert(ctx, session); err != nil {
+				return fmt.Errorf("failed to import session %s: %w", session.ID, err)
+			}
+		}
+		return nil
+	})
+}
+
+// Change notification
+
+// Watch reports changes announced by the schema's trigger with NOTIFY, which catches
+// writes from every client of the database. After a dropped connection the whole store
+// is compared against the last snapshot, since notifications sent meanwhile are lost.
+func (r *postgresRepository) Watch(ctx context.Context) (<-chan StorageEvent, error) {
+	if r.db == nil {
+		return nil, fmt.Errorf("watch cannot run inside a transaction")
+	}
+
+	listener := pq.NewListener(r.dsn, time.Second, time.Minute, nil)
+	if err := listener.Listen(postgresChangeChannel); err != nil {
+		listener.Close()
+		return nil, fmt.Errorf("failed to listen for changes: %w", err)
+	}
+
+	// Listen before taking the snapshot so no change falls between the two
+	sessions, err := r.List(ctx, nil)
+	if err != nil {
+		listener.Close()
+		return nil, err
+	}
+	watched := newWatchedSessions(sessions)
+
+	events := make(chan StorageEvent)
+	go func() {
+		defer close(events)
+		defer listener.Close()
+
+		for {
+			var notification *pq.Notification
+			select {
+			case <-ctx.Done():
+				return
+			case notification = <-listener.Notify:
+			}
+
+			// A nil notification means the connection was re-established
+			if notification == nil {
+				sessions, err := r.List(ctx, nil)
+				if err != nil {
+					continue
+				}
+				if !sendEvents(ctx, events, watched.diff(sessions)...) {
+					return
+				}
+				continue
+			}
+
+			id := notification.Extra
+			session, err := r.Get(ctx, id)
+			if err != nil {
+				session = nil
+			}
+			if event, ok := watched.observe(id, session); ok && !sendEvents(ctx, events, event) {
+				return
+			}
+		}
+	}()
+
+	return events, nil
+}
+
+// Transaction support
+
+func (r *postgresRepository) BeginTx(ctx context.Context) (Transaction, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	return &postgresTransaction{postgresRepository: &postgresRepository{q: tx, dsn: r.dsn}, tx: tx}, nil
+}
+
+// postgresTransaction is a repository whose operations all run in one database transaction
+type postgresTransaction struct {
+	*postgresRepository
+	tx *sql.Tx
+}
+
+func (t *postgresTransaction) Commit() error {
+	return t.tx.Commit()
+}
+
+func (t *postgresTransaction) Rollback() error {
+	return t.tx.Rollback()
+}
+
+func (t *postgresTransaction) BeginTx(ctx context.Context) (Transaction, error) {
+	return t, nil // Nested transactions join the outer one
+}
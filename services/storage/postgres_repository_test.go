@@ -0,0 +1,97 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"claude-squad/services/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// openTestPostgres connects to the database in $CS_TEST_POSTGRES_DSN and empties it,
+// skipping the test when none is set
+func openTestPostgres(t *testing.T) StorageRepository {
+	dsn := os.Getenv("CS_TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("CS_TEST_POSTGRES_DSN not set")
+	}
+	repo, err := NewPostgresRepository(dsn)
+	require.NoError(t, err)
+	require.NoError(t, repo.DeleteAll(context.Background()))
+	return repo
+}
+
+func TestPostgresRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := openTestPostgres(t)
+
+	require.NoError(t, repo.Create(ctx, &types.SessionData{ID: "a", Title: "fix-auth", Branch: "fix", Prompt: "Fix the 100% login_bug", Status: types.StatusRunning}))
+	require.NoError(t, repo.Create(ctx, &types.SessionData{ID: "b", Title: "docs", Branch: "docs", Status: types.StatusPaused}))
+	assert.Error(t, repo.Create(ctx, &types.SessionData{ID: "a"}))
+
+	session, err := repo.Get(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, "fix-auth", session.Title)
+
+	require.NoError(t, repo.Tag(ctx, "a", "urgent"))
+	require.NoError(t, repo.UpdateStatus(ctx, "b", types.StatusRunning))
+
+	tagged, err := repo.GetByTag(ctx, "urgent")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a"}, sessionIDs(tagged))
+
+	active, err := repo.GetActive(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, sessionIDs(active))
+
+	// Wildcards in search terms match literally
+	found, err := repo.Search(ctx, "100% login_", nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a"}, sessionIDs(found))
+	found, err = repo.Search(ctx, "%", nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a"}, sessionIDs(found))
+
+	require.NoError(t, repo.Delete(ctx, "a"))
+	assert.Error(t, repo.Delete(ctx, "a"))
+	count, err := repo.Count(ctx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
+
+func TestPostgresOptimisticConcurrency(t *testing.T) {
+	ctx := context.Background()
+	repo := openTestPostgres(t)
+	require.NoError(t, repo.Create(ctx, &types.SessionData{ID: "a", Title: "fix-auth"}))
+
+	// Two clients read the same session, and the second to save loses
+	mine, err := repo.Get(ctx, "a")
+	require.NoError(t, err)
+	theirs, err := repo.Get(ctx, "a")
+	require.NoError(t, err)
+
+	theirs.Prompt = "theirs"
+	require.NoError(t, repo.Update(ctx, theirs))
+	mine.Prompt = "mine"
+	err = repo.Update(ctx, mine)
+	assert.True(t, errors.Is(err, ErrConflict), "got %v", err)
+
+	stored, err := repo.Get(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, "theirs", stored.Prompt)
+
+	// Re-reading picks up the change and saves cleanly, and read-modify-write operations
+	// never conflict
+	mine, err = repo.Get(ctx, "a")
+	require.NoError(t, err)
+	mine.Prompt = "mine"
+	require.NoError(t, repo.Update(ctx, mine))
+	require.NoError(t, repo.SetMetadata(ctx, "a", "key", "value"))
+
+	assert.Error(t, repo.Update(ctx, &types.SessionData{ID: "missing", Title: "missing"}))
+	assert.False(t, errors.Is(repo.Update(ctx, &types.SessionData{ID: "missing"}), ErrConflict))
+}
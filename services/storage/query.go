@@ -0,0 +1,185 @@
+package storage
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"claude-squad/services/types"
+)
+
+// matchesQuery reports whether a session passes the filters in opts. Backends that
+// can't filter natively, like the JSON and bolt stores, share it.
+func matchesQuery(session *types.SessionData, opts *QueryOptions) bool {
+	if opts == nil {
+		return true
+	}
+	if opts.Status != nil && session.Status != *opts.Status {
+		return false
+	}
+	if opts.Branch != nil && session.Branch != *opts.Branch {
+		return false
+	}
+	if opts.Path != nil && session.Path != *opts.Path {
+		return false
+	}
+	if opts.Program != nil && session.Program != *opts.Program {
+		return false
+	}
+	if opts.AutoYes != nil && session.AutoYes != *opts.AutoYes {
+		return false
+	}
+	if opts.RepoPath != nil && repoPathOf(session) != *opts.RepoPath {
+		return false
+	}
+	for _, tag := range opts.Tags {
+		if !slices.Contains(session.Tags, tag) {
+			return false
+		}
+	}
+	if opts.CreatedAfter != nil && session.CreatedAt.Before(*opts.CreatedAfter) {
+		return false
+	}
+	if opts.CreatedBefore != nil && session.CreatedAt.After(*opts.CreatedBefore) {
+		return false
+	}
+	if opts.UpdatedAfter != nil && session.UpdatedAt.Before(*opts.UpdatedAfter) {
+		return false
+	}
+	if opts.UpdatedBefore != nil && session.UpdatedAt.After(*opts.UpdatedBefore) {
+		return false
+	}
+	return true
+}
+
+// repoPathOf returns the repository a session was created from. Sessions stored before
+// RepoPath was recorded fall back to the "<repo>-worktree-<session id>" naming.
+func repoPathOf(session *types.SessionData) string {
+	if session.RepoPath != "" {
+		return session.RepoPath
+	}
+	return strings.TrimSuffix(session.Path, "-worktree-"+session.ID)
+}
+
+// addTags adds tags a session doesn't have yet to its tags, reporting whether any were
+// added. Surrounding whitespace is trimmed and empty tags are rejected.
+func addTags(session *types.SessionData, tags []string) (bool, error) {
+	added := false
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			return false, fmt.Errorf("tag must not be empty")
+		}
+		if !slices.Contains(session.Tags, tag) {
+			session.Tags = append(session.Tags, tag)
+			added = true
+		}
+	}
+	return added, nil
+}
+
+// removeTags removes tags from a session, reporting whether it had any of them
+func removeTags(session *types.SessionData, tags []string) bool {
+	before := len(session.Tags)
+	session.Tags = slices.DeleteFunc(session.Tags, func(tag string) bool {
+		return slices.Contains(tags, tag)
+	})
+	if len(session.Tags) == 0 {
+		session.Tags = nil
+	}
+	return len(session.Tags) != before
+}
+
+// searchTerms splits a search query into the lowercase words every match must contain
+func searchTerms(query string) []string {
+	return strings.Fields(strings.ToLower(query))
+}
+
+// searchText is the lowercase text of a session searches look in: its title, prompts
+// and metadata
+func searchText(session *types.SessionData) string {
+	fields := []string{session.Title, session.Prompt}
+	for _, input := range session.Inputs {
+		if input.Kind == types.InputPrompt {
+			fields = append(fields, input.Text)
+		}
+	}
+	for key, value := range session.Metadata {
+		fields = append(fields, key, value)
+	}
+	return strings.ToLower(strings.Join(fields, "\n"))
+}
+
+// matchesSearch reports whether each term appears somewhere in the session's title,
+// prompts or metadata, ignoring case
+func matchesSearch(session *types.SessionData, terms []string) bool {
+	if len(terms) == 0 {
+		return true
+	}
+
+	text := searchText(session)
+	for _, term := range terms {
+		if !strings.Contains(text, term) {
+			return false
+		}
+	}
+	return true
+}
+
+// sortAndPaginate applies the sorting and pagination in opts to filtered sessions.
+// Sessions are ordered oldest first when opts doesn't say otherwise, with ties broken
+// by ID so pages are stable, matching the SQLite store.
+func sortAndPaginate(sessions []*types.SessionData, opts *QueryOptions) ([]*types.SessionData, error) {
+	sortBy, sortOrder := "created_at", "asc"
+	if opts != nil && opts.SortBy != "" {
+		sortBy, sortOrder = opts.SortBy, opts.SortOrder
+	}
+	if err := sortSessions(sessions, sortBy, sortOrder); err != nil {
+		return nil, err
+	}
+
+	if opts == nil {
+		return sessions, nil
+	}
+	if opts.Offset > 0 {
+		if opts.Offset >= len(sessions) {
+			return []*types.SessionData{}, nil
+		}
+		sessions = sessions[opts.Offset:]
+	}
+	if opts.Limit > 0 && opts.Limit < len(sessions) {
+		sessions = sessions[:opts.Limit]
+	}
+	return sessions, nil
+}
+
+// sessionComparers compare two sessions by each supported QueryOptions.SortBy field
+var sessionComparers = map[string]func(a, b *types.SessionData) int{
+	"created_at": func(a, b *types.SessionData) int { return a.CreatedAt.Compare(b.CreatedAt) },
+	"updated_at": func(a, b *types.SessionData) int { return a.UpdatedAt.Compare(b.UpdatedAt) },
+	"title":      func(a, b *types.SessionData) int { return strings.Compare(a.Title, b.Title) },
+	"status":     func(a, b *types.SessionData) int { return cmp.Compare(a.Status, b.Status) },
+}
+
+// sortSessions sorts sessions in place by a SortBy field, descending if sortOrder is
+// "desc" and ascending otherwise. Ties stay in ID order whatever the direction.
+func sortSessions(sessions []*types.SessionData, sortBy, sortOrder string) error {
+	compare, ok := sessionComparers[sortBy]
+	if !ok {
+		return fmt.Errorf("unsupported sort field: %s", sortBy)
+	}
+	desc := strings.EqualFold(sortOrder, "desc")
+
+	slices.SortStableFunc(sessions, func(a, b *types.SessionData) int {
+		c := compare(a, b)
+		if desc {
+			c = -c
+		}
+		if c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+	return nil
+}
@@ -0,0 +1,189 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"claude-squad/services/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testBackends opens each storage backend, for tests that every backend should pass
+var testBackends = map[string]func(dir string) (StorageRepository, error){
+	"json":   NewJSONRepository,
+	"sqlite": NewSQLiteRepository,
+	"bolt":   NewBoltRepository,
+}
+
+func TestListSorting(t *testing.T) {
+	// Created in this order, since Create stamps CreatedAt
+	sessions := []*types.SessionData{
+		{ID: "b", Title: "alpha", Status: types.StatusRunning},
+		{ID: "c", Title: "bravo", Status: types.StatusReady},
+		{ID: "a", Title: "charlie", Status: types.StatusPaused},
+		{ID: "d", Title: "delta", Status: types.StatusRunning},
+	}
+
+	tests := []struct {
+		name string
+		opts *QueryOptions
+		want []string
+	}{
+		{"default is oldest first", nil, []string{"b", "c", "a", "d"}},
+		{"created_at desc", &QueryOptions{SortBy: "created_at", SortOrder: "desc"}, []string{"d", "a", "c", "b"}},
+		{"title", &QueryOptions{SortBy: "title"}, []string{"b", "c", "a", "d"}},
+		{"status breaks ties by id", &QueryOptions{SortBy: "status"}, []string{"b", "d", "c", "a"}},
+		{"status desc keeps ties in id order", &QueryOptions{SortBy: "status", SortOrder: "desc"}, []string{"a", "c", "b", "d"}},
+		{"page", &QueryOptions{SortBy: "title", Limit: 2, Offset: 1}, []string{"c", "a"}},
+		{"offset without limit", &QueryOptions{SortBy: "title", Offset: 3}, []string{"d"}},
+		{"offset past the end", &QueryOptions{Offset: 10}, []string{}},
+	}
+
+	for name, open := range testBackends {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			repo, err := open(t.TempDir())
+			require.NoError(t, err)
+			for _, session := range sessions {
+				require.NoError(t, repo.Create(ctx, session))
+				time.Sleep(time.Millisecond)
+			}
+
+			for _, tt := range tests {
+				t.Run(tt.name, func(t *testing.T) {
+					got, err := repo.List(ctx, tt.opts)
+					require.NoError(t, err)
+					ids := []string{}
+					for _, session := range got {
+						ids = append(ids, session.ID)
+					}
+					assert.Equal(t, tt.want, ids)
+
+					count, err := repo.Count(ctx, tt.opts)
+					require.NoError(t, err)
+					assert.Equal(t, len(tt.want), count)
+				})
+			}
+
+			_, err = repo.List(ctx, &QueryOptions{SortBy: "branch"})
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestSearch(t *testing.T) {
+	for name, open := range testBackends {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			repo, err := open(t.TempDir())
+			require.NoError(t, err)
+
+			for _, session := range []*types.SessionData{
+				{ID: "a", Title: "fix-auth", Prompt: "Fix the login redirect", Status: types.StatusRunning},
+				{ID: "b", Title: "docs", Prompt: "Update the README", Status: types.StatusPaused,
+					Inputs: []types.InputRecord{{Kind: types.InputPrompt, Text: "mention the setup guide"}}},
+				{ID: "c", Title: "refactor", Status: types.StatusRunning, Metadata: map[string]string{"ticket": "AUTH-12"}},
+			} {
+				require.NoError(t, repo.Create(ctx, session))
+				time.Sleep(time.Millisecond)
+			}
+
+			search := func(query string, opts *QueryOptions) []string {
+				sessions, err := repo.Search(ctx, query, opts)
+				require.NoError(t, err)
+				ids := []string{}
+				for _, session := range sessions {
+					ids = append(ids, session.ID)
+				}
+				return ids
+			}
+
+			assert.Equal(t, []string{"a", "c"}, search("auth", nil))
+			assert.Equal(t, []string{"a"}, search("LOGIN fix", nil))
+			assert.Equal(t, []string{"b"}, search("guide", nil))
+			assert.Equal(t, []string{"c"}, search("ticket", nil))
+			assert.Equal(t, []string{}, search("login readme", nil))
+			assert.Equal(t, []string{"a", "b", "c"}, search("", nil))
+			assert.Equal(t, []string{"a"}, search("the", &QueryOptions{Status: &[]types.Status{types.StatusRunning}[0]}))
+
+			// The index follows updates and deletes
+			require.NoError(t, repo.SetMetadata(ctx, "b", "ticket", "AUTH-13"))
+			require.NoError(t, repo.Delete(ctx, "a"))
+			assert.Equal(t, []string{"b", "c"}, search("auth", nil))
+		})
+	}
+}
+
+func TestTags(t *testing.T) {
+	for name, open := range testBackends {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			repo, err := open(t.TempDir())
+			require.NoError(t, err)
+			for _, id := range []string{"a", "b", "c"} {
+				require.NoError(t, repo.Create(ctx, &types.SessionData{ID: id, Title: id}))
+			}
+
+			require.NoError(t, repo.Tag(ctx, "a", "auth", "p1"))
+			require.NoError(t, repo.Tag(ctx, "b", "auth", " auth "))
+			require.NoError(t, repo.Tag(ctx, "c", "billing"))
+			assert.Error(t, repo.Tag(ctx, "c", ""))
+			assert.Error(t, repo.Tag(ctx, "missing", "auth"))
+
+			b, err := repo.Get(ctx, "b")
+			require.NoError(t, err)
+			assert.Equal(t, []string{"auth"}, b.Tags)
+
+			tagged, err := repo.GetByTag(ctx, "auth")
+			require.NoError(t, err)
+			assert.Equal(t, []string{"a", "b"}, sessionIDs(tagged))
+
+			both, err := repo.List(ctx, &QueryOptions{Tags: []string{"auth", "p1"}})
+			require.NoError(t, err)
+			assert.Equal(t, []string{"a"}, sessionIDs(both))
+
+			require.NoError(t, repo.Untag(ctx, "a", "auth", "unknown"))
+			tagged, err = repo.GetByTag(ctx, "auth")
+			require.NoError(t, err)
+			assert.Equal(t, []string{"b"}, sessionIDs(tagged))
+
+			count, err := repo.Count(ctx, &QueryOptions{Tags: []string{"billing"}})
+			require.NoError(t, err)
+			assert.Equal(t, 1, count)
+		})
+	}
+}
+
+func sessionIDs(sessions []*types.SessionData) []string {
+	ids := []string{}
+	for _, session := range sessions {
+		ids = append(ids, session.ID)
+	}
+	return ids
+}
+
+func TestGetByRepo(t *testing.T) {
+	for name, open := range testBackends {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			repo, err := open(t.TempDir())
+			require.NoError(t, err)
+			require.NoError(t, repo.Create(ctx, &types.SessionData{ID: "a", Title: "a", RepoPath: "/src/app", Path: "/src/app-worktree-a"}))
+			require.NoError(t, repo.Create(ctx, &types.SessionData{ID: "b", Title: "b", RepoPath: "/src/lib", Path: "/src/lib-worktree-b"}))
+			// Stored before the repository was recorded
+			require.NoError(t, repo.Create(ctx, &types.SessionData{ID: "c", Title: "c", Path: "/src/app-worktree-c"}))
+
+			app, err := repo.GetByRepo(ctx, "/src/app")
+			require.NoError(t, err)
+			assert.Equal(t, []string{"a", "c"}, sessionIDs(app))
+
+			lib := "/src/lib"
+			count, err := repo.Count(ctx, &QueryOptions{RepoPath: &lib})
+			require.NoError(t, err)
+			assert.Equal(t, 1, count)
+		})
+	}
+}
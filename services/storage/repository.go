@@ -2,6 +2,7 @@ package storage
 
 import (
 	"context"
+	"io"
 	"time"
 
 	"claude-squad/services/types"
@@ -15,9 +16,13 @@ type QueryOptions struct {
 	Path     *string
 	Program  *string
 	AutoYes  *bool
+	// Tags matches sessions that have every one of these tags
+	Tags []string
+	// RepoPath matches sessions created from the repository rooted here
+	RepoPath *string
 
 	// Sorting
-	SortBy    string // "created_at", "updated_at", "title"
+	SortBy    string // "created_at" (default), "updated_at", "title", "status"
 	SortOrder string // "asc", "desc"
 
 	// Pagination
@@ -54,6 +59,16 @@ type StorageRepository interface {
 	GetByBranch(ctx context.Context, branch string) ([]*types.SessionData, error)
 	GetActive(ctx context.Context) ([]*types.SessionData, error)
 	GetPaused(ctx context.Context) ([]*types.SessionData, error)
+	GetByRepo(ctx context.Context, repoPath string) ([]*types.SessionData, error)
+
+	// Search returns the sessions whose title, prompts or metadata contain every word of
+	// query, filtered, sorted and paginated by opts like List
+	Search(ctx context.Context, query string, opts *QueryOptions) ([]*types.SessionData, error)
+
+	// Tag operations. Tag and Untag ignore tags the session already has or lacks.
+	Tag(ctx context.Context, id string, tags ...string) error
+	Untag(ctx context.Context, id string, tags ...string) error
+	GetByTag(ctx context.Context, tag string) ([]*types.SessionData, error)
 
 	// Status operations
 	UpdateStatus(ctx context.Context, id string, status types.Status) error
@@ -68,11 +83,40 @@ type StorageRepository interface {
 	DeleteAll(ctx context.Context) error
 	DeleteOlderThan(ctx context.Context, duration time.Duration) error
 	Vacuum(ctx context.Context) error
-	Backup(ctx context.Context, path string) error
-	Restore(ctx context.Context, path string) error
+	// Verify checks every stored record against the checksum saved with it and reports
+	// the ones that are truncated or corrupt
+	Verify(ctx context.Context) (*VerifyReport, error)
+
+	// Export writes every session to w as a single gzipped tar archive, which Import on
+	// any backend can read
+	Export(ctx context.Context, w io.Writer) error
+	// Import replaces every session with the ones in an archive written by Export, all or
+	// nothing
+	Import(ctx context.Context, r io.Reader) error
 
 	// Transaction support (optional - implementations may return ErrNotSupported)
 	BeginTx(ctx context.Context) (Transaction, error)
+
+	// Change notification. Watch reports changes made by any process sharing the store
+	// until ctx is cancelled, then closes the channel.
+	Watch(ctx context.Context) (<-chan StorageEvent, error)
+}
+
+// RepairReport describes what a Repair recovered or set aside
+type RepairReport struct {
+	// Restored are the IDs of sessions recovered from their previous version
+	Restored []string
+	// Quarantined are the paths corrupted records were moved to
+	Quarantined []string
+}
+
+// Repairer is implemented by stores that can recover from corrupted session records
+type Repairer interface {
+	// Unreadable returns the records that can't be parsed
+	Unreadable(ctx context.Context) ([]string, error)
+	// Repair restores unreadable records from their previous version where possible and
+	// moves the rest aside
+	Repair(ctx context.Context) (*RepairReport, error)
 }
 
 // Transaction provides transactional operations
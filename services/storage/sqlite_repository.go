@@ -0,0 +1,789 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"claude-squad/services/types"
+
+	_ "modernc.org/sqlite"
+)
+
+// SQLiteFileName is the database file NewSQLiteRepository creates in its directory
+const SQLiteFileName = "sessions.db"
+
+// sqliteSchema stores each session as its JSON encoding, with the columns queries filter
+// and sort on copied out of it. New SessionData fields need no migration.
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS sessions (
+	id         TEXT PRIMARY KEY,
+	title      TEXT NOT NULL,
+	path       TEXT NOT NULL,
+	branch     TEXT NOT NULL,
+	program    TEXT NOT NULL,
+	auto_yes   INTEGER NOT NULL,
+	status     INTEGER NOT NULL,
+	created_at INTEGER NOT NULL,
+	updated_at INTEGER NOT NULL,
+	data       TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS sessions_status ON sessions(status);
+CREATE INDEX IF NOT EXISTS sessions_branch ON sessions(branch);
+CREATE INDEX IF NOT EXISTS sessions_updated_at ON sessions(updated_at);
+
+CREATE TABLE IF NOT EXISTS session_changes (
+	seq INTEGER PRIMARY KEY AUTOINCREMENT,
+	id  TEXT NOT NULL
+);
+CREATE TRIGGER IF NOT EXISTS sessions_inserted AFTER INSERT ON sessions
+BEGIN INSERT INTO session_changes (id) VALUES (NEW.id); END;
+CREATE TRIGGER IF NOT EXISTS sessions_updated AFTER UPDATE ON sessions
+BEGIN INSERT INTO session_changes (id) VALUES (NEW.id); END;
+CREATE TRIGGER IF NOT EXISTS sessions_deleted AFTER DELETE ON sessions
+BEGIN INSERT INTO session_changes (id) VALUES (OLD.id); END;
+
+CREATE VIRTUAL TABLE IF NOT EXISTS sessions_fts USING fts5 (id UNINDEXED, text);
+CREATE TRIGGER IF NOT EXISTS sessions_fts_inserted AFTER INSERT ON sessions
+BEGIN
+	DELETE FROM sessions_fts WHERE id = NEW.id;
+	INSERT INTO sessions_fts (id, text) VALUES (NEW.id, ` + sqliteSearchText + `);
+END;
+CREATE TRIGGER IF NOT EXISTS sessions_fts_updated AFTER UPDATE ON sessions
+BEGIN
+	DELETE FROM sessions_fts WHERE id = OLD.id;
+	INSERT INTO sessions_fts (id, text) VALUES (NEW.id, ` + sqliteSearchText + `);
+END;
+CREATE TRIGGER IF NOT EXISTS sessions_fts_deleted AFTER DELETE ON sessions
+BEGIN DELETE FROM sessions_fts WHERE id = OLD.id; END;
+`
+
+// sqliteSearchText is the text indexed for a NEW session row: its title, prompts and
+// metadata, like the other stores search. INSERT OR REPLACE doesn't fire the delete
+// trigger, so the insert trigger clears the old entry itself.
+const sqliteSearchText = `NEW.title || ' ' || COALESCE(json_extract(NEW.data, '$.prompt'), '') || ' ' ||
+		COALESCE((SELECT group_concat(json_extract(value, '$.text'), ' ') FROM json_each(NEW.data, '$.inputs')
+			WHERE json_extract(value, '$.kind') = 'prompt'), '') || ' ' ||
+		COALESCE((SELECT group_concat(key || ' ' || value, ' ') FROM json_each(NEW.data, '$.metadata')), '')`
+
+// sqliteSearchBackfill indexes sessions written before the search index existed
+const sqliteSearchBackfill = `UPDATE sessions SET id = id WHERE id NOT IN (SELECT id FROM sessions_fts)`
+
+// sqliteWatchInterval is how often Watch checks the change log the triggers fill
+const sqliteWatchInterval = 250 * time.Millisecond
+
+// sqlQuerier is the subset of *sql.DB and *sql.Tx the repository needs
+type sqlQuerier interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// sqliteRepository is a SQLite-backed implementation of StorageRepository
+type sqliteRepository struct {
+	// db is nil when the repository runs inside a transaction
+	db *sql.DB
+	q  sqlQuerier
+}
+
+// NewSQLiteRepository creates a storage repository backed by a SQLite database in dir
+func NewSQLiteRepository(dir string) (StorageRepository, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create storage directory: %w", err)
+	}
+
+	// The daemon and CLI share the database, so wait on locks instead of failing, and
+	// take the write lock up front so read-modify-write transactions can't deadlock
+	dsn := "file:" + filepath.Join(dir, SQLiteFileName) +
+		"?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_txlock=immediate"
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create schema: %w", err)
+	}
+	if _, err := db.Exec(sqliteSearchBackfill); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to build search index: %w", err)
+	}
+
+	return &sqliteRepository{db: db, q: db}, nil
+}
+
+// withTx runs fn in a transaction, or directly when the repository already is one
+func (r *sqliteRepository) withTx(ctx context.Context, fn func(tx *sqliteRepository) error) error {
+	if r.db == nil {
+		return fn(r)
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	if err := fn(&sqliteRepository{q: tx}); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// write inserts or replaces a session as is, without touching its timestamps
+func (r *sqliteRepository) write(ctx context.Context, session *types.SessionData) error {
+	data, err := sealRecord(session, false)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.q.ExecContext(ctx, `INSERT OR REPLACE INTO sessions
+		(id, title, path, branch, program, auto_yes, status, created_at, updated_at, data)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		session.ID, session.Title, session.Path, session.Branch, session.Program, session.AutoYes,
+		int(session.Status), session.CreatedAt.UnixNano(), session.UpdatedAt.UnixNano(), string(data))
+	if err != nil {
+		return fmt.Errorf("failed to write session: %w", err)
+	}
+	return nil
+}
+
+// query returns the sessions matching a WHERE clause, or every session if where is empty
+func (r *sqliteRepository) query(ctx context.Context, where string, args ...interface{}) ([]*types.SessionData, error) {
+	query := "SELECT data FROM sessions"
+	if where != "" {
+		query += " WHERE " + where
+	}
+	return r.queryRows(ctx, query, args...)
+}
+
+func (r *sqliteRepository) queryRows(ctx context.Context, query string, args ...interface{}) ([]*types.SessionData, error) {
+	rows, err := r.q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query sessions: %w", err)
+	}
+	defer rows.Close()
+
+	var sessions []*types.SessionData
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("failed to read session: %w", err)
+		}
+		var session types.SessionData
+		if err := json.Unmarshal([]byte(data), &session); err != nil {
+			continue // Skip rows that can't be decoded, like the JSON store skips bad files
+		}
+		sessions = append(sessions, &session)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query sessions: %w", err)
+	}
+	return sessions, nil
+}
+
+// Basic CRUD operations
+
+func (r *sqliteRepository) Create(ctx context.Context, session *types.SessionData) error {
+	if session.ID == "" {
+		return fmt.Errorf("session ID is required")
+	}
+
+	return r.withTx(ctx, func(tx *sqliteRepository) error {
+		exists, err := tx.Exists(ctx, session.ID)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return fmt.Errorf("session already exists: %s", session.ID)
+		}
+
+		session.CreatedAt = time.Now()
+		session.UpdatedAt = time.Now()
+		return tx.write(ctx, session)
+	})
+}
+
+func (r *sqliteRepository) Get(ctx context.Context, id string) (*types.SessionData, error) {
+	var data string
+	err := r.q.QueryRowContext(ctx, "SELECT data FROM sessions WHERE id = ?", id).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("session not found: %s", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read session: %w", err)
+	}
+
+	var session types.SessionData
+	if err := json.Unmarshal([]byte(data), &session); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal session: %w", err)
+	}
+	return &session, nil
+}
+
+func (r *sqliteRepository) Update(ctx context.Context, session *types.SessionData) error {
+	if session.ID == "" {
+		return fmt.Errorf("session ID is required")
+	}
+
+	return r.withTx(ctx, func(tx *sqliteRepository) error {
+		exists, err := tx.Exists(ctx, session.ID)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return fmt.Errorf("session not found: %s", session.ID)
+		}
+
+		session.UpdatedAt = time.Now()
+		return tx.write(ctx, session)
+	})
+}
+
+func (r *sqliteRepository) Delete(ctx context.Context, id string) error {
+	res, err := r.q.ExecContext(ctx, "DELETE FROM sessions WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete session: %w", err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("session not found: %s", id)
+	}
+	return nil
+}
+
+// Batch operations
+
+func (r *sqliteRepository) CreateBatch(ctx context.Context, sessions []*types.SessionData) error {
+	return r.withTx(ctx, func(tx *sqliteRepository) error {
+		for _, session := range sessions {
+			if err := tx.Create(ctx, session); err != nil {
+				return fmt.Errorf("failed to create session %s: %w", session.ID, err)
+			}
+		}
+		return nil
+	})
+}
+
+func (r *sqliteRepository) UpdateBatch(ctx context.Context, sessions []*types.SessionData) error {
+	return r.withTx(ctx, func(tx *sqliteRepository) error {
+		for _, session := range sessions {
+			if err := tx.Update(ctx, session); err != nil {
+				return fmt.Errorf("failed to update session %s: %w", session.ID, err)
+			}
+		}
+		return nil
+	})
+}
+
+func (r *sqliteRepository) DeleteBatch(ctx context.Context, ids []string) error {
+	return r.withTx(ctx, func(tx *sqliteRepository) error {
+		for _, id := range ids {
+			if err := tx.Delete(ctx, id); err != nil {
+				return fmt.Errorf("failed to delete session %s: %w", id, err)
+			}
+		}
+		return nil
+	})
+}
+
+// Query operations
+
+// sqliteSortColumns maps QueryOptions.SortBy values to columns
+var sqliteSortColumns = map[string]string{
+	"created_at": "created_at",
+	"updated_at": "updated_at",
+	"title":      "title",
+	"status":     "status",
+}
+
+// whereClause builds the WHERE clause and arguments for the filters in opts
+func whereClause(opts *QueryOptions) (string, []interface{}) {
+	if opts == nil {
+		return "", nil
+	}
+
+	var conds []string
+	var args []interface{}
+	add := func(cond string, arg interface{}) {
+		conds = append(conds, cond)
+		args = append(args, arg)
+	}
+
+	if opts.Status != nil {
+		add("status = ?", int(*opts.Status))
+	}
+	if opts.Branch != nil {
+		add("branch = ?", *opts.Branch)
+	}
+	if opts.Path != nil {
+		add("path = ?", *opts.Path)
+	}
+	if opts.Program != nil {
+		add("program = ?", *opts.Program)
+	}
+	if opts.AutoYes != nil {
+		add("auto_yes = ?", *opts.AutoYes)
+	}
+	if opts.RepoPath != nil {
+		// Matches repoPathOf, including sessions stored before repo_path was recorded
+		conds = append(conds, `(CASE WHEN COALESCE(json_extract(data, '$.repo_path'), '') = ''
+			THEN path = ? || '-worktree-' || id ELSE json_extract(data, '$.repo_path') = ? END)`)
+		args = append(args, *opts.RepoPath, *opts.RepoPath)
+	}
+	for _, tag := range opts.Tags {
+		add("EXISTS (SELECT 1 FROM json_each(data, '$.tags') WHERE value = ?)", tag)
+	}
+	if opts.CreatedAfter != nil {
+		add("created_at >= ?", opts.CreatedAfter.UnixNano())
+	}
+	if opts.CreatedBefore != nil {
+		add("created_at <= ?", opts.CreatedBefore.UnixNano())
+	}
+	if opts.UpdatedAfter != nil {
+		add("updated_at >= ?", opts.UpdatedAfter.UnixNano())
+	}
+	if opts.UpdatedBefore != nil {
+		add("updated_at <= ?", opts.UpdatedBefore.UnixNano())
+	}
+
+	return strings.Join(conds, " AND "), args
+}
+
+func (r *sqliteRepository) List(ctx context.Context, opts *QueryOptions) ([]*types.SessionData, error) {
+	return r.list(ctx, opts, "")
+}
+
+// list returns the sessions matching opts and, when match isn't empty, the FTS5 query
+func (r *sqliteRepository) list(ctx context.Context, opts *QueryOptions, match string) ([]*types.SessionData, error) {
+	where, args := whereClause(opts)
+	if match != "" {
+		if where != "" {
+			where += " AND "
+		}
+		where += "id IN (SELECT id FROM sessions_fts WHERE sessions_fts MATCH ?)"
+		args = append(args, match)
+	}
+
+	query := "SELECT data FROM sessions"
+	if where != "" {
+		query += " WHERE " + where
+	}
+
+	order := "created_at ASC"
+	if opts != nil && opts.SortBy != "" {
+		column, ok := sqliteSortColumns[opts.SortBy]
+		if !ok {
+			return nil, fmt.Errorf("unsupported sort field: %s", opts.SortBy)
+		}
+		direction := "ASC"
+		if strings.EqualFold(opts.SortOrder, "desc") {
+			direction = "DESC"
+		}
+		order = column + " " + direction
+	}
+	query += " ORDER BY " + order + ", id ASC"
+
+	// LIMIT -1 lets an offset apply on its own
+	if opts != nil && (opts.Limit > 0 || opts.Offset > 0) {
+		limit := opts.Limit
+		if limit <= 0 {
+			limit = -1
+		}
+		query += " LIMIT ? OFFSET ?"
+		args = append(args, limit, opts.Offset)
+	}
+
+	return r.queryRows(ctx, query, args...)
+}
+
+func (r *sqliteRepository) Count(ctx context.Context, opts *QueryOptions) (int, error) {
+	where, args := whereClause(opts)
+	query := "SELECT COUNT(*) FROM sessions"
+	if where != "" {
+		query += " WHERE " + where
+	}
+
+	var count int
+	if err := r.q.QueryRowContext(ctx, query, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count sessions: %w", err)
+	}
+
+	// Pagination applies to counts too, matching List
+	if opts != nil {
+		count = max(count-opts.Offset, 0)
+		if opts.Limit > 0 {
+			count = min(count, opts.Limit)
+		}
+	}
+	return count, nil
+}
+
+func (r *sqliteRepository) Exists(ctx context.Context, id string) (bool, error) {
+	var one int
+	err := r.q.QueryRowContext(ctx, "SELECT 1 FROM sessions WHERE id = ?", id).Scan(&one)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to check session: %w", err)
+	}
+	return true, nil
+}
+
+// Specialized queries
+
+func (r *sqliteRepository) GetByTitle(ctx context.Context, title string) (*types.SessionData, error) {
+	sessions, err := r.query(ctx, "title = ? ORDER BY created_at LIMIT 1", title)
+	if err != nil {
+		return nil, err
+	}
+	if len(sessions) == 0 {
+		return nil, fmt.Errorf("session not found with title: %s", title)
+	}
+	return sessions[0], nil
+}
+
+func (r *sqliteRepository) GetByBranch(ctx context.Context, branch string) ([]*types.SessionData, error) {
+	return r.List(ctx, &QueryOptions{Branch: &branch})
+}
+
+func (r *sqliteRepository) GetActive(ctx context.Context) ([]*types.SessionData, error) {
+	return r.query(ctx, "status IN (?, ?) ORDER BY created_at", int(types.StatusRunning), int(types.StatusReady))
+}
+
+func (r *sqliteRepository) GetPaused(ctx context.Context) ([]*types.SessionData, error) {
+	paused := types.StatusPaused
+	return r.List(ctx, &QueryOptions{Status: &paused})
+}
+
+func (r *sqliteRepository) GetByRepo(ctx context.Context, repoPath string) ([]*types.SessionData, error) {
+	return r.List(ctx, &QueryOptions{RepoPath: &repoPath})
+}
+
+// Search uses the full-text index, matching words that start with each term of query
+func (r *sqliteRepository) Search(ctx context.Context, query string, opts *QueryOptions) ([]*types.SessionData, error) {
+	// Quote each term so FTS5 operators in the query are searched for literally
+	var terms []string
+	for _, term := range searchTerms(query) {
+		terms = append(terms, `"`+strings.ReplaceAll(term, `"`, `""`)+`"*`)
+	}
+	return r.list(ctx, opts, strings.Join(terms, " "))
+}
+
+// Status operations
+
+func (r *sqliteRepository) UpdateStatus(ctx context.Context, id string, status types.Status) error {
+	return r.withTx(ctx, func(tx *sqliteRepository) error {
+		session, err := tx.Get(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		session.Status = status
+		return tx.Update(ctx, session)
+	})
+}
+
+func (r *sqliteRepository) UpdateStatusBatch(ctx context.Context, updates map[string]types.Status) error {
+	return r.withTx(ctx, func(tx *sqliteRepository) error {
+		for id, status := range updates {
+			if err := tx.UpdateStatus(ctx, id, status); err != nil {
+				return fmt.Errorf("failed to update status for %s: %w", id, err)
+			}
+		}
+		return nil
+	})
+}
+
+// Tag operations
+
+func (r *sqliteRepository) Tag(ctx context.Context, id string, tags ...string) error {
+	return r.withTx(ctx, func(tx *sqliteRepository) error {
+		session, err := tx.Get(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		added, err := addTags(session, tags)
+		if err != nil || !added {
+			return err
+		}
+		return tx.Update(ctx, session)
+	})
+}
+
+func (r *sqliteRepository) Untag(ctx context.Context, id string, tags ...string) error {
+	return r.withTx(ctx, func(tx *sqliteRepository) error {
+		session, err := tx.Get(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if !removeTags(session, tags) {
+			return nil
+		}
+		return tx.Update(ctx, session)
+	})
+}
+
+func (r *sqliteRepository) GetByTag(ctx context.Context, tag string) ([]*types.SessionData, error) {
+	return r.List(ctx, &QueryOptions{Tags: []string{tag}})
+}
+
+// Metadata operations
+
+func (r *sqliteRepository) SetMetadata(ctx context.Context, id string, key, value string) error {
+	return r.withTx(ctx, func(tx *sqliteRepository) error {
+		session, err := tx.Get(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if session.Metadata == nil {
+			session.Metadata = make(map[string]string)
+		}
+		session.Metadata[key] = value
+		return tx.Update(ctx, session)
+	})
+}
+
+func (r *sqliteRepository) GetMetadata(ctx context.Context, id string, key string) (string, error) {
+	session, err := r.Get(ctx, id)
+	if err != nil {
+		return "", err
+	}
+
+	value, exists := session.Metadata[key]
+	if !exists {
+		return "", fmt.Errorf("metadata key not found: %s", key)
+	}
+	return value, nil
+}
+
+func (r *sqliteRepository) DeleteMetadata(ctx context.Context, id string, key string) error {
+	return r.withTx(ctx, func(tx *sqliteRepository) error {
+		session, err := tx.Get(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if _, exists := session.Metadata[key]; !exists {
+			return nil
+		}
+		delete(session.Metadata, key)
+		return tx.Update(ctx, session)
+	})
+}
+
+// Maintenance operations
+
+func (r *sqliteRepository) DeleteAll(ctx context.Context) error {
+	if _, err := r.q.ExecContext(ctx, "DELETE FROM sessions"); err != nil {
+		return fmt.Errorf("failed to delete sessions: %w", err)
+	}
+	return nil
+}
+
+func (r *sqliteRepository) DeleteOlderThan(ctx context.Context, duration time.Duration) error {
+	cutoff := time.Now().Add(-duration)
+	if _, err := r.q.ExecContext(ctx, "DELETE FROM sessions WHERE updated_at <= ?", cutoff.UnixNano()); err != nil {
+		return fmt.Errorf("failed to delete old sessions: %w", err)
+	}
+	return nil
+}
+
+// Verify runs SQLite's own consistency check of the database file, then checks each
+// session record
+func (r *sqliteRepository) Verify(ctx context.Context) (*VerifyReport, error) {
+	report := &VerifyReport{}
+
+	var result string
+	if err := r.q.QueryRowContext(ctx, "PRAGMA quick_check").Scan(&result); err != nil {
+		return nil, fmt.Errorf("failed to check database: %w", err)
+	}
+	if result != "ok" {
+		report.Corrupt = append(report.Corrupt, CorruptRecord{Record: "database", Problem: result})
+	}
+
+	rows, err := r.q.QueryContext(ctx, "SELECT id, data FROM sessions ORDER BY id")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query sessions: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id, data string
+		if err := rows.Scan(&id, &data); err != nil {
+			return nil, fmt.Errorf("failed to read session: %w", err)
+		}
+		report.add(id, []byte(data))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query sessions: %w", err)
+	}
+	return report, nil
+}
+
+func (r *sqliteRepository) Vacuum(ctx context.Context) error {
+	if r.db == nil {
+		return fmt.Errorf("vacuum cannot run inside a transaction")
+	}
+	// Watchers remember the last change they saw, and AUTOINCREMENT never reuses a
+	// sequence number, so the change log can be emptied at any time
+	if _, err := r.db.ExecContext(ctx, "DELETE FROM session_changes"); err != nil {
+		return fmt.Errorf("failed to clear change log: %w", err)
+	}
+	if _, err := r.db.ExecContext(ctx, "VACUUM"); err != nil {
+		return fmt.Errorf("failed to vacuum database: %w", err)
+	}
+	return nil
+}
+
+func (r *sqliteRepository) Export(ctx context.Context, w io.Writer) error {
+	sessions, err := r.query(ctx, "")
+	if err != nil {
+		return err
+	}
+	return writeArchive(w, sessions)
+}
+
+// Import replaces every session with the ones in an archive in a single transaction
+func (r *sqliteRepository) Import(ctx context.Context, rd io.Reader) error {
+	sessions, err := readArchive(rd)
+	if err != nil {
+		return err
+	}
+
+	return r.withTx(ctx, func(tx *sqliteRepository) error {
+		if err := tx.DeleteAll(ctx); err != nil {
+			return fmt.Errorf("failed to clear existing data: %w", err)
+		}
+		for _, session := range sessions {
+			if err := tx.write(ctx, session); err != nil {
+				return fmt.Errorf("failed to import session %s: %w", session.ID, err)
+			}
+		}
+		return nil
+	})
+}
+
+// Change notification
+
+// Watch reports changes recorded in the change log by the schema's triggers, which catch
+// writes from every process using the database
+func (r *sqliteRepository) Watch(ctx context.Context) (<-chan StorageEvent, error) {
+	if r.db == nil {
+		return nil, fmt.Errorf("watch cannot run inside a transaction")
+	}
+
+	// Read the snapshot and the change log position together so no change is missed
+	var (
+		sessions []*types.SessionData
+		lastSeq  int64
+	)
+	err := r.withTx(ctx, func(tx *sqliteRepository) error {
+		if err := tx.q.QueryRowContext(ctx, "SELECT COALESCE(MAX(seq), 0) FROM session_changes").Scan(&lastSeq); err != nil {
+			return fmt.Errorf("failed to read change log: %w", err)
+		}
+		var err error
+		sessions, err = tx.query(ctx, "")
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	watched := newWatchedSessions(sessions)
+
+	events := make(chan StorageEvent)
+	go func() {
+		defer close(events)
+
+		ticker := time.NewTicker(sqliteWatchInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			ids, seq, err := r.changesSince(ctx, lastSeq)
+			if err != nil {
+				continue // The database can be busy; try again next tick
+			}
+			lastSeq = seq
+
+			for _, id := range ids {
+				session, err := r.Get(ctx, id)
+				if err != nil {
+					session = nil
+				}
+				if event, ok := watched.observe(id, session); ok && !sendEvents(ctx, events, event) {
+					return
+				}
+			}
+		}
+	}()
+
+	return events, nil
+}
+
+// changesSince returns the IDs of the sessions changed after seq, in the order they first
+// changed, and the sequence number of the latest change
+func (r *sqliteRepository) changesSince(ctx context.Context, seq int64) ([]string, int64, error) {
+	rows, err := r.q.QueryContext(ctx, "SELECT seq, id FROM session_changes WHERE seq > ? ORDER BY seq", seq)
+	if err != nil {
+		return nil, seq, fmt.Errorf("failed to read change log: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	seen := make(map[string]bool)
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&seq, &id); err != nil {
+			return nil, seq, fmt.Errorf("failed to read change log: %w", err)
+		}
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	return ids, seq, rows.Err()
+}
+
+// Transaction support
+
+func (r *sqliteRepository) BeginTx(ctx context.Context) (Transaction, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	return &sqliteTransaction{sqliteRepository: &sqliteRepository{q: tx}, tx: tx}, nil
+}
+
+// sqliteTransaction is a repository whose operations all run in one database transaction
+type sqliteTransaction struct {
+	*sqliteRepository
+	tx *sql.Tx
+}
+
+func (t *sqliteTransaction) Commit() error {
+	return t.tx.Commit()
+}
+
+func (t *sqliteTransaction) Rollback() error {
+	return t.tx.Rollback()
+}
+
+func (t *sqliteTransaction) BeginTx(ctx context.Context) (Transaction, error) {
+	return t, nil // Nested transactions join the outer one
+}
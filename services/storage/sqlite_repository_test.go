@@ -0,0 +1,110 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"claude-squad/services/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLiteRepository(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	repo, err := NewSQLiteRepository(dir)
+	require.NoError(t, err)
+
+	require.NoError(t, repo.CreateBatch(ctx, []*types.SessionData{
+		{ID: "a", Title: "alpha", Branch: "feat", Status: types.StatusRunning, Metadata: map[string]string{"k": "v"}},
+		{ID: "b", Title: "beta", Branch: "feat", Status: types.StatusPaused},
+		{ID: "c", Title: "gamma", Branch: "fix", Status: types.StatusReady},
+	}))
+	assert.Error(t, repo.Create(ctx, &types.SessionData{ID: "a", Title: "dup"}))
+
+	got, err := repo.Get(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, "alpha", got.Title)
+	assert.Equal(t, "v", got.Metadata["k"])
+
+	byBranch, err := repo.GetByBranch(ctx, "feat")
+	require.NoError(t, err)
+	assert.Len(t, byBranch, 2)
+
+	active, err := repo.GetActive(ctx)
+	require.NoError(t, err)
+	assert.Len(t, active, 2)
+
+	sorted, err := repo.List(ctx, &QueryOptions{SortBy: "title", SortOrder: "desc", Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, sorted, 2)
+	assert.Equal(t, []string{"gamma", "beta"}, []string{sorted[0].Title, sorted[1].Title})
+
+	require.NoError(t, repo.UpdateStatus(ctx, "b", types.StatusReady))
+	count, err := repo.Count(ctx, &QueryOptions{Status: &[]types.Status{types.StatusReady}[0]})
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	require.NoError(t, repo.SetMetadata(ctx, "c", "pr", "42"))
+	value, err := repo.GetMetadata(ctx, "c", "pr")
+	require.NoError(t, err)
+	assert.Equal(t, "42", value)
+
+	assert.Error(t, repo.Update(ctx, &types.SessionData{ID: "missing"}))
+	assert.Error(t, repo.Delete(ctx, "missing"))
+	assert.NoError(t, repo.Vacuum(ctx))
+
+	// Sessions persist across reopening the database
+	reopened, err := NewSQLiteRepository(dir)
+	require.NoError(t, err)
+	byTitle, err := reopened.GetByTitle(ctx, "gamma")
+	require.NoError(t, err)
+	assert.Equal(t, "c", byTitle.ID)
+}
+
+func TestSQLiteTransactionRollback(t *testing.T) {
+	ctx := context.Background()
+	repo, err := NewSQLiteRepository(t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, repo.Create(ctx, &types.SessionData{ID: "a", Title: "alpha"}))
+
+	tx, err := repo.BeginTx(ctx)
+	require.NoError(t, err)
+	require.NoError(t, tx.Create(ctx, &types.SessionData{ID: "b", Title: "beta"}))
+	require.NoError(t, tx.Delete(ctx, "a"))
+	require.NoError(t, tx.Rollback())
+
+	sessions, err := repo.List(ctx, nil)
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	assert.Equal(t, "a", sessions[0].ID)
+
+	// A failed batch leaves nothing behind
+	err = repo.CreateBatch(ctx, []*types.SessionData{{ID: "c", Title: "gamma"}, {ID: "a", Title: "dup"}})
+	assert.Error(t, err)
+	exists, err := repo.Exists(ctx, "c")
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestSQLiteExportImport(t *testing.T) {
+	ctx := context.Background()
+	repo, err := NewSQLiteRepository(t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, repo.Create(ctx, &types.SessionData{ID: "a", Title: "alpha"}))
+	original, err := repo.Get(ctx, "a")
+	require.NoError(t, err)
+
+	var archive bytes.Buffer
+	require.NoError(t, repo.Export(ctx, &archive))
+	require.NoError(t, repo.DeleteAll(ctx))
+
+	require.NoError(t, repo.Import(ctx, &archive))
+	restored, err := repo.Get(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, "alpha", restored.Title)
+	assert.WithinDuration(t, original.CreatedAt, restored.CreatedAt, time.Millisecond)
+}
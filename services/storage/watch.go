@@ -0,0 +1,91 @@
+package storage
+
+import (
+	"context"
+	"time"
+
+	"claude-squad/services/types"
+)
+
+// StorageEventType is the kind of change a StorageEvent reports
+type StorageEventType string
+
+const (
+	StorageEventCreated StorageEventType = "created"
+	StorageEventUpdated StorageEventType = "updated"
+	StorageEventDeleted StorageEventType = "deleted"
+)
+
+// StorageEvent reports a change to a stored session, made by this process or another one
+// sharing the store
+type StorageEvent struct {
+	Type StorageEventType
+	ID   string
+	// Session is the session after the change, or nil when it was deleted
+	Session *types.SessionData
+}
+
+// watchedSessions remembers the version of each session a watch last reported, so it can
+// tell creates from updates and drop notifications that changed nothing
+type watchedSessions map[string]time.Time
+
+func newWatchedSessions(sessions []*types.SessionData) watchedSessions {
+	w := make(watchedSessions, len(sessions))
+	for _, session := range sessions {
+		w[session.ID] = session.UpdatedAt
+	}
+	return w
+}
+
+// observe records the current state of a session, which is nil when it no longer exists,
+// and returns the event to report for it. ok is false when nothing changed.
+func (w watchedSessions) observe(id string, session *types.SessionData) (event StorageEvent, ok bool) {
+	updatedAt, known := w[id]
+	switch {
+	case session == nil && !known:
+		return StorageEvent{}, false
+	case session == nil:
+		delete(w, id)
+		return StorageEvent{Type: StorageEventDeleted, ID: id}, true
+	case !known:
+		w[id] = session.UpdatedAt
+		return StorageEvent{Type: StorageEventCreated, ID: id, Session: session}, true
+	case updatedAt.Equal(session.UpdatedAt):
+		return StorageEvent{}, false
+	default:
+		w[id] = session.UpdatedAt
+		return StorageEvent{Type: StorageEventUpdated, ID: id, Session: session}, true
+	}
+}
+
+// diff observes a full snapshot of the store and returns the events since the last one
+func (w watchedSessions) diff(sessions []*types.SessionData) []StorageEvent {
+	var events []StorageEvent
+	seen := make(map[string]bool, len(sessions))
+	for _, session := range sessions {
+		seen[session.ID] = true
+		if event, ok := w.observe(session.ID, session); ok {
+			events = append(events, event)
+		}
+	}
+	for id := range w {
+		if !seen[id] {
+			if event, ok := w.observe(id, nil); ok {
+				events = append(events, event)
+			}
+		}
+	}
+	return events
+}
+
+// sendEvents delivers events in order, returning false if ctx ends first
+func sendEvents(ctx context.Context, ch chan<- StorageEvent, events ...StorageEvent) bool {
+	for _, event := range events {
+		select {
+		case ch <- event:
+		case <-ctx.Done():
+			return false
+		}
+	}
+	return true
+}
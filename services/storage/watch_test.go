@@ -0,0 +1,60 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"claude-squad/services/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatch(t *testing.T) {
+	for name, open := range testBackends {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+
+			dir := t.TempDir()
+			watcher, err := open(dir)
+			require.NoError(t, err)
+			events, err := watcher.Watch(ctx)
+			require.NoError(t, err)
+
+			// Changes come from a second repository, as they would from another process
+			writer, err := open(dir)
+			require.NoError(t, err)
+			next := func() StorageEvent {
+				select {
+				case event := <-events:
+					return event
+				case <-ctx.Done():
+					t.Fatal("timed out waiting for event")
+					return StorageEvent{}
+				}
+			}
+
+			require.NoError(t, writer.Create(ctx, &types.SessionData{ID: "a", Title: "alpha"}))
+			event := next()
+			assert.Equal(t, StorageEventCreated, event.Type)
+			assert.Equal(t, "alpha", event.Session.Title)
+
+			require.NoError(t, writer.Update(ctx, &types.SessionData{ID: "a", Title: "renamed"}))
+			event = next()
+			assert.Equal(t, StorageEventUpdated, event.Type)
+			assert.Equal(t, "renamed", event.Session.Title)
+
+			require.NoError(t, writer.Delete(ctx, "a"))
+			event = next()
+			assert.Equal(t, StorageEventDeleted, event.Type)
+			assert.Equal(t, "a", event.ID)
+			assert.Nil(t, event.Session)
+
+			cancel()
+			for range events {
+			}
+		})
+	}
+}
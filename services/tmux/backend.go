@@ -0,0 +1,34 @@
+package tmux
+
+import (
+	"fmt"
+	"runtime"
+
+	"claude-squad/services/executor"
+)
+
+// Multiplexers selectable with the multiplexer config key
+const (
+	MultiplexerTmux       = "tmux"
+	MultiplexerWezTerm    = "wezterm"
+	MultiplexerKubernetes = "kubernetes"
+)
+
+// NewService creates the TmuxService for multiplexer, running its commands through exec.
+// An empty multiplexer selects tmux, or WezTerm on Windows where tmux doesn't run natively.
+// kube configures the kubernetes multiplexer and is ignored by the others.
+func NewService(multiplexer string, exec executor.CommandExecutor, kube KubernetesOptions) (TmuxService, error) {
+	if multiplexer == "" && runtime.GOOS == "windows" {
+		multiplexer = MultiplexerWezTerm
+	}
+	switch multiplexer {
+	case "", MultiplexerTmux:
+		return NewExecTmuxService(exec), nil
+	case MultiplexerWezTerm:
+		return NewWezTermService(exec), nil
+	case MultiplexerKubernetes:
+		return NewKubernetesService(exec, kube)
+	default:
+		return nil, fmt.Errorf("unknown multiplexer '%s'", multiplexer)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"os"
 	"regexp"
 	"strconv"
 	"strings"
@@ -28,13 +29,18 @@ func NewExecTmuxService(exec executor.CommandExecutor) TmuxService {
 	}
 }
 
-// sanitizeTmuxName converts a string to a valid tmux session name
-func (s *execTmuxService) sanitizeTmuxName(name string) string {
+// SessionName returns the tmux session name used for the session with the given name
+func SessionName(name string) string {
 	name = whiteSpaceRegex.ReplaceAllString(name, "")
 	name = strings.ReplaceAll(name, ".", "_") // tmux replaces dots with underscores
 	return fmt.Sprintf("%s%s", tmuxPrefix, name)
 }
 
+// sanitizeTmuxName converts a string to a valid tmux session name
+func (s *execTmuxService) sanitizeTmuxName(name string) string {
+	return SessionName(name)
+}
+
 // runTmuxCommand executes a tmux command
 func (s *execTmuxService) runTmuxCommand(ctx context.Context, args ...string) (string, error) {
 	cmd := executor.Command{
@@ -60,7 +66,7 @@ func (s *execTmuxService) CreateSession(ctx context.Context, name, startDir, com
 	sanitizedName := s.sanitizeTmuxName(name)
 
 	// Check if session already exists
-	if exists, _ := s.SessionExists(ctx, sanitizedName); exists {
+	if exists, _ := s.SessionExists(ctx, name); exists {
 		return nil, fmt.Errorf("session already exists: %s", sanitizedName)
 	}
 
@@ -77,7 +83,7 @@ func (s *execTmuxService) CreateSession(ctx context.Context, name, startDir, com
 		return nil, fmt.Errorf("failed to create session: %w", err)
 	}
 
-	return s.GetSession(ctx, sanitizedName)
+	return s.GetSession(ctx, name)
 }
 
 func (s *execTmuxService) AttachSession(ctx context.Context, sessionName string) error {
@@ -88,21 +94,27 @@ func (s *execTmuxService) AttachSession(ctx context.Context, sessionName string)
 		return fmt.Errorf("session does not exist: %s", sanitizedName)
 	}
 
-	// Use interactive execution for attachment
+	// tmux needs a real terminal, so hand it ours rather than pipes
 	cmd := executor.Command{
 		Program: "tmux",
 		Args:    []string{"attach-session", "-t", sanitizedName},
+		Stdin:   os.Stdin,
+		Stdout:  os.Stdout,
+		Stderr:  os.Stderr,
 	}
 
-	// This needs to be run interactively
-	pipe, err := s.executor.ExecuteInteractive(ctx, cmd)
+	handle, err := s.executor.Start(ctx, cmd)
 	if err != nil {
 		return fmt.Errorf("failed to attach session: %w", err)
 	}
 
 	// Wait for detach
-	if pipe != nil {
-		pipe.Close()
+	result, err := s.executor.Wait(ctx, handle)
+	if err != nil {
+		return fmt.Errorf("failed to attach session: %w", err)
+	}
+	if result.Error != nil {
+		return fmt.Errorf("failed to attach session: %w", result.Error)
 	}
 
 	return nil
@@ -438,6 +450,17 @@ func (s *execTmuxService) SendKeys(ctx context.Context, sessionName string, keys
 	return nil
 }
 
+// SendText types text into the session literally, without interpreting key names. The
+// text follows "--" so that a prompt starting with a dash isn't parsed as a flag.
+func (s *execTmuxService) SendText(ctx context.Context, sessionName string, text string) error {
+	sanitizedName := s.sanitizeTmuxName(sessionName)
+
+	if _, err := s.runTmuxCommand(ctx, "send-keys", "-t", sanitizedName, "-l", "--", text); err != nil {
+		return fmt.Errorf("failed to send text: %w", err)
+	}
+	return nil
+}
+
 func (s *execTmuxService) SendKeysToPane(ctx context.Context, sessionName, paneID, keys string) error {
 	sanitizedName := s.sanitizeTmuxName(sessionName)
 	target := fmt.Sprintf("%s:%s", sanitizedName, paneID)
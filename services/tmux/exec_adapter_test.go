@@ -0,0 +1,25 @@
+package tmux
+
+import (
+	"context"
+	"testing"
+
+	"claude-squad/services/executor"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecSendTextLeadingDash(t *testing.T) {
+	var args []string
+	svc := NewExecTmuxService(&executor.MockExecutor{
+		ExecuteFunc: func(ctx context.Context, cmd executor.Command) (*executor.Result, error) {
+			args = cmd.Args
+			return &executor.Result{}, nil
+		},
+	})
+
+	// A prompt that looks like a flag is still sent as text
+	require.NoError(t, svc.SendText(context.Background(), "fix", "-n refactor the parser"))
+	assert.Equal(t, []string{"send-keys", "-t", SessionName("fix"), "-l", "--", "-n refactor the parser"}, args)
+}
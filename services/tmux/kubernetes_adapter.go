@@ -0,0 +1,366 @@
+package tmux
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"claude-squad/services/executor"
+)
+
+const (
+	// podManagedByLabel marks the pods of sessions, so they can be listed
+	podManagedByLabel = "app.kubernetes.io/managed-by=claude-squad"
+	// podSessionAnnotation holds the name of the session a pod runs, which pod names
+	// can't always hold as it is
+	podSessionAnnotation = "claude-squad/session"
+	// defaultPodReadyTimeout is how long CreateSession waits for a pod to be scheduled and
+	// started by default
+	defaultPodReadyTimeout = 2 * time.Minute
+)
+
+// KubernetesOptions configures where and how a Kubernetes multiplexer runs sessions
+type KubernetesOptions struct {
+	// Context is the kubeconfig context to use instead of the current one
+	Context string
+	// Namespace is the namespace pods are created in instead of the context's default one
+	Namespace string
+	// Image is the image of session pods, which must provide sh, tmux and the programs
+	// sessions run
+	Image string
+	// WorkDir is the directory sessions start in within their pod instead of the image's
+	// working directory
+	WorkDir string
+	// ReadyTimeout is how long to wait for a new pod to start. Defaults to 2 minutes.
+	ReadyTimeout time.Duration
+}
+
+// kubernetesPod is a pod as listed by `kubectl get pods -o json`
+type kubernetesPod struct {
+	Metadata struct {
+		Name              string            `json:"name"`
+		Annotations       map[string]string `json:"annotations"`
+		CreationTimestamp string            `json:"creationTimestamp"`
+	} `json:"metadata"`
+	Status struct {
+		Phase string `json:"phase"`
+	} `json:"status"`
+}
+
+// kubernetesService implements TmuxService by running each session in its own pod, so a
+// fleet of agents can run on a cluster while cs orchestrates them from here. The pod runs
+// tmux, which every operation on the session's windows and panes is passed on to through
+// `kubectl exec`. Killing a session deletes its pod.
+//
+// Sessions start in the image's checkout of the code: the local worktree cs creates for a
+// session isn't available in the pod, so the image has to fetch and push the code itself.
+type kubernetesService struct {
+	executor executor.CommandExecutor
+	opts     KubernetesOptions
+}
+
+// NewKubernetesService creates a TmuxService running sessions in pods, with kubectl run
+// through exec
+func NewKubernetesService(exec executor.CommandExecutor, opts KubernetesOptions) (TmuxService, error) {
+	if opts.Image == "" {
+		return nil, fmt.Errorf("an image is required for the kubernetes multiplexer")
+	}
+	if opts.ReadyTimeout <= 0 {
+		opts.ReadyTimeout = defaultPodReadyTimeout
+	}
+	return &kubernetesService{executor: exec, opts: opts}, nil
+}
+
+// Program returns the program sessions are managed with
+func (s *kubernetesService) Program() string {
+	return "kubectl"
+}
+
+// runKubectl executes a kubectl command in the configured context and namespace
+func (s *kubernetesService) runKubectl(ctx context.Context, timeout time.Duration, args ...string) (string, error) {
+	kubectlOpts := executor.KubernetesOptions{Context: s.opts.Context, Namespace: s.opts.Namespace}
+	result, err := s.executor.Execute(ctx, executor.Command{
+		Program: "kubectl",
+		Args:    append(kubectlOpts.KubectlArgs(), args...),
+		Timeout: timeout,
+	})
+	if err != nil {
+		return "", fmt.Errorf("kubectl command failed: %w", err)
+	}
+	if result.ExitCode != 0 {
+		return "", fmt.Errorf("kubectl command failed with exit code %d: %s", result.ExitCode, string(result.Stderr))
+	}
+	return string(result.Stdout), nil
+}
+
+var invalidPodNameChars = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// podName returns the name of a session's pod. Pod names are limited to 63 lowercase
+// letters, digits and dashes, so the session's name is cut down to fit, with a hash of it
+// keeping names that end up the same apart.
+func podName(sessionName string) string {
+	name := workspaceName(sessionName)
+	sum := sha1.Sum([]byte(name))
+	base := invalidPodNameChars.ReplaceAllString(strings.ToLower(name), "-")
+	base = strings.Trim(base, "-")
+	if len(base) > 50 {
+		base = strings.TrimRight(base[:50], "-")
+	}
+	return "cs-" + base + "-" + hex.EncodeToString(sum[:4])
+}
+
+// podSessionName returns the name a session's tmux session has inside its pod, given the
+// name callers pass with or without the prefix
+func podSessionName(name string) string {
+	return strings.TrimPrefix(name, tmuxPrefix)
+}
+
+// inPod returns the tmux service driving the tmux server in a session's pod
+func (s *kubernetesService) inPod(sessionName string) TmuxService {
+	// A pod name is always given, so this can't fail
+	exec, _ := executor.NewKubernetesExecutor(s.executor, executor.KubernetesOptions{
+		Context:   s.opts.Context,
+		Namespace: s.opts.Namespace,
+		Pod:       podName(sessionName),
+	})
+	return NewExecTmuxService(exec)
+}
+
+// pods lists the pods of sessions
+func (s *kubernetesService) pods(ctx context.Context) ([]kubernetesPod, error) {
+	output, err := s.runKubectl(ctx, 30*time.Second, "get", "pods", "--selector", podManagedByLabel, "--output", "json")
+	if err != nil {
+		return nil, err
+	}
+	var list struct {
+		Items []kubernetesPod `json:"items"`
+	}
+	if err := json.Unmarshal([]byte(output), &list); err != nil {
+		return nil, fmt.Errorf("failed to parse pod list: %w", err)
+	}
+	return list.Items, nil
+}
+
+// deletePod deletes a session's pod without waiting for it to terminate
+func (s *kubernetesService) deletePod(ctx context.Context, sessionName string) error {
+	_, err := s.runKubectl(ctx, 30*time.Second, "delete", "pod", podName(sessionName), "--ignore-not-found", "--wait=false")
+	return err
+}
+
+// Session management
+
+// CreateSession starts a pod for the session and runs command in tmux inside it. The pod
+// only sleeps, so the session lasts until it is killed even if tmux exits.
+func (s *kubernetesService) CreateSession(ctx context.Context, name, startDir, command string) (*Session, error) {
+	pod := podName(name)
+	if exists, _ := s.SessionExists(ctx, name); exists {
+		return nil, fmt.Errorf("session already exists: %s", workspaceName(name))
+	}
+
+	if _, err := s.runKubectl(ctx, 30*time.Second, "run", pod,
+		"--image", s.opts.Image,
+		"--restart", "Never",
+		"--labels", podManagedByLabel,
+		"--annotations", podSessionAnnotation+"="+workspaceName(name),
+		"--command", "--", "sleep", "infinity"); err != nil {
+		return nil, fmt.Errorf("failed to create session pod: %w", err)
+	}
+	if _, err := s.runKubectl(ctx, s.opts.ReadyTimeout+10*time.Second, "wait", "--for", "condition=Ready",
+		"pod/"+pod, "--timeout", s.opts.ReadyTimeout.String()); err != nil {
+		_ = s.deletePod(ctx, name)
+		return nil, fmt.Errorf("session pod didn't start: %w", err)
+	}
+
+	// The local directory doesn't exist in the pod
+	session, err := s.inPod(name).CreateSession(ctx, podSessionName(name), s.opts.WorkDir, command)
+	if err != nil {
+		_ = s.deletePod(ctx, name)
+		return nil, err
+	}
+	return session, nil
+}
+
+// AttachSession attaches to tmux in the session's pod through `kubectl exec -it`
+func (s *kubernetesService) AttachSession(ctx context.Context, name string) error {
+	return s.inPod(name).AttachSession(ctx, podSessionName(name))
+}
+
+func (s *kubernetesService) DetachSession(ctx context.Context, name string) error {
+	return s.inPod(name).DetachSession(ctx, podSessionName(name))
+}
+
+// KillSession deletes the session's pod, along with everything running in it
+func (s *kubernetesService) KillSession(ctx context.Context, name string) error {
+	if err := s.deletePod(ctx, name); err != nil {
+		return fmt.Errorf("failed to kill session: %w", err)
+	}
+	return nil
+}
+
+// ListSessions lists the sessions whose pods are running, without asking each pod's tmux
+// about its windows
+func (s *kubernetesService) ListSessions(ctx context.Context) ([]*Session, error) {
+	pods, err := s.pods(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	sessions := []*Session{}
+	for _, pod := range pods {
+		name := pod.Metadata.Annotations[podSessionAnnotation]
+		if name == "" || pod.Status.Phase != "Running" {
+			continue
+		}
+		sessions = append(sessions, &Session{
+			Name:    name,
+			ID:      pod.Metadata.Name,
+			Windows: 1,
+			Created: pod.Metadata.CreationTimestamp,
+		})
+	}
+	return sessions, nil
+}
+
+func (s *kubernetesService) GetSession(ctx context.Context, name string) (*Session, error) {
+	return s.inPod(name).GetSession(ctx, podSessionName(name))
+}
+
+// RenameSession isn't supported, as pods can't be renamed
+func (s *kubernetesService) RenameSession(ctx context.Context, oldName, newName string) error {
+	return fmt.Errorf("failed to rename session: pods can't be renamed")
+}
+
+// SessionExists reports whether the session's pod is running tmux with the session
+func (s *kubernetesService) SessionExists(ctx context.Context, name string) (bool, error) {
+	return s.inPod(name).SessionExists(ctx, podSessionName(name))
+}
+
+// Window management, in the session's pod
+
+func (s *kubernetesService) CreateWindow(ctx context.Context, name, windowName, command string) (*Window, error) {
+	return s.inPod(name).CreateWindow(ctx, podSessionName(name), windowName, command)
+}
+
+func (s *kubernetesService) KillWindow(ctx context.Context, name, windowID string) error {
+	return s.inPod(name).KillWindow(ctx, podSessionName(name), windowID)
+}
+
+func (s *kubernetesService) ListWindows(ctx context.Context, name string) ([]*Window, error) {
+	return s.inPod(name).ListWindows(ctx, podSessionName(name))
+}
+
+func (s *kubernetesService) RenameWindow(ctx context.Context, name, windowID, newName string) error {
+	return s.inPod(name).RenameWindow(ctx, podSessionName(name), windowID, newName)
+}
+
+func (s *kubernetesService) SelectWindow(ctx context.Context, name, windowID string) error {
+	return s.inPod(name).SelectWindow(ctx, podSessionName(name), windowID)
+}
+
+// Pane management, in the session's pod
+
+func (s *kubernetesService) SplitPane(ctx context.Context, name, windowID string, vertical bool, command string) (*Pane, error) {
+	return s.inPod(name).SplitPane(ctx, podSessionName(name), windowID, vertical, command)
+}
+
+func (s *kubernetesService) KillPane(ctx context.Context, name, paneID string) error {
+	return s.inPod(name).KillPane(ctx, podSessionName(name), paneID)
+}
+
+func (s *kubernetesService) ListPanes(ctx context.Context, name, windowID string) ([]*Pane, error) {
+	return s.inPod(name).ListPanes(ctx, podSessionName(name), windowID)
+}
+
+func (s *kubernetesService) ResizePane(ctx context.Context, name, paneID string, width, height int) error {
+	return s.inPod(name).ResizePane(ctx, podSessionName(name), paneID, width, height)
+}
+
+func (s *kubernetesService) SelectPane(ctx context.Context, name, paneID string) error {
+	return s.inPod(name).SelectPane(ctx, podSessionName(name), paneID)
+}
+
+// Input/Output operations, in the session's pod
+
+func (s *kubernetesService) SendKeys(ctx context.Context, name string, keys string) error {
+	return s.inPod(name).SendKeys(ctx, podSessionName(name), keys)
+}
+
+func (s *kubernetesService) SendKeysToPane(ctx context.Context, name, paneID, keys string) error {
+	return s.inPod(name).SendKeysToPane(ctx, podSessionName(name), paneID, keys)
+}
+
+func (s *kubernetesService) SendText(ctx context.Context, name string, text string) error {
+	return s.inPod(name).SendText(ctx, podSessionName(name), text)
+}
+
+func (s *kubernetesService) CapturePane(ctx context.Context, name, paneID string) (string, error) {
+	return s.inPod(name).CapturePane(ctx, podSessionName(name), paneID)
+}
+
+func (s *kubernetesService) GetPaneOutput(ctx context.Context, name, paneID string, lines int) (string, error) {
+	return s.inPod(name).GetPaneOutput(ctx, podSessionName(name), paneID, lines)
+}
+
+func (s *kubernetesService) GetPaneScrollback(ctx context.Context, name, paneID string) (string, error) {
+	return s.inPod(name).GetPaneScrollback(ctx, podSessionName(name), paneID)
+}
+
+// Streaming operations, in the session's pod
+
+func (s *kubernetesService) StreamOutput(ctx context.Context, name string) (io.ReadCloser, error) {
+	return s.inPod(name).StreamOutput(ctx, podSessionName(name))
+}
+
+func (s *kubernetesService) StreamPaneOutput(ctx context.Context, name, paneID string) (io.ReadCloser, error) {
+	return s.inPod(name).StreamPaneOutput(ctx, podSessionName(name), paneID)
+}
+
+// Configuration and utilities, in the session's pod
+
+func (s *kubernetesService) SetOption(ctx context.Context, name, option, value string) error {
+	return s.inPod(name).SetOption(ctx, podSessionName(name), option, value)
+}
+
+func (s *kubernetesService) GetOption(ctx context.Context, name, option string) (string, error) {
+	return s.inPod(name).GetOption(ctx, podSessionName(name), option)
+}
+
+func (s *kubernetesService) ResizeSession(ctx context.Context, name string, width, height int) error {
+	return s.inPod(name).ResizeSession(ctx, podSessionName(name), width, height)
+}
+
+func (s *kubernetesService) HasActivity(ctx context.Context, name string) (bool, error) {
+	return s.inPod(name).HasActivity(ctx, podSessionName(name))
+}
+
+// GetSessionPID fails, as the PID of the session's program within its pod means nothing
+// to executors outside it
+func (s *kubernetesService) GetSessionPID(ctx context.Context, name string) (int, error) {
+	return 0, fmt.Errorf("failed to get session PID: sessions run in pods")
+}
+
+// Cleanup operations
+
+func (s *kubernetesService) CleanupSessions(ctx context.Context, prefix string) error {
+	sessions, err := s.ListSessions(ctx)
+	if err != nil {
+		return err
+	}
+	for _, session := range sessions {
+		if strings.HasPrefix(session.Name, prefix) {
+			_ = s.KillSession(ctx, session.Name)
+		}
+	}
+	return nil
+}
+
+func (s *kubernetesService) CleanupOrphanedSessions(ctx context.Context) error {
+	// Delete the pods of all sessions with the claudesquad prefix
+	return s.CleanupSessions(ctx, tmuxPrefix)
+}
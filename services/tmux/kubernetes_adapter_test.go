@@ -0,0 +1,99 @@
+package tmux
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"claude-squad/services/executor"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const podList = `{"items": [
+	{"metadata": {"name": "cs-claudesquad-fix-0a1b2c3d", "annotations": {"claude-squad/session": "claudesquad_fix"}, "creationTimestamp": "2026-10-16T09:00:00Z"}, "status": {"phase": "Running"}},
+	{"metadata": {"name": "cs-claudesquad-old-4e5f6a7b", "annotations": {"claude-squad/session": "claudesquad_old"}}, "status": {"phase": "Succeeded"}},
+	{"metadata": {"name": "unrelated"}, "status": {"phase": "Running"}}
+]}`
+
+// fakeKubectl answers kubectl like a cluster without the session's pod until it is run,
+// recording every command
+func fakeKubectl(commands *[][]string) *executor.MockExecutor {
+	created := false
+	return &executor.MockExecutor{
+		ExecuteFunc: func(ctx context.Context, cmd executor.Command) (*executor.Result, error) {
+			*commands = append(*commands, append([]string{cmd.Program}, cmd.Args...))
+			script := cmd.Args[len(cmd.Args)-1]
+			switch {
+			case slices.Contains(cmd.Args, "get"):
+				return &executor.Result{Stdout: []byte(podList)}, nil
+			case slices.Contains(cmd.Args, "run"):
+				created = true
+			case strings.Contains(script, "has-session"):
+				if !created {
+					return &executor.Result{ExitCode: 1, Stderr: []byte("error: pod not found")}, nil
+				}
+				return &executor.Result{ExitCode: 1, Stderr: []byte("can't find session")}, nil
+			case strings.Contains(script, "tmux ls"):
+				return &executor.Result{Stdout: []byte("claudesquad_fix:1:1760605200:0:80:24:/src\n")}, nil
+			}
+			return &executor.Result{}, nil
+		},
+	}
+}
+
+func TestPodName(t *testing.T) {
+	name := podName("Fix the_Bug.now")
+	assert.Regexp(t, `^cs-claudesquad-fixthe-bug-now-[0-9a-f]{8}$`, name)
+	assert.Equal(t, name, podName("claudesquad_Fix the_Bug.now"))
+	assert.NotEqual(t, name, podName("fix-the-bug-now"))
+	assert.LessOrEqual(t, len(podName(strings.Repeat("x", 200))), 63)
+}
+
+func TestKubernetesSessions(t *testing.T) {
+	var commands [][]string
+	svc, err := NewKubernetesService(fakeKubectl(&commands), KubernetesOptions{Namespace: "agents", Image: "agent:1", WorkDir: "/src"})
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	sessions, err := svc.ListSessions(ctx)
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	assert.Equal(t, &Session{Name: "claudesquad_fix", ID: "cs-claudesquad-fix-0a1b2c3d", Windows: 1, Created: "2026-10-16T09:00:00Z"}, sessions[0])
+
+	commands = nil
+	session, err := svc.CreateSession(ctx, "fix", "/home/me/worktree", "claude")
+	require.NoError(t, err)
+	assert.Equal(t, "claudesquad_fix", session.Name)
+
+	pod := podName("fix")
+	var run, wait, newSession []string
+	for _, c := range commands {
+		switch c[3] {
+		case "run":
+			run = c
+		case "wait":
+			wait = c
+		case "exec":
+			if strings.Contains(c[len(c)-1], "new-session") {
+				newSession = c
+			}
+		}
+	}
+	assert.Equal(t, []string{"kubectl", "--namespace", "agents", "run", pod, "--image", "agent:1", "--restart", "Never",
+		"--labels", podManagedByLabel, "--annotations", "claude-squad/session=claudesquad_fix",
+		"--command", "--", "sleep", "infinity"}, run)
+	assert.Contains(t, wait, "pod/"+pod)
+	require.NotNil(t, newSession)
+	assert.Contains(t, newSession, pod)
+	assert.Equal(t, "exec tmux new-session -d -s claudesquad_fix -c /src claude", newSession[len(newSession)-1])
+
+	commands = nil
+	require.NoError(t, svc.KillSession(ctx, "claudesquad_fix"))
+	assert.Equal(t, [][]string{{"kubectl", "--namespace", "agents", "delete", "pod", pod, "--ignore-not-found", "--wait=false"}}, commands)
+
+	_, err = NewKubernetesService(fakeKubectl(&commands), KubernetesOptions{})
+	assert.Error(t, err)
+}
@@ -36,6 +36,7 @@ type MockTmuxService struct {
 	// I/O mocks
 	SendKeysFunc         func(ctx context.Context, sessionName string, keys string) error
 	SendKeysToPaneFunc   func(ctx context.Context, sessionName, paneID, keys string) error
+	SendTextFunc         func(ctx context.Context, sessionName string, text string) error
 	CapturePaneFunc      func(ctx context.Context, sessionName, paneID string) (string, error)
 	GetPaneOutputFunc    func(ctx context.Context, sessionName, paneID string, lines int) (string, error)
 	GetPaneScrollbackFunc func(ctx context.Context, sessionName, paneID string) (string, error)
@@ -257,6 +258,14 @@ func (m *MockTmuxService) SendKeys(ctx context.Context, sessionName string, keys
 	return nil
 }
 
+func (m *MockTmuxService) SendText(ctx context.Context, sessionName string, text string) error {
+	if m.SendTextFunc != nil {
+		return m.SendTextFunc(ctx, sessionName, text)
+	}
+	m.Output[sessionName] += text
+	return nil
+}
+
 func (m *MockTmuxService) SendKeysToPane(ctx context.Context, sessionName, paneID, keys string) error {
 	if m.SendKeysToPaneFunc != nil {
 		return m.SendKeysToPaneFunc(ctx, sessionName, paneID, keys)
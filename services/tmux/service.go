@@ -65,6 +65,7 @@ type TmuxService interface {
 	// Input/Output operations
 	SendKeys(ctx context.Context, sessionName string, keys string) error
 	SendKeysToPane(ctx context.Context, sessionName, paneID, keys string) error
+	SendText(ctx context.Context, sessionName string, text string) error
 	CapturePane(ctx context.Context, sessionName, paneID string) (string, error)
 	GetPaneOutput(ctx context.Context, sessionName, paneID string, lines int) (string, error)
 	GetPaneScrollback(ctx context.Context, sessionName, paneID string) (string, error)
@@ -83,4 +84,11 @@ type TmuxService interface {
 	// Cleanup operations
 	CleanupSessions(ctx context.Context, prefix string) error
 	CleanupOrphanedSessions(ctx context.Context) error
-}
\ No newline at end of file
+}
+// ProgramOf returns the program a TmuxService drives, for checking that it is installed
+func ProgramOf(svc TmuxService) string {
+	if p, ok := svc.(interface{ Program() string }); ok {
+		return p.Program()
+	}
+	return "tmux"
+}
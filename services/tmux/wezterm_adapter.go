@@ -0,0 +1,637 @@
+package tmux
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"claude-squad/services/executor"
+)
+
+// errUnsupported is returned for operations WezTerm has no equivalent of
+var errUnsupported = errors.New("not supported by the wezterm multiplexer")
+
+// scrollbackStart is the line get-text starts from to capture all of the scrollback
+const scrollbackStart = -1000000
+
+// wezTermPane is a pane as listed by `wezterm cli list --format json`
+type wezTermPane struct {
+	WindowID  int    `json:"window_id"`
+	TabID     int    `json:"tab_id"`
+	PaneID    int    `json:"pane_id"`
+	Workspace string `json:"workspace"`
+	Size      struct {
+		Rows int `json:"rows"`
+		Cols int `json:"cols"`
+	} `json:"size"`
+	Title    string `json:"title"`
+	TabTitle string `json:"tab_title"`
+	CWD      string `json:"cwd"`
+	IsActive bool   `json:"is_active"`
+}
+
+// wezTermService implements TmuxService on WezTerm's multiplexer, which unlike tmux runs
+// natively on Windows. Each session is a WezTerm workspace, its windows are tabs and its
+// panes are panes. The mux server is started when no WezTerm is running, so sessions
+// outlive the GUI like tmux sessions outlive their clients.
+type wezTermService struct {
+	executor executor.CommandExecutor
+}
+
+// NewWezTermService creates a TmuxService backed by `wezterm cli`
+func NewWezTermService(exec executor.CommandExecutor) TmuxService {
+	return &wezTermService{executor: exec}
+}
+
+// Program returns the program sessions run in
+func (s *wezTermService) Program() string {
+	return "wezterm"
+}
+
+// runCLI executes a `wezterm cli` command
+func (s *wezTermService) runCLI(ctx context.Context, args ...string) (string, error) {
+	result, err := s.executor.Execute(ctx, executor.Command{
+		Program: "wezterm",
+		Args:    append([]string{"cli"}, args...),
+		Timeout: 10 * time.Second,
+	})
+	if err != nil {
+		return "", fmt.Errorf("wezterm command failed: %w", err)
+	}
+	if result.ExitCode != 0 {
+		return "", fmt.Errorf("wezterm command failed with exit code %d: %s", result.ExitCode, string(result.Stderr))
+	}
+	return string(result.Stdout), nil
+}
+
+// ensureServer starts the mux server unless WezTerm is already running
+func (s *wezTermService) ensureServer(ctx context.Context) error {
+	if _, err := s.runCLI(ctx, "list"); err == nil {
+		return nil
+	}
+	result, err := s.executor.Execute(ctx, executor.Command{
+		Program: "wezterm-mux-server",
+		Args:    []string{"--daemonize"},
+		Timeout: 10 * time.Second,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to start wezterm mux server: %w", err)
+	}
+	if result.ExitCode != 0 {
+		return fmt.Errorf("failed to start wezterm mux server: %s", string(result.Stderr))
+	}
+	return nil
+}
+
+// panes lists every pane. No panes are listed when WezTerm isn't running.
+func (s *wezTermService) panes(ctx context.Context) ([]wezTermPane, error) {
+	output, err := s.runCLI(ctx, "list", "--format", "json")
+	if err != nil {
+		return nil, nil
+	}
+	var panes []wezTermPane
+	if err := json.Unmarshal([]byte(output), &panes); err != nil {
+		return nil, fmt.Errorf("failed to parse wezterm pane list: %w", err)
+	}
+	return panes, nil
+}
+
+// sessionPanes lists the panes of a session, failing if it has none
+func (s *wezTermService) sessionPanes(ctx context.Context, sessionName string) ([]wezTermPane, error) {
+	name := workspaceName(sessionName)
+	panes, err := s.panes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var matched []wezTermPane
+	for _, p := range panes {
+		if p.Workspace == name {
+			matched = append(matched, p)
+		}
+	}
+	if len(matched) == 0 {
+		return nil, fmt.Errorf("session not found: %s", name)
+	}
+	return matched, nil
+}
+
+// pane resolves paneID to a WezTerm pane ID within the session. IDs that aren't one of
+// its panes, like the "0" tmux callers use for the first pane, select its first pane.
+func (s *wezTermService) pane(ctx context.Context, sessionName, paneID string) (string, error) {
+	panes, err := s.sessionPanes(ctx, sessionName)
+	if err != nil {
+		return "", err
+	}
+	for _, p := range panes {
+		if strconv.Itoa(p.PaneID) == paneID {
+			return paneID, nil
+		}
+	}
+	return strconv.Itoa(panes[0].PaneID), nil
+}
+
+// workspaceName returns the workspace of a session, whose name callers pass both with
+// and without the prefix
+func workspaceName(name string) string {
+	return SessionName(strings.TrimPrefix(name, tmuxPrefix))
+}
+
+// shellArgs returns the arguments that run a shell command line
+func shellArgs(command string) []string {
+	if runtime.GOOS == "windows" {
+		return []string{"cmd.exe", "/c", command}
+	}
+	return []string{"sh", "-c", command}
+}
+
+// cwdPath converts the file URL WezTerm reports a pane's directory as to a path
+func cwdPath(cwd string) string {
+	u, err := url.Parse(cwd)
+	if err != nil || u.Scheme != "file" {
+		return cwd
+	}
+	if runtime.GOOS == "windows" {
+		return strings.TrimPrefix(u.Path, "/")
+	}
+	return u.Path
+}
+
+// Session management
+
+func (s *wezTermService) CreateSession(ctx context.Context, name, startDir, command string) (*Session, error) {
+	sanitizedName := workspaceName(name)
+	if exists, _ := s.SessionExists(ctx, sanitizedName); exists {
+		return nil, fmt.Errorf("session already exists: %s", sanitizedName)
+	}
+	if err := s.ensureServer(ctx); err != nil {
+		return nil, fmt.Errorf("failed to create session: %w", err)
+	}
+
+	args := []string{"spawn", "--new-window", "--workspace", sanitizedName}
+	if startDir != "" {
+		args = append(args, "--cwd", startDir)
+	}
+	if command != "" {
+		args = append(append(args, "--"), shellArgs(command)...)
+	}
+	if _, err := s.runCLI(ctx, args...); err != nil {
+		return nil, fmt.Errorf("failed to create session: %w", err)
+	}
+
+	return s.GetSession(ctx, sanitizedName)
+}
+
+// AttachSession opens a WezTerm window on the session and waits for it to be closed,
+// which detaches
+func (s *wezTermService) AttachSession(ctx context.Context, sessionName string) error {
+	sanitizedName := workspaceName(sessionName)
+	if exists, _ := s.SessionExists(ctx, sanitizedName); !exists {
+		return fmt.Errorf("session does not exist: %s", sanitizedName)
+	}
+
+	handle, err := s.executor.Start(ctx, executor.Command{
+		Program: "wezterm",
+		Args:    []string{"connect", "--workspace", sanitizedName, "unix"},
+		Stdout:  os.Stdout,
+		Stderr:  os.Stderr,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to attach session: %w", err)
+	}
+	result, err := s.executor.Wait(ctx, handle)
+	if err != nil {
+		return fmt.Errorf("failed to attach session: %w", err)
+	}
+	if result.Error != nil {
+		return fmt.Errorf("failed to attach session: %w", result.Error)
+	}
+	return nil
+}
+
+// DetachSession does nothing: closing the window opened by AttachSession detaches
+func (s *wezTermService) DetachSession(ctx context.Context, sessionName string) error {
+	return nil
+}
+
+func (s *wezTermService) KillSession(ctx context.Context, sessionName string) error {
+	panes, err := s.sessionPanes(ctx, sessionName)
+	if err != nil {
+		// Session might not exist, which is ok
+		return nil
+	}
+	for _, p := range panes {
+		if _, err := s.runCLI(ctx, "kill-pane", "--pane-id", strconv.Itoa(p.PaneID)); err != nil {
+			return fmt.Errorf("failed to kill session: %w", err)
+		}
+	}
+	return nil
+}
+
+func (s *wezTermService) ListSessions(ctx context.Context) ([]*Session, error) {
+	panes, err := s.panes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	sessions := []*Session{}
+	byName := map[string]*Session{}
+	tabs := map[string]map[int]bool{}
+	for _, p := range panes {
+		session, ok := byName[p.Workspace]
+		if !ok {
+			// The first pane listed stands for the session's size and directory
+			session = &Session{
+				Name:      p.Workspace,
+				ID:        p.Workspace,
+				Width:     p.Size.Cols,
+				Height:    p.Size.Rows,
+				Directory: cwdPath(p.CWD),
+			}
+			byName[p.Workspace] = session
+			tabs[p.Workspace] = map[int]bool{}
+			sessions = append(sessions, session)
+		}
+		if !tabs[p.Workspace][p.TabID] {
+			tabs[p.Workspace][p.TabID] = true
+			session.Windows++
+		}
+	}
+	return sessions, nil
+}
+
+func (s *wezTermService) GetSession(ctx context.Context, sessionName string) (*Session, error) {
+	sanitizedName := workspaceName(sessionName)
+
+	sessions, err := s.ListSessions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, session := range sessions {
+		if session.Name == sanitizedName {
+			return session, nil
+		}
+	}
+	return nil, fmt.Errorf("session not found: %s", sanitizedName)
+}
+
+func (s *wezTermService) RenameSession(ctx context.Context, oldName, newName string) error {
+	if _, err := s.runCLI(ctx, "rename-workspace", "--workspace", workspaceName(oldName), workspaceName(newName)); err != nil {
+		return fmt.Errorf("failed to rename session: %w", err)
+	}
+	return nil
+}
+
+func (s *wezTermService) SessionExists(ctx context.Context, sessionName string) (bool, error) {
+	if _, err := s.sessionPanes(ctx, sessionName); err != nil {
+		return false, nil
+	}
+	return true, nil
+}
+
+// Window management, with windows being tabs
+
+func (s *wezTermService) CreateWindow(ctx context.Context, sessionName, windowName, command string) (*Window, error) {
+	paneID, err := s.pane(ctx, sessionName, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create window: %w", err)
+	}
+
+	args := []string{"spawn", "--pane-id", paneID}
+	if command != "" {
+		args = append(append(args, "--"), shellArgs(command)...)
+	}
+	output, err := s.runCLI(ctx, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create window: %w", err)
+	}
+
+	newPane := strings.TrimSpace(output)
+	panes, err := s.sessionPanes(ctx, sessionName)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range panes {
+		if strconv.Itoa(p.PaneID) != newPane {
+			continue
+		}
+		tabID := strconv.Itoa(p.TabID)
+		if windowName != "" {
+			if err := s.RenameWindow(ctx, sessionName, tabID, windowName); err != nil {
+				return nil, err
+			}
+		}
+		return &Window{ID: tabID, Name: windowName, Active: p.IsActive, Panes: 1}, nil
+	}
+	return nil, fmt.Errorf("window not found after creation")
+}
+
+func (s *wezTermService) KillWindow(ctx context.Context, sessionName, windowID string) error {
+	panes, err := s.sessionPanes(ctx, sessionName)
+	if err != nil {
+		return fmt.Errorf("failed to kill window: %w", err)
+	}
+	for _, p := range panes {
+		if strconv.Itoa(p.TabID) != windowID {
+			continue
+		}
+		if _, err := s.runCLI(ctx, "kill-pane", "--pane-id", strconv.Itoa(p.PaneID)); err != nil {
+			return fmt.Errorf("failed to kill window: %w", err)
+		}
+	}
+	return nil
+}
+
+func (s *wezTermService) ListWindows(ctx context.Context, sessionName string) ([]*Window, error) {
+	panes, err := s.sessionPanes(ctx, sessionName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list windows: %w", err)
+	}
+
+	var windows []*Window
+	byID := map[int]*Window{}
+	for _, p := range panes {
+		window, ok := byID[p.TabID]
+		if !ok {
+			window = &Window{ID: strconv.Itoa(p.TabID), Name: p.TabTitle}
+			byID[p.TabID] = window
+			windows = append(windows, window)
+		}
+		window.Panes++
+		window.Active = window.Active || p.IsActive
+	}
+	return windows, nil
+}
+
+func (s *wezTermService) RenameWindow(ctx context.Context, sessionName, windowID, newName string) error {
+	if _, err := s.runCLI(ctx, "set-tab-title", "--tab-id", windowID, newName); err != nil {
+		return fmt.Errorf("failed to rename window: %w", err)
+	}
+	return nil
+}
+
+func (s *wezTermService) SelectWindow(ctx context.Context, sessionName, windowID string) error {
+	if _, err := s.runCLI(ctx, "activate-tab", "--tab-id", windowID); err != nil {
+		return fmt.Errorf("failed to select window: %w", err)
+	}
+	return nil
+}
+
+// Pane management
+
+func (s *wezTermService) SplitPane(ctx context.Context, sessionName, windowID string, vertical bool, command string) (*Pane, error) {
+	panes, err := s.sessionPanes(ctx, sessionName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to split pane: %w", err)
+	}
+	target := panes[0]
+	for _, p := range panes {
+		if strconv.Itoa(p.TabID) == windowID {
+			target = p
+			break
+		}
+	}
+
+	// Like tmux, a vertical split stacks the panes
+	args := []string{"split-pane", "--pane-id", strconv.Itoa(target.PaneID), "--right"}
+	if vertical {
+		args[len(args)-1] = "--bottom"
+	}
+	if command != "" {
+		args = append(append(args, "--"), shellArgs(command)...)
+	}
+	output, err := s.runCLI(ctx, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to split pane: %w", err)
+	}
+	return &Pane{ID: strings.TrimSpace(output), Command: command}, nil
+}
+
+func (s *wezTermService) KillPane(ctx context.Context, sessionName, paneID string) error {
+	if _, err := s.runCLI(ctx, "kill-pane", "--pane-id", paneID); err != nil {
+		return fmt.Errorf("failed to kill pane: %w", err)
+	}
+	return nil
+}
+
+func (s *wezTermService) ListPanes(ctx context.Context, sessionName, windowID string) ([]*Pane, error) {
+	panes, err := s.sessionPanes(ctx, sessionName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list panes: %w", err)
+	}
+
+	var result []*Pane
+	for _, p := range panes {
+		if strconv.Itoa(p.TabID) != windowID {
+			continue
+		}
+		result = append(result, &Pane{
+			ID:        strconv.Itoa(p.PaneID),
+			Active:    p.IsActive,
+			Width:     p.Size.Cols,
+			Height:    p.Size.Rows,
+			Command:   p.Title,
+			Directory: cwdPath(p.CWD),
+		})
+	}
+	return result, nil
+}
+
+// ResizePane isn't supported, WezTerm only resizes panes relative to their current size
+func (s *wezTermService) ResizePane(ctx context.Context, sessionName, paneID string, width, height int) error {
+	return fmt.Errorf("failed to resize pane: %w", errUnsupported)
+}
+
+func (s *wezTermService) SelectPane(ctx context.Context, sessionName, paneID string) error {
+	if _, err := s.runCLI(ctx, "activate-pane", "--pane-id", paneID); err != nil {
+		return fmt.Errorf("failed to select pane: %w", err)
+	}
+	return nil
+}
+
+// Input/Output operations
+
+// wezTermKeys are the tmux key names SendKeys understands, and what they send
+var wezTermKeys = map[string]string{
+	"Enter":  "\r",
+	"Escape": "\x1b",
+	"Tab":    "\t",
+	"BSpace": "\x7f",
+	"Space":  " ",
+	"Up":     "\x1b[A",
+	"Down":   "\x1b[B",
+	"Right":  "\x1b[C",
+	"Left":   "\x1b[D",
+}
+
+var ctrlKeyRegex = regexp.MustCompile(`^C-([a-z])$`)
+
+// keySequences converts keys the way tmux send-keys does: each space separated key name,
+// like Enter or C-c, becomes what the key sends. Anything else is sent as typed.
+func keySequences(keys string) string {
+	words := strings.Split(keys, " ")
+	for _, word := range words {
+		if _, ok := wezTermKeys[word]; !ok && !ctrlKeyRegex.MatchString(word) {
+			return keys
+		}
+	}
+
+	var b strings.Builder
+	for _, word := range words {
+		if seq, ok := wezTermKeys[word]; ok {
+			b.WriteString(seq)
+		} else {
+			b.WriteByte(ctrlKeyRegex.FindStringSubmatch(word)[1][0] - 'a' + 1)
+		}
+	}
+	return b.String()
+}
+
+func (s *wezTermService) sendText(ctx context.Context, sessionName, paneID, text string) error {
+	target, err := s.pane(ctx, sessionName, paneID)
+	if err != nil {
+		return err
+	}
+	_, err = s.runCLI(ctx, "send-text", "--no-paste", "--pane-id", target, "--", text)
+	return err
+}
+
+func (s *wezTermService) SendKeys(ctx context.Context, sessionName string, keys string) error {
+	if err := s.sendText(ctx, sessionName, "", keySequences(keys)); err != nil {
+		return fmt.Errorf("failed to send keys: %w", err)
+	}
+	return nil
+}
+
+func (s *wezTermService) SendKeysToPane(ctx context.Context, sessionName, paneID, keys string) error {
+	if err := s.sendText(ctx, sessionName, paneID, keySequences(keys)); err != nil {
+		return fmt.Errorf("failed to send keys to pane: %w", err)
+	}
+	return nil
+}
+
+// SendText types text into the session literally, without interpreting key names
+func (s *wezTermService) SendText(ctx context.Context, sessionName string, text string) error {
+	if err := s.sendText(ctx, sessionName, "", text); err != nil {
+		return fmt.Errorf("failed to send text: %w", err)
+	}
+	return nil
+}
+
+// getText returns the text of a pane, starting startLine lines into the scrollback
+func (s *wezTermService) getText(ctx context.Context, sessionName, paneID string, startLine int) (string, error) {
+	target, err := s.pane(ctx, sessionName, paneID)
+	if err != nil {
+		return "", err
+	}
+	args := []string{"get-text", "--pane-id", target}
+	if startLine < 0 {
+		args = append(args, "--start-line", strconv.Itoa(startLine))
+	}
+	return s.runCLI(ctx, args...)
+}
+
+func (s *wezTermService) CapturePane(ctx context.Context, sessionName, paneID string) (string, error) {
+	output, err := s.getText(ctx, sessionName, paneID, 0)
+	if err != nil {
+		return "", fmt.Errorf("failed to capture pane: %w", err)
+	}
+	return output, nil
+}
+
+func (s *wezTermService) GetPaneOutput(ctx context.Context, sessionName, paneID string, lines int) (string, error) {
+	output, err := s.getText(ctx, sessionName, paneID, -lines)
+	if err != nil {
+		return "", fmt.Errorf("failed to get pane output: %w", err)
+	}
+	return output, nil
+}
+
+func (s *wezTermService) GetPaneScrollback(ctx context.Context, sessionName, paneID string) (string, error) {
+	output, err := s.getText(ctx, sessionName, paneID, scrollbackStart)
+	if err != nil {
+		return "", fmt.Errorf("failed to get pane scrollback: %w", err)
+	}
+	return output, nil
+}
+
+// Streaming operations
+
+func (s *wezTermService) StreamOutput(ctx context.Context, sessionName string) (io.ReadCloser, error) {
+	return s.StreamPaneOutput(ctx, sessionName, "")
+}
+
+func (s *wezTermService) StreamPaneOutput(ctx context.Context, sessionName, paneID string) (io.ReadCloser, error) {
+	pr, pw := io.Pipe()
+
+	// Poll the pane, as WezTerm has no way to follow its output
+	go func() {
+		defer pw.Close()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			default:
+				output, err := s.CapturePane(ctx, sessionName, paneID)
+				if err != nil {
+					pw.CloseWithError(err)
+					return
+				}
+				pw.Write([]byte(output))
+				time.Sleep(100 * time.Millisecond)
+			}
+		}
+	}()
+
+	return pr, nil
+}
+
+// Configuration and utilities, which have no WezTerm equivalents
+
+func (s *wezTermService) SetOption(ctx context.Context, sessionName, option, value string) error {
+	return fmt.Errorf("failed to set option: %w", errUnsupported)
+}
+
+func (s *wezTermService) GetOption(ctx context.Context, sessionName, option string) (string, error) {
+	return "", fmt.Errorf("failed to get option: %w", errUnsupported)
+}
+
+func (s *wezTermService) ResizeSession(ctx context.Context, sessionName string, width, height int) error {
+	return fmt.Errorf("failed to resize session: %w", errUnsupported)
+}
+
+func (s *wezTermService) HasActivity(ctx context.Context, sessionName string) (bool, error) {
+	return false, fmt.Errorf("failed to check activity: %w", errUnsupported)
+}
+
+func (s *wezTermService) GetSessionPID(ctx context.Context, sessionName string) (int, error) {
+	return 0, fmt.Errorf("failed to get session PID: %w", errUnsupported)
+}
+
+// Cleanup operations
+
+func (s *wezTermService) CleanupSessions(ctx context.Context, prefix string) error {
+	sessions, err := s.ListSessions(ctx)
+	if err != nil {
+		return err
+	}
+	for _, session := range sessions {
+		if strings.HasPrefix(session.Name, prefix) {
+			_ = s.KillSession(ctx, session.Name)
+		}
+	}
+	return nil
+}
+
+func (s *wezTermService) CleanupOrphanedSessions(ctx context.Context) error {
+	// Kill all sessions with the claudesquad prefix
+	return s.CleanupSessions(ctx, tmuxPrefix)
+}
@@ -0,0 +1,79 @@
+package tmux
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"claude-squad/services/executor"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const paneList = `[
+	{"window_id": 0, "tab_id": 0, "pane_id": 0, "workspace": "default", "size": {"rows": 24, "cols": 80}, "cwd": "file://host/home/me"},
+	{"window_id": 1, "tab_id": 1, "pane_id": 3, "workspace": "claudesquad_fix", "size": {"rows": 40, "cols": 120}, "cwd": "file://host/work/fix", "is_active": true},
+	{"window_id": 1, "tab_id": 1, "pane_id": 4, "workspace": "claudesquad_fix", "size": {"rows": 40, "cols": 60}, "cwd": "file://host/work/fix"},
+	{"window_id": 1, "tab_id": 2, "pane_id": 5, "workspace": "claudesquad_fix", "size": {"rows": 40, "cols": 120}, "cwd": "file://host/work/fix"}
+]`
+
+// fakeWezTerm answers `wezterm cli list` with paneList and records every other command
+func fakeWezTerm(commands *[][]string) *executor.MockExecutor {
+	return &executor.MockExecutor{
+		ExecuteFunc: func(ctx context.Context, cmd executor.Command) (*executor.Result, error) {
+			if cmd.Program == "wezterm" && len(cmd.Args) > 1 && cmd.Args[1] == "list" {
+				return &executor.Result{Stdout: []byte(paneList)}, nil
+			}
+			*commands = append(*commands, append([]string{cmd.Program}, cmd.Args...))
+			return &executor.Result{}, nil
+		},
+	}
+}
+
+func TestWezTermSessions(t *testing.T) {
+	var commands [][]string
+	svc := NewWezTermService(fakeWezTerm(&commands))
+	ctx := context.Background()
+
+	sessions, err := svc.ListSessions(ctx)
+	require.NoError(t, err)
+	require.Len(t, sessions, 2)
+	assert.Equal(t, &Session{Name: "claudesquad_fix", ID: "claudesquad_fix", Windows: 2, Width: 120, Height: 40, Directory: "/work/fix"}, sessions[1])
+
+	// Names are accepted with and without the prefix
+	for _, name := range []string{"fix", "claudesquad_fix"} {
+		exists, err := svc.SessionExists(ctx, name)
+		require.NoError(t, err)
+		assert.True(t, exists)
+	}
+	exists, err := svc.SessionExists(ctx, "other")
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	require.NoError(t, svc.KillSession(ctx, "fix"))
+	assert.Equal(t, [][]string{
+		{"wezterm", "cli", "kill-pane", "--pane-id", "3"},
+		{"wezterm", "cli", "kill-pane", "--pane-id", "4"},
+		{"wezterm", "cli", "kill-pane", "--pane-id", "5"},
+	}, commands)
+}
+
+func TestWezTermSendKeys(t *testing.T) {
+	var commands [][]string
+	svc := NewWezTermService(fakeWezTerm(&commands))
+	ctx := context.Background()
+
+	require.NoError(t, svc.SendKeys(ctx, "fix", "Enter"))
+	require.NoError(t, svc.SendKeys(ctx, "fix", "C-c"))
+	require.NoError(t, svc.SendKeys(ctx, "fix", "fix the Enter key"))
+	// tmux's "0" for the first pane resolves to the session's first WezTerm pane
+	require.NoError(t, svc.SendKeysToPane(ctx, "fix", "0", "Escape"))
+
+	var sent []string
+	for _, cmd := range commands {
+		assert.Equal(t, "3", cmd[5], strings.Join(cmd, " "))
+		sent = append(sent, cmd[len(cmd)-1])
+	}
+	assert.Equal(t, []string{"\r", "\x03", "fix the Enter key", "\x1b"}, sent)
+}
@@ -17,6 +17,7 @@ type Session struct {
 	ID        string
 	Title     string
 	Path      string
+	RepoPath  string // repository the worktree was created from
 	Branch    string
 	Status    Status
 	Program   string
@@ -26,6 +27,103 @@ type Session struct {
 	UpdatedAt time.Time
 	AutoYes   bool
 	Prompt    string
+	// Inputs is every prompt and keystroke sequence sent to the session, oldest first
+	Inputs []InputRecord
+	// Commands is every command run in the session's worktree, oldest first
+	Commands []CommandRecord
+	// DiffHistory is the size of the worktree's uncommitted changes over time, oldest
+	// first
+	DiffHistory []DiffSample
+	// Archived sessions are paused sessions hidden from the default list
+	Archived   bool
+	ArchivedAt time.Time
+	// DiffSnapshot holds the uncommitted changes the worktree had when it was archived or
+	// deleted
+	DiffSnapshot string
+	// DeletedAt is set while a deleted session is in the trash, where it can be restored
+	DeletedAt time.Time
+	// Tags group sessions, e.g. by project, epic or priority
+	Tags []string
+	// Conflicted is set when the session's branch was last found to conflict with its base
+	// branch, so it can no longer be merged cleanly
+	Conflicted bool
+	// Submodules is set when the session's worktree has its submodules initialized each
+	// time it is created
+	Submodules bool
+	// CommitSettings override, for this session, how its commits are made; nil for none
+	CommitSettings *CommitSettings
+	// InPlace is set for sessions that work in an existing checkout instead of a worktree
+	// of their own, with Path the directory
+	InPlace *InPlace
+}
+
+// InPlace records how to give an in-place session's checkout back as it was found once
+// the session is deleted
+type InPlace struct {
+	// RestoreBranch is the branch to check out again, if the session switched branches
+	RestoreBranch string `json:"restore_branch,omitempty"`
+	// Stash is the stash commit holding the changes the checkout had before the session
+	Stash string `json:"stash,omitempty"`
+}
+
+// CommitSettings control how commits are made for sessions. Unset fields leave it to the
+// settings below them: a session's to the global ones, and those to git's defaults.
+type CommitSettings struct {
+	// RunHooks runs the repository's pre-commit and commit-msg hooks, skipped by default
+	RunHooks *bool `json:"run_hooks,omitempty"`
+	// Sign signs commits with SigningKey, or git's user.signingkey when empty
+	Sign       *bool  `json:"sign,omitempty"`
+	SigningKey string `json:"signing_key,omitempty"`
+	// SigningFormat is "openpgp", "ssh" or "x509"
+	SigningFormat string `json:"signing_format,omitempty"`
+	// Author is who commits are authored by, as "Name <email>"
+	Author string `json:"author,omitempty"`
+}
+
+// BranchTracking counts the commits a session's branch and its base branch don't share
+type BranchTracking struct {
+	Base string `json:"base"`
+	// Ahead is the number of commits on the session's branch that aren't on Base
+	Ahead int `json:"ahead"`
+	// Behind is the number of commits on Base that aren't on the session's branch
+	Behind int `json:"behind"`
+}
+
+// InputKind distinguishes submitted prompts from raw keystrokes
+type InputKind string
+
+const (
+	InputPrompt InputKind = "prompt"
+	InputKeys   InputKind = "keys"
+)
+
+// InputRecord is one piece of input sent to a session
+type InputRecord struct {
+	Time time.Time `json:"time"`
+	Kind InputKind `json:"kind"`
+	Text string    `json:"text"`
+}
+
+// CommandRecord is one command run in a session's worktree
+type CommandRecord struct {
+	Time    time.Time `json:"time"`
+	Program string    `json:"program"`
+	Args    []string  `json:"args,omitempty"`
+	// Shell is set when Program is a command line run with the user's shell
+	Shell    bool `json:"shell,omitempty"`
+	ExitCode int  `json:"exit_code"`
+}
+
+// DiffSample is the size of a session's uncommitted changes at one point in time
+type DiffSample struct {
+	Time time.Time `json:"time"`
+	// Head is the commit the changes were made on
+	Head    string `json:"head,omitempty"`
+	Added   int    `json:"added"`
+	Removed int    `json:"removed"`
+	Files   int    `json:"files"`
+	// Patch is the changes themselves, kept for the latest samples when recording patches
+	Patch string `json:"patch,omitempty"`
 }
 
 // CreateSessionRequest contains parameters for creating a new session
@@ -38,6 +136,217 @@ type CreateSessionRequest struct {
 	Width   int
 	AutoYes bool
 	Prompt  string
+
+	// BaseRef is the branch or commit a new Branch starts from; defaults to HEAD
+	BaseRef string
+	// ReuseBranch uses Branch as-is when it already exists instead of failing
+	ReuseBranch bool
+	// Submodules initializes the worktree's submodules; nil leaves it to the orchestrator
+	Submodules *bool
+	// ForkBranch starts a new branch from Branch, or the current branch, when another
+	// session already works on it, instead of failing
+	ForkBranch bool
+	// InPlace runs the session in the directory at Path instead of a new worktree, for
+	// repositories too big or too tied to their location for worktrees. Branch, if set, is
+	// checked out there.
+	InPlace bool
+	// Stash sets the uncommitted changes of an in-place session's directory aside while the
+	// session works there
+	Stash bool
+}
+
+// SessionOverlap is a group of sessions working on the same branch of a repository, or in
+// the same worktree, where their agents overwrite each other's work
+type SessionOverlap struct {
+	// RepoPath and Branch are set for sessions sharing a branch
+	RepoPath string
+	Branch   string
+	// Path is set for sessions sharing a worktree
+	Path       string
+	SessionIDs []string
+}
+
+// CloneSessionRequest contains parameters for duplicating an existing session
+type CloneSessionRequest struct {
+	SourceID string
+	Title    string
+	// Branch is the fresh branch for the clone; derived from Title when empty
+	Branch string
+	// FromSourceBranch starts the new branch from the source session's branch instead of HEAD
+	FromSourceBranch bool
+}
+
+// PushResult describes where a session's branch was pushed
+type PushResult struct {
+	Branch    string
+	Remote    string
+	RemoteURL string
+	// BranchURL is the branch's web page, if the remote is a hosted repository
+	BranchURL string
+}
+
+// PullRequestRequest describes a pull request to open from a session's branch. Empty
+// fields are filled in from the session and its commits.
+type PullRequestRequest struct {
+	Remote string
+	Base   string
+	Title  string
+	Body   string
+	Draft  bool
+}
+
+// PullRequest is a pull request opened from a session's branch
+type PullRequest struct {
+	URL    string
+	Title  string
+	Branch string
+	Base   string
+}
+
+// MergeStrategy is how a finished session's branch is integrated into its base branch
+type MergeStrategy string
+
+const (
+	// MergeCommit merges the branch with a merge commit
+	MergeCommit MergeStrategy = "merge"
+	// MergeSquash applies the branch's changes as a single commit
+	MergeSquash MergeStrategy = "squash"
+	// MergeRebase replays the branch's commits onto the base branch and fast-forwards it
+	MergeRebase MergeStrategy = "rebase"
+)
+
+// FinishSessionRequest describes how to integrate a session's branch into its base
+// branch. Empty fields have defaults.
+type FinishSessionRequest struct {
+	// Base is the branch merged into; defaults to the one checked out in the repository
+	Base string
+	// Strategy defaults to MergeCommit
+	Strategy MergeStrategy
+	// Message is the message of the merge or squash commit
+	Message string
+	// DeleteBranch deletes the session's branch once it is merged
+	DeleteBranch bool
+	// Tag tags the tip of the session's branch before merging it, so the work stays
+	// findable as it was once the branch is gone. TagName defaults to
+	// "squad/<title>/done".
+	Tag     bool
+	TagName string
+}
+
+// FinishResult describes a session's branch integrated into its base branch
+type FinishResult struct {
+	Branch   string
+	Base     string
+	Strategy MergeStrategy
+	// Head is the commit the base branch is at afterwards
+	Head          string
+	BranchDeleted bool
+	// Tag is the tag created at the tip of the branch, if any
+	Tag string
+}
+
+// TransplantRequest describes which of a session's work to copy where
+type TransplantRequest struct {
+	// TargetID is the session whose branch receives the work; empty for the branch
+	// checked out in the repository itself
+	TargetID string
+	// Commits are the commits, or ranges, to cherry-pick. Empty for every commit on the
+	// session's branch that the target branch doesn't have.
+	Commits []string
+	// Uncommitted applies the session's uncommitted changes instead of its commits
+	Uncommitted bool
+}
+
+// TransplantResult describes work copied from one session onto another branch
+type TransplantResult struct {
+	// Path is the worktree or repository the work was copied into
+	Path   string
+	Branch string
+	// Commits are the hashes of the commits picked from the session's branch, newest
+	// first, when all of them were
+	Commits []string
+	// Patched is set when uncommitted changes were applied
+	Patched bool
+}
+
+// WorktreeMove is a session whose worktree was, or would be, moved to where the worktree
+// layout puts it
+type WorktreeMove struct {
+	SessionID string
+	Title     string
+	From      string
+	To        string
+	// Skipped says why the worktree wasn't moved, when it wasn't
+	Skipped string
+}
+
+// RebaseRequest describes how to rebase a session's branch. Continue and Abort act on a
+// rebase stopped by conflicts instead of starting one.
+type RebaseRequest struct {
+	// Onto is the ref rebased onto; defaults to the default branch of Remote, fetched first
+	Onto string
+	// Remote defaults to "origin"
+	Remote string
+	// Continue continues a stopped rebase with the conflicts resolved in the worktree
+	Continue bool
+	// Abort gives up on a stopped rebase, restoring the branch
+	Abort bool
+}
+
+// RebaseStatus is where a session's rebase ended up
+type RebaseStatus string
+
+const (
+	// RebaseDone means every commit of the branch was replayed
+	RebaseDone RebaseStatus = "rebased"
+	// RebaseConflicted means the rebase stopped on conflicts to be resolved in the worktree
+	RebaseConflicted RebaseStatus = "conflicted"
+	// RebaseAborted means the rebase was given up and the branch restored
+	RebaseAborted RebaseStatus = "aborted"
+)
+
+// RebaseResult describes a session's branch after rebasing it
+type RebaseResult struct {
+	Branch string
+	// Onto is empty when continuing or aborting
+	Onto   string
+	Status RebaseStatus
+	// Head is the commit the branch is at afterwards
+	Head string
+	// Conflicts are the files, relative to the worktree, left to resolve
+	Conflicts []string
+}
+
+// PruneRequest selects sessions to delete by retention policy
+type PruneRequest struct {
+	// OlderThan matches sessions not updated within this duration
+	OlderThan time.Duration
+	// Status, when set, only matches sessions with this status
+	Status *Status
+	// DryRun reports matching sessions without deleting anything
+	DryRun bool
+}
+
+// RetentionPolicy limits how many sessions are kept and for how long. A zero limit is disabled.
+type RetentionPolicy struct {
+	// MaxSessions is the number of sessions kept outside the trash. The least recently
+	// updated paused sessions beyond it are moved to the trash; running ones are never.
+	MaxSessions int
+	// MaxAge moves paused sessions not updated within it to the trash, and purges sessions
+	// that have been in the trash for longer
+	MaxAge time.Duration
+	// ArchiveAfterPaused archives sessions that have been paused for this long
+	ArchiveAfterPaused time.Duration
+}
+
+// RetentionReport lists what enforcing a retention policy did
+type RetentionReport struct {
+	Archived []*Session
+	// Trashed were moved to the trash to meet the session limit, Expired for their age
+	Trashed []*Session
+	Expired []*Session
+	// Purged were permanently deleted from the trash
+	Purged []*Session
 }
 
 // SessionData represents the persistent data of a session (for storage)
@@ -45,6 +354,7 @@ type SessionData struct {
 	ID        string            `json:"id"`
 	Title     string            `json:"title"`
 	Path      string            `json:"path"`
+	RepoPath  string            `json:"repo_path,omitempty"`
 	Branch    string            `json:"branch"`
 	Status    Status            `json:"status"`
 	Program   string            `json:"program"`
@@ -54,5 +364,25 @@ type SessionData struct {
 	UpdatedAt time.Time         `json:"updated_at"`
 	AutoYes   bool              `json:"auto_yes"`
 	Prompt    string            `json:"prompt"`
+	Inputs    []InputRecord     `json:"inputs,omitempty"`
+	Commands  []CommandRecord   `json:"commands,omitempty"`
 	Metadata  map[string]string `json:"metadata,omitempty"`
+
+	DiffHistory []DiffSample `json:"diff_history,omitempty"`
+
+	Archived     bool      `json:"archived,omitempty"`
+	ArchivedAt   time.Time `json:"archived_at,omitempty"`
+	DiffSnapshot string    `json:"diff_snapshot,omitempty"`
+	DeletedAt    time.Time `json:"deleted_at,omitempty"`
+	Tags         []string  `json:"tags,omitempty"`
+	Conflicted   bool      `json:"conflicted,omitempty"`
+
+	Submodules bool `json:"submodules,omitempty"`
+
+	CommitSettings *CommitSettings `json:"commit_settings,omitempty"`
+	InPlace        *InPlace        `json:"in_place,omitempty"`
+
+	// Checksum is a hash of the rest of the record, set by the store when it is saved so
+	// damage to the stored copy can be detected
+	Checksum string `json:"checksum,omitempty"`
 }
\ No newline at end of file
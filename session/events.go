@@ -0,0 +1,160 @@
+package session
+
+import (
+	"bytes"
+	"claude-squad/config"
+	"claude-squad/log"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+	"unicode/utf8"
+)
+
+// EventType is the kind of activity recorded in a session's event log.
+type EventType string
+
+const (
+	// EventPrompt is recorded when a prompt is sent to the agent.
+	EventPrompt EventType = "prompt"
+	// EventOutput is recorded when the agent starts producing output after being idle.
+	EventOutput EventType = "output"
+	// EventAutoYes is recorded when auto-yes answers a prompt on the user's behalf.
+	EventAutoYes EventType = "auto-yes"
+	// EventCommit is recorded when the session's changes are committed.
+	EventCommit EventType = "commit"
+	// EventStatus is recorded on any other status change.
+	EventStatus EventType = "status"
+)
+
+const eventsDirName = "events"
+
+// maxEventDetail caps the length of event details so prompts don't bloat the log.
+const maxEventDetail = 200
+
+// Event is a single entry in a session's activity timeline.
+type Event struct {
+	Time   time.Time `json:"time"`
+	Type   EventType `json:"type"`
+	Detail string    `json:"detail,omitempty"`
+}
+
+// String formats the event as a single timeline line.
+func (e Event) String() string {
+	return fmt.Sprintf("%s  %-8s  %s", e.Time.Format("2006-01-02 15:04:05"), e.Type, e.Detail)
+}
+
+var unsafeFileChars = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+// eventLogPath returns the JSONL event log file for the session with the given ID. Logs
+// are keyed by ID rather than title so a new session reusing a title starts afresh.
+func eventLogPath(id string) (string, error) {
+	configDir, err := config.GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, eventsDirName, unsafeFileChars.ReplaceAllString(id, "_")+".jsonl"), nil
+}
+
+// truncateDetail cuts detail to at most maxEventDetail bytes, backing off to the start of
+// the rune the limit falls in so multi-byte characters aren't split.
+func truncateDetail(detail string) string {
+	if len(detail) <= maxEventDetail {
+		return detail
+	}
+	cut := maxEventDetail
+	for cut > 0 && !utf8.RuneStart(detail[cut]) {
+		cut--
+	}
+	return detail[:cut] + "..."
+}
+
+// RecordEvent appends an event to the log of the session with the given ID. Details are
+// redacted and truncated. Failures are logged rather than returned since the timeline is
+// best-effort.
+func RecordEvent(id string, eventType EventType, detail string) {
+	if id == "" {
+		return
+	}
+	detail = truncateDetail(log.Redact(detail))
+
+	data, err := json.Marshal(Event{Time: time.Now(), Type: eventType, Detail: detail})
+	if err != nil {
+		log.ErrorLog.Printf("failed to marshal event: %v", err)
+		return
+	}
+
+	path, err := eventLogPath(id)
+	if err != nil {
+		log.ErrorLog.Printf("failed to get event log path: %v", err)
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		log.ErrorLog.Printf("failed to create events directory: %v", err)
+		return
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		log.ErrorLog.Printf("failed to open event log: %v", err)
+		return
+	}
+	defer f.Close()
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		log.ErrorLog.Printf("failed to write event: %v", err)
+	}
+}
+
+// LoadEvents reads the event log for the session with the given ID, oldest first.
+// A session with no recorded events returns an empty slice.
+func LoadEvents(id string) ([]Event, error) {
+	events, _, err := ReadEvents(id, 0)
+	return events, err
+}
+
+// ReadEvents reads the events appended to the log of the session with the given ID since
+// offset, and returns the offset to read from next time. An event still being written is
+// left for the next read.
+func ReadEvents(id string, offset int64) ([]Event, int64, error) {
+	path, err := eventLogPath(id)
+	if err != nil {
+		return nil, offset, err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []Event{}, offset, nil
+		}
+		return nil, offset, fmt.Errorf("failed to open event log: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return nil, offset, fmt.Errorf("failed to read event log: %w", err)
+	}
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil, offset, fmt.Errorf("failed to read event log: %w", err)
+	}
+
+	events := []Event{}
+	for {
+		end := bytes.IndexByte(data, '\n')
+		if end < 0 {
+			break
+		}
+		line := data[:end]
+		data = data[end+1:]
+		offset += int64(end + 1)
+
+		var event Event
+		if err := json.Unmarshal(line, &event); err != nil {
+			// Skip lines torn by a concurrent writer rather than losing the whole timeline.
+			continue
+		}
+		events = append(events, event)
+	}
+	return events, offset, nil
+}
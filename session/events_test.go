@@ -0,0 +1,102 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordAndReadEvents(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	events, err := LoadEvents("abc123")
+	require.NoError(t, err)
+	assert.Empty(t, events)
+
+	RecordEvent("abc123", EventPrompt, "fix the tests")
+	RecordEvent("abc123", EventStatus, "ready")
+	events, offset, err := ReadEvents("abc123", 0)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, EventPrompt, events[0].Type)
+	assert.Equal(t, "fix the tests", events[0].Detail)
+	assert.Equal(t, EventStatus, events[1].Type)
+
+	// Reading from the returned offset only picks up what was appended since
+	events, offset, err = ReadEvents("abc123", offset)
+	require.NoError(t, err)
+	assert.Empty(t, events)
+	RecordEvent("abc123", EventCommit, "wip")
+	events, offset, err = ReadEvents("abc123", offset)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, EventCommit, events[0].Type)
+
+	// An event still being written is left for the next read
+	path, err := eventLogPath("abc123")
+	require.NoError(t, err)
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0644)
+	require.NoError(t, err)
+	defer f.Close()
+	_, err = f.WriteString(`{"type":"output"`)
+	require.NoError(t, err)
+	events, next, err := ReadEvents("abc123", offset)
+	require.NoError(t, err)
+	assert.Empty(t, events)
+	assert.Equal(t, offset, next)
+	_, err = f.WriteString("}\n")
+	require.NoError(t, err)
+	events, _, err = ReadEvents("abc123", next)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, EventOutput, events[0].Type)
+}
+
+func TestRecordEventTruncatesOnRuneBoundary(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	// Three-byte runes don't line up with the limit, so a byte cut would split one
+	detail := "a" + strings.Repeat("日", maxEventDetail)
+	RecordEvent("abc123", EventPrompt, detail)
+	events, err := LoadEvents("abc123")
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+
+	got := events[0].Detail
+	assert.True(t, utf8.ValidString(got))
+	assert.True(t, strings.HasSuffix(got, "..."))
+	assert.LessOrEqual(t, len(got), maxEventDetail+len("..."))
+	assert.True(t, strings.HasPrefix(detail, strings.TrimSuffix(got, "...")))
+
+	assert.Equal(t, "short", truncateDetail("short"))
+}
+
+func TestEventsKeyedByID(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	old, err := NewInstance(InstanceOptions{Title: "auth", Path: home})
+	require.NoError(t, err)
+	old.SetStatus(Running)
+
+	// A later session reusing the title starts with its own empty timeline
+	reused, err := NewInstance(InstanceOptions{Title: "auth", Path: home})
+	require.NoError(t, err)
+	assert.NotEqual(t, old.ID, reused.ID)
+	events, err := LoadEvents(reused.ID)
+	require.NoError(t, err)
+	assert.Empty(t, events)
+
+	events, err = LoadEvents(old.ID)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, EventOutput, events[0].Type)
+
+	_, err = os.Stat(filepath.Join(home, ".claude-squad", eventsDirName, "auth.jsonl"))
+	assert.True(t, os.IsNotExist(err))
+}
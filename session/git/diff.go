@@ -1,6 +1,8 @@
 package git
 
 import (
+	"claude-squad/log"
+	gitservice "claude-squad/services/git"
 	"strings"
 )
 
@@ -12,6 +14,8 @@ type DiffStats struct {
 	Added int
 	// Removed is the number of removed lines
 	Removed int
+	// FilesChanged is the number of files touched by the diff
+	FilesChanged int
 	// Error holds any error that occurred during diff computation
 	// This allows propagating setup errors (like missing base commit) without breaking the flow
 	Error error
@@ -37,9 +41,17 @@ func (g *GitWorktree) Diff() *DiffStats {
 		stats.Error = err
 		return stats
 	}
+	// Leave out the files the repository excludes from review, such as lockfiles
+	if rules, err := gitservice.LoadDiffRules(g.worktreePath); err != nil {
+		log.ErrorLog.Print(err)
+	} else {
+		content, _ = rules.FilterPatch(content)
+	}
 	lines := strings.Split(content, "\n")
 	for _, line := range lines {
-		if strings.HasPrefix(line, "+") && !strings.HasPrefix(line, "+++") {
+		if strings.HasPrefix(line, "diff --git ") {
+			stats.FilesChanged++
+		} else if strings.HasPrefix(line, "+") && !strings.HasPrefix(line, "+++") {
 			stats.Added++
 		} else if strings.HasPrefix(line, "-") && !strings.HasPrefix(line, "---") {
 			stats.Removed++
@@ -3,6 +3,7 @@ package git
 import (
 	"errors"
 	"fmt"
+	"os"
 
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
@@ -43,6 +44,22 @@ func (g *GitWorktree) cleanupExistingBranch(repo *git.Repository) error {
 	return nil
 }
 
+// CheckAvailable verifies that the repository and branch backing this worktree still exist,
+// so callers can skip sessions whose repo was deleted or whose branch was removed.
+func (g *GitWorktree) CheckAvailable() error {
+	if _, err := os.Stat(g.repoPath); err != nil {
+		return fmt.Errorf("repository %s no longer exists", g.repoPath)
+	}
+	repo, err := git.PlainOpen(g.repoPath)
+	if err != nil {
+		return fmt.Errorf("%s is no longer a git repository: %w", g.repoPath, err)
+	}
+	if _, err := repo.Reference(plumbing.NewBranchReferenceName(g.branchName), false); err != nil {
+		return fmt.Errorf("branch %s no longer exists in %s", g.branchName, g.repoPath)
+	}
+	return nil
+}
+
 // combineErrors combines multiple errors into a single error
 func (g *GitWorktree) combineErrors(errs []error) error {
 	if len(errs) == 0 {
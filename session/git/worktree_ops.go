@@ -1,6 +1,7 @@
 package git
 
 import (
+	"claude-squad/cmd"
 	"claude-squad/log"
 	"fmt"
 	"os"
@@ -180,8 +181,9 @@ func (g *GitWorktree) Prune() error {
 	return nil
 }
 
-// CleanupWorktrees removes all worktrees and their associated branches
-func CleanupWorktrees() error {
+// CleanupWorktrees removes all worktrees and their associated branches. With a
+// cmd.DryRun executor it only prints what it would remove.
+func CleanupWorktrees(cmdExec cmd.Executor) error {
 	worktreesDir, err := getWorktreeDirectory()
 	if err != nil {
 		return fmt.Errorf("failed to get worktree directory: %w", err)
@@ -193,8 +195,7 @@ func CleanupWorktrees() error {
 	}
 
 	// Get a list of all branches associated with worktrees
-	cmd := exec.Command("git", "worktree", "list", "--porcelain")
-	output, err := cmd.Output()
+	output, err := cmdExec.Output(exec.Command("git", "worktree", "list", "--porcelain"))
 	if err != nil {
 		return fmt.Errorf("failed to list worktrees: %w", err)
 	}
@@ -224,8 +225,7 @@ func CleanupWorktrees() error {
 			for path, branch := range worktreeBranches {
 				if strings.Contains(path, entry.Name()) {
 					// Delete the branch
-					deleteCmd := exec.Command("git", "branch", "-D", branch)
-					if err := deleteCmd.Run(); err != nil {
+					if err := cmdExec.Run(exec.Command("git", "branch", "-D", branch)); err != nil {
 						// Log the error but continue with other worktrees
 						log.ErrorLog.Printf("failed to delete branch %s: %v", branch, err)
 					}
@@ -234,14 +234,16 @@ func CleanupWorktrees() error {
 			}
 
 			// Remove the worktree directory
-			os.RemoveAll(worktreePath)
+			if dryRun, ok := cmdExec.(cmd.DryRun); ok {
+				dryRun.Skip("remove " + worktreePath)
+			} else {
+				os.RemoveAll(worktreePath)
+			}
 		}
 	}
 
 	// You have to prune the cleaned up worktrees.
-	cmd = exec.Command("git", "worktree", "prune")
-	_, err = cmd.Output()
-	if err != nil {
+	if err := cmdExec.Run(exec.Command("git", "worktree", "prune")); err != nil {
 		return fmt.Errorf("failed to prune worktrees: %w", err)
 	}
 
@@ -0,0 +1,33 @@
+package session
+
+import (
+	"claude-squad/config"
+	"fmt"
+)
+
+// CheckDiffGuardrails compares the instance's latest diff stats against the limits and records
+// a warning if any is exceeded. It returns true only when the instance newly crosses a limit,
+// so callers notify once rather than on every metadata tick.
+func (i *Instance) CheckDiffGuardrails(limits config.DiffGuardrails) bool {
+	stats := i.diffStats
+	if stats == nil || stats.Error != nil {
+		return false
+	}
+
+	var warning string
+	switch {
+	case limits.MaxFiles > 0 && stats.FilesChanged > limits.MaxFiles:
+		warning = fmt.Sprintf("diff touches %d files (limit %d)", stats.FilesChanged, limits.MaxFiles)
+	case limits.MaxLines > 0 && stats.Added+stats.Removed > limits.MaxLines:
+		warning = fmt.Sprintf("diff changes %d lines (limit %d)", stats.Added+stats.Removed, limits.MaxLines)
+	}
+
+	exceeded := warning != "" && i.diffWarning == ""
+	i.diffWarning = warning
+	return exceeded
+}
+
+// DiffWarning returns the guardrail warning for this instance, or "" if the diff is within limits.
+func (i *Instance) DiffWarning() string {
+	return i.diffWarning
+}
@@ -0,0 +1,33 @@
+package session
+
+import (
+	"claude-squad/config"
+	"claude-squad/session/git"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckDiffGuardrails(t *testing.T) {
+	limits := config.DiffGuardrails{MaxFiles: 3, MaxLines: 100}
+	instance := &Instance{diffStats: &git.DiffStats{FilesChanged: 2, Added: 10, Removed: 5}}
+
+	assert.False(t, instance.CheckDiffGuardrails(limits))
+	assert.Empty(t, instance.DiffWarning())
+
+	// Crossing a limit reports once, then stays flagged without re-reporting.
+	instance.diffStats = &git.DiffStats{FilesChanged: 5, Added: 10}
+	assert.True(t, instance.CheckDiffGuardrails(limits))
+	assert.Contains(t, instance.DiffWarning(), "5 files")
+	assert.False(t, instance.CheckDiffGuardrails(limits))
+	assert.NotEmpty(t, instance.DiffWarning())
+
+	// Shrinking back under the limits clears the warning.
+	instance.diffStats = &git.DiffStats{FilesChanged: 1, Added: 1}
+	assert.False(t, instance.CheckDiffGuardrails(limits))
+	assert.Empty(t, instance.DiffWarning())
+
+	// Zero limits are disabled.
+	instance.diffStats = &git.DiffStats{FilesChanged: 500, Added: 10000}
+	assert.False(t, instance.CheckDiffGuardrails(config.DiffGuardrails{}))
+}
@@ -4,6 +4,8 @@ import (
 	"claude-squad/log"
 	"claude-squad/session/git"
 	"claude-squad/session/tmux"
+	"crypto/rand"
+	"encoding/hex"
 	"path/filepath"
 
 	"fmt"
@@ -27,8 +29,26 @@ const (
 	Paused
 )
 
+func (s Status) String() string {
+	switch s {
+	case Running:
+		return "running"
+	case Ready:
+		return "ready"
+	case Loading:
+		return "loading"
+	case Paused:
+		return "paused"
+	default:
+		return "unknown"
+	}
+}
+
 // Instance is a running instance of claude code.
 type Instance struct {
+	// ID identifies the instance for its whole life, unlike Title which a later instance
+	// may reuse.
+	ID string
 	// Title is the title of the instance.
 	Title string
 	// Path is the path to the workspace.
@@ -54,6 +74,8 @@ type Instance struct {
 
 	// DiffStats stores the current git diff statistics
 	diffStats *git.DiffStats
+	// diffWarning is set when the diff exceeds the configured guardrails
+	diffWarning string
 
 	// The below fields are initialized upon calling Start().
 
@@ -67,6 +89,7 @@ type Instance struct {
 // ToInstanceData converts an Instance to its serializable form
 func (i *Instance) ToInstanceData() InstanceData {
 	data := InstanceData{
+		ID:        i.ID,
 		Title:     i.Title,
 		Path:      i.Path,
 		Branch:    i.Branch,
@@ -95,7 +118,7 @@ func (i *Instance) ToInstanceData() InstanceData {
 		data.DiffStats = DiffStatsData{
 			Added:   i.diffStats.Added,
 			Removed: i.diffStats.Removed,
-			Content: i.diffStats.Content,
+			Content: log.Redact(i.diffStats.Content),
 		}
 	}
 
@@ -105,6 +128,7 @@ func (i *Instance) ToInstanceData() InstanceData {
 // FromInstanceData creates a new Instance from serialized data
 func FromInstanceData(data InstanceData) (*Instance, error) {
 	instance := &Instance{
+		ID:        data.ID,
 		Title:     data.Title,
 		Path:      data.Path,
 		Branch:    data.Branch,
@@ -127,6 +151,10 @@ func FromInstanceData(data InstanceData) (*Instance, error) {
 			Content: data.DiffStats.Content,
 		},
 	}
+	// Instances saved before IDs existed get one now, kept from the next save on
+	if instance.ID == "" {
+		instance.ID = newInstanceID()
+	}
 
 	if instance.Paused() {
 		instance.started = true
@@ -162,6 +190,7 @@ func NewInstance(opts InstanceOptions) (*Instance, error) {
 	}
 
 	return &Instance{
+		ID:        newInstanceID(),
 		Title:     opts.Title,
 		Status:    Ready,
 		Path:      absPath,
@@ -174,6 +203,16 @@ func NewInstance(opts InstanceOptions) (*Instance, error) {
 	}, nil
 }
 
+// newInstanceID returns a random ID for a new instance.
+func newInstanceID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		// Only fails if the system's randomness source is broken
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
+
 func (i *Instance) RepoName() (string, error) {
 	if !i.started {
 		return "", fmt.Errorf("cannot get repo name for instance that has not been started")
@@ -182,6 +221,13 @@ func (i *Instance) RepoName() (string, error) {
 }
 
 func (i *Instance) SetStatus(status Status) {
+	if status != i.Status {
+		if status == Running {
+			RecordEvent(i.ID, EventOutput, "agent is producing output")
+		} else {
+			RecordEvent(i.ID, EventStatus, status.String())
+		}
+	}
 	i.Status = status
 }
 
@@ -315,7 +361,9 @@ func (i *Instance) TapEnter() {
 	}
 	if err := i.tmuxSession.TapEnter(); err != nil {
 		log.ErrorLog.Printf("error tapping enter: %v", err)
+		return
 	}
+	RecordEvent(i.ID, EventAutoYes, "accepted prompt")
 }
 
 func (i *Instance) Attach() (chan struct{}, error) {
@@ -388,6 +436,7 @@ func (i *Instance) Pause() error {
 			// Return early if we can't commit changes to avoid corrupted state
 			return i.combineErrors(errs)
 		}
+		RecordEvent(i.ID, EventCommit, commitMsg)
 	}
 
 	// Detach from tmux session instead of closing to preserve session output
@@ -426,6 +475,15 @@ func (i *Instance) Pause() error {
 
 // Resume recreates the worktree and restarts the tmux session
 func (i *Instance) Resume() error {
+	if err := i.resumeResources(); err != nil {
+		return err
+	}
+	i.SetStatus(Running)
+	return nil
+}
+
+// resumeResources does Resume's work but leaves the instance marked paused
+func (i *Instance) resumeResources() error {
 	if !i.started {
 		return fmt.Errorf("cannot resume instance that has not been started")
 	}
@@ -475,8 +533,6 @@ func (i *Instance) Resume() error {
 			return fmt.Errorf("failed to start new session: %w", err)
 		}
 	}
-
-	i.SetStatus(Running)
 	return nil
 }
 
@@ -528,6 +584,7 @@ func (i *Instance) SendPrompt(prompt string) error {
 	if err := i.tmuxSession.TapEnter(); err != nil {
 		return fmt.Errorf("error tapping enter: %w", err)
 	}
+	RecordEvent(i.ID, EventPrompt, prompt)
 
 	return nil
 }
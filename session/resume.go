@@ -0,0 +1,64 @@
+package session
+
+import (
+	"sync"
+)
+
+// DefaultResumeConcurrency is the number of instances resumed in parallel by ResumeAll.
+const DefaultResumeConcurrency = 4
+
+// ResumeResult is the outcome of resuming a single instance in a bulk resume.
+type ResumeResult struct {
+	Title string
+	// Skipped is true if the instance's repository or branch is gone, so it wasn't attempted.
+	Skipped bool
+	Err     error
+
+	instance *Instance
+}
+
+// ResumeAll resumes the paused instances given, recreating worktrees and tmux sessions with
+// at most concurrency instances in flight. Instances whose repository or branch has since
+// disappeared are skipped and reported rather than failing the whole batch. Results are
+// returned in the same order as paused.
+//
+// The instances stay marked paused, so that ResumeAll can run while the UI reads them;
+// MarkResumed marks them running from the goroutine that owns them.
+func ResumeAll(paused []*Instance, concurrency int) []ResumeResult {
+	if concurrency <= 0 {
+		concurrency = DefaultResumeConcurrency
+	}
+
+	results := make([]ResumeResult, len(paused))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for idx, instance := range paused {
+		wg.Add(1)
+		go func(idx int, instance *Instance) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			result := ResumeResult{Title: instance.Title, instance: instance}
+			if err := instance.gitWorktree.CheckAvailable(); err != nil {
+				result.Skipped = true
+				result.Err = err
+			} else if err := instance.resumeResources(); err != nil {
+				result.Err = err
+			}
+			results[idx] = result
+		}(idx, instance)
+	}
+	wg.Wait()
+
+	return results
+}
+
+// MarkResumed marks the instances ResumeAll resumed running
+func MarkResumed(results []ResumeResult) {
+	for _, result := range results {
+		if result.Err == nil {
+			result.instance.SetStatus(Running)
+		}
+	}
+}
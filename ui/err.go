@@ -8,6 +8,8 @@ import (
 type ErrBox struct {
 	height, width int
 	err           error
+	// hint is a low-priority message shown when there is no error, e.g. an update notice.
+	hint string
 }
 
 var errStyle = lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{
@@ -15,6 +17,11 @@ var errStyle = lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{
 	Dark:  "#FF0000",
 })
 
+var hintStyle = lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{
+	Light: "#A49FA5",
+	Dark:  "#777777",
+})
+
 func NewErrBox() *ErrBox {
 	return &ErrBox{}
 }
@@ -23,6 +30,11 @@ func (e *ErrBox) SetError(err error) {
 	e.err = err
 }
 
+// SetHint sets a dimmed message that is displayed whenever no error is shown.
+func (e *ErrBox) SetHint(hint string) {
+	e.hint = hint
+}
+
 func (e *ErrBox) Clear() {
 	e.err = nil
 }
@@ -33,6 +45,13 @@ func (e *ErrBox) SetSize(width, height int) {
 }
 
 func (e *ErrBox) String() string {
+	if e.err == nil && e.hint != "" {
+		hint := e.hint
+		if len(hint) > e.width-3 && e.width-3 >= 0 {
+			hint = hint[:e.width-3] + "..."
+		}
+		return lipgloss.Place(e.width, e.height, lipgloss.Center, lipgloss.Center, hintStyle.Render(hint))
+	}
 	var err string
 	if e.err != nil {
 		err = e.err.Error()
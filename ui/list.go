@@ -13,6 +13,7 @@ import (
 
 const readyIcon = "● "
 const pausedIcon = "⏸ "
+const warningIcon = "⚠ "
 
 var readyStyle = lipgloss.NewStyle().
 	Foreground(lipgloss.AdaptiveColor{Light: "#51bd73", Dark: "#51bd73"})
@@ -23,6 +24,9 @@ var addedLinesStyle = lipgloss.NewStyle().
 var removedLinesStyle = lipgloss.NewStyle().
 	Foreground(lipgloss.Color("#de613e"))
 
+var warningStyle = lipgloss.NewStyle().
+	Foreground(lipgloss.AdaptiveColor{Light: "#d79921", Dark: "#fabd2f"})
+
 var pausedStyle = lipgloss.NewStyle().
 	Foreground(lipgloss.AdaptiveColor{Light: "#888888", Dark: "#888888"})
 
@@ -136,6 +140,10 @@ func (r *InstanceRenderer) Render(i *session.Instance, idx int, selected bool, h
 		join = pausedStyle.Render(pausedIcon)
 	default:
 	}
+	// Flag sessions whose diff has grown past the configured guardrails.
+	if i.DiffWarning() != "" && i.Status != session.Paused {
+		join = warningStyle.Render(warningIcon)
+	}
 
 	// Cut the title if it's too long
 	titleText := i.Title
@@ -33,6 +33,7 @@ var (
 const (
 	PreviewTab int = iota
 	DiffTab
+	TimelineTab
 )
 
 type Tab struct {
@@ -51,17 +52,20 @@ type TabbedWindow struct {
 
 	preview  *PreviewPane
 	diff     *DiffPane
+	timeline *TimelinePane
 	instance *session.Instance
 }
 
-func NewTabbedWindow(preview *PreviewPane, diff *DiffPane) *TabbedWindow {
+func NewTabbedWindow(preview *PreviewPane, diff *DiffPane, timeline *TimelinePane) *TabbedWindow {
 	return &TabbedWindow{
 		tabs: []string{
 			"Preview",
 			"Diff",
+			"Timeline",
 		},
-		preview: preview,
-		diff:    diff,
+		preview:  preview,
+		diff:     diff,
+		timeline: timeline,
 	}
 }
 
@@ -88,6 +92,7 @@ func (w *TabbedWindow) SetSize(width, height int) {
 
 	w.preview.SetSize(contentWidth, contentHeight)
 	w.diff.SetSize(contentWidth, contentHeight)
+	w.timeline.SetSize(contentWidth, contentHeight)
 }
 
 func (w *TabbedWindow) GetPreviewSize() (width, height int) {
@@ -123,6 +128,14 @@ func (w *TabbedWindow) UpdateDiff(instance *session.Instance) {
 	w.diff.SetDiff(instance)
 }
 
+// UpdateTimeline reloads the activity timeline. instance may be nil.
+func (w *TabbedWindow) UpdateTimeline(instance *session.Instance) {
+	if w.activeTab != TimelineTab {
+		return
+	}
+	w.timeline.SetTimeline(instance)
+}
+
 // ResetPreviewToNormalMode resets the preview pane to normal mode
 func (w *TabbedWindow) ResetPreviewToNormalMode(instance *session.Instance) error {
 	return w.preview.ResetToNormalMode(instance)
@@ -135,8 +148,10 @@ func (w *TabbedWindow) ScrollUp() {
 		if err != nil {
 			log.InfoLog.Printf("tabbed window failed to scroll up: %v", err)
 		}
-	} else {
+	} else if w.activeTab == DiffTab {
 		w.diff.ScrollUp()
+	} else {
+		w.timeline.ScrollUp()
 	}
 }
 
@@ -146,14 +161,16 @@ func (w *TabbedWindow) ScrollDown() {
 		if err != nil {
 			log.InfoLog.Printf("tabbed window failed to scroll down: %v", err)
 		}
-	} else {
+	} else if w.activeTab == DiffTab {
 		w.diff.ScrollDown()
+	} else {
+		w.timeline.ScrollDown()
 	}
 }
 
 // IsInDiffTab returns true if the diff tab is currently active
 func (w *TabbedWindow) IsInDiffTab() bool {
-	return w.activeTab == 1
+	return w.activeTab == DiffTab
 }
 
 // IsPreviewInScrollMode returns true if the preview pane is in scroll mode
@@ -202,10 +219,13 @@ func (w *TabbedWindow) String() string {
 
 	row := lipgloss.JoinHorizontal(lipgloss.Top, renderedTabs...)
 	var content string
-	if w.activeTab == 0 {
+	switch w.activeTab {
+	case PreviewTab:
 		content = w.preview.String()
-	} else {
+	case DiffTab:
 		content = w.diff.String()
+	default:
+		content = w.timeline.String()
 	}
 	window := windowStyle.Render(
 		lipgloss.Place(
@@ -0,0 +1,131 @@
+package ui
+
+import (
+	"claude-squad/session"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var (
+	timelineTimeStyle = lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "#A49FA5", Dark: "#777777"})
+	timelineDayStyle  = lipgloss.NewStyle().Bold(true).Foreground(highlightColor)
+
+	timelineEventStyles = map[session.EventType]lipgloss.Style{
+		session.EventPrompt:  lipgloss.NewStyle().Foreground(lipgloss.Color("#0ea5e9")),
+		session.EventOutput:  lipgloss.NewStyle().Foreground(lipgloss.Color("#22c55e")),
+		session.EventAutoYes: lipgloss.NewStyle().Foreground(lipgloss.Color("#eab308")),
+		session.EventCommit:  lipgloss.NewStyle().Foreground(lipgloss.Color("#a855f7")),
+		session.EventStatus:  lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "#888888", Dark: "#888888"}),
+	}
+)
+
+// TimelinePane shows the activity timeline of the selected session, newest at the bottom.
+type TimelinePane struct {
+	viewport viewport.Model
+	width    int
+	height   int
+
+	// The events of the session with this ID read so far, and the offset in its log to read
+	// new ones from, so each refresh only reads what was appended since the last
+	id       string
+	events   []session.Event
+	offset   int64
+	rendered bool
+}
+
+func NewTimelinePane() *TimelinePane {
+	return &TimelinePane{
+		viewport: viewport.New(0, 0),
+	}
+}
+
+func (t *TimelinePane) SetSize(width, height int) {
+	t.width = width
+	t.height = height
+	t.viewport.Width = width
+	t.viewport.Height = height
+	t.rendered = false
+}
+
+// SetTimeline reads new events from the event log for instance. instance may be nil.
+func (t *TimelinePane) SetTimeline(instance *session.Instance) {
+	if instance == nil {
+		t.id, t.events, t.offset = "", nil, 0
+		t.viewport.SetContent(lipgloss.Place(t.width, t.height, lipgloss.Center, lipgloss.Center, "No activity"))
+		t.rendered = false
+		return
+	}
+
+	if instance.ID != t.id {
+		t.id, t.events, t.offset = instance.ID, nil, 0
+		t.rendered = false
+	}
+	events, offset, err := session.ReadEvents(t.id, t.offset)
+	if err != nil {
+		t.viewport.SetContent(lipgloss.Place(t.width, t.height, lipgloss.Center, lipgloss.Center,
+			fmt.Sprintf("Error: %v", err)))
+		t.rendered = false
+		return
+	}
+	t.events, t.offset = append(t.events, events...), offset
+	if len(events) == 0 && t.rendered {
+		return
+	}
+	t.rendered = true
+
+	if len(t.events) == 0 {
+		t.viewport.SetContent(lipgloss.Place(t.width, t.height, lipgloss.Center, lipgloss.Center, "No activity"))
+		return
+	}
+
+	// Only follow the tail if the user hasn't scrolled up.
+	atBottom := t.viewport.AtBottom()
+	t.viewport.SetContent(t.render(t.events))
+	if atBottom {
+		t.viewport.GotoBottom()
+	}
+}
+
+func (t *TimelinePane) render(events []session.Event) string {
+	var b strings.Builder
+	var day string
+	for _, e := range events {
+		if d := e.Time.Format("Mon Jan 2"); d != day {
+			if day != "" {
+				b.WriteString("\n")
+			}
+			day = d
+			b.WriteString(timelineDayStyle.Render(day))
+			b.WriteString("\n")
+		}
+
+		style, ok := timelineEventStyles[e.Type]
+		if !ok {
+			style = lipgloss.NewStyle()
+		}
+		line := fmt.Sprintf("%s  %s  %s",
+			timelineTimeStyle.Render(e.Time.Format("15:04:05")),
+			style.Render(fmt.Sprintf("%-8s", e.Type)),
+			strings.ReplaceAll(e.Detail, "\n", " "))
+		b.WriteString(lipgloss.NewStyle().MaxWidth(t.width).Render(line))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+func (t *TimelinePane) String() string {
+	return t.viewport.View()
+}
+
+// ScrollUp scrolls the viewport up
+func (t *TimelinePane) ScrollUp() {
+	t.viewport.LineUp(1)
+}
+
+// ScrollDown scrolls the viewport down
+func (t *TimelinePane) ScrollDown() {
+	t.viewport.LineDown(1)
+}
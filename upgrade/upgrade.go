@@ -0,0 +1,314 @@
+package upgrade
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// Repo is the GitHub repository releases are published to.
+	Repo = "smtg-ai/claude-squad"
+	// BinaryName is the name of the executable inside release archives.
+	BinaryName = "claude-squad"
+	// checksumsAsset is the goreleaser checksum file attached to every release.
+	checksumsAsset = "checksums.txt"
+)
+
+var latestReleaseURL = fmt.Sprintf("https://api.github.com/repos/%s/releases/latest", Repo)
+
+// Asset is a downloadable file attached to a release.
+type Asset struct {
+	Name string `json:"name"`
+	URL  string `json:"browser_download_url"`
+}
+
+// Release is the subset of the GitHub release payload we care about.
+type Release struct {
+	TagName string  `json:"tag_name"`
+	HTMLURL string  `json:"html_url"`
+	Assets  []Asset `json:"assets"`
+}
+
+// Version returns the release version without the leading "v".
+func (r *Release) Version() string {
+	return strings.TrimPrefix(r.TagName, "v")
+}
+
+// asset finds an asset by name.
+func (r *Release) asset(name string) (*Asset, error) {
+	for i := range r.Assets {
+		if r.Assets[i].Name == name {
+			return &r.Assets[i], nil
+		}
+	}
+	return nil, fmt.Errorf("release %s has no asset named %s", r.TagName, name)
+}
+
+// Client talks to the GitHub releases API.
+type Client struct {
+	http *http.Client
+}
+
+// NewClient creates a new upgrade client.
+func NewClient() *Client {
+	return &Client{http: &http.Client{Timeout: 60 * time.Second}}
+}
+
+// LatestRelease fetches the latest published release.
+func (c *Client) LatestRelease(ctx context.Context) (*Release, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, latestReleaseURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query releases: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to query releases: %s", resp.Status)
+	}
+
+	var release Release
+	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
+		return nil, fmt.Errorf("failed to parse release: %w", err)
+	}
+	return &release, nil
+}
+
+// Check returns the latest release and whether it is newer than current.
+func (c *Client) Check(ctx context.Context, current string) (*Release, bool, error) {
+	release, err := c.LatestRelease(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	return release, IsNewer(release.Version(), current), nil
+}
+
+// Apply downloads the archive for this platform from release, verifies it against the
+// release checksums and replaces the executable at execPath with the new binary.
+func (c *Client) Apply(ctx context.Context, release *Release, execPath string) error {
+	archiveName := ArchiveName(release.Version(), runtime.GOOS, runtime.GOARCH)
+	archiveAsset, err := release.asset(archiveName)
+	if err != nil {
+		return err
+	}
+	checksumAsset, err := release.asset(checksumsAsset)
+	if err != nil {
+		return err
+	}
+
+	checksums, err := c.download(ctx, checksumAsset.URL)
+	if err != nil {
+		return fmt.Errorf("failed to download checksums: %w", err)
+	}
+	expected, err := findChecksum(checksums, archiveName)
+	if err != nil {
+		return err
+	}
+
+	archive, err := c.download(ctx, archiveAsset.URL)
+	if err != nil {
+		return fmt.Errorf("failed to download %s: %w", archiveName, err)
+	}
+	sum := sha256.Sum256(archive)
+	if actual := hex.EncodeToString(sum[:]); actual != expected {
+		return fmt.Errorf("checksum mismatch for %s: expected %s, got %s", archiveName, expected, actual)
+	}
+
+	binary, err := extractBinary(archive, archiveName)
+	if err != nil {
+		return err
+	}
+
+	return replaceExecutable(execPath, binary)
+}
+
+func (c *Client) download(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// ArchiveName returns the goreleaser archive name for the given version and platform.
+func ArchiveName(version, goos, goarch string) string {
+	ext := ".tar.gz"
+	if goos == "windows" {
+		ext = ".zip"
+	}
+	return fmt.Sprintf("%s_%s_%s_%s%s", BinaryName, version, goos, goarch, ext)
+}
+
+// IsNewer reports whether version latest is newer than current. Both are dotted numeric
+// versions with an optional "v" prefix; pre-release suffixes are ignored.
+func IsNewer(latest, current string) bool {
+	l := parseVersion(latest)
+	c := parseVersion(current)
+	for i := 0; i < len(l) || i < len(c); i++ {
+		var lv, cv int
+		if i < len(l) {
+			lv = l[i]
+		}
+		if i < len(c) {
+			cv = c[i]
+		}
+		if lv != cv {
+			return lv > cv
+		}
+	}
+	return false
+}
+
+func parseVersion(v string) []int {
+	v = strings.TrimPrefix(strings.TrimSpace(v), "v")
+	if i := strings.IndexAny(v, "-+"); i >= 0 {
+		v = v[:i]
+	}
+	var parts []int
+	for _, p := range strings.Split(v, ".") {
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			break
+		}
+		parts = append(parts, n)
+	}
+	return parts
+}
+
+// findChecksum looks up the sha256 for name in a goreleaser checksums.txt file.
+func findChecksum(checksums []byte, name string) (string, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(checksums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && fields[1] == name {
+			return fields[0], nil
+		}
+	}
+	return "", fmt.Errorf("no checksum found for %s", name)
+}
+
+// extractBinary pulls the claude-squad executable out of a release archive.
+func extractBinary(archive []byte, archiveName string) ([]byte, error) {
+	if strings.HasSuffix(archiveName, ".zip") {
+		zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to open zip archive: %w", err)
+		}
+		for _, f := range zr.File {
+			if isBinaryName(f.Name) {
+				rc, err := f.Open()
+				if err != nil {
+					return nil, err
+				}
+				defer rc.Close()
+				return io.ReadAll(rc)
+			}
+		}
+		return nil, fmt.Errorf("%s not found in %s", BinaryName, archiveName)
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open tar.gz archive: %w", err)
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read archive: %w", err)
+		}
+		if hdr.Typeflag == tar.TypeReg && isBinaryName(hdr.Name) {
+			return io.ReadAll(tr)
+		}
+	}
+	return nil, fmt.Errorf("%s not found in %s", BinaryName, archiveName)
+}
+
+func isBinaryName(name string) bool {
+	base := filepath.Base(name)
+	return base == BinaryName || base == BinaryName+".exe"
+}
+
+// replaceExecutable swaps the binary at execPath for the new contents. The new binary is
+// written next to the old one and renamed into place so a failed write never leaves a
+// half-written executable behind.
+func replaceExecutable(execPath string, binary []byte) error {
+	execPath, err := filepath.EvalSymlinks(execPath)
+	if err != nil {
+		return fmt.Errorf("failed to resolve executable path: %w", err)
+	}
+
+	info, err := os.Stat(execPath)
+	if err != nil {
+		return fmt.Errorf("failed to stat executable: %w", err)
+	}
+
+	dir := filepath.Dir(execPath)
+	tmp, err := os.CreateTemp(dir, "."+BinaryName+"-upgrade-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file (do you have write access to %s?): %w", dir, err)
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath)
+
+	if _, err := tmp.Write(binary); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write new binary: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write new binary: %w", err)
+	}
+	if err := os.Chmod(tmpPath, info.Mode().Perm()); err != nil {
+		return fmt.Errorf("failed to set permissions on new binary: %w", err)
+	}
+
+	// Windows can't overwrite a running executable, but it can rename it out of the way.
+	oldPath := execPath + ".old"
+	_ = os.Remove(oldPath)
+	if err := os.Rename(execPath, oldPath); err != nil {
+		return fmt.Errorf("failed to move old binary aside: %w", err)
+	}
+	if err := os.Rename(tmpPath, execPath); err != nil {
+		_ = os.Rename(oldPath, execPath)
+		return fmt.Errorf("failed to install new binary: %w", err)
+	}
+	if runtime.GOOS != "windows" {
+		_ = os.Remove(oldPath)
+	}
+	return nil
+}
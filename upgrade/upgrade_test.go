@@ -0,0 +1,61 @@
+package upgrade
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsNewer(t *testing.T) {
+	tests := []struct {
+		latest, current string
+		want            bool
+	}{
+		{"1.0.14", "1.0.13", true},
+		{"v1.1.0", "1.0.13", true},
+		{"1.0.13", "1.0.13", false},
+		{"1.0.9", "1.0.13", false},
+		{"2.0.0-rc1", "1.9.9", true},
+		{"1.0", "1.0.1", false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, IsNewer(tt.latest, tt.current), "%s vs %s", tt.latest, tt.current)
+	}
+}
+
+func TestArchiveName(t *testing.T) {
+	assert.Equal(t, "claude-squad_1.2.3_linux_amd64.tar.gz", ArchiveName("1.2.3", "linux", "amd64"))
+	assert.Equal(t, "claude-squad_1.2.3_windows_arm64.zip", ArchiveName("1.2.3", "windows", "arm64"))
+}
+
+func TestFindChecksum(t *testing.T) {
+	checksums := []byte("abc123  claude-squad_1.2.3_linux_amd64.tar.gz\ndef456  claude-squad_1.2.3_darwin_arm64.tar.gz\n")
+
+	sum, err := findChecksum(checksums, "claude-squad_1.2.3_darwin_arm64.tar.gz")
+	require.NoError(t, err)
+	assert.Equal(t, "def456", sum)
+
+	_, err = findChecksum(checksums, "claude-squad_1.2.3_windows_amd64.zip")
+	assert.Error(t, err)
+}
+
+func TestExtractBinaryTarGz(t *testing.T) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, body := range map[string]string{"README.md": "readme", "claude-squad": "binary"} {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0755, Size: int64(len(body)), Typeflag: tar.TypeReg}))
+		_, err := tw.Write([]byte(body))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+
+	binary, err := extractBinary(buf.Bytes(), "claude-squad_1.2.3_linux_amd64.tar.gz")
+	require.NoError(t, err)
+	assert.Equal(t, "binary", string(binary))
+}